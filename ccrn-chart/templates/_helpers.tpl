@@ -67,4 +67,11 @@ Webhook name
 */}}
 {{- define "ccrn.webhookName" -}}
 {{- printf "%s.%s.svc" (include "ccrn.fullname" .) .Release.Namespace }}
+{{- end }}
+
+{{/*
+Comma separated CCRN groups served by the webhook, the apiGroup and the legacy groups
+*/}}
+{{- define "ccrn.groups" -}}
+{{- prepend .Values.ccrn.legacyGroups .Values.ccrn.apiGroup | join "," }}
 {{- end }}
\ No newline at end of file
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ccrn is the library facade for services producing or consuming Common Cloud Resource Names.
+// It parses, canonicalizes, compares and renders CCRNs and URNs with pure functions that need neither
+// a validation backend nor a Kubernetes client. Use pkg/validation for validation against live CRDs.
+package ccrn
+
+import (
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/ccrnset"
+)
+
+// Resource is a parsed CCRN or URN
+type Resource = apis.ParsedResource
+
+// Schema describes a resource type version, as loaded from its CRD
+type Schema = apis.CRDInfo
+
+// ParseError describes where and why parsing failed
+type ParseError = apis.ParseError
+
+// Failure classes, use errors.Is to branch on them
+var (
+	ErrUnknownFormat    = apis.ErrUnknownFormat
+	ErrUnsupportedType  = apis.ErrUnsupportedType
+	ErrTemplateMismatch = apis.ErrTemplateMismatch
+	ErrSchemaViolation  = apis.ErrSchemaViolation
+	ErrInvalidTemplate  = apis.ErrInvalidTemplate
+	ErrTooLong          = apis.ErrTooLong
+	ErrFieldNotSet      = apis.ErrFieldNotSet
+)
+
+// Parse parses a field-based CCRN like "ccrn=pod.k8s-registry.ccrn.example.com/v1, name=foo".
+// URNs need the template of their resource type, use ParseURN for them.
+func Parse(input string) (*Resource, error) {
+	return apis.ParseCCRN(input)
+}
+
+// ParseURN parses a URN with the URN template of its resource type
+func ParseURN(urn, template string) (*Resource, error) {
+	parsed, err := apis.ParseURN(urn, template)
+	if err != nil {
+		return nil, err
+	}
+	parsed.UrnTemplate = template
+	return parsed, nil
+}
+
+// Canonicalize returns the canonical form of a CCRN: the ccrn field first, followed by all other
+// fields sorted by name with values quoted only where needed
+func Canonicalize(input string) (string, error) {
+	parsed, err := apis.ParseCCRN(input)
+	if err != nil {
+		return "", err
+	}
+	return parsed.CanonicalCCRN(), nil
+}
+
+// Equal reports whether two CCRNs, or two URNs, identify the same resource
+func Equal(a, b string) (bool, error) {
+	return apis.Equal(a, b)
+}
+
+// Fingerprint returns the stable hash of a CCRN, usable as label value or object name, see ParsedResource.Fingerprint
+func Fingerprint(input string) (string, error) {
+	parsed, err := apis.ParseCCRN(input)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Fingerprint(), nil
+}
+
+// LabelSelector returns the label selector, e.g. for "kubectl get -l", of the CCRN objects covered by the
+// possibly wildcarded CCRN pattern, see ccrnset.LabelSelector
+func LabelSelector(pattern string) (string, error) {
+	selector, err := ccrnset.LabelSelector(pattern)
+	if err != nil {
+		return "", err
+	}
+	return selector.String(), nil
+}
+
+// RenderURN renders the URN of a CCRN with the URN template of its resource type
+func RenderURN(input, template string) (string, error) {
+	parsed, err := apis.ParseCCRN(input)
+	if err != nil {
+		return "", err
+	}
+	return apis.BuildURN(parsed, template)
+}
+
+// Validate parses a CCRN, or a URN using the URN template of the schema, and checks it against the schema:
+// the resource type and version must match, fields must be declared, required fields present and values
+// must satisfy their enum, length and pattern constraints, see Resource.ValidateAgainst. It does not evaluate
+// the full OpenAPI schema.
+func Validate(input string, schema *Schema) (*Resource, error) {
+	if err := apis.CheckLength(input, apis.DefaultMaxLength); err != nil {
+		return nil, err
+	}
+
+	var parsed *Resource
+	var err error
+	if strings.HasPrefix(input, "ccrn=") || schema == nil {
+		parsed, err = Parse(input)
+	} else {
+		parsed, err = ParseURN(input, schema.URNFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return parsed, nil
+	}
+
+	if err := parsed.ValidateAgainst(schema); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
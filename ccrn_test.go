@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ccrn_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	ccrn "github.com/cloudoperators/common-cloud-resource-names"
+)
+
+func TestCCRN(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CCRN Suite")
+}
+
+var _ = Describe("Facade", func() {
+	var schema *ccrn.Schema
+
+	BeforeEach(func() {
+		schema = &ccrn.Schema{
+			Kind:      "pod",
+			Group:     "k8s-registry.ccrn.example.com",
+			Version:   "v1",
+			URNFormat: "urn:ccrn:<ccrn>/<cluster>/<name>",
+			Schema: &apiextensionsv1.JSONSchemaProps{
+				Required: []string{"ccrn", "cluster", "name"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"ccrn":    {Type: "string"},
+					"cluster": {Type: "string"},
+					"name":    {Type: "string", Pattern: "^[a-z0-9-]+$"},
+				},
+			},
+		}
+	})
+
+	It("canonicalizes CCRNs", func() {
+		// Act
+		canonical, err := ccrn.Canonicalize(`ccrn=pod.k8s-registry.ccrn.example.com/v1,name="my-pod", cluster=eu-de-1`)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(canonical).To(Equal("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod"))
+	})
+
+	It("renders and parses URNs", func() {
+		// Act
+		urn, err := ccrn.RenderURN("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod", schema.URNFormat)
+		Expect(err).ToNot(HaveOccurred())
+		parsed, err := ccrn.ParseURN(urn, schema.URNFormat)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod"))
+		Expect(parsed.CanonicalCCRN()).To(Equal("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod"))
+	})
+
+	It("validates CCRNs and URNs against a schema", func() {
+		// Act
+		_, ccrnErr := ccrn.Validate("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod", schema)
+		_, urnErr := ccrn.Validate("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod", schema)
+		// Assert
+		Expect(ccrnErr).ToNot(HaveOccurred())
+		Expect(urnErr).ToNot(HaveOccurred())
+	})
+
+	It("reports schema violations", func() {
+		// Act
+		_, missingErr := ccrn.Validate("ccrn=pod.k8s-registry.ccrn.example.com/v1, name=my-pod", schema)
+		_, patternErr := ccrn.Validate("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=My_Pod", schema)
+		_, typeErr := ccrn.Validate("ccrn=node.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod", schema)
+		// Assert
+		Expect(missingErr).To(MatchError(ccrn.ErrSchemaViolation))
+		Expect(missingErr.Error()).To(ContainSubstring("missing required fields: cluster"))
+		Expect(patternErr).To(MatchError(ccrn.ErrSchemaViolation))
+		Expect(typeErr).To(MatchError(ccrn.ErrUnsupportedType))
+	})
+
+	It("parses without schema", func() {
+		// Act
+		parsed, err := ccrn.Validate("ccrn=pod.k8s-registry.ccrn.example.com/v1, name=my-pod", nil)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(HaveKeyWithValue("name", "my-pod"))
+	})
+
+	It("returns the label selector of a CCRN pattern", func() {
+		// Act
+		selector, err := ccrn.LabelSelector("ccrn=pod.k8s-registry.ccrn.example.com/*, cluster=eu-de-1, name=*")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selector).To(Equal("ccrn/field.cluster=eu-de-1,ccrn/group=k8s-registry.ccrn.example.com,ccrn/kind=pod"))
+	})
+})
@@ -7,38 +7,165 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/audit"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/certs"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cleanup"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/drift"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/featuregate"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/namespaceconfig"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/opa"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/plugin"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/policy"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/resolver"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/version"
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
 )
 
 func main() {
 	// Define command line flags
 	var (
-		port      int
-		certFile  string
-		keyFile   string
-		logLevel  string
-		ccrnGroup string
+		port        int
+		certFile    string
+		keyFile     string
+		logLevel    string
+		logFormat   string
+		logSampling int
+		ccrnGroup   string
+		urnPrefixes string
+		typeAliases string
+		duplicates  string
+		limits      = apis.DefaultLimits
+
+		selfSignedCerts   bool
+		certSecretName    string
+		certNamespace     string
+		serviceName       string
+		webhookConfigName string
+
+		printVersion      bool
+		maxRequestBytes   int64
+		authorizeCreators bool
+		auditSinkURL      string
+		recordFile        string
+		recordMaxSizeMB   int64
+		recordKeyFile     string
+		bundleDigest      string
+		emitEvents        bool
+		namespaceEvents   bool
+		regoPolicies      string
+		regoPackage       string
+		policyRules       string
+		fieldResolvers    string
+		resolverTimeout   time.Duration
+		resolverCacheTTL  time.Duration
+		resolverCacheSize int
+		compileCacheSize  int
+		plugins           string
+		namespaceConfigs  bool
+		pluginTimeout     time.Duration
+		annotateResources string
+		annotateFields    string
+		driftInterval     time.Duration
+
+		metricsBindAddress     string
+		healthProbeBindAddress string
+
+		otlpEndpoint     string
+		otlpInsecure     bool
+		traceSampleRatio float64
 	)
 
 	flag.IntVar(&port, "port", 8443, "Port to listen on")
 	flag.StringVar(&certFile, "cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate file")
 	flag.StringVar(&keyFile, "key-file", "/etc/webhook/certs/tls.key", "Path to the TLS key file")
 	flag.StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
-	flag.StringVar(&ccrnGroup, "ccrn-group", "ccrn.example.com", "The CCRN CRD group used for all CCRN CRDs")
+	flag.StringVar(&logFormat, "log-format", "text", "Log format (text, json)")
+	flag.IntVar(&logSampling, "log-sampling", 1, "Log only every nth allowed admission request at info level and the others at debug level, denials are always logged")
+	flag.StringVar(&ccrnGroup, "ccrn-group", "ccrn.example.com", "The CCRN CRD group used for all CCRN CRDs, comma separated to serve several groups, e.g. a legacy and a new group during a migration")
+	flag.StringVar(&urnPrefixes, "urn-prefixes", apis.URNPrefix, "Comma separated list of accepted URN prefixes (urn:<NID>:), the URN template of a CRD decides which one its URNs use")
+	flag.StringVar(&typeAliases, "type-aliases", "", "Comma separated <alias>=<kind>.<group> pairs of short resource types, in addition to the ccrn/aliases CRD annotations")
+	flag.StringVar(&duplicates, "duplicate-fields", "reject", "Handling of CCRNs setting a field more than once: reject denies them, keep-last admits them with a warning and the last value wins")
+	flag.IntVar(&limits.MaxFields, "max-fields", limits.MaxFields, "Maximum number of fields of an identifier, 0 disables the limit")
+	flag.IntVar(&limits.MaxKeyLength, "max-key-length", limits.MaxKeyLength, "Maximum number of characters of a field key, 0 disables the limit")
+	flag.IntVar(&limits.MaxDepth, "max-depth", limits.MaxDepth, "Maximum number of segments of dot path field keys, 0 disables the limit")
+	flag.IntVar(&compileCacheSize, "compile-cache-size", apis.DefaultCompileCacheSize, "Number of compiled URN templates and schema patterns cached each, the least recently used ones are evicted first, 0 does not bound the caches")
+	flag.BoolVar(&selfSignedCerts, "self-signed-certs", false, "Generate and rotate a self-signed CA and serving certificate instead of reading cert-file/key-file")
+	flag.StringVar(&certSecretName, "cert-secret-name", "ccrn-webhook-certs", "Secret used to store the self-signed certificates")
+	flag.StringVar(&certNamespace, "cert-namespace", os.Getenv("NAMESPACE"), "Namespace of the certificate secret and webhook service")
+	flag.StringVar(&serviceName, "service-name", "ccrn", "Name of the webhook service, used for the certificate DNS names")
+	flag.StringVar(&webhookConfigName, "webhook-config-name", "", "MutatingWebhookConfiguration to inject the CA bundle into (empty to skip)")
+	flag.BoolVar(&printVersion, "version", false, "Print build information and exit")
+	flag.Int64Var(&maxRequestBytes, "max-request-bytes", webhook.DefaultMaxRequestBytes, "Maximum size of admission request bodies in bytes")
+	flag.BoolVar(&authorizeCreators, "authorize-creators", false, "Verify via SubjectAccessReview that users may create the resource type referenced by a CCRN")
+	flag.StringVar(&auditSinkURL, "audit-sink", "", "Audit sink URL for admission decisions (file:///path?maxSizeMB=100&maxBackups=5, https://host/path or kafka://broker1,broker2/topic), auditing is disabled when empty")
+	flag.StringVar(&recordFile, "record-file", "", "File the sanitized admission requests and their responses are appended to as JSON lines for \"ccrn replay\", recording is disabled when empty")
+	flag.Int64Var(&recordMaxSizeMB, "record-max-size-mb", 100, "Maximum size of the recording file in MiB, further requests are not recorded")
+	flag.StringVar(&recordKeyFile, "record-key-file", "", "File with the secret key the usernames of recorded requests are pseudonymized with (HMAC-SHA256), required for recording")
+	flag.StringVar(&bundleDigest, "bundle-digest", "", "Digest of the schema bundle (sha256:...) the CRDs of the cluster must match, see \"ccrn bundle\", CCRN resources are denied while they differ, pinning is disabled when empty")
+	flag.BoolVar(&emitEvents, "emit-events", false, "Record Kubernetes Events on CCRN objects for rejections, mutations and deprecated versions")
+	flag.BoolVar(&namespaceEvents, "namespace-events", false, "Additionally record every event on the namespace of the object (requires --emit-events)")
+	flag.StringVar(&regoPolicies, "rego-policies", "", "Comma separated list of Rego files or directories with policies evaluated after schema validation, policies are disabled when empty")
+	flag.StringVar(&regoPackage, "rego-package", opa.DefaultPackage, "Rego package of the deny and warn rules of the policies")
+	flag.BoolVar(&namespaceConfigs, "namespace-configs", false, "Watch the CCRNConfigs of validate.<ccrn-group> tuning the enforcement per namespace")
+	flag.StringVar(&plugins, "plugins", "", "Comma separated list of validation plugins evaluated after schema validation, executables or WASI modules (.wasm) reading JSON from stdin")
+	flag.DurationVar(&pluginTimeout, "plugin-timeout", plugin.DefaultTimeout, "Maximum time a validation plugin may take, requests are denied when exceeded")
+	flag.StringVar(&fieldResolvers, "field-resolvers", "", "Comma separated <field>=<url> pairs of external inventories verifying field values (https://host/path/{value} or file:///path with one value per line)")
+	flag.DurationVar(&resolverTimeout, "resolver-timeout", webhook.DefaultResolverTimeout, "Maximum time to resolve the field values of a request, requests are denied when exceeded")
+	flag.DurationVar(&resolverCacheTTL, "resolver-cache-ttl", 5*time.Minute, "Time the results of HTTP field resolvers are cached, 0 disables caching")
+	flag.IntVar(&resolverCacheSize, "resolver-cache-size", resolver.DefaultMaxCacheEntries, "Number of values cached per HTTP field resolver, the least recently used values are evicted first, 0 does not bound the cache")
+	flag.StringVar(&policyRules, "policy-rules", "", "YAML file with declarative naming rules evaluated after schema validation, rules are disabled when empty")
+	flag.StringVar(&annotateResources, "annotate-resources", "", "Comma separated resources (e.g. pods,services,deployments.apps) annotated with their CCRN in "+apis.IdentifierAnnotation+" via the /annotate path, disabled when empty")
+	flag.StringVar(&annotateFields, "annotate-fields", "", "Comma separated <field>=<value> pairs of CCRN fields the annotated objects do not provide, e.g. cluster=eu-de-1")
+	flag.DurationVar(&driftInterval, "drift-interval", 0, "Interval at which the targets of all CCRN resources are checked and their TargetFound condition is updated, 0 disables drift detection")
+	flag.StringVar(&metricsBindAddress, "metrics-bind-address", ":8080", "Address of the plaintext /metrics listener, \"0\" disables it")
+	flag.StringVar(&healthProbeBindAddress, "health-probe-bind-address", ":8081", "Address of the plaintext /healthz and /readyz listener, \"0\" disables it")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP endpoint (host:port) to export traces to, tracing is disabled when empty")
+	flag.BoolVar(&otlpInsecure, "otlp-insecure", false, "Export traces via plain HTTP instead of HTTPS")
+	flag.Float64Var(&traceSampleRatio, "trace-sample-ratio", 1.0, "Fraction of admission requests to trace (0..1)")
+	flag.Var(featuregate.Default, "feature-gates", "Comma separated list of Feature=bool pairs to enable experimental behavior. Known features:\n"+strings.Join(featuregate.Default.KnownFeatures(), "\n"))
 	flag.Parse()
 
+	if printVersion {
+		fmt.Println(version.Get())
+		return
+	}
+
 	// Configure logger
 	log := logrus.New()
-	log.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	switch logFormat {
+	case "json":
+		log.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		if logFormat != "text" {
+			log.Warnf("Invalid log format %s, using text", logFormat)
+		}
+		log.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
 
 	// Set log level
 	level, err := logrus.ParseLevel(logLevel)
@@ -48,28 +175,278 @@ func main() {
 	}
 	log.SetLevel(level)
 
-	// Create webhook server using the refactored structure
-	// This maintains backward compatibility by using the Kubernetes backend
-	server, err := webhook.NewWebhookServerFromConfig(log, ccrnGroup)
+	apis.SetCompileCacheSize(compileCacheSize)
+
+	// Route controller-runtime logs through logrus
+	ctrl.SetLogger(webhook.NewLogrusLogger(log))
+
+	log.Infof("Starting CCRN webhook %s", version.Get())
+	if gates := featuregate.Default.String(); gates != "" {
+		log.Infof("Feature gates: %s", gates)
+	}
+
+	// Configure tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    otlpEndpoint,
+		Insecure:    otlpInsecure,
+		SampleRatio: traceSampleRatio,
+		ServiceName: "ccrn-webhook",
+	})
 	if err != nil {
-		log.Fatalf("Failed to create webhook server: %v", err)
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	var auditSink audit.Sink
+	var recorder replay.Recorder
+	flush := func() {
+		if auditSink != nil {
+			if err := auditSink.Close(); err != nil {
+				log.Warnf("Failed to flush audit events: %v", err)
+			}
+		}
+		if recorder != nil {
+			if err := recorder.Close(); err != nil {
+				log.Warnf("Failed to close the recording file: %v", err)
+			}
+		}
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warnf("Failed to flush traces: %v", err)
+		}
+	}
+
+	// Connect to the cluster using the in-cluster config or $KUBECONFIG
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		log.Fatalf("Failed to get Kubernetes config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes client: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		log.Fatalf("Failed to create dynamic client: %v", err)
+	}
+
+	// The webhook server reloads cert-file/key-file when they change on disk
+	webhookOptions := ctrlwebhook.Options{
+		Port:     port,
+		CertDir:  filepath.Dir(certFile),
+		CertName: filepath.Base(certFile),
+		KeyName:  filepath.Base(keyFile),
+	}
+
+	// Bootstrap self-signed certificates if requested, this removes the dependency on cert-manager
+	var certManager *certs.Manager
+	if selfSignedCerts {
+		certConfig := certs.DefaultConfig()
+		certConfig.Namespace = certNamespace
+		certConfig.SecretName = certSecretName
+		certConfig.ServiceName = serviceName
+		certConfig.WebhookConfigName = webhookConfigName
+
+		certManager = certs.NewManager(log, client, certConfig)
+		if err := certManager.Bootstrap(context.Background()); err != nil {
+			log.Fatalf("Failed to bootstrap self-signed certificates: %v", err)
+		}
+		webhookOptions.TLSOpts = append(webhookOptions.TLSOpts, func(config *tls.Config) {
+			config.GetCertificate = certManager.GetCertificate
+		})
+	} else if filepath.Dir(keyFile) != webhookOptions.CertDir {
+		log.Fatalf("cert-file and key-file must be located in the same directory")
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Metrics:                metricsserver.Options{BindAddress: metricsBindAddress},
+		HealthProbeBindAddress: healthProbeBindAddress,
+		WebhookServer:          ctrlwebhook.NewServer(webhookOptions),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create manager: %v", err)
 	}
 
-	// Set up signal handling for graceful shutdown
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	// Renew the self-signed certificates before they expire and reload them when another replica did
+	if certManager != nil {
+		err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return certManager.Run(ctx, time.Hour)
+		}))
+		if err != nil {
+			log.Fatalf("Failed to start certificate rotation: %v", err)
+		}
+	}
 
-	// Start the webhook server in a goroutine
-	errCh := make(chan error)
-	go func() {
-		errCh <- server.Serve(port, certFile, keyFile)
-	}()
+	opts := []webhook.Option{webhook.WithMaxRequestBytes(maxRequestBytes), webhook.WithURNPrefixes(strings.Split(urnPrefixes, ",")...), webhook.WithLogSampling(logSampling), webhook.WithLimits(limits)}
+	if typeAliases != "" {
+		aliases, err := apis.ParseAliases(typeAliases)
+		if err != nil {
+			log.Fatalf("Invalid type aliases: %v", err)
+		}
+		opts = append(opts, webhook.WithTypeAliases(aliases))
+	}
+	switch duplicates {
+	case "reject":
+	case "keep-last":
+		opts = append(opts, webhook.WithKeepLastDuplicates())
+	default:
+		log.Fatalf("Invalid duplicate-fields %q, use reject or keep-last", duplicates)
+	}
+	if auditSinkURL != "" {
+		auditSink, err = audit.NewSinkFromURL(log, auditSinkURL)
+		if err != nil {
+			log.Fatalf("Failed to create audit sink: %v", err)
+		}
+		opts = append(opts, webhook.WithAuditSink(auditSink))
+	}
+	if recordFile != "" {
+		if recordKeyFile == "" {
+			log.Fatal("--record-file requires --record-key-file to pseudonymize the recorded users")
+		}
+		key, err := os.ReadFile(recordKeyFile)
+		if err != nil {
+			log.Fatalf("Failed to read the pseudonym key of the recording: %v", err)
+		}
+		if key = bytes.TrimSpace(key); len(key) == 0 {
+			log.Fatalf("The pseudonym key file %s of the recording is empty", recordKeyFile)
+		}
+		fileRecorder, err := replay.NewFileRecorder(recordFile, recordMaxSizeMB*1024*1024)
+		if err != nil {
+			log.Fatalf("Failed to create recorder: %v", err)
+		}
+		recorder = replay.NewBufferedRecorder(log, fileRecorder, 1024)
+		opts = append(opts, webhook.WithRecorder(recorder, key))
+	}
+	if bundleDigest != "" {
+		opts = append(opts, webhook.WithBundleDigest(bundleDigest))
+	}
+	if authorizeCreators {
+		opts = append(opts, webhook.WithAuthorizer(webhook.NewSubjectAccessReviewAuthorizer(client)))
+	}
+	if emitEvents {
+		opts = append(opts, webhook.WithEventRecorder(mgr.GetEventRecorderFor("ccrn-webhook")))
+		if namespaceEvents {
+			opts = append(opts, webhook.WithNamespaceEvents())
+		}
+	}
+	if namespaceConfigs {
+		store := namespaceconfig.NewStore()
+		for _, group := range apis.ParseGroups(ccrnGroup) {
+			err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+				return namespaceconfig.Watch(ctx, log, dynamicClient, group, store)
+			}))
+			if err != nil {
+				log.Fatalf("Failed to watch CCRNConfigs of %s: %v", group, err)
+			}
+		}
+		opts = append(opts, webhook.WithNamespaceConfigs(store))
+	}
+	if fieldResolvers != "" {
+		for _, pair := range strings.Split(fieldResolvers, ",") {
+			field, rawURL, found := strings.Cut(pair, "=")
+			if !found || field == "" {
+				log.Fatalf("Invalid field resolver %q, use <field>=<url>", pair)
+			}
+			fieldResolver, err := resolver.NewFromURL(rawURL, resolverTimeout, resolverCacheTTL, resolver.WithMaxEntries(resolverCacheSize))
+			if err != nil {
+				log.Fatalf("Failed to create field resolver for %s: %v", field, err)
+			}
+			opts = append(opts, webhook.WithFieldResolver(field, fieldResolver))
+		}
+		opts = append(opts, webhook.WithResolverTimeout(resolverTimeout))
+	}
+	if annotateResources != "" {
+		fields := map[string]string{}
+		for _, pair := range strings.Split(annotateFields, ",") {
+			if pair == "" {
+				continue
+			}
+			field, value, found := strings.Cut(pair, "=")
+			if !found || field == "" {
+				log.Fatalf("Invalid annotation field %q, use <field>=<value>", pair)
+			}
+			fields[field] = value
+		}
+		opts = append(opts, webhook.WithWorkloadAnnotation(strings.Split(annotateResources, ","), fields))
+	}
+	if regoPolicies != "" {
+		engine, err := opa.LoadEngine(context.Background(), regoPackage, strings.Split(regoPolicies, ",")...)
+		if err != nil {
+			log.Fatalf("Failed to load Rego policies: %v", err)
+		}
+		opts = append(opts, webhook.WithPolicyEngines(engine))
+	}
+	if plugins != "" {
+		for _, file := range strings.Split(plugins, ",") {
+			engine, err := plugin.Load(context.Background(), file, pluginTimeout)
+			if err != nil {
+				log.Fatalf("Failed to load plugin %s: %v", file, err)
+			}
+			opts = append(opts, webhook.WithPolicyEngines(engine))
+		}
+	}
+	if policyRules != "" {
+		engine, err := policy.LoadEngine(policyRules)
+		if err != nil {
+			log.Fatalf("Failed to load policy rules: %v", err)
+		}
+		opts = append(opts, webhook.WithPolicyEngines(engine))
+		ctrlmetrics.Registry.MustRegister(policy.Collector())
+	}
+
+	// Validate against the CRDs installed in the cluster, with a backend and cache per CCRN group
+	backend, err := validation.NewMultiGroupBackend(apis.ParseGroups(ccrnGroup), func(group string) (apis.ValidationBackend, error) {
+		groupBackend, err := validation.NewKubernetesBackend(restConfig, log, group)
+		if err != nil {
+			return nil, err
+		}
+		groupBackend.StartRefreshLoop(5 * time.Minute)
+		return groupBackend, nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes backend: %v", err)
+	}
+
+	// Delete the derived resources of deleted CCRN resources, the webhook adds the finalizer waiting for it
+	if derived, ok := backend.(apis.DerivedResourceManager); ok {
+		controller := cleanup.NewController(log, dynamicClient, derived)
+		for _, group := range apis.ParseGroups(ccrnGroup) {
+			err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+				return controller.Watch(ctx, group)
+			}))
+			if err != nil {
+				log.Fatalf("Failed to watch the CCRNs of %s: %v", group, err)
+			}
+		}
+	}
+
+	// Periodically flag CCRN resources whose identified objects no longer exist
+	if driftInterval > 0 {
+		reconciler, err := drift.NewReconcilerForConfig(log, backend, validation.NewCCRNValidator(backend), restConfig)
+		if err != nil {
+			log.Fatalf("Failed to create drift reconciler: %v", err)
+		}
+		err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return reconciler.Run(ctx, driftInterval)
+		}))
+		if err != nil {
+			log.Fatalf("Failed to start drift detection: %v", err)
+		}
+		ctrlmetrics.Registry.MustRegister(drift.Collector())
+	}
+
+	server, err := webhook.NewWebhookServer(log, backend, opts...)
+	if err != nil {
+		log.Fatalf("Failed to create webhook server: %v", err)
+	}
+	if err := server.SetupWithManager(mgr); err != nil {
+		log.Fatalf("Failed to set up webhook server: %v", err)
+	}
+	server.StartBundleVerification(time.Minute)
 
-	// Wait for shutdown signal or error
-	select {
-	case err := <-errCh:
+	// Run until SIGINT or SIGTERM
+	log.Infof("Starting webhook server on port %d", port)
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		flush()
 		log.Fatalf("Webhook server failed: %v", err)
-	case <-stop:
-		log.Info("Received shutdown signal, exiting...")
 	}
+	log.Info("Received shutdown signal, exiting...")
+	flush()
 }
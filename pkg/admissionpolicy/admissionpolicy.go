@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package admissionpolicy generates a Kubernetes ValidatingAdmissionPolicy and its binding from the CCRN CRDs,
+// so clusters from Kubernetes 1.30 on enforce the structural validity of CCRN resources even when the webhook
+// is down. The CEL rules only check what is derivable from the CRDs without parsing: the resource type version
+// of CCRNs and their required fields, and the resource type version and URN template of URNs. Everything the
+// policy denies is denied by the webhook as well, the webhook remains responsible for the full validation.
+package admissionpolicy
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// DefaultName is the default name of the policy and its binding
+const DefaultName = "ccrn-structure"
+
+// Options configure the generated policy
+type Options struct {
+	Name    string                                     // Name of the policy and its binding, defaults to DefaultName
+	Group   string                                     // CCRN API group, the policy applies to the ccrns of validate.<Group>
+	Actions []admissionregistrationv1.ValidationAction // Actions of the binding on violations, defaults to Deny
+}
+
+// Generate returns the policy and its binding for the resource type versions of the CRDs
+func Generate(crds []*apis.CRDInfo, opts Options) (*admissionregistrationv1.ValidatingAdmissionPolicy, *admissionregistrationv1.ValidatingAdmissionPolicyBinding, error) {
+	if len(crds) == 0 {
+		return nil, nil, fmt.Errorf("no resource types to generate a policy for")
+	}
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if len(opts.Actions) == 0 {
+		opts.Actions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}
+	}
+
+	validations, err := Validations(crds)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "ValidatingAdmissionPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+			FailurePolicy: ptr.To(admissionregistrationv1.Fail),
+			MatchConstraints: &admissionregistrationv1.MatchResources{
+				ResourceRules: []admissionregistrationv1.NamedRuleWithOperations{{
+					RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{"validate." + opts.Group},
+							APIVersions: []string{"*"},
+							Resources:   []string{"ccrns"},
+						},
+					},
+				}},
+			},
+			// The webhook validates spec.urn only without spec.ccrn
+			Variables: []admissionregistrationv1.Variable{
+				{Name: "ccrn", Expression: "has(object.spec) && has(object.spec.ccrn) ? object.spec.ccrn : ''"},
+				{Name: "urn", Expression: "variables.ccrn == '' && has(object.spec) && has(object.spec.urn) ? object.spec.urn : ''"},
+			},
+			Validations: validations,
+		},
+	}
+	binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "ValidatingAdmissionPolicyBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        opts.Name,
+			ValidationActions: opts.Actions,
+		},
+	}
+	return policy, binding, nil
+}
+
+// Validations returns the CEL validations of the resource type versions of the CRDs. They expect the variables
+// ccrn and urn, holding spec.ccrn and, if it is empty, spec.urn or the empty string.
+func Validations(crds []*apis.CRDInfo) ([]admissionregistrationv1.Validation, error) {
+	types := resourceTypes(crds)
+	validations := []admissionregistrationv1.Validation{{
+		Expression: "variables.ccrn != '' || variables.urn != ''",
+		Message:    "Resource must have either spec.ccrn or spec.urn defined",
+	}}
+
+	var ccrnKeys, urnKeys []string
+	for _, t := range types {
+		ccrnKeys = append(ccrnKeys, t.keyPattern(t.versions))
+		if len(t.templated) > 0 {
+			urnKeys = append(urnKeys, t.keyPattern(t.templated))
+		}
+	}
+	validations = append(validations, admissionregistrationv1.Validation{
+		Expression: "variables.ccrn == '' || variables.ccrn.matches(" + quote(ccrnPrefix(strings.Join(ccrnKeys, "|"))) + ")",
+		Message:    "spec.ccrn must start with ccrn=<kind>.<group>/<version> of a known resource type version",
+	})
+	if len(urnKeys) == 0 {
+		validations = append(validations, admissionregistrationv1.Validation{
+			Expression: "variables.urn == ''",
+			Message:    "spec.urn is not supported, no resource type declares a URN template",
+		})
+	} else {
+		validations = append(validations, admissionregistrationv1.Validation{
+			Expression: "variables.urn == '' || variables.urn.matches(" + quote(urnPrefix(strings.Join(urnKeys, "|"))) + ")",
+			Message:    "spec.urn must start with urn:<NID>:<kind>.<group>/<version> of a resource type version with a URN template",
+		})
+	}
+
+	for _, t := range types {
+		for _, crd := range t.crds {
+			key := t.keyPattern([]string{crd.Version})
+			if required := requiredFields(crd); len(required) > 0 {
+				checks := make([]string, 0, len(required))
+				for _, field := range required {
+					checks = append(checks, "variables.ccrn.matches("+quote(`,\s*`+regexp.QuoteMeta(field)+`(?:\.[^=,]*)?\s*=`)+")")
+				}
+				validations = append(validations, admissionregistrationv1.Validation{
+					Expression: "!variables.ccrn.matches(" + quote(ccrnPrefix(key)) + ") || (" + strings.Join(checks, " && ") + ")",
+					Message:    fmt.Sprintf("spec.ccrn of %s must set the required fields %s", crd.CCRNKey(), strings.Join(required, ", ")),
+				})
+			}
+
+			if crd.URNFormat == "" {
+				continue
+			}
+			template, err := apis.CompileTemplate(crd.URNFormat)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URN template of %s: %w", crd.CCRNKey(), err)
+			}
+			pattern := template.Pattern(func(field string) string {
+				if field == "ccrn" {
+					return key
+				}
+				return fieldPattern(crd, field)
+			})
+			// Allow RFC 8141 components like ?=version=2 after the URN
+			pattern = strings.TrimSuffix(pattern, "$") + `(?:[?#].*)?$`
+			validations = append(validations, admissionregistrationv1.Validation{
+				Expression: "!variables.urn.matches(" + quote(urnPrefix(key)) + ") || variables.urn.matches(" + quote(pattern) + ")",
+				Message:    fmt.Sprintf("spec.urn of %s must match the URN template %s", crd.CCRNKey(), crd.URNFormat),
+			})
+		}
+	}
+	return validations, nil
+}
+
+// Marshal returns the policy and its binding as YAML documents ready for kubectl apply
+func Marshal(policy *admissionregistrationv1.ValidatingAdmissionPolicy, binding *admissionregistrationv1.ValidatingAdmissionPolicyBinding) ([]byte, error) {
+	var documents []string
+	for _, object := range []runtime.Object{policy, binding} {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+		if err != nil {
+			return nil, err
+		}
+		// Leave out the fields of an empty object meta and status
+		delete(content["metadata"].(map[string]any), "creationTimestamp")
+		delete(content, "status")
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, string(data))
+	}
+	return []byte(strings.Join(documents, "---\n")), nil
+}
+
+// resourceType are the versions of a resource type
+type resourceType struct {
+	names     []string        // Name "<kind>.<group>" and aliases
+	versions  []string        // Versions of the resource type
+	templated []string        // Versions with URN template
+	crds      []*apis.CRDInfo // CRD versions in order of versions
+}
+
+// resourceTypes groups the CRD versions by resource type, sorted by name and version
+func resourceTypes(crds []*apis.CRDInfo) []*resourceType {
+	sorted := append([]*apis.CRDInfo{}, crds...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CCRNKey() < sorted[j].CCRNKey()
+	})
+
+	var types []*resourceType
+	byName := map[string]*resourceType{}
+	for _, crd := range sorted {
+		name := strings.ToLower(crd.Kind + "." + crd.Group)
+		t, exists := byName[name]
+		if !exists {
+			t = &resourceType{names: []string{name}}
+			byName[name] = t
+			types = append(types, t)
+		}
+		for _, alias := range crd.Aliases {
+			if !slices.Contains(t.names, alias) {
+				t.names = append(t.names, alias)
+			}
+		}
+		t.versions = append(t.versions, crd.Version)
+		if crd.URNFormat != "" {
+			t.templated = append(t.templated, crd.Version)
+		}
+		t.crds = append(t.crds, crd)
+	}
+	return types
+}
+
+// keyPattern returns the expression of the CCRN keys of the resource type with one of the versions. The
+// resource type is matched case-insensitively, the latest version only if all versions are given.
+func (t *resourceType) keyPattern(versions []string) string {
+	names := make([]string, 0, len(t.names))
+	for _, name := range t.names {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	alternatives := make([]string, 0, len(versions)+1)
+	for _, version := range versions {
+		alternatives = append(alternatives, regexp.QuoteMeta(version))
+	}
+	if len(versions) == len(t.versions) {
+		alternatives = append(alternatives, apis.LatestVersion)
+	}
+	return "(?:(?i:" + strings.Join(names, "|") + ")/(?:" + strings.Join(alternatives, "|") + "))"
+}
+
+// ccrnPrefix returns the expression of CCRNs starting with one of the keys, possibly quoted
+func ccrnPrefix(keys string) string {
+	return `^ccrn=\s*"?(?:` + keys + `)"?\s*(?:,|$)`
+}
+
+// urnPrefix returns the expression of URNs starting with one of the keys
+func urnPrefix(keys string) string {
+	return `^(?i:urn:)[^:/<>]+:(?:` + keys + `)(?:[/?#]|$)`
+}
+
+// requiredFields returns the required top-level fields of the CRD version without the ccrn field and
+// fields with a default value
+func requiredFields(crd *apis.CRDInfo) []string {
+	var required []string
+	for _, field := range crd.RequiredFields() {
+		if schema, declared := crd.FieldSchema(field); field == "ccrn" || (declared && schema.Default != nil) {
+			continue
+		}
+		required = append(required, field)
+	}
+	return required
+}
+
+// fieldPattern returns the expression of the values of a field of the CRD version in URNs, the alternation of
+// its enum values or its schema pattern. Patterns that are not anchored to the whole value are left out.
+func fieldPattern(crd *apis.CRDInfo, field string) string {
+	info, declared := crd.Field(field)
+	switch {
+	case !declared:
+		return ""
+	case len(info.Enum) > 0:
+		values := make([]string, 0, len(info.Enum))
+		for _, value := range info.Enum {
+			values = append(values, regexp.QuoteMeta(value))
+		}
+		return strings.Join(values, "|")
+	case strings.HasPrefix(info.Pattern, "^") && strings.HasSuffix(info.Pattern, "$") && !strings.HasSuffix(info.Pattern, `\$`):
+		inner := info.Pattern[1 : len(info.Pattern)-1]
+		if strings.ContainsAny(inner, "^$") {
+			return ""
+		}
+		if _, err := regexp.Compile(inner); err != nil {
+			return ""
+		}
+		return inner
+	}
+	return ""
+}
+
+// quote returns the string as CEL string literal
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package admissionpolicy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/admissionpolicy"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+func TestAdmissionPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Admission Policy Suite")
+}
+
+// evaluate evaluates the variables and validations of the policy for the spec and returns the messages of the
+// failed validations
+func evaluate(policy *admissionregistrationv1.ValidatingAdmissionPolicy, spec map[string]any) []string {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType), cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)))
+	Expect(err).ToNot(HaveOccurred())
+	eval := func(expression string, variables map[string]any) any {
+		ast, issues := env.Compile(expression)
+		Expect(issues.Err()).ToNot(HaveOccurred(), expression)
+		program, err := env.Program(ast)
+		Expect(err).ToNot(HaveOccurred())
+		value, _, err := program.Eval(map[string]any{"object": map[string]any{"spec": spec}, "variables": variables})
+		Expect(err).ToNot(HaveOccurred(), expression)
+		return value.Value()
+	}
+
+	variables := map[string]any{}
+	for _, variable := range policy.Spec.Variables {
+		variables[variable.Name] = eval(variable.Expression, variables)
+	}
+	var failed []string
+	for _, validation := range policy.Spec.Validations {
+		if eval(validation.Expression, variables) != true {
+			failed = append(failed, validation.Message)
+		}
+	}
+	return failed
+}
+
+var _ = Describe("Generate", func() {
+	const (
+		podV1      = "pod.k8s.ccrn.example.com/v1"
+		podV1beta1 = "pod.k8s.ccrn.example.com/v1beta1"
+		volume     = "volume.storage.ccrn.example.com/v1"
+	)
+	maxLength := int64(63)
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"ccrn", "region", "namespace", "name", "tier"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"ccrn":      {Type: "string"},
+			"region":    {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"eu-de"`)}, {Raw: []byte(`"*"`)}}},
+			"namespace": {Type: "string", Pattern: "^[a-z0-9-]+$", MaxLength: &maxLength},
+			"name":      {Type: "string", Pattern: "^[a-z0-9-]+$"},
+			"tier":      {Type: "string", Default: &apiextensionsv1.JSON{Raw: []byte(`"gold"`)}},
+			"labels":    {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+		},
+	}
+	crds := []*apis.CRDInfo{
+		{Kind: "Pod", Group: "k8s.ccrn.example.com", Version: "v1", Schema: schema, Aliases: []string{"po"},
+			URNFormat: "urn:ccrn:<ccrn>/<region>/[<namespace>]/<name>"},
+		{Kind: "Pod", Group: "k8s.ccrn.example.com", Version: "v1beta1", Schema: schema, Aliases: []string{"po"}},
+		{Kind: "Volume", Group: "storage.ccrn.example.com", Version: "v1",
+			Schema: &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{"ccrn": {Type: "string"}}}},
+	}
+
+	var policy *admissionregistrationv1.ValidatingAdmissionPolicy
+
+	BeforeEach(func() {
+		var err error
+		policy, _, err = admissionpolicy.Generate(crds, admissionpolicy.Options{Group: "ccrn.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("matches the CCRN validation resources", func() {
+		// Act
+		policy, binding, err := admissionpolicy.Generate(crds, admissionpolicy.Options{
+			Name:    "ccrn",
+			Group:   "ccrn.example.com",
+			Actions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Warn},
+		})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy.Name).To(Equal("ccrn"))
+		Expect(policy.Spec.MatchConstraints.ResourceRules[0].APIGroups).To(Equal([]string{"validate.ccrn.example.com"}))
+		Expect(policy.Spec.MatchConstraints.ResourceRules[0].Resources).To(Equal([]string{"ccrns"}))
+		Expect(binding.Spec.PolicyName).To(Equal("ccrn"))
+		Expect(binding.Spec.ValidationActions).To(Equal([]admissionregistrationv1.ValidationAction{admissionregistrationv1.Warn}))
+	})
+
+	It("fails without resource types", func() {
+		// Act
+		_, _, err := admissionpolicy.Generate(nil, admissionpolicy.Options{})
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("admits structurally valid resources",
+		func(spec map[string]any) {
+			// Act & Assert
+			Expect(evaluate(policy, spec)).To(BeEmpty())
+		},
+		Entry("CCRN", map[string]any{"ccrn": "ccrn=" + podV1 + ", region=eu-de, namespace=shop, name=web"}),
+		Entry("CCRN with alias, latest version and nested field", map[string]any{"ccrn": `ccrn="PO/latest",region=*,namespace=shop,name=web,labels.app=web`}),
+		Entry("CCRN of a type without required fields", map[string]any{"ccrn": "ccrn=" + volume}),
+		Entry("URN", map[string]any{"urn": "urn:ccrn:" + podV1 + "/eu-de/shop/web"}),
+		Entry("URN without optional segment and with components", map[string]any{"urn": "URN:CCRN:po/v1/*/web?=version=2"}),
+		Entry("invalid URN next to a CCRN", map[string]any{"ccrn": "ccrn=" + volume, "urn": "urn:ccrn:unknown/v1/web"}),
+	)
+
+	DescribeTable("denies structurally invalid resources",
+		func(spec map[string]any, message string) {
+			// Act & Assert
+			Expect(evaluate(policy, spec)).To(ConsistOf(message))
+		},
+		Entry("no identifier", map[string]any{}, "Resource must have either spec.ccrn or spec.urn defined"),
+		Entry("unknown resource type", map[string]any{"ccrn": "ccrn=node.k8s.ccrn.example.com/v1, name=web"},
+			"spec.ccrn must start with ccrn=<kind>.<group>/<version> of a known resource type version"),
+		Entry("unknown version", map[string]any{"ccrn": "ccrn=" + volume + "2"},
+			"spec.ccrn must start with ccrn=<kind>.<group>/<version> of a known resource type version"),
+		Entry("missing required field", map[string]any{"ccrn": "ccrn=" + podV1beta1 + ", region=eu-de, name=web"},
+			"spec.ccrn of "+podV1beta1+" must set the required fields name, namespace, region"),
+		Entry("URN of a version without template", map[string]any{"urn": "urn:ccrn:" + podV1beta1 + "/eu-de/shop/web"},
+			"spec.urn must start with urn:<NID>:<kind>.<group>/<version> of a resource type version with a URN template"),
+		Entry("URN with an invalid enum value", map[string]any{"urn": "urn:ccrn:" + podV1 + "/us-west/shop/web"},
+			"spec.urn of "+podV1+" must match the URN template urn:ccrn:<ccrn>/<region>/[<namespace>]/<name>"),
+		Entry("URN with missing segments", map[string]any{"urn": "urn:ccrn:" + podV1 + "/eu-de"},
+			"spec.urn of "+podV1+" must match the URN template urn:ccrn:<ccrn>/<region>/[<namespace>]/<name>"),
+	)
+
+	It("denies URNs if no resource type has a URN template", func() {
+		// Arrange
+		policy, _, err := admissionpolicy.Generate(crds[1:], admissionpolicy.Options{Group: "ccrn.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		failed := evaluate(policy, map[string]any{"urn": "urn:ccrn:" + podV1beta1 + "/eu-de/shop/web"})
+		// Assert
+		Expect(failed).To(ConsistOf("spec.urn is not supported, no resource type declares a URN template"))
+	})
+
+	It("marshals the policy and the binding as YAML documents", func() {
+		// Arrange
+		policy, binding, err := admissionpolicy.Generate(crds, admissionpolicy.Options{Group: "ccrn.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		data, err := admissionpolicy.Marshal(policy, binding)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).ToNot(ContainSubstring("creationTimestamp"))
+		Expect(string(data)).ToNot(ContainSubstring("status"))
+		policyDocument, bindingDocument, found := strings.Cut(string(data), "---\n")
+		Expect(found).To(BeTrue())
+		var decoded admissionregistrationv1.ValidatingAdmissionPolicy
+		Expect(yaml.Unmarshal([]byte(policyDocument), &decoded)).To(Succeed())
+		Expect(decoded.Kind).To(Equal("ValidatingAdmissionPolicy"))
+		Expect(decoded.Spec.Validations).To(Equal(policy.Spec.Validations))
+		Expect(bindingDocument).To(HavePrefix("apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicyBinding\n"))
+	})
+})
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// LatestVersion can be used instead of a version in CCRNs and URNs, it resolves to the highest
+// served version of the resource type
+const LatestVersion = "latest"
+
+// AliasResolver resolves short names of resource types, so users can write "ccrn=pod/v1, ..."
+// instead of the full "ccrn=pod.k8s-registry.ccrn.example.com/v1, ...". Backends implement it
+// for the aliases declared in the CRD annotations.
+type AliasResolver interface {
+	// ResolveAlias returns the full resource type "<kind>.<group>" of a short name like "pod"
+	ResolveAlias(alias string) (string, bool)
+}
+
+// VersionResolver is implemented by backends knowing the served versions of resource types
+type VersionResolver interface {
+	// ResolveLatestVersion returns the highest served version of the resource type "<kind>.<group>"
+	ResolveLatestVersion(resourceType string) (string, bool)
+}
+
+// Aliases maps short names to full resource types "<kind>.<group>", names are case-insensitive
+type Aliases map[string]string
+
+// ResolveAlias implements AliasResolver
+func (a Aliases) ResolveAlias(alias string) (string, bool) {
+	resourceType, exists := a[strings.ToLower(alias)]
+	return resourceType, exists
+}
+
+// ParseAliases parses comma separated <alias>=<kind>.<group> pairs like "pod=pod.k8s-registry.ccrn.example.com"
+func ParseAliases(s string) (Aliases, error) {
+	aliases := Aliases{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		alias, resourceType, found := strings.Cut(pair, "=")
+		alias, resourceType = strings.TrimSpace(alias), strings.TrimSpace(resourceType)
+		if !found || alias == "" || strings.Contains(alias, ".") || !strings.Contains(resourceType, ".") {
+			return nil, fmt.Errorf("invalid alias %q: expected <alias>=<kind>.<group>", pair)
+		}
+		aliases[strings.ToLower(alias)] = strings.ToLower(resourceType)
+	}
+	return aliases, nil
+}
+
+// ResolveCCRNKey resolves the resource type of a CCRN key like "pod/v1" with the first resolver knowing it.
+// Full resource types contain a dot and are returned unchanged, as are unknown aliases.
+func ResolveCCRNKey(key string, resolvers ...AliasResolver) string {
+	resourceType, version, hasVersion := strings.Cut(key, "/")
+	if resourceType == "" || strings.Contains(resourceType, ".") {
+		return key
+	}
+	for _, resolver := range resolvers {
+		if resolved, exists := resolver.ResolveAlias(resourceType); exists {
+			if hasVersion {
+				return resolved + "/" + version
+			}
+			return resolved
+		}
+	}
+	return key
+}
+
+// HighestVersion returns the highest of the Kubernetes versions, GA before beta before alpha versions
+// like v2, v1, v1beta1, v1alpha1
+func HighestVersion(versions []string) string {
+	highest := ""
+	for _, v := range versions {
+		if highest == "" || version.CompareKubeAwareVersionStrings(v, highest) > 0 {
+			highest = v
+		}
+	}
+	return highest
+}
+
+// HighestVersions returns the highest version of every resource type of the CRD versions, sorted by resource type
+func HighestVersions(crds []*CRDInfo) []*CRDInfo {
+	versions := map[string][]string{}
+	byKey := map[string]*CRDInfo{}
+	var names []string
+	for _, crd := range crds {
+		name := strings.ToLower(crd.Kind + "." + crd.Group)
+		if versions[name] == nil {
+			names = append(names, name)
+		}
+		versions[name] = append(versions[name], crd.Version)
+		byKey[crd.CCRNKey()] = crd
+	}
+	sort.Strings(names)
+
+	highest := make([]*CRDInfo, 0, len(names))
+	for _, name := range names {
+		highest = append(highest, byKey[name+"/"+HighestVersion(versions[name])])
+	}
+	return highest
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Aliases", func() {
+	It("parses alias pairs", func() {
+		// Act
+		aliases, err := apis.ParseAliases("pod=pod.k8s-registry.ccrn.example.com, Deploy=Deployment.k8s-registry.ccrn.example.com")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aliases).To(Equal(apis.Aliases{
+			"pod":    "pod.k8s-registry.ccrn.example.com",
+			"deploy": "deployment.k8s-registry.ccrn.example.com",
+		}))
+	})
+
+	It("rejects aliases without full resource type", func() {
+		// Act
+		_, err := apis.ParseAliases("pod=pod")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`invalid alias "pod=pod"`)))
+	})
+
+	DescribeTable("resolves the resource type of CCRN keys",
+		func(key, expected string) {
+			// Arrange
+			aliases := apis.Aliases{"pod": "pod.k8s-registry.ccrn.example.com"}
+			// Act & Assert
+			Expect(apis.ResolveCCRNKey(key, aliases)).To(Equal(expected))
+		},
+		Entry("alias with version", "pod/v1", "pod.k8s-registry.ccrn.example.com/v1"),
+		Entry("alias without version", "Pod", "pod.k8s-registry.ccrn.example.com"),
+		Entry("full resource type", "pod.k8s-registry.ccrn.example.com/v1", "pod.k8s-registry.ccrn.example.com/v1"),
+		Entry("unknown alias", "node/v1", "node/v1"),
+	)
+})
+
+var _ = Describe("HighestVersion", func() {
+	It("orders versions like Kubernetes", func() {
+		// Act & Assert
+		Expect(apis.HighestVersion([]string{"v1alpha1", "v1", "v2beta1", "v1beta2"})).To(Equal("v1"))
+		Expect(apis.HighestVersion([]string{"v1", "v2"})).To(Equal("v2"))
+		Expect(apis.HighestVersion(nil)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("HighestVersions", func() {
+	It("returns the highest version of every resource type", func() {
+		// Arrange
+		crds := []*apis.CRDInfo{
+			{Kind: "widget", Group: "tr.ccrn.example.com", Version: "v1alpha1"},
+			{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1"},
+			{Kind: "widget", Group: "tr.ccrn.example.com", Version: "v1"},
+			{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v2beta1"},
+		}
+		// Act
+		highest := apis.HighestVersions(crds)
+		// Assert
+		Expect(highest).To(Equal([]*apis.CRDInfo{crds[1], crds[2]}))
+	})
+})
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"testing"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Run with e.g. "make bench" or "go test ./pkg/apis -run '^$' -bench . -benchmem", compare runs with benchstat.
+
+const (
+	benchCCRN     = "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"
+	benchQuoted   = `ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name="app=frontend, blue", zones=[a, b]`
+	benchURN      = "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"
+	benchTemplate = "urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>"
+)
+
+// allocationBudget is the maximum number of allocations per operation of the hot path, raise it only
+// with a reason in the review
+var allocationBudget = map[string]float64{
+	"ParseCCRN":     6,
+	"ParseURN":      40,
+	"CanonicalCCRN": 35,
+}
+
+func BenchmarkParseCCRN(b *testing.B) {
+	for _, input := range []struct{ name, ccrn string }{{"plain", benchCCRN}, {"quoted", benchQuoted}} {
+		b.Run(input.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := apis.ParseCCRN(input.ccrn); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseURN(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := apis.ParseURN(benchURN, benchTemplate); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCanonicalCCRN(b *testing.B) {
+	parsed, err := apis.ParseCCRN(benchQuoted)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = parsed.CanonicalCCRN()
+	}
+}
+
+// TestAllocationBudget fails if an operation of the hot path allocates more than its budget
+func TestAllocationBudget(t *testing.T) {
+	parsed, err := apis.ParseCCRN(benchQuoted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operations := map[string]func(){
+		"ParseCCRN":     func() { _, _ = apis.ParseCCRN(benchCCRN) },
+		"ParseURN":      func() { _, _ = apis.ParseURN(benchURN, benchTemplate) },
+		"CanonicalCCRN": func() { _ = parsed.CanonicalCCRN() },
+	}
+	for name, operation := range operations {
+		if allocs := testing.AllocsPerRun(100, operation); allocs > allocationBudget[name] {
+			t.Errorf("%s allocates %.0f times per operation, budget is %.0f", name, allocs, allocationBudget[name])
+		}
+	}
+}
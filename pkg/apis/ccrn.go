@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ParseCCRN parses a field-based CCRN string like "ccrn=pod.k8s.ccrn.example.com/v1, name=foo".
+// It needs no backend, the fields are not validated against the schema of the resource type.
+// Fields set more than once like "cluster=a, cluster=b" fail with ErrDuplicateField.
+func ParseCCRN(input string) (*ParsedResource, error) {
+	return parseCCRN(input, false)
+}
+
+// ParseCCRNKeepLast is like ParseCCRN, but the last value of fields set more than once wins,
+// see RepeatedFields
+func ParseCCRNKeepLast(input string) (*ParsedResource, error) {
+	return parseCCRN(input, true)
+}
+
+func parseCCRN(input string, keepLast bool) (*ParsedResource, error) {
+	fields, err := parseCCRNFields(input, keepLast)
+	if err != nil {
+		return nil, err
+	}
+	return &ParsedResource{
+		Format: "CCRN",
+		Fields: fields,
+		Raw:    input,
+	}, nil
+}
+
+// parseCCRNFields parses a CCRN string into fields.
+// Values containing commas, equals signs or quotes must be enclosed in double quotes,
+// within quoted values \" and \\ escape a quote and a backslash, see QuoteValue.
+// Keys and unquoted values are substrings of the input, common keys are interned.
+// Repeated keys are rejected unless keepLast is set, then the last value wins.
+func parseCCRNFields(ccrn string, keepLast bool) (map[string]string, error) {
+	if !strings.HasPrefix(ccrn, "ccrn=") {
+		return nil, &ParseError{Input: ccrn, Segment: 1, Expected: "'ccrn=' prefix", Got: ccrn, Err: ErrUnknownFormat}
+	}
+	fieldEntries, err := splitCCRNEntries(ccrn)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(fieldEntries))
+	for i, entry := range fieldEntries {
+		text := strings.TrimSpace(entry.text)
+		if text == "" {
+			continue
+		}
+		key, value, found := strings.Cut(text, "=")
+		if !found {
+			return nil, &ParseError{Input: ccrn, Segment: i + 1, Offset: entry.offset, Expected: "key=value", Got: text}
+		}
+		key = internKey(strings.TrimSpace(key))
+		value, err := unquoteValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, &ParseError{Input: ccrn, Segment: i + 1, Offset: entry.offset, Expected: err.Error() + " in value of " + key, Got: text}
+		}
+		if _, repeated := fields[key]; repeated && !keepLast {
+			return nil, &ParseError{Input: ccrn, Segment: i + 1, Offset: entry.offset, Expected: "a single value of " + key, Got: text, Err: ErrDuplicateField}
+		}
+		fields[key] = value
+	}
+	if _, exists := fields["ccrn"]; !exists {
+		return nil, &ParseError{Input: ccrn, Segment: 1, Expected: "ccrn=<kind>.<group>/<version>"}
+	}
+	return fields, nil
+}
+
+// RepeatedFields returns the sorted keys set more than once in a CCRN string, nil if there are none
+// or the input is no CCRN
+func RepeatedFields(input string) []string {
+	if !strings.HasPrefix(input, "ccrn=") {
+		return nil
+	}
+	entries, err := splitCCRNEntries(input)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(entries))
+	var repeated []string
+	for _, entry := range entries {
+		key, _, found := strings.Cut(strings.TrimSpace(entry.text), "=")
+		if key = strings.TrimSpace(key); !found {
+			continue
+		}
+		if seen[key] && !slices.Contains(repeated, key) {
+			repeated = append(repeated, key)
+		}
+		seen[key] = true
+	}
+	slices.Sort(repeated)
+	return repeated
+}
+
+// internKey returns the constant of a common field key, so the fields of a parsed CCRN do not keep
+// the whole input alive through their keys
+func internKey(key string) string {
+	switch key {
+	case "ccrn":
+		return "ccrn"
+	case "cluster":
+		return "cluster"
+	case "namespace":
+		return "namespace"
+	case "name":
+		return "name"
+	case "region":
+		return "region"
+	case "project":
+		return "project"
+	case "domain":
+		return "domain"
+	case "id":
+		return "id"
+	}
+	return key
+}
+
+// ccrnEntry is a single key=value field of a CCRN and its byte offset
+type ccrnEntry struct {
+	text   string
+	offset int
+}
+
+// splitCCRNEntries splits a CCRN at all commas outside of quoted and list values. A quoted value
+// starts with a double quote directly following the equals sign of a field, a list value with a
+// square bracket. Items of a list may be quoted as well.
+func splitCCRNEntries(ccrn string) ([]ccrnEntry, error) {
+	entries := make([]ccrnEntry, 0, strings.Count(ccrn, ",")+1)
+	start := 0
+	quoteStart, listStart, itemStart := -1, -1, -1
+	for i := 0; i < len(ccrn); i++ {
+		inQuotes, inList := quoteStart >= 0, listStart >= 0
+		switch c := ccrn[i]; {
+		case inQuotes && c == '\\':
+			i++ // Skip the escaped character
+		case inQuotes && c == '"':
+			quoteStart = -1
+		case inQuotes:
+		case c == '"' && inList && strings.TrimSpace(ccrn[itemStart+1:i]) == "":
+			quoteStart = i
+		case c == '"' && !inList && strings.HasSuffix(strings.TrimRight(ccrn[start:i], " "), "="):
+			quoteStart = i
+		case c == '[' && !inList && strings.HasSuffix(strings.TrimRight(ccrn[start:i], " "), "="):
+			listStart, itemStart = i, i
+		case c == ']' && inList:
+			listStart = -1
+		case c == ',' && inList:
+			itemStart = i
+		case c == ',':
+			entries = append(entries, ccrnEntry{text: ccrn[start:i], offset: start})
+			start = i + 1
+		}
+	}
+	if quoteStart >= 0 {
+		return nil, &ParseError{Input: ccrn, Segment: len(entries) + 1, Offset: quoteStart, Expected: "closing quote", Got: ccrn[quoteStart:]}
+	}
+	if listStart >= 0 {
+		return nil, &ParseError{Input: ccrn, Segment: len(entries) + 1, Offset: listStart, Expected: "closing bracket", Got: ccrn[listStart:]}
+	}
+	return append(entries, ccrnEntry{text: ccrn[start:], offset: start}), nil
+}
+
+// unquoteValue removes the enclosing quotes of a quoted value and resolves its escape sequences,
+// list values are brought into the canonical form of FormatList, unquoted values are returned as they are
+func unquoteValue(value string) (string, error) {
+	if strings.HasPrefix(value, "[") {
+		items, err := parseListItems(value)
+		if err != nil {
+			return "", err
+		}
+		return FormatList(items), nil
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value, nil
+	}
+
+	var b strings.Builder
+	inner := value[1 : len(value)-1]
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			if i+1 == len(inner) {
+				return "", errors.New("escaped character after backslash")
+			}
+			i++
+			b.WriteByte(inner[i])
+		case '"':
+			return "", errors.New("escaped quote")
+		default:
+			b.WriteByte(inner[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// parseListItems parses a list value like [a, "b,c"] into its unquoted items
+func parseListItems(value string) ([]string, error) {
+	if len(value) < 2 || value[0] != '[' || value[len(value)-1] != ']' {
+		return nil, errors.New("list enclosed in square brackets")
+	}
+	inner := value[1 : len(value)-1]
+	if strings.TrimSpace(inner) == "" {
+		return []string{}, nil
+	}
+
+	var items []string
+	start, inQuotes := 0, false
+	for i := 0; i <= len(inner); i++ {
+		switch {
+		case i == len(inner) || (!inQuotes && inner[i] == ','):
+			item, err := unquoteValue(strings.TrimSpace(inner[start:i]))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			start = i + 1
+		case inQuotes && inner[i] == '\\':
+			i++
+		case inner[i] == '"' && (inQuotes || strings.TrimSpace(inner[start:i]) == ""):
+			inQuotes = !inQuotes
+		case !inQuotes && (inner[i] == '[' || inner[i] == ']'):
+			return nil, errors.New("quoted list item containing square brackets")
+		}
+	}
+	return items, nil
+}
+
+// ListValue returns the items of a list value like "[a,b]". Field values enclosed in square brackets
+// are lists, use a quoted string item to express a single value with brackets.
+func ListValue(value string) ([]string, bool) {
+	items, err := parseListItems(value)
+	if err != nil {
+		return nil, false
+	}
+	return items, true
+}
+
+// FormatList renders the items as a list value in canonical form, items are quoted if needed
+func FormatList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		if item == "" || strings.ContainsAny(item, ",[]") {
+			quoted = append(quoted, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item)+`"`)
+			continue
+		}
+		quoted = append(quoted, QuoteValue(item))
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ParseCCRN", func() {
+	It("parses list values into their canonical form", func() {
+		// Act
+		parsed, err := apis.ParseCCRN(`ccrn=cluster.k8s.ccrn.example.com/v1, zones=[ a, "b,c" ,d], name=foo`)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(HaveKeyWithValue("zones", `[a,"b,c",d]`))
+		Expect(parsed.Fields).To(HaveKeyWithValue("name", "foo"))
+		items, isList := apis.ListValue(parsed.Fields["zones"])
+		Expect(isList).To(BeTrue())
+		Expect(items).To(Equal([]string{"a", "b,c", "d"}))
+	})
+
+	It("keeps list values when rendering the CCRN", func() {
+		// Arrange
+		input := `ccrn=cluster.k8s.ccrn.example.com/v1, name=foo, zones=[a,"b,c"]`
+		parsed, err := apis.ParseCCRN(input)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		reparsed, err := apis.ParseCCRN(parsed.CanonicalCCRN())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.CanonicalCCRN()).To(Equal(input))
+		Expect(reparsed.Fields).To(Equal(parsed.Fields))
+	})
+
+	It("quotes values starting with a bracket that are no canonical list", func() {
+		// Arrange
+		parsed := &apis.ParsedResource{Fields: map[string]string{"ccrn": "cluster.k8s.ccrn.example.com/v1", "name": "[a", "zones": "[a, b]"}}
+		// Act
+		reparsed, err := apis.ParseCCRN(parsed.CanonicalCCRN())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.CanonicalCCRN()).To(Equal(`ccrn=cluster.k8s.ccrn.example.com/v1, name="[a", zones="[a, b]"`))
+		Expect(reparsed.Fields).To(Equal(parsed.Fields))
+	})
+
+	It("rejects unterminated lists", func() {
+		// Act
+		_, err := apis.ParseCCRN("ccrn=cluster.k8s.ccrn.example.com/v1, zones=[a,b")
+		// Assert
+		var parseErr *apis.ParseError
+		Expect(err).To(BeAssignableToTypeOf(parseErr))
+		Expect(err.Error()).To(ContainSubstring("closing bracket"))
+	})
+
+	It("rejects fields set more than once", func() {
+		// Act
+		_, err := apis.ParseCCRN("ccrn=cluster.k8s.ccrn.example.com/v1, name=foo, cluster=a, cluster=b")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrDuplicateField))
+		Expect(err.Error()).To(Equal("segment 4: expected a single value of cluster, got 'cluster=b'"))
+	})
+
+	It("keeps the last value of fields set more than once if asked to", func() {
+		// Arrange
+		input := "ccrn=cluster.k8s.ccrn.example.com/v1, cluster=a, name=foo, cluster=b, name=bar, zone=c"
+		// Act
+		parsed, err := apis.ParseCCRNKeepLast(input)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(HaveKeyWithValue("cluster", "b"))
+		Expect(apis.RepeatedFields(input)).To(Equal([]string{"cluster", "name"}))
+		Expect(apis.RepeatedFields("ccrn=cluster.k8s.ccrn.example.com/v1, name=\"a,name=b\"")).To(BeEmpty())
+	})
+
+	It("converts dot paths and lists to nested resource objects", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=cluster.k8s.ccrn.example.com/v1, name=foo, metadata.team=core, spec.network.zones=[a,b]")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		resourceObj := parsed.ToResourceMap("default", "foo-1")
+		// Assert
+		Expect(resourceObj).To(HaveKeyWithValue("name", "foo"))
+		Expect(resourceObj).To(HaveKeyWithValue("metadata", map[string]any{"name": "foo-1", "namespace": "default", "team": "core"}))
+		Expect(resourceObj).To(HaveKeyWithValue("spec", map[string]any{"network": map[string]any{"zones": []any{"a", "b"}}}))
+	})
+})
+
+var _ = Describe("CRDInfo", func() {
+	It("resolves dot paths through nested properties", func() {
+		// Arrange
+		crdInfo := &apis.CRDInfo{Schema: &apiextensionsv1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"name": {Type: "string"},
+				"spec": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"zones": {Type: "array"},
+				}},
+				"labels": {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+				}},
+			},
+		}}
+		// Act & Assert
+		Expect(crdInfo.HasField("name")).To(BeTrue())
+		Expect(crdInfo.HasField("spec.zones")).To(BeTrue())
+		Expect(crdInfo.HasField("spec.region")).To(BeFalse())
+		Expect(crdInfo.HasField("labels.app")).To(BeTrue())
+		Expect(crdInfo.HasField("name.first")).To(BeFalse())
+	})
+})
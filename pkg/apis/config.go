@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Strictness of the enforcement in a namespace
+const (
+	StrictnessEnforce = "Enforce" // Violations of enforced groups and policies deny the resource
+	StrictnessWarn    = "Warn"    // Violations of enforced groups and policies are returned as warnings
+)
+
+// CCRNConfig tunes the enforcement of CCRNs in its namespace, e.g. for the namespaces of a team
+type CCRNConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec CCRNConfigSpec `json:"spec"`
+}
+
+// CCRNConfigSpec defines the enforcement of CCRNs in a namespace
+type CCRNConfigSpec struct {
+	// EnforcedGroups are glob patterns of the groups of the resource types CCRNs may reference,
+	// e.g. "*.k8s-registry.ccrn.example.com", all groups are allowed if empty
+	EnforcedGroups []string `json:"enforcedGroups,omitempty"`
+	// Exemptions are users, groups and resource types that are not subject to enforced groups and policies
+	Exemptions []CCRNConfigExemption `json:"exemptions,omitempty"`
+	// Strictness is StrictnessEnforce, the default, or StrictnessWarn
+	Strictness string `json:"strictness,omitempty"`
+	// Defaults are the values of fields missing in CCRNs, e.g. the cluster of the namespace
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// CCRNConfigExemption exempts requests matching all of its non-empty lists, an exemption without any list
+// exempts nothing
+type CCRNConfigExemption struct {
+	Users         []string `json:"users,omitempty"`         // Names of exempted users
+	Groups        []string `json:"groups,omitempty"`        // Exempted groups of users
+	ResourceTypes []string `json:"resourceTypes,omitempty"` // Exempted resource types "<kind>.<group>"
+}
+
+// MergeConfigs merges the configs of a namespace in order: enforced groups and exemptions are combined,
+// later defaults override earlier ones and the namespace only warns if all configs only warn
+func MergeConfigs(configs ...CCRNConfigSpec) *CCRNConfigSpec {
+	merged := &CCRNConfigSpec{Strictness: StrictnessWarn}
+	for _, config := range configs {
+		merged.EnforcedGroups = append(merged.EnforcedGroups, config.EnforcedGroups...)
+		merged.Exemptions = append(merged.Exemptions, config.Exemptions...)
+		if config.Strictness != StrictnessWarn {
+			merged.Strictness = StrictnessEnforce
+		}
+		for field, value := range config.Defaults {
+			if merged.Defaults == nil {
+				merged.Defaults = map[string]string{}
+			}
+			merged.Defaults[field] = value
+		}
+	}
+	if len(configs) == 0 {
+		merged.Strictness = StrictnessEnforce
+	}
+	return merged
+}
+
+// WarnOnly reports whether violations of enforced groups and policies are only returned as warnings
+func (c *CCRNConfigSpec) WarnOnly() bool {
+	return c != nil && c.Strictness == StrictnessWarn
+}
+
+// EnforcesGroup reports whether CCRNs may reference resource types of the group
+func (c *CCRNConfigSpec) EnforcesGroup(group string) bool {
+	if c == nil || len(c.EnforcedGroups) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(c.EnforcedGroups, func(pattern string) bool {
+		matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(group))
+		return matched
+	})
+}
+
+// Exempts reports whether the user and the resource type "<kind>.<group>" match an exemption
+func (c *CCRNConfigSpec) Exempts(username string, groups []string, resourceType string) bool {
+	if c == nil {
+		return false
+	}
+	return slices.ContainsFunc(c.Exemptions, func(exemption CCRNConfigExemption) bool {
+		// An empty exemption must not turn off the enforcement of the namespace
+		if len(exemption.Users) == 0 && len(exemption.Groups) == 0 && len(exemption.ResourceTypes) == 0 {
+			return false
+		}
+		return (len(exemption.Users) == 0 || slices.Contains(exemption.Users, username)) &&
+			(len(exemption.Groups) == 0 || slices.ContainsFunc(exemption.Groups, func(group string) bool {
+				return slices.Contains(groups, group)
+			})) &&
+			(len(exemption.ResourceTypes) == 0 || slices.ContainsFunc(exemption.ResourceTypes, func(exempted string) bool {
+				return strings.EqualFold(exempted, resourceType)
+			}))
+	})
+}
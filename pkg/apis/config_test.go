@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("CCRNConfigSpec", func() {
+	It("enforces groups matching the patterns case-insensitively", func() {
+		// Arrange
+		config := &apis.CCRNConfigSpec{EnforcedGroups: []string{"*.k8s-registry.ccrn.example.com", "vault.ccrn.example.com"}}
+		// Act & Assert
+		Expect(config.EnforcesGroup("tr.k8s-registry.ccrn.example.com")).To(BeTrue())
+		Expect(config.EnforcesGroup("Vault.ccrn.example.com")).To(BeTrue())
+		Expect(config.EnforcesGroup("keystone.openstack.ccrn.example.com")).To(BeFalse())
+	})
+
+	It("enforces all groups without config or patterns", func() {
+		// Arrange
+		var config *apis.CCRNConfigSpec
+		// Act & Assert
+		Expect(config.EnforcesGroup("vault.ccrn.example.com")).To(BeTrue())
+		Expect((&apis.CCRNConfigSpec{}).EnforcesGroup("vault.ccrn.example.com")).To(BeTrue())
+		Expect(config.WarnOnly()).To(BeFalse())
+		Expect(config.Exempts("admin", nil, "pod.k8s-registry.ccrn.example.com")).To(BeFalse())
+	})
+
+	It("exempts requests matching all lists of an exemption", func() {
+		// Arrange
+		config := &apis.CCRNConfigSpec{Exemptions: []apis.CCRNConfigExemption{
+			{Groups: []string{"platform-admins"}, ResourceTypes: []string{"pod.k8s-registry.ccrn.example.com"}},
+			{Users: []string{"system:serviceaccount:flux-system:kustomize-controller"}},
+		}}
+		// Act & Assert
+		Expect(config.Exempts("jane", []string{"platform-admins"}, "Pod.k8s-registry.ccrn.example.com")).To(BeTrue())
+		Expect(config.Exempts("jane", []string{"platform-admins"}, "secret.vault.ccrn.example.com")).To(BeFalse())
+		Expect(config.Exempts("system:serviceaccount:flux-system:kustomize-controller", nil, "secret.vault.ccrn.example.com")).To(BeTrue())
+	})
+
+	It("exempts nothing with an exemption without criteria", func() {
+		// Arrange
+		config := &apis.CCRNConfigSpec{Exemptions: []apis.CCRNConfigExemption{{}, {Users: []string{}}}}
+		// Act & Assert
+		Expect(config.Exempts("jane", []string{"platform-admins"}, "pod.k8s-registry.ccrn.example.com")).To(BeFalse())
+	})
+
+	It("merges configs", func() {
+		// Act
+		merged := apis.MergeConfigs(
+			apis.CCRNConfigSpec{EnforcedGroups: []string{"vault.*"}, Strictness: apis.StrictnessWarn, Defaults: map[string]string{"cluster": "eu-de-1"}},
+			apis.CCRNConfigSpec{EnforcedGroups: []string{"keystone.*"}, Strictness: apis.StrictnessWarn, Defaults: map[string]string{"cluster": "eu-de-2"}},
+		)
+		// Assert
+		Expect(merged.EnforcedGroups).To(Equal([]string{"vault.*", "keystone.*"}))
+		Expect(merged.Defaults).To(Equal(map[string]string{"cluster": "eu-de-2"}))
+		Expect(merged.WarnOnly()).To(BeTrue())
+		Expect(apis.MergeConfigs().WarnOnly()).To(BeFalse())
+	})
+})
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// conformanceCase is a case of the conformance corpus in test/conformance, see its README for the fields
+type conformanceCase struct {
+	Name       string              `json:"name"`
+	Input      string              `json:"input"`
+	Template   string              `json:"template"`
+	Valid      bool                `json:"valid"`
+	Fields     map[string]string   `json:"fields"`
+	Components apis.URNComponents  `json:"components"`
+	Canonical  string              `json:"canonical"`
+	URN        string              `json:"urn"`
+	Error      *conformanceFailure `json:"error"`
+
+	loadErr error // Set if the corpus file cannot be read
+}
+
+// conformanceFailure is the expected failure of an invalid case
+type conformanceFailure struct {
+	Class   string `json:"class"`
+	Segment int    `json:"segment"`
+}
+
+// conformanceClasses maps the error classes of the corpus to the failure classes of the package
+var conformanceClasses = map[string]error{
+	"unknown_format":    apis.ErrUnknownFormat,
+	"template_mismatch": apis.ErrTemplateMismatch,
+	"duplicate_field":   apis.ErrDuplicateField,
+}
+
+// loadConformanceCases reads the cases of a file of the conformance corpus
+func loadConformanceCases(file string) ([]conformanceCase, error) {
+	content, err := os.ReadFile(filepath.Join("..", "..", "test", "conformance", file))
+	if err != nil {
+		return nil, err
+	}
+	var corpus struct {
+		Cases []conformanceCase `json:"cases"`
+	}
+	if err := json.Unmarshal(content, &corpus); err != nil {
+		return nil, err
+	}
+	return corpus.Cases, nil
+}
+
+// expectConformantFailure checks the error of an invalid case against its expected class and segment
+func expectConformantFailure(c conformanceCase, err error) {
+	Expect(err).To(HaveOccurred(), "%q must not parse", c.Input)
+	if c.Error == nil {
+		return
+	}
+	if c.Error.Class != "" {
+		class, known := conformanceClasses[c.Error.Class]
+		Expect(known).To(BeTrue(), "unknown error class %q", c.Error.Class)
+		Expect(errors.Is(err, class)).To(BeTrue(), "error %q of %q is not of class %s", err, c.Input, c.Error.Class)
+	}
+	if c.Error.Segment > 0 {
+		var parseErr *apis.ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue(), "error %q of %q is no parse error", err, c.Input)
+		Expect(parseErr.Segment).To(Equal(c.Error.Segment), "segment of error %q of %q", err, c.Input)
+	}
+}
+
+// conformanceEntries returns a table entry per case of the file, or a failing entry if it cannot be read
+func conformanceEntries(file string) []TableEntry {
+	cases, err := loadConformanceCases(file)
+	if err != nil {
+		return []TableEntry{Entry(file, conformanceCase{loadErr: err})}
+	}
+	entries := make([]TableEntry, 0, len(cases))
+	for _, c := range cases {
+		entries = append(entries, Entry(c.Name, c))
+	}
+	return entries
+}
+
+var _ = Describe("Conformance", func() {
+	DescribeTable("CCRN", func(c conformanceCase) {
+		Expect(c.loadErr).ToNot(HaveOccurred())
+
+		// Act
+		parsed, err := apis.ParseCCRN(c.Input)
+		// Assert
+		if !c.Valid {
+			expectConformantFailure(c, err)
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(Equal(c.Fields))
+		Expect(parsed.CanonicalCCRN()).To(Equal(c.Canonical))
+
+		// The canonical form may compose characters, it identifies the same resource and is stable
+		reparsed, err := apis.ParseCCRN(c.Canonical)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reparsed.Equals(parsed)).To(BeTrue())
+		Expect(reparsed.CanonicalCCRN()).To(Equal(c.Canonical))
+	}, conformanceEntries("ccrn.json"))
+
+	DescribeTable("URN", func(c conformanceCase) {
+		Expect(c.loadErr).ToNot(HaveOccurred())
+
+		// Act
+		parsed, err := apis.ParseURN(c.Input, c.Template)
+		// Assert
+		if !c.Valid {
+			expectConformantFailure(c, err)
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(Equal(c.Fields))
+		Expect(parsed.URNComponents).To(Equal(c.Components))
+		Expect(parsed.CanonicalCCRN()).To(Equal(c.Canonical))
+		Expect(apis.BuildURN(parsed, c.Template)).To(Equal(c.URN))
+	}, conformanceEntries("urn.json"))
+})
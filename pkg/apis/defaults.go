@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// ApplyDefaults sets the top-level fields missing in the parsed resource to the default values of their schema
+// and returns the names of the defaulted fields sorted by name. Arrays become list values, object defaults are
+// not applied. Without schema nothing is defaulted. It is used by the webhook and the CLI alike, so both add the
+// same fields.
+func ApplyDefaults(parsed *ParsedResource, info *CRDInfo) []string {
+	var defaulted []string
+	for _, field := range info.Fields() {
+		if strings.Contains(field.Name, ".") || field.Name == "ccrn" || field.Default == "" {
+			continue
+		}
+		if _, set := parsed.Fields[field.Name]; set {
+			continue
+		}
+		if parsed.Fields == nil {
+			parsed.Fields = map[string]string{}
+		}
+		parsed.Fields[field.Name] = field.Default
+		defaulted = append(defaulted, field.Name)
+	}
+	return defaulted
+}
+
+// defaultValue returns the default of the schema as field value, the empty string if it has none or it is an object
+func defaultValue(schema *v1.JSONSchemaProps) string {
+	if schema.Default == nil {
+		return ""
+	}
+	var value any
+	if err := json.Unmarshal(schema.Default.Raw, &value); err != nil {
+		return ""
+	}
+	switch value := value.(type) {
+	case nil, map[string]any:
+		return ""
+	case string:
+		return value
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+		return FormatList(items)
+	}
+	return fmt.Sprint(value)
+}
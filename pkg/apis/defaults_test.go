@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ApplyDefaults", func() {
+	var crdInfo *apis.CRDInfo
+
+	BeforeEach(func() {
+		crdInfo = &apis.CRDInfo{
+			Schema: &apiextensionsv1.JSONSchemaProps{Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"ccrn":    {Type: "string", Default: &apiextensionsv1.JSON{Raw: []byte(`"volume.example.com/v1"`)}},
+				"cluster": {Type: "string", Default: &apiextensionsv1.JSON{Raw: []byte(`"eu-de-1"`)}},
+				"name":    {Type: "string"},
+				"size":    {Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`10`)}},
+				"zones":   {Type: "array", Default: &apiextensionsv1.JSON{Raw: []byte(`["a","b,c"]`)}},
+				"settings": {Type: "object", Default: &apiextensionsv1.JSON{Raw: []byte(`{"tier":"gold"}`)}, Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"tier": {Type: "string", Default: &apiextensionsv1.JSON{Raw: []byte(`"silver"`)}},
+				}},
+			}},
+		}
+	})
+
+	It("sets the missing top-level fields to their defaults", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=volume.example.com/v1, name=data, size=20")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		defaulted := apis.ApplyDefaults(parsed, crdInfo)
+		// Assert
+		Expect(defaulted).To(Equal([]string{"cluster", "zones"}))
+		Expect(parsed.CanonicalCCRN()).To(Equal(`ccrn=volume.example.com/v1, cluster=eu-de-1, name=data, size=20, zones=[a,"b,c"]`))
+	})
+
+	It("sets nothing without schema", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=volume.example.com/v1, name=data")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		defaulted := apis.ApplyDefaults(parsed, nil)
+		// Assert
+		Expect(defaulted).To(BeEmpty())
+		Expect(parsed.Fields).To(HaveLen(2))
+	})
+
+	It("describes the defaults of the fields", func() {
+		// Act
+		field, declared := crdInfo.Field("size")
+		// Assert
+		Expect(declared).To(BeTrue())
+		Expect(field.Default).To(Equal("10"))
+	})
+})
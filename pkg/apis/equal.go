@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"sort"
+	"strings"
+)
+
+// Equals reports whether both parsed resources identify the same resource: the resource types
+// match case-insensitively, the versions match exactly and both have the same set of fields.
+// The order of the fields, the format they were parsed from and the Unicode normalization form of the
+// values do not matter, see NormalizeValue.
+func (p *ParsedResource) Equals(other *ParsedResource) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	if len(p.Fields) != len(other.Fields) {
+		return false
+	}
+	for key, value := range p.Fields {
+		otherValue, exists := other.Fields[key]
+		if !exists {
+			return false
+		}
+		if key == "ccrn" {
+			value, otherValue = normalizeCCRNKey(value), normalizeCCRNKey(otherValue)
+		}
+		if NormalizeValue(value) != NormalizeValue(otherValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// FieldDiff is a field whose value differs between two parsed resources, see Diff
+type FieldDiff struct {
+	Field string  `json:"field"`
+	A     *string `json:"a,omitempty"` // Value in the first resource, nil if the field is not set there
+	B     *string `json:"b,omitempty"` // Value in the second resource, nil if the field is not set there
+}
+
+// Diff returns the fields whose values differ between both parsed resources, the ccrn field first followed
+// by all other fields sorted by name. It is empty exactly if Equals reports both as the same resource.
+func Diff(a, b *ParsedResource) []FieldDiff {
+	fields := make(map[string]bool, len(a.Fields)+len(b.Fields))
+	for key := range a.Fields {
+		fields[key] = true
+	}
+	for key := range b.Fields {
+		fields[key] = true
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[j] != "ccrn" && (keys[i] == "ccrn" || keys[i] < keys[j])
+	})
+
+	var diffs []FieldDiff
+	for _, key := range keys {
+		valueA, inA := a.Fields[key]
+		valueB, inB := b.Fields[key]
+		if inA && inB && (NormalizeValue(valueA) == NormalizeValue(valueB) || key == "ccrn" && normalizeCCRNKey(valueA) == normalizeCCRNKey(valueB)) {
+			continue
+		}
+		diff := FieldDiff{Field: key}
+		if inA {
+			diff.A = &valueA
+		}
+		if inB {
+			diff.B = &valueB
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// Equal reports whether two CCRNs, or two URNs, identify the same resource, see ParsedResource.Equals.
+// URNs are compared segment by segment as the field names are only known from the URN template,
+// their RFC 8141 components are ignored.
+// Comparing a CCRN with a URN requires the URN template, parse both and use ParsedResource.Equals instead.
+func Equal(a, b string) (bool, error) {
+	_, _, aIsURN := SplitURNPrefix(a)
+	_, _, bIsURN := SplitURNPrefix(b)
+	if aIsURN && bIsURN {
+		return normalizeURN(a) == normalizeURN(b), nil
+	}
+	if aIsURN != bIsURN {
+		return false, Errorf(ErrTemplateMismatch, "cannot compare a CCRN with a URN without URN template")
+	}
+
+	parsedA, err := ParseCCRN(a)
+	if err != nil {
+		return false, err
+	}
+	parsedB, err := ParseCCRN(b)
+	if err != nil {
+		return false, err
+	}
+	return parsedA.Equals(parsedB), nil
+}
+
+// normalizeCCRNKey lowercases the resource type of a type/version key, the version is kept as is
+func normalizeCCRNKey(key string) string {
+	name, version, found := strings.Cut(key, "/")
+	if !found {
+		return strings.ToLower(key)
+	}
+	return strings.ToLower(name) + "/" + version
+}
+
+// normalizeURN lowercases the prefix and resource type segment of a URN and drops its RFC 8141 components
+func normalizeURN(urn string) string {
+	urn, _ = SplitURNComponents(urn)
+	prefix, body, _ := SplitURNPrefix(urn)
+	name, rest, _ := strings.Cut(body, "/")
+	return strings.ToLower(prefix) + strings.ToLower(name) + "/" + rest
+}
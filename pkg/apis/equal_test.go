@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Equal", func() {
+	DescribeTable("compares identifiers semantically",
+		func(a, b string, expected bool) {
+			// Act
+			equal, err := apis.Equal(a, b)
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(equal).To(Equal(expected))
+		},
+		Entry("reordered fields",
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo, cluster=c1", true),
+		Entry("quoted and unquoted values",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=\"foo\"",
+			"ccrn=pod.k8s.ccrn.example.com/v1,name=foo", true),
+		Entry("resource type case",
+			"ccrn=Pod.k8s.ccrn.example.com/v1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo", true),
+		Entry("different versions",
+			"ccrn=pod.k8s.ccrn.example.com/v1alpha1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo", false),
+		Entry("different values",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=bar", false),
+		Entry("additional fields",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, name=foo, cluster=c1", false),
+		Entry("URNs",
+			"urn:ccrn:Pod.k8s.ccrn.example.com/v1/c1/foo",
+			"urn:ccrn:pod.k8s.ccrn.example.com/v1/c1/foo", true),
+	)
+
+	It("refuses to compare a CCRN with a URN", func() {
+		// Act
+		_, err := apis.Equal("ccrn=pod.k8s.ccrn.example.com/v1, name=foo", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+	})
+
+	It("returns parse errors", func() {
+		// Act
+		_, err := apis.Equal("ccrn=pod.k8s.ccrn.example.com/v1, name", "ccrn=pod.k8s.ccrn.example.com/v1")
+		// Assert
+		var parseErr *apis.ParseError
+		Expect(err).To(BeAssignableToTypeOf(parseErr))
+	})
+
+	It("compares parsed resources regardless of their format", func() {
+		// Arrange
+		fromCCRN := &apis.ParsedResource{Format: "CCRN", Fields: map[string]string{"ccrn": "pod.k8s.ccrn.example.com/v1", "name": "foo"}}
+		fromURN := &apis.ParsedResource{Format: "URN", Fields: map[string]string{"name": "foo", "ccrn": "pod.k8s.ccrn.example.com/v1"}}
+		// Act & Assert
+		Expect(fromCCRN.Equals(fromURN)).To(BeTrue())
+		Expect(fromCCRN.Equals(nil)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Diff", func() {
+	It("returns the differing fields with the ccrn field first", func() {
+		// Arrange
+		a, err := apis.ParseCCRN("ccrn=Pod.k8s.ccrn.example.com/v1, name=foo, cluster=c1, zone=a")
+		Expect(err).ToNot(HaveOccurred())
+		b, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v2, cluster=c1, name=bar, region=eu")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		diffs := apis.Diff(a, b)
+		// Assert
+		value := func(s string) *string { return &s }
+		Expect(diffs).To(Equal([]apis.FieldDiff{
+			{Field: "ccrn", A: value("Pod.k8s.ccrn.example.com/v1"), B: value("pod.k8s.ccrn.example.com/v2")},
+			{Field: "name", A: value("foo"), B: value("bar")},
+			{Field: "region", B: value("eu")},
+			{Field: "zone", A: value("a")},
+		}))
+	})
+
+	It("is empty for equal resources", func() {
+		// Arrange
+		a, err := apis.ParseCCRN("ccrn=Pod.k8s.ccrn.example.com/v1, name=foo")
+		Expect(err).ToNot(HaveOccurred())
+		b, err := apis.ParseCCRN(`ccrn=pod.k8s.ccrn.example.com/v1, name="foo"`)
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.Diff(a, b)).To(BeEmpty())
+		Expect(a.Equals(b)).To(BeTrue())
+	})
+})
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Failure classes of the parser and the validation backends, use errors.Is to branch on them
+var (
+	ErrUnknownFormat    = errors.New("unknown format")                  // Input is neither a CCRN nor a URN
+	ErrUnsupportedType  = errors.New("unsupported resource type")       // The referenced resource type is not known
+	ErrTemplateMismatch = errors.New("URN does not match template")     // A URN does not match the URN template of its type
+	ErrCRDNotFound      = errors.New("CRD not found")                   // The CRD or its URN template could not be found
+	ErrSchemaViolation  = errors.New("resource violates schema of CRD") // The fields do not satisfy the schema of the resource type
+	ErrInvalidTemplate  = errors.New("invalid URN template")            // A URN template is malformed
+	ErrTooLong          = errors.New("identifier too long")             // A CCRN or URN exceeds the maximum length
+	ErrVersionSunset    = errors.New("version past its sunset date")    // The referenced version reached its sunset date
+	ErrDuplicateField   = errors.New("duplicate field")                 // A CCRN sets the same field more than once
+	ErrTooManyFields    = errors.New("too many fields")                 // An identifier exceeds the maximum number of fields, see Limits
+	ErrKeyTooLong       = errors.New("field key too long")              // A field key exceeds the maximum key length, see Limits
+	ErrTooDeep          = errors.New("field key nested too deeply")     // A dot path key exceeds the maximum depth, see Limits
+	ErrFieldNotSet      = errors.New("field not set")                   // A field requested from a parsed resource is not set
+)
+
+// classifiedError attaches a failure class to an error without changing its message
+type classifiedError struct {
+	err   error
+	class error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.class}
+}
+
+// Errorf formats an error like fmt.Errorf and marks it with the failure class, so errors.Is(err, class) holds
+func Errorf(class error, format string, args ...any) error {
+	return &classifiedError{err: fmt.Errorf(format, args...), class: class}
+}
+
+// ParseError describes where and why parsing a CCRN or URN failed, so users can be pointed
+// at the exact broken part of long strings
+type ParseError struct {
+	Input    string // The string that failed to parse
+	Segment  int    // 1-based index of the offending field (CCRN) or path segment (URN)
+	Offset   int    // Byte offset of the offending segment within Input
+	Expected string // Description of the expected token
+	Got      string // The offending segment, if any
+	Err      error  // Failure class like ErrUnknownFormat or ErrTemplateMismatch, if any
+}
+
+// Error implements error, e.g. "segment 3: expected key=value, got 'clustereu-de-1'"
+func (e *ParseError) Error() string {
+	if e.Got == "" {
+		return fmt.Sprintf("segment %d: expected %s", e.Segment, e.Expected)
+	}
+	return fmt.Sprintf("segment %d: expected %s, got '%s'", e.Segment, e.Expected, e.Got)
+}
+
+// Unwrap returns the failure class, so errors.Is(err, ErrTemplateMismatch) works on parse errors
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError describes a field value violating a length or character set constraint of the schema
+type FieldError struct {
+	Field  string // Name or dot path of the field
+	Value  string // The offending value, or list item
+	Reason string // The violated constraint, e.g. "longer than 63 characters"
+}
+
+// Error implements error, e.g. "field namespace: value 'Shop' does not match pattern ^[a-z]+$"
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: value '%s' %s", e.Field, e.Value, e.Reason)
+}
+
+// Unwrap classifies field errors as schema violations
+func (e *FieldError) Unwrap() error {
+	return ErrSchemaViolation
+}
+
+// ErrorMessages returns the messages of all errors joined in err, e.g. by errors.Join or CheckSchemaFields,
+// so every violation can be reported on its own. It returns nil for a nil error.
+func ErrorMessages(err error) []string {
+	if err == nil {
+		return nil
+	}
+	if _, classified := err.(*classifiedError); !classified {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			var messages []string
+			for _, inner := range joined.Unwrap() {
+				messages = append(messages, ErrorMessages(inner)...)
+			}
+			return messages
+		}
+	}
+	return []string{err.Error()}
+}
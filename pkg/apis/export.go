@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Formats of exported schema bundles, see ExportSchemas
+const (
+	SchemaFormatJSONSchema = "jsonschema" // JSON Schema draft 2020-12 with one definition per resource type version
+	SchemaFormatOpenAPI    = "openapi"    // OpenAPI 3.0 document with the schemas as components
+)
+
+// jsonSchemaDialect is the $schema of exported JSON Schema bundles
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaExporter is implemented by backends that can export the schemas of all resource types they know,
+// e.g. for editors and third-party validators
+type SchemaExporter interface {
+	// Export returns a self-contained schema bundle in the format, see ExportSchemas
+	Export(format string) (map[string]any, error)
+}
+
+// SchemaName returns the name of the schema of the CRD version in exported bundles, "<kind>.<group>.<version>"
+func (c *CRDInfo) SchemaName() string {
+	return strings.ToLower(c.Kind+"."+c.Group) + "." + c.Version
+}
+
+// ExportSchemas returns a self-contained bundle of the schemas of the CRD versions, describing the fields of
+// CCRNs as JSON objects like {"ccrn": "pod.k8s.ccrn.example.com/v1", "name": "web"}. The ccrn field is the
+// constant CCRN key, fields not declared by the schema are not allowed. Kubernetes extensions (x-kubernetes-*)
+// are removed, so strict validators like ajv accept the schemas. JSON Schema bundles define the schemas in $defs
+// and accept any of them, OpenAPI bundles list them in components.schemas.
+func ExportSchemas(crds []*CRDInfo, format string) (map[string]any, error) {
+	schemas := make(map[string]any, len(crds))
+	refs := make([]any, 0, len(crds))
+	refPrefix := "#/$defs/"
+	if format == SchemaFormatOpenAPI {
+		refPrefix = "#/components/schemas/"
+	}
+	for _, crd := range crds {
+		schema, err := exportSchema(crd, format)
+		if err != nil {
+			return nil, err
+		}
+		schemas[crd.SchemaName()] = schema
+		refs = append(refs, map[string]any{"$ref": refPrefix + crd.SchemaName()})
+	}
+
+	switch format {
+	case SchemaFormatJSONSchema:
+		return map[string]any{
+			"$schema":     jsonSchemaDialect,
+			"title":       "CCRN resource types",
+			"description": "Fields of the CCRNs of all resource types, the ccrn field selects the resource type version",
+			"oneOf":       refs,
+			"$defs":       schemas,
+		}, nil
+	case SchemaFormatOpenAPI:
+		return map[string]any{
+			"openapi":    "3.0.3",
+			"info":       map[string]any{"title": "CCRN resource types", "version": "1.0.0"},
+			"paths":      map[string]any{},
+			"components": map[string]any{"schemas": schemas},
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid schema format %q, use %s or %s", format, SchemaFormatJSONSchema, SchemaFormatOpenAPI)
+	}
+}
+
+// exportSchema returns the schema of the fields of the CRD version as generic JSON value
+func exportSchema(crd *CRDInfo, format string) (map[string]any, error) {
+	schema := map[string]any{"type": "object"}
+	if fields := crd.FieldsSchema(); fields != nil {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schema of %s: %w", crd.CCRNKey(), err)
+		}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schema of %s: %w", crd.CCRNKey(), err)
+		}
+		stripExtensions(schema, format)
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+		schema["properties"] = properties
+	}
+	ccrn, _ := properties["ccrn"].(map[string]any)
+	if ccrn == nil {
+		ccrn = map[string]any{"type": "string"}
+	}
+	delete(ccrn, "enum")
+	if format == SchemaFormatOpenAPI {
+		ccrn["enum"] = []any{crd.CCRNKey()}
+	} else {
+		ccrn["const"] = crd.CCRNKey()
+	}
+	properties["ccrn"] = ccrn
+	if !containsString(schema["required"], "ccrn") {
+		required, _ := schema["required"].([]any)
+		schema["required"] = append([]any{"ccrn"}, required...)
+	}
+	if _, hasAdditional := schema["additionalProperties"]; !hasAdditional {
+		schema["additionalProperties"] = false
+	}
+
+	schema["title"] = crd.CCRNKey()
+	if crd.URNFormat != "" {
+		if format == SchemaFormatOpenAPI {
+			schema["x-ccrn-urn-template"] = crd.URNFormat
+		} else {
+			schema["$comment"] = "URN template: " + crd.URNFormat
+		}
+	}
+	if crd.Deprecated {
+		schema["deprecated"] = true
+	}
+	return schema, nil
+}
+
+// stripExtensions removes the Kubernetes extensions from the schema and all nested schemas, and the
+// OpenAPI nullable keyword from JSON Schemas
+func stripExtensions(schema map[string]any, format string) {
+	for key, value := range schema {
+		if strings.HasPrefix(key, "x-kubernetes-") || (key == "nullable" && format != SchemaFormatOpenAPI) {
+			delete(schema, key)
+			continue
+		}
+		switch key {
+		case "properties", "patternProperties", "definitions":
+			// Maps of field names to schemas, field names are no keywords
+			nested, _ := value.(map[string]any)
+			for _, property := range nested {
+				if property, ok := property.(map[string]any); ok {
+					stripExtensions(property, format)
+				}
+			}
+		case "allOf", "anyOf", "oneOf":
+			nested, _ := value.([]any)
+			for _, item := range nested {
+				if item, ok := item.(map[string]any); ok {
+					stripExtensions(item, format)
+				}
+			}
+		case "items", "additionalProperties", "not":
+			if nested, ok := value.(map[string]any); ok {
+				stripExtensions(nested, format)
+			}
+		}
+	}
+}
+
+// containsString reports whether the JSON array contains the string
+func containsString(array any, s string) bool {
+	values, _ := array.([]any)
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ExportSchemas", func() {
+	preserve := true
+	crds := []*apis.CRDInfo{
+		{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1", URNFormat: "urn:ccrn:<ccrn>/<name>", Schema: &apiextensionsv1.JSONSchemaProps{
+			Type:     "object",
+			Required: []string{"name"},
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"ccrn":     {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"pod.k8s.ccrn.example.com/v1"`)}}},
+				"name":     {Type: "string", Nullable: true},
+				"nullable": {Type: "object", XPreserveUnknownFields: &preserve},
+			},
+		}},
+		{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1beta1", Deprecated: true},
+	}
+
+	It("defines a JSON Schema per resource type version", func() {
+		// Act
+		bundle, err := apis.ExportSchemas(crds, apis.SchemaFormatJSONSchema)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bundle).To(HaveKeyWithValue("$schema", "https://json-schema.org/draft/2020-12/schema"))
+		Expect(bundle["oneOf"]).To(Equal([]any{
+			map[string]any{"$ref": "#/$defs/pod.k8s.ccrn.example.com.v1"},
+			map[string]any{"$ref": "#/$defs/pod.k8s.ccrn.example.com.v1beta1"},
+		}))
+
+		schema := bundle["$defs"].(map[string]any)["pod.k8s.ccrn.example.com.v1"].(map[string]any)
+		Expect(schema).To(HaveKeyWithValue("required", []any{"ccrn", "name"}))
+		Expect(schema).To(HaveKeyWithValue("additionalProperties", false))
+		Expect(schema).To(HaveKeyWithValue("$comment", "URN template: urn:ccrn:<ccrn>/<name>"))
+		properties := schema["properties"].(map[string]any)
+		Expect(properties["ccrn"]).To(Equal(map[string]any{"type": "string", "const": "pod.k8s.ccrn.example.com/v1"}))
+		Expect(properties["name"]).To(Equal(map[string]any{"type": "string"}))
+		Expect(properties["nullable"]).To(Equal(map[string]any{"type": "object"}))
+	})
+
+	It("marks deprecated versions", func() {
+		// Act
+		bundle, err := apis.ExportSchemas(crds, apis.SchemaFormatJSONSchema)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bundle["$defs"]).To(HaveKeyWithValue("pod.k8s.ccrn.example.com.v1beta1", HaveKeyWithValue("deprecated", true)))
+	})
+
+	It("lists the schemas as OpenAPI components", func() {
+		// Act
+		bundle, err := apis.ExportSchemas(crds, apis.SchemaFormatOpenAPI)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bundle).To(HaveKeyWithValue("openapi", "3.0.3"))
+		schemas := bundle["components"].(map[string]any)["schemas"].(map[string]any)
+		schema := schemas["pod.k8s.ccrn.example.com.v1"].(map[string]any)
+		Expect(schema).To(HaveKeyWithValue("x-ccrn-urn-template", "urn:ccrn:<ccrn>/<name>"))
+		properties := schema["properties"].(map[string]any)
+		Expect(properties["ccrn"]).To(HaveKeyWithValue("enum", []any{"pod.k8s.ccrn.example.com/v1"}))
+		Expect(properties["name"]).To(HaveKeyWithValue("nullable", true))
+	})
+
+	It("rejects unknown formats", func() {
+		// Act
+		_, err := apis.ExportSchemas(crds, "xsd")
+		// Assert
+		Expect(err).To(MatchError(`invalid schema format "xsd", use jsonschema or openapi`))
+	})
+})
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"strings"
+)
+
+// FingerprintLength is the length of fingerprints, 32 base32 characters carry 160 bits of the hash
+const FingerprintLength = 32
+
+// fingerprintEncoding is lowercase base32 without padding, valid in label values and DNS-1123 names
+var fingerprintEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Fingerprint returns a stable, collision-resistant hash of the resource: the truncated SHA-256 of its
+// canonical CCRN, encoded in lowercase base32. Resources that are Equals have the same fingerprint,
+// so it can be used as label value, object name or deduplication key where the CCRN itself is too
+// long or contains invalid characters. Fingerprints of URNs parsed with their template match those
+// of the corresponding CCRNs, URN components are not part of the fingerprint.
+func (p *ParsedResource) Fingerprint() string {
+	normalized := &ParsedResource{Fields: make(map[string]string, len(p.Fields))}
+	for key, value := range p.Fields {
+		if key == "ccrn" {
+			value = normalizeCCRNKey(value)
+		}
+		normalized.Fields[key] = value
+	}
+
+	sum := sha256.Sum256([]byte(normalized.CanonicalCCRN()))
+	return strings.ToLower(fingerprintEncoding.EncodeToString(sum[:]))[:FingerprintLength]
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Fingerprint", func() {
+	fingerprint := func(ccrn string) string {
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed.Fingerprint()
+	}
+
+	It("is a valid label value", func() {
+		// Act
+		value := fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo")
+		// Assert
+		Expect(value).To(HaveLen(apis.FingerprintLength))
+		Expect(value).To(MatchRegexp(`^[a-z2-7]+$`))
+	})
+
+	It("is stable for equal resources", func() {
+		// Act & Assert
+		Expect(fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo")).To(Equal(
+			fingerprint(`ccrn=Pod.k8s.ccrn.example.com/v1,name="foo", cluster=eu-de-1`)))
+	})
+
+	It("differs for different resources", func() {
+		// Act & Assert
+		Expect(fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, name=foo")).ToNot(Equal(
+			fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, name=bar")))
+		Expect(fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, name=foo")).ToNot(Equal(
+			fingerprint("ccrn=pod.k8s.ccrn.example.com/v2, name=foo")))
+	})
+
+	It("does not change between releases", func() {
+		// Act & Assert
+		Expect(fingerprint("ccrn=pod.k8s.ccrn.example.com/v1, name=foo")).To(Equal("bqaoyqacueod7spydtyzmwlg5ij4h6q7"))
+	})
+})
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Run with e.g. "go test ./pkg/apis -run '^$' -fuzz FuzzParseCCRN -fuzztime 1m", inputs found to fail
+// are added to testdata/fuzz and replayed by every "go test" run.
+
+// FuzzParseCCRN checks that parsing never panics and that the canonical form of a parsed CCRN parses
+// to the same fields
+func FuzzParseCCRN(f *testing.F) {
+	f.Add("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo")
+	f.Add(`ccrn=pod.k8s.ccrn.example.com/v1, name="a,b=c", note="say \"hi\""`)
+	f.Add("ccrn=pod.k8s.ccrn.example.com/v1, zones=[a, \"b,c\"], labels.app=shop")
+	f.Add("ccrn=pod.k8s.ccrn.example.com/*, cluster=*")
+	f.Add("ccrn=")
+	f.Add(`ccrn=pod/v1, name="unterminated`)
+	f.Add("ccrn=pod/v1, zones=[a,b")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		parsed, err := apis.ParseCCRN(input)
+		if err != nil {
+			return
+		}
+		canonical := parsed.CanonicalCCRN()
+		reparsed, err := apis.ParseCCRN(canonical)
+		if err != nil {
+			t.Fatalf("canonical form %q of %q does not parse: %v", canonical, input, err)
+		}
+		if !maps.Equal(parsed.Fields, reparsed.Fields) {
+			t.Fatalf("canonical form %q of %q parses to %v, want %v", canonical, input, reparsed.Fields, parsed.Fields)
+		}
+	})
+}
+
+// FuzzParseURN checks that parsing URNs with arbitrary templates never panics and that parsed URNs
+// carry their resource type
+func FuzzParseURN(f *testing.F) {
+	f.Add("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/default/foo", "urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>")
+	f.Add("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/foo?+r=1?=q=2#f", "urn:ccrn:<ccrn>/<cluster>/[<namespace>]/<name>")
+	f.Add("urn:ccrn:pod.k8s.ccrn.example.com/v1/3/a/b/c", "urn:ccrn:<ccrn>/<replicas:int>/<path>")
+	f.Add("urn:ccrn:pod", "urn:ccrn:<ccrn>")
+	f.Add("urn:ccrn:/", "urn:ccrn:<ccrn>/<")
+	f.Add("urn:x:", "urn:x:[<a>]/[<b>]")
+
+	f.Fuzz(func(t *testing.T, urn, template string) {
+		parsed, err := apis.ParseURN(urn, template)
+		if err != nil {
+			return
+		}
+		if parsed.CCRNKey() == "" {
+			t.Fatalf("URN %q parsed with template %q has no resource type", urn, template)
+		}
+	})
+}
+
+// FuzzTemplateMatch checks that compiling templates and matching values never panics and that
+// rendering the matched fields yields a value the template matches again
+func FuzzTemplateMatch(f *testing.F) {
+	f.Add("urn:ccrn:<ccrn>/<region>-<az>/<name>", "pod.k8s.ccrn.example.com/v1/eu-de-1a/foo")
+	f.Add("urn:ccrn:<ccrn>/[<namespace>]/<name>", "pod.k8s.ccrn.example.com/v1/foo")
+	f.Add("urn:ccrn:<ccrn>/<name:dns>", "pod.k8s.ccrn.example.com/v1/Foo")
+	f.Add("urn:ccrn:<ccrn>/<id:uuid>", "pod.k8s.ccrn.example.com/v1/123e4567-e89b-12d3-a456-426614174000")
+	f.Add("urn:ccrn:<ccrn>/<a:(>", "x/y/z")
+	f.Add("urn:ccrn:<<>>", "")
+
+	f.Fuzz(func(t *testing.T, template, value string) {
+		compiled, err := apis.CompileTemplate(template)
+		if err != nil {
+			return
+		}
+		fields, err := compiled.Match(compiled.Prefix + value)
+		if err != nil {
+			return
+		}
+		rendered, err := compiled.Render(fields)
+		if err != nil {
+			return
+		}
+		if _, err := compiled.Match(rendered); err != nil {
+			t.Fatalf("template %q does not match %q rendered from the fields %v of %q: %v", template, rendered, fields, value, err)
+		}
+	})
+}
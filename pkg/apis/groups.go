@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import "strings"
+
+// ParseGroups splits a comma separated list of CCRN groups, e.g. the value of --ccrn-group while CRDs are
+// served under a legacy and a new group. Blank and duplicate groups are dropped.
+func ParseGroups(value string) []string {
+	var groups []string
+	seen := map[string]bool{}
+	for _, group := range strings.Split(value, ",") {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if group != "" && !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// MatchGroup returns the CCRN group the API group belongs to, i.e. the longest CCRN group it contains,
+// e.g. ccrn.example.com for k8s-registry.ccrn.example.com
+func MatchGroup(apiGroup string, ccrnGroups []string) (string, bool) {
+	apiGroup = strings.ToLower(apiGroup)
+	match, found := "", false
+	for _, group := range ccrnGroups {
+		if strings.Contains(apiGroup, group) && (!found || len(group) > len(match)) {
+			match, found = group, true
+		}
+	}
+	return match, found
+}
+
+// InGroups reports whether the API group belongs to one of the comma separated CCRN groups, all API groups
+// belong to an empty list
+func InGroups(apiGroup, ccrnGroups string) bool {
+	groups := ParseGroups(ccrnGroups)
+	if len(groups) == 0 {
+		return true
+	}
+	_, found := MatchGroup(apiGroup, groups)
+	return found
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Groups", func() {
+	It("parses comma separated groups", func() {
+		// Act & Assert
+		Expect(apis.ParseGroups(" ccrn.example.com, CCRN.legacy.example.com,,ccrn.example.com ")).To(Equal([]string{"ccrn.example.com", "ccrn.legacy.example.com"}))
+		Expect(apis.ParseGroups("")).To(BeEmpty())
+	})
+
+	It("matches the longest CCRN group of an API group", func() {
+		// Arrange
+		groups := []string{"example.com", "ccrn.example.com"}
+		// Act
+		group, found := apis.MatchGroup("k8s-registry.CCRN.example.com", groups)
+		// Assert
+		Expect(found).To(BeTrue())
+		Expect(group).To(Equal("ccrn.example.com"))
+		_, found = apis.MatchGroup("k8s-registry.ccrn.example.org", groups)
+		Expect(found).To(BeFalse())
+	})
+
+	It("reports whether an API group belongs to a list of CCRN groups", func() {
+		// Act & Assert
+		Expect(apis.InGroups("vault.ccrn.legacy.example.com", "ccrn.example.com,ccrn.legacy.example.com")).To(BeTrue())
+		Expect(apis.InGroups("vault.ccrn.example.org", "ccrn.example.com,ccrn.legacy.example.com")).To(BeFalse())
+		Expect(apis.InGroups("vault.ccrn.example.org", "")).To(BeTrue())
+	})
+})
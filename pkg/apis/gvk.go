@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionKind returns the group, version and kind of the referenced resource type. CCRNs carry
+// the kind in lowercase, use CRDInfo.GroupVersionKind or RESTMapping for the kind as declared in the CRD.
+func (p *ParsedResource) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: p.ApiGroup(), Version: p.Version(), Kind: p.GetKind()}
+}
+
+// RESTMapping resolves the referenced resource type with the REST mapper, e.g. the mapper of a
+// controller-runtime client, so the live objects can be fetched with a dynamic client
+func (p *ParsedResource) RESTMapping(mapper meta.RESTMapper) (*meta.RESTMapping, error) {
+	gvk := p.GroupVersionKind()
+	// The lowercase kind of a CCRN is the singular resource name, which REST mappers resolve
+	kind, err := mapper.KindFor(schema.GroupVersionResource{Group: gvk.Group, Version: gvk.Version, Resource: strings.ToLower(gvk.Kind)})
+	if err != nil {
+		return nil, Errorf(ErrUnsupportedType, "failed to map %s: %w", p.CCRNKey(), err)
+	}
+	mapping, err := mapper.RESTMapping(kind.GroupKind(), kind.Version)
+	if err != nil {
+		return nil, Errorf(ErrUnsupportedType, "failed to map %s: %w", p.CCRNKey(), err)
+	}
+	return mapping, nil
+}
+
+// GroupVersionKind returns the group, version and kind of the CRD version
+func (c *CRDInfo) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: c.Group, Version: c.Version, Kind: c.Kind}
+}
+
+// GroupVersionResource returns the group, version and plural resource name of the CRD version
+func (c *CRDInfo) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: c.Plural}
+}
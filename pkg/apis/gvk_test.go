@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("GroupVersionKind", func() {
+	var parsed *apis.ParsedResource
+
+	BeforeEach(func() {
+		var err error
+		parsed, err = apis.ParseCCRN("ccrn=testresource.tr.ccrn.example.com/v1, name=foo")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("splits the CCRN key", func() {
+		// Act & Assert
+		Expect(parsed.GroupVersionKind()).To(Equal(schema.GroupVersionKind{Group: "tr.ccrn.example.com", Version: "v1", Kind: "testresource"}))
+	})
+
+	It("maps the CRD to its GVK and GVR", func() {
+		// Arrange
+		crdInfo := &apis.CRDInfo{Kind: "TestResource", Plural: "testresources", Group: "tr.ccrn.example.com", Version: "v1"}
+		// Act & Assert
+		Expect(crdInfo.GroupVersionKind()).To(Equal(schema.GroupVersionKind{Group: "tr.ccrn.example.com", Version: "v1", Kind: "TestResource"}))
+		Expect(crdInfo.GroupVersionResource()).To(Equal(schema.GroupVersionResource{Group: "tr.ccrn.example.com", Version: "v1", Resource: "testresources"}))
+	})
+
+	It("resolves the kind and resource with a REST mapper", func() {
+		// Arrange
+		gv := schema.GroupVersion{Group: "tr.ccrn.example.com", Version: "v1"}
+		mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+		mapper.Add(gv.WithKind("TestResource"), meta.RESTScopeNamespace)
+		// Act
+		mapping, err := parsed.RESTMapping(mapper)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping.GroupVersionKind.Kind).To(Equal("TestResource"))
+		Expect(mapping.Resource).To(Equal(gv.WithResource("testresources")))
+	})
+
+	It("fails for unknown resource types", func() {
+		// Arrange
+		mapper := meta.NewDefaultRESTMapper(nil)
+		// Act
+		_, err := parsed.RESTMapping(mapper)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrUnsupportedType))
+	})
+})
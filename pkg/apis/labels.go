@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Labels set on CCRN objects by the LabelMutation feature of the webhook
+const (
+	LabelGroup       = "ccrn/group"       // Lowercase API group of the resource type
+	LabelKind        = "ccrn/kind"        // Lowercase kind of the resource type
+	LabelVersion     = "ccrn/version"     // Version of the resource type
+	LabelFingerprint = "ccrn/fingerprint" // Fingerprint of the CCRN, see ParsedResource.Fingerprint
+	FieldLabelPrefix = "ccrn/field."      // Prefix of the labels carrying the field values, e.g. "ccrn/field.cluster"
+)
+
+// FieldLabel returns the label key carrying the value of the field, it reports false if the field name
+// cannot be part of a label key
+func FieldLabel(field string) (string, bool) {
+	key := FieldLabelPrefix + field
+	return key, len(validation.IsQualifiedName(key)) == 0
+}
+
+// Labels returns the labels identifying the resource: its type, version and fingerprint, and a label per
+// field. Fields whose name or value cannot be represented as label are left out, so are list values.
+func (p *ParsedResource) Labels() map[string]string {
+	labels := map[string]string{LabelFingerprint: p.Fingerprint()}
+	typeLabels := map[string]string{
+		LabelGroup:   strings.ToLower(p.ApiGroup()),
+		LabelKind:    strings.ToLower(p.GetKind()),
+		LabelVersion: p.Version(),
+	}
+	for key, value := range typeLabels {
+		if value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			labels[key] = value
+		}
+	}
+	for field, value := range p.Fields {
+		if field == "ccrn" {
+			continue
+		}
+		if _, isList := ListValue(value); isList {
+			continue
+		}
+		key, ok := FieldLabel(field)
+		if ok && len(validation.IsValidLabelValue(value)) == 0 {
+			labels[key] = value
+		}
+	}
+	return labels
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Labels", func() {
+	It("labels the resource type, version, fingerprint and fields", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=Pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		labels := parsed.Labels()
+		// Assert
+		Expect(labels).To(Equal(map[string]string{
+			apis.LabelGroup:       "k8s.ccrn.example.com",
+			apis.LabelKind:        "pod",
+			apis.LabelVersion:     "v1",
+			apis.LabelFingerprint: parsed.Fingerprint(),
+			"ccrn/field.cluster":  "eu-de-1",
+			"ccrn/field.name":     "foo",
+		}))
+	})
+
+	It("leaves out fields that cannot be represented as label", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN(`ccrn=pod.k8s.ccrn.example.com/v1, name="a b", zones=[a,b], labels.app=shop`)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		labels := parsed.Labels()
+		// Assert
+		Expect(labels).ToNot(HaveKey("ccrn/field.name"))
+		Expect(labels).ToNot(HaveKey("ccrn/field.zones"))
+		Expect(labels).To(HaveKeyWithValue("ccrn/field.labels.app", "shop"))
+	})
+})
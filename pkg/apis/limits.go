@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/lru"
+)
+
+// DefaultMaxLength is the recommended maximum length of CCRNs and URNs, it keeps them usable
+// as annotation values, log fields and database keys
+const DefaultMaxLength = 1024
+
+// DefaultCompileCacheSize is the default number of compiled URN templates and schema patterns kept each, it
+// exceeds the templates and patterns of hundreds of resource types while bounding the memory if callers pass
+// arbitrary ones
+const DefaultCompileCacheSize = 4096
+
+// patternCache holds the compiled schema patterns, keyed by pattern
+var patternCache = lru.New[string, *regexp.Regexp]("patterns", DefaultCompileCacheSize)
+
+// SetCompileCacheSize sets the number of compiled URN templates and schema patterns kept each, the least
+// recently used ones are evicted first. A size of 0 does not bound the caches.
+func SetCompileCacheSize(size int) {
+	templateCache.Resize(size)
+	patternCache.Resize(size)
+}
+
+// CheckLength rejects inputs longer than maxLength characters, a maxLength <= 0 disables the check
+func CheckLength(input string, maxLength int) error {
+	if maxLength <= 0 {
+		return nil
+	}
+	if length := utf8.RuneCountInString(input); length > maxLength {
+		return Errorf(ErrTooLong, "%d characters exceed the maximum length of %d", length, maxLength)
+	}
+	return nil
+}
+
+// Limits bound the complexity of identifiers, so crafted identifiers cannot make validation expensive.
+// A limit <= 0 is disabled, see CheckComplexity.
+type Limits struct {
+	MaxFields    int // Maximum number of fields including ccrn
+	MaxKeyLength int // Maximum number of characters of a field key
+	MaxDepth     int // Maximum number of segments of dot path keys, e.g. 3 for spec.network.zones
+}
+
+// DefaultLimits are the recommended limits, far above the identifiers of real resource types
+var DefaultLimits = Limits{MaxFields: 64, MaxKeyLength: 128, MaxDepth: 8}
+
+// CheckComplexity rejects parsed identifiers exceeding the limits with ErrTooManyFields, ErrKeyTooLong
+// or ErrTooDeep. The keys are checked in sorted order, the first violation is reported.
+func CheckComplexity(parsed *ParsedResource, limits Limits) error {
+	if limits.MaxFields > 0 && len(parsed.Fields) > limits.MaxFields {
+		return Errorf(ErrTooManyFields, "%d fields exceed the maximum of %d", len(parsed.Fields), limits.MaxFields)
+	}
+	if limits.MaxKeyLength <= 0 && limits.MaxDepth <= 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(parsed.Fields))
+	for key := range parsed.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if length := utf8.RuneCountInString(key); limits.MaxKeyLength > 0 && length > limits.MaxKeyLength {
+			return Errorf(ErrKeyTooLong, "key %s of %d characters exceeds the maximum length of %d", truncateRunes(key, 32), length, limits.MaxKeyLength)
+		}
+		if depth := strings.Count(key, ".") + 1; limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return Errorf(ErrTooDeep, "key %s is nested %d levels deep, the maximum is %d", key, depth, limits.MaxDepth)
+		}
+	}
+	return nil
+}
+
+// truncateRunes shortens s to its first n characters followed by "...", so crafted keys do not flood messages
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i] + "..."
+		}
+		n--
+	}
+	return s
+}
+
+// CheckFieldConstraints checks the field values against the maxLength, minLength, enum and pattern
+// constraints of their schema, the items of list values against the schema of the array items.
+// All violations are reported as FieldErrors sorted by field. Fields the schema does not declare
+// are not checked, it needs no backend and can be used to reject values before a round trip.
+// Wildcards are checked against the declared wildcards of the resource type, see CheckWildcards,
+// and wildcards declared WildcardAny are exempt from the constraints.
+func CheckFieldConstraints(parsed *ParsedResource, crdInfo *CRDInfo) error {
+	keys := make([]string, 0, len(parsed.Fields))
+	for key := range parsed.Fields {
+		if key != "ccrn" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		schema, declared := crdInfo.FieldSchema(key)
+		if !declared {
+			continue
+		}
+		values := []string{parsed.Fields[key]}
+		if items, isList := ListValue(parsed.Fields[key]); isList && schema.Type == "array" {
+			values = items
+			if schema.Items != nil && schema.Items.Schema != nil {
+				schema = schema.Items.Schema
+			} else {
+				schema = &v1.JSONSchemaProps{}
+			}
+		}
+		for _, value := range values {
+			if err := checkWildcard(key, value, crdInfo); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if crdInfo.declaredWildcard(key, value) {
+				continue
+			}
+			if err := checkValue(key, value, schema); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkValue checks a single value against the length, enum and pattern constraints of the schema
+func checkValue(field, value string, schema *v1.JSONSchemaProps) error {
+	length := int64(utf8.RuneCountInString(value))
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return &FieldError{Field: field, Value: value, Reason: fmt.Sprintf("is longer than %d characters", *schema.MaxLength)}
+	}
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return &FieldError{Field: field, Value: value, Reason: fmt.Sprintf("is shorter than %d characters", *schema.MinLength)}
+	}
+	if len(schema.Enum) > 0 && !slices.Contains(enumValues(schema.Enum), value) {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, e := range schema.Enum {
+			allowed = append(allowed, string(e.Raw))
+		}
+		return &FieldError{Field: field, Value: value, Reason: "is not one of " + strings.Join(allowed, ", ")}
+	}
+	if schema.Pattern == "" {
+		return nil
+	}
+	pattern, err := compilePattern(schema.Pattern)
+	if err != nil {
+		return &FieldError{Field: field, Value: value, Reason: fmt.Sprintf("cannot be checked against invalid pattern %s", schema.Pattern)}
+	}
+	if !pattern.MatchString(value) {
+		return &FieldError{Field: field, Value: value, Reason: fmt.Sprintf("does not match pattern %s", schema.Pattern)}
+	}
+	return nil
+}
+
+// compilePattern compiles a schema pattern once and caches it
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Get(pattern); ok {
+		return cached, nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Add(pattern, compiled)
+	return compiled, nil
+}
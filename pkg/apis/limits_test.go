@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("CheckLength", func() {
+	It("counts characters, not bytes", func() {
+		// Act & Assert
+		Expect(apis.CheckLength("ccrn=pod.k8s.ccrn.example.com/v1, name=äöü", 42)).To(Succeed())
+		Expect(apis.CheckLength("ccrn=pod.k8s.ccrn.example.com/v1, name=äöü", 41)).To(MatchError(apis.ErrTooLong))
+	})
+
+	It("is disabled without maximum", func() {
+		// Act & Assert
+		Expect(apis.CheckLength(strings.Repeat("a", 10*apis.DefaultMaxLength), 0)).To(Succeed())
+	})
+})
+
+var _ = Describe("CheckComplexity", func() {
+	limits := apis.Limits{MaxFields: 4, MaxKeyLength: 10, MaxDepth: 2}
+
+	DescribeTable("enforces the limits",
+		func(ccrn string, expected error) {
+			// Arrange
+			parsed, err := apis.ParseCCRN(ccrn)
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			err = apis.CheckComplexity(parsed, limits)
+			// Assert
+			if expected == nil {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expected))
+			}
+		},
+		Entry("within the limits", "ccrn=pod.k8s.ccrn.example.com/v1, cluster=a, labels.app=b, name=c", nil),
+		Entry("with too many fields", "ccrn=pod.k8s.ccrn.example.com/v1, cluster=a, namespace=b, name=c, zone=d", apis.ErrTooManyFields),
+		Entry("with a key too long", "ccrn=pod.k8s.ccrn.example.com/v1, clusterName=a", apis.ErrKeyTooLong),
+		Entry("with a key nested too deeply", "ccrn=pod.k8s.ccrn.example.com/v1, a.b.c=a", apis.ErrTooDeep),
+	)
+
+	It("truncates crafted keys in its messages", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, " + strings.Repeat("k", 1000) + "=v")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		err = apis.CheckComplexity(parsed, apis.DefaultLimits)
+		// Assert
+		Expect(err).To(MatchError("key " + strings.Repeat("k", 32) + "... of 1000 characters exceeds the maximum length of 128"))
+	})
+
+	It("is disabled without limits", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, a.b.c.d.e.f.g.h.i.j=" + strings.Repeat("k", 200))
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.CheckComplexity(parsed, apis.Limits{})).To(Succeed())
+	})
+})
+
+var _ = Describe("CheckFieldConstraints", func() {
+	var crdInfo *apis.CRDInfo
+
+	BeforeEach(func() {
+		maxLength, minLength := int64(8), int64(2)
+		crdInfo = &apis.CRDInfo{Schema: &apiextensionsv1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"name":      {Type: "string", Pattern: "^[a-z0-9-]+$", MaxLength: &maxLength},
+				"namespace": {Type: "string", MinLength: &minLength},
+				"zones": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]{2}-[a-z]{2}-[0-9][a-z]$"},
+				}},
+			},
+		}}
+	})
+
+	It("accepts values within their constraints", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, name=my-pod, namespace=ns, zones=[eu-de-1a,eu-de-1b], color=blue")
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.CheckFieldConstraints(parsed, crdInfo)).To(Succeed())
+	})
+
+	It("reports every violation as field error", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, name=My_Pod, namespace=n, zones=[eu-de-1a,eu-west]")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		err = apis.CheckFieldConstraints(parsed, crdInfo)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		var fieldErr *apis.FieldError
+		Expect(errors.As(err, &fieldErr)).To(BeTrue())
+		Expect(fieldErr.Field).To(Equal("name"))
+		Expect(err.Error()).To(Equal("field name: value 'My_Pod' does not match pattern ^[a-z0-9-]+$\n" +
+			"field namespace: value 'n' is shorter than 2 characters\n" +
+			"field zones: value 'eu-west' does not match pattern ^[a-z]{2}-[a-z]{2}-[0-9][a-z]$"))
+	})
+
+	It("checks enum values", func() {
+		// Arrange
+		crdInfo.Schema.Properties["tier"] = apiextensionsv1.JSONSchemaProps{Type: "string", Enum: []apiextensionsv1.JSON{
+			{Raw: []byte(`"gold"`)}, {Raw: []byte(`"silver"`)},
+		}}
+		gold, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, tier=gold")
+		Expect(err).ToNot(HaveOccurred())
+		bronze, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, tier=bronze")
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.CheckFieldConstraints(gold, crdInfo)).To(Succeed())
+		Expect(apis.CheckFieldConstraints(bronze, crdInfo)).To(MatchError(`field tier: value 'bronze' is not one of "gold", "silver"`))
+	})
+
+	It("checks the maximum length in characters", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, name=a-very-long-name")
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.CheckFieldConstraints(parsed, crdInfo)).To(MatchError("field name: value 'a-very-long-name' is longer than 8 characters"))
+	})
+})
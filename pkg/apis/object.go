@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultObjectMapping is used for CRDs without object mapping, fields missing in the schema are skipped
+var DefaultObjectMapping = map[string]string{
+	"namespace": "metadata.namespace",
+	"name":      "metadata.name",
+}
+
+// SourceResolver is implemented by backends knowing which resource types identify the objects of a Kubernetes
+// resource, see CRDInfo.SourceResource
+type SourceResolver interface {
+	// ResolveSource returns the highest version of the resource type whose source is the resource
+	ResolveSource(resource schema.GroupResource) (*CRDInfo, bool)
+}
+
+// SourceIndex maps the source resources of the CRD versions, sorted by CCRN key, to the highest version of the
+// first resource type declaring them. CRDs without SourceResource are the source of the resource named like
+// their lowercase kind.
+func SourceIndex(crds []*CRDInfo) map[schema.GroupResource]*CRDInfo {
+	index := map[schema.GroupResource]*CRDInfo{}
+	for _, crdInfo := range crds {
+		source := crdInfo.SourceResource
+		if source == "" {
+			source = strings.ToLower(crdInfo.Kind)
+		}
+		resource := schema.ParseGroupResource(source)
+		found, exists := index[resource]
+		if exists && (found.Name != crdInfo.Name || HighestVersion([]string{found.Version, crdInfo.Version}) != crdInfo.Version) {
+			continue
+		}
+		index[resource] = crdInfo
+	}
+	return index
+}
+
+// ObjectMappingOrDefault returns the object mapping of the CRD version, or the fields of
+// DefaultObjectMapping declared by its schema
+func (c *CRDInfo) ObjectMappingOrDefault() map[string]string {
+	if len(c.ObjectMapping) > 0 {
+		return c.ObjectMapping
+	}
+	mapping := make(map[string]string, len(DefaultObjectMapping))
+	for field, path := range DefaultObjectMapping {
+		if c.Schema == nil || c.HasField(field) {
+			mapping[field] = path
+		}
+	}
+	return mapping
+}
+
+// ResourceFromObject derives the CCRN of a live Kubernetes object, given as unstructured content, from the
+// object mapping of the CRD version. Fields whose path does not exist in the object are left out, maps of
+// scalars become nested fields like "labels.app" and lists of scalars become list values. The fields passed
+// in set values the object does not provide (e.g. the cluster) and take precedence over the mapping.
+// The resulting CCRN is checked against the schema, see ValidateAgainst.
+func (c *CRDInfo) ResourceFromObject(obj map[string]interface{}, fields map[string]string) (*ParsedResource, error) {
+	parsed := &ParsedResource{
+		Format:      "CCRN",
+		Fields:      map[string]string{"ccrn": c.CCRNKey()},
+		UrnTemplate: c.URNFormat,
+	}
+
+	mapping := c.ObjectMappingOrDefault()
+	names := make([]string, 0, len(mapping))
+	for field := range mapping {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	for _, field := range names {
+		value, found, err := lookupPath(obj, mapping[field])
+		if err != nil {
+			return nil, Errorf(ErrInvalidTemplate, "object mapping of field %s: %v", field, err)
+		}
+		if !found {
+			continue
+		}
+		if err := setObjectField(parsed.Fields, field, value); err != nil {
+			return nil, Errorf(ErrUnsupportedType, "field %s from %s: %v", field, mapping[field], err)
+		}
+	}
+	for field, value := range fields {
+		parsed.Fields[field] = value
+	}
+
+	parsed.Raw = parsed.CanonicalCCRN()
+	if err := parsed.ValidateAgainst(c); err != nil {
+		return parsed, err
+	}
+	return parsed, nil
+}
+
+// lookupPath returns the value at a dotted path like "metadata.ownerReferences[0].name"
+func lookupPath(obj map[string]interface{}, path string) (interface{}, bool, error) {
+	var current interface{} = obj
+	for _, element := range strings.Split(path, ".") {
+		name, index, hasIndex := strings.Cut(element, "[")
+		if name == "" {
+			return nil, false, fmt.Errorf("invalid path %q", path)
+		}
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		if current, ok = m[name]; !ok {
+			return nil, false, nil
+		}
+		if !hasIndex {
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+		if err != nil || !strings.HasSuffix(index, "]") || i < 0 {
+			return nil, false, fmt.Errorf("invalid index in path %q", path)
+		}
+		list, ok := current.([]interface{})
+		if !ok || i >= len(list) {
+			return nil, false, nil
+		}
+		current = list[i]
+	}
+	return current, current != nil, nil
+}
+
+// setObjectField stores the value of an object as one or, for maps, several CCRN fields
+func setObjectField(fields map[string]string, field string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			scalar, err := scalarValue(item)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", key, err)
+			}
+			fields[field+"."+key] = scalar
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			scalar, err := scalarValue(item)
+			if err != nil {
+				return err
+			}
+			items = append(items, scalar)
+		}
+		fields[field] = FormatList(items)
+	default:
+		scalar, err := scalarValue(v)
+		if err != nil {
+			return err
+		}
+		fields[field] = scalar
+	}
+	return nil
+}
+
+func scalarValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, int64, float64, int:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ResourceFromObject", func() {
+	var crdInfo *apis.CRDInfo
+	pod := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "web-1",
+			"namespace": "shop",
+			"labels":    map[string]interface{}{"app": "web"},
+			"ownerReferences": []interface{}{
+				map[string]interface{}{"kind": "ReplicaSet", "name": "web-5d8f"},
+			},
+		},
+		"spec": map[string]interface{}{
+			"nodeName":   "node-1",
+			"priority":   int64(100),
+			"finalizers": []interface{}{"a", "b"},
+		},
+	}
+
+	BeforeEach(func() {
+		crdInfo = &apis.CRDInfo{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1",
+			URNFormat: "urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>",
+			Schema: &apiextensionsv1.JSONSchemaProps{
+				Type:     "object",
+				Required: []string{"ccrn", "cluster", "namespace", "name"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"ccrn":      {Type: "string"},
+					"cluster":   {Type: "string"},
+					"namespace": {Type: "string", Pattern: "^[a-z0-9-]+$"},
+					"name":      {Type: "string"},
+					"node":      {Type: "string"},
+					"owner":     {Type: "string"},
+					"priority":  {Type: "string"},
+					"tags":      {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+					"labels": {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+						Allows: true, Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+					}},
+				},
+			}}
+	})
+
+	It("maps name and namespace without object mapping", func() {
+		// Act
+		parsed, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.CanonicalCCRN()).To(Equal("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=web-1, namespace=shop"))
+		Expect(apis.BuildURN(parsed, "")).To(Equal("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/shop/web-1"))
+	})
+
+	It("maps scalars, maps, lists and indexed paths of the object mapping", func() {
+		// Arrange
+		crdInfo.ObjectMapping = map[string]string{
+			"namespace": "metadata.namespace",
+			"name":      "metadata.name",
+			"node":      "spec.nodeName",
+			"owner":     "metadata.ownerReferences[0].name",
+			"priority":  "spec.priority",
+			"tags":      "spec.finalizers",
+			"labels":    "metadata.labels",
+		}
+		// Act
+		parsed, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(Equal(map[string]string{
+			"ccrn":       "pod.k8s.ccrn.example.com/v1",
+			"cluster":    "eu-de-1",
+			"namespace":  "shop",
+			"name":       "web-1",
+			"node":       "node-1",
+			"owner":      "web-5d8f",
+			"priority":   "100",
+			"tags":       "[a,b]",
+			"labels.app": "web",
+		}))
+	})
+
+	It("skips paths missing in the object", func() {
+		// Arrange
+		crdInfo.ObjectMapping = map[string]string{
+			"namespace": "metadata.namespace",
+			"name":      "metadata.name",
+			"node":      "spec.missing",
+			"owner":     "metadata.ownerReferences[3].name",
+		}
+		// Act
+		parsed, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields).ToNot(HaveKey("node"))
+		Expect(parsed.Fields).ToNot(HaveKey("owner"))
+	})
+
+	It("lets the given fields override the object", func() {
+		// Act
+		parsed, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1", "name": "*"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields["name"]).To(Equal("*"))
+	})
+
+	It("returns the CCRN together with its schema violations", func() {
+		// Act
+		parsed, err := crdInfo.ResourceFromObject(pod, nil)
+		// Assert
+		Expect(errors.Is(err, apis.ErrSchemaViolation)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("cluster"))
+		Expect(parsed.CanonicalCCRN()).To(Equal("ccrn=pod.k8s.ccrn.example.com/v1, name=web-1, namespace=shop"))
+	})
+
+	It("rejects invalid paths", func() {
+		// Arrange
+		crdInfo.ObjectMapping = map[string]string{"name": "metadata.ownerReferences[x].name"}
+		// Act
+		_, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(errors.Is(err, apis.ErrInvalidTemplate)).To(BeTrue())
+	})
+
+	It("rejects values that are neither scalars, lists nor maps of scalars", func() {
+		// Arrange
+		crdInfo.ObjectMapping = map[string]string{"owner": "metadata.ownerReferences"}
+		// Act
+		_, err := crdInfo.ResourceFromObject(pod, map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(errors.Is(err, apis.ErrUnsupportedType)).To(BeTrue())
+	})
+})
+
+var _ = Describe("SourceIndex", func() {
+	It("maps source resources to the highest version of the first resource type declaring them", func() {
+		// Arrange
+		crds := []*apis.CRDInfo{
+			{Name: "node.k8s.ccrn.example.com", Kind: "node", Group: "k8s.ccrn.example.com", Version: "v1"},
+			{Name: "pod.k8s.ccrn.example.com", Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1", SourceResource: "pods"},
+			{Name: "pod.k8s.ccrn.example.com", Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v2", SourceResource: "pods"},
+			{Name: "workload.k8s.ccrn.example.com", Kind: "workload", Group: "k8s.ccrn.example.com", Version: "v1", SourceResource: "pods"},
+			{Name: "deployment.k8s.ccrn.example.com", Kind: "deployment", Group: "k8s.ccrn.example.com", Version: "v1", SourceResource: "deployments.apps"},
+		}
+		// Act
+		index := apis.SourceIndex(crds)
+		// Assert
+		Expect(index).To(HaveLen(3))
+		Expect(index[schema.GroupResource{Resource: "pods"}]).To(BeIdenticalTo(crds[2]))
+		Expect(index[schema.GroupResource{Group: "apps", Resource: "deployments"}]).To(BeIdenticalTo(crds[4]))
+		Expect(index[schema.GroupResource{Resource: "node"}]).To(BeIdenticalTo(crds[0]))
+	})
+})
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// LabelOwner is set on the resources a backend derives from a CCRN object, its value is the
+// name of the CCRN object, see OwnerLabelValue
+const LabelOwner = "ccrn/owner"
+
+// LabelOwnerGroup is set next to LabelOwner, its value is the API group of the CCRN object, so CCRN
+// objects of the same name in different groups do not share their derived resources
+const LabelOwnerGroup = "ccrn/owner-group"
+
+// FinalizerDerivedResources is added to CCRN objects with derived resources, it is removed once the
+// derived resources are deleted, see DerivedResourceManager
+const FinalizerDerivedResources = "ccrn/derived-resources"
+
+// DerivedResourceManager is implemented by backends creating resources for the validated CCRNs, like the
+// KubernetesBackend. The derived resources belong to the CCRN object they were created for and are deleted
+// together with it. The methods log with the logger of the request or controller calling them.
+type DerivedResourceManager interface {
+	// ValidateOwnedResource validates the resource like ValidateResource, created resources are owned by
+	// the CCRN object, see WithOwner
+	ValidateOwnedResource(log *logrus.Entry, namespace string, parsedCCRN *ParsedResource, owner metav1.OwnerReference) error
+
+	// DeleteDerivedResources deletes the resources derived from the CCRN object in the namespace
+	DeleteDerivedResources(log *logrus.Entry, namespace string, owner metav1.OwnerReference) error
+}
+
+// DryRunValidator is implemented by backends whose validation has side effects, like creating resources.
+// ValidateResourceDryRun validates the resource like ValidateResource without persisting anything, it is
+// used for dry-run admission requests.
+type DryRunValidator interface {
+	ValidateResourceDryRun(log *logrus.Entry, namespace string, parsedCCRN *ParsedResource) error
+}
+
+// OwnerLabelValue returns the value of LabelOwner for the name of a CCRN object. Names that are no valid
+// label values, e.g. because they exceed 63 characters, are replaced by a hash.
+func OwnerLabelValue(name string) string {
+	if len(validation.IsValidLabelValue(name)) == 0 {
+		return name
+	}
+	hash := sha256.Sum256([]byte(name))
+	return "sha256-" + hex.EncodeToString(hash[:])[:48]
+}
+
+// OwnerSelector returns the label selector of the resources derived from the CCRN object
+func OwnerSelector(owner metav1.OwnerReference) string {
+	return LabelOwner + "=" + OwnerLabelValue(owner.Name) + "," + LabelOwnerGroup + "=" + OwnerLabelValue(ownerGroup(owner))
+}
+
+// ownerGroup returns the API group of the owner, the core group is empty
+func ownerGroup(owner metav1.OwnerReference) string {
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return ""
+	}
+	return gv.Group
+}
+
+// WithOwner labels the resource object with the owner (LabelOwner and LabelOwnerGroup) and, if the UID of the owner is known,
+// adds an owner reference, so the garbage collector deletes the object together with its owner. Objects
+// being created have no UID yet.
+func WithOwner(owner metav1.OwnerReference) ResourceMapOption {
+	return func(o *resourceMapOptions) {
+		o.owner = &owner
+	}
+}
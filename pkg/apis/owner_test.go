@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Owner", func() {
+	parsed := &apis.ParsedResource{Fields: map[string]string{"ccrn": "pod.k8s-registry.ccrn.example.com/v1", "name": "web"}}
+	owner := metav1.OwnerReference{APIVersion: "validate.ccrn.example.com/v1", Kind: "CCRN", Name: "web"}
+
+	It("labels derived resources with the owner before its UID is known", func() {
+		// Act
+		resource := parsed.ToResourceMap("default", "pod-abcd-1", apis.WithOwner(owner))
+		// Assert
+		metadata := resource["metadata"].(map[string]any)
+		Expect(metadata["labels"]).To(HaveKeyWithValue(apis.LabelOwner, "web"))
+		Expect(metadata["labels"]).To(HaveKeyWithValue(apis.LabelOwnerGroup, "validate.ccrn.example.com"))
+		Expect(metadata).ToNot(HaveKey("ownerReferences"))
+	})
+
+	It("adds an owner reference once the UID is known", func() {
+		// Arrange
+		created := owner
+		created.UID = "1234"
+		// Act
+		resource := parsed.ToResourceMap("default", "pod-abcd-1", apis.WithOwner(created))
+		// Assert
+		Expect(resource["metadata"]).To(HaveKeyWithValue("ownerReferences", ConsistOf(map[string]any{
+			"apiVersion": "validate.ccrn.example.com/v1", "kind": "CCRN", "name": "web", "uid": "1234"})))
+	})
+
+	It("hashes names that are no valid label values", func() {
+		// Act
+		value := apis.OwnerLabelValue(strings.Repeat("a", 64))
+		// Assert
+		Expect(value).To(HavePrefix("sha256-"))
+		Expect(len(value)).To(BeNumerically("<=", 63))
+		Expect(apis.OwnerLabelValue("web")).To(Equal("web"))
+	})
+
+	It("selects the derived resources by the name and group of the owner", func() {
+		// Act
+		selector := apis.OwnerSelector(owner)
+		// Assert
+		Expect(selector).To(Equal("ccrn/owner=web,ccrn/owner-group=validate.ccrn.example.com"))
+	})
+})
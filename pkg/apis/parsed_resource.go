@@ -4,16 +4,36 @@
 package apis
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Methods for ParsedResource
 type ParsedResource struct {
-	Format      string // "CCRN" or "URN"
-	Fields      map[string]string
-	Raw         string
-	UrnTemplate string // URN template used for parsing, if applicable
+	Format      string            `json:"format"`                // "CCRN" or "URN"
+	Fields      map[string]string `json:"fields"`                // Fields are marshaled sorted by key
+	Raw         string            `json:"raw,omitempty"`         // Original input
+	UrnTemplate string            `json:"urnTemplate,omitempty"` // URN template used for parsing, if applicable
+
+	URNComponents URNComponents `json:"urnComponents,omitzero"` // RFC 8141 components of a parsed URN, if any
+}
+
+// UnmarshalJSON decodes a parsed resource and makes sure Fields is never nil
+func (p *ParsedResource) UnmarshalJSON(data []byte) error {
+	type plain ParsedResource
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Fields == nil {
+		decoded.Fields = map[string]string{}
+	}
+	*p = ParsedResource(decoded)
+	return nil
 }
 
 // CCRN returns the full CCRN string from the parsed resource
@@ -22,16 +42,54 @@ func (p *ParsedResource) CCRN() string {
 	if !exists {
 		return ""
 	}
-	ccrn := "ccrn=" + ccrnString
+	ccrn := "ccrn=" + QuoteValue(ccrnString)
 	for key, value := range p.Fields {
 		if key != "ccrn" {
-			ccrn += fmt.Sprintf(", %s=%s", key, value)
+			ccrn += fmt.Sprintf(", %s=%s", key, QuoteValue(value))
 		}
 	}
 	return ccrn
 }
 
-// URN returns the URN string from the parsed resource using the provided template
+// QuoteValue quotes a CCRN field value if needed, so it survives parsing unchanged. Values containing
+// commas, equals signs, quotes or leading/trailing whitespace and values starting with a square bracket
+// are enclosed in double quotes, quotes and backslashes within them are escaped with a backslash.
+// List values in the canonical form of FormatList are returned unchanged.
+func QuoteValue(value string) string {
+	if items, isList := ListValue(value); isList && FormatList(items) == value {
+		return value
+	}
+	if !strings.ContainsAny(value, ",=\"") && !strings.HasPrefix(value, "[") && strings.TrimSpace(value) == value {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + escaped + `"`
+}
+
+// CanonicalCCRN returns the CCRN string in canonical form: the ccrn field first,
+// followed by all other fields sorted by name, values in Unicode normalization form C
+func (p *ParsedResource) CanonicalCCRN() string {
+	ccrnString, exists := p.Fields["ccrn"]
+	if !exists {
+		return ""
+	}
+	keys := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		if key != "ccrn" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	ccrn := "ccrn=" + QuoteValue(ccrnString)
+	for _, key := range keys {
+		ccrn += fmt.Sprintf(", %s=%s", key, QuoteValue(NormalizeValue(p.Fields[key])))
+	}
+	return ccrn
+}
+
+// URN returns the URN string from the parsed resource using the provided template.
+// Placeholders without a matching field are left in place, use BuildURN to detect them.
 func (p *ParsedResource) URN(template string) string {
 	if template == "" {
 		if p.UrnTemplate != "" {
@@ -41,10 +99,15 @@ func (p *ParsedResource) URN(template string) string {
 		}
 	}
 
+	if compiled, err := compileTemplateCached(template); err == nil {
+		if urn, err := compiled.Render(p.Fields); err == nil {
+			return urn + p.URNComponents.String()
+		}
+	}
 	for key, value := range p.Fields {
 		template = strings.Replace(template, "<"+key+">", value, 1)
 	}
-	return template
+	return template + p.URNComponents.String()
 }
 
 // Version returns the version from the parsed CCRN or URN
@@ -104,20 +167,133 @@ func (p *ParsedResource) GetFieldValue(key string) (string, bool) {
 	return value, exists
 }
 
-// ToResourceMap converts the parsed resource to a map suitable for creating a K8s resource
-func (p *ParsedResource) ToResourceMap(namespace, name string) map[string]any {
+// ResourceMapOption configures the resource object created by ToResourceMap
+type ResourceMapOption func(*resourceMapOptions)
+
+type resourceMapOptions struct {
+	crdInfo     *CRDInfo
+	labels      map[string]string
+	annotations map[string]string
+	owner       *metav1.OwnerReference
+}
+
+// WithCRD takes apiVersion and kind from the CRD and places the fields under spec if its schema
+// wraps them in a spec object, see SpecWrapped
+func WithCRD(crdInfo *CRDInfo) ResourceMapOption {
+	return func(o *resourceMapOptions) {
+		o.crdInfo = crdInfo
+	}
+}
+
+// WithLabels adds the labels to the metadata of the resource object
+func WithLabels(labels map[string]string) ResourceMapOption {
+	return func(o *resourceMapOptions) {
+		o.labels = labels
+	}
+}
+
+// WithAnnotations adds the annotations to the metadata of the resource object
+func WithAnnotations(annotations map[string]string) ResourceMapOption {
+	return func(o *resourceMapOptions) {
+		o.annotations = annotations
+	}
+}
+
+// ToResourceMap converts the parsed resource to a map suitable for creating a K8s resource.
+// Dot path keys like "metadata.team" become nested objects and list values become arrays of strings.
+// A key conflicting with a previously set path, e.g. "team.name" next to "team", is dropped.
+// apiVersion and kind are derived from the CCRN key unless the CRD is passed WithCRD.
+func (p *ParsedResource) ToResourceMap(namespace, name string, opts ...ResourceMapOption) map[string]any {
+	options := &resourceMapOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	resourceObj := map[string]any{
-		"ccrn": p.CCRNKey(),
-		"metadata": map[string]any{
-			"name":      name,
-			"namespace": namespace,
-		},
+		"metadata": map[string]any{},
 	}
-	for key, value := range p.Fields {
-		if key == "ccrn" || key == "metadata" {
+	fieldsObj := resourceObj
+	if options.crdInfo != nil && options.crdInfo.SpecWrapped() {
+		fieldsObj = map[string]any{}
+		resourceObj["spec"] = fieldsObj
+	}
+	fieldsObj["ccrn"] = p.CCRNKey()
+
+	keys := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		if key != "ccrn" && key != "metadata" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var value any = p.Fields[key]
+		if items, isList := ListValue(p.Fields[key]); isList {
+			list := make([]any, len(items))
+			for i, item := range items {
+				list[i] = item
+			}
+			value = list
+		}
+		path := strings.Split(key, ".")
+		if path[0] == "metadata" {
+			// Metadata paths address the object metadata even if the fields are wrapped in spec
+			setPath(resourceObj, path, value)
 			continue
 		}
-		resourceObj[key] = value
+		setPath(fieldsObj, path, value)
+	}
+
+	metadata := resourceObj["metadata"].(map[string]any)
+	metadata["name"] = name
+	metadata["namespace"] = namespace
+	for field, values := range map[string]map[string]string{"labels": options.labels, "annotations": options.annotations} {
+		for key, value := range values {
+			setPath(metadata, []string{field, key}, value)
+		}
+	}
+	if options.owner != nil {
+		setPath(metadata, []string{"labels", LabelOwner}, OwnerLabelValue(options.owner.Name))
+		setPath(metadata, []string{"labels", LabelOwnerGroup}, OwnerLabelValue(ownerGroup(*options.owner)))
+		if options.owner.UID != "" {
+			metadata["ownerReferences"] = []any{map[string]any{
+				"apiVersion": options.owner.APIVersion,
+				"kind":       options.owner.Kind,
+				"name":       options.owner.Name,
+				"uid":        string(options.owner.UID),
+			}}
+		}
+	}
+
+	if options.crdInfo != nil {
+		resourceObj["apiVersion"] = options.crdInfo.Group + "/" + options.crdInfo.Version
+		resourceObj["kind"] = options.crdInfo.Kind
+	} else {
+		resourceObj["apiVersion"] = p.ApiGroup() + "/" + p.Version()
+		resourceObj["kind"] = p.GetKind()
 	}
 	return resourceObj
 }
+
+// setPath sets the value at the path of nested objects, it returns false if the path conflicts
+// with a value already set
+func setPath(obj map[string]any, path []string, value any) bool {
+	for _, name := range path[:len(path)-1] {
+		child, exists := obj[name]
+		if !exists {
+			child = map[string]any{}
+			obj[name] = child
+		}
+		nested, isObject := child.(map[string]any)
+		if !isObject {
+			return false
+		}
+		obj = nested
+	}
+	last := path[len(path)-1]
+	if _, exists := obj[last]; exists {
+		return false
+	}
+	obj[last] = value
+	return true
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ToResourceMap", func() {
+	var parsed *apis.ParsedResource
+
+	BeforeEach(func() {
+		var err error
+		parsed, err = apis.ParseCCRN("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, metadata.labels.team=core")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("derives apiVersion and kind from the CCRN key", func() {
+		// Act
+		resourceObj := parsed.ToResourceMap("default", "pod-1")
+		// Assert
+		Expect(resourceObj).To(HaveKeyWithValue("apiVersion", "k8s-registry.ccrn.example.com/v1"))
+		Expect(resourceObj).To(HaveKeyWithValue("kind", "pod"))
+		Expect(resourceObj).To(HaveKeyWithValue("ccrn", "pod.k8s-registry.ccrn.example.com/v1"))
+	})
+
+	It("takes apiVersion and kind from the CRD and injects labels and annotations", func() {
+		// Arrange
+		crdInfo := &apis.CRDInfo{Kind: "Pod", Group: "k8s-registry.ccrn.example.com", Version: "v1"}
+		// Act
+		resourceObj := parsed.ToResourceMap("default", "pod-1", apis.WithCRD(crdInfo),
+			apis.WithLabels(map[string]string{"app.kubernetes.io/managed-by": "ccrn"}),
+			apis.WithAnnotations(map[string]string{"ccrn/source": "webhook"}))
+		// Assert
+		Expect(resourceObj).To(HaveKeyWithValue("kind", "Pod"))
+		Expect(resourceObj).To(HaveKeyWithValue("metadata", map[string]any{
+			"name":        "pod-1",
+			"namespace":   "default",
+			"labels":      map[string]any{"team": "core", "app.kubernetes.io/managed-by": "ccrn"},
+			"annotations": map[string]any{"ccrn/source": "webhook"},
+		}))
+	})
+
+	It("places the fields under spec for spec-wrapped schemas", func() {
+		// Arrange
+		crdInfo := &apis.CRDInfo{Kind: "Pod", Group: "k8s-registry.ccrn.example.com", Version: "v1", Schema: &apiextensionsv1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": {Type: "object"}},
+		}}
+		// Act
+		resourceObj := parsed.ToResourceMap("default", "pod-1", apis.WithCRD(crdInfo))
+		// Assert
+		Expect(crdInfo.SpecWrapped()).To(BeTrue())
+		Expect(resourceObj).ToNot(HaveKey("ccrn"))
+		Expect(resourceObj).To(HaveKeyWithValue("spec", map[string]any{
+			"ccrn":    "pod.k8s-registry.ccrn.example.com/v1",
+			"cluster": "eu-de-1",
+			"name":    "my-pod",
+		}))
+		Expect(resourceObj["metadata"]).To(HaveKeyWithValue("labels", map[string]any{"team": "core"}))
+	})
+})
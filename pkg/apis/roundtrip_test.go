@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// roundTripIterations is the number of generated field sets per template
+const roundTripIterations = 500
+
+// roundTripAlphabets are the characters of generated values by placeholder constraint. Unconstrained values
+// include the characters CCRNs quote or escape and the delimiters of URN segments and components.
+var roundTripAlphabets = map[string]string{
+	"":                `abcz09-._:~ ,="\[]/?+#`,
+	"int":             "0123456789",
+	"dns":             "abcz09-",
+	"[a-z0-9-]+":      "abcz09-",
+	"[a-z]{2}-[a-z]+": "abcz-",
+}
+
+// fieldGenerator generates field sets for a URN template
+type fieldGenerator struct {
+	random   *rand.Rand
+	template *apis.Template
+}
+
+// value returns a random value of up to 8 characters of the alphabet
+func (g *fieldGenerator) value(alphabet string) string {
+	var value strings.Builder
+	for range 1 + g.random.IntN(8) {
+		value.WriteByte(alphabet[g.random.IntN(len(alphabet))])
+	}
+	return value.String()
+}
+
+// fields returns a random field set for the placeholders of the template, the fields of optional segments
+// are left out at times. Values may violate the constraints of the template, rendering rejects them.
+func (g *fieldGenerator) fields() map[string]string {
+	fields := map[string]string{"ccrn": "pod.k8s-registry.tr.ccrn.example.com/v1"}
+	for _, segment := range g.template.Segments {
+		if segment.Optional && g.random.IntN(2) == 0 {
+			continue
+		}
+		for _, part := range segment.Parts {
+			if part.Field == "" || part.Field == "ccrn" {
+				continue
+			}
+			alphabet, known := roundTripAlphabets[part.Constraint]
+			Expect(known).To(BeTrue(), "no alphabet for constraint %s", part.Constraint)
+			fields[part.Field] = g.value(alphabet)
+		}
+	}
+	return fields
+}
+
+var _ = Describe("Round trips", func() {
+	templates := []string{
+		"urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>",
+		"urn:ccrn:<ccrn>/<region>/[<domain>]/<id:int>",
+		"urn:ccrn:<ccrn>/<region>-<az>/<name>",
+		"urn:ccrn:<ccrn>/<cluster:[a-z0-9-]+>/[<namespace:dns>]/<name>",
+		"urn:ccrn:<ccrn>/<region:[a-z]{2}-[a-z]+>/[<project>]/[<zone>]/<name:dns>",
+	}
+
+	var random *rand.Rand
+
+	BeforeEach(func() {
+		seed := uint64(GinkgoRandomSeed())
+		random = rand.New(rand.NewPCG(seed, seed))
+	})
+
+	for _, template := range templates {
+		Context(template, func() {
+			var generator *fieldGenerator
+
+			BeforeEach(func() {
+				compiled, err := apis.CompileTemplate(template)
+				Expect(err).ToNot(HaveOccurred())
+				generator = &fieldGenerator{random: random, template: compiled}
+			})
+
+			It("keeps the fields of a CCRN through its URN", func() {
+				rendered := 0
+				for range roundTripIterations {
+					// Arrange
+					ccrn := (&apis.ParsedResource{Format: "CCRN", Fields: generator.fields()}).CanonicalCCRN()
+					// Act
+					parsed, err := apis.ParseCCRN(ccrn)
+					Expect(err).ToNot(HaveOccurred(), "CCRN %q does not parse", ccrn)
+					urn, err := apis.BuildURN(parsed, template)
+					if err != nil {
+						Expect(errors.Is(err, apis.ErrTemplateMismatch)).To(BeTrue(), "CCRN %q: %v", ccrn, err)
+						continue
+					}
+					rendered++
+					fromURN, err := apis.ParseURN(urn, template)
+					// Assert
+					Expect(err).ToNot(HaveOccurred(), "URN %q of CCRN %q does not parse", urn, ccrn)
+					Expect(fromURN.CanonicalCCRN()).To(Equal(ccrn), "URN %q", urn)
+				}
+				Expect(rendered).To(BeNumerically(">", 0), "no generated field set was rendered")
+			})
+
+			It("keeps a URN through its CCRN", func() {
+				for range roundTripIterations {
+					// Arrange
+					urn, err := apis.BuildURN(&apis.ParsedResource{Format: "CCRN", Fields: generator.fields()}, template)
+					if err != nil {
+						continue
+					}
+					// Act
+					parsed, err := apis.ParseURN(urn, template)
+					Expect(err).ToNot(HaveOccurred(), "URN %q does not parse", urn)
+					fromCCRN, err := apis.ParseCCRN(parsed.CanonicalCCRN())
+					Expect(err).ToNot(HaveOccurred(), "CCRN %q of URN %q does not parse", parsed.CanonicalCCRN(), urn)
+					// Assert
+					Expect(apis.BuildURN(fromCCRN, template)).To(Equal(urn), "CCRN %q", parsed.CanonicalCCRN())
+				}
+			})
+		})
+	}
+})
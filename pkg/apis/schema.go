@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// FieldInfo describes a CCRN field declared in the schema of a resource type version,
+// e.g. for explaining resource types, shell completion or generated forms
+type FieldInfo struct {
+	Name        string   `json:"name"`                  // Dot path of the field, e.g. "labels.app"
+	Type        string   `json:"type,omitempty"`        // OpenAPI type, e.g. "string", "array" or "object"
+	ItemType    string   `json:"itemType,omitempty"`    // OpenAPI type of the items of an array or the values of a map
+	Map         bool     `json:"map,omitempty"`         // Object with arbitrary keys like labels, set as "<name>.<key>"
+	Required    bool     `json:"required,omitempty"`    // Required by its parent, nested fields only if the parent is set
+	Pattern     string   `json:"pattern,omitempty"`     // Regular expression values must match
+	Enum        []string `json:"enum,omitempty"`        // Allowed values
+	MinLength   *int64   `json:"minLength,omitempty"`   // Minimum length of values in characters
+	MaxLength   *int64   `json:"maxLength,omitempty"`   // Maximum length of values in characters
+	Description string   `json:"description,omitempty"` // Description from the schema
+	Example     string   `json:"example,omitempty"`     // Example value from the schema
+	Default     string   `json:"default,omitempty"`     // Value of the field if it is not set, see ApplyDefaults
+}
+
+// kubernetesFields are the standard top-level properties of Kubernetes objects, they are no CCRN fields
+var kubernetesFields = []string{"apiVersion", "kind", "metadata", "status"}
+
+// Fields returns the CCRN fields declared in the schema sorted by name. Nested objects are listed
+// followed by their properties as dot paths, maps are listed once with Map set.
+func (c *CRDInfo) Fields() []FieldInfo {
+	if c == nil || c.Schema == nil {
+		return nil
+	}
+	var fields []FieldInfo
+	collectFields(&fields, "", c.FieldsSchema(), true)
+	return fields
+}
+
+// Field returns the description of the field at the dot path, see FieldSchema
+func (c *CRDInfo) Field(path string) (FieldInfo, bool) {
+	schema, declared := c.FieldSchema(path)
+	if !declared {
+		return FieldInfo{}, false
+	}
+	parentPath, name := "", path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parentPath, name = path[:i], path[i+1:]
+	}
+	parent := c.FieldsSchema()
+	if parentPath != "" {
+		if parent, declared = c.FieldSchema(parentPath); !declared {
+			parent = nil
+		}
+	} else if path == "metadata" {
+		parent = c.Schema
+	}
+	return fieldInfo(path, schema, parent != nil && slices.Contains(parent.Required, name)), true
+}
+
+// RequiredFields returns the names of the required top-level CCRN fields sorted by name
+func (c *CRDInfo) RequiredFields() []string {
+	if c == nil || c.Schema == nil {
+		return nil
+	}
+	required := slices.Clone(c.FieldsSchema().Required)
+	sort.Strings(required)
+	return required
+}
+
+// collectFields appends the properties of the object schema and their nested properties to fields
+func collectFields(fields *[]FieldInfo, prefix string, schema *v1.JSONSchemaProps, topLevel bool) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		if topLevel && slices.Contains(kubernetesFields, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property := schema.Properties[name]
+		info := fieldInfo(prefix+name, &property, slices.Contains(schema.Required, name))
+		*fields = append(*fields, info)
+		if len(property.Properties) > 0 {
+			collectFields(fields, info.Name+".", &property, false)
+		}
+	}
+}
+
+// fieldInfo describes the field with the schema
+func fieldInfo(name string, schema *v1.JSONSchemaProps, required bool) FieldInfo {
+	info := FieldInfo{
+		Name:        name,
+		Type:        schema.Type,
+		Required:    required,
+		Pattern:     schema.Pattern,
+		Enum:        enumValues(schema.Enum),
+		MinLength:   schema.MinLength,
+		MaxLength:   schema.MaxLength,
+		Description: schema.Description,
+		Default:     defaultValue(schema),
+	}
+	if schema.Example != nil {
+		if values := enumValues([]v1.JSON{*schema.Example}); len(values) > 0 {
+			info.Example = values[0]
+		}
+	}
+	switch {
+	case schema.Items != nil && schema.Items.Schema != nil:
+		info.ItemType = schema.Items.Schema.Type
+	case len(schema.Properties) == 0 && schema.AdditionalProperties != nil:
+		info.Map = schema.AdditionalProperties.Allows || schema.AdditionalProperties.Schema != nil
+		if schema.AdditionalProperties.Schema != nil {
+			info.ItemType = schema.AdditionalProperties.Schema.Type
+		}
+	}
+	return info
+}
+
+// enumValues returns the enum or example values as text, non-string values are formatted with fmt
+func enumValues(enum []v1.JSON) []string {
+	var values []string
+	for _, e := range enum {
+		var value any
+		if err := json.Unmarshal(e.Raw, &value); err == nil {
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+	return values
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Schema introspection", func() {
+	maxLength := int64(63)
+	schema := &apis.CRDInfo{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1", Schema: &apiextensionsv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"name", "ccrn"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"metadata":   {Type: "object"},
+			"ccrn":       {Type: "string", Description: "Resource type"},
+			"name":       {Type: "string", Pattern: "^[a-z0-9-]+$", MaxLength: &maxLength, Description: "Pod name", Example: &apiextensionsv1.JSON{Raw: []byte(`"web"`)}},
+			"tier":       {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"gold"`)}, {Raw: []byte(`1`)}}},
+			"zones":      {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+			"labels": {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+				Allows: true, Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+			}},
+			"owner": {Type: "object", Required: []string{"team"}, Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"team":  {Type: "string"},
+				"email": {Type: "string"},
+			}},
+		},
+	}}
+
+	It("enumerates the CCRN fields sorted by name", func() {
+		// Act
+		fields := schema.Fields()
+		// Assert
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			names = append(names, field.Name)
+		}
+		Expect(names).To(Equal([]string{"ccrn", "labels", "name", "owner", "owner.email", "owner.team", "tier", "zones"}))
+	})
+
+	It("describes types, constraints and required flags", func() {
+		// Act
+		fields := map[string]apis.FieldInfo{}
+		for _, field := range schema.Fields() {
+			fields[field.Name] = field
+		}
+		// Assert
+		Expect(fields["name"]).To(Equal(apis.FieldInfo{
+			Name: "name", Type: "string", Required: true, Pattern: "^[a-z0-9-]+$", MaxLength: &maxLength, Description: "Pod name", Example: "web",
+		}))
+		Expect(fields["tier"].Enum).To(Equal([]string{"gold", "1"}))
+		Expect(fields["zones"].ItemType).To(Equal("string"))
+		Expect(fields["labels"].Map).To(BeTrue())
+		Expect(fields["owner"].Required).To(BeFalse())
+		Expect(fields["owner.team"].Required).To(BeTrue())
+	})
+
+	It("describes single fields by dot path", func() {
+		// Act
+		team, teamFound := schema.Field("owner.team")
+		label, labelFound := schema.Field("labels.app")
+		_, unknownFound := schema.Field("color")
+		// Assert
+		Expect(teamFound).To(BeTrue())
+		Expect(team.Required).To(BeTrue())
+		Expect(labelFound).To(BeTrue())
+		Expect(label.Type).To(Equal("string"))
+		Expect(unknownFound).To(BeFalse())
+	})
+
+	It("lists the required fields", func() {
+		// Act & Assert
+		Expect(schema.RequiredFields()).To(Equal([]string{"ccrn", "name"}))
+		Expect((&apis.CRDInfo{}).Fields()).To(BeEmpty())
+	})
+})
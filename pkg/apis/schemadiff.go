@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// URNTemplateName names the URN template in TemplateChange, external templates are named by their scheme
+const URNTemplateName = "urn"
+
+// SchemaDiffSource is implemented by backends remembering how their last refresh changed the loaded CRD set
+type SchemaDiffSource interface {
+	// LastSchemaDiff returns the diff of the last refresh that changed the CRD set, nil if none did
+	LastSchemaDiff() *SchemaDiff
+}
+
+// SchemaDiff describes how a CRD set changed, resource types are identified by their CCRN key
+type SchemaDiff struct {
+	Time    time.Time    `json:"time,omitzero"`     // Time of the refresh that changed the CRD set
+	Added   []string     `json:"added,omitempty"`   // Resource types only in the new CRD set
+	Removed []string     `json:"removed,omitempty"` // Resource types only in the old CRD set
+	Changed []TypeChange `json:"changed,omitempty"` // Resource types in both CRD sets with different fields or templates
+}
+
+// TypeChange describes how the fields and templates of a resource type changed
+type TypeChange struct {
+	Type          string           `json:"type"`                    // CCRN key of the resource type
+	FieldsAdded   []string         `json:"fieldsAdded,omitempty"`   // Fields only declared by the new schema
+	FieldsRemoved []string         `json:"fieldsRemoved,omitempty"` // Fields only declared by the old schema
+	FieldsChanged []string         `json:"fieldsChanged,omitempty"` // Fields with a different schema or whose requiredness changed
+	Templates     []TemplateChange `json:"templates,omitempty"`     // Changed URN and external templates
+}
+
+// TemplateChange describes a changed template, an empty Old or New template was added or removed
+type TemplateChange struct {
+	Name string `json:"name"` // URNTemplateName or the scheme of an external template
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// DiffSchemas compares the resource types of two CRD sets by CCRN key: which were added or removed, and which
+// fields and templates of the others changed. Other changes, e.g. of deprecations, are not reported.
+func DiffSchemas(before, after []*CRDInfo) *SchemaDiff {
+	oldTypes := make(map[string]*CRDInfo, len(before))
+	for _, crd := range before {
+		oldTypes[crd.CCRNKey()] = crd
+	}
+	newTypes := make(map[string]*CRDInfo, len(after))
+	for _, crd := range after {
+		newTypes[crd.CCRNKey()] = crd
+	}
+
+	diff := &SchemaDiff{}
+	for _, key := range slices.Sorted(maps.Keys(newTypes)) {
+		previous, exists := oldTypes[key]
+		if !exists {
+			diff.Added = append(diff.Added, key)
+			continue
+		}
+		if change := diffType(previous, newTypes[key]); change != nil {
+			diff.Changed = append(diff.Changed, *change)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(oldTypes)) {
+		if _, exists := newTypes[key]; !exists {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	return diff
+}
+
+// diffType compares the fields and templates of two versions of a resource type, nil if they are the same
+func diffType(before, after *CRDInfo) *TypeChange {
+	change := &TypeChange{Type: after.CCRNKey()}
+
+	oldFields, oldRequired := schemaFields(before)
+	newFields, newRequired := schemaFields(after)
+	for _, name := range slices.Sorted(maps.Keys(newFields)) {
+		previous, exists := oldFields[name]
+		switch {
+		case !exists:
+			change.FieldsAdded = append(change.FieldsAdded, name)
+		case !reflect.DeepEqual(previous, newFields[name]) || oldRequired[name] != newRequired[name]:
+			change.FieldsChanged = append(change.FieldsChanged, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(oldFields)) {
+		if _, exists := newFields[name]; !exists {
+			change.FieldsRemoved = append(change.FieldsRemoved, name)
+		}
+	}
+
+	oldTemplates, newTemplates := urnAndExternalTemplates(before), urnAndExternalTemplates(after)
+	names := slices.Sorted(maps.Keys(newTemplates))
+	for name := range oldTemplates {
+		if _, exists := newTemplates[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if oldTemplates[name] != newTemplates[name] {
+			change.Templates = append(change.Templates, TemplateChange{Name: name, Old: oldTemplates[name], New: newTemplates[name]})
+		}
+	}
+
+	if len(change.FieldsAdded) == 0 && len(change.FieldsRemoved) == 0 && len(change.FieldsChanged) == 0 && len(change.Templates) == 0 {
+		return nil
+	}
+	return change
+}
+
+// schemaFields returns the schemas of the CCRN fields and which of them are required
+func schemaFields(crd *CRDInfo) (map[string]v1.JSONSchemaProps, map[string]bool) {
+	fields := map[string]v1.JSONSchemaProps{}
+	required := map[string]bool{}
+	if crd.Schema == nil {
+		return fields, required
+	}
+	schema := crd.FieldsSchema()
+	maps.Copy(fields, schema.Properties)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	return fields, required
+}
+
+// urnAndExternalTemplates returns the URN template and the external templates of the resource type by name
+func urnAndExternalTemplates(crd *CRDInfo) map[string]string {
+	templates := maps.Clone(crd.ExternalTemplates)
+	if templates == nil {
+		templates = map[string]string{}
+	}
+	if crd.URNFormat != "" {
+		templates[URNTemplateName] = crd.URNFormat
+	}
+	return templates
+}
+
+// Empty reports whether the CRD sets have the same resource types with the same fields and templates
+func (d *SchemaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String summarizes the diff in a line, e.g. for logs:
+// "added pod.k8s.ccrn.example.com/v2; changed pod.k8s.ccrn.example.com/v1 (+zone ~cluster urn template)"
+func (d *SchemaDiff) String() string {
+	var parts []string
+	if len(d.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.Removed, ", "))
+	}
+	if len(d.Changed) > 0 {
+		changed := make([]string, len(d.Changed))
+		for i, change := range d.Changed {
+			changed[i] = change.String()
+		}
+		parts = append(parts, "changed "+strings.Join(changed, ", "))
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// String summarizes the change with added fields prefixed with "+", removed ones with "-" and changed ones with "~"
+func (c TypeChange) String() string {
+	var changes []string
+	for _, field := range c.FieldsAdded {
+		changes = append(changes, "+"+field)
+	}
+	for _, field := range c.FieldsRemoved {
+		changes = append(changes, "-"+field)
+	}
+	for _, field := range c.FieldsChanged {
+		changes = append(changes, "~"+field)
+	}
+	for _, template := range c.Templates {
+		changes = append(changes, fmt.Sprintf("%s template", template.Name))
+	}
+	return fmt.Sprintf("%s (%s)", c.Type, strings.Join(changes, " "))
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("DiffSchemas", func() {
+	// pod returns the schema of a pod resource type version with the fields
+	pod := func(version string, required []string, fields ...string) *apis.CRDInfo {
+		properties := map[string]v1.JSONSchemaProps{}
+		for _, field := range fields {
+			properties[field] = v1.JSONSchemaProps{Type: "string"}
+		}
+		return &apis.CRDInfo{
+			Kind:      "pod",
+			Group:     "k8s.ccrn.example.com",
+			Version:   version,
+			Schema:    &v1.JSONSchemaProps{Type: "object", Properties: properties, Required: required},
+			URNFormat: "urn:ccrn:<ccrn>/<cluster>/<name>",
+		}
+	}
+
+	It("reports no changes of equal CRD sets", func() {
+		// Act
+		diff := apis.DiffSchemas([]*apis.CRDInfo{pod("v1", nil, "cluster", "name")}, []*apis.CRDInfo{pod("v1", nil, "name", "cluster")})
+		// Assert
+		Expect(diff.Empty()).To(BeTrue())
+		Expect(diff.String()).To(Equal("no changes"))
+	})
+
+	It("reports added and removed resource types", func() {
+		// Act
+		diff := apis.DiffSchemas([]*apis.CRDInfo{pod("v1alpha1", nil, "name")}, []*apis.CRDInfo{pod("v1", nil, "name")})
+		// Assert
+		Expect(diff.Added).To(Equal([]string{"pod.k8s.ccrn.example.com/v1"}))
+		Expect(diff.Removed).To(Equal([]string{"pod.k8s.ccrn.example.com/v1alpha1"}))
+		Expect(diff.Changed).To(BeEmpty())
+		Expect(diff.String()).To(Equal("added pod.k8s.ccrn.example.com/v1; removed pod.k8s.ccrn.example.com/v1alpha1"))
+	})
+
+	It("reports added, removed and changed fields", func() {
+		// Arrange
+		before := pod("v1", []string{"name"}, "cluster", "name", "id")
+		after := pod("v1", []string{"name", "cluster"}, "cluster", "name", "zone")
+		after.Schema.Properties["name"] = v1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"}
+		// Act
+		diff := apis.DiffSchemas([]*apis.CRDInfo{before}, []*apis.CRDInfo{after})
+		// Assert
+		Expect(diff.Changed).To(Equal([]apis.TypeChange{{
+			Type:          "pod.k8s.ccrn.example.com/v1",
+			FieldsAdded:   []string{"zone"},
+			FieldsRemoved: []string{"id"},
+			FieldsChanged: []string{"cluster", "name"},
+		}}))
+		Expect(diff.String()).To(Equal("changed pod.k8s.ccrn.example.com/v1 (+zone -id ~cluster ~name)"))
+	})
+
+	It("reports changed templates", func() {
+		// Arrange
+		before := pod("v1", nil, "cluster", "name")
+		before.ExternalTemplates = map[string]string{"arn": "arn:aws:eks:<cluster>:pod/<name>"}
+		after := pod("v1", nil, "cluster", "name")
+		after.URNFormat = "urn:ccrn:<ccrn>/<name>"
+		// Act
+		diff := apis.DiffSchemas([]*apis.CRDInfo{before}, []*apis.CRDInfo{after})
+		// Assert
+		Expect(diff.Changed).To(HaveLen(1))
+		Expect(diff.Changed[0].Templates).To(Equal([]apis.TemplateChange{
+			{Name: "arn", Old: "arn:aws:eks:<cluster>:pod/<name>"},
+			{Name: apis.URNTemplateName, Old: "urn:ccrn:<ccrn>/<cluster>/<name>", New: "urn:ccrn:<ccrn>/<name>"},
+		}))
+	})
+})
@@ -0,0 +1,532 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/lru"
+)
+
+// placeholderPattern matches a single <field> placeholder of a URN template
+var placeholderPattern = regexp.MustCompile(`<([^<>]*)>`)
+
+// placeholderTypes are the named constraints of typed placeholders like <replicas:int>
+var placeholderTypes = map[string]string{
+	"int":  `[0-9]+`,
+	"dns":  `[a-z0-9]([-a-z0-9]*[a-z0-9])?`,
+	"uuid": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// Template is a compiled URN template like "urn:ccrn:<ccrn>/<region>-<az>/[<namespace>]/<name>".
+// Each "/"-separated segment consists of literals and <field> placeholders, segments enclosed in
+// [] are optional and left out when rendering without their fields. The <ccrn> placeholder spans the
+// type and version segments of a URN, a placeholder in the last segment takes all remaining segments.
+// Placeholders may be constrained by a regular expression, <cluster:[a-z0-9-]+>, or a type, <replicas:int>.
+// Within a segment, placeholders match as few characters as possible, except for the last one.
+type Template struct {
+	Raw      string            // Template as written in the CRD annotation
+	Prefix   string            // URN prefix, e.g. "urn:ccrn:"
+	Segments []TemplateSegment // Segments following the prefix
+}
+
+// TemplateSegment is a single "/"-separated segment of a URN template
+type TemplateSegment struct {
+	Raw      string         // Segment as written in the template, without the brackets of optional segments
+	Optional bool           // Segment is enclosed in [] and may be absent
+	Parts    []TemplatePart // Literals and placeholders of the segment in order
+	pattern  *regexp.Regexp // Matches a whole segment value, with a named group per placeholder
+}
+
+// TemplatePart is a literal or a placeholder within a template segment
+type TemplatePart struct {
+	Raw        string         // Part as written in the template
+	Field      string         // Name of the placeholder, empty for literals
+	Constraint string         // Regular expression or type the value must match, empty if any value is allowed
+	pattern    *regexp.Regexp // Compiled constraint, anchored to the whole value
+}
+
+// CompileTemplate parses a URN template
+func CompileTemplate(template string) (*Template, error) {
+	prefix, body, ok := SplitURNPrefix(template)
+	if !ok {
+		return nil, Errorf(ErrInvalidTemplate, "invalid URN template %s: must start with 'urn:<NID>:'", template)
+	}
+
+	raws, err := splitTemplateSegments(body)
+	if err != nil {
+		return nil, Errorf(ErrInvalidTemplate, "invalid URN template %s: %v", template, err)
+	}
+	t := &Template{Raw: template, Prefix: prefix}
+	for _, raw := range raws {
+		segment, err := compileSegment(raw)
+		if err != nil {
+			return nil, Errorf(ErrInvalidTemplate, "invalid URN template %s: %v", template, err)
+		}
+		t.Segments = append(t.Segments, segment)
+	}
+	return t, nil
+}
+
+// templateCache holds the templates compiled by compileTemplateCached, keyed by template
+var templateCache = lru.New[string, *Template]("templates", DefaultCompileCacheSize)
+
+// compileTemplateCached compiles a template once and caches it for the parse and render paths, which
+// see the same few templates of the CRDs over and over. The cached templates are shared and must not be
+// modified, invalid templates are not cached.
+func compileTemplateCached(template string) (*Template, error) {
+	if cached, ok := templateCache.Get(template); ok {
+		return cached, nil
+	}
+	compiled, err := CompileTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Add(template, compiled)
+	return compiled, nil
+}
+
+// splitTemplateSegments splits a template body at all slashes outside of placeholders
+func splitTemplateSegments(body string) ([]string, error) {
+	var segments []string
+	start, placeholderStart := 0, -1
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '<':
+			if placeholderStart >= 0 {
+				return nil, fmt.Errorf("nested placeholder in %s", body[placeholderStart:])
+			}
+			placeholderStart = i
+		case '>':
+			if placeholderStart < 0 {
+				return nil, fmt.Errorf("unbalanced placeholder brackets in %s", body[start:])
+			}
+			placeholderStart = -1
+		case '/':
+			if placeholderStart < 0 {
+				segments = append(segments, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if placeholderStart >= 0 {
+		return nil, fmt.Errorf("unbalanced placeholder brackets in %s", body[placeholderStart:])
+	}
+	return append(segments, body[start:]), nil
+}
+
+// compileSegment parses a single template segment
+func compileSegment(raw string) (TemplateSegment, error) {
+	segment := TemplateSegment{Raw: raw}
+	literal := placeholderPattern.ReplaceAllString(raw, "")
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") && len(literal) >= 2 {
+		segment.Raw = raw[1 : len(raw)-1]
+		segment.Optional = true
+		literal = literal[1 : len(literal)-1]
+	}
+	if strings.ContainsAny(literal, "[]") {
+		return segment, fmt.Errorf("unbalanced brackets in segment %s, only whole segments can be optional", raw)
+	}
+
+	placeholders := placeholderPattern.FindAllStringSubmatchIndex(segment.Raw, -1)
+	if len(placeholders) == 0 && segment.Optional {
+		return segment, fmt.Errorf("optional segment %s has no placeholder", raw)
+	}
+
+	expression := "^"
+	position := 0
+	for i, index := range placeholders {
+		if index[0] > position {
+			text := segment.Raw[position:index[0]]
+			segment.Parts = append(segment.Parts, TemplatePart{Raw: text})
+			expression += regexp.QuoteMeta(text)
+		}
+		part, err := compilePlaceholder(segment.Raw[index[0]:index[1]], segment.Raw[index[2]:index[3]])
+		if err != nil {
+			return segment, err
+		}
+		if part.Field == "ccrn" && (len(placeholders) > 1 || len(literal) > 0) {
+			return segment, fmt.Errorf("<ccrn> must be the only content of its segment, got %s", raw)
+		}
+		if part.Field == "ccrn" && segment.Optional {
+			return segment, fmt.Errorf("<ccrn> cannot be optional")
+		}
+		segment.Parts = append(segment.Parts, part)
+
+		group := ".*"
+		switch {
+		case part.Constraint != "":
+			group = part.pattern.String()[1 : len(part.pattern.String())-1]
+		case len(placeholders) > 1 && i < len(placeholders)-1:
+			group = ".+?"
+		case len(placeholders) > 1:
+			group = ".+"
+		}
+		expression += fmt.Sprintf("(?P<p%d>%s)", i, group)
+		position = index[1]
+	}
+	if position < len(segment.Raw) {
+		text := segment.Raw[position:]
+		segment.Parts = append(segment.Parts, TemplatePart{Raw: text})
+		expression += regexp.QuoteMeta(text)
+	}
+
+	pattern, err := regexp.Compile(expression + "$")
+	if err != nil {
+		return segment, fmt.Errorf("invalid segment %s: %v", raw, err)
+	}
+	segment.pattern = pattern
+	return segment, nil
+}
+
+// compilePlaceholder parses a placeholder like <name> or <name:constraint>
+func compilePlaceholder(raw, spec string) (TemplatePart, error) {
+	name, constraint, constrained := strings.Cut(spec, ":")
+	part := TemplatePart{Raw: raw, Field: name}
+	if name == "" {
+		return part, fmt.Errorf("placeholder without name: %s", raw)
+	}
+	if !constrained {
+		return part, nil
+	}
+	if constraint == "" {
+		return part, fmt.Errorf("invalid constraint of <%s>: empty constraint", name)
+	}
+	expression := constraint
+	if typed, ok := placeholderTypes[constraint]; ok {
+		expression = typed
+	}
+	pattern, err := regexp.Compile("^(?:" + expression + ")$")
+	if err != nil {
+		return part, fmt.Errorf("invalid constraint of <%s>: %v", name, err)
+	}
+	part.Constraint = constraint
+	part.pattern = pattern
+	return part, nil
+}
+
+// Placeholder returns the placeholder if it is the only content of the segment
+func (s TemplateSegment) Placeholder() (TemplatePart, bool) {
+	if len(s.Parts) == 1 && s.Parts[0].Field != "" {
+		return s.Parts[0], true
+	}
+	return TemplatePart{}, false
+}
+
+// Fields returns the placeholder names of the segment in order of appearance
+func (s TemplateSegment) Fields() []string {
+	var fields []string
+	for _, part := range s.Parts {
+		if part.Field != "" {
+			fields = append(fields, part.Field)
+		}
+	}
+	return fields
+}
+
+// Match extracts the placeholder values from a segment value
+func (s TemplateSegment) Match(value string) (map[string]string, bool) {
+	submatches := s.pattern.FindStringSubmatch(value)
+	if submatches == nil {
+		return nil, false
+	}
+	values := map[string]string{}
+	i := 0
+	for _, part := range s.Parts {
+		if part.Field == "" {
+			continue
+		}
+		v := submatches[s.pattern.SubexpIndex(fmt.Sprintf("p%d", i))]
+		if previous, exists := values[part.Field]; exists && previous != v {
+			return nil, false
+		}
+		values[part.Field] = v
+		i++
+	}
+	return values, true
+}
+
+// Matches reports whether the value satisfies the constraint of the placeholder, literals must match exactly
+func (p TemplatePart) Matches(value string) bool {
+	switch {
+	case p.Field == "":
+		return value == p.Raw
+	case p.pattern != nil:
+		return p.pattern.MatchString(value)
+	}
+	return true
+}
+
+// Fields returns the placeholder names of the template in order of appearance
+func (t *Template) Fields() []string {
+	var fields []string
+	for _, segment := range t.Segments {
+		fields = append(fields, segment.Fields()...)
+	}
+	return fields
+}
+
+// Pattern returns a regular expression (RE2) matching the URNs of the template without RFC 8141 components, e.g. for
+// CEL rules. fieldPattern returns the expression of a placeholder without constraint, or "" for any value. Like in
+// Match, the value of a placeholder in the last segment may span several segments.
+func (t *Template) Pattern(fieldPattern func(field string) string) string {
+	expression := "^(?i:" + regexp.QuoteMeta(t.Prefix) + ")"
+	leading := true
+	for i, segment := range t.Segments {
+		value := ""
+		for _, part := range segment.Parts {
+			switch {
+			case part.Field == "":
+				value += regexp.QuoteMeta(part.Raw)
+			case part.Constraint != "":
+				value += part.pattern.String()[1 : len(part.pattern.String())-1]
+			case fieldPattern(part.Field) != "":
+				value += "(?:" + fieldPattern(part.Field) + ")"
+			case part.Field == "ccrn":
+				value += "[^/]+/[^/]+"
+			case i == len(t.Segments)-1:
+				value += ".+"
+			default:
+				value += "[^/]+"
+			}
+		}
+
+		// Segments before the first required one carry the separator to their successor
+		switch {
+		case leading && segment.Optional:
+			expression += "(?:" + value + "/)?"
+		case leading:
+			expression += value
+			leading = false
+		case segment.Optional:
+			expression += "(?:/" + value + ")?"
+		default:
+			expression += "/" + value
+		}
+	}
+	return expression + "$"
+}
+
+// Render fills the placeholders with the fields. Optional segments without any of their fields are
+// left out. Missing required fields, values violating a constraint and values that would not be
+// parsed back unchanged fail with ErrTemplateMismatch.
+func (t *Template) Render(fields map[string]string) (string, error) {
+	var segments, missing, invalid []string
+	for i, segment := range t.Segments {
+		var rendered string
+		var absent []string
+		for _, part := range segment.Parts {
+			value := fields[part.Field]
+			switch {
+			case part.Field == "":
+				rendered += part.Raw
+			case value == "":
+				absent = append(absent, part.Raw)
+			case !part.Matches(value):
+				invalid = append(invalid, fmt.Sprintf("value '%s' of <%s> does not match %s", value, part.Field, part.Constraint))
+			case part.Field != "ccrn" && i < len(t.Segments)-1 && strings.Contains(value, "/"):
+				invalid = append(invalid, fmt.Sprintf("value '%s' of <%s> must not contain '/'", value, part.Field))
+			case hasComponentDelimiter(value):
+				invalid = append(invalid, fmt.Sprintf("value '%s' of <%s> must not contain '#', '?+' or '?='", value, part.Field))
+			default:
+				rendered += value
+			}
+		}
+
+		switch {
+		case len(absent) > 0 && segment.Optional && len(absent) == len(segment.Fields()):
+			continue
+		case len(absent) > 0:
+			missing = append(missing, absent...)
+			continue
+		}
+		if len(segment.Fields()) > 1 && !roundTrips(segment, rendered, fields) {
+			invalid = append(invalid, fmt.Sprintf("segment '%s' would not be parsed back into the same values of %s", rendered, segment.Raw))
+		}
+		segments = append(segments, rendered)
+	}
+	if len(missing) > 0 {
+		return "", Errorf(ErrTemplateMismatch, "unresolved placeholders in URN template %s: %s", t.Raw, strings.Join(missing, ", "))
+	}
+	urn := t.Prefix + strings.Join(segments, "/")
+	switch {
+	case len(invalid) > 0:
+	case hasComponentDelimiter(urn):
+		invalid = append(invalid, fmt.Sprintf("'%s' would be parsed with RFC 8141 components", urn))
+	case t.hasOptionalSegments() && !t.matchesFields(urn, fields):
+		invalid = append(invalid, fmt.Sprintf("'%s' would not be parsed back into the same values, the optional segments are ambiguous", urn))
+	}
+	if len(invalid) > 0 {
+		return "", Errorf(ErrTemplateMismatch, "invalid values for URN template %s: %s", t.Raw, strings.Join(invalid, "; "))
+	}
+	return urn, nil
+}
+
+// hasComponentDelimiter reports whether the value contains a delimiter of the RFC 8141 components of a URN,
+// rendering it into a URN would cut the URN short when it is parsed
+func hasComponentDelimiter(value string) bool {
+	return strings.Contains(value, "#") || strings.Contains(value, "?+") || strings.Contains(value, "?=")
+}
+
+// hasOptionalSegments reports whether the template has optional segments
+func (t *Template) hasOptionalSegments() bool {
+	for _, segment := range t.Segments {
+		if segment.Optional {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFields reports whether matching the rendered URN yields the fields it was rendered from, values of
+// optional segments may otherwise be taken for those of neighbouring segments
+func (t *Template) matchesFields(urn string, fields map[string]string) bool {
+	matched, err := t.Match(urn)
+	if err != nil {
+		return false
+	}
+	for _, field := range t.Fields() {
+		if matched[field] != fields[field] {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTrips reports whether matching the rendered segment yields the fields it was rendered from
+func roundTrips(segment TemplateSegment, rendered string, fields map[string]string) bool {
+	values, ok := segment.Match(rendered)
+	if !ok {
+		return false
+	}
+	for field, value := range values {
+		if value != fields[field] {
+			return false
+		}
+	}
+	return true
+}
+
+// Match extracts the fields of a URN without RFC 8141 components. Optional segments are matched
+// greedily from left to right. Failures are reported as *ParseError pointing at the segment
+// furthest into the URN that could not be matched.
+func (t *Template) Match(urn string) (map[string]string, error) {
+	prefix, body, ok := SplitURNPrefix(urn)
+	if !ok {
+		return nil, &ParseError{Input: urn, Segment: 1, Expected: "'" + t.Prefix + "' prefix", Got: urn, Err: ErrUnknownFormat}
+	}
+	if !strings.EqualFold(prefix, t.Prefix) {
+		return nil, &ParseError{Input: urn, Segment: 1, Expected: "'" + t.Prefix + "' prefix as in template " + t.Raw, Got: prefix, Err: ErrTemplateMismatch}
+	}
+
+	count := strings.Count(body, "/") + 1
+	m := &templateMatcher{template: t, urn: urn, fields: make(map[string]string, len(t.Segments)+1), failureAt: -1,
+		segments: make([]string, 0, count), offsets: make([]int, 0, count)}
+	offset := len(prefix)
+	for segment := range strings.SplitSeq(body, "/") {
+		m.segments = append(m.segments, segment)
+		m.offsets = append(m.offsets, offset)
+		offset += len(segment) + 1
+	}
+	if !m.match(0, 0) {
+		return nil, m.failure
+	}
+	return m.fields, nil
+}
+
+// templateMatcher matches the segments of a URN against a template with backtracking over optional segments
+type templateMatcher struct {
+	template  *Template
+	urn       string
+	segments  []string
+	offsets   []int
+	fields    map[string]string
+	failure   *ParseError
+	failureAt int
+}
+
+// match reports whether the URN segments from ui on match the template segments from ti on
+func (m *templateMatcher) match(ti, ui int) bool {
+	if ti == len(m.template.Segments) {
+		if ui == len(m.segments) {
+			return true
+		}
+		m.fail(ti, ui, fmt.Sprintf("%d segments as in template %s", len(m.template.Segments), m.template.Raw), strings.Join(m.segments[ui:], "/"))
+		return false
+	}
+	if m.consume(ti, ui) {
+		return true
+	}
+	return m.template.Segments[ti].Optional && m.match(ti+1, ui)
+}
+
+// consume matches the template segment ti at the URN segment ui and continues with the following segments
+func (m *templateMatcher) consume(ti, ui int) bool {
+	segment := m.template.Segments[ti]
+	placeholder, single := segment.Placeholder()
+	n := 1
+	if single && placeholder.Field == "ccrn" {
+		n = 2
+	}
+	if ui+n > len(m.segments) {
+		if single && placeholder.Field == "ccrn" {
+			m.fail(ti, ui, "<kind>.<group>/<version>", strings.Join(m.segments[ui:], "/"))
+		} else {
+			m.fail(ti, len(m.segments), fmt.Sprintf("%d segments as in template %s", len(m.template.Segments), m.template.Raw), "")
+		}
+		return false
+	}
+	if len(segment.Fields()) > 0 && ti == len(m.template.Segments)-1 {
+		n = len(m.segments) - ui
+	}
+	value := strings.Join(m.segments[ui:ui+n], "/")
+
+	values, ok := segment.Match(value)
+	if !ok {
+		if single && placeholder.Constraint != "" {
+			m.fail(ti, ui, "<"+placeholder.Field+"> matching "+placeholder.Constraint, value)
+		} else {
+			m.fail(ti, ui, "'"+segment.Raw+"' as in template "+m.template.Raw, value)
+		}
+		return false
+	}
+
+	var added []string
+	defer func() {
+		for _, field := range added {
+			delete(m.fields, field)
+		}
+	}()
+	for field, v := range values {
+		previous, exists := m.fields[field]
+		if exists && previous != v {
+			m.fail(ti, ui, "'"+previous+"' as in the previous <"+field+">", value)
+			return false
+		}
+		if !exists {
+			m.fields[field] = v
+			added = append(added, field)
+		}
+	}
+	if m.match(ti+1, ui+n) {
+		added = nil
+		return true
+	}
+	return false
+}
+
+// fail records the mismatch if it is at least as far into the URN as the previous one
+func (m *templateMatcher) fail(ti, ui int, expected, got string) {
+	if ui < m.failureAt {
+		return
+	}
+	offset := len(m.urn)
+	if ui < len(m.offsets) {
+		offset = m.offsets[ui]
+	}
+	m.failureAt = ui
+	m.failure = &ParseError{Input: m.urn, Segment: ti + 1, Offset: offset, Expected: expected, Got: got, Err: ErrTemplateMismatch}
+}
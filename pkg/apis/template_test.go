@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"errors"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Template", func() {
+	const ccrn = "pod.k8s.ccrn.example.com/v1"
+
+	DescribeTable("rejects malformed templates",
+		func(template string) {
+			// Act
+			_, err := apis.CompileTemplate(template)
+			// Assert
+			Expect(err).To(MatchError(apis.ErrInvalidTemplate))
+		},
+		Entry("missing prefix", "<ccrn>/<name>"),
+		Entry("unbalanced placeholder", "urn:ccrn:<ccrn>/<name"),
+		Entry("unbalanced optional segment", "urn:ccrn:<ccrn>/[<namespace>/<name>"),
+		Entry("optional literal", "urn:ccrn:<ccrn>/[pods]/<name>"),
+		Entry("optional ccrn", "urn:ccrn:[<ccrn>]/<name>"),
+		Entry("empty placeholder", "urn:ccrn:<ccrn>/<>"),
+		Entry("invalid constraint", "urn:ccrn:<ccrn>/<name:[a-z>"),
+		Entry("empty constraint", "urn:ccrn:<ccrn>/<name:>"),
+		Entry("ccrn sharing its segment", "urn:ccrn:<ccrn>-<suffix>/<name>"),
+	)
+
+	Context("optional segments", func() {
+		var template *apis.Template
+
+		BeforeEach(func() {
+			var err error
+			template, err = apis.CompileTemplate("urn:ccrn:<ccrn>/<cluster>/[<namespace>]/<name>")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("renders present optional segments", func() {
+			// Act
+			urn, err := template.Render(map[string]string{"ccrn": ccrn, "cluster": "c1", "namespace": "shop", "name": "foo"})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(urn).To(Equal("urn:ccrn:" + ccrn + "/c1/shop/foo"))
+		})
+
+		It("leaves out absent optional segments", func() {
+			// Act
+			urn, err := template.Render(map[string]string{"ccrn": ccrn, "cluster": "c1", "name": "foo"})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(urn).To(Equal("urn:ccrn:" + ccrn + "/c1/foo"))
+		})
+
+		It("matches URNs with and without the optional segment", func() {
+			// Act
+			namespaced, err := template.Match("urn:ccrn:" + ccrn + "/c1/shop/foo")
+			Expect(err).ToNot(HaveOccurred())
+			clusterScoped, err := template.Match("urn:ccrn:" + ccrn + "/c1/foo")
+			Expect(err).ToNot(HaveOccurred())
+			// Assert
+			Expect(namespaced).To(Equal(map[string]string{"ccrn": ccrn, "cluster": "c1", "namespace": "shop", "name": "foo"}))
+			Expect(clusterScoped).To(Equal(map[string]string{"ccrn": ccrn, "cluster": "c1", "name": "foo"}))
+		})
+
+		It("refuses to render values that would be taken for the optional segment", func() {
+			// Act
+			_, err := template.Render(map[string]string{"ccrn": ccrn, "cluster": "c1", "name": "shop/foo"})
+			// Assert
+			Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+			Expect(err).To(MatchError(ContainSubstring("optional segments are ambiguous")))
+		})
+
+		It("points at missing segments", func() {
+			// Act
+			_, err := template.Match("urn:ccrn:" + ccrn)
+			// Assert
+			var parseErr *apis.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.Segment).To(Equal(2))
+			Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		})
+	})
+
+	It("lets the last placeholder take the remaining segments", func() {
+		// Arrange
+		template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<path>")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		fields, err := template.Match("urn:ccrn:" + ccrn + "/a/b/c")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fields).To(HaveKeyWithValue("path", "a/b/c"))
+	})
+
+	It("refuses to render the delimiters of RFC 8141 components", func() {
+		// Arrange
+		template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<name>")
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		for _, name := range []string{"a#b", "a?+b", "a?=b"} {
+			_, err := template.Render(map[string]string{"ccrn": ccrn, "name": name})
+			Expect(err).To(MatchError(ContainSubstring("must not contain '#', '?+' or '?='")), name)
+		}
+	})
+
+	Context("constrained placeholders", func() {
+		var template *apis.Template
+
+		BeforeEach(func() {
+			var err error
+			template, err = apis.CompileTemplate("urn:ccrn:<ccrn>/<cluster:[a-z]{2}-[a-z]{2}-[0-9]>/[<namespace:ns-[a-z]+>]/<replicas:int>")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("uses constraints to decide about optional segments", func() {
+			// Act
+			withNamespace, err := template.Match("urn:ccrn:" + ccrn + "/eu-de-1/ns-shop/3")
+			Expect(err).ToNot(HaveOccurred())
+			withoutNamespace, err := template.Match("urn:ccrn:" + ccrn + "/eu-de-1/3")
+			Expect(err).ToNot(HaveOccurred())
+			// Assert
+			Expect(withNamespace).To(HaveKeyWithValue("namespace", "ns-shop"))
+			Expect(withoutNamespace).ToNot(HaveKey("namespace"))
+			Expect(withoutNamespace).To(HaveKeyWithValue("replicas", "3"))
+		})
+
+		It("reports values violating a constraint", func() {
+			// Act
+			_, err := template.Match("urn:ccrn:" + ccrn + "/EU-DE-1/3")
+			// Assert
+			var parseErr *apis.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.Segment).To(Equal(2))
+			Expect(err).To(MatchError("segment 2: expected <cluster> matching [a-z]{2}-[a-z]{2}-[0-9], got 'EU-DE-1'"))
+		})
+
+		It("refuses to render values violating a constraint", func() {
+			// Act
+			_, err := template.Render(map[string]string{"ccrn": ccrn, "cluster": "eu-de-1", "replicas": "three"})
+			// Assert
+			Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+			Expect(err.Error()).To(ContainSubstring("value 'three' of <replicas> does not match int"))
+		})
+
+		It("returns the placeholder names without constraints", func() {
+			// Act & Assert
+			Expect(template.Fields()).To(Equal([]string{"ccrn", "cluster", "namespace", "replicas"}))
+			Expect(apis.TemplatePlaceholders(template.Raw)).To(Equal(template.Fields()))
+		})
+	})
+
+	Context("segments with several placeholders", func() {
+		It("splits segments at the literals between placeholders", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<region>-<az>/vm-<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			fields, err := template.Match("urn:ccrn:" + ccrn + "/eu-de-1a/vm-web-01")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(HaveKeyWithValue("region", "eu"))
+			Expect(fields).To(HaveKeyWithValue("az", "de-1a"))
+			Expect(fields).To(HaveKeyWithValue("name", "web-01"))
+		})
+
+		It("lets constraints override the shortest match", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<region:[a-z]+-[a-z]+-[0-9]+>-<az:[a-z]>/<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			fields, err := template.Match("urn:ccrn:" + ccrn + "/eu-de-1-a/web")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(HaveKeyWithValue("region", "eu-de-1"))
+			Expect(fields).To(HaveKeyWithValue("az", "a"))
+		})
+
+		It("points at segments missing a literal", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/vm-<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			_, err = template.Match("urn:ccrn:" + ccrn + "/web-01")
+			// Assert
+			Expect(err).To(MatchError("segment 2: expected 'vm-<name>' as in template urn:ccrn:<ccrn>/vm-<name>, got 'web-01'"))
+		})
+
+		It("renders segments that parse back into the same values", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<region>-<az>/<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			urn, err := template.Render(map[string]string{"ccrn": ccrn, "region": "eu", "az": "de-1a", "name": "web"})
+			Expect(err).ToNot(HaveOccurred())
+			_, ambiguousErr := template.Render(map[string]string{"ccrn": ccrn, "region": "eu-de", "az": "1a", "name": "web"})
+			// Assert
+			Expect(urn).To(Equal("urn:ccrn:" + ccrn + "/eu-de-1a/web"))
+			Expect(ambiguousErr).To(MatchError(ContainSubstring("would not be parsed back")))
+		})
+	})
+
+	Context("patterns", func() {
+		It("matches the URNs the template matches", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:<ccrn>/<region>-<az:[a-z]>/[<namespace>]/<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			pattern := regexp.MustCompile(template.Pattern(func(field string) string {
+				if field == "region" {
+					return "eu|us"
+				}
+				return ""
+			}))
+			// Assert
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/eu-a/shop/web")).To(BeTrue())
+			Expect(pattern.MatchString("URN:CCRN:" + ccrn + "/us-b/web")).To(BeTrue())
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/eu-a/shop/web/v2")).To(BeTrue())
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/ap-a/web")).To(BeFalse())
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/eu-1/web")).To(BeFalse())
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/eu-a")).To(BeFalse())
+		})
+
+		It("lets leading optional segments carry their separator", func() {
+			// Arrange
+			template, err := apis.CompileTemplate("urn:ccrn:[<cluster>]/<ccrn>/<name>")
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			pattern := regexp.MustCompile(template.Pattern(func(string) string { return "" }))
+			// Assert
+			Expect(pattern.MatchString("urn:ccrn:c1/" + ccrn + "/web")).To(BeTrue())
+			Expect(pattern.MatchString("urn:ccrn:" + ccrn + "/web")).To(BeTrue())
+			Expect(pattern.MatchString("urn:ccrn:/" + ccrn + "/web")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// GetString returns the value of the field, failing with ErrFieldNotSet if it is not set
+func (p *ParsedResource) GetString(key string) (string, error) {
+	value, exists := p.Fields[key]
+	if !exists {
+		return "", Errorf(ErrFieldNotSet, "field %s is not set", key)
+	}
+	return value, nil
+}
+
+// GetInt returns the value of the field as integer, failing with ErrFieldNotSet if it is not set and
+// with a FieldError if it is no integer
+func (p *ParsedResource) GetInt(key string) (int64, error) {
+	value, err := p.GetString(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseInt(key, value)
+}
+
+// GetFloat returns the value of the field as number, failing with ErrFieldNotSet if it is not set and
+// with a FieldError if it is no number
+func (p *ParsedResource) GetFloat(key string) (float64, error) {
+	value, err := p.GetString(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseFloat(key, value)
+}
+
+// GetBool returns the value of the field as boolean, failing with ErrFieldNotSet if it is not set and
+// with a FieldError if it is neither true nor false
+func (p *ParsedResource) GetBool(key string) (bool, error) {
+	value, err := p.GetString(key)
+	if err != nil {
+		return false, err
+	}
+	return parseBool(key, value)
+}
+
+// GetList returns the items of a list value like "[a,b]", failing with ErrFieldNotSet if it is not set and
+// with a FieldError if it is no list, see ListValue
+func (p *ParsedResource) GetList(key string) ([]string, error) {
+	value, err := p.GetString(key)
+	if err != nil {
+		return nil, err
+	}
+	items, isList := ListValue(value)
+	if !isList {
+		return nil, &FieldError{Field: key, Value: value, Reason: "is not a list"}
+	}
+	return items, nil
+}
+
+// TypedFields returns the fields converted to the types of their schema: int64 for integer, float64 for
+// number and bool for boolean fields, []any of the converted items for arrays. Fields of type string or
+// not declared in the schema keep their string value. All values not matching their type are reported as
+// FieldErrors sorted by field.
+func (p *ParsedResource) TypedFields(crdInfo *CRDInfo) (map[string]any, error) {
+	keys := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	typed := make(map[string]any, len(p.Fields))
+	var errs []error
+	for _, key := range keys {
+		value := p.Fields[key]
+		schema, declared := crdInfo.FieldSchema(key)
+		if !declared || key == "ccrn" {
+			typed[key] = value
+			continue
+		}
+		converted, err := convertValue(key, value, schema.Type)
+		if schema.Type == "array" {
+			itemType := ""
+			if schema.Items != nil && schema.Items.Schema != nil {
+				itemType = schema.Items.Schema.Type
+			}
+			converted, err = convertList(key, value, itemType)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		typed[key] = converted
+	}
+	return typed, errors.Join(errs...)
+}
+
+// convertValue converts the value to the schema type, values of other types are kept as strings
+func convertValue(key, value, schemaType string) (any, error) {
+	switch schemaType {
+	case "integer":
+		return parseInt(key, value)
+	case "number":
+		return parseFloat(key, value)
+	case "boolean":
+		return parseBool(key, value)
+	}
+	return value, nil
+}
+
+// convertList converts the items of a list value to the item type
+func convertList(key, value, itemType string) ([]any, error) {
+	items, isList := ListValue(value)
+	if !isList {
+		return nil, &FieldError{Field: key, Value: value, Reason: "is not a list"}
+	}
+	converted := make([]any, 0, len(items))
+	for _, item := range items {
+		typed, err := convertValue(key, item, itemType)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, typed)
+	}
+	return converted, nil
+}
+
+func parseInt(key, value string) (int64, error) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, &FieldError{Field: key, Value: value, Reason: "is not an integer"}
+	}
+	return parsed, nil
+}
+
+func parseFloat(key, value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, &FieldError{Field: key, Value: value, Reason: "is not a number"}
+	}
+	return parsed, nil
+}
+
+func parseBool(key, value string) (bool, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, &FieldError{Field: key, Value: value, Reason: "is neither true nor false"}
+}
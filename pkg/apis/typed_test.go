@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Typed accessors", func() {
+	var parsed *apis.ParsedResource
+
+	BeforeEach(func() {
+		var err error
+		parsed, err = apis.ParseCCRN("ccrn=deployment.k8s.ccrn.example.com/v1, cluster=eu-de-1, replicas=3, ratio=0.5, paused=true, zones=[a,b]")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("returns the values converted to the requested type", func() {
+		// Act & Assert
+		Expect(parsed.GetString("cluster")).To(Equal("eu-de-1"))
+		Expect(parsed.GetInt("replicas")).To(Equal(int64(3)))
+		Expect(parsed.GetFloat("ratio")).To(Equal(0.5))
+		Expect(parsed.GetBool("paused")).To(BeTrue())
+		Expect(parsed.GetList("zones")).To(Equal([]string{"a", "b"}))
+	})
+
+	It("fails for fields that are not set", func() {
+		// Act
+		_, err := parsed.GetInt("namespace")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrFieldNotSet))
+		Expect(err).To(MatchError(ContainSubstring("field namespace is not set")))
+	})
+
+	DescribeTable("fails for values of another type",
+		func(get func() error, expected string) {
+			// Act
+			err := get()
+			// Assert
+			Expect(err).To(MatchError(apis.ErrSchemaViolation))
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("integer", func() error { _, err := parsed.GetInt("ratio"); return err }, "field ratio: value '0.5' is not an integer"),
+		Entry("number", func() error { _, err := parsed.GetFloat("cluster"); return err }, "field cluster: value 'eu-de-1' is not a number"),
+		Entry("boolean", func() error { _, err := parsed.GetBool("replicas"); return err }, "field replicas: value '3' is neither true nor false"),
+		Entry("list", func() error { _, err := parsed.GetList("cluster"); return err }, "field cluster: value 'eu-de-1' is not a list"),
+	)
+})
+
+var _ = Describe("TypedFields", func() {
+	var crdInfo *apis.CRDInfo
+
+	BeforeEach(func() {
+		crdInfo = &apis.CRDInfo{
+			Schema: &apiextensionsv1.JSONSchemaProps{Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"cluster":  {Type: "string"},
+				"replicas": {Type: "integer"},
+				"ratio":    {Type: "number"},
+				"paused":   {Type: "boolean"},
+				"ports":    {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "integer"}}},
+			}},
+		}
+	})
+
+	It("converts the values to the types of the schema", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=deployment.k8s.ccrn.example.com/v1, cluster=1, replicas=3, ratio=0.5, paused=false, ports=[80,443], team=core")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		typed, err := parsed.TypedFields(crdInfo)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(typed).To(Equal(map[string]any{
+			"ccrn":     "deployment.k8s.ccrn.example.com/v1",
+			"cluster":  "1",
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"paused":   false,
+			"ports":    []any{int64(80), int64(443)},
+			"team":     "core",
+		}))
+	})
+
+	It("reports all values not matching their type", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=deployment.k8s.ccrn.example.com/v1, replicas=three, paused=yes, ports=[80,http]")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		_, err = parsed.TypedFields(crdInfo)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		Expect(apis.ErrorMessages(err)).To(Equal([]string{
+			"field paused: value 'yes' is neither true nor false",
+			"field ports: value 'http' is not an integer",
+			"field replicas: value 'three' is not an integer",
+		}))
+	})
+
+	It("keeps the string values without schema", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=deployment.k8s.ccrn.example.com/v1, replicas=3")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		typed, err := parsed.TypedFields(nil)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(typed).To(HaveKeyWithValue("replicas", "3"))
+	})
+})
@@ -10,6 +10,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// IdentifierAnnotation carries the CCRN or URN of the resource an arbitrary Kubernetes object represents
+const IdentifierAnnotation = "ccrn.cloud/id"
+
 // CCRN defines the Common Cloud Resource Name resource
 type CCRN struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -34,6 +37,8 @@ type CCRNStatus struct {
 	Message string `json:"message,omitempty"`
 	// ValidatedAt is the timestamp when the CCRN was last validated
 	ValidatedAt metav1.Time `json:"validatedAt"`
+	// Conditions report observations like the existence of the identified object, see package drift
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // GenericResource is a dynamic resource that can represent any custom resource
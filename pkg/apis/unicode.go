@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeValue returns the value in Unicode normalization form C, so composed and decomposed spellings
+// of the same characters like "caf\u00e9" and "cafe\u0301" compare equal. ASCII values are returned unchanged.
+func NormalizeValue(value string) string {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return norm.NFC.String(value)
+		}
+	}
+	return value
+}
+
+// scripts are the scripts of letters told apart by CheckHomoglyphs, letters of other scripts are
+// reported by the name of their script as well, digits and punctuation belong to no script
+var scripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+	{"Cherokee", unicode.Cherokee},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Bopomofo", unicode.Bopomofo},
+}
+
+// compatibleScripts are combinations of scripts commonly written together, following the highly
+// restrictive profile of Unicode Technical Standard #39
+var compatibleScripts = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Hangul"},
+	{"Latin", "Han", "Bopomofo"},
+}
+
+// latinLookalikes are the letters of other scripts indistinguishable from Latin letters in common fonts
+var latinLookalikes = map[rune]bool{
+	// Cyrillic
+	'а': true, 'в': true, 'е': true, 'к': true, 'м': true, 'н': true, 'о': true, 'р': true, 'с': true, 'т': true,
+	'у': true, 'х': true, 'ѕ': true, 'і': true, 'ј': true, 'һ': true, 'ԁ': true, 'ԛ': true, 'ԝ': true, 'ӏ': true,
+	'А': true, 'В': true, 'Е': true, 'К': true, 'М': true, 'Н': true, 'О': true, 'Р': true, 'С': true, 'Т': true,
+	'У': true, 'Х': true, 'Ѕ': true, 'І': true, 'Ј': true,
+	// Greek
+	'α': true, 'ι': true, 'κ': true, 'ν': true, 'ο': true, 'ρ': true, 'τ': true, 'υ': true, 'χ': true,
+	'Α': true, 'Β': true, 'Ε': true, 'Ζ': true, 'Η': true, 'Ι': true, 'Κ': true, 'Μ': true, 'Ν': true, 'Ο': true,
+	'Ρ': true, 'Τ': true, 'Υ': true, 'Χ': true,
+	// Armenian
+	'օ': true, 'ս': true, 'ց': true, 'հ': true, 'ո': true,
+}
+
+// scriptOf returns the script of the letter, the empty string for other characters
+func scriptOf(r rune) string {
+	if !unicode.IsLetter(r) {
+		return ""
+	}
+	for _, script := range scripts {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// CheckHomoglyph reports why the value could be mistaken for a different identifier: it mixes letters of
+// scripts that are not written together, e.g. a Cyrillic "а" in "pаyments", or it consists of letters of
+// another script that all look like Latin letters, e.g. the Cyrillic "сео". The empty string means the
+// value is unambiguous.
+func CheckHomoglyph(value string) string {
+	var found []string
+	lookalikes := true
+	for _, r := range NormalizeValue(value) {
+		script := scriptOf(r)
+		if script == "" {
+			continue
+		}
+		if !slices.Contains(found, script) {
+			found = append(found, script)
+		}
+		if script == "Latin" || !latinLookalikes[r] {
+			lookalikes = false
+		}
+	}
+	switch {
+	case len(found) > 1 && !compatible(found):
+		slices.Sort(found)
+		return "mixes " + strings.Join(found, " and ") + " letters"
+	case len(found) == 1 && found[0] != "Latin" && lookalikes:
+		return "consists of " + found[0] + " letters looking like Latin ones"
+	}
+	return ""
+}
+
+// compatible reports whether the scripts are commonly written together, see compatibleScripts
+func compatible(found []string) bool {
+	for _, combination := range compatibleScripts {
+		matches := true
+		for _, script := range found {
+			matches = matches && slices.Contains(combination, script)
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHomoglyphs rejects values of identifier fields that could be mistaken for a different identifier,
+// see CheckHomoglyph. Identifier fields are the placeholders of the URN template of the resource type, all
+// fields if the resource type or its template is unknown. The items of list values are checked individually.
+// All violations are reported as FieldErrors sorted by field.
+func CheckHomoglyphs(parsed *ParsedResource, crdInfo *CRDInfo) error {
+	var keys []string
+	if crdInfo != nil && crdInfo.URNFormat != "" {
+		for _, key := range TemplatePlaceholders(crdInfo.URNFormat) {
+			if _, exists := parsed.Fields[key]; exists && key != "ccrn" {
+				keys = append(keys, key)
+			}
+		}
+	} else {
+		for key := range parsed.Fields {
+			if key != "ccrn" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var errs []error
+	for _, key := range keys {
+		values := []string{parsed.Fields[key]}
+		if items, isList := ListValue(parsed.Fields[key]); isList {
+			values = items
+		}
+		for _, value := range values {
+			if reason := CheckHomoglyph(value); reason != "" {
+				errs = append(errs, &FieldError{Field: key, Value: value, Reason: reason})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Unicode normalization", func() {
+	composed, decomposed := "caf\u00e9", "cafe\u0301"
+
+	It("normalizes values to NFC", func() {
+		// Act & Assert
+		Expect(apis.NormalizeValue(decomposed)).To(Equal(composed))
+		Expect(apis.NormalizeValue(composed)).To(Equal(composed))
+		Expect(apis.NormalizeValue("my-pod")).To(Equal("my-pod"))
+	})
+
+	It("treats composed and decomposed values as the same resource", func() {
+		// Arrange
+		a, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, name=" + composed)
+		Expect(err).ToNot(HaveOccurred())
+		b, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, name=" + decomposed)
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(a.Equals(b)).To(BeTrue())
+		Expect(apis.Diff(a, b)).To(BeEmpty())
+		Expect(a.CanonicalCCRN()).To(Equal(b.CanonicalCCRN()))
+		Expect(a.Fingerprint()).To(Equal(b.Fingerprint()))
+	})
+})
+
+var _ = Describe("CheckHomoglyph", func() {
+	DescribeTable("screens values",
+		func(value, expected string) {
+			// Act & Assert
+			Expect(apis.CheckHomoglyph(value)).To(Equal(expected))
+		},
+		Entry("with Latin letters", "payments-eu-1", ""),
+		Entry("with accented Latin letters", "café", ""),
+		Entry("with Cyrillic words", "платежи", ""),
+		Entry("with Japanese mixed with Latin", "東京-tokyo", ""),
+		Entry("with a Cyrillic letter among Latin ones", "pаyments", "mixes Cyrillic and Latin letters"),
+		Entry("with a Greek letter among Latin ones", "dοcs", "mixes Greek and Latin letters"),
+		Entry("with Cyrillic letters looking like Latin ones", "сео", "consists of Cyrillic letters looking like Latin ones"),
+	)
+
+	It("screens the identifier fields of the URN template only", func() {
+		// Arrange
+		crdInfo := &apis.CRDInfo{URNFormat: "urn:ccrn:<ccrn>/<cluster>/<name>"}
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=[pаy,сео], note=pаy")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		err = apis.CheckHomoglyphs(parsed, crdInfo)
+		unknown := apis.CheckHomoglyphs(parsed, nil)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		Expect(apis.ErrorMessages(err)).To(Equal([]string{
+			"field name: value 'pаy' mixes Cyrillic and Latin letters",
+			"field name: value 'сео' consists of Cyrillic letters looking like Latin ones",
+		}))
+		Expect(apis.ErrorMessages(unknown)).To(HaveLen(3), "all fields of unknown resource types")
+	})
+})
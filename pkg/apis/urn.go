@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"strings"
+)
+
+// URNPrefix is the default prefix of CCRN URNs, deployments with a registered URN namespace
+// may use their own "urn:<NID>:" prefix instead
+const URNPrefix = "urn:ccrn:"
+
+// TemplatePlaceholders returns the field names referenced by the template, in order of appearance
+func TemplatePlaceholders(template string) []string {
+	matches := placeholderPattern.FindAllStringSubmatch(template, -1)
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		name, _, _ := strings.Cut(match[1], ":")
+		names = append(names, name)
+	}
+	return names
+}
+
+// ParseURN parses a URN with the URN template of its resource type, the RFC 8141 components
+// of the URN are kept in URNComponents
+func ParseURN(urn, template string) (*ParsedResource, error) {
+	name, components := SplitURNComponents(urn)
+	compiled, err := compileTemplateCached(template)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := compiled.Match(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := fields["ccrn"]; !exists {
+		return nil, errors.New("missing required field: ccrn")
+	}
+	return &ParsedResource{
+		Format:        "URN",
+		Fields:        fields,
+		Raw:           urn,
+		URNComponents: components,
+	}, nil
+}
+
+// ParseURNWithTemplates parses a URN with the first of the templates matching it, e.g. the current URN template of
+// its resource type followed by its legacy templates, and records the matching template in UrnTemplate. If no
+// template matches, the error of the first one is returned.
+func ParseURNWithTemplates(urn string, templates ...string) (*ParsedResource, error) {
+	if len(templates) == 0 {
+		return nil, Errorf(ErrTemplateMismatch, "no URN template for %s", urn)
+	}
+	var firstErr error
+	for _, template := range templates {
+		parsed, err := ParseURN(urn, template)
+		if err == nil {
+			parsed.UrnTemplate = template
+			return parsed, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// BuildURN renders the URN template with the fields of the parsed resource and appends its URN components.
+// Unlike ParsedResource.URN it fails if the template is malformed or a required placeholder has no
+// matching non-empty field, instead of returning a URN with leftover placeholders.
+func BuildURN(parsed *ParsedResource, template string) (string, error) {
+	if parsed == nil {
+		return "", errors.New("cannot build URN from nil resource")
+	}
+	if template == "" {
+		template = parsed.UrnTemplate
+	}
+	if template == "" {
+		return "", Errorf(ErrTemplateMismatch, "no URN template for %s", parsed.CCRNKey())
+	}
+
+	compiled, err := compileTemplateCached(template)
+	if err != nil {
+		return "", err
+	}
+	urn, err := compiled.Render(parsed.Fields)
+	if err != nil {
+		return "", err
+	}
+	return urn + parsed.URNComponents.String(), nil
+}
+
+// URNComponents are the optional RFC 8141 components of a URN. They carry metadata like a
+// revision or fragment, are not part of the template match and do not affect URN equality.
+type URNComponents struct {
+	R string `json:"r,omitempty"` // r-component, following "?+", passed to resolvers
+	Q string `json:"q,omitempty"` // q-component, following "?=", passed to the named resource
+	F string `json:"f,omitempty"` // f-component, following "#", identifies a part of the resource
+}
+
+// String renders the components in RFC 8141 order, empty components are left out
+func (c URNComponents) String() string {
+	var s string
+	if c.R != "" {
+		s += "?+" + c.R
+	}
+	if c.Q != "" {
+		s += "?=" + c.Q
+	}
+	if c.F != "" {
+		s += "#" + c.F
+	}
+	return s
+}
+
+// SplitURNComponents splits a URN into the assigned name and its r-, q- and f-components
+func SplitURNComponents(urn string) (string, URNComponents) {
+	var components URNComponents
+	if i := strings.Index(urn, "#"); i >= 0 {
+		urn, components.F = urn[:i], urn[i+1:]
+	}
+	if i := strings.Index(urn, "?="); i >= 0 {
+		urn, components.Q = urn[:i], urn[i+2:]
+	}
+	if i := strings.Index(urn, "?+"); i >= 0 {
+		urn, components.R = urn[:i], urn[i+2:]
+	}
+	return urn, components
+}
+
+// SplitURNPrefix splits a URN or URN template into its "urn:<NID>:" prefix and the namespace
+// specific remainder. It reports false if the input does not start with a URN prefix.
+func SplitURNPrefix(urn string) (string, string, bool) {
+	if len(urn) < 4 || !strings.EqualFold(urn[:4], "urn:") {
+		return "", urn, false
+	}
+	nid, nss, found := strings.Cut(urn[4:], ":")
+	if !found || nid == "" || strings.ContainsAny(nid, "/<>") {
+		return "", urn, false
+	}
+	return urn[:len(urn)-len(nss)], nss, true
+}
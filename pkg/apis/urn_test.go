@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "APIs Suite")
+}
+
+var _ = Describe("BuildURN", func() {
+	var parsed *apis.ParsedResource
+
+	BeforeEach(func() {
+		parsed = &apis.ParsedResource{
+			Format: "CCRN",
+			Fields: map[string]string{"ccrn": "pod.k8s-registry.ccrn.example.com/v1", "cluster": "c1", "name": "my-pod"},
+		}
+	})
+
+	It("renders all placeholders", func() {
+		// Act
+		urn, err := apis.BuildURN(parsed, "urn:ccrn:<ccrn>/<cluster>/<name>")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/c1/my-pod"))
+	})
+
+	It("falls back to the template the resource was parsed with", func() {
+		// Arrange
+		parsed.UrnTemplate = "urn:ccrn:<ccrn>/<name>"
+		// Act
+		urn, err := apis.BuildURN(parsed, "")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/my-pod"))
+	})
+
+	It("fails on placeholders without a field", func() {
+		// Act
+		_, err := apis.BuildURN(parsed, "urn:ccrn:<ccrn>/<namespace>/<name>/<container>")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		Expect(err.Error()).To(ContainSubstring("<namespace>, <container>"))
+	})
+
+	It("fails on empty field values", func() {
+		// Arrange
+		parsed.Fields["cluster"] = ""
+		// Act
+		_, err := apis.BuildURN(parsed, "urn:ccrn:<ccrn>/<cluster>/<name>")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("<cluster>")))
+	})
+
+	It("fails on malformed templates", func() {
+		// Act
+		_, err := apis.BuildURN(parsed, "urn:ccrn:<ccrn>/<name")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("unbalanced")))
+	})
+
+	It("fails without a template", func() {
+		// Act
+		_, err := apis.BuildURN(parsed, "")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ParseURNWithTemplates", func() {
+	templates := []string{"urn:ccrn:<ccrn>/<region>/<name:dns>", "urn:ccrn:<ccrn>/<name:dns>"}
+
+	It("parses with the first matching template", func() {
+		// Act
+		current, currentErr := apis.ParseURNWithTemplates("urn:ccrn:disk.example.com/v1/eu-de-1/data", templates...)
+		legacy, legacyErr := apis.ParseURNWithTemplates("urn:ccrn:disk.example.com/v1/data?=rev=2", templates...)
+		// Assert
+		Expect(currentErr).ToNot(HaveOccurred())
+		Expect(current.Fields).To(HaveKeyWithValue("region", "eu-de-1"))
+		Expect(current.UrnTemplate).To(Equal(templates[0]))
+		Expect(legacyErr).ToNot(HaveOccurred())
+		Expect(legacy.Fields).To(Equal(map[string]string{"ccrn": "disk.example.com/v1", "name": "data"}))
+		Expect(legacy.UrnTemplate).To(Equal(templates[1]))
+		Expect(legacy.URNComponents.Q).To(Equal("rev=2"))
+	})
+
+	It("fails with the error of the first template if none matches", func() {
+		// Act
+		_, err := apis.ParseURNWithTemplates("urn:ccrn:disk.example.com/v1/eu-de-1/Data", templates...)
+		_, noTemplateErr := apis.ParseURNWithTemplates("urn:ccrn:disk.example.com/v1/data")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		Expect(noTemplateErr).To(MatchError(apis.ErrTemplateMismatch))
+	})
+})
+
+var _ = Describe("SplitURNComponents", func() {
+	DescribeTable("splits off the RFC 8141 components",
+		func(input, expectedURN string, expected apis.URNComponents) {
+			// Act
+			urn, components := apis.SplitURNComponents(input)
+			// Assert
+			Expect(urn).To(Equal(expectedURN))
+			Expect(components).To(Equal(expected))
+			Expect(urn + components.String()).To(Equal(input))
+		},
+		Entry("no components", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", apis.URNComponents{}),
+		Entry("r-component", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo?+rev=2", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", apis.URNComponents{R: "rev=2"}),
+		Entry("q-component", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo?=a=b", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", apis.URNComponents{Q: "a=b"}),
+		Entry("f-component", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo#spec", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", apis.URNComponents{F: "spec"}),
+		Entry("all components", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo?+r?=q#f", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", apis.URNComponents{R: "r", Q: "q", F: "f"}),
+	)
+
+	It("ignores components when comparing URNs", func() {
+		// Act
+		equal, err := apis.Equal("urn:ccrn:pod.k8s.ccrn.example.com/v1/foo#status", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo?+rev=1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(equal).To(BeTrue())
+	})
+})
+
+var _ = Describe("SplitURNPrefix", func() {
+	DescribeTable("splits off the URN namespace",
+		func(input, expectedPrefix, expectedNSS string, expectedOK bool) {
+			// Act
+			prefix, nss, ok := apis.SplitURNPrefix(input)
+			// Assert
+			Expect(prefix).To(Equal(expectedPrefix))
+			Expect(nss).To(Equal(expectedNSS))
+			Expect(ok).To(Equal(expectedOK))
+		},
+		Entry("default namespace", "urn:ccrn:pod.k8s.ccrn.example.com/v1/foo", "urn:ccrn:", "pod.k8s.ccrn.example.com/v1/foo", true),
+		Entry("custom namespace", "urn:sap-ccrn:<ccrn>/<name>", "urn:sap-ccrn:", "<ccrn>/<name>", true),
+		Entry("uppercase scheme", "URN:ccrn:pod.k8s.ccrn.example.com/v1/foo", "URN:ccrn:", "pod.k8s.ccrn.example.com/v1/foo", true),
+		Entry("no URN", "ccrn=pod.k8s.ccrn.example.com/v1", "", "ccrn=pod.k8s.ccrn.example.com/v1", false),
+		Entry("missing namespace", "urn:pod.k8s.ccrn.example.com/v1", "", "urn:pod.k8s.ccrn.example.com/v1", false),
+	)
+})
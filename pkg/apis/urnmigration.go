@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TemplateMapping maps the fields of a former URN template to those of the current one, e.g. {"az": "zone"} if the
+// <az> placeholder became <zone>. Fields not mapped keep their name, segments moved to another position need no
+// mapping as they are matched by name.
+type TemplateMapping map[string]string
+
+// ParseTemplateMapping parses a comma separated list of <former field>=<current field> pairs like "az=zone"
+func ParseTemplateMapping(s string) (TemplateMapping, error) {
+	var mapping TemplateMapping
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		from, to, found := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !found || from == "" || to == "" || from == "ccrn" || to == "ccrn" {
+			return nil, fmt.Errorf("invalid template mapping %q, use <former field>=<current field>", strings.TrimSpace(pair))
+		}
+		if mapping == nil {
+			mapping = TemplateMapping{}
+		}
+		mapping[from] = to
+	}
+	return mapping, nil
+}
+
+// Apply returns the fields renamed according to the mapping, the fields themselves are left unchanged
+func (m TemplateMapping) Apply(fields map[string]string) map[string]string {
+	if len(m) == 0 {
+		return fields
+	}
+	renamed := make(map[string]string, len(fields))
+	for key, value := range fields {
+		if to, mapped := m[key]; mapped {
+			key = to
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+// ApplyTemplate returns the template with its placeholders renamed according to the mapping, their constraints are
+// kept, e.g. "urn:ccrn:<ccrn>/<az:[a-z0-9]+>" becomes "urn:ccrn:<ccrn>/<zone:[a-z0-9]+>" for {"az": "zone"}
+func (m TemplateMapping) ApplyTemplate(template string) string {
+	if len(m) == 0 {
+		return template
+	}
+	return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name, constraint, constrained := strings.Cut(placeholder[1:len(placeholder)-1], ":")
+		to, mapped := m[name]
+		if !mapped {
+			return placeholder
+		}
+		if constrained {
+			return "<" + to + ":" + constraint + ">"
+		}
+		return "<" + to + ">"
+	})
+}
+
+// ConvertURN converts a URN of one URN template to another one, e.g. to upgrade stored URNs after the template of
+// their resource type changed. The RFC 8141 components of the URN are kept. See ConvertURNMapped for renamed fields.
+func ConvertURN(urn, fromTemplate, toTemplate string) (string, error) {
+	return ConvertURNMapped(urn, fromTemplate, toTemplate, nil)
+}
+
+// ConvertURNMapped is like ConvertURN, but renames the fields matched by fromTemplate according to the mapping before
+// rendering them with toTemplate. It fails with ErrTemplateMismatch if a field would be lost because toTemplate has
+// no placeholder for it.
+func ConvertURNMapped(urn, fromTemplate, toTemplate string, mapping TemplateMapping) (string, error) {
+	parsed, err := ParseURN(urn, fromTemplate)
+	if err != nil {
+		return "", err
+	}
+	parsed.Fields = mapping.Apply(parsed.Fields)
+	return renderConverted(parsed, toTemplate)
+}
+
+// UpgradeURN converts a URN of the version to its current URN template. URNs of legacy templates are parsed with
+// the first matching one and their fields renamed according to LegacyURNMapping, URNs of the current template are
+// returned in their rendered form.
+func (c *CRDInfo) UpgradeURN(urn string) (string, error) {
+	parsed, err := ParseURNWithTemplates(urn, c.URNFormats()...)
+	if err != nil {
+		return "", err
+	}
+	if parsed.UrnTemplate != c.URNFormat {
+		parsed.Fields = c.LegacyURNMapping.Apply(parsed.Fields)
+	}
+	return renderConverted(parsed, c.URNFormat)
+}
+
+// renderConverted renders the fields of the parsed URN with the template, failing if a field has no placeholder
+func renderConverted(parsed *ParsedResource, template string) (string, error) {
+	placeholders := TemplatePlaceholders(template)
+	for _, key := range slices.Sorted(maps.Keys(parsed.Fields)) {
+		if !slices.Contains(placeholders, key) {
+			return "", Errorf(ErrTemplateMismatch, "field %s of the URN has no placeholder in the URN template %s", key, template)
+		}
+	}
+	return BuildURN(parsed, template)
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ParseTemplateMapping", func() {
+	It("parses the renamed fields", func() {
+		// Act
+		mapping, err := apis.ParseTemplateMapping("az=zone, cluster = region,")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping).To(Equal(apis.TemplateMapping{"az": "zone", "cluster": "region"}))
+	})
+
+	DescribeTable("rejects invalid mappings",
+		func(annotation string) {
+			// Act
+			_, err := apis.ParseTemplateMapping(annotation)
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("use <former field>=<current field>")))
+		},
+		Entry("without current field", "az"),
+		Entry("with an empty field", "az="),
+		Entry("renaming the ccrn field", "ccrn=type"),
+	)
+
+	It("renames the placeholders of templates", func() {
+		// Arrange
+		mapping := apis.TemplateMapping{"az": "zone"}
+		// Act & Assert
+		Expect(mapping.ApplyTemplate("urn:ccrn:<ccrn>/<region>-<az:[a-z0-9]+>/[<az2>]")).To(Equal("urn:ccrn:<ccrn>/<region>-<zone:[a-z0-9]+>/[<az2>]"))
+	})
+})
+
+var _ = Describe("ConvertURN", func() {
+	It("moves the segments to their position in the new template", func() {
+		// Act
+		urn, err := apis.ConvertURN("urn:ccrn:pod.example.com/v1/web/eu-de-1#logs", "urn:ccrn:<ccrn>/<name>/<cluster>", "urn:ccrn:<ccrn>/<cluster>/<name>")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:pod.example.com/v1/eu-de-1/web#logs"))
+	})
+
+	It("renames fields according to the mapping", func() {
+		// Act
+		urn, err := apis.ConvertURNMapped("urn:ccrn:vm.example.com/v1/eu-de/1a/web", "urn:ccrn:<ccrn>/<region>/<az>/<name>",
+			"urn:ccrn:<ccrn>/<region:[a-z]+-[a-z]+>-<zone>/<name>", apis.TemplateMapping{"az": "zone"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:vm.example.com/v1/eu-de-1a/web"))
+	})
+
+	It("fails if a field would be lost", func() {
+		// Act
+		_, err := apis.ConvertURN("urn:ccrn:pod.example.com/v1/eu-de-1/web", "urn:ccrn:<ccrn>/<cluster>/<name>", "urn:ccrn:<ccrn>/<name>")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		Expect(err).To(MatchError(ContainSubstring("field cluster of the URN has no placeholder in the URN template urn:ccrn:<ccrn>/<name>")))
+	})
+
+	It("fails for URNs not matching the former template", func() {
+		// Act
+		_, err := apis.ConvertURN("urn:ccrn:pod.example.com/v1/web", "urn:ccrn:<ccrn>/<cluster>/<name>", "urn:ccrn:<ccrn>/<name>/<cluster>")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+	})
+})
+
+var _ = Describe("UpgradeURN", func() {
+	crdInfo := &apis.CRDInfo{
+		URNFormat:        "urn:ccrn:<ccrn>/<region:[a-z]+-[a-z]+>-<zone>/<name:dns>",
+		LegacyURNFormats: []string{"urn:ccrn:<ccrn>/<region>/<az>/<name>"},
+		LegacyURNMapping: apis.TemplateMapping{"az": "zone"},
+	}
+
+	It("converts URNs of legacy templates to the current template", func() {
+		// Act
+		urn, err := crdInfo.UpgradeURN("urn:ccrn:vm.example.com/v1/eu-de/1a/web")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:vm.example.com/v1/eu-de-1a/web"))
+	})
+
+	It("keeps URNs of the current template", func() {
+		// Act
+		urn, err := crdInfo.UpgradeURN("urn:ccrn:vm.example.com/v1/eu-de-1a/web")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(urn).To(Equal("urn:ccrn:vm.example.com/v1/eu-de-1a/web"))
+	})
+})
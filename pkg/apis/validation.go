@@ -4,6 +4,11 @@
 package apis
 
 import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
@@ -27,22 +32,213 @@ type ValidationBackend interface {
 	IsResourceTypeSupported(ccrnVersion string) bool
 }
 
+// CRDLister is implemented by backends that can enumerate the resource types they know, e.g. for shell completion
+type CRDLister interface {
+	// ListCRDs returns the information of all cached CRD versions sorted by CCRN key
+	ListCRDs() []*CRDInfo
+}
+
+// CRDSource is implemented by backends keeping the CustomResourceDefinitions their CRD versions stem from,
+// e.g. for snapshotting them into a bundle
+type CRDSource interface {
+	// CustomResourceDefinitions returns the cached CRDs sorted by name
+	CustomResourceDefinitions() []*v1.CustomResourceDefinition
+}
+
 // CRDInfo contains information about a Custom Resource Definition
 type CRDInfo struct {
-	Name      string              // CRD name (e.g., "pod.k8s-registry.ccrn.example.com")
-	Plural    string              // Plural resource name (e.g., "pods")
-	Singular  string              // Singular resource name (e.g., "pod")
-	Group     string              // API group (e.g., "k8s-registry.ccrn.example.com")
-	Kind      string              // Resource kind (e.g., "pod")
-	Version   string              // API version (e.g., "v1")
-	Schema    *v1.JSONSchemaProps // OpenAPI schema (for offline validation)
-	URNFormat string              // URN template from annotations
+	Name               string                  `json:"name"`                         // CRD name (e.g., "pod.k8s-registry.ccrn.example.com")
+	Plural             string                  `json:"plural"`                       // Plural resource name (e.g., "pods")
+	Singular           string                  `json:"singular"`                     // Singular resource name (e.g., "pod")
+	Group              string                  `json:"group"`                        // API group (e.g., "k8s-registry.ccrn.example.com")
+	Kind               string                  `json:"kind"`                         // Resource kind (e.g., "pod")
+	Version            string                  `json:"version"`                      // API version (e.g., "v1")
+	Schema             *v1.JSONSchemaProps     `json:"schema,omitempty"`             // OpenAPI schema (for offline validation)
+	URNFormat          string                  `json:"urnFormat,omitempty"`          // URN template from annotations
+	LegacyURNFormats   []string                `json:"legacyUrnFormats,omitempty"`   // Former URN templates still accepted when parsing, in priority order
+	LegacyURNMapping   TemplateMapping         `json:"legacyUrnMapping,omitempty"`   // Fields of the legacy URN templates renamed in the current one
+	Deprecated         bool                    `json:"deprecated,omitempty"`         // Version is deprecated in the CRD or by its ccrn/<version>.deprecated annotation
+	DeprecationWarning string                  `json:"deprecationWarning,omitempty"` // Optional deprecation warning of the version
+	SunsetDate         time.Time               `json:"sunsetDate,omitzero"`          // Day after which the deprecated version may be rejected, see Sunset
+	FieldMapping       map[string]string       `json:"fieldMapping,omitempty"`       // Field names of this version mapped to the storage version field names
+	Aliases            []string                `json:"aliases,omitempty"`            // Short names of the resource type, see AliasResolver
+	ExternalTemplates  map[string]string       `json:"externalTemplates,omitempty"`  // Templates of external identifiers by scheme (e.g. "arn"), see pkg/convert
+	ObjectMapping      map[string]string       `json:"objectMapping,omitempty"`      // Fields mapped to paths in the live objects identified by the CCRNs, see ResourceFromObject
+	SourceResource     string                  `json:"sourceResource,omitempty"`     // Kubernetes resource of the live objects (e.g. "deployments.apps"), defaults to Kind
+	Wildcards          map[string]WildcardMode `json:"wildcards,omitempty"`          // Meaning of "*" by field, nil if not declared, see WildcardMode
 }
 
-// ValidationResult contains the result of a CCRN validation
+// ValidationResult contains the result of a CCRN validation.
+// It marshals to JSON and, via sigs.k8s.io/yaml, to YAML with a stable field order.
 type ValidationResult struct {
-	Valid      bool            // Whether the CCRN is valid
-	ParsedCCRN *ParsedResource // The parsed CCRN
-	Errors     []string        // Validation errors
-	Warnings   []string        // Validation warnings
+	Valid      bool            `json:"valid"`                // Whether the CCRN is valid
+	ParsedCCRN *ParsedResource `json:"parsedCCRN,omitempty"` // The parsed CCRN
+	Errors     []string        `json:"errors,omitempty"`     // Validation errors
+	Warnings   []string        `json:"warnings,omitempty"`   // Validation warnings
+}
+
+// CCRNKey returns the CCRN key "<kind>.<group>/<version>" of the CRD version
+func (c *CRDInfo) CCRNKey() string {
+	return strings.ToLower(c.Kind+"."+c.Group) + "/" + c.Version
+}
+
+// URNFormats returns the URN templates accepted when parsing URNs of the version: the current template used for
+// rendering, followed by the legacy templates in priority order. It is empty if the version has no URN template.
+func (c *CRDInfo) URNFormats() []string {
+	if c.URNFormat == "" {
+		return nil
+	}
+	return append([]string{c.URNFormat}, c.LegacyURNFormats...)
+}
+
+// SpecWrapped reports whether the schema expects the CCRN fields in a spec object rather than at the
+// top level of the resource, i.e. it declares a spec object next to the standard Kubernetes fields only
+func (c *CRDInfo) SpecWrapped() bool {
+	if c == nil || c.Schema == nil {
+		return false
+	}
+	spec, hasSpec := c.Schema.Properties["spec"]
+	if !hasSpec || spec.Type != "object" {
+		return false
+	}
+	for name := range c.Schema.Properties {
+		switch name {
+		case "apiVersion", "kind", "metadata", "spec", "status":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FieldsSchema returns the object schema declaring the CCRN fields, the spec schema of spec-wrapped CRDs
+func (c *CRDInfo) FieldsSchema() *v1.JSONSchemaProps {
+	if c.SpecWrapped() {
+		spec := c.Schema.Properties["spec"]
+		return &spec
+	}
+	return c.Schema
+}
+
+// DeprecationMessage returns the deprecation warning of the version, or a generic one if the CRD
+// declares none. It is empty if the version is not deprecated.
+func (c *CRDInfo) DeprecationMessage() string {
+	if !c.Deprecated {
+		return ""
+	}
+	message := c.CCRNKey() + " is deprecated"
+	if c.DeprecationWarning != "" {
+		message = c.DeprecationWarning
+	}
+	if !c.SunsetDate.IsZero() {
+		message += ", sunset on " + c.SunsetDate.Format(time.DateOnly)
+	}
+	return message
+}
+
+// Sunset reports whether the sunset date of the version has passed at the time, versions are sunset
+// at the end of the day of their sunset date
+func (c *CRDInfo) Sunset(now time.Time) bool {
+	return !c.SunsetDate.IsZero() && !now.Before(c.SunsetDate.AddDate(0, 0, 1))
+}
+
+// HasField reports whether the schema declares the field. Dot paths like "metadata.team" are
+// resolved through the properties of nested objects and the additionalProperties of maps.
+func (c *CRDInfo) HasField(path string) bool {
+	_, declared := c.FieldSchema(path)
+	return declared
+}
+
+// FieldSchema returns the schema of the field at the dot path, see HasField. Map values allowed
+// without a schema are described by an empty schema.
+func (c *CRDInfo) FieldSchema(path string) (*v1.JSONSchemaProps, bool) {
+	if c == nil || c.Schema == nil {
+		return nil, false
+	}
+	schema := c.FieldsSchema()
+	if strings.HasPrefix(path, "metadata.") {
+		schema = c.Schema
+	}
+	for _, name := range strings.Split(path, ".") {
+		if property, declared := schema.Properties[name]; declared {
+			schema = &property
+			continue
+		}
+		// Maps like labels declare their values via additionalProperties
+		additional := schema.AdditionalProperties
+		if additional == nil || (!additional.Allows && additional.Schema == nil) {
+			return nil, false
+		}
+		if additional.Schema == nil {
+			schema = &v1.JSONSchemaProps{}
+			continue
+		}
+		schema = additional.Schema
+	}
+	return schema, true
+}
+
+// ValidateAgainst checks the resource locally against the schema of its resource type version: the resource
+// type must match, fields must be declared, required fields present and values must satisfy their enum, length
+// and pattern constraints. It needs no backend, so producers can validate before ever talking to a cluster.
+// It does not evaluate the full OpenAPI schema, the backends still do.
+func (p *ParsedResource) ValidateAgainst(schema *CRDInfo) error {
+	if schema == nil {
+		return Errorf(ErrUnsupportedType, "no schema for resource type %s", p.CCRNKey())
+	}
+	if !strings.EqualFold(p.CCRNKey(), schema.CCRNKey()) {
+		return Errorf(ErrUnsupportedType, "resource type %s does not match schema %s", p.CCRNKey(), schema.CCRNKey())
+	}
+	return CheckSchemaFields(p, schema)
+}
+
+// CheckSchemaFields verifies the parsed fields against the properties and required fields of the schema,
+// and the values of declared fields against their enum, length and pattern constraints, see CheckFieldConstraints.
+// All violations are reported at once, joined with errors.Join, see ErrorMessages. Without schema nothing is checked.
+func CheckSchemaFields(parsed *ParsedResource, crdInfo *CRDInfo) error {
+	if crdInfo == nil || crdInfo.Schema == nil {
+		return nil
+	}
+
+	var unknown, missing []string
+	for key := range parsed.Fields {
+		if !crdInfo.HasField(key) && key != "ccrn" {
+			unknown = append(unknown, key)
+		}
+	}
+	for _, key := range crdInfo.FieldsSchema().Required {
+		if !hasFieldPath(parsed.Fields, key) {
+			missing = append(missing, key)
+		}
+	}
+
+	var errs []error
+	if len(unknown) > 0 || len(missing) > 0 {
+		sort.Strings(unknown)
+		var problems []string
+		if len(unknown) > 0 {
+			problems = append(problems, "unknown fields: "+strings.Join(unknown, ", "))
+		}
+		if len(missing) > 0 {
+			problems = append(problems, "missing required fields: "+strings.Join(missing, ", "))
+		}
+		errs = append(errs, Errorf(ErrSchemaViolation, "invalid fields for %s: %s", parsed.CCRNKey(), strings.Join(problems, "; ")))
+	}
+	if err := CheckFieldConstraints(parsed, crdInfo); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// hasFieldPath reports whether the field or any dot path below it is set
+func hasFieldPath(fields map[string]string, key string) bool {
+	if _, present := fields[key]; present {
+		return true
+	}
+	for field := range fields {
+		if strings.HasPrefix(field, key+".") {
+			return true
+		}
+	}
+	return false
 }
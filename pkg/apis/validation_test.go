@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Marshaling", func() {
+	var result *apis.ValidationResult
+
+	BeforeEach(func() {
+		result = &apis.ValidationResult{
+			Valid: true,
+			ParsedCCRN: &apis.ParsedResource{
+				Format: "CCRN",
+				Fields: map[string]string{"name": "my-pod", "ccrn": "pod.k8s-registry.ccrn.example.com/v1", "cluster": "c1"},
+				Raw:    "ccrn=pod.k8s-registry.ccrn.example.com/v1, name=my-pod, cluster=c1",
+			},
+			Warnings: []string{"deprecated"},
+		}
+	})
+
+	It("marshals a validation result to stable JSON", func() {
+		// Act
+		data, err := json.Marshal(result)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"valid":true,"parsedCCRN":{"format":"CCRN","fields":{"ccrn":"pod.k8s-registry.ccrn.example.com/v1","cluster":"c1","name":"my-pod"},` +
+			`"raw":"ccrn=pod.k8s-registry.ccrn.example.com/v1, name=my-pod, cluster=c1"},"warnings":["deprecated"]}`))
+	})
+
+	It("round-trips a validation result through YAML", func() {
+		// Arrange
+		data, err := yaml.Marshal(result)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		var decoded apis.ValidationResult
+		err = yaml.Unmarshal(data, &decoded)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(&decoded).To(Equal(result))
+	})
+
+	It("never decodes a parsed resource with nil fields", func() {
+		// Act
+		var decoded apis.ParsedResource
+		err := json.Unmarshal([]byte(`{"format":"URN"}`), &decoded)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decoded.Fields).ToNot(BeNil())
+	})
+
+	It("marshals CRD information without empty optional fields", func() {
+		// Arrange
+		info := apis.CRDInfo{Name: "pod.k8s-registry.ccrn.example.com", Kind: "pod", Version: "v1", URNFormat: "urn:ccrn:<ccrn>/<name>"}
+		// Act
+		data, err := json.Marshal(info)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"name":"pod.k8s-registry.ccrn.example.com","plural":"","singular":"","group":"","kind":"pod","version":"v1","urnFormat":"urn:ccrn:<ccrn>/<name>"}`))
+	})
+})
+
+var _ = Describe("ValidateAgainst", func() {
+	schema := &apis.CRDInfo{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1", Schema: &apiextensionsv1.JSONSchemaProps{
+		Required: []string{"ccrn", "cluster", "name"},
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"ccrn":    {Type: "string"},
+			"cluster": {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"eu-de-1"`)}, {Raw: []byte(`"eu-de-2"`)}}},
+			"name":    {Type: "string", Pattern: "^[a-z0-9-]+$"},
+		},
+	}}
+	parse := func(ccrn string) *apis.ParsedResource {
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed
+	}
+
+	It("accepts resources satisfying the schema", func() {
+		// Act & Assert
+		Expect(parse("ccrn=Pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod").ValidateAgainst(schema)).To(Succeed())
+	})
+
+	It("reports missing required fields, enum and pattern violations", func() {
+		// Act
+		missingErr := parse("ccrn=pod.k8s.ccrn.example.com/v1, name=my-pod").ValidateAgainst(schema)
+		enumErr := parse("ccrn=pod.k8s.ccrn.example.com/v1, cluster=us-east-1, name=my-pod").ValidateAgainst(schema)
+		patternErr := parse("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-2, name=My_Pod").ValidateAgainst(schema)
+		// Assert
+		Expect(missingErr).To(MatchError(ContainSubstring("missing required fields: cluster")))
+		Expect(enumErr).To(MatchError(apis.ErrSchemaViolation))
+		Expect(enumErr).To(MatchError(ContainSubstring("is not one of")))
+		Expect(patternErr).To(MatchError(apis.ErrSchemaViolation))
+	})
+
+	It("reports all violations at once", func() {
+		// Act
+		err := parse("ccrn=pod.k8s.ccrn.example.com/v1, cluster=us-east-1, name=My_Pod, color=blue").ValidateAgainst(schema)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		Expect(apis.ErrorMessages(err)).To(Equal([]string{
+			"invalid fields for pod.k8s.ccrn.example.com/v1: unknown fields: color",
+			`field cluster: value 'us-east-1' is not one of "eu-de-1", "eu-de-2"`,
+			"field name: value 'My_Pod' does not match pattern ^[a-z0-9-]+$",
+		}))
+	})
+
+	It("returns no messages without error", func() {
+		// Act & Assert
+		Expect(apis.ErrorMessages(nil)).To(BeNil())
+	})
+
+	It("rejects resources of another type or without schema", func() {
+		// Arrange
+		parsed := parse("ccrn=pod.k8s.ccrn.example.com/v2, cluster=eu-de-1, name=my-pod")
+		// Act & Assert
+		Expect(parsed.ValidateAgainst(schema)).To(MatchError(apis.ErrUnsupportedType))
+		Expect(parsed.ValidateAgainst(nil)).To(MatchError(apis.ErrUnsupportedType))
+	})
+})
+
+var _ = Describe("Sunset", func() {
+	crdInfo := &apis.CRDInfo{Group: "ccrn.example.com", Version: "v1alpha1", Kind: "widget", Deprecated: true,
+		SunsetDate: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	It("sunsets versions at the end of their sunset date", func() {
+		// Act & Assert
+		Expect(crdInfo.Sunset(time.Date(2025, time.December, 31, 23, 59, 0, 0, time.UTC))).To(BeFalse())
+		Expect(crdInfo.Sunset(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect((&apis.CRDInfo{Deprecated: true}).Sunset(time.Now())).To(BeFalse())
+	})
+
+	It("names the sunset date in the deprecation message", func() {
+		// Act & Assert
+		Expect(crdInfo.DeprecationMessage()).To(Equal("widget.ccrn.example.com/v1alpha1 is deprecated, sunset on 2025-12-31"))
+	})
+})
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Wildcard is the field value matching any value, see package ccrnset
+const Wildcard = "*"
+
+// NearLimitRatio is the share of a length limit from which on lengths are reported as close to the limit
+const NearLimitRatio = 0.9
+
+// Warnings returns the warnings about a valid resource: the deprecation of its version, fields set more
+// than once, wildcard fields and an input or field values close to their maximum length. The schema may be nil, a maxLength <= 0
+// skips the check of the input length.
+func Warnings(input string, parsed *ParsedResource, crdInfo *CRDInfo, maxLength int) []string {
+	var warnings []string
+	if crdInfo != nil {
+		if message := crdInfo.DeprecationMessage(); message != "" {
+			warnings = append(warnings, message)
+		}
+	}
+	if length := utf8.RuneCountInString(input); nearLimit(int64(length), int64(maxLength)) {
+		warnings = append(warnings, fmt.Sprintf("length of %d characters is close to the maximum of %d", length, maxLength))
+	}
+	for _, key := range RepeatedFields(input) {
+		warnings = append(warnings, fmt.Sprintf("field %s is set more than once, the last value wins", key))
+	}
+	if parsed == nil {
+		return warnings
+	}
+
+	keys := make([]string, 0, len(parsed.Fields))
+	for key := range parsed.Fields {
+		if key != "ccrn" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := parsed.Fields[key]
+		if crdInfo.IsWildcard(key, value) {
+			warnings = append(warnings, fmt.Sprintf("field %s is a wildcard and matches any value", key))
+			continue
+		}
+		schema, declared := crdInfo.FieldSchema(key)
+		if !declared || schema.MaxLength == nil {
+			continue
+		}
+		if length := int64(utf8.RuneCountInString(value)); nearLimit(length, *schema.MaxLength) {
+			warnings = append(warnings, fmt.Sprintf("field %s: length of %d characters is close to the maximum of %d", key, length, *schema.MaxLength))
+		}
+	}
+	return warnings
+}
+
+// nearLimit reports whether the length reached NearLimitRatio of the limit, limits <= 0 are disabled
+func nearLimit(length, limit int64) bool {
+	return limit > 0 && float64(length) >= NearLimitRatio*float64(limit)
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("Warnings", func() {
+	maxLength := int64(10)
+	schema := &apis.CRDInfo{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1alpha1", Deprecated: true, Schema: &apiextensionsv1.JSONSchemaProps{
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"cluster": {Type: "string"},
+			"name":    {Type: "string", MaxLength: &maxLength},
+		},
+	}}
+	parse := func(ccrn string) *apis.ParsedResource {
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed
+	}
+
+	It("warns about deprecated versions, wildcards and values close to their limits", func() {
+		// Arrange
+		input := "ccrn=pod.k8s.ccrn.example.com/v1alpha1, cluster=*, name=my-pod-123"
+		// Act
+		warnings := apis.Warnings(input, parse(input), schema, len(input)+1)
+		// Assert
+		Expect(warnings).To(Equal([]string{
+			"pod.k8s.ccrn.example.com/v1alpha1 is deprecated",
+			"length of 66 characters is close to the maximum of 67",
+			"field cluster is a wildcard and matches any value",
+			"field name: length of 10 characters is close to the maximum of 10",
+		}))
+	})
+
+	It("warns about fields set more than once", func() {
+		// Arrange
+		input := "ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, cluster=eu-de-2"
+		parsed, err := apis.ParseCCRNKeepLast(input)
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(apis.Warnings(input, parsed, nil, 0)).To(Equal([]string{"field cluster is set more than once, the last value wins"}))
+	})
+
+	It("has no warnings for unremarkable resources", func() {
+		// Arrange
+		input := "ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo"
+		schema := &apis.CRDInfo{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1"}
+		// Act & Assert
+		Expect(apis.Warnings(input, parse(input), schema, apis.DefaultMaxLength)).To(BeEmpty())
+		Expect(apis.Warnings(strings.Repeat("a", apis.DefaultMaxLength), nil, nil, 0)).To(BeEmpty())
+	})
+})
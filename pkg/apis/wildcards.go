@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WildcardMode is the meaning of the value "*" in a field, see CRDInfo.Wildcards
+type WildcardMode string
+
+const (
+	// WildcardAny makes "*" match any value, the enum, length and pattern constraints of the field do not apply to it
+	WildcardAny WildcardMode = "any"
+	// WildcardLiteral makes "*" an ordinary value checked against the constraints of the field
+	WildcardLiteral WildcardMode = "literal"
+)
+
+// ParseWildcards parses comma separated <field>=<mode> pairs like "cluster=any, name=literal", see WildcardMode
+func ParseWildcards(s string) (map[string]WildcardMode, error) {
+	wildcards := make(map[string]WildcardMode)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, mode, found := strings.Cut(pair, "=")
+		field, mode = strings.TrimSpace(field), strings.TrimSpace(mode)
+		if !found || field == "" {
+			return nil, fmt.Errorf("invalid wildcard declaration %q, use <field>=any or <field>=literal", strings.TrimSpace(pair))
+		}
+		switch WildcardMode(mode) {
+		case WildcardAny, WildcardLiteral:
+			wildcards[field] = WildcardMode(mode)
+		default:
+			return nil, fmt.Errorf("invalid wildcard mode %q of field %s, use any or literal", mode, field)
+		}
+	}
+	return wildcards, nil
+}
+
+// WildcardMode returns the meaning of "*" in the field and whether the field accepts it. Resource types without
+// declared wildcards accept "*" in every field as a wildcard, as far as the schema of the field allows it. Dot
+// paths like labels.app have the mode of their top-level field.
+func (c *CRDInfo) WildcardMode(field string) (WildcardMode, bool) {
+	if c == nil || c.Wildcards == nil {
+		return WildcardAny, true
+	}
+	if mode, declared := c.Wildcards[field]; declared {
+		return mode, true
+	}
+	if top, _, nested := strings.Cut(field, "."); nested {
+		mode, declared := c.Wildcards[top]
+		return mode, declared
+	}
+	return "", false
+}
+
+// IsWildcard reports whether the value of the field is a wildcard matching any value, i.e. "*" in a field that
+// does not declare it literal
+func (c *CRDInfo) IsWildcard(field, value string) bool {
+	if value != Wildcard {
+		return false
+	}
+	mode, accepted := c.WildcardMode(field)
+	return accepted && mode == WildcardAny
+}
+
+// CheckWildcards rejects "*" in the fields of resource types declaring their wildcards unless the field accepts
+// it, see WildcardMode. List items are checked individually. All violations are reported as FieldErrors sorted
+// by field.
+func CheckWildcards(parsed *ParsedResource, crdInfo *CRDInfo) error {
+	if crdInfo == nil || crdInfo.Wildcards == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(parsed.Fields))
+	for key := range parsed.Fields {
+		if key != "ccrn" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		values := []string{parsed.Fields[key]}
+		if items, isList := ListValue(parsed.Fields[key]); isList {
+			values = items
+		}
+		for _, value := range values {
+			if err := checkWildcard(key, value, crdInfo); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkWildcard rejects "*" as value of a field not accepting it
+func checkWildcard(field, value string, crdInfo *CRDInfo) error {
+	if value != Wildcard {
+		return nil
+	}
+	if _, accepted := crdInfo.WildcardMode(field); !accepted {
+		return &FieldError{Field: field, Value: value, Reason: "is a wildcard, which the field does not accept"}
+	}
+	return nil
+}
+
+// declaredWildcard reports whether the value is "*" in a field explicitly declaring it a wildcard, the only
+// wildcards exempt from the constraints of the schema
+func (c *CRDInfo) declaredWildcard(field, value string) bool {
+	return c != nil && c.Wildcards != nil && c.IsWildcard(field, value)
+}
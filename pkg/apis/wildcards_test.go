@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package apis_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var _ = Describe("ParseWildcards", func() {
+	It("parses the modes of the fields", func() {
+		// Act
+		wildcards, err := apis.ParseWildcards("cluster=any, name = literal,")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wildcards).To(Equal(map[string]apis.WildcardMode{"cluster": apis.WildcardAny, "name": apis.WildcardLiteral}))
+	})
+
+	DescribeTable("rejects invalid declarations",
+		func(annotation, expected string) {
+			// Act
+			_, err := apis.ParseWildcards(annotation)
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("without mode", "cluster", `invalid wildcard declaration "cluster"`),
+		Entry("without field", "=any", `invalid wildcard declaration "=any"`),
+		Entry("with an unknown mode", "cluster=all", `invalid wildcard mode "all" of field cluster`),
+	)
+})
+
+var _ = Describe("Wildcards", func() {
+	var crdInfo *apis.CRDInfo
+
+	BeforeEach(func() {
+		crdInfo = &apis.CRDInfo{
+			Schema: &apiextensionsv1.JSONSchemaProps{Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"cluster": {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"eu-de-1"`)}}},
+				"name":    {Type: "string", Pattern: "^[a-z0-9-]+$"},
+				"zone":    {Type: "string"},
+				"zones":   {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+				"labels":  {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+			}},
+			Wildcards: map[string]apis.WildcardMode{"cluster": apis.WildcardAny, "name": apis.WildcardLiteral, "labels": apis.WildcardAny},
+		}
+	})
+
+	It("treats * as wildcard in every field without declarations", func() {
+		// Arrange
+		var undeclared *apis.CRDInfo
+		// Act & Assert
+		Expect(undeclared.IsWildcard("cluster", "*")).To(BeTrue())
+		Expect((&apis.CRDInfo{}).IsWildcard("name", "*")).To(BeTrue())
+		Expect((&apis.CRDInfo{}).IsWildcard("name", "my-pod")).To(BeFalse())
+	})
+
+	It("treats * as wildcard in fields declared any only", func() {
+		// Act & Assert
+		Expect(crdInfo.IsWildcard("cluster", "*")).To(BeTrue())
+		Expect(crdInfo.IsWildcard("labels.app", "*")).To(BeTrue(), "dot paths have the mode of their top-level field")
+		Expect(crdInfo.IsWildcard("name", "*")).To(BeFalse())
+		Expect(crdInfo.IsWildcard("zone", "*")).To(BeFalse())
+	})
+
+	It("rejects * in undeclared fields", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, cluster=*, name=*, zone=*, zones=[a,*]")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		err = apis.CheckWildcards(parsed, crdInfo)
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		Expect(apis.ErrorMessages(err)).To(Equal([]string{
+			"field zone: value '*' is a wildcard, which the field does not accept",
+			"field zones: value '*' is a wildcard, which the field does not accept",
+		}))
+	})
+
+	It("exempts wildcards declared any from the field constraints", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, cluster=*, name=*")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		err = apis.CheckFieldConstraints(parsed, crdInfo)
+		// Assert
+		Expect(apis.ErrorMessages(err)).To(Equal([]string{`field name: value '*' does not match pattern ^[a-z0-9-]+$`}))
+	})
+
+	It("warns about wildcards matching any value only", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s.ccrn.example.com/v1, cluster=*, name=*")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		warnings := apis.Warnings("", parsed, crdInfo, 0)
+		// Assert
+		Expect(warnings).To(Equal([]string{"field cluster is a wildcard and matches any value"}))
+	})
+})
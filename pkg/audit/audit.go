@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Decision values of an audit event
+const (
+	DecisionAllowed = "allowed"
+	DecisionDenied  = "denied"
+)
+
+// Event describes a single admission decision for compliance reporting
+type Event struct {
+	Timestamp time.Time       `json:"timestamp"`
+	UID       string          `json:"uid"`
+	User      string          `json:"user"`
+	Groups    []string        `json:"groups,omitempty"`
+	Operation string          `json:"operation"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	CCRN      string          `json:"ccrn,omitempty"`
+	URN       string          `json:"urn,omitempty"`
+	Decision  string          `json:"decision"`
+	Reason    string          `json:"reason,omitempty"`
+	Message   string          `json:"message,omitempty"`
+	Patches   json.RawMessage `json:"patches,omitempty"`
+	LatencyMS float64         `json:"latencyMs"`
+}
+
+// Sink receives audit events
+type Sink interface {
+	// Write persists or forwards a single audit event
+	Write(ctx context.Context, event Event) error
+	// Close flushes pending events and releases resources
+	Close() error
+}
+
+// NewSinkFromURL creates a sink from a URL:
+//   - file:///var/log/ccrn/audit.log?maxSizeMB=100&maxBackups=5 writes JSON lines with size based rotation
+//   - http(s)://collector.example.com/audit POSTs every event as JSON
+//   - kafka://broker-1:9092,broker-2:9092/topic produces every event as JSON message
+//
+// The returned sink is buffered, so slow sinks never block admission requests.
+func NewSinkFromURL(log *logrus.Logger, rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit sink URL %s: %w", rawURL, err)
+	}
+
+	var sink Sink
+	switch u.Scheme {
+	case "file":
+		maxSizeMB, err := intQueryParam(u, "maxSizeMB", 100)
+		if err != nil {
+			return nil, err
+		}
+		maxBackups, err := intQueryParam(u, "maxBackups", 5)
+		if err != nil {
+			return nil, err
+		}
+		sink, err = NewFileSink(u.Path, int64(maxSizeMB)*1024*1024, maxBackups)
+		if err != nil {
+			return nil, err
+		}
+	case "http", "https":
+		sink = NewWebhookSink(u.String(), 5*time.Second)
+	case "kafka":
+		topic := strings.TrimPrefix(u.Path, "/")
+		if topic == "" {
+			return nil, fmt.Errorf("kafka audit sink URL %s must contain a topic", rawURL)
+		}
+		sink = NewKafkaSink(strings.Split(u.Host, ","), topic)
+	default:
+		return nil, fmt.Errorf("unsupported audit sink scheme %q", u.Scheme)
+	}
+
+	return NewBufferedSink(log, sink, 1024), nil
+}
+
+func intQueryParam(u *url.URL, key string, defaultValue int) (int, error) {
+	raw := u.Query().Get(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", raw, key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/audit"
+)
+
+func TestAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Audit Suite")
+}
+
+var _ = Describe("FileSink", func() {
+	It("rotates the file when it exceeds the maximum size", func() {
+		// Arrange
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		sink, err := audit.NewFileSink(path, 200, 2)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		for i := 0; i < 5; i++ {
+			Expect(sink.Write(context.Background(), audit.Event{UID: "uid", Decision: audit.DecisionAllowed})).To(Succeed())
+		}
+		Expect(sink.Close()).To(Succeed())
+		// Assert
+		Expect(path + ".1").To(BeAnExistingFile())
+		Expect(path + ".2").To(BeAnExistingFile())
+		Expect(path + ".3").ToNot(BeAnExistingFile())
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(content)).To(BeNumerically("<=", 200))
+	})
+
+	It("keeps writing after a failed rotation", func() {
+		// Arrange
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		sink, err := audit.NewFileSink(path, 200, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Write(context.Background(), audit.Event{UID: "first", Decision: audit.DecisionAllowed})).To(Succeed())
+		// A non-empty directory in place of the backup makes the rename fail
+		Expect(os.MkdirAll(filepath.Join(path+".1", "blocked"), 0o700)).To(Succeed())
+		Expect(sink.Write(context.Background(), audit.Event{UID: "second", Decision: audit.DecisionAllowed})).ToNot(Succeed())
+		Expect(os.RemoveAll(path + ".1")).To(Succeed())
+		// Act
+		err = sink.Write(context.Background(), audit.Event{UID: "third", Decision: audit.DecisionAllowed})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+		backup, err := os.ReadFile(path + ".1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(backup)).To(ContainSubstring(`"uid":"first"`))
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring(`"uid":"third"`))
+	})
+})
+
+var _ = Describe("NewSinkFromURL", func() {
+	It("posts events to HTTP endpoints", func() {
+		// Arrange
+		received := make(chan audit.Event, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			event := audit.Event{}
+			Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+			received <- event
+		}))
+		defer server.Close()
+		sink, err := audit.NewSinkFromURL(logrus.New(), server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		Expect(sink.Write(context.Background(), audit.Event{UID: "uid", User: "alice", Decision: audit.DecisionDenied})).To(Succeed())
+		Expect(sink.Close()).To(Succeed())
+		// Assert
+		Eventually(received).Should(Receive(HaveField("User", "alice")))
+	})
+
+	It("rejects kafka URLs without topic", func() {
+		// Act
+		_, err := audit.NewSinkFromURL(logrus.New(), "kafka://broker:9092")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects unsupported schemes", func() {
+		// Act
+		_, err := audit.NewSinkFromURL(logrus.New(), "ftp://example.com/audit")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("unsupported audit sink scheme")))
+	})
+})
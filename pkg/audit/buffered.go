@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BufferedSink decouples admission handling from the audit sink. Events are written
+// by a background worker, when the buffer is full events are dropped and logged.
+type BufferedSink struct {
+	log    *logrus.Logger
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewBufferedSink wraps the sink with a buffer of the given size
+func NewBufferedSink(log *logrus.Logger, sink Sink, size int) *BufferedSink {
+	if log == nil {
+		log = logrus.New()
+	}
+	b := &BufferedSink{
+		log:    log,
+		sink:   sink,
+		events: make(chan Event, size),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Write queues the event, it never blocks
+func (b *BufferedSink) Write(_ context.Context, event Event) error {
+	select {
+	case b.events <- event:
+	default:
+		b.log.Warnf("Audit buffer full, dropping audit event for %s/%s (uid %s)", event.Namespace, event.Name, event.UID)
+	}
+	return nil
+}
+
+// Close writes all queued events and closes the underlying sink
+func (b *BufferedSink) Close() error {
+	b.once.Do(func() {
+		close(b.events)
+	})
+	<-b.done
+	return b.sink.Close()
+}
+
+func (b *BufferedSink) run() {
+	defer close(b.done)
+	for event := range b.events {
+		if err := b.sink.Write(context.Background(), event); err != nil {
+			b.log.Errorf("Failed to write audit event for %s/%s (uid %s): %v", event.Namespace, event.Name, event.UID, err)
+		}
+	}
+}
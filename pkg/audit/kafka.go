@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink produces every audit event as JSON message to a Kafka topic,
+// keyed by the admission request UID
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink creates a sink producing to the topic on the given brokers
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+// Write produces the event to the topic
+func (k *KafkaSink) Write(ctx context.Context, event Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	if err := k.writer.WriteMessages(ctx, kafka.Message{Key: []byte(event.UID), Value: value}); err != nil {
+		return fmt.Errorf("failed to produce audit event: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the producer
+func (k *KafkaSink) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,319 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bundle snapshots the CRDs of a backend into versioned schema bundles for reproducible validation
+// across environments. A bundle is a gzipped tarball with a manifest.json and a file per CRD below crds/.
+// The manifest lists the digest of every CRD and the digest of the whole set, which only depends on the
+// CRDs, not on the version or creation time of the bundle, so backends and webhooks can be pinned to it.
+//
+// Digests are computed over the canonical form of the CRDs, see Canonical, so a CRD read from a file and the
+// same CRD served by the API server have the same digest.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion identifies the format of the manifest
+	APIVersion = "bundle.ccrn.cloud/v1"
+
+	// ManifestFile is the name of the manifest in the tarball
+	ManifestFile = "manifest.json"
+
+	// CRDDirectory is the directory of the CRD files in the tarball
+	CRDDirectory = "crds/"
+
+	// MaxBytes limits the uncompressed size of bundles read by Read
+	MaxBytes = 32 << 20
+
+	// annotationPrefix is the prefix of the CRD annotations read by the backends
+	annotationPrefix = "ccrn/"
+)
+
+// ErrDigestMismatch is returned when CRDs do not have the digest they are pinned to
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	APIVersion string    `json:"apiVersion"`
+	Version    string    `json:"version,omitempty"` // Version of the bundle chosen by its creator, e.g. 1.4.0
+	Created    time.Time `json:"created,omitzero"`
+	Source     string    `json:"source,omitempty"` // Backend the CRDs were snapshotted from
+	Digest     string    `json:"digest"`           // Digest of the CRD set, see Digest
+	CRDs       []Entry   `json:"crds"`
+}
+
+// Entry describes a CRD of a bundle
+type Entry struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Digest string `json:"digest"` // Digest of the canonical CRD
+}
+
+// Bundle is a versioned snapshot of a CRD set
+type Bundle struct {
+	Manifest Manifest
+	CRDs     []*apiextensionsv1.CustomResourceDefinition // Canonical CRDs sorted by name
+}
+
+// New creates a bundle of the CRDs, the CRDs are stored in their canonical form
+func New(version, source string, crds []*apiextensionsv1.CustomResourceDefinition) (*Bundle, error) {
+	b := &Bundle{Manifest: Manifest{
+		APIVersion: APIVersion,
+		Version:    version,
+		Created:    time.Now().UTC().Truncate(time.Second),
+		Source:     source,
+	}}
+	for _, crd := range crds {
+		b.CRDs = append(b.CRDs, Canonical(crd))
+	}
+	sort.Slice(b.CRDs, func(i, j int) bool { return b.CRDs[i].Name < b.CRDs[j].Name })
+
+	for i, crd := range b.CRDs {
+		if i > 0 && b.CRDs[i-1].Name == crd.Name {
+			return nil, fmt.Errorf("CRD %s is contained twice", crd.Name)
+		}
+		digest, err := crdDigest(crd)
+		if err != nil {
+			return nil, err
+		}
+		b.Manifest.CRDs = append(b.Manifest.CRDs, Entry{Name: crd.Name, File: CRDDirectory + crd.Name + ".yaml", Digest: digest})
+	}
+	b.Manifest.Digest = setDigest(b.Manifest.CRDs)
+	return b, nil
+}
+
+// Canonical returns the part of the CRD that determines validation: the name, the annotations read by the
+// backends (ccrn/...) and the spec with the defaults of the API server applied. Other metadata and the
+// status are dropped.
+func Canonical(crd *apiextensionsv1.CustomResourceDefinition) *apiextensionsv1.CustomResourceDefinition {
+	canonical := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: crd.Name},
+		Spec:       *crd.Spec.DeepCopy(),
+	}
+	for key, value := range crd.Annotations {
+		if strings.HasPrefix(key, annotationPrefix) {
+			if canonical.Annotations == nil {
+				canonical.Annotations = map[string]string{}
+			}
+			canonical.Annotations[key] = value
+		}
+	}
+	apiextensionsv1.SetObjectDefaults_CustomResourceDefinition(canonical)
+	return canonical
+}
+
+// Digest returns the digest of the CRD set, "sha256:<hex>". It is computed over the names and digests of the
+// canonical CRDs sorted by name, so it does not depend on the order of the CRDs or their source.
+func Digest(crds []*apiextensionsv1.CustomResourceDefinition) (string, error) {
+	entries := make([]Entry, 0, len(crds))
+	for _, crd := range crds {
+		digest, err := crdDigest(Canonical(crd))
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, Entry{Name: crd.Name, Digest: digest})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return setDigest(entries), nil
+}
+
+// Verify returns ErrDigestMismatch if the digest of the CRD set is not the pinned one
+func Verify(crds []*apiextensionsv1.CustomResourceDefinition, pinned string) error {
+	digest, err := Digest(crds)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(digest, pinned) {
+		return fmt.Errorf("%w: CRDs have digest %s, pinned is %s", ErrDigestMismatch, digest, pinned)
+	}
+	return nil
+}
+
+// IsBundle reports whether the path or URL names a bundle by its extension, .tar.gz or .tgz
+func IsBundle(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// crdDigest returns the digest of the JSON encoding of the canonical CRD
+func crdDigest(crd *apiextensionsv1.CustomResourceDefinition) (string, error) {
+	data, err := json.Marshal(crd)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode CRD %s: %w", crd.Name, err)
+	}
+	return sha256Digest(data), nil
+}
+
+// setDigest returns the digest of the entries sorted by name
+func setDigest(entries []Entry) string {
+	var content bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&content, "%s %s\n", entry.Name, entry.Digest)
+	}
+	return sha256Digest(content.Bytes())
+}
+
+// sha256Digest returns the digest "sha256:<hex>" of the data
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// YAML returns the CRDs as multi-document YAML, as loaded by validation.FilesystemBackend
+func (b *Bundle) YAML() ([]byte, error) {
+	var content bytes.Buffer
+	for _, crd := range b.CRDs {
+		data, err := yaml.Marshal(crd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode CRD %s: %w", crd.Name, err)
+		}
+		content.WriteString("---\n")
+		content.Write(data)
+	}
+	return content.Bytes(), nil
+}
+
+// tarFile is a file of the bundle tarball
+type tarFile struct {
+	name    string
+	content []byte
+}
+
+// Write writes the bundle as gzipped tarball. The files are written in a stable order with the creation
+// time of the bundle, so writing the same bundle twice gives the same bytes.
+func (b *Bundle) Write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	files := []tarFile{{ManifestFile, append(manifest, '\n')}}
+	for i, crd := range b.CRDs {
+		data, err := yaml.Marshal(crd)
+		if err != nil {
+			return fmt.Errorf("failed to encode CRD %s: %w", crd.Name, err)
+		}
+		files = append(files, tarFile{b.Manifest.CRDs[i].File, data})
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:     file.name,
+			Mode:     0o644,
+			Size:     int64(len(file.content)),
+			ModTime:  b.Manifest.Created,
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+		if _, err := tw.Write(file.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// Read reads a gzipped bundle tarball and verifies the digests of its CRDs and the digest of the set against
+// the manifest. Files not listed in the manifest and bundles exceeding MaxBytes are rejected.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	defer gz.Close()
+	limited := &io.LimitedReader{R: gz, N: MaxBytes + 1}
+	tr := tar.NewReader(limited)
+
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if limited.N <= 0 {
+			return nil, fmt.Errorf("bundle exceeds %d bytes", MaxBytes)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+
+	content, found := files[ManifestFile]
+	if !found {
+		return nil, fmt.Errorf("invalid bundle: %s not found", ManifestFile)
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(content, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if b.Manifest.APIVersion != APIVersion {
+		return nil, fmt.Errorf("unsupported bundle apiVersion %q, expected %s", b.Manifest.APIVersion, APIVersion)
+	}
+	delete(files, ManifestFile)
+
+	for _, entry := range b.Manifest.CRDs {
+		content, found := files[path.Clean(entry.File)]
+		if !found {
+			return nil, fmt.Errorf("invalid bundle: %s of CRD %s not found", entry.File, entry.Name)
+		}
+		delete(files, path.Clean(entry.File))
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.UnmarshalStrict(content, crd); err != nil {
+			return nil, fmt.Errorf("invalid bundle: %s: %w", entry.File, err)
+		}
+		if crd.Name != entry.Name {
+			return nil, fmt.Errorf("invalid bundle: %s contains CRD %s instead of %s", entry.File, crd.Name, entry.Name)
+		}
+		canonical := Canonical(crd)
+		digest, err := crdDigest(canonical)
+		if err != nil {
+			return nil, err
+		}
+		if digest != entry.Digest {
+			return nil, fmt.Errorf("%w: CRD %s has digest %s, manifest lists %s", ErrDigestMismatch, entry.Name, digest, entry.Digest)
+		}
+		b.CRDs = append(b.CRDs, canonical)
+	}
+	for name := range files {
+		return nil, fmt.Errorf("invalid bundle: %s is not listed in the manifest", name)
+	}
+
+	entries := append([]Entry(nil), b.Manifest.CRDs...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	if digest := setDigest(entries); digest != b.Manifest.Digest {
+		return nil, fmt.Errorf("%w: CRDs have digest %s, manifest lists %s", ErrDigestMismatch, digest, b.Manifest.Digest)
+	}
+	sort.Slice(b.CRDs, func(i, j int) bool { return b.CRDs[i].Name < b.CRDs[j].Name })
+	return b, nil
+}
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+)
+
+func TestBundle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bundle Suite")
+}
+
+// testpodCRD returns the testpod CRD shared with the validation tests
+func testpodCRD() *apiextensionsv1.CustomResourceDefinition {
+	content, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))
+	Expect(err).ToNot(HaveOccurred())
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(yaml.Unmarshal(content, crd)).To(Succeed())
+	return crd
+}
+
+// writeTarball writes the files as gzipped tarball
+func writeTarball(files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+// readTarball returns the files of a gzipped tarball
+func readTarball(data []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	Expect(err).ToNot(HaveOccurred())
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		var content bytes.Buffer
+		_, err = content.ReadFrom(tr)
+		Expect(err).ToNot(HaveOccurred())
+		files[header.Name] = content.String()
+	}
+	return files
+}
+
+var _ = Describe("Bundle", func() {
+	It("writes bundles which are read back and verified", func() {
+		// Arrange
+		b, err := bundle.New("1.4.0", "file://crds", []*apiextensionsv1.CustomResourceDefinition{testpodCRD()})
+		Expect(err).ToNot(HaveOccurred())
+		var buf bytes.Buffer
+		// Act
+		Expect(b.Write(&buf)).To(Succeed())
+		read, err := bundle.Read(&buf)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read.Manifest).To(Equal(b.Manifest))
+		Expect(read.Manifest.APIVersion).To(Equal(bundle.APIVersion))
+		Expect(read.Manifest.CRDs).To(ConsistOf(HaveField("File", "crds/pod.k8s-registry.tr.ccrn.example.com.yaml")))
+		Expect(read.CRDs).To(Equal(b.CRDs))
+		digest, err := bundle.Digest(read.CRDs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(digest).To(Equal(b.Manifest.Digest))
+	})
+
+	It("writes the same bytes for the same bundle", func() {
+		// Arrange
+		b, err := bundle.New("1.4.0", "", []*apiextensionsv1.CustomResourceDefinition{testpodCRD()})
+		Expect(err).ToNot(HaveOccurred())
+		var first, second bytes.Buffer
+		// Act
+		Expect(b.Write(&first)).To(Succeed())
+		Expect(b.Write(&second)).To(Succeed())
+		// Assert
+		Expect(first.Bytes()).To(Equal(second.Bytes()))
+	})
+
+	It("rejects CRDs contained twice", func() {
+		// Act
+		_, err := bundle.New("", "", []*apiextensionsv1.CustomResourceDefinition{testpodCRD(), testpodCRD()})
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("CRD pod.k8s-registry.tr.ccrn.example.com is contained twice")))
+	})
+
+	Context("reading", func() {
+		var files map[string]string
+
+		BeforeEach(func() {
+			b, err := bundle.New("1.4.0", "", []*apiextensionsv1.CustomResourceDefinition{testpodCRD()})
+			Expect(err).ToNot(HaveOccurred())
+			var buf bytes.Buffer
+			Expect(b.Write(&buf)).To(Succeed())
+			files = readTarball(buf.Bytes())
+		})
+
+		It("rejects changed CRDs", func() {
+			// Arrange
+			file := "crds/pod.k8s-registry.tr.ccrn.example.com.yaml"
+			files[file] = string(bytes.Replace([]byte(files[file]), []byte("eu-de-2"), []byte("eu-de-9"), 1))
+			// Act
+			_, err := bundle.Read(bytes.NewReader(writeTarball(files)))
+			// Assert
+			Expect(err).To(MatchError(bundle.ErrDigestMismatch))
+			Expect(err).To(MatchError(ContainSubstring("CRD pod.k8s-registry.tr.ccrn.example.com has digest")))
+		})
+
+		It("rejects files not listed in the manifest", func() {
+			// Arrange
+			files["crds/other.yaml"] = "{}"
+			// Act
+			_, err := bundle.Read(bytes.NewReader(writeTarball(files)))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("crds/other.yaml is not listed in the manifest")))
+		})
+
+		It("rejects missing CRD files", func() {
+			// Arrange
+			delete(files, "crds/pod.k8s-registry.tr.ccrn.example.com.yaml")
+			// Act
+			_, err := bundle.Read(bytes.NewReader(writeTarball(files)))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("crds/pod.k8s-registry.tr.ccrn.example.com.yaml of CRD pod.k8s-registry.tr.ccrn.example.com not found")))
+		})
+
+		It("rejects tarballs without manifest", func() {
+			// Arrange
+			delete(files, bundle.ManifestFile)
+			// Act
+			_, err := bundle.Read(bytes.NewReader(writeTarball(files)))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("manifest.json not found")))
+		})
+
+		It("rejects data that is no gzipped tarball", func() {
+			// Act
+			_, err := bundle.Read(bytes.NewReader([]byte("apiVersion: v1")))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("invalid bundle")))
+		})
+	})
+})
+
+var _ = Describe("Digest", func() {
+	It("ignores metadata, status and defaults not affecting validation", func() {
+		// Arrange
+		crd := testpodCRD()
+		served := crd.DeepCopy()
+		served.UID = "1234"
+		served.ResourceVersion = "42"
+		served.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = "{}"
+		served.Status.StoredVersions = []string{"v1"}
+		apiextensionsv1.SetObjectDefaults_CustomResourceDefinition(served)
+		// Act
+		digest, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{crd})
+		Expect(err).ToNot(HaveOccurred())
+		servedDigest, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{served})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(servedDigest).To(Equal(digest))
+		Expect(digest).To(MatchRegexp(`^sha256:[0-9a-f]{64}$`))
+	})
+
+	DescribeTable("changes with the CRDs",
+		func(change func(crd *apiextensionsv1.CustomResourceDefinition)) {
+			// Arrange
+			crd := testpodCRD()
+			changed := crd.DeepCopy()
+			change(changed)
+			// Act
+			digest, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{crd})
+			Expect(err).ToNot(HaveOccurred())
+			changedDigest, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{changed})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changedDigest).ToNot(Equal(digest))
+		},
+		Entry("with a changed schema", func(crd *apiextensionsv1.CustomResourceDefinition) {
+			crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Description = "changed"
+		}),
+		Entry("with a changed URN template", func(crd *apiextensionsv1.CustomResourceDefinition) {
+			crd.Annotations["ccrn/v1.urn-template"] = "urn:ccrn:<ccrn>/<name>"
+		}),
+		Entry("with another version served", func(crd *apiextensionsv1.CustomResourceDefinition) {
+			crd.Spec.Versions[0].Served = !crd.Spec.Versions[0].Served
+		}),
+	)
+
+	It("does not depend on the order of the CRDs", func() {
+		// Arrange
+		pod := testpodCRD()
+		other := testpodCRD()
+		other.Name = "other.k8s-registry.tr.ccrn.example.com"
+		// Act
+		digest, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{pod, other})
+		Expect(err).ToNot(HaveOccurred())
+		reversed, err := bundle.Digest([]*apiextensionsv1.CustomResourceDefinition{other, pod})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reversed).To(Equal(digest))
+	})
+
+	It("verifies CRDs against a pinned digest", func() {
+		// Arrange
+		crds := []*apiextensionsv1.CustomResourceDefinition{testpodCRD()}
+		digest, err := bundle.Digest(crds)
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(bundle.Verify(crds, digest)).To(Succeed())
+		Expect(bundle.Verify(crds, "sha256:0")).To(MatchError(bundle.ErrDigestMismatch))
+	})
+
+	It("recognizes bundles by their extension", func() {
+		// Act & Assert
+		Expect(bundle.IsBundle("crds-1.4.0.tgz")).To(BeTrue())
+		Expect(bundle.IsBundle("/crds/crds.tar.gz")).To(BeTrue())
+		Expect(bundle.IsBundle("crds.yaml")).To(BeFalse())
+	})
+})
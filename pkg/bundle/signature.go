@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SignatureSuffix is appended to the path or URL of a bundle to find its signature, like cosign sign-blob
+// --output-signature <bundle>.sig writes it
+const SignatureSuffix = ".sig"
+
+// ErrInvalidSignature is returned when a bundle is not signed by the trusted key
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// LoadPublicKey reads a PEM encoded public key, e.g. the cosign.pub written by cosign generate-key-pair
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA, Ed25519 or RSA public key in PKIX format
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// VerifySignature verifies the signature of the content with the key. The signature is base64 encoded as
+// written by cosign sign-blob: ASN.1 ECDSA or PKCS #1 v1.5 RSA signatures of the SHA-256 digest of the
+// content, or Ed25519 signatures of the content itself.
+func VerifySignature(key crypto.PublicKey, content, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64 encoded: %v", ErrInvalidSignature, err)
+	}
+	digest := sha256.Sum256(content)
+	valid := false
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], sig)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, content, sig)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	if !valid {
+		return fmt.Errorf("%w: the bundle is not signed by the trusted key or was modified", ErrInvalidSignature)
+	}
+	return nil
+}
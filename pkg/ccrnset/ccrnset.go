@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ccrnset implements set operations over collections of CCRNs, e.g. for quota and
+// access-scope tooling. Every element is treated as a pattern describing a set of resources:
+// a field with the value "*" matches any value, a field that is not set matches any value as well,
+// and the version of the resource type may be "*" to match all versions.
+package ccrnset
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Wildcard matches any value of a field or any version of a resource type
+const Wildcard = apis.Wildcard
+
+// Set is an immutable collection of CCRN patterns without duplicates or redundant elements
+type Set struct {
+	items []*apis.ParsedResource
+}
+
+// New parses the CCRNs and returns a set containing them
+func New(ccrns ...string) (*Set, error) {
+	items := make([]*apis.ParsedResource, 0, len(ccrns))
+	for _, ccrn := range ccrns {
+		parsed, err := apis.ParseCCRN(ccrn)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parsed)
+	}
+	return FromParsed(items...), nil
+}
+
+// FromParsed returns a set containing the parsed resources, elements covered by other
+// elements are dropped
+func FromParsed(items ...*apis.ParsedResource) *Set {
+	s := &Set{}
+	for _, item := range items {
+		s.add(item)
+	}
+	s.sort()
+	return s
+}
+
+// Len returns the number of elements of the set
+func (s *Set) Len() int {
+	return len(s.items)
+}
+
+// Items returns the elements of the set sorted by their canonical CCRN
+func (s *Set) Items() []*apis.ParsedResource {
+	return append([]*apis.ParsedResource(nil), s.items...)
+}
+
+// Strings returns the canonical CCRNs of the elements in sorted order
+func (s *Set) Strings() []string {
+	ccrns := make([]string, 0, len(s.items))
+	for _, item := range s.items {
+		ccrns = append(ccrns, item.CanonicalCCRN())
+	}
+	return ccrns
+}
+
+// Contains reports whether the resource is matched by any element of the set
+func (s *Set) Contains(resource *apis.ParsedResource) bool {
+	for _, item := range s.items {
+		if Covers(item, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns a set with all resources matched by a or b
+func Union(a, b *Set) *Set {
+	return FromParsed(append(a.Items(), b.items...)...)
+}
+
+// Intersect returns a set with all resources matched by both a and b
+func Intersect(a, b *Set) *Set {
+	var items []*apis.ParsedResource
+	for _, x := range a.items {
+		for _, y := range b.items {
+			if intersection, ok := intersect(x, y); ok {
+				items = append(items, intersection)
+			}
+		}
+	}
+	return FromParsed(items...)
+}
+
+// Subset reports whether every element of a is covered by an element of b. An element of a
+// that is only covered by several elements of b together is not detected.
+func Subset(a, b *Set) bool {
+	for _, item := range a.items {
+		if !b.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Covers reports whether every resource matched by resource is also matched by pattern
+func Covers(pattern, resource *apis.ParsedResource) bool {
+	if pattern == nil || resource == nil {
+		return false
+	}
+	if !typeCovers(pattern.CCRNKey(), resource.CCRNKey()) {
+		return false
+	}
+	for key, value := range pattern.Fields {
+		if key == "ccrn" || value == Wildcard {
+			continue
+		}
+		if resource.Fields[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// add inserts the item unless it is covered by an existing element and removes elements it covers
+func (s *Set) add(item *apis.ParsedResource) {
+	if item == nil || s.Contains(item) {
+		return
+	}
+	kept := s.items[:0]
+	for _, existing := range s.items {
+		if !Covers(item, existing) {
+			kept = append(kept, existing)
+		}
+	}
+	s.items = append(kept, item)
+}
+
+// sort orders the elements by their canonical CCRN
+func (s *Set) sort() {
+	sort.Slice(s.items, func(i, j int) bool {
+		return s.items[i].CanonicalCCRN() < s.items[j].CanonicalCCRN()
+	})
+}
+
+// intersect returns the pattern matching all resources matched by both x and y, if any
+func intersect(x, y *apis.ParsedResource) (*apis.ParsedResource, bool) {
+	key, ok := intersectType(x.CCRNKey(), y.CCRNKey())
+	if !ok {
+		return nil, false
+	}
+	fields := map[string]string{"ccrn": key}
+	for _, item := range []*apis.ParsedResource{x, y} {
+		for field, value := range item.Fields {
+			if field == "ccrn" {
+				continue
+			}
+			current, exists := fields[field]
+			switch {
+			case !exists || current == Wildcard:
+				fields[field] = value
+			case value != Wildcard && value != current:
+				return nil, false
+			}
+		}
+	}
+	return &apis.ParsedResource{Format: "CCRN", Fields: fields}, true
+}
+
+// typeCovers reports whether the type/version key of a pattern covers the key of a resource
+func typeCovers(pattern, resource string) bool {
+	patternType, patternVersion := splitKey(pattern)
+	resourceType, resourceVersion := splitKey(resource)
+	if patternType != resourceType {
+		return false
+	}
+	return patternVersion == Wildcard || patternVersion == resourceVersion
+}
+
+// intersectType returns the more specific of two type/version keys, if they overlap
+func intersectType(x, y string) (string, bool) {
+	switch {
+	case typeCovers(x, y):
+		return y, true
+	case typeCovers(y, x):
+		return x, true
+	}
+	return "", false
+}
+
+// splitKey splits a type/version key, the type is compared case-insensitively
+func splitKey(key string) (string, string) {
+	name, version, _ := strings.Cut(key, "/")
+	return strings.ToLower(name), version
+}
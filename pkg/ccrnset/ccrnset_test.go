@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ccrnset_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/ccrnset"
+)
+
+func TestCCRNSet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CCRNSet Suite")
+}
+
+// mustNew builds a set from CCRNs known to be valid
+func mustNew(ccrns ...string) *ccrnset.Set {
+	set, err := ccrnset.New(ccrns...)
+	Expect(err).ToNot(HaveOccurred())
+	return set
+}
+
+var _ = Describe("Set", func() {
+	It("drops elements covered by wildcards", func() {
+		// Act
+		set := mustNew(
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=foo",
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=*",
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=foo",
+		)
+		// Assert
+		Expect(set.Strings()).To(Equal([]string{"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=*"}))
+	})
+
+	It("fails on invalid CCRNs", func() {
+		// Act
+		_, err := ccrnset.New("ccrn=pod.k8s.ccrn.example.com/v1, name")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("builds the union of two sets", func() {
+		// Arrange
+		a := mustNew("ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1")
+		b := mustNew("ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=foo", "ccrn=pod.k8s.ccrn.example.com/v1, cluster=c2")
+		// Act
+		union := ccrnset.Union(a, b)
+		// Assert
+		Expect(union.Strings()).To(Equal([]string{
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1",
+			"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c2",
+		}))
+	})
+
+	It("builds the intersection of two sets", func() {
+		// Arrange
+		a := mustNew("ccrn=pod.k8s.ccrn.example.com/*, cluster=c1, namespace=*")
+		b := mustNew("ccrn=pod.k8s.ccrn.example.com/v1, namespace=shop", "ccrn=pod.k8s.ccrn.example.com/v1, cluster=c2")
+		// Act
+		intersection := ccrnset.Intersect(a, b)
+		// Assert
+		Expect(intersection.Strings()).To(Equal([]string{"ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, namespace=shop"}))
+	})
+
+	It("checks subsets", func() {
+		// Arrange
+		scope := mustNew("ccrn=pod.k8s.ccrn.example.com/*, cluster=c1")
+		requested := mustNew("ccrn=pod.k8s.ccrn.example.com/v1, cluster=c1, name=foo")
+		// Act & Assert
+		Expect(ccrnset.Subset(requested, scope)).To(BeTrue())
+		Expect(ccrnset.Subset(scope, requested)).To(BeFalse())
+	})
+
+	It("does not cover other resource types", func() {
+		// Arrange
+		pods := mustNew("ccrn=pod.k8s.ccrn.example.com/*")
+		nodes := mustNew("ccrn=node.k8s.ccrn.example.com/v1, name=n1")
+		// Act & Assert
+		Expect(ccrnset.Subset(nodes, pods)).To(BeFalse())
+		Expect(ccrnset.Intersect(pods, nodes).Len()).To(BeZero())
+	})
+})
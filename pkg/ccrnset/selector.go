@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ccrnset
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// LabelSelector turns a CCRN pattern into a selector over the labels set by the LabelMutation feature of
+// the webhook, see ParsedResource.Labels. It selects the CCRN objects of all resources the pattern covers:
+// wildcard fields and versions add no requirement. Fields that cannot be represented as label fail.
+func LabelSelector(pattern string) (labels.Selector, error) {
+	parsed, err := apis.ParseCCRN(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return SelectorFor(parsed)
+}
+
+// SelectorFor returns the label selector of a parsed CCRN pattern, see LabelSelector
+func SelectorFor(pattern *apis.ParsedResource) (labels.Selector, error) {
+	selector := labels.NewSelector()
+	add := func(key, value string) error {
+		requirement, err := labels.NewRequirement(key, selection.Equals, []string{value})
+		if err != nil {
+			return apis.Errorf(apis.ErrUnsupportedType, "cannot select %s=%s by label: %w", key, value, err)
+		}
+		selector = selector.Add(*requirement)
+		return nil
+	}
+
+	if err := add(apis.LabelGroup, strings.ToLower(pattern.ApiGroup())); err != nil {
+		return nil, err
+	}
+	if err := add(apis.LabelKind, strings.ToLower(pattern.GetKind())); err != nil {
+		return nil, err
+	}
+	if version := pattern.Version(); version != Wildcard {
+		if err := add(apis.LabelVersion, version); err != nil {
+			return nil, err
+		}
+	}
+	for field, value := range pattern.Fields {
+		if field == "ccrn" || value == Wildcard {
+			continue
+		}
+		key, ok := apis.FieldLabel(field)
+		if !ok {
+			return nil, apis.Errorf(apis.ErrUnsupportedType, "cannot select field %s by label", field)
+		}
+		if _, isList := apis.ListValue(value); isList {
+			return nil, apis.Errorf(apis.ErrUnsupportedType, "cannot select list value of field %s by label", field)
+		}
+		if err := add(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return selector, nil
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ccrnset_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/ccrnset"
+)
+
+var _ = Describe("LabelSelector", func() {
+	labelsOf := func(ccrn string) labels.Set {
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed.Labels()
+	}
+
+	It("selects type, version and fields", func() {
+		// Act
+		selector, err := ccrnset.LabelSelector("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selector.String()).To(Equal("ccrn/field.cluster=eu-de-1,ccrn/group=k8s.ccrn.example.com,ccrn/kind=pod,ccrn/version=v1"))
+	})
+
+	It("adds no requirement for wildcards", func() {
+		// Act
+		selector, err := ccrnset.LabelSelector("ccrn=pod.k8s.ccrn.example.com/*, cluster=*, namespace=shop")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selector.String()).To(Equal("ccrn/field.namespace=shop,ccrn/group=k8s.ccrn.example.com,ccrn/kind=pod"))
+	})
+
+	It("matches the labels of the resources covered by the pattern", func() {
+		// Arrange
+		selector, err := ccrnset.LabelSelector("ccrn=pod.k8s.ccrn.example.com/*, cluster=eu-de-1")
+		Expect(err).ToNot(HaveOccurred())
+		// Act & Assert
+		Expect(selector.Matches(labelsOf("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo"))).To(BeTrue())
+		Expect(selector.Matches(labelsOf("ccrn=pod.k8s.ccrn.example.com/v2, cluster=eu-de-1, name=bar"))).To(BeTrue())
+		Expect(selector.Matches(labelsOf("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-2, name=foo"))).To(BeFalse())
+		Expect(selector.Matches(labelsOf("ccrn=node.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=foo"))).To(BeFalse())
+	})
+
+	It("fails for values that cannot be selected by label", func() {
+		// Act
+		_, errValue := ccrnset.LabelSelector(`ccrn=pod.k8s.ccrn.example.com/v1, name="a b"`)
+		_, errList := ccrnset.LabelSelector("ccrn=pod.k8s.ccrn.example.com/v1, zones=[a,b]")
+		// Assert
+		Expect(errValue).To(MatchError(apis.ErrUnsupportedType))
+		Expect(errList).To(MatchError(apis.ErrUnsupportedType))
+	})
+})
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// Secret data keys, the serving pair follows the kubernetes.io/tls conventions
+	CACertKey = "ca.crt" // CA bundle, the current CA first followed by previous CAs until they expire
+	CAKeyKey  = "ca.key"
+)
+
+// Config describes where the self-signed certificates are kept and who consumes them
+type Config struct {
+	Namespace         string        // Namespace of the managed Secret and the webhook Service
+	SecretName        string        // Name of the Secret holding CA and serving certificate
+	ServiceName       string        // Name of the webhook Service, used for the certificate DNS names
+	WebhookConfigName string        // MutatingWebhookConfiguration receiving the CA bundle
+	CAValidity        time.Duration // Lifetime of a generated CA
+	CertValidity      time.Duration // Lifetime of a generated serving certificate
+	RotateBefore      time.Duration // Renew certificates when they expire within this window
+}
+
+// DefaultConfig returns a Config with sensible lifetimes for dev clusters
+func DefaultConfig() Config {
+	return Config{
+		CAValidity:   10 * 365 * 24 * time.Hour,
+		CertValidity: 365 * 24 * time.Hour,
+		RotateBefore: 30 * 24 * time.Hour,
+	}
+}
+
+// Manager bootstraps and rotates a self-signed CA and serving certificate stored in a Secret
+// and keeps the CA bundle of the webhook configuration in sync. It removes the need for
+// cert-manager in development clusters.
+type Manager struct {
+	log    *logrus.Logger
+	client kubernetes.Interface
+	config Config
+
+	mutex   sync.RWMutex
+	current *tls.Certificate
+}
+
+// NewManager creates a new certificate manager
+func NewManager(log *logrus.Logger, client kubernetes.Interface, config Config) *Manager {
+	if log == nil {
+		log = logrus.New()
+	}
+	return &Manager{
+		log:    log,
+		client: client,
+		config: config,
+	}
+}
+
+// Bootstrap makes sure a valid CA and serving certificate exist, loads the serving
+// certificate for use by the TLS listener and injects the CA bundle into the webhook configuration
+func (m *Manager) Bootstrap(ctx context.Context) error {
+	secret, err := m.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.load(secret); err != nil {
+		return err
+	}
+
+	if m.config.WebhookConfigName != "" {
+		if err := m.injectCABundle(ctx, secret.Data[CACertKey]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load makes the serving certificate of the secret the current one
+func (m *Manager) load(secret *corev1.Secret) error {
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("failed to load serving certificate from secret %s/%s: %w", m.config.Namespace, m.config.SecretName, err)
+	}
+
+	m.mutex.Lock()
+	m.current = &cert
+	m.mutex.Unlock()
+	return nil
+}
+
+// GetCertificate returns the current serving certificate, suitable for tls.Config.GetCertificate
+func (m *Manager) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.current == nil {
+		return nil, errors.New("serving certificate not bootstrapped yet")
+	}
+	return m.current, nil
+}
+
+// Run renews the certificates every interval and reloads the serving certificate whenever the Secret
+// changes, e.g. because another replica rotated it, until the context is done
+func (m *Manager) Run(ctx context.Context, interval time.Duration) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(m.client, 0, informers.WithNamespace(m.config.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", m.config.SecretName).String()
+		}))
+	_, err := factory.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    m.reload,
+		UpdateFunc: func(_, obj any) { m.reload(obj) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch certificate secret: %w", err)
+	}
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.Bootstrap(ctx); err != nil {
+				m.log.Errorf("Failed to rotate webhook certificates: %v", err)
+			}
+		}
+	}
+}
+
+// reload loads the serving certificate of a changed Secret
+func (m *Manager) reload(obj any) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Name != m.config.SecretName {
+		return
+	}
+	if err := m.load(secret); err != nil {
+		m.log.Errorf("Failed to reload webhook certificates: %v", err)
+		return
+	}
+	m.log.Debugf("Loaded serving certificate of secret %s/%s", m.config.Namespace, m.config.SecretName)
+}
+
+// ensureSecret returns the managed Secret, creating or renewing its content when needed
+func (m *Manager) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secrets := m.client.CoreV1().Secrets(m.config.Namespace)
+
+	secret, err := secrets.Get(ctx, m.config.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		m.log.Infof("Certificate secret %s/%s not found, generating a new CA and serving certificate", m.config.Namespace, m.config.SecretName)
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.config.SecretName,
+				Namespace: m.config.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+		if err := m.populate(secret, true); err != nil {
+			return nil, err
+		}
+		created, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Another replica won the race, use its certificates
+			return secrets.Get(ctx, m.config.SecretName, metav1.GetOptions{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create certificate secret: %w", err)
+		}
+		return created, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate secret: %w", err)
+	}
+
+	renewCA := m.expiresSoon(secret.Data[CACertKey])
+	if !renewCA && !m.expiresSoon(secret.Data[corev1.TLSCertKey]) {
+		return secret, nil
+	}
+
+	m.log.Infof("Rotating webhook certificates in secret %s/%s (renew CA: %t)", m.config.Namespace, m.config.SecretName, renewCA)
+	secret = secret.DeepCopy()
+	if err := m.populate(secret, renewCA); err != nil {
+		return nil, err
+	}
+	updated, err := secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		// Another replica rotated at the same time, use its certificates
+		return secrets.Get(ctx, m.config.SecretName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update certificate secret: %w", err)
+	}
+	return updated, nil
+}
+
+// populate fills the secret with a serving certificate and, if requested, a new CA. A renewed CA is
+// prepended to the CA bundle, the previous CAs stay in it until they expire, so serving certificates of
+// replicas that have not reloaded the Secret yet are still trusted.
+func (m *Manager) populate(secret *corev1.Secret, renewCA bool) error {
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	ca := &KeyPair{CertPEM: secret.Data[CACertKey], KeyPEM: secret.Data[CAKeyKey]}
+	if renewCA || len(ca.CertPEM) == 0 || len(ca.KeyPEM) == 0 {
+		previous := ca.CertPEM
+		var err error
+		ca, err = GenerateCA("CCRN Webhook CA", m.config.CAValidity)
+		if err != nil {
+			return err
+		}
+		ca.CertPEM = append(ca.CertPEM, unexpiredCertificates(previous)...)
+	}
+
+	serving, err := GenerateServingCert(ca, ServiceDNSNames(m.config.ServiceName, m.config.Namespace), m.config.CertValidity)
+	if err != nil {
+		return err
+	}
+
+	secret.Data[CACertKey] = ca.CertPEM
+	secret.Data[CAKeyKey] = ca.KeyPEM
+	secret.Data[corev1.TLSCertKey] = serving.CertPEM
+	secret.Data[corev1.TLSPrivateKeyKey] = serving.KeyPEM
+	return nil
+}
+
+// expiresSoon reports whether the certificate is missing, unreadable or within the rotation window
+func (m *Manager) expiresSoon(certPEM []byte) bool {
+	notAfter, err := NotAfter(certPEM)
+	if err != nil {
+		return true
+	}
+	return time.Until(notAfter) < m.config.RotateBefore
+}
+
+// injectCABundle writes the CA bundle into every webhook of the configured MutatingWebhookConfiguration
+func (m *Manager) injectCABundle(ctx context.Context, caBundle []byte) error {
+	webhookConfigs := m.client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+
+	webhookConfig, err := webhookConfigs.Get(ctx, m.config.WebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get webhook configuration %s: %w", m.config.WebhookConfigName, err)
+	}
+
+	changed := false
+	for i := range webhookConfig.Webhooks {
+		if !bytes.Equal(webhookConfig.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := webhookConfigs.Update(ctx, webhookConfig, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update CA bundle of webhook configuration %s: %w", m.config.WebhookConfigName, err)
+	}
+	m.log.Infof("Injected CA bundle into webhook configuration %s", m.config.WebhookConfigName)
+	return nil
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package certs_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/certs"
+)
+
+func TestCerts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certs Suite")
+}
+
+var _ = Describe("Manager", func() {
+	var client *fake.Clientset
+	var config certs.Config
+
+	BeforeEach(func() {
+		client = fake.NewClientset(&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "ccrn-webhook"},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "ccrn.ccrn-system.svc"}},
+		})
+		config = certs.DefaultConfig()
+		config.Namespace = "ccrn-system"
+		config.SecretName = "ccrn-webhook-certs"
+		config.ServiceName = "ccrn"
+		config.WebhookConfigName = "ccrn-webhook"
+	})
+
+	It("generates certificates and injects the CA bundle", func() {
+		// Arrange
+		manager := certs.NewManager(logrus.New(), client, config)
+		// Act
+		err := manager.Bootstrap(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+
+		secret, err := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKey(certs.CACertKey))
+		Expect(secret.Data).To(HaveKey(corev1.TLSCertKey))
+
+		webhookConfig, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), "ccrn-webhook", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(webhookConfig.Webhooks[0].ClientConfig.CABundle).To(Equal(secret.Data[certs.CACertKey]))
+
+		cert, err := manager.GetCertificate(nil)
+		Expect(err).ToNot(HaveOccurred())
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leaf.DNSNames).To(ContainElement("ccrn.ccrn-system.svc"))
+	})
+
+	It("reuses certificates that are still valid", func() {
+		// Arrange
+		manager := certs.NewManager(logrus.New(), client, config)
+		Expect(manager.Bootstrap(context.Background())).To(Succeed())
+		before, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		// Act
+		err := manager.Bootstrap(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		after, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		Expect(after.Data[corev1.TLSCertKey]).To(Equal(before.Data[corev1.TLSCertKey]))
+	})
+
+	It("rotates the serving certificate when it is about to expire", func() {
+		// Arrange
+		config.CertValidity = time.Hour
+		manager := certs.NewManager(logrus.New(), client, config)
+		Expect(manager.Bootstrap(context.Background())).To(Succeed())
+		before, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		// Act
+		err := manager.Bootstrap(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		after, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		Expect(after.Data[corev1.TLSCertKey]).ToNot(Equal(before.Data[corev1.TLSCertKey]))
+		Expect(after.Data[certs.CACertKey]).To(Equal(before.Data[certs.CACertKey]))
+	})
+
+	It("keeps the previous CA in the bundle when renewing the CA", func() {
+		// Arrange
+		config.CAValidity = time.Hour
+		manager := certs.NewManager(logrus.New(), client, config)
+		Expect(manager.Bootstrap(context.Background())).To(Succeed())
+		before, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		// Act
+		err := manager.Bootstrap(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		after, _ := client.CoreV1().Secrets("ccrn-system").Get(context.Background(), "ccrn-webhook-certs", metav1.GetOptions{})
+		Expect(after.Data[certs.CAKeyKey]).ToNot(Equal(before.Data[certs.CAKeyKey]))
+		Expect(bytes.Count(after.Data[certs.CACertKey], []byte("BEGIN CERTIFICATE"))).To(Equal(2))
+		Expect(after.Data[certs.CACertKey]).To(HaveSuffix(string(before.Data[certs.CACertKey])))
+		webhookConfig, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), "ccrn-webhook", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(webhookConfig.Webhooks[0].ClientConfig.CABundle).To(Equal(after.Data[certs.CACertKey]))
+	})
+
+	It("uses the certificates of another replica rotating at the same time", func() {
+		// Arrange
+		config.CertValidity = time.Hour
+		manager := certs.NewManager(logrus.New(), client, config)
+		Expect(manager.Bootstrap(context.Background())).To(Succeed())
+		client.PrependReactor("update", "secrets", func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewConflict(corev1.Resource("secrets"), "ccrn-webhook-certs", errors.New("modified"))
+		})
+		// Act
+		err := manager.Bootstrap(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		_, err = manager.GetCertificate(nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reloads the serving certificate when the secret changes", func() {
+		// Arrange
+		manager := certs.NewManager(logrus.New(), client, config)
+		Expect(manager.Bootstrap(context.Background())).To(Succeed())
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		go func() {
+			defer GinkgoRecover()
+			Expect(manager.Run(ctx, time.Hour)).To(Succeed())
+		}()
+		secret, err := client.CoreV1().Secrets("ccrn-system").Get(ctx, "ccrn-webhook-certs", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		serving, err := certs.GenerateServingCert(&certs.KeyPair{CertPEM: secret.Data[certs.CACertKey], KeyPEM: secret.Data[certs.CAKeyKey]},
+			[]string{"rotated"}, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		secret.Data[corev1.TLSCertKey] = serving.CertPEM
+		secret.Data[corev1.TLSPrivateKeyKey] = serving.KeyPEM
+		// Act
+		_, err = client.CoreV1().Secrets("ccrn-system").Update(ctx, secret, metav1.UpdateOptions{})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() []string {
+			cert, err := manager.GetCertificate(nil)
+			Expect(err).ToNot(HaveOccurred())
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			Expect(err).ToNot(HaveOccurred())
+			return leaf.DNSNames
+		}).Should(Equal([]string{"rotated"}))
+	})
+})
+
+var _ = Describe("GenerateServingCert", func() {
+	It("never outlives its CA", func() {
+		// Arrange
+		ca, err := certs.GenerateCA("test CA", time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		serving, err := certs.GenerateServingCert(ca, []string{"ccrn"}, 24*time.Hour)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		block, _ := pem.Decode(serving.CertPEM)
+		leaf, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		caExpiry, err := certs.NotAfter(ca.CertPEM)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leaf.NotAfter).To(BeTemporally("<=", caExpiry))
+	})
+})
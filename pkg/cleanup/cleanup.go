@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cleanup deletes the resources derived from CCRN resources together with them. The webhook adds the
+// finalizer apis.FinalizerDerivedResources to CCRN resources owning derived resources, the controller deletes
+// the derived resources once the CCRN resource is being deleted and removes the finalizer afterwards.
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// ResyncPeriod is the interval at which all CCRN resources are checked again, failed cleanups are retried with it
+const ResyncPeriod = time.Minute
+
+// GroupVersionResource returns the resource of the CCRNs of the CCRN group
+func GroupVersionResource(ccrnGroup string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "validate." + ccrnGroup, Version: "v1", Resource: "ccrns"}
+}
+
+// Controller deletes the derived resources of deleted CCRN resources with the DerivedResourceManager
+type Controller struct {
+	log     *logrus.Logger
+	client  dynamic.Interface
+	manager apis.DerivedResourceManager
+}
+
+// NewController creates a controller finalizing the CCRN resources read with the client
+func NewController(log *logrus.Logger, client dynamic.Interface, manager apis.DerivedResourceManager) *Controller {
+	return &Controller{log: log, client: client, manager: manager}
+}
+
+// Watch finalizes the CCRN resources of the CCRN group being deleted until the context is done. It returns
+// once the informer is synced, failed cleanups are logged and retried every ResyncPeriod.
+func (c *Controller) Watch(ctx context.Context, ccrnGroup string) error {
+	gvr := GroupVersionResource(ccrnGroup)
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(c.client, ResyncPeriod)
+	informer := factory.ForResource(gvr).Informer()
+
+	finalize := func(obj any) {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		if err := c.Finalize(ctx, gvr, item); err != nil {
+			c.log.Warnf("Failed to finalize %s %s/%s: %v", gvr.GroupResource(), item.GetNamespace(), item.GetName(), err)
+		}
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    finalize,
+		UpdateFunc: func(_, obj any) { finalize(obj) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", gvr.GroupResource(), err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync %s: %w", gvr.GroupResource(), ctx.Err())
+	}
+	return nil
+}
+
+// Finalize deletes the derived resources of the CCRN resource if it is being deleted and then removes
+// apis.FinalizerDerivedResources. Resources without the finalizer are left alone.
+func (c *Controller) Finalize(ctx context.Context, gvr schema.GroupVersionResource, item *unstructured.Unstructured) error {
+	index := slices.Index(item.GetFinalizers(), apis.FinalizerDerivedResources)
+	if item.GetDeletionTimestamp() == nil || index < 0 {
+		return nil
+	}
+
+	kind := item.GetKind()
+	if kind == "" {
+		kind = "CCRN"
+	}
+	owner := metav1.OwnerReference{APIVersion: gvr.GroupVersion().String(), Kind: kind, Name: item.GetName(), UID: item.GetUID()}
+	if err := c.manager.DeleteDerivedResources(c.log.WithFields(logrus.Fields{"resource": gvr.GroupResource().String(), "namespace": item.GetNamespace(), "name": item.GetName()}), item.GetNamespace(), owner); err != nil {
+		return fmt.Errorf("failed to delete derived resources: %w", err)
+	}
+
+	// The test fails the patch if the finalizers changed since the item was read, it is retried with the next event
+	path := fmt.Sprintf("/metadata/finalizers/%d", index)
+	patch, err := json.Marshal([]map[string]any{
+		{"op": "test", "path": path, "value": apis.FinalizerDerivedResources},
+		{"op": "remove", "path": path},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Resource(gvr).Namespace(item.GetNamespace()).Patch(ctx, item.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to remove finalizer %s: %w", apis.FinalizerDerivedResources, err)
+	}
+	c.log.Infof("Deleted the resources derived from %s %s/%s", gvr.GroupResource(), item.GetNamespace(), item.GetName())
+	return nil
+}
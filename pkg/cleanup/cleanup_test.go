@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cleanup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cleanup"
+)
+
+func TestCleanup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cleanup Suite")
+}
+
+// recordingManager is a DerivedResourceManager recording the owners whose derived resources are deleted
+type recordingManager struct {
+	deleted []metav1.OwnerReference
+	err     error
+}
+
+func (m *recordingManager) ValidateOwnedResource(*logrus.Entry, string, *apis.ParsedResource, metav1.OwnerReference) error {
+	return nil
+}
+
+func (m *recordingManager) DeleteDerivedResources(_ *logrus.Entry, _ string, owner metav1.OwnerReference) error {
+	m.deleted = append(m.deleted, owner)
+	return m.err
+}
+
+// ccrnObject returns a CCRN resource with the finalizers, deleted ones carry a deletion timestamp
+func ccrnObject(name string, deleted bool, finalizers ...string) *unstructured.Unstructured {
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "validate.tr.ccrn.example.com/v1",
+		"kind":       "CCRN",
+		"metadata":   map[string]any{"name": name, "namespace": "default", "uid": "uid-" + name},
+	}}
+	item.SetFinalizers(finalizers)
+	if deleted {
+		now := metav1.Now()
+		item.SetDeletionTimestamp(&now)
+	}
+	return item
+}
+
+var _ = Describe("Controller", func() {
+	gvr := cleanup.GroupVersionResource("tr.ccrn.example.com")
+	var (
+		client     *dynamicfake.FakeDynamicClient
+		manager    *recordingManager
+		controller *cleanup.Controller
+	)
+
+	BeforeEach(func() {
+		manager = &recordingManager{}
+		client = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "CCRNList"},
+			ccrnObject("deleted", true, "other", apis.FinalizerDerivedResources),
+			ccrnObject("alive", false, apis.FinalizerDerivedResources),
+			ccrnObject("foreign", true, "other"),
+		)
+		controller = cleanup.NewController(logrus.New(), client, manager)
+	})
+
+	// finalizers returns the finalizers of the stored CCRN resource
+	finalizers := func(name string) []string {
+		item, err := client.Resource(gvr).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return item.GetFinalizers()
+	}
+
+	It("deletes the derived resources of deleted CCRN resources and removes the finalizer", func() {
+		// Arrange
+		item := ccrnObject("deleted", true, "other", apis.FinalizerDerivedResources)
+		// Act
+		err := controller.Finalize(context.Background(), gvr, item)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.deleted).To(ConsistOf(metav1.OwnerReference{
+			APIVersion: "validate.tr.ccrn.example.com/v1", Kind: "CCRN", Name: "deleted", UID: "uid-deleted"}))
+		Expect(finalizers("deleted")).To(Equal([]string{"other"}))
+	})
+
+	It("keeps the finalizer if the derived resources cannot be deleted", func() {
+		// Arrange
+		manager.err = errors.New("forbidden")
+		// Act
+		err := controller.Finalize(context.Background(), gvr, ccrnObject("deleted", true, "other", apis.FinalizerDerivedResources))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("forbidden")))
+		Expect(finalizers("deleted")).To(ContainElement(apis.FinalizerDerivedResources))
+	})
+
+	It("leaves CCRN resources alone that are not deleted or have no derived resources", func() {
+		// Act
+		for _, item := range []*unstructured.Unstructured{ccrnObject("alive", false, apis.FinalizerDerivedResources), ccrnObject("foreign", true, "other")} {
+			Expect(controller.Finalize(context.Background(), gvr, item)).To(Succeed())
+		}
+		// Assert
+		Expect(manager.deleted).To(BeEmpty())
+		Expect(finalizers("alive")).To(Equal([]string{apis.FinalizerDerivedResources}))
+	})
+
+	It("finalizes the CCRN resources being deleted when watching them", func() {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		// Act
+		err := controller.Watch(ctx, "tr.ccrn.example.com")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(func() []string { return finalizers("deleted") }).Should(Equal([]string{"other"}))
+		Expect(finalizers("alive")).To(Equal([]string{apis.FinalizerDerivedResources}))
+	})
+})
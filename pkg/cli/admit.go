@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// admitUser is the user of the synthetic admission requests
+const admitUser = "ccrn-admit"
+
+// admitResult is the decision of the webhook for an object in JSON and YAML output
+type admitResult struct {
+	File      string           `json:"file"`
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Allowed   bool             `json:"allowed"`
+	Message   string           `json:"message,omitempty"`
+	Warnings  []string         `json:"warnings,omitempty"`
+	Patches   []map[string]any `json:"patches,omitempty"` // JSON patch of the mutation, if any
+}
+
+// admitReport is the result of admit in JSON and YAML output
+type admitReport struct {
+	Objects int           `json:"objects"`
+	Allowed int           `json:"allowed"`
+	Denied  int           `json:"denied"`
+	Results []admitResult `json:"results"`
+}
+
+func newAdmitCommand(opts *options) *cobra.Command {
+	var (
+		files     []string
+		namespace string
+		operation string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "admit --file <manifest>...",
+		Short: "Run CCRN resources of local manifests through the admission webhook",
+		Long: `Debug why the webhook mutates or denies a CCRN resource without a cluster: each
+object of the manifests is wrapped into a synthetic AdmissionReview and sent to
+the /validate handler of the webhook, which runs in process with the CRDs of
+--backend. The decision is printed with its message, warnings and the JSON patch
+of the mutation, if any.
+
+The requests are dry runs by the user ` + admitUser + `, the namespace of an object
+defaults to --namespace. Only offline backends (file:// and https://) are
+supported since the Kubernetes backend creates the validated resources.
+The command fails if any object is denied.`,
+		Example: `  ccrn admit --backend file://crds -f ccrn.yaml
+  ccrn admit --backend file://crds -f ccrns.yaml --operation UPDATE -o json
+  kubectl get ccrn web -o yaml | ccrn admit --backend file://crds -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(files) == 0 {
+				return errors.New("no manifests given, use --file")
+			}
+			op := admissionv1.Operation(strings.ToUpper(operation))
+			if op != admissionv1.Create && op != admissionv1.Update {
+				return fmt.Errorf("invalid operation %q, use %s or %s", operation, admissionv1.Create, admissionv1.Update)
+			}
+			var reviews []*admissionv1.AdmissionReview
+			var sources []string
+			for _, file := range files {
+				fileReviews, err := readAdmissionReviews(cmd.InOrStdin(), file, namespace, op)
+				if err != nil {
+					return err
+				}
+				reviews = append(reviews, fileReviews...)
+				for range fileReviews {
+					sources = append(sources, file)
+				}
+			}
+
+			handler, err := opts.newOfflineWebhook(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			report := admitReport{Objects: len(reviews)}
+			for i, review := range reviews {
+				result, err := admit(handler, review)
+				if err != nil {
+					return fmt.Errorf("%s: %s/%s: %w", sources[i], review.Request.Namespace, review.Request.Name, err)
+				}
+				result.File = sources[i]
+				if result.Allowed {
+					report.Allowed++
+				} else {
+					report.Denied++
+				}
+				report.Results = append(report.Results, result)
+			}
+
+			err = opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				for _, result := range report.Results {
+					printAdmitResult(out, result)
+				}
+				fmt.Fprintf(out, "%d objects: %d allowed, %d denied\n", report.Objects, report.Allowed, report.Denied)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Denied > 0 {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "Manifest with the CCRN resources, - reads stdin (repeatable)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of objects without one")
+	cmd.Flags().StringVar(&operation, "operation", string(admissionv1.Create), "Operation of the admission requests, CREATE or UPDATE")
+	return cmd
+}
+
+// newOfflineWebhook returns the handler of a webhook server validating with the backend selected by --backend,
+// which must not be the Kubernetes backend since it creates the validated resources
+func (o *options) newOfflineWebhook(stderr io.Writer) (http.Handler, error) {
+	if u, err := url.Parse(o.backend); err == nil && u.Scheme == "k8s" {
+		return nil, errors.New("only offline backends are supported, use a file:// or https:// backend")
+	}
+	backend, err := o.newBackend(stderr)
+	if err != nil {
+		return nil, err
+	}
+	server, err := webhook.NewWebhookServer(o.newLogger(stderr), backend)
+	if err != nil {
+		return nil, err
+	}
+	return server.Handler(), nil
+}
+
+// readAdmissionReviews returns an AdmissionReview per object of the YAML or JSON manifest, empty documents
+// are skipped
+func readAdmissionReviews(stdin io.Reader, file, namespace string, operation admissionv1.Operation) ([]*admissionv1.AdmissionReview, error) {
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", file, err)
+	}
+
+	var reviews []*admissionv1.AdmissionReview
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		if object.GetNamespace() == "" {
+			object.SetNamespace(namespace)
+		}
+		raw, err := json.Marshal(object.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode object of %s: %w", file, err)
+		}
+		reviews = append(reviews, newAdmissionReview(object, raw, operation, len(reviews)))
+	}
+	return reviews, nil
+}
+
+// newAdmissionReview wraps the object into a dry-run AdmissionReview like the API server sends it, the old
+// object of updates is the object itself
+func newAdmissionReview(object *unstructured.Unstructured, raw []byte, operation admissionv1.Operation, index int) *admissionv1.AdmissionReview {
+	gvk := object.GroupVersionKind()
+	request := &admissionv1.AdmissionRequest{
+		UID:       types.UID(fmt.Sprintf("ccrn-admit-%d", index)),
+		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+		Operation: operation,
+		UserInfo:  authenticationv1.UserInfo{Username: admitUser},
+		Object:    runtime.RawExtension{Raw: raw},
+		DryRun:    ptr.To(true),
+	}
+	if operation == admissionv1.Update {
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request:  request,
+	}
+}
+
+// admit sends the AdmissionReview to the /validate path of the handler and returns the decision
+func admit(handler http.Handler, review *admissionv1.AdmissionReview) (admitResult, error) {
+	body, err := json.Marshal(review)
+	if err != nil {
+		return admitResult{}, fmt.Errorf("failed to encode AdmissionReview: %w", err)
+	}
+	request := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	answered := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), answered); err != nil || answered.Response == nil {
+		return admitResult{}, fmt.Errorf("webhook answered with status %d: %s", recorder.Code, strings.TrimSpace(recorder.Body.String()))
+	}
+	response := answered.Response
+	result := admitResult{
+		Namespace: review.Request.Namespace,
+		Name:      review.Request.Name,
+		Allowed:   response.Allowed,
+		Warnings:  response.Warnings,
+	}
+	if response.Result != nil {
+		result.Message = response.Result.Message
+	}
+	if len(response.Patch) > 0 {
+		if err := json.Unmarshal(response.Patch, &result.Patches); err != nil {
+			return admitResult{}, fmt.Errorf("webhook answered with an invalid patch: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// printAdmitResult prints the decision for an object followed by its warnings and patch operations
+func printAdmitResult(out io.Writer, result admitResult) {
+	decision := "allowed"
+	if !result.Allowed {
+		decision = "denied"
+	}
+	fmt.Fprintf(out, "%s: %s/%s: %s: %s\n", result.File, result.Namespace, result.Name, decision, result.Message)
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "  warning: %s\n", warning)
+	}
+	for _, patch := range result.Patches {
+		value, _ := json.Marshal(patch["value"])
+		fmt.Fprintf(out, "  %s %s %s\n", patch["op"], patch["path"], value)
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("admit", func() {
+	manifest := filepath.Join("testdata", "admit", "ccrns.yaml")
+
+	It("prints the decision and patches of each object", func() {
+		// Act
+		stdout, _, err := run("admit", "--backend", testpodBackend, "-f", manifest)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring(manifest + ": default/web: allowed: CCRN is valid, missing format added"))
+		Expect(stdout).To(ContainSubstring(`  add /spec/urn "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web"`))
+		Expect(stdout).To(ContainSubstring(manifest + ": shop/bad: denied: "))
+		Expect(stdout).To(ContainSubstring("namespace: Required value"))
+		Expect(stdout).To(HaveSuffix("2 objects: 1 allowed, 1 denied\n"))
+	})
+
+	It("reports the decisions as JSON", func() {
+		// Arrange
+		stdin := `{"apiVersion": "validate.tr.ccrn.example.com/v1", "kind": "CCRN", "metadata": {"name": "web"},
+			"spec": {"ccrn": "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=shop, name=web"}}`
+		// Act
+		stdout, _, err := runWithStdin(stdin, "admit", "--backend", testpodBackend, "-f", "-", "-n", "shop", "--operation", "update", "-o", "json")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var report struct {
+			Objects int `json:"objects"`
+			Results []struct {
+				Namespace string           `json:"namespace"`
+				Allowed   bool             `json:"allowed"`
+				Patches   []map[string]any `json:"patches"`
+			} `json:"results"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+		Expect(report.Objects).To(Equal(1))
+		Expect(report.Results[0].Namespace).To(Equal("shop"))
+		Expect(report.Results[0].Allowed).To(BeTrue())
+		Expect(report.Results[0].Patches).To(ContainElement(HaveKeyWithValue("path", "/spec/urn")))
+	})
+
+	DescribeTable("rejects invalid arguments",
+		func(message string, args ...string) {
+			// Act
+			_, _, err := run(append([]string{"admit"}, args...)...)
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			Expect(cli.ExitCode(err)).To(Equal(cli.ExitUsage))
+		},
+		Entry("without manifests", "no manifests given", "--backend", testpodBackend),
+		Entry("with an unknown operation", `invalid operation "DELETE"`, "--backend", testpodBackend, "-f", manifest, "--operation", "DELETE"),
+		Entry("with the Kubernetes backend", "only offline backends are supported", "--backend", "k8s://", "-f", manifest),
+	)
+})
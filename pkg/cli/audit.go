@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/sweep"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func newAuditCommand(opts *options) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		kubeContext   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Re-validate the CCRN resources of a cluster",
+		Long: `List the CCRN resources of the cluster of $KUBECONFIG and re-validate their
+identifiers against the current CRDs, e.g. for periodic compliance sweeps:
+
+  - invalid: spec.ccrn or spec.urn violates the current schema or is missing
+  - duplicate: another resource identifies the same resource
+  - deprecated: the identifier references a deprecated version
+
+The command fails if any resource is invalid or a duplicate, deprecated versions
+are only reported. Use -o json or -o yaml to export the report.
+
+The CRDs are read from the same cluster unless --backend is given.`,
+		Example: `  ccrn audit --all-namespaces
+  ccrn audit -n shop --context prod -o json > audit.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := config.GetConfigWithContext(kubeContext)
+			if err != nil {
+				return fmt.Errorf("failed to get Kubernetes config: %w", err)
+			}
+			if opts.backend == "" {
+				opts.backend = "k8s://" + kubeContext
+			}
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			auditor, err := sweep.NewAuditorForConfig(backend, validation.NewCCRNValidator(backend), restConfig)
+			if err != nil {
+				return &BackendError{Err: err}
+			}
+
+			if allNamespaces {
+				namespace = ""
+			}
+			report, err := auditor.Audit(cmd.Context(), namespace)
+			if err != nil {
+				return &BackendError{Err: err}
+			}
+			err = opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				for _, finding := range report.Findings {
+					fmt.Fprintln(out, finding)
+				}
+				fmt.Fprintf(out, "%d resources audited: %d invalid, %d duplicates, %d deprecated\n",
+					report.Objects, report.Invalid, report.Duplicates, report.Deprecated)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Failed() {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the CCRN resources")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Audit the CCRN resources of all namespaces, including cluster-scoped ones")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "Kubeconfig context of the cluster to audit")
+	return cmd
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("audit", func() {
+	It("takes no arguments", func() {
+		// Act
+		_, _, err := run("audit", "--all-namespaces", "pod")
+		// Assert
+		Expect(err).To(MatchError(`unknown command "pod" for "ccrn audit"`))
+	})
+})
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/generate"
+)
+
+func newBackfillCommand(opts *options) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		cluster       string
+		kubeContext   string
+		set           map[string]string
+		dryRun        bool
+		report        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backfill <resource-type>...",
+		Short: "Annotate the existing objects of a cluster with their CCRNs",
+		Long: `Retrofit identifiers onto brownfield clusters: list the live objects of the
+resource types in the cluster of $KUBECONFIG and annotate each object with its
+CCRN in ` + apis.IdentifierAnnotation + `. Objects that already carry the annotation are kept.
+
+Like generate, the CCRN CRD of a resource type declares the Kubernetes resource of
+the objects (ccrn/source-resource) and where the CCRN fields are found in them
+(ccrn/<version>.object-mapping). Fields the objects do not provide are set with
+--cluster and --set.
+
+--dry-run sends the annotations as server-side dry run, --report only reports the
+objects lacking the annotation without contacting the API server for updates.
+The command fails if a CCRN cannot be derived or written for any object, it can
+run as a Kubernetes Job for periodic back-fills.
+
+The CRDs are read from the same cluster unless --backend is given.`,
+		Example: `  ccrn backfill pod deployment --all-namespaces --cluster eu-de-1 --report
+  ccrn backfill pod -n shop --cluster eu-de-1 --set region=eu-de`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if opts.backend == "" {
+				opts.backend = "k8s://" + kubeContext
+			}
+			return opts.completeResourceTypes(toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun && report {
+				return errors.New("--dry-run cannot be combined with --report")
+			}
+			restConfig, err := config.GetConfigWithContext(kubeContext)
+			if err != nil {
+				return fmt.Errorf("failed to get Kubernetes config: %w", err)
+			}
+			if opts.backend == "" {
+				opts.backend = "k8s://" + kubeContext
+			}
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			generator, err := generate.NewGeneratorForConfig(backend, restConfig)
+			if err != nil {
+				return &BackendError{Err: err}
+			}
+
+			fields := make(map[string]string, len(set)+1)
+			for field, value := range set {
+				fields[field] = value
+			}
+			if cluster != "" {
+				fields["cluster"] = cluster
+			}
+			mode := generate.ModeApply
+			switch {
+			case dryRun:
+				mode = generate.ModeDryRun
+			case report:
+				mode = generate.ModeReport
+			}
+			if allNamespaces {
+				namespace = ""
+			}
+			result, err := generator.Backfill(cmd.Context(), args, namespace, fields, mode)
+			if err != nil {
+				return &BackendError{Err: err}
+			}
+
+			err = opts.write(cmd.OutOrStdout(), result, func(out io.Writer) {
+				for _, object := range result.Results {
+					fmt.Fprintln(out, object)
+				}
+				fmt.Fprintf(out, "%d objects: %d annotated, %d missing, %d existing, %d failed\n",
+					result.Objects, result.Annotated, result.Missing, result.Existing, result.Failed)
+			})
+			if err != nil {
+				return err
+			}
+			if result.Failed > 0 {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the objects")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Annotate the objects of all namespaces, including cluster-scoped ones")
+	cmd.Flags().StringVar(&cluster, "cluster", "", "Value of the cluster field, which cannot be read from the objects")
+	cmd.Flags().StringToStringVar(&set, "set", nil, "Additional <field>=<value> pairs, overriding the values read from the objects")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Send the annotations as server-side dry run without persisting them")
+	cmd.Flags().BoolVar(&report, "report", false, "Only report the objects lacking the annotation")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "Kubeconfig context of the cluster to back-fill")
+	return cmd
+}
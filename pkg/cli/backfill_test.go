@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backfill", func() {
+	It("requires a resource type", func() {
+		// Act
+		_, _, err := run("backfill", "--cluster", "eu-de-1")
+		// Assert
+		Expect(err).To(MatchError("requires at least 1 arg(s), only received 0"))
+	})
+
+	It("rejects dry run and report together", func() {
+		// Act
+		_, _, err := run("backfill", "pod", "--dry-run", "--report")
+		// Assert
+		Expect(err).To(MatchError("--dry-run cannot be combined with --report"))
+	})
+})
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+)
+
+// digestReport is the result of bundle digest in JSON and YAML output
+type digestReport struct {
+	Digest string `json:"digest"`
+	CRDs   int    `json:"crds"`
+}
+
+func newBundleCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Snapshot the CRDs of a backend into versioned schema bundles",
+		Long: `Snapshot the CRDs of the backend into a versioned schema bundle for reproducible
+validation across environments. A bundle is a gzipped tarball with a manifest
+and a file per CRD. The manifest lists the digest of every CRD and the digest of
+the whole CRD set, which only depends on the CRDs.
+
+Bundles are loaded like CRD files, e.g. --backend file://crds-1.4.0.tgz, and the
+digest query parameter pins a backend to a CRD set, e.g.
+--backend file://crds-1.4.0.tgz?digest=sha256:... The webhook is pinned with
+--bundle-digest, and the key query parameter only loads bundles signed with
+cosign sign-blob, e.g. --backend file://crds-1.4.0.tgz?key=cosign.pub.`,
+	}
+	cmd.AddCommand(newBundleCreateCommand(opts), newBundleInspectCommand(opts), newBundleDigestCommand(opts))
+	return cmd
+}
+
+func newBundleCreateCommand(opts *options) *cobra.Command {
+	var version string
+
+	cmd := &cobra.Command{
+		Use:   "create <bundle.tgz>",
+		Short: "Write the CRDs of the backend into a schema bundle",
+		Long: `Write the CRDs of the CCRN groups of the backend into a schema bundle, - writes
+to stdout. The CRDs are stored in their canonical form: the name, the ccrn/
+annotations and the spec with the defaults of the API server. The manifest is
+printed afterwards.`,
+		Example: `  ccrn bundle create --backend k8s://production --version 1.4.0 crds-1.4.0.tgz
+  ccrn bundle create --backend file://crds/ --version "$(git describe)" - > crds.tgz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			crds, err := opts.customResourceDefinitions(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			b, err := bundle.New(version, opts.backend, crds)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if args[0] != "-" {
+				f, err := os.Create(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to create bundle: %w", err)
+				}
+				defer f.Close()
+				if err := b.Write(f); err != nil {
+					return err
+				}
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("failed to write bundle: %w", err)
+				}
+			} else {
+				// The manifest goes to stderr, stdout carries the bundle
+				if err := b.Write(out); err != nil {
+					return err
+				}
+				out = cmd.ErrOrStderr()
+			}
+			return opts.write(out, b.Manifest, func(out io.Writer) {
+				printManifest(out, b.Manifest)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&version, "version", "", "Version of the bundle recorded in its manifest, e.g. 1.4.0")
+	return cmd
+}
+
+func newBundleInspectCommand(opts *options) *cobra.Command {
+	var (
+		digest    string
+		keyFile   string
+		signature string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect <bundle.tgz>",
+		Short: "Verify a schema bundle and print its manifest",
+		Long: `Verify the digests of the CRDs of a schema bundle against its manifest and print
+the manifest, - reads stdin. With --digest, the command fails unless the bundle
+has the digest, e.g. to check a bundle before pinning the webhook to it.
+
+With --key, the command also fails unless the bundle is signed by the public key
+as cosign sign-blob signs it. The signature is read from <bundle.tgz>.sig unless
+--signature names another file.`,
+		Example: `  ccrn bundle inspect crds-1.4.0.tgz
+  ccrn bundle inspect crds-1.4.0.tgz --digest sha256:3b0c... -o json
+  ccrn bundle inspect crds-1.4.0.tgz --key cosign.pub`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var content []byte
+			var err error
+			if args[0] == "-" {
+				content, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				content, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read bundle: %w", err)
+			}
+			if keyFile != "" {
+				if err := verifyBundleSignature(content, args[0], keyFile, signature); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+					return ErrValidationFailed
+				}
+			}
+			b, err := bundle.Read(bytes.NewReader(content))
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			err = opts.write(cmd.OutOrStdout(), b.Manifest, func(out io.Writer) {
+				printManifest(out, b.Manifest)
+			})
+			if err != nil {
+				return err
+			}
+			if digest != "" && !strings.EqualFold(digest, b.Manifest.Digest) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "bundle has digest %s, expected %s\n", b.Manifest.Digest, digest)
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&digest, "digest", "", "Expected digest of the bundle (sha256:...)")
+	cmd.Flags().StringVar(&keyFile, "key", "", "PEM encoded public key the bundle must be signed with, e.g. cosign.pub")
+	cmd.Flags().StringVar(&signature, "signature", "", "Signature of the bundle as written by cosign sign-blob (default <bundle.tgz>.sig)")
+	return cmd
+}
+
+// verifyBundleSignature verifies that the bundle read from file is signed with the key
+func verifyBundleSignature(content []byte, file, keyFile, signatureFile string) error {
+	key, err := bundle.LoadPublicKey(keyFile)
+	if err != nil {
+		return err
+	}
+	if signatureFile == "" {
+		if file == "-" {
+			return errors.New("--signature is required for bundles read from stdin")
+		}
+		signatureFile = file + bundle.SignatureSuffix
+	}
+	signature, err := os.ReadFile(signatureFile)
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	return bundle.VerifySignature(key, content, signature)
+}
+
+func newBundleDigestCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Print the digest of the CRDs of the backend",
+		Long: `Print the digest of the CRDs of the CCRN groups of the backend, which is the
+digest of a bundle created from them. Compare it with the digest of a bundle to
+check whether a cluster runs the CRDs of the bundle.`,
+		Example: `  ccrn bundle digest --backend k8s://production
+  ccrn bundle digest --backend file://crds-1.4.0.tgz -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			crds, err := opts.customResourceDefinitions(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			digest, err := bundle.Digest(crds)
+			if err != nil {
+				return err
+			}
+			report := digestReport{Digest: digest, CRDs: len(crds)}
+			return opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				fmt.Fprintln(out, report.Digest)
+			})
+		},
+	}
+	return cmd
+}
+
+// customResourceDefinitions returns the CRDs of the backend selected by --backend
+func (o *options) customResourceDefinitions(stderr io.Writer) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	backend, err := o.newBackend(stderr)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := backend.(apis.CRDSource)
+	if !ok {
+		return nil, errors.New("the backend cannot list its CRDs")
+	}
+	return source.CustomResourceDefinitions(), nil
+}
+
+// printManifest prints the manifest of a bundle with a line per CRD
+func printManifest(out io.Writer, manifest bundle.Manifest) {
+	fmt.Fprintf(out, "Version: %s\n", manifest.Version)
+	if !manifest.Created.IsZero() {
+		fmt.Fprintf(out, "Created: %s\n", manifest.Created.Format(time.RFC3339))
+	}
+	if manifest.Source != "" {
+		fmt.Fprintf(out, "Source:  %s\n", manifest.Source)
+	}
+	fmt.Fprintf(out, "Digest:  %s\n", manifest.Digest)
+	fmt.Fprintf(out, "CRDs:    %d\n", len(manifest.CRDs))
+	for _, entry := range manifest.CRDs {
+		fmt.Fprintf(out, "  %s %s\n", entry.Name, entry.Digest)
+	}
+}
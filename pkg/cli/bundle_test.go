@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("bundle", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "crds-1.4.0.tgz")
+	})
+
+	// digest returns the digest of the CRDs of the backend
+	digest := func(backend string) string {
+		stdout, _, err := run("bundle", "digest", "--backend", backend, "--ccrn-group", "tr.ccrn.example.com")
+		Expect(err).ToNot(HaveOccurred())
+		return strings.TrimSpace(stdout)
+	}
+
+	It("creates bundles with the digest of the CRDs", func() {
+		// Act
+		stdout, _, err := run("bundle", "create", "--backend", testpodBackend, "--ccrn-group", "tr.ccrn.example.com", "--version", "1.4.0", path)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("Version: 1.4.0\n"))
+		Expect(stdout).To(ContainSubstring("Digest:  " + digest(testpodBackend) + "\n"))
+		Expect(stdout).To(ContainSubstring("CRDs:    1\n  pod.k8s-registry.tr.ccrn.example.com sha256:"))
+		Expect(digest("file://" + path)).To(Equal(digest(testpodBackend)))
+	})
+
+	It("inspects bundles", func() {
+		// Arrange
+		_, _, err := run("bundle", "create", "--backend", testpodBackend, "--ccrn-group", "tr.ccrn.example.com", "--version", "1.4.0", path)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		stdout, _, err := run("bundle", "inspect", path, "--digest", digest(testpodBackend), "-o", "json")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var manifest struct {
+			Version string `json:"version"`
+			Source  string `json:"source"`
+			CRDs    []struct {
+				Name string `json:"name"`
+			} `json:"crds"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &manifest)).To(Succeed())
+		Expect(manifest.Version).To(Equal("1.4.0"))
+		Expect(manifest.Source).To(Equal(testpodBackend))
+		Expect(manifest.CRDs).To(HaveLen(1))
+	})
+
+	It("fails when a bundle does not have the expected digest", func() {
+		// Arrange
+		_, _, err := run("bundle", "create", "--backend", testpodBackend, "--ccrn-group", "tr.ccrn.example.com", path)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		_, stderr, err := run("bundle", "inspect", path, "--digest", "sha256:0")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stderr).To(ContainSubstring("expected sha256:0"))
+	})
+
+	It("fails when the pinned digest of a backend does not match", func() {
+		// Act
+		_, _, err := run("bundle", "digest", "--backend", testpodBackend+"?digest=sha256:0", "--ccrn-group", "tr.ccrn.example.com")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("digest mismatch")))
+		Expect(cli.ExitCode(err)).To(Equal(cli.ExitBackend))
+	})
+
+	It("verifies the signature of bundles", func() {
+		// Arrange
+		_, _, err := run("bundle", "create", "--backend", testpodBackend, "--ccrn-group", "tr.ccrn.example.com", path)
+		Expect(err).ToNot(HaveOccurred())
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalPKIXPublicKey(public)
+		Expect(err).ToNot(HaveOccurred())
+		keyFile := filepath.Join(GinkgoT().TempDir(), "cosign.pub")
+		Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)).To(Succeed())
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, content))
+		Expect(os.WriteFile(path+".sig", []byte(signature), 0o600)).To(Succeed())
+		// Act
+		_, _, err = run("bundle", "inspect", path, "--key", keyFile)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+
+		// Arrange
+		signature = base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte("other content")))
+		Expect(os.WriteFile(path+".sig", []byte(signature), 0o600)).To(Succeed())
+		// Act
+		_, stderr, err := run("bundle", "inspect", path, "--key", keyFile)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stderr).To(ContainSubstring("invalid signature"))
+	})
+
+	It("rejects files that are no bundles", func() {
+		// Act
+		_, _, err := run("bundle", "inspect", filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("invalid bundle")))
+	})
+})
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+func TestCLI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CLI Suite")
+}
+
+// testpodBackend is the --backend URL of the testpod CRD shared with the validation tests
+var testpodBackend = "file://" + filepath.Join("..", "validation", "testdata", "testpod_crd.yaml")
+
+// run executes the ccrn command with the given arguments and returns its stdout, stderr and error
+func run(args ...string) (string, string, error) {
+	return runWithStdin("", args...)
+}
+
+// runWithStdin executes the ccrn command like run, reading stdin from the given string
+func runWithStdin(stdin string, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := cli.NewRootCommand()
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	err := cmd.ExecuteContext(context.Background())
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// completionCRDs returns the CRD versions of the backend for shell completion. Completion must not print
+// anything but candidates, so problems of the backend are not reported and no candidates are offered.
+func (o *options) completionCRDs() ([]*apis.CRDInfo, apis.ValidationBackend) {
+	backend, err := o.newBackend(io.Discard)
+	if err != nil {
+		return nil, nil
+	}
+	lister, ok := backend.(apis.CRDLister)
+	if !ok {
+		return nil, backend
+	}
+	return lister.ListCRDs(), backend
+}
+
+// completeIdentifiers completes CCRNs and URNs: first the resource type after "ccrn=" or "urn:ccrn:",
+// then the fields of the resource type that are not set yet and the allowed values of enum fields.
+// Candidates use "," without spaces as separator, so they need no quoting in the shell.
+func (o *options) completeIdentifiers(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	const directive = cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	crds, backend := o.completionCRDs()
+	if len(crds) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rest, isCCRN := strings.CutPrefix(toComplete, "ccrn=")
+	key, fields, hasFields := strings.Cut(rest, ",")
+	if !isCCRN || !hasFields {
+		var candidates []string
+		for _, crd := range crds {
+			candidates = append(candidates, "ccrn="+crd.CCRNKey()+",", "urn:ccrn:"+crd.CCRNKey()+"/")
+		}
+		return withPrefix(candidates, toComplete), directive
+	}
+
+	crd := findCRD(crds, backend, strings.TrimSpace(key))
+	if crd == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	set := map[string]bool{"ccrn": true}
+	entries := strings.Split(fields, ",")
+	for _, entry := range entries[:len(entries)-1] {
+		name, _, _ := strings.Cut(entry, "=")
+		set[strings.TrimSpace(name)] = true
+	}
+	// done is the text before the field being completed, including the spaces after the last comma
+	last := entries[len(entries)-1]
+	current := strings.TrimLeft(last, " ")
+	done := toComplete[:len(toComplete)-len(current)]
+
+	var candidates []string
+	if name, _, hasValue := strings.Cut(current, "="); hasValue {
+		if field, declared := crd.Field(name); declared {
+			for _, value := range field.Enum {
+				candidates = append(candidates, done+name+"="+value+",")
+			}
+		}
+		return withPrefix(candidates, toComplete), directive
+	}
+	for _, field := range crd.Fields() {
+		switch {
+		case set[field.Name]:
+		case field.Map:
+			candidates = append(candidates, done+field.Name+".")
+		case field.Type != "object":
+			candidates = append(candidates, done+field.Name+"=")
+		}
+	}
+	return withPrefix(candidates, toComplete), directive
+}
+
+// completeResourceTypes completes the resource types "<kind>.<group>" and their aliases
+func (o *options) completeResourceTypes(toComplete string) ([]string, cobra.ShellCompDirective) {
+	crds, _ := o.completionCRDs()
+	var candidates []string
+	for _, crd := range crds {
+		resourceType, _, _ := strings.Cut(crd.CCRNKey(), "/")
+		candidates = append(candidates, resourceType)
+		candidates = append(candidates, crd.Aliases...)
+	}
+	sort.Strings(candidates)
+	return withPrefix(slices.Compact(candidates), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFieldNames completes "<field>=" for the fields of the resource type, e.g. for --set
+func (o *options) completeFieldNames(resourceType, toComplete string) ([]string, cobra.ShellCompDirective) {
+	crds, backend := o.completionCRDs()
+	crd := findCRD(crds, backend, resourceType)
+	if crd == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var candidates []string
+	for _, field := range crd.Fields() {
+		switch {
+		case field.Map:
+			candidates = append(candidates, field.Name+".")
+		case field.Type != "object" && field.Name != "ccrn":
+			candidates = append(candidates, field.Name+"=")
+		}
+	}
+	return withPrefix(candidates, toComplete), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// findCRD returns the CRD version of a CCRN key or resource type, which may use an alias and no or the
+// latest version, nil if it is unknown
+func findCRD(crds []*apis.CRDInfo, backend apis.ValidationBackend, key string) *apis.CRDInfo {
+	var resolvers []apis.AliasResolver
+	if resolver, ok := backend.(apis.AliasResolver); ok {
+		resolvers = append(resolvers, resolver)
+	}
+	resourceType, version, _ := strings.Cut(strings.ToLower(apis.ResolveCCRNKey(key, resolvers...)), "/")
+	if version == "" || version == apis.LatestVersion {
+		if resolver, ok := backend.(apis.VersionResolver); ok {
+			version, _ = resolver.ResolveLatestVersion(resourceType)
+		}
+	}
+	for _, crd := range crds {
+		if crd.CCRNKey() == resourceType+"/"+version {
+			return crd
+		}
+	}
+	return nil
+}
+
+// withPrefix returns the candidates starting with the text to complete
+func withPrefix(candidates []string, toComplete string) []string {
+	var matching []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			matching = append(matching, candidate)
+		}
+	}
+	return matching
+}
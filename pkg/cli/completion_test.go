@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// complete runs the hidden completion command of cobra and returns the candidates
+func complete(args ...string) []string {
+	stdout, _, err := run(append([]string{"__complete"}, args...)...)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	var candidates []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		// The last line is the directive, e.g. ":6"
+		if !strings.HasPrefix(line, ":") {
+			candidates = append(candidates, line)
+		}
+	}
+	return candidates
+}
+
+var _ = Describe("completion", func() {
+	It("completes the resource types of CCRNs and URNs", func() {
+		// Act
+		candidates := complete("validate", "--backend", testpodBackend, "")
+		// Assert
+		Expect(candidates).To(Equal([]string{
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1,",
+			"urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/",
+		}))
+	})
+
+	It("completes the fields that are not set yet", func() {
+		// Act
+		candidates := complete("validate", "--backend", testpodBackend, "ccrn=pod/v1,cluster=eu-de-1, n")
+		// Assert
+		Expect(candidates).To(Equal([]string{
+			"ccrn=pod/v1,cluster=eu-de-1, name=",
+			"ccrn=pod/v1,cluster=eu-de-1, namespace=",
+			"ccrn=pod/v1,cluster=eu-de-1, nodeName=",
+		}))
+	})
+
+	It("completes the values of enum fields", func() {
+		// Act
+		candidates := complete("validate", "--backend", testpodBackend, "ccrn=pod/latest,cluster=eu")
+		// Assert
+		Expect(candidates).To(Equal([]string{
+			"ccrn=pod/latest,cluster=eu-de-1,",
+			"ccrn=pod/latest,cluster=eu-de-2,",
+			"ccrn=pod/latest,cluster=eu-de-3,",
+		}))
+	})
+
+	It("offers no candidates for unknown resource types", func() {
+		// Act
+		candidates := complete("validate", "--backend", testpodBackend, "ccrn=unknown/v1,")
+		// Assert
+		Expect(candidates).To(BeEmpty())
+	})
+
+	It("offers no candidates without backend", func() {
+		// Arrange
+		GinkgoT().Setenv("CCRN_BACKEND", "")
+		// Act
+		candidates := complete("validate", "ccrn=")
+		// Assert
+		Expect(candidates).To(BeEmpty())
+	})
+
+	It("completes resource types and --set fields of generate", func() {
+		// Act
+		types := complete("generate", "--backend", testpodBackend, "po")
+		fields := complete("generate", "--backend", testpodBackend, "pod", "web", "--set", "l")
+		// Assert
+		Expect(types).To(Equal([]string{"po", "pod", "pod.k8s-registry.tr.ccrn.example.com"}))
+		Expect(fields).To(Equal([]string{"labels."}))
+	})
+
+	It("generates completion scripts", func() {
+		// Act
+		stdout, _, err := run("completion", "zsh")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("#compdef ccrn"))
+	})
+})
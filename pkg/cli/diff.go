@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// diffResult is the result of diff in JSON and YAML output
+type diffResult struct {
+	A           string           `json:"a"`
+	B           string           `json:"b"`
+	CCRNA       string           `json:"ccrnA"` // Canonical CCRN of a
+	CCRNB       string           `json:"ccrnB"` // Canonical CCRN of b
+	Equivalent  bool             `json:"equivalent"`
+	Differences []apis.FieldDiff `json:"differences"`
+}
+
+func newDiffCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Compare two CCRNs or URNs field by field",
+		Long: `Parse two CCRNs or URNs, in any combination of formats, and print their fields:
+fields only or differently set in a are prefixed with "-", those of b with "+".
+Resource types are compared case-insensitively, the order and quoting of fields
+and the RFC 8141 components of URNs do not matter.
+
+The command fails if both do not identify the same resource. Parsing URNs requires
+a backend for their URN templates, aliases and the latest version of CCRNs are
+resolved with it if one is configured.`,
+		Example: `  ccrn diff 'ccrn=pod.k8s.ccrn.example.com/v1, name=web, cluster=eu-de-1' 'ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-2, name=web'
+  ccrn diff --backend k8s:// 'ccrn=pod/latest, cluster=eu-de-1, namespace=default, name=web' urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/default/web`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: opts.completeIdentifiers,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var backend apis.ValidationBackend
+			if opts.backend != "" {
+				var err error
+				if backend, err = opts.newBackend(cmd.ErrOrStderr()); err != nil {
+					return err
+				}
+			}
+			resourceParser := parser.NewResourceParser(nil, backend)
+
+			parsed := make([]*apis.ParsedResource, len(args))
+			for i, input := range args {
+				if backend == nil && !strings.HasPrefix(input, "ccrn=") {
+					return errors.New("comparing URNs requires a backend, use --backend or $" + BackendEnv)
+				}
+				var err error
+				if parsed[i], err = resourceParser.ParseContext(cmd.Context(), input, parser.DEFAULT_URN_TEMPLATE); err != nil {
+					return fmt.Errorf("failed to parse %s: %w", input, err)
+				}
+			}
+
+			differences := apis.Diff(parsed[0], parsed[1])
+			result := diffResult{
+				A:           args[0],
+				B:           args[1],
+				CCRNA:       parsed[0].CanonicalCCRN(),
+				CCRNB:       parsed[1].CanonicalCCRN(),
+				Equivalent:  len(differences) == 0,
+				Differences: append([]apis.FieldDiff{}, differences...),
+			}
+			err := opts.write(cmd.OutOrStdout(), result, func(out io.Writer) {
+				printDiff(out, result, parsed[0])
+			})
+			if err != nil {
+				return err
+			}
+			if !result.Equivalent {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+}
+
+// printDiff writes the fields of both resources in unified diff style, unchanged fields are taken from a
+func printDiff(out io.Writer, result diffResult, a *apis.ParsedResource) {
+	fmt.Fprintf(out, "--- a: %s\n+++ b: %s\n", result.A, result.B)
+	differences := make(map[string]apis.FieldDiff, len(result.Differences))
+	for _, diff := range result.Differences {
+		differences[diff.Field] = diff
+	}
+	keys := make([]string, 0, len(a.Fields)+len(differences))
+	for key := range a.Fields {
+		keys = append(keys, key)
+	}
+	for key, diff := range differences {
+		if diff.A == nil {
+			keys = append(keys, key)
+		}
+	}
+	sortFieldNames(keys)
+
+	for _, key := range keys {
+		diff, differs := differences[key]
+		if !differs {
+			fmt.Fprintf(out, "  %s=%s\n", key, apis.QuoteValue(a.Fields[key]))
+			continue
+		}
+		if diff.A != nil {
+			fmt.Fprintf(out, "- %s=%s\n", key, apis.QuoteValue(*diff.A))
+		}
+		if diff.B != nil {
+			fmt.Fprintf(out, "+ %s=%s\n", key, apis.QuoteValue(*diff.B))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("diff", func() {
+	It("prints the differing fields of a CCRN and a URN", func() {
+		// Act
+		stdout, _, err := run("diff", "--backend", testpodBackend,
+			"ccrn=pod/latest, name=web, namespace=default, cluster=eu-de-1",
+			"urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-2/default/web")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(Equal("--- a: ccrn=pod/latest, name=web, namespace=default, cluster=eu-de-1\n" +
+			"+++ b: urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-2/default/web\n" +
+			"  ccrn=pod.k8s-registry.tr.ccrn.example.com/v1\n" +
+			"- cluster=eu-de-1\n" +
+			"+ cluster=eu-de-2\n" +
+			"  name=web\n" +
+			"  namespace=default\n"))
+	})
+
+	It("succeeds for equivalent CCRNs without backend", func() {
+		// Arrange
+		GinkgoT().Setenv(cli.BackendEnv, "")
+		// Act
+		stdout, _, err := run("diff", "-o", "json",
+			"ccrn=Pod.k8s.ccrn.example.com/v1, name=web, zones=[a,b]",
+			`ccrn=pod.k8s.ccrn.example.com/v1,zones=[a,b],name="web"`)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var result map[string]any
+		Expect(json.Unmarshal([]byte(stdout), &result)).To(Succeed())
+		Expect(result).To(HaveKeyWithValue("equivalent", true))
+		Expect(result).To(HaveKeyWithValue("differences", BeEmpty()))
+		Expect(result).To(HaveKeyWithValue("ccrnB", "ccrn=pod.k8s.ccrn.example.com/v1, name=web, zones=[a,b]"))
+	})
+
+	It("reports fields set in only one of both", func() {
+		// Arrange
+		GinkgoT().Setenv(cli.BackendEnv, "")
+		// Act
+		stdout, _, err := run("diff", "ccrn=pod/v1, name=web, zone=a", "ccrn=pod/v1, name=web, region=eu")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(HaveSuffix("  ccrn=pod/v1\n  name=web\n+ region=eu\n- zone=a\n"))
+	})
+
+	It("requires a backend to compare URNs", func() {
+		// Arrange
+		GinkgoT().Setenv(cli.BackendEnv, "")
+		// Act
+		_, _, err := run("diff", "ccrn=pod/v1, name=web", "urn:ccrn:pod/v1/web")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("requires a backend")))
+		Expect(cli.ExitCode(err)).To(Equal(cli.ExitUsage))
+	})
+
+	It("fails for inputs that cannot be parsed", func() {
+		// Act
+		_, _, err := run("diff", "--backend", testpodBackend, "ccrn=pod/v1, name", "ccrn=pod/v1")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to parse ccrn=pod/v1, name")))
+	})
+})
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/docs"
+)
+
+// docsReport is the result of docs in JSON and YAML output
+type docsReport struct {
+	Directory string   `json:"directory"`
+	Files     []string `json:"files"`
+}
+
+func newDocsCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "docs <directory>",
+		Short: "Generate Markdown reference pages of the resource types",
+		Long: `Generate a Markdown reference page per resource type of the backend into the
+directory, which is created if needed: the aliases and versions of the type and,
+per version, the URN template, the fields with their types and constraints and an
+example CCRN and URN. ` + docs.IndexFile + ` links the pages of all resource types.
+Existing pages are overwritten, pages of removed resource types are kept.`,
+		Example: `  ccrn docs --backend ./crds/ ./docs/reference/
+  ccrn docs --backend k8s:// ./reference/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			lister, ok := backend.(apis.CRDLister)
+			if !ok {
+				return errors.New("the backend cannot list its resource types")
+			}
+
+			directory := args[0]
+			if err := os.MkdirAll(directory, 0o755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", directory, err)
+			}
+			report := docsReport{Directory: directory, Files: []string{}}
+			for _, page := range docs.Generate(lister.ListCRDs()) {
+				file := filepath.Join(directory, page.File)
+				if err := os.WriteFile(file, page.Content, 0o644); err != nil {
+					return fmt.Errorf("failed to write file %s: %w", file, err)
+				}
+				report.Files = append(report.Files, file)
+			}
+			return opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				for _, file := range report.Files {
+					fmt.Fprintln(out, file)
+				}
+			})
+		},
+	}
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("docs", func() {
+	It("writes the reference pages into the directory", func() {
+		// Arrange
+		directory := filepath.Join(GinkgoT().TempDir(), "reference")
+		// Act
+		stdout, _, err := run("docs", "--backend", testpodBackend, directory)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		page := filepath.Join(directory, "pod.k8s-registry.tr.ccrn.example.com.md")
+		Expect(stdout).To(Equal(filepath.Join(directory, "README.md") + "\n" + page + "\n"))
+		content, err := os.ReadFile(page)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("URN template: `urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>`\n"))
+		Expect(string(content)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/namespace/name\n"))
+	})
+
+	It("lists the written files in JSON", func() {
+		// Arrange
+		directory := GinkgoT().TempDir()
+		// Act
+		stdout, _, err := run("docs", "--backend", testpodBackend, "-o", "json", directory)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var report struct {
+			Files []string `json:"files"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+		Expect(report.Files).To(HaveLen(2))
+	})
+
+	It("requires a backend", func() {
+		// Act
+		_, _, err := run("docs", GinkgoT().TempDir())
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+func newExportCommand(opts *options) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the schemas of all resource types as JSON Schema or OpenAPI bundle",
+		Long: `Export the schemas of all resource types of the backend as one self-contained
+bundle for editors and third-party validators like ajv. The schemas describe the
+fields of CCRNs as JSON objects, e.g. {"ccrn": "pod.k8s.ccrn.example.com/v1", "name": "web"},
+with the CCRN key as constant ccrn field.
+
+--format jsonschema writes a JSON Schema (draft 2020-12) accepting the fields of
+any resource type version, --format openapi an OpenAPI 3.0 document with the
+schemas as components. The bundle is written as JSON unless -o yaml is given.`,
+		Example: `  ccrn export --backend file://./crds/ > ccrn.schema.json
+  ccrn export --backend k8s:// --format openapi -o yaml > ccrn.openapi.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			exporter, ok := backend.(apis.SchemaExporter)
+			if !ok {
+				return errors.New("the backend cannot export its schemas")
+			}
+			bundle, err := exporter.Export(format)
+			if err != nil {
+				return err
+			}
+			return opts.write(cmd.OutOrStdout(), bundle, func(out io.Writer) {
+				encoder := json.NewEncoder(out)
+				encoder.SetEscapeHTML(false)
+				encoder.SetIndent("", "  ")
+				_ = encoder.Encode(bundle)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", apis.SchemaFormatJSONSchema, "Bundle format, "+apis.SchemaFormatJSONSchema+" or "+apis.SchemaFormatOpenAPI)
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{apis.SchemaFormatJSONSchema, apis.SchemaFormatOpenAPI}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("export", func() {
+	It("writes a JSON Schema bundle", func() {
+		// Act
+		stdout, _, err := run("export", "--backend", testpodBackend)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var bundle map[string]any
+		Expect(json.Unmarshal([]byte(stdout), &bundle)).To(Succeed())
+		Expect(bundle["$defs"]).To(HaveKey("pod.k8s-registry.tr.ccrn.example.com.v1"))
+		Expect(stdout).To(ContainSubstring(`"$comment": "URN template: urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>"`))
+	})
+
+	It("writes an OpenAPI bundle as YAML", func() {
+		// Act
+		stdout, _, err := run("export", "--backend", testpodBackend, "--format", "openapi", "-o", "yaml")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("openapi: 3.0.3\n"))
+		Expect(stdout).To(ContainSubstring("    pod.k8s-registry.tr.ccrn.example.com.v1:\n"))
+	})
+
+	It("rejects unknown formats", func() {
+		// Act
+		_, _, err := run("export", "--backend", testpodBackend, "--format", "xsd")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`invalid schema format "xsd"`)))
+	})
+})
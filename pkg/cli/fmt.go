@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func newFmtCommand(opts *options) *cobra.Command {
+	var write, list, defaults bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt <ccrn-or-file>...",
+		Short: "Rewrite CCRNs into canonical form",
+		Long: `Rewrite CCRNs into canonical form: the ccrn field first, followed by all other
+fields sorted by name, with values quoted only where needed. By default formatting is
+purely syntactic and needs no backend, aliases and versions are kept.
+
+Arguments starting with "ccrn=" are printed in canonical form. All other arguments
+are files, directories or glob patterns of YAML manifests, in which spec.ccrn of
+CCRN resources and the ` + apis.IdentifierAnnotation + ` annotation of any object are rewritten
+without touching the rest of the file. The formatted files are printed unless
+--write or --list is given, --list fails if any file is not formatted.
+
+With --defaults, missing fields are set to the default values of their schema,
+which needs a backend.`,
+		Example: `  ccrn fmt 'ccrn=pod.k8s.ccrn.example.com/v1, name=web, cluster=eu-de-1'
+  ccrn fmt -w ./manifests/
+  ccrn fmt -l 'deploy/**/*.yaml'
+  ccrn fmt --defaults --backend file://crds -w ./manifests/`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, stderr := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			failed := false
+
+			format := apis.ParseCCRN
+			if defaults {
+				backend, err := opts.newBackend(stderr)
+				if err != nil {
+					return err
+				}
+				format = defaulter(parser.NewResourceParser(nil, backend), backend)
+			}
+
+			var patterns []string
+			for _, arg := range args {
+				if !strings.HasPrefix(arg, "ccrn=") {
+					patterns = append(patterns, arg)
+					continue
+				}
+				parsed, err := format(arg)
+				if err != nil {
+					fmt.Fprintf(stderr, "%s: %v\n", arg, err)
+					failed = true
+					continue
+				}
+				fmt.Fprintln(out, parsed.CanonicalCCRN())
+			}
+			if len(patterns) == 0 {
+				if failed {
+					return ErrValidationFailed
+				}
+				return nil
+			}
+
+			files, err := validation.FindYAMLFiles(patterns...)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read file %s: %w", file, err)
+				}
+				identifiers, _ := scan.Extract(file, content, opts.ccrnGroup)
+				formatted, skipped := scan.Rewrite(content, identifiers, func(identifier scan.Identifier) (string, bool) {
+					if !strings.HasPrefix(identifier.Value, "ccrn=") {
+						return "", false
+					}
+					parsed, err := format(identifier.Value)
+					if err != nil {
+						fmt.Fprintf(stderr, "%s:%d:%d: %v\n", identifier.File, identifier.Line, identifier.Column, err)
+						failed = true
+						return "", false
+					}
+					return parsed.CanonicalCCRN(), true
+				})
+				for _, identifier := range skipped {
+					fmt.Fprintf(stderr, "%s:%d:%d: value spans several lines, left unchanged\n", identifier.File, identifier.Line, identifier.Column)
+				}
+				changed := !bytes.Equal(content, formatted)
+
+				switch {
+				case list:
+					if changed {
+						fmt.Fprintln(out, file)
+						failed = true
+					}
+				case write:
+					if !changed {
+						continue
+					}
+					info, err := os.Stat(file)
+					if err != nil {
+						return err
+					}
+					if err := os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+						return fmt.Errorf("failed to write file %s: %w", file, err)
+					}
+				default:
+					if _, err := out.Write(formatted); err != nil {
+						return err
+					}
+				}
+			}
+			if failed {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write the formatted manifests back to their files")
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List the files that are not formatted instead of printing them")
+	cmd.Flags().BoolVar(&defaults, "defaults", false, "Set missing fields to the defaults of their schema, needs a backend")
+	return cmd
+}
+
+// defaulter returns a parser of CCRNs setting their missing fields to the defaults of the schema of their resource
+// type, see apis.ApplyDefaults. CCRNs of unknown resource types fail.
+func defaulter(resourceParser *parser.ResourceParser, backend apis.ValidationBackend) func(string) (*apis.ParsedResource, error) {
+	return func(ccrn string) (*apis.ParsedResource, error) {
+		parsed, err := apis.ParseCCRN(ccrn)
+		if err != nil {
+			return nil, err
+		}
+		crdInfo, err := backend.GetCRD(resourceParser.ResolveKey(parsed.Fields["ccrn"]))
+		if err != nil {
+			return nil, err
+		}
+		apis.ApplyDefaults(parsed, crdInfo)
+		return parsed, nil
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("fmt", func() {
+	const formatted = `  ccrn: "ccrn=pod/v1, cluster=eu-de-1, name=web, namespace=default"`
+
+	// copyManifest copies the manifest to be formatted to a temporary directory
+	copyManifest := func() string {
+		content, err := os.ReadFile(filepath.Join("testdata", "fmt", "pod.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		file := filepath.Join(GinkgoT().TempDir(), "pod.yaml")
+		Expect(os.WriteFile(file, content, 0o600)).To(Succeed())
+		return file
+	}
+
+	It("prints CCRN arguments in canonical form", func() {
+		// Act
+		stdout, _, err := run("fmt", `ccrn=pod/v1,zones=[b, "a"],  name="web"`)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("ccrn=pod/v1, name=web, zones=[b,a]\n"))
+	})
+
+	It("sets missing fields to the defaults of their schema", func() {
+		// Arrange
+		backend := "file://" + filepath.Join("..", "validation", "testdata", "defaults_crd.yaml")
+		// Act
+		stdout, _, err := run("fmt", "--defaults", "--backend", backend, "ccrn=volume/v1, name=data, zones=[c]")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("ccrn=volume/v1, cluster=eu-de-1, name=data, size=10Gi, zones=[c]\n"))
+	})
+
+	It("reports CCRN arguments that cannot be parsed", func() {
+		// Act
+		_, stderr, err := run("fmt", `ccrn=pod/v1, name="web`)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stderr).To(ContainSubstring(`ccrn=pod/v1, name="web: `))
+	})
+
+	It("prints formatted manifests", func() {
+		// Act
+		stdout, _, err := run("fmt", filepath.Join("testdata", "fmt"))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("  # Fields in arbitrary order\n" + formatted + "\n"))
+		Expect(stdout).To(ContainSubstring("ccrn.cloud/id: urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web\n"))
+	})
+
+	It("writes formatted manifests back to their files", func() {
+		// Arrange
+		file := copyManifest()
+		// Act
+		stdout, _, err := run("fmt", "-w", file)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(BeEmpty())
+		content, err := os.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Split(string(content), "\n")).To(ContainElement(formatted))
+	})
+
+	It("lists files that are not formatted", func() {
+		// Arrange
+		file := copyManifest()
+		// Act
+		stdout, _, err := run("fmt", "-l", file)
+		_, _, writeErr := run("fmt", "-w", file)
+		formattedStdout, _, formattedErr := run("fmt", "-l", file)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(Equal(file + "\n"))
+		Expect(writeErr).ToNot(HaveOccurred())
+		Expect(formattedErr).ToNot(HaveOccurred())
+		Expect(formattedStdout).To(BeEmpty())
+	})
+})
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/admissionpolicy"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhookconfig"
+)
+
+func newGenCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate Kubernetes manifests from the resource types",
+		Long: `Generate Kubernetes manifests from the resource types of the backend, e.g. for
+enforcing CCRN validity in clusters. The manifests are written to stdout.`,
+	}
+	cmd.AddCommand(newGenAdmissionPolicyCommand(opts), newGenWebhookConfigCommand(opts))
+	return cmd
+}
+
+func newGenAdmissionPolicyCommand(opts *options) *cobra.Command {
+	var (
+		name    string
+		actions []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "admission-policy",
+		Short: "Generate a ValidatingAdmissionPolicy checking the structure of CCRN resources",
+		Long: `Generate a ValidatingAdmissionPolicy and its binding from the resource types of
+the backend, so clusters from Kubernetes 1.30 on reject structurally invalid CCRN
+resources even when the webhook is down. The CEL rules check that CCRNs reference
+a known resource type version and set its required fields, and that URNs match
+the URN template of their resource type version. The policy applies to the ccrns
+of validate.<ccrn-group>. It only denies what the webhook denies as well, the
+webhook remains responsible for the full validation.
+
+The manifests are written as YAML documents ready for kubectl apply.`,
+		Example: `  ccrn gen admission-policy --backend file://./crds/ | kubectl apply -f -
+  ccrn gen admission-policy --backend k8s:// --action Warn --action Audit`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			lister, ok := backend.(apis.CRDLister)
+			if !ok {
+				return errors.New("the backend cannot list its resource types")
+			}
+
+			options := admissionpolicy.Options{Name: name, Group: opts.ccrnGroup}
+			for _, action := range actions {
+				switch validationAction := admissionregistrationv1.ValidationAction(action); validationAction {
+				case admissionregistrationv1.Deny, admissionregistrationv1.Warn, admissionregistrationv1.Audit:
+					options.Actions = append(options.Actions, validationAction)
+				default:
+					return fmt.Errorf("invalid action %q, use Deny, Warn or Audit", action)
+				}
+			}
+			policy, binding, err := admissionpolicy.Generate(lister.ListCRDs(), options)
+			if err != nil {
+				return err
+			}
+			data, err := admissionpolicy.Marshal(policy, binding)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", admissionpolicy.DefaultName, "Name of the policy and its binding")
+	cmd.Flags().StringSliceVar(&actions, "action", []string{string(admissionregistrationv1.Deny)}, "Actions on violations, Deny, Warn or Audit")
+	_ = cmd.RegisterFlagCompletionFunc("action", cobra.FixedCompletions([]string{"Deny", "Warn", "Audit"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newGenWebhookConfigCommand(opts *options) *cobra.Command {
+	var (
+		name          string
+		service       string
+		port          int32
+		caBundle      string
+		failurePolicy string
+		timeout       int32
+		annotate      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "webhook-config",
+		Short: "Generate the MutatingWebhookConfiguration of the webhook",
+		Long: `Generate the MutatingWebhookConfiguration registering the webhook with the API
+server, with rules derived from the resource types of the backend instead of
+hand-written rule lists:
+
+- the ccrns of validate.<ccrn-group> of every CCRN group, in the versions of
+  their CRD if the backend knows it, v1 otherwise, are sent to /validate
+- with --annotate, the source resources of the resource types with an object
+  mapping are sent to /annotate, start the webhook with the printed
+  --annotate-resources
+
+Both paths patch the objects they admit, the API server rejects patches of
+validating webhooks. Without --ca-bundle the CA bundle is left empty for the
+webhook (--webhook-config-name) or cert-manager to inject it.`,
+		Example: `  ccrn gen webhook-config --backend k8s:// --service ccrn-webhook.ccrn-system --ca-bundle ca.crt | kubectl apply -f -
+  ccrn gen webhook-config --backend file://./crds/ --service ccrn-webhook.ccrn-system --annotate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			lister, ok := backend.(apis.CRDLister)
+			if !ok {
+				return errors.New("the backend cannot list its resource types")
+			}
+
+			options := webhookconfig.Options{
+				Name:           name,
+				Service:        service,
+				Port:           port,
+				Groups:         apis.ParseGroups(opts.ccrnGroup),
+				TimeoutSeconds: timeout,
+				Annotate:       annotate,
+			}
+			switch policy := admissionregistrationv1.FailurePolicyType(failurePolicy); policy {
+			case admissionregistrationv1.Fail, admissionregistrationv1.Ignore:
+				options.FailurePolicy = policy
+			default:
+				return fmt.Errorf("invalid failure policy %q, use Fail or Ignore", failurePolicy)
+			}
+			if caBundle != "" {
+				if options.CABundle, err = os.ReadFile(caBundle); err != nil {
+					return fmt.Errorf("failed to read CA bundle: %w", err)
+				}
+			}
+			config, err := webhookconfig.Generate(lister.ListCRDs(), options)
+			if err != nil {
+				return err
+			}
+			data, err := webhookconfig.Marshal(config)
+			if err != nil {
+				return err
+			}
+			if annotate {
+				resources := webhookconfig.AnnotatedResources(config.Webhooks[len(config.Webhooks)-1].Rules)
+				fmt.Fprintf(cmd.ErrOrStderr(), "Start the webhook with --annotate-resources=%s\n", strings.Join(resources, ","))
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", webhookconfig.DefaultName, "Name of the webhook configuration")
+	cmd.Flags().StringVar(&service, "service", "", "Service of the webhook as <name>.<namespace>, e.g. ccrn-webhook.ccrn-system")
+	cmd.Flags().Int32Var(&port, "port", 443, "Port of the service")
+	cmd.Flags().StringVar(&caBundle, "ca-bundle", "", "PEM file with the CA certificates of the serving certificate of the webhook")
+	cmd.Flags().StringVar(&failurePolicy, "failure-policy", string(admissionregistrationv1.Fail), "Failure policy of the validation, Fail or Ignore")
+	cmd.Flags().Int32Var(&timeout, "timeout", 10, "Timeout of the webhook calls in seconds")
+	cmd.Flags().BoolVar(&annotate, "annotate", false, "Send the source resources of the resource types to /annotate for workload annotation")
+	_ = cmd.MarkFlagRequired("service")
+	_ = cmd.RegisterFlagCompletionFunc("failure-policy", cobra.FixedCompletions([]string{"Fail", "Ignore"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
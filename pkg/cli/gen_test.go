@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("gen admission-policy", func() {
+	It("writes the policy and its binding", func() {
+		// Act
+		stdout, _, err := run("gen", "admission-policy", "--backend", testpodBackend, "--action", "Warn,Audit")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		policyDocument, bindingDocument, found := strings.Cut(stdout, "---\n")
+		Expect(found).To(BeTrue())
+		var policy admissionregistrationv1.ValidatingAdmissionPolicy
+		Expect(yaml.Unmarshal([]byte(policyDocument), &policy)).To(Succeed())
+		Expect(policy.Name).To(Equal("ccrn-structure"))
+		Expect(policy.Spec.MatchConstraints.ResourceRules[0].APIGroups).To(Equal([]string{"validate.ccrn.example.com"}))
+		Expect(policy.Spec.Validations).To(ContainElement(HaveField("Message",
+			"spec.urn of pod.k8s-registry.tr.ccrn.example.com/v1 must match the URN template urn:ccrn:<ccrn>/<cluster>/<namespace>/<name>")))
+		var binding admissionregistrationv1.ValidatingAdmissionPolicyBinding
+		Expect(yaml.Unmarshal([]byte(bindingDocument), &binding)).To(Succeed())
+		Expect(binding.Spec.ValidationActions).To(Equal([]admissionregistrationv1.ValidationAction{admissionregistrationv1.Warn, admissionregistrationv1.Audit}))
+	})
+
+	It("rejects invalid actions", func() {
+		// Act
+		_, _, err := run("gen", "admission-policy", "--backend", testpodBackend, "--action", "Block")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`invalid action "Block"`)))
+	})
+})
+
+var _ = Describe("gen webhook-config", func() {
+	It("writes the webhook configuration with the rules of the resource types", func() {
+		// Arrange
+		caBundle := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caBundle, []byte("-----BEGIN CERTIFICATE-----\n"), 0o600)).To(Succeed())
+		// Act
+		stdout, stderr, err := run("gen", "webhook-config", "--backend", testpodBackend, "--ccrn-group", "tr.ccrn.example.com",
+			"--service", "ccrn-webhook.ccrn-system", "--ca-bundle", caBundle, "--annotate")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stderr).To(ContainSubstring("--annotate-resources=pods"))
+		var config admissionregistrationv1.MutatingWebhookConfiguration
+		Expect(yaml.Unmarshal([]byte(stdout), &config)).To(Succeed())
+		Expect(config.Webhooks).To(HaveLen(2))
+		Expect(config.Webhooks[0].Rules[0].APIGroups).To(Equal([]string{"validate.tr.ccrn.example.com"}))
+		Expect(string(config.Webhooks[0].ClientConfig.CABundle)).To(HavePrefix("-----BEGIN CERTIFICATE-----"))
+		Expect(config.Webhooks[1].Rules[0].Resources).To(Equal([]string{"pods"}))
+	})
+
+	It("requires the service", func() {
+		// Act
+		_, _, err := run("gen", "webhook-config", "--backend", testpodBackend)
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`"service" not set`)))
+	})
+
+	It("rejects invalid failure policies", func() {
+		// Act
+		_, _, err := run("gen", "webhook-config", "--backend", testpodBackend, "--service", "ccrn-webhook.ccrn-system", "--failure-policy", "Retry")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`invalid failure policy "Retry"`)))
+	})
+})
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/generate"
+)
+
+// generateResult is the result of generate in JSON and YAML output
+type generateResult struct {
+	CCRN string `json:"ccrn"`
+	URN  string `json:"urn,omitempty"`
+	*apis.ValidationResult
+}
+
+func newGenerateCommand(opts *options) *cobra.Command {
+	var (
+		namespace   string
+		cluster     string
+		kubeContext string
+		set         map[string]string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate <resource-type> <name>",
+		Short: "Generate the CCRN and URN of a live cluster object",
+		Long: `Read an object from the cluster of $KUBECONFIG and print its CCRN and URN.
+
+The CCRN CRD of the resource type declares the Kubernetes resource of the object
+(ccrn/source-resource, defaulting to its kind) and where the CCRN fields are found
+in the object (ccrn/<version>.object-mapping, defaulting to name and namespace).
+Fields the object does not provide are set with --cluster and --set.
+
+The CRDs are read from the same cluster unless --backend is given.`,
+		Example: `  ccrn generate pod my-pod -n default --cluster eu-de-1
+  ccrn generate deployment/v1 web -n shop --cluster eu-de-1 --set region=eu-de`,
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			if opts.backend == "" {
+				opts.backend = "k8s://" + kubeContext
+			}
+			return opts.completeResourceTypes(toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := config.GetConfigWithContext(kubeContext)
+			if err != nil {
+				return fmt.Errorf("failed to get Kubernetes config: %w", err)
+			}
+			if opts.backend == "" {
+				opts.backend = "k8s://" + kubeContext
+			}
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			generator, err := generate.NewGeneratorForConfig(backend, restConfig)
+			if err != nil {
+				return &BackendError{Err: err}
+			}
+
+			fields := make(map[string]string, len(set)+1)
+			for field, value := range set {
+				fields[field] = value
+			}
+			if cluster != "" {
+				fields["cluster"] = cluster
+			}
+			parsed, err := generator.Generate(cmd.Context(), args[0], namespace, args[1], fields)
+			if parsed == nil {
+				return &BackendError{Err: err}
+			}
+			result := generateResult{
+				CCRN:             parsed.CanonicalCCRN(),
+				ValidationResult: &apis.ValidationResult{Valid: err == nil, ParsedCCRN: parsed, Errors: apis.ErrorMessages(err)},
+			}
+			if err == nil && parsed.UrnTemplate != "" {
+				if result.URN, err = apis.BuildURN(parsed, ""); err != nil {
+					return err
+				}
+			}
+
+			err = opts.write(cmd.OutOrStdout(), result, func(out io.Writer) {
+				if !result.Valid {
+					printResult(out, result.CCRN, result.ValidationResult)
+					return
+				}
+				fmt.Fprintln(out, result.CCRN)
+				if result.URN != "" {
+					fmt.Fprintln(out, result.URN)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if !result.Valid {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the object, ignored for cluster-scoped resources")
+	cmd.Flags().StringVar(&cluster, "cluster", "", "Value of the cluster field, which cannot be read from the object")
+	cmd.Flags().StringToStringVar(&set, "set", nil, "Additional <field>=<value> pairs, overriding the values read from the object")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "Kubeconfig context of the cluster to read the object from")
+	_ = cmd.RegisterFlagCompletionFunc("set", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if opts.backend == "" {
+			opts.backend = "k8s://" + kubeContext
+		}
+		return opts.completeFieldNames(args[0], toComplete)
+	})
+	return cmd
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("generate", func() {
+	It("requires a resource type and a name", func() {
+		// Act
+		_, _, err := run("generate", "pod", "--cluster", "eu-de-1")
+		// Assert
+		Expect(err).To(MatchError("accepts 2 arg(s), received 1"))
+	})
+})
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// lintReport is the result of lint crds in JSON and YAML output
+type lintReport struct {
+	CRDs     int                  `json:"crds"`
+	Errors   int                  `json:"errors"`
+	Warnings int                  `json:"warnings"`
+	Findings []validation.Finding `json:"findings"`
+}
+
+func newLintCommand(opts *options) *cobra.Command {
+	lint := &cobra.Command{
+		Use:   "lint",
+		Short: "Check CCRN definitions for problems",
+	}
+	lint.AddCommand(&cobra.Command{
+		Use:   "crds <file-or-pattern>...",
+		Short: "Check CCRN CRDs and their URN templates",
+		Long: `Check the CCRN CRDs in files, directories or glob patterns ("**" matches any
+number of directories) for problems the webhook only reports at runtime:
+
+  - missing or invalid ccrn/<version>.urn-template annotations
+  - URN template placeholders that are not fields of the schema
+  - non-structural schemas
+  - CCRN keys (<kind>.<group>/<version>) defined by more than one CRD
+
+Documents that are not CRDs of the CCRN group are ignored. The command fails if
+any error is found, warnings are only reported.`,
+		Example: `  ccrn lint crds ./charts/**
+  ccrn lint crds crds/pod.yaml crds/namespace.yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			linter := validation.NewLinter(opts.newLogger(cmd.ErrOrStderr()), opts.ccrnGroup)
+			if err := linter.LintFiles(args...); err != nil {
+				return err
+			}
+
+			report := lintReport{CRDs: linter.LintedCRDs(), Findings: linter.Findings()}
+			for _, finding := range report.Findings {
+				if finding.Severity == parser.SeverityError {
+					report.Errors++
+				} else {
+					report.Warnings++
+				}
+			}
+			err := opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				for _, finding := range report.Findings {
+					fmt.Fprintln(out, finding)
+				}
+				fmt.Fprintf(out, "%d CRDs checked, %d errors, %d warnings\n", report.CRDs, report.Errors, report.Warnings)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Errors > 0 {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	})
+	return lint
+}
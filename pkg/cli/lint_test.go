@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("lint crds", func() {
+	It("reports the problems of all CRDs below a directory", func() {
+		// Act
+		stdout, _, err := run("lint", "crds", filepath.Join("testdata", "charts", "**"))
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring("volume.storage.tr.ccrn.example.com/v1: error: schema is not structural: properties[name].type: Required value"))
+		Expect(stdout).To(ContainSubstring("volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.urn-template: segment 2: placeholder <region> is not a field of volume"))
+		Expect(stdout).To(ContainSubstring("bucket.storage.tr.ccrn.example.com/v1: error: no URN template annotation ccrn/v1.urn-template"))
+		Expect(stdout).To(ContainSubstring("error: duplicate CCRN key pod.k8s-registry.tr.ccrn.example.com/v1, already defined by CRD"))
+		Expect(stdout).To(ContainSubstring("volume.storage.tr.ccrn.example.com: warning: annotation ccrn/v2.urn-template refers to the unknown version v2"))
+		Expect(stdout).To(HaveSuffix("4 CRDs checked, 4 errors, 1 warnings\n"))
+	})
+
+	It("passes valid CRDs", func() {
+		// Act
+		stdout, _, err := run("lint", "crds", filepath.Join("testdata", "charts", "ccrn", "crds", "pod.yaml"))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("1 CRDs checked, 0 errors, 0 warnings\n"))
+	})
+
+	It("fails when no file matches", func() {
+		// Act
+		_, _, err := run("lint", "crds", filepath.Join("testdata", "missing", "*.yaml"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no files found matching pattern")))
+	})
+})
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/conversion"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// migrateReport is the result of migrate --live in JSON and YAML output
+type migrateReport struct {
+	DryRun  bool                `json:"dryRun"`
+	Changes []conversion.Change `json:"changes"`
+}
+
+func newMigrateCommand(opts *options) *cobra.Command {
+	var (
+		from, to      string
+		write         bool
+		live          bool
+		dryRun        bool
+		namespace     string
+		allNamespaces bool
+		kubeContext   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from <version> --to <version> [file-or-pattern]...",
+		Short: "Migrate CCRN resources to another version of their resource types",
+		Long: `Rewrite the identifiers of CCRN resources from one version of their resource
+types to another. Fields are renamed via the ccrn/<version>.field-mapping annotations
+of the CRDs, CCRNs are written in canonical form and URNs are rendered with the
+URN template of the target version. Identifiers that would violate the schema of
+the target version are reported and left unchanged.
+
+Without --live, spec.ccrn and spec.urn of CCRN resources and the ` + apis.IdentifierAnnotation + `
+annotation of any object are rewritten in the YAML manifests of files, directories
+or glob patterns. The migrated files are printed unless --write is given.
+
+With --live, the CCRN resources of the cluster of $KUBECONFIG are updated.
+--dry-run sends the updates as server-side dry run, which validates them with
+the API server and admission webhooks without persisting them. The CRDs are read
+from the same cluster unless --backend is given.`,
+		Example: `  ccrn migrate --from v1alpha1 --to v1 --backend file://./crds/ -w ./manifests/
+  ccrn migrate --from v1alpha1 --to v1 --live --all-namespaces --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if live && len(args) > 0 {
+				return errors.New("--live cannot be combined with files")
+			}
+			if !live && len(args) == 0 {
+				return errors.New("requires files or --live")
+			}
+
+			if live {
+				restConfig, err := config.GetConfigWithContext(kubeContext)
+				if err != nil {
+					return fmt.Errorf("failed to get Kubernetes config: %w", err)
+				}
+				if opts.backend == "" {
+					opts.backend = "k8s://" + kubeContext
+				}
+				backend, err := opts.newBackend(cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+				client, err := dynamic.NewForConfig(restConfig)
+				if err != nil {
+					return &BackendError{Err: fmt.Errorf("failed to create dynamic client: %w", err)}
+				}
+				if allNamespaces {
+					namespace = ""
+				}
+				changes, err := conversion.NewConverter(backend).MigrateObjects(cmd.Context(), client, namespace, from, to, dryRun)
+				if err != nil {
+					return &BackendError{Err: err}
+				}
+				return opts.writeMigrateReport(cmd.OutOrStdout(), migrateReport{DryRun: dryRun, Changes: append([]conversion.Change{}, changes...)})
+			}
+
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			converter := conversion.NewConverter(backend)
+			files, err := validation.FindYAMLFiles(args...)
+			if err != nil {
+				return err
+			}
+
+			out, stderr := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			failed := false
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read file %s: %w", file, err)
+				}
+				identifiers, _ := scan.Extract(file, content, opts.ccrnGroup)
+				migrated, skipped := scan.Rewrite(content, identifiers, func(identifier scan.Identifier) (string, bool) {
+					value, ok, err := converter.MigrateIdentifier(cmd.Context(), identifier.Value, from, to)
+					if err != nil {
+						fmt.Fprintf(stderr, "%s:%d:%d: %v\n", identifier.File, identifier.Line, identifier.Column, err)
+						failed = true
+					}
+					return value, ok
+				})
+				for _, identifier := range skipped {
+					fmt.Fprintf(stderr, "%s:%d:%d: value spans several lines, left unchanged\n", identifier.File, identifier.Line, identifier.Column)
+					failed = true
+				}
+
+				if !write {
+					if _, err := out.Write(migrated); err != nil {
+						return err
+					}
+					continue
+				}
+				info, err := os.Stat(file)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(file, migrated, info.Mode().Perm()); err != nil {
+					return fmt.Errorf("failed to write file %s: %w", file, err)
+				}
+			}
+			if failed {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Version to migrate from, e.g. v1alpha1")
+	cmd.Flags().StringVar(&to, "to", "", "Version to migrate to, e.g. v1")
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write the migrated manifests back to their files")
+	cmd.Flags().BoolVar(&live, "live", false, "Migrate the CCRN resources of the cluster instead of files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --live, validate the updates with the API server without persisting them")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "With --live, namespace of the CCRN resources")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "With --live, migrate the CCRN resources of all namespaces")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "With --live, kubeconfig context of the cluster")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+// writeMigrateReport writes the changes of a live migration and fails if any identifier could not be migrated
+func (o *options) writeMigrateReport(out io.Writer, report migrateReport) error {
+	failed := 0
+	for _, change := range report.Changes {
+		if change.Error != "" {
+			failed++
+		}
+	}
+	err := o.write(out, report, func(out io.Writer) {
+		for _, change := range report.Changes {
+			fmt.Fprintln(out, change)
+		}
+		suffix := ""
+		if report.DryRun {
+			suffix = " (dry run)"
+		}
+		fmt.Fprintf(out, "%d identifiers migrated, %d failed%s\n", len(report.Changes)-failed, failed, suffix)
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ErrValidationFailed
+	}
+	return nil
+}
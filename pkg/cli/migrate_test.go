@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("migrate", func() {
+	gadgetBackend := "file://" + filepath.Join("..", "validation", "testdata", "conversion_crd.yaml")
+	manifest := filepath.Join("testdata", "migrate", "gadget.yaml")
+
+	It("prints the migrated manifests and reports identifiers that cannot be migrated", func() {
+		// Act
+		stdout, stderr, err := run("migrate", "--backend", gadgetBackend, "--from", "v1alpha1", "--to", "v1", manifest)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring("ccrn.cloud/id: urn:ccrn:gadget.tr.ccrn.example.com/v1/eu-de-1/web\n"))
+		Expect(stdout).To(ContainSubstring("  # Written before the region field was renamed\n" +
+			`  ccrn: "ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web"` + "\n"))
+		Expect(stdout).To(ContainSubstring(`  ccrn: "ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1"` + "\n"))
+		Expect(stderr).To(ContainSubstring(manifest + ":19:9: "))
+		Expect(stderr).To(ContainSubstring("missing required fields: name"))
+	})
+
+	It("writes the migrated manifests back to their files", func() {
+		// Arrange
+		content, err := os.ReadFile(manifest)
+		Expect(err).ToNot(HaveOccurred())
+		file := filepath.Join(GinkgoT().TempDir(), "gadget.yaml")
+		Expect(os.WriteFile(file, content, 0o600)).To(Succeed())
+		// Act
+		stdout, _, err := run("migrate", "--backend", gadgetBackend, "--from", "v1alpha1", "--to", "v1", "-w", file)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(BeEmpty())
+		migrated, err := os.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(migrated)).To(ContainSubstring("urn:ccrn:gadget.tr.ccrn.example.com/v1/eu-de-1/web"))
+	})
+
+	It("requires files or --live", func() {
+		// Act
+		_, _, err := run("migrate", "--backend", gadgetBackend, "--from", "v1alpha1", "--to", "v1")
+		// Assert
+		Expect(err).To(MatchError("requires files or --live"))
+	})
+})
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats selected by --output
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputSARIF = "sarif" // Only supported by commands reporting identifiers
+)
+
+// Exit codes of the ccrn command, see ExitCode
+const (
+	ExitValid   = 0 // All inputs are valid
+	ExitInvalid = 1 // At least one input is invalid or a check found errors
+	ExitUsage   = 2 // Invalid arguments, flags or configuration
+	ExitBackend = 3 // The CRDs or cluster objects could not be loaded
+)
+
+// BackendError is returned by commands when the CRDs or cluster objects could not be loaded
+type BackendError struct {
+	Err error
+}
+
+func (e *BackendError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BackendError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for the error returned by a command: ExitValid without error, ExitInvalid for
+// ErrValidationFailed, ExitBackend for a BackendError and ExitUsage for all other errors
+func ExitCode(err error) int {
+	var backendErr *BackendError
+	switch {
+	case err == nil:
+		return ExitValid
+	case errors.Is(err, ErrValidationFailed):
+		return ExitInvalid
+	case errors.As(err, &backendErr):
+		return ExitBackend
+	default:
+		return ExitUsage
+	}
+}
+
+// checkOutput fails for unknown output formats and formats the command does not support
+func (o *options) checkOutput(cmd *cobra.Command) error {
+	switch o.output {
+	case OutputText, OutputJSON, OutputYAML:
+		return nil
+	case OutputSARIF:
+		if cmd.Annotations[sarifAnnotation] == "" {
+			return fmt.Errorf("output format %s is not supported by %s", OutputSARIF, cmd.CommandPath())
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, use %s, %s, %s or %s", o.output, OutputText, OutputJSON, OutputYAML, OutputSARIF)
+	}
+}
+
+// write writes the value as JSON (also used for SARIF) or YAML, or calls text for the text output
+func (o *options) write(out io.Writer, value any, text func(io.Writer)) error {
+	switch o.output {
+	case OutputJSON, OutputSARIF:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	case OutputYAML:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		text(out)
+		return nil
+	}
+}
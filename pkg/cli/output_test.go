@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("Output", func() {
+	It("writes validation results as JSON", func() {
+		// Act
+		stdout, _, err := run("validate", "-o", "json", "--backend", testpodBackend,
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web", "not a ccrn")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		var results []map[string]any
+		Expect(json.Unmarshal([]byte(stdout), &results)).To(Succeed())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(HaveKeyWithValue("input", "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web"))
+		Expect(results[0]).To(HaveKeyWithValue("valid", true))
+		Expect(results[0]).To(HaveKey("parsedCCRN"))
+		Expect(results[1]).To(HaveKeyWithValue("valid", false))
+		Expect(results[1]).To(HaveKeyWithValue("errors", ConsistOf(ContainSubstring("expected 'ccrn=' or 'urn:ccrn:' prefix"))))
+	})
+
+	It("writes lint reports as YAML", func() {
+		// Act
+		stdout, _, err := run("lint", "crds", "-o", "yaml", filepath.Join("testdata", "charts", "**"))
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		var report map[string]any
+		Expect(yaml.Unmarshal([]byte(stdout), &report)).To(Succeed())
+		Expect(report).To(HaveKeyWithValue("crds", BeNumerically("==", 4)))
+		Expect(report).To(HaveKeyWithValue("errors", BeNumerically("==", 4)))
+		Expect(report).To(HaveKeyWithValue("findings", HaveLen(5)))
+	})
+
+	It("rejects unknown output formats", func() {
+		// Act
+		_, _, err := run("validate", "-o", "xml", "--backend", testpodBackend, "ccrn=pod/v1")
+		// Assert
+		Expect(err).To(MatchError(`invalid output format "xml", use text, json, yaml or sarif`))
+		Expect(cli.ExitCode(err)).To(Equal(cli.ExitUsage))
+	})
+
+	DescribeTable("maps errors to exit codes",
+		func(args []string, expected int) {
+			// Act
+			_, _, err := run(args...)
+			// Assert
+			Expect(cli.ExitCode(err)).To(Equal(expected))
+		},
+		Entry("valid inputs", []string{"validate", "--backend", testpodBackend, "ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web"}, cli.ExitValid),
+		Entry("invalid inputs", []string{"validate", "--backend", testpodBackend, "ccrn=pod/v1, cluster=mars"}, cli.ExitInvalid),
+		Entry("missing arguments", []string{"validate", "--backend", testpodBackend}, cli.ExitUsage),
+		Entry("unknown flags", []string{"validate", "--unknown", "ccrn=pod/v1"}, cli.ExitUsage),
+		Entry("unreadable backends", []string{"validate", "--backend", "file:///non/existent/crds.yaml", "ccrn=pod/v1"}, cli.ExitBackend),
+		Entry("unsupported backends", []string{"validate", "--backend", "ftp://example.com/crds.yaml", "ccrn=pod/v1"}, cli.ExitBackend),
+		Entry("unverified downloads", []string{"validate", "--require-signature", "--backend", "https://example.com/crds.yaml", "ccrn=pod/v1"}, cli.ExitBackend),
+	)
+
+	It("keeps wrapped errors classified", func() {
+		// Arrange
+		backendErr := &cli.BackendError{Err: errors.New("connection refused")}
+		// Act & Assert
+		Expect(cli.ExitCode(nil)).To(Equal(cli.ExitValid))
+		Expect(cli.ExitCode(fmt.Errorf("lint: %w", cli.ErrValidationFailed))).To(Equal(cli.ExitInvalid))
+		Expect(cli.ExitCode(fmt.Errorf("generate: %w", backendErr))).To(Equal(cli.ExitBackend))
+		Expect(backendErr).To(MatchError("connection refused"))
+	})
+})
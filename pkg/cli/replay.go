@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+)
+
+// replayReport is the result of replay in JSON and YAML output
+type replayReport struct {
+	Requests  int             `json:"requests"`
+	Unchanged int             `json:"unchanged"`
+	Changed   int             `json:"changed"`
+	Results   []replay.Result `json:"results"`
+}
+
+func newReplayCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <recording>...",
+		Short: "Replay recorded admission requests against the CRDs of a backend",
+		Long: `Validate a schema upgrade or a new build against real traffic before rolling it
+out: the admission requests recorded by the webhook with --record-file are sent
+to the /validate and /annotate handlers of this build, which run in process with
+the CRDs of --backend, and the responses are compared with the recorded ones.
+
+Requests that are allowed or denied differently, or patched differently, are
+reported with both decisions, followed by a summary. Changed messages and
+warnings alone are not reported. The command fails if any decision changed.
+Only offline backends (file:// and https://) are supported, - reads the
+recording from stdin.`,
+		Example: `  ccrn replay --backend file://crds-v2 recording.jsonl
+  ccrn replay --backend https://example.com/crds.yaml recording.jsonl -o json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var records []replay.Record
+			for _, file := range args {
+				fileRecords, err := readRecords(cmd.InOrStdin(), file)
+				if err != nil {
+					return err
+				}
+				records = append(records, fileRecords...)
+			}
+
+			handler, err := opts.newOfflineWebhook(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			results, err := replay.Replay(handler, records)
+			if err != nil {
+				return err
+			}
+
+			report := replayReport{Requests: len(results), Results: results}
+			for _, result := range results {
+				if result.Changed {
+					report.Changed++
+				} else {
+					report.Unchanged++
+				}
+			}
+			err = opts.write(cmd.OutOrStdout(), report, func(out io.Writer) {
+				for _, result := range report.Results {
+					if result.Changed {
+						printReplayResult(out, result)
+					}
+				}
+				fmt.Fprintf(out, "%d requests replayed: %d unchanged, %d changed\n", report.Requests, report.Unchanged, report.Changed)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Changed > 0 {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// readRecords reads the records of a recording file, - reads stdin
+func readRecords(stdin io.Reader, file string) ([]replay.Record, error) {
+	in := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	records, err := replay.Read(in)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return records, nil
+}
+
+// printReplayResult prints the recorded and the replayed decision of a changed request
+func printReplayResult(out io.Writer, result replay.Result) {
+	fmt.Fprintf(out, "%s %s %s/%s:\n", result.UID, result.Operation, result.Namespace, result.Name)
+	for _, d := range []struct {
+		label    string
+		decision replay.Decision
+	}{{"recorded", result.Recorded}, {"replayed", result.Replayed}} {
+		decision := "allowed"
+		if !d.decision.Allowed {
+			decision = "denied"
+		}
+		fmt.Fprintf(out, "  %s: %s: %s\n", d.label, decision, d.decision.Message)
+		if len(d.decision.Patch) > 0 {
+			fmt.Fprintf(out, "    patch: %s\n", d.decision.Patch)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+var _ = Describe("replay", func() {
+	var recording string
+
+	// BeforeEach records an allowed request for cluster eu-de-2 with the webhook validating against the testpod CRD
+	BeforeEach(func() {
+		recording = filepath.Join(GinkgoT().TempDir(), "recording.jsonl")
+		recorder, err := replay.NewFileRecorder(recording, 0)
+		Expect(err).ToNot(HaveOccurred())
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithRecorder(recorder, []byte("pseudonym-key")))
+		Expect(err).ToNot(HaveOccurred())
+
+		body, err := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("recorded-1"),
+			Namespace: "default",
+			Name:      "web",
+			Operation: admissionv1.Create,
+			UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+			Object: runtime.RawExtension{Raw: []byte(`{"apiVersion": "validate.tr.ccrn.example.com/v1", "kind": "CCRN",
+				"metadata": {"name": "web", "namespace": "default"},
+				"spec": {"ccrn": "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-2, namespace=default, name=web"}}`)},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		request := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		server.Handler().ServeHTTP(httptest.NewRecorder(), request)
+		Expect(recorder.Close()).To(Succeed())
+	})
+
+	It("succeeds when no decision changed", func() {
+		// Act
+		stdout, _, err := run("replay", "--backend", testpodBackend, recording)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("1 requests replayed: 1 unchanged, 0 changed\n"))
+	})
+
+	It("reports decisions changed by a schema upgrade", func() {
+		// Arrange
+		crd, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		upgraded := filepath.Join(GinkgoT().TempDir(), "testpod_crd.yaml")
+		Expect(os.WriteFile(upgraded, []byte(strings.Replace(string(crd), `"eu-de-2", `, "", 1)), 0o600)).To(Succeed())
+		// Act
+		stdout, _, err := run("replay", "--backend", "file://"+upgraded, recording)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring("recorded-1 CREATE default/web:\n  recorded: allowed: "))
+		Expect(stdout).To(ContainSubstring("  replayed: denied: "))
+		Expect(stdout).To(HaveSuffix("1 requests replayed: 0 unchanged, 1 changed\n"))
+	})
+
+	It("reports the results as JSON", func() {
+		// Act
+		stdout, _, err := runWithStdin(mustRead(recording), "replay", "--backend", testpodBackend, "-", "-o", "json")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		var report struct {
+			Requests int             `json:"requests"`
+			Results  []replay.Result `json:"results"`
+		}
+		Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+		Expect(report.Requests).To(Equal(1))
+		Expect(report.Results[0].UID).To(Equal("recorded-1"))
+		Expect(report.Results[0].Replayed.Allowed).To(BeTrue())
+	})
+
+	It("rejects the Kubernetes backend", func() {
+		// Act
+		_, _, err := run("replay", "--backend", "k8s://", recording)
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("only offline backends are supported")))
+	})
+})
+
+// mustRead returns the content of the file
+func mustRead(path string) string {
+	content, err := os.ReadFile(path)
+	Expect(err).ToNot(HaveOccurred())
+	return string(content)
+}
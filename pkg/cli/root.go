@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cli implements the ccrn command line tool, which checks CCRNs and URNs against the CCRN CRDs
+// of a cluster, a directory or a downloaded CRD bundle, and lints the CRDs themselves.
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/version"
+)
+
+// ErrValidationFailed is returned by commands when at least one input is invalid or a check found errors,
+// the details are already written to the output of the command, see ExitCode
+var ErrValidationFailed = errors.New("validation failed")
+
+// BackendEnv is the environment variable providing the default of --backend
+const BackendEnv = "CCRN_BACKEND"
+
+// RequireSignatureEnv is the environment variable enabling --require-signature by default, if set to "true"
+const RequireSignatureEnv = "CCRN_REQUIRE_SIGNATURE"
+
+// options are the global flags shared by all commands
+type options struct {
+	backend          string
+	ccrnGroup        string
+	logLevel         string
+	output           string
+	requireSignature bool
+}
+
+// NewRootCommand creates the ccrn command with all subcommands
+func NewRootCommand() *cobra.Command {
+	opts := &options{}
+	root := &cobra.Command{
+		Use:           "ccrn",
+		Short:         "Validate and work with Common Cloud Resource Names",
+		Version:       version.Get().String(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.checkOutput(cmd)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.backend, "backend", os.Getenv(BackendEnv), "Where to load the CCRN CRDs from: file:///path (file, glob, directory or .tgz schema bundle), k8s://[context] or https://host/crds.yaml, ?digest=sha256:... pins the CRDs (default $"+BackendEnv+")")
+	flags.BoolVar(&opts.requireSignature, "require-signature", os.Getenv(RequireSignatureEnv) == "true", "Refuse HTTP backends and schema bundles without ?key= or ?digest= parameter (default $"+RequireSignatureEnv+")")
+	flags.StringVar(&opts.ccrnGroup, "ccrn-group", "ccrn.example.com", "The CCRN CRD group used for all CCRN CRDs, comma separated for several groups")
+	flags.StringVar(&opts.logLevel, "log-level", "warn", "Log level (debug, info, warn, error)")
+	flags.StringVarP(&opts.output, "output", "o", OutputText, "Output format (text, json, yaml, sarif for commands reporting identifiers)")
+
+	root.AddCommand(newValidateCommand(opts), newScanCommand(opts), newFmtCommand(opts), newDiffCommand(opts), newTemplateCommand(opts), newLintCommand(opts), newGenerateCommand(opts), newBackfillCommand(opts), newAuditCommand(opts), newMigrateCommand(opts), newDocsCommand(opts), newExportCommand(opts), newGenCommand(opts), newAdmitCommand(opts), newReplayCommand(opts), newBundleCommand(opts))
+	return root
+}
+
+// newBackend creates the validation backend selected by --backend, logging to stderr
+func (o *options) newBackend(stderr io.Writer) (apis.ValidationBackend, error) {
+	if o.backend == "" {
+		return nil, errors.New("no backend configured, use --backend or $" + BackendEnv)
+	}
+
+	var backendOpts []validation.BackendURLOption
+	if o.requireSignature {
+		backendOpts = append(backendOpts, validation.RequireSignature())
+	}
+	backend, err := validation.NewBackendFromURL(o.newLogger(stderr), o.backend, o.ccrnGroup, backendOpts...)
+	if err != nil {
+		return nil, &BackendError{Err: err}
+	}
+	return backend, nil
+}
+
+// newLogger creates a logger writing to stderr with the level selected by --log-level
+func (o *options) newLogger(stderr io.Writer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(stderr)
+	level, err := logrus.ParseLevel(o.logLevel)
+	if err != nil {
+		log.Warnf("Invalid log level %s, using warn", o.logLevel)
+		level = logrus.WarnLevel
+	}
+	log.SetLevel(level)
+	return log
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/version"
+)
+
+// SARIF rules reported by the ccrn command
+const (
+	RuleInvalidIdentifier = "ccrn/invalid-identifier"
+	RuleIdentifierWarning = "ccrn/identifier-warning"
+)
+
+// sarifAnnotation marks commands supporting --output sarif
+const sarifAnnotation = "ccrn/sarif"
+
+// sarifLog is a SARIF 2.1.0 log as consumed by code scanning tools, only the properties set by ccrn are modeled
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration sarifLevel   `json:"defaultConfiguration"`
+}
+
+type sarifLevel struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// newSARIFLog reports every invalid identifier as an error and every warning of a valid identifier as a warning,
+// located in the file the identifier was found in if known
+func newSARIFLog(results []inputResult) *sarifLog {
+	sarifResults := make([]sarifResult, 0, len(results))
+	for _, result := range results {
+		locations := sarifLocations(result.Location)
+		if !result.Valid {
+			sarifResults = append(sarifResults, sarifResult{
+				RuleID:    RuleInvalidIdentifier,
+				Level:     "error",
+				Message:   sarifMessage{Text: fmt.Sprintf("Invalid identifier %s: %s", result.Input, strings.Join(result.Errors, "; "))},
+				Locations: locations,
+			})
+			continue
+		}
+		for _, warning := range result.Warnings {
+			sarifResults = append(sarifResults, sarifResult{
+				RuleID:    RuleIdentifierWarning,
+				Level:     "warning",
+				Message:   sarifMessage{Text: fmt.Sprintf("Identifier %s: %s", result.Input, warning)},
+				Locations: locations,
+			})
+		}
+	}
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "ccrn",
+				Version:        version.Get().Version,
+				InformationURI: "https://github.com/cloudoperators/common-cloud-resource-names",
+				Rules: []sarifRule{
+					{ID: RuleInvalidIdentifier, ShortDescription: sarifMessage{Text: "CCRN or URN is invalid"}, DefaultConfiguration: sarifLevel{Level: "error"}},
+					{ID: RuleIdentifierWarning, ShortDescription: sarifMessage{Text: "CCRN or URN is valid but may not be what was intended"}, DefaultConfiguration: sarifLevel{Level: "warning"}},
+				},
+			}},
+			Results: sarifResults,
+		}},
+	}
+}
+
+// sarifLocations returns the SARIF location of an identifier, none for identifiers given as arguments
+func sarifLocations(location *Location) []sarifLocation {
+	if location == nil {
+		return nil
+	}
+	physical := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(location.File)}}
+	if location.Line > 0 {
+		physical.Region = &sarifRegion{StartLine: location.Line, StartColumn: location.Column}
+	}
+	return []sarifLocation{{PhysicalLocation: physical}}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+// sarifResults returns the results of the only run of a SARIF log
+func sarifResults(stdout string) []map[string]any {
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string           `json:"name"`
+					Rules []map[string]any `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []map[string]any `json:"results"`
+		} `json:"runs"`
+	}
+	ExpectWithOffset(1, json.Unmarshal([]byte(stdout), &log)).To(Succeed())
+	ExpectWithOffset(1, log.Version).To(Equal("2.1.0"))
+	ExpectWithOffset(1, log.Runs).To(HaveLen(1))
+	ExpectWithOffset(1, log.Runs[0].Tool.Driver.Name).To(Equal("ccrn"))
+	ExpectWithOffset(1, log.Runs[0].Tool.Driver.Rules).To(ConsistOf(
+		HaveKeyWithValue("id", cli.RuleInvalidIdentifier),
+		HaveKeyWithValue("id", cli.RuleIdentifierWarning),
+	))
+	return log.Runs[0].Results
+}
+
+var _ = Describe("SARIF output", func() {
+	It("reports invalid identifiers as errors and warnings of valid ones as warnings", func() {
+		// Act
+		stdout, _, err := run("validate", "-o", "sarif", "--backend", testpodBackend,
+			"ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web",
+			"ccrn=pod/v1, cluster=*, namespace=default, name=web",
+			"ccrn=pod/v1, cluster=mars, namespace=default, name=web")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		results := sarifResults(stdout)
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(HaveKeyWithValue("ruleId", cli.RuleIdentifierWarning))
+		Expect(results[0]).To(HaveKeyWithValue("level", "warning"))
+		Expect(results[0]).To(HaveKeyWithValue("message", HaveKeyWithValue("text", ContainSubstring("field cluster is a wildcard"))))
+		Expect(results[1]).To(HaveKeyWithValue("ruleId", cli.RuleInvalidIdentifier))
+		Expect(results[1]).To(HaveKeyWithValue("level", "error"))
+		Expect(results[1]).To(HaveKeyWithValue("message", HaveKeyWithValue("text", ContainSubstring(`Unsupported value: "mars"`))))
+		Expect(results[1]).ToNot(HaveKey("locations"))
+	})
+
+	It("writes an empty result list for valid identifiers", func() {
+		// Act
+		stdout, _, err := run("validate", "-o", "sarif", "--backend", testpodBackend, "ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sarifResults(stdout)).To(BeEmpty())
+	})
+
+	It("is rejected by commands not reporting identifiers", func() {
+		// Act
+		_, _, err := run("lint", "crds", "-o", "sarif", filepath.Join("testdata", "charts", "**"))
+		// Assert
+		Expect(err).To(MatchError("output format sarif is not supported by ccrn lint crds"))
+		Expect(cli.ExitCode(err)).To(Equal(cli.ExitUsage))
+	})
+})
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// scanReport is the result of scan in JSON and YAML output
+type scanReport struct {
+	Files            int           `json:"files"`
+	SkippedDocuments int           `json:"skippedDocuments"` // Documents that are no valid YAML, e.g. Helm templates
+	Identifiers      int           `json:"identifiers"`
+	Valid            int           `json:"valid"`
+	Invalid          int           `json:"invalid"`
+	Results          []inputResult `json:"results"`
+}
+
+func newScanCommand(opts *options) *cobra.Command {
+	var (
+		workers  int
+		watch    bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan <file-or-pattern>...",
+		Short: "Validate the CCRNs and URNs of Kubernetes manifests",
+		Long: `Validate all CCRNs and URNs in the manifests of files, directories or glob
+patterns ("**" matches any number of directories). Identifiers are read from
+spec.ccrn and spec.urn of CCRN resources and from the ` + apis.IdentifierAnnotation + ` annotation
+of any object, documents that are no valid YAML are skipped.
+
+Invalid identifiers and identifiers with warnings are reported with their
+location followed by a summary, the command fails if any identifier is invalid.
+
+With --watch, the files are scanned again whenever they change until the command
+is interrupted. Each scan prints the problems of the changed files, or that they
+are fixed, followed by the summary of all files.`,
+		Example: `  ccrn scan --backend file://crds ./manifests/ --workers 8
+  ccrn scan --backend k8s:// -o sarif 'deploy/**/*.yaml' > ccrn.sarif
+  ccrn scan --backend file://crds --watch ./manifests/`,
+		Args:        cobra.MinimumNArgs(1),
+		Annotations: map[string]string{sarifAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if watch {
+				if opts.output != OutputText {
+					return fmt.Errorf("--watch only supports %s output", OutputText)
+				}
+				backend, err := opts.newBackend(cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+				return opts.watchScan(cmd.Context(), cmd.OutOrStdout(), validation.NewCCRNValidator(backend), args, workers, interval)
+			}
+
+			files, err := validation.FindYAMLFiles(args...)
+			if err != nil {
+				return err
+			}
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			report := scanReport{Files: len(files)}
+			var identifiers []scan.Identifier
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read file %s: %w", file, err)
+				}
+				found, skipped := scan.Extract(file, content, opts.ccrnGroup)
+				identifiers = append(identifiers, found...)
+				report.SkippedDocuments += skipped
+			}
+
+			report.Results = scanResults(scan.Validate(cmd.Context(), validation.NewCCRNValidator(backend), identifiers, workers))
+			report.Identifiers = len(report.Results)
+			for _, result := range report.Results {
+				if result.Valid {
+					report.Valid++
+				} else {
+					report.Invalid++
+				}
+			}
+
+			var value any = report
+			if opts.output == OutputSARIF {
+				value = newSARIFLog(report.Results)
+			}
+			err = opts.write(cmd.OutOrStdout(), value, func(out io.Writer) {
+				for _, result := range report.Results {
+					printScanResult(out, result)
+				}
+				fmt.Fprintf(out, "%d identifiers in %d files: %d valid, %d invalid\n", report.Identifiers, report.Files, report.Valid, report.Invalid)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Invalid > 0 {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", runtime.NumCPU(), "Number of identifiers validated concurrently")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Scan again whenever files change, until interrupted")
+	cmd.Flags().DurationVar(&interval, "watch-interval", time.Second, "How often files are checked for changes with --watch")
+	return cmd
+}
+
+// scanResults converts the results of scan.Validate to results with their location
+func scanResults(results []scan.Result) []inputResult {
+	converted := make([]inputResult, 0, len(results))
+	for _, result := range results {
+		converted = append(converted, inputResult{
+			Input:            result.Value,
+			Location:         &Location{File: result.File, Line: result.Line, Column: result.Column},
+			Object:           result.Object,
+			Path:             result.Path,
+			ValidationResult: result.ValidationResult,
+		})
+	}
+	return converted
+}
+
+// printScanResult prints an invalid result or a result with warnings prefixed with its location, valid
+// results without warnings are not printed
+func printScanResult(out io.Writer, result inputResult) {
+	if !result.Valid || len(result.Warnings) > 0 {
+		fmt.Fprintf(out, "%s:%d:%d: ", result.Location.File, result.Location.Line, result.Location.Column)
+		printResult(out, result.Input, result.ValidationResult)
+	}
+}
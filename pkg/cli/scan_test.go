@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("scan", func() {
+	manifests := filepath.Join("testdata", "manifests")
+
+	It("reports invalid identifiers and warnings with their location", func() {
+		// Act
+		stdout, _, err := run("scan", "--backend", testpodBackend, "--workers", "4", manifests)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring(filepath.Join(manifests, "ccrns.yaml") + ":16:8: FAIL urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/mars/default/db\n"))
+		Expect(stdout).To(ContainSubstring(filepath.Join(manifests, "apps", "deployment.yaml") + ":9:20: PASS ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=*"))
+		Expect(stdout).To(ContainSubstring("  warning: field cluster is a wildcard and matches any value"))
+		Expect(stdout).ToNot(ContainSubstring("name=web\"\n"))
+		Expect(stdout).To(HaveSuffix("3 identifiers in 2 files: 2 valid, 1 invalid\n"))
+	})
+
+	It("succeeds when all identifiers are valid", func() {
+		// Act
+		stdout, _, err := run("scan", "--backend", testpodBackend, filepath.Join(manifests, "apps"))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(HaveSuffix("1 identifiers in 1 files: 1 valid, 0 invalid\n"))
+	})
+
+	It("prints a report with objects and skipped documents as JSON", func() {
+		// Act
+		stdout, _, err := run("scan", "-o", "json", "--backend", testpodBackend, manifests)
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		var report map[string]any
+		Expect(json.Unmarshal([]byte(stdout), &report)).To(Succeed())
+		Expect(report).To(HaveKeyWithValue("files", BeNumerically("==", 2)))
+		Expect(report).To(HaveKeyWithValue("skippedDocuments", BeNumerically("==", 1)))
+		Expect(report).To(HaveKeyWithValue("identifiers", BeNumerically("==", 3)))
+		Expect(report).To(HaveKeyWithValue("invalid", BeNumerically("==", 1)))
+		Expect(report["results"]).To(ContainElement(And(
+			HaveKeyWithValue("object", "pod/db"),
+			HaveKeyWithValue("path", "spec.urn"),
+			HaveKeyWithValue("valid", false),
+		)))
+	})
+
+	It("reports the file and line of identifiers in SARIF", func() {
+		// Act
+		stdout, _, err := run("scan", "-o", "sarif", "--backend", testpodBackend, filepath.Join(manifests, "ccrns.yaml"))
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		results := sarifResults(stdout)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0]).To(HaveKeyWithValue("locations", ConsistOf(HaveKeyWithValue("physicalLocation", And(
+			HaveKeyWithValue("artifactLocation", HaveKeyWithValue("uri", filepath.ToSlash(filepath.Join(manifests, "ccrns.yaml")))),
+			HaveKeyWithValue("region", And(HaveKeyWithValue("startLine", BeNumerically("==", 16)), HaveKeyWithValue("startColumn", BeNumerically("==", 8)))),
+		)))))
+	})
+
+	It("fails when a pattern matches no files", func() {
+		// Act
+		_, _, err := run("scan", "--backend", testpodBackend, filepath.Join(manifests, "*.json"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no files found")))
+	})
+})
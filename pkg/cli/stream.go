@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// StdinFile is the file name of inputs read from stdin in the location of results
+const StdinFile = "<stdin>"
+
+// maxStreamLine limits the length of a line read from stdin
+const maxStreamLine = 1 << 20
+
+// streamInput is an input read from a line of stdin
+type streamInput struct {
+	line  int
+	input string
+	err   error // The line cannot be decoded, reported as invalid input
+}
+
+// validateStream validates the inputs read from in with the given number of workers and writes every
+// result as soon as it is available, so the results are in the order of completion. JSON results are
+// written as one object per line (NDJSON), YAML results as separate documents and SARIF as one log
+// after all inputs are validated.
+func (o *options) validateStream(ctx context.Context, validator *validation.CCRNValidator, in io.Reader, out io.Writer, workers int) error {
+	inputs := make(chan streamInput)
+	results := make(chan inputResult)
+	var readErr error
+	go func() {
+		defer close(inputs)
+		readErr = readStream(ctx, in, inputs)
+	}()
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for input := range inputs {
+				var result *apis.ValidationResult
+				if input.err != nil {
+					result = &apis.ValidationResult{Errors: []string{input.err.Error()}}
+				} else {
+					result, _ = validator.ValidateCCRNContext(ctx, input.input)
+				}
+				results <- inputResult{Input: input.input, Location: &Location{File: StdinFile, Line: input.line}, ValidationResult: result}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	var collected []inputResult
+	var writeErr error
+	failed := false
+	for result := range results {
+		failed = failed || !result.Valid
+		if writeErr != nil {
+			continue
+		}
+		switch o.output {
+		case OutputSARIF:
+			collected = append(collected, result)
+		case OutputJSON:
+			writeErr = encoder.Encode(result)
+		case OutputYAML:
+			writeErr = writeYAMLDocument(out, result)
+		default:
+			printResult(out, result.Input, result.ValidationResult)
+		}
+	}
+	if writeErr == nil && o.output == OutputSARIF {
+		writeErr = o.write(out, newSARIFLog(collected), nil)
+	}
+
+	switch {
+	case readErr != nil:
+		return fmt.Errorf("failed to read inputs from stdin: %w", readErr)
+	case writeErr != nil:
+		return writeErr
+	case failed:
+		return ErrValidationFailed
+	default:
+		return nil
+	}
+}
+
+// readStream sends the inputs of the lines read from in until it is exhausted or the context is done.
+// Lines are CCRNs or URNs, or NDJSON: JSON strings or objects with an input, ccrn or urn member.
+// Empty lines and lines starting with # are skipped.
+func readStream(ctx context.Context, in io.Reader, inputs chan<- streamInput) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		input := streamInput{line: line}
+		input.input, input.err = decodeStreamLine(text)
+		if input.err != nil {
+			input.input = text
+			input.err = fmt.Errorf("line %d: %w", line, input.err)
+		}
+		select {
+		case inputs <- input:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+// decodeStreamLine returns the input of a line, decoding NDJSON strings and objects
+func decodeStreamLine(text string) (string, error) {
+	switch text[0] {
+	case '"':
+		var input string
+		if err := json.Unmarshal([]byte(text), &input); err != nil {
+			return "", fmt.Errorf("invalid JSON string: %w", err)
+		}
+		return input, nil
+	case '{':
+		var object struct {
+			Input string `json:"input"`
+			CCRN  string `json:"ccrn"`
+			URN   string `json:"urn"`
+		}
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return "", fmt.Errorf("invalid JSON object: %w", err)
+		}
+		for _, input := range []string{object.Input, object.CCRN, object.URN} {
+			if input != "" {
+				return input, nil
+			}
+		}
+		return "", errors.New("JSON object has no input, ccrn or urn member")
+	default:
+		return text, nil
+	}
+}
+
+// writeYAMLDocument writes the value as a YAML document starting with a document separator
+func writeYAMLDocument(out io.Writer, value any) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "---\n%s", data)
+	return err
+}
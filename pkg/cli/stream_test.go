@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("validate -", func() {
+	const inputs = `ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web
+
+# inventory export
+{"urn": "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/mars/default/db"}
+"ccrn=pod/v1, cluster=*, namespace=default, name=api"
+{"name": "web"}
+`
+
+	It("validates every line read from stdin", func() {
+		// Act
+		stdout, _, err := runWithStdin(inputs, "validate", "--backend", testpodBackend, "-")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring("PASS ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web\n"))
+		Expect(stdout).To(ContainSubstring("FAIL urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/mars/default/db\n"))
+		Expect(stdout).To(ContainSubstring("PASS ccrn=pod/v1, cluster=*, namespace=default, name=api\n"))
+		Expect(stdout).To(ContainSubstring("FAIL {\"name\": \"web\"}\n  error: line 6: JSON object has no input, ccrn or urn member\n"))
+	})
+
+	It("writes one JSON object per line with the line of the input", func() {
+		// Act
+		stdout, _, err := runWithStdin(inputs, "validate", "-o", "json", "--workers", "4", "--backend", testpodBackend, "-")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		Expect(lines).To(HaveLen(4))
+		results := make(map[float64]map[string]any, len(lines))
+		for _, line := range lines {
+			var result map[string]any
+			Expect(json.Unmarshal([]byte(line), &result)).To(Succeed())
+			location, ok := result["location"].(map[string]any)
+			Expect(ok).To(BeTrue())
+			Expect(location).To(HaveKeyWithValue("file", cli.StdinFile))
+			results[location["line"].(float64)] = result
+		}
+		Expect(results).To(HaveKeyWithValue(1.0, HaveKeyWithValue("valid", true)))
+		Expect(results).To(HaveKeyWithValue(4.0, HaveKeyWithValue("valid", false)))
+		Expect(results).To(HaveKeyWithValue(5.0, HaveKeyWithValue("warnings", ConsistOf(ContainSubstring("wildcard")))))
+		Expect(results).To(HaveKeyWithValue(6.0, HaveKeyWithValue("valid", false)))
+	})
+
+	It("succeeds when all inputs are valid", func() {
+		// Act
+		stdout, _, err := runWithStdin("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web\n",
+			"validate", "-o", "yaml", "--backend", testpodBackend, "-")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(HavePrefix("---\ninput: urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web\n"))
+	})
+
+	It("rejects stdin combined with arguments", func() {
+		// Act
+		_, _, err := runWithStdin("", "validate", "--backend", testpodBackend, "-", "ccrn=pod/v1")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("cannot be combined")))
+		Expect(cli.ExitCode(err)).To(Equal(cli.ExitUsage))
+	})
+})
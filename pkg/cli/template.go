@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// templateResult is the result of template render and match in JSON and YAML output
+type templateResult struct {
+	Template string            `json:"template"`
+	From     string            `json:"from,omitempty"` // Former template of a converted URN
+	URN      string            `json:"urn,omitempty"`
+	CCRN     string            `json:"ccrn,omitempty"`   // Canonical CCRN of the fields
+	Fields   map[string]string `json:"fields,omitempty"` // Fields rendered into or matched from the URN
+	Issues   []parser.Issue    `json:"issues,omitempty"` // Problems of the template itself
+	Error    string            `json:"error,omitempty"`  // Why rendering or matching failed
+}
+
+func newTemplateCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Develop and debug URN templates",
+		Long: `Render and match URN templates locally before annotating CRDs with them.
+No backend is needed, the template is linted without schema.`,
+	}
+	cmd.AddCommand(newTemplateRenderCommand(opts), newTemplateMatchCommand(opts), newTemplateConvertCommand(opts))
+	return cmd
+}
+
+func newTemplateRenderCommand(opts *options) *cobra.Command {
+	var (
+		template string
+		set      []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render [ccrn]",
+		Short: "Render a URN from fields",
+		Long: `Render the URN template with the fields of the CCRN argument and of --set, which
+takes precedence. Issues of the template are printed before the URN.`,
+		Example: `  ccrn template render --template 'urn:ccrn:<ccrn>/<region>-<az>/[<namespace>]/<name>' \
+    --set ccrn=pod.k8s.ccrn.example.com/v1 --set region=eu-de --set az=1a --set name=web
+  ccrn template render --template 'urn:ccrn:<ccrn>/<cluster>/<name>' 'ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=web'`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields := map[string]string{}
+			if len(args) == 1 {
+				parsed, err := apis.ParseCCRN(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to parse %s: %w", args[0], err)
+				}
+				fields = parsed.Fields
+			}
+			for _, pair := range set {
+				field, value, found := strings.Cut(pair, "=")
+				if !found || field == "" {
+					return fmt.Errorf("invalid --set %q, expected <field>=<value>", pair)
+				}
+				fields[field] = value
+			}
+
+			result := templateResult{Template: template, Fields: fields, Issues: parser.ValidateTemplate(template, nil)}
+			compiled, err := apis.CompileTemplate(template)
+			if err == nil {
+				result.URN, err = compiled.Render(fields)
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else if _, hasCCRN := fields["ccrn"]; hasCCRN {
+				result.CCRN = (&apis.ParsedResource{Fields: fields}).CanonicalCCRN()
+			}
+			return opts.writeTemplateResult(cmd.OutOrStdout(), result, func(out io.Writer) {
+				if result.Error != "" {
+					fmt.Fprintf(out, "error: %s\n", result.Error)
+					return
+				}
+				fmt.Fprintln(out, result.URN)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&template, "template", "", "URN template, e.g. 'urn:ccrn:<ccrn>/<cluster>/<name>'")
+	cmd.Flags().StringArrayVar(&set, "set", nil, "Field as <field>=<value>, may be repeated")
+	_ = cmd.MarkFlagRequired("template")
+	return cmd
+}
+
+func newTemplateMatchCommand(opts *options) *cobra.Command {
+	var template string
+
+	cmd := &cobra.Command{
+		Use:   "match <urn>",
+		Short: "Extract the fields of a URN",
+		Long: `Match the URN against the template and print the extracted fields, one per line.
+If the URN does not match, the segment that could not be matched is marked.`,
+		Example: `  ccrn template match urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1a/web --template 'urn:ccrn:<ccrn>/<region>-<az>/[<namespace>]/<name>'`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			urn, _ := apis.SplitURNComponents(args[0])
+			result := templateResult{Template: template, Issues: parser.ValidateTemplate(template, nil)}
+			compiled, err := apis.CompileTemplate(template)
+			if err == nil {
+				result.Fields, err = compiled.Match(urn)
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.URN = args[0]
+				result.CCRN = (&apis.ParsedResource{Fields: result.Fields}).CanonicalCCRN()
+			}
+			return opts.writeTemplateResult(cmd.OutOrStdout(), result, func(out io.Writer) {
+				if result.Error == "" {
+					printFields(out, result.Fields)
+					return
+				}
+				var parseErr *apis.ParseError
+				if errors.As(err, &parseErr) && parseErr.Input == urn {
+					fmt.Fprintf(out, "%s\n%s^\n", urn, strings.Repeat(" ", parseErr.Offset))
+				}
+				fmt.Fprintf(out, "error: %s\n", result.Error)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&template, "template", "", "URN template, e.g. 'urn:ccrn:<ccrn>/<cluster>/<name>'")
+	_ = cmd.MarkFlagRequired("template")
+	return cmd
+}
+
+func newTemplateConvertCommand(opts *options) *cobra.Command {
+	var from, to, mapping string
+
+	cmd := &cobra.Command{
+		Use:   "convert <urn>",
+		Short: "Convert a URN to another URN template",
+		Long: `Match the URN against the --from template and render its fields with the --to
+template, e.g. to upgrade stored URNs after the URN template of their resource
+type changed. Fields renamed in the new template are mapped with --mapping,
+conversions losing a field fail. Issues of the --to template are printed before
+the URN.`,
+		Example: `  ccrn template convert urn:ccrn:vm.ccrn.example.com/v1/eu-de/1a/web \
+    --from 'urn:ccrn:<ccrn>/<region>/<az>/<name>' --to 'urn:ccrn:<ccrn>/<region:[a-z]+-[a-z]+>-<zone>/<name>' --mapping az=zone`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renamed, err := apis.ParseTemplateMapping(mapping)
+			if err != nil {
+				return err
+			}
+			result := templateResult{Template: to, From: from, Issues: parser.ValidateTemplate(to, nil)}
+			if result.URN, err = apis.ConvertURNMapped(args[0], from, to, renamed); err != nil {
+				result.Error = err.Error()
+			}
+			return opts.writeTemplateResult(cmd.OutOrStdout(), result, func(out io.Writer) {
+				if result.Error != "" {
+					fmt.Fprintf(out, "error: %s\n", result.Error)
+					return
+				}
+				fmt.Fprintln(out, result.URN)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "URN template of the URN")
+	cmd.Flags().StringVar(&to, "to", "", "URN template to convert the URN to")
+	cmd.Flags().StringVar(&mapping, "mapping", "", "Renamed fields as <former field>=<current field> pairs, e.g. az=zone")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+// writeTemplateResult writes the result, in text output preceded by the issues of the template,
+// and fails if rendering or matching failed
+func (o *options) writeTemplateResult(out io.Writer, result templateResult, text func(io.Writer)) error {
+	err := o.write(out, result, func(out io.Writer) {
+		for _, issue := range result.Issues {
+			fmt.Fprintf(out, "template %s\n", issue)
+		}
+		text(out)
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return ErrValidationFailed
+	}
+	return nil
+}
+
+// printFields writes the fields one per line, the ccrn field first followed by all other fields sorted by name
+func printFields(out io.Writer, fields map[string]string) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sortFieldNames(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s=%s\n", key, apis.QuoteValue(fields[key]))
+	}
+}
+
+// sortFieldNames sorts field names in canonical order, the ccrn field first followed by all other fields
+func sortFieldNames(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[j] != "ccrn" && (names[i] == "ccrn" || names[i] < names[j])
+	})
+}
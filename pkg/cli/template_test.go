@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("template", func() {
+	const template = "urn:ccrn:<ccrn>/<cluster>/<name:dns>"
+
+	Context("render", func() {
+		It("renders the fields of the CCRN and --set", func() {
+			// Act
+			stdout, _, err := run("template", "render", "--template", template,
+				"ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=db", "--set", "name=web")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout).To(Equal("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web\n"))
+		})
+
+		It("reports missing fields and constraint violations", func() {
+			// Act
+			stdout, _, err := run("template", "render", "--template", template,
+				"--set", "ccrn=pod.k8s.ccrn.example.com/v1", "--set", "name=Web")
+			// Assert
+			Expect(err).To(MatchError(cli.ErrValidationFailed))
+			Expect(stdout).To(HavePrefix("error: "))
+			Expect(stdout).To(ContainSubstring("<cluster>"))
+		})
+
+		It("prints the issues of the template", func() {
+			// Act
+			stdout, _, err := run("template", "render", "--template", "urn:ccrn:<ccrn>/<a>/<a>",
+				"--set", "ccrn=pod.k8s.ccrn.example.com/v1", "--set", "a=x")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout).To(HavePrefix("template error: segment 3: duplicate placeholder <a>"))
+		})
+
+		It("rejects --set without value", func() {
+			// Act
+			_, _, err := run("template", "render", "--template", template, "--set", "name")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring(`invalid --set "name"`)))
+		})
+	})
+
+	Context("match", func() {
+		It("prints the fields of the URN", func() {
+			// Act
+			stdout, _, err := run("template", "match", "--template", template, "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout).To(Equal("ccrn=pod.k8s.ccrn.example.com/v1\ncluster=eu-de-1\nname=web\n"))
+		})
+
+		It("marks the segment that does not match", func() {
+			// Act
+			stdout, _, err := run("template", "match", "--template", template, "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/Web")
+			// Assert
+			Expect(err).To(MatchError(cli.ErrValidationFailed))
+			Expect(stdout).To(Equal("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/Web\n" +
+				strings.Repeat(" ", len("urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/")) + "^\n" +
+				"error: segment 3: expected <name> matching dns, got 'Web'\n"))
+		})
+
+		It("writes the fields and the canonical CCRN as JSON", func() {
+			// Act
+			stdout, _, err := run("template", "match", "-o", "json", "--template", template, "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			var result map[string]any
+			Expect(json.Unmarshal([]byte(stdout), &result)).To(Succeed())
+			Expect(result).To(HaveKeyWithValue("ccrn", "ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=web"))
+			Expect(result).To(HaveKeyWithValue("fields", HaveKeyWithValue("name", "web")))
+			Expect(result).ToNot(HaveKey("error"))
+		})
+
+		It("requires a template", func() {
+			// Act
+			_, _, err := run("template", "match", "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring(`required flag(s) "template" not set`)))
+		})
+	})
+
+	Context("convert", func() {
+		It("converts the URN to the new template", func() {
+			// Act
+			stdout, _, err := run("template", "convert", "--from", "urn:ccrn:<ccrn>/<region>/<az>/<name>", "--to", "urn:ccrn:<ccrn>/<region:[a-z]+-[a-z]+>-<zone>/<name>",
+				"--mapping", "az=zone", "urn:ccrn:vm.ccrn.example.com/v1/eu-de/1a/web")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout).To(Equal("urn:ccrn:vm.ccrn.example.com/v1/eu-de-1a/web\n"))
+		})
+
+		It("fails if a field would be lost", func() {
+			// Act
+			stdout, _, err := run("template", "convert", "--from", template, "--to", "urn:ccrn:<ccrn>/<name>", "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web")
+			// Assert
+			Expect(err).To(MatchError(cli.ErrValidationFailed))
+			Expect(stdout).To(Equal("error: field cluster of the URN has no placeholder in the URN template urn:ccrn:<ccrn>/<name>\n"))
+		})
+
+		It("rejects invalid mappings", func() {
+			// Act
+			_, _, err := run("template", "convert", "--from", template, "--to", template, "--mapping", "cluster", "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring(`invalid template mapping "cluster"`)))
+		})
+	})
+})
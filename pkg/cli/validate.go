@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func newValidateCommand(opts *options) *cobra.Command {
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "validate <ccrn-or-urn>...",
+		Short: "Validate CCRNs and URNs against their CRD schemas",
+		Long: `Validate CCRNs and URNs against the schemas of their CRDs.
+
+Every input is reported as PASS or FAIL followed by its errors and warnings,
+the command fails when any input is invalid. With --output json or yaml a list
+of validation results is written instead, --output sarif reports invalid inputs
+and warnings for code scanning tools.
+
+With "-" as only argument the inputs are read from stdin, one per line, either
+plain or as NDJSON strings or objects with an input, ccrn or urn member. They are
+validated by --workers goroutines and every result is written as soon as it is
+available, JSON as one object per line.`,
+		Example: `  ccrn validate --backend file://crds 'ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web'
+  ccrn validate --backend k8s:// urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/default/web
+  cat ids.txt | ccrn validate --backend k8s:// -o json -`,
+		Args:              cobra.MinimumNArgs(1),
+		Annotations:       map[string]string{sarifAnnotation: "true"},
+		ValidArgsFunction: opts.completeIdentifiers,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backend, err := opts.newBackend(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			validator := validation.NewCCRNValidator(backend)
+			if len(args) == 1 && args[0] == "-" {
+				return opts.validateStream(cmd.Context(), validator, cmd.InOrStdin(), cmd.OutOrStdout(), workers)
+			}
+			if slices.Contains(args, "-") {
+				return errors.New(`"-" reads the inputs from stdin and cannot be combined with other arguments`)
+			}
+
+			results := make([]inputResult, 0, len(args))
+			failed := false
+			for _, input := range args {
+				result, _ := validator.ValidateCCRNContext(cmd.Context(), input)
+				results = append(results, inputResult{Input: input, ValidationResult: result})
+				failed = failed || !result.Valid
+			}
+			var value any = results
+			if opts.output == OutputSARIF {
+				value = newSARIFLog(results)
+			}
+			err = opts.write(cmd.OutOrStdout(), value, func(out io.Writer) {
+				for _, result := range results {
+					printResult(out, result.Input, result.ValidationResult)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if failed {
+				return ErrValidationFailed
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", runtime.NumCPU(), "Number of inputs read from stdin validated concurrently")
+	return cmd
+}
+
+// Location is where an identifier was found, line and column are 1-based
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// inputResult is the validation result of an input in JSON, YAML and SARIF output
+type inputResult struct {
+	Input    string    `json:"input"`
+	Location *Location `json:"location,omitempty"` // Only set for identifiers read from files
+	Object   string    `json:"object,omitempty"`   // Kind and name of the object carrying the identifier, if read from a manifest
+	Path     string    `json:"path,omitempty"`     // Where the identifier is found in the object, e.g. "spec.ccrn"
+	*apis.ValidationResult
+}
+
+// printResult writes the verdict for one input followed by its errors and warnings
+func printResult(out io.Writer, input string, result *apis.ValidationResult) {
+	verdict := "PASS"
+	if !result.Valid {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(out, "%s %s\n", verdict, input)
+	for _, msg := range result.Errors {
+		fmt.Fprintf(out, "  error: %s\n", msg)
+	}
+	for _, msg := range result.Warnings {
+		fmt.Fprintf(out, "  warning: %s\n", msg)
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+var _ = Describe("validate", func() {
+	It("passes valid CCRNs and URNs", func() {
+		// Act
+		stdout, _, err := run("validate", "--backend", testpodBackend,
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web",
+			"urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("PASS ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web\n" +
+			"PASS urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web\n"))
+	})
+
+	It("reports field level errors of invalid inputs", func() {
+		// Act
+		stdout, _, err := run("validate", "--backend", testpodBackend,
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=us-west-1, namespace=Default, name=web")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(HavePrefix("FAIL ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=us-west-1, namespace=Default, name=web\n"))
+		Expect(stdout).To(ContainSubstring("  error: "))
+		Expect(stdout).To(ContainSubstring("cluster"))
+		Expect(stdout).To(ContainSubstring("namespace"))
+	})
+
+	It("prints warnings of valid inputs", func() {
+		// Act
+		stdout, _, err := run("validate", "--backend", testpodBackend,
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=*, namespace=default, name=web")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(ContainSubstring("  warning: field cluster is a wildcard and matches any value"))
+	})
+
+	It("reports every input even if an earlier one fails", func() {
+		// Act
+		stdout, _, err := run("validate", "--backend", testpodBackend, "not a ccrn",
+			"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web")
+		// Assert
+		Expect(err).To(MatchError(cli.ErrValidationFailed))
+		Expect(stdout).To(ContainSubstring("FAIL not a ccrn\n"))
+		Expect(stdout).To(ContainSubstring("PASS ccrn="))
+	})
+
+	It("requires a backend", func() {
+		// Arrange
+		GinkgoT().Setenv(cli.BackendEnv, "")
+		// Act
+		_, _, err := run("validate", "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no backend configured")))
+	})
+
+	It("fails when the backend cannot be loaded", func() {
+		// Act
+		_, _, err := run("validate", "--backend", "file:///non/existent/crds.yaml", "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1")
+		// Assert
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(cli.ErrValidationFailed))
+	})
+})
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// watchedFile is a scanned file in watch mode, it is scanned again when its modification time or size changes
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	results []inputResult
+}
+
+// watchScan scans the files of the patterns every interval until the context is done, only files that were
+// added or changed since the last scan are validated again. The first scan prints all problems like scan,
+// later scans the problems of the changed files or that they have none, followed by the summary of all files.
+// Problems finding or reading files are printed and retried with the next scan.
+func (o *options) watchScan(ctx context.Context, out io.Writer, validator scan.Validator, patterns []string, workers int, interval time.Duration) error {
+	watched := map[string]*watchedFile{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		changed, removed, err := o.rescan(ctx, watched, validator, patterns, workers)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "error: %v\n", err)
+		case first:
+			for _, file := range changed {
+				for _, result := range watched[file].results {
+					printScanResult(out, result)
+				}
+			}
+			printWatchSummary(out, watched)
+		case len(changed) > 0 || len(removed) > 0:
+			fmt.Fprintf(out, "\n[%s] %d files changed\n", time.Now().Format(time.TimeOnly), len(changed)+len(removed))
+			for _, file := range removed {
+				fmt.Fprintf(out, "%s: removed\n", file)
+			}
+			for _, file := range changed {
+				problems := false
+				for _, result := range watched[file].results {
+					problems = problems || !result.Valid || len(result.Warnings) > 0
+					printScanResult(out, result)
+				}
+				if !problems {
+					fmt.Fprintf(out, "%s: ok\n", file)
+				}
+			}
+			printWatchSummary(out, watched)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rescan updates the watched files and returns the sorted names of the files that were added or changed and
+// of those that were removed since the last scan
+func (o *options) rescan(ctx context.Context, watched map[string]*watchedFile, validator scan.Validator, patterns []string, workers int) ([]string, []string, error) {
+	files, err := validation.FindYAMLFiles(patterns...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changed, removed []string
+	current := make(map[string]bool, len(files))
+	for _, file := range files {
+		current[file] = true
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if previous, ok := watched[file]; ok && previous.modTime.Equal(info.ModTime()) && previous.size == info.Size() {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		identifiers, _ := scan.Extract(file, content, o.ccrnGroup)
+		watched[file] = &watchedFile{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+			results: scanResults(scan.Validate(ctx, validator, identifiers, workers)),
+		}
+		changed = append(changed, file)
+	}
+	for file := range watched {
+		if !current[file] {
+			delete(watched, file)
+			removed = append(removed, file)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed, nil
+}
+
+// printWatchSummary prints the summary of all watched files like scan
+func printWatchSummary(out io.Writer, watched map[string]*watchedFile) {
+	identifiers, valid := 0, 0
+	for _, file := range watched {
+		for _, result := range file.results {
+			identifiers++
+			if result.Valid {
+				valid++
+			}
+		}
+	}
+	fmt.Fprintf(out, "%d identifiers in %d files: %d valid, %d invalid\n", identifiers, len(watched), valid, identifiers-valid)
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cli"
+)
+
+// syncBuffer is a buffer safe for concurrent writes by the command and reads by the test
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+var _ = Describe("scan --watch", func() {
+	// manifest returns a CCRN resource with the cluster
+	manifest := func(name, cluster string) []byte {
+		return []byte(`apiVersion: k8s-registry.tr.ccrn.example.com/v1
+kind: pod
+metadata:
+  name: ` + name + `
+spec:
+  ccrn: "ccrn=pod/v1, cluster=` + cluster + `, namespace=default, name=` + name + `"
+`)
+	}
+
+	It("scans changed files again until interrupted", func() {
+		// Arrange
+		directory := GinkgoT().TempDir()
+		web, db := filepath.Join(directory, "web.yaml"), filepath.Join(directory, "db.yaml")
+		Expect(os.WriteFile(web, manifest("web", "eu-de-1"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(db, manifest("db", "eu-de-1"), 0o600)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var stdout syncBuffer
+		cmd := cli.NewRootCommand()
+		cmd.SetArgs([]string{"scan", "--backend", testpodBackend, "--watch", "--watch-interval", "10ms", directory})
+		cmd.SetOut(&stdout)
+		cmd.SetErr(GinkgoWriter)
+		done := make(chan error)
+		go func() {
+			done <- cmd.ExecuteContext(ctx)
+		}()
+
+		// Act & Assert
+		Eventually(stdout.String).Should(Equal("2 identifiers in 2 files: 2 valid, 0 invalid\n"))
+
+		Expect(os.WriteFile(web, manifest("web", "mars"), 0o600)).To(Succeed())
+		Eventually(stdout.String).Should(And(
+			MatchRegexp(`\[\d\d:\d\d:\d\d\] 1 files changed\n`+regexp.QuoteMeta(web)+`:6:9: FAIL ccrn=pod/v1, cluster=mars`),
+			HaveSuffix("2 identifiers in 2 files: 1 valid, 1 invalid\n"),
+		))
+
+		Expect(os.WriteFile(web, manifest("web", "eu-de-2"), 0o600)).To(Succeed())
+		Expect(os.Remove(db)).To(Succeed())
+		Eventually(stdout.String).Should(HaveSuffix(db + ": removed\n" + web + ": ok\n1 identifiers in 1 files: 1 valid, 0 invalid\n"))
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("only supports text output", func() {
+		// Act
+		_, _, err := run("scan", "--backend", testpodBackend, "--watch", "-o", "json", filepath.Join("testdata", "manifests"))
+		// Assert
+		Expect(err).To(MatchError("--watch only supports text output"))
+	})
+})
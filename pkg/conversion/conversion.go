@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package conversion
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// reservedFields are never renamed by a field mapping
+var reservedFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"ccrn":       true,
+	"urn":        true,
+}
+
+// Converter converts CCRN custom resources between the versions of their CRD. Fields are renamed
+// via the field mappings declared in the CRD annotations, which map the field names of a version
+// to the field names of the storage version. The ccrn field is rewritten to the target version
+// and an urn field is regenerated from the URN template of the target version. The fields of
+// spec-wrapped resource types are converted in their spec.
+type Converter struct {
+	backend apis.ValidationBackend
+	parser  *parser.ResourceParser
+}
+
+// NewConverter creates a new converter using the CRDs known to the backend
+func NewConverter(backend apis.ValidationBackend) *Converter {
+	return &Converter{backend: backend, parser: parser.NewResourceParser(nil, backend)}
+}
+
+// Convert returns a copy of the object converted to the desired API version
+func (c *Converter) Convert(object map[string]any, desiredAPIVersion string) (map[string]any, error) {
+	apiVersion, _ := object["apiVersion"].(string)
+	kind, _ := object["kind"].(string)
+	from, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+	to, err := schema.ParseGroupVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid desired apiVersion %q: %w", desiredAPIVersion, err)
+	}
+	if from.Group != to.Group {
+		return nil, fmt.Errorf("cannot convert %s from group %s to group %s", kind, from.Group, to.Group)
+	}
+
+	converted := maps.Clone(object)
+	converted["apiVersion"] = desiredAPIVersion
+	if from.Version == to.Version {
+		return converted, nil
+	}
+
+	fromCRD, err := c.backend.GetCRD(crdKey(kind, from))
+	if err != nil {
+		return nil, err
+	}
+	toCRD, err := c.backend.GetCRD(crdKey(kind, to))
+	if err != nil {
+		return nil, err
+	}
+
+	// Rename fields: version A -> storage version -> version B. Spec-wrapped resource types keep their
+	// fields in spec, which is copied as a whole, see apis.CRDInfo.SpecWrapped.
+	rename := fieldRenamer(fromCRD, toCRD)
+	if !fromCRD.SpecWrapped() {
+		converted = renameFields(converted, rename)
+		if err := regenerateIdentifiers(converted, toCRD, to.Version); err != nil {
+			return nil, fmt.Errorf("failed to regenerate URN for %s: %w", desiredAPIVersion, err)
+		}
+		return converted, nil
+	}
+	if spec, ok := object["spec"].(map[string]any); ok {
+		fields := renameFields(runtime.DeepCopyJSON(spec), rename)
+		if err := regenerateIdentifiers(fields, toCRD, to.Version); err != nil {
+			return nil, fmt.Errorf("failed to regenerate URN for %s: %w", desiredAPIVersion, err)
+		}
+		converted["spec"] = fields
+	}
+	return converted, nil
+}
+
+// renameFields returns the fields renamed by the function, reserved fields keep their names
+func renameFields(fields map[string]any, rename func(string) string) map[string]any {
+	renamed := make(map[string]any, len(fields))
+	for key, value := range fields {
+		if !reservedFields[key] {
+			key = rename(key)
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+// regenerateIdentifiers rewrites the ccrn field to the version and regenerates the urn field from the
+// URN template of the version, fields the object does not have are not added
+func regenerateIdentifiers(fields map[string]any, toCRD *apis.CRDInfo, version string) error {
+	if _, ok := fields["ccrn"]; ok {
+		fields["ccrn"] = toCRD.Name + "/" + version
+	}
+	if _, ok := fields["urn"]; ok && toCRD.URNFormat != "" {
+		urn, err := urnFor(fields, toCRD.URNFormat)
+		if err != nil {
+			return err
+		}
+		fields["urn"] = urn
+	}
+	return nil
+}
+
+// fieldRenamer returns a function renaming the fields of the from version to the fields of the to version,
+// via the storage version field names of the field mappings
+func fieldRenamer(fromCRD, toCRD *apis.CRDInfo) func(string) string {
+	toFieldNames := make(map[string]string, len(toCRD.FieldMapping))
+	for toName, storageName := range toCRD.FieldMapping {
+		toFieldNames[storageName] = toName
+	}
+	return func(name string) string {
+		if storageName, ok := fromCRD.FieldMapping[name]; ok {
+			name = storageName
+		}
+		if toName, ok := toFieldNames[name]; ok {
+			name = toName
+		}
+		return name
+	}
+}
+
+// crdKey returns the backend key of the CRD version serving the kind
+func crdKey(kind string, gv schema.GroupVersion) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s/%s", kind, gv.Group, gv.Version))
+}
+
+// urnFor renders the URN template with the string fields of the object
+func urnFor(object map[string]any, template string) (string, error) {
+	parsed := &apis.ParsedResource{Format: "CCRN", Fields: map[string]string{}}
+	for key, value := range object {
+		if s, ok := value.(string); ok && key != "urn" {
+			parsed.Fields[key] = s
+		}
+	}
+	return apis.BuildURN(parsed, template)
+}
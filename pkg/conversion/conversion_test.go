@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package conversion_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/conversion"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestConversion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Conversion Suite")
+}
+
+var _ = Describe("Converter", func() {
+	var converter *conversion.Converter
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "conversion*_crd.yaml"))).To(Succeed())
+		converter = conversion.NewConverter(backend)
+	})
+
+	v1alpha1 := func() map[string]any {
+		return map[string]any{
+			"apiVersion": "tr.ccrn.example.com/v1alpha1",
+			"kind":       "gadget",
+			"metadata":   map[string]any{"name": "my-gadget", "namespace": "default"},
+			"ccrn":       "gadget.tr.ccrn.example.com/v1alpha1",
+			"urn":        "urn:ccrn:gadget.tr.ccrn.example.com/v1alpha1/eu-de-1/my-gadget",
+			"region":     "eu-de-1",
+			"name":       "my-gadget",
+		}
+	}
+
+	It("renames mapped fields and rewrites ccrn and urn", func() {
+		// Act
+		converted, err := converter.Convert(v1alpha1(), "tr.ccrn.example.com/v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted).To(HaveKeyWithValue("apiVersion", "tr.ccrn.example.com/v1"))
+		Expect(converted).To(HaveKeyWithValue("cluster", "eu-de-1"))
+		Expect(converted).ToNot(HaveKey("region"))
+		Expect(converted).To(HaveKeyWithValue("ccrn", "gadget.tr.ccrn.example.com/v1"))
+		Expect(converted).To(HaveKeyWithValue("urn", "urn:ccrn:gadget.tr.ccrn.example.com/v1/eu-de-1/my-gadget"))
+	})
+
+	It("round-trips back to the original version", func() {
+		// Arrange
+		converted, err := converter.Convert(v1alpha1(), "tr.ccrn.example.com/v1")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		back, err := converter.Convert(converted, "tr.ccrn.example.com/v1alpha1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(back).To(Equal(v1alpha1()))
+	})
+
+	It("converts the spec of spec-wrapped resource types", func() {
+		// Arrange
+		object := map[string]any{
+			"apiVersion": "tr.ccrn.example.com/v1alpha1",
+			"kind":       "widget",
+			"metadata":   map[string]any{"name": "my-widget", "namespace": "default"},
+			"spec": map[string]any{
+				"ccrn":   "widget.tr.ccrn.example.com/v1alpha1",
+				"urn":    "urn:ccrn:widget.tr.ccrn.example.com/v1alpha1/eu-de-1/my-widget",
+				"region": "eu-de-1",
+				"name":   "my-widget",
+			},
+		}
+		// Act
+		converted, err := converter.Convert(object, "tr.ccrn.example.com/v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(converted).To(HaveKeyWithValue("apiVersion", "tr.ccrn.example.com/v1"))
+		Expect(converted["spec"]).To(Equal(map[string]any{
+			"ccrn":    "widget.tr.ccrn.example.com/v1",
+			"urn":     "urn:ccrn:widget.tr.ccrn.example.com/v1/eu-de-1/my-widget",
+			"cluster": "eu-de-1",
+			"name":    "my-widget",
+		}))
+		Expect(object["spec"]).To(HaveKey("region"), "the original object is not changed")
+	})
+
+	It("fails for unknown versions", func() {
+		// Act
+		_, err := converter.Convert(v1alpha1(), "tr.ccrn.example.com/v2")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// Change is the migration of an identifier of a CCRN resource in the cluster
+type Change struct {
+	Resource  string `json:"resource"` // Resource and group, e.g. "pods.k8s-registry.ccrn.example.com"
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Path      string `json:"path"` // "spec.ccrn" or "spec.urn"
+	From      string `json:"from"`
+	To        string `json:"to,omitempty"`
+	Error     string `json:"error,omitempty"` // Why the identifier or the resource could not be migrated
+}
+
+// String returns the change as "<resource>/<namespace>/<name> <path>: <from> -> <to>"
+func (c Change) String() string {
+	object := c.Resource + "/" + c.Name
+	if c.Namespace != "" {
+		object = c.Resource + "/" + c.Namespace + "/" + c.Name
+	}
+	if c.Error != "" {
+		return fmt.Sprintf("%s %s: %s: %s", object, c.Path, c.From, c.Error)
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", object, c.Path, c.From, c.To)
+}
+
+// MigrateIdentifier converts a CCRN or URN of the from version of its resource type to the to version: fields are
+// renamed via the field mappings of both versions, CCRNs are returned in canonical form with the resource type as
+// written and URNs are rendered with the URN template of the to version. The result must be valid for the schema
+// of the to version. Identifiers of other versions are returned unchanged and migrated is false.
+func (c *Converter) MigrateIdentifier(ctx context.Context, identifier, from, to string) (string, bool, error) {
+	parsed, err := c.parser.ParseContext(ctx, identifier, parser.DEFAULT_URN_TEMPLATE)
+	if err != nil {
+		return identifier, false, err
+	}
+	if parsed.Version() != from {
+		return identifier, false, nil
+	}
+	fromCRD, err := c.backend.GetCRD(parsed.CCRNKey())
+	if err != nil {
+		return identifier, false, err
+	}
+	toCRD, err := c.backend.GetCRD(parsed.CCRNName() + "/" + to)
+	if err != nil {
+		return identifier, false, fmt.Errorf("%s has no version %s: %w", parsed.CCRNName(), to, err)
+	}
+
+	rename := fieldRenamer(fromCRD, toCRD)
+	migrated := &apis.ParsedResource{Format: parsed.Format, Fields: make(map[string]string, len(parsed.Fields)), URNComponents: parsed.URNComponents}
+	for key, value := range parsed.Fields {
+		if key == "ccrn" {
+			continue
+		}
+		// Nested fields like labels.app are renamed by their top-level field
+		field, nested, isNested := strings.Cut(key, ".")
+		if field = rename(field); isNested {
+			field += "." + nested
+		}
+		migrated.Fields[field] = value
+	}
+	migrated.Fields["ccrn"] = toCRD.CCRNKey()
+	if err := migrated.ValidateAgainst(toCRD); err != nil {
+		return identifier, false, err
+	}
+
+	if parsed.Format == "URN" {
+		if toCRD.URNFormat == "" {
+			return identifier, false, apis.Errorf(apis.ErrTemplateMismatch, "no URN template for %s", toCRD.CCRNKey())
+		}
+		urn, err := apis.BuildURN(migrated, toCRD.URNFormat)
+		if err != nil {
+			return identifier, false, err
+		}
+		return urn, true, nil
+	}
+
+	// Keep the resource type as written, e.g. an alias
+	if written, err := apis.ParseCCRN(identifier); err == nil {
+		resourceType, _, _ := strings.Cut(written.Fields["ccrn"], "/")
+		migrated.Fields["ccrn"] = resourceType + "/" + to
+	}
+	return migrated.CanonicalCCRN(), true, nil
+}
+
+// MigrateObjects migrates spec.ccrn and spec.urn of the CCRN resources in the namespace, in all namespaces if it
+// is empty, from the from to the to version, and updates the resources. With dryRun the updates are sent as
+// server-side dry run, so they are validated by the API server and admission webhooks but not persisted.
+// Resource types without both versions are skipped. Identifiers that cannot be migrated and failed updates
+// are returned as changes with an error.
+func (c *Converter) MigrateObjects(ctx context.Context, client dynamic.Interface, namespace, from, to string, dryRun bool) ([]Change, error) {
+	lister, ok := c.backend.(apis.CRDLister)
+	if !ok {
+		return nil, fmt.Errorf("backend %T cannot list its resource types", c.backend)
+	}
+	crds := lister.ListCRDs()
+	versions := make(map[string]bool, len(crds))
+	for _, crd := range crds {
+		versions[crd.CCRNKey()] = true
+	}
+
+	var changes []Change
+	for _, crd := range crds {
+		resourceType, _, _ := strings.Cut(crd.CCRNKey(), "/")
+		if crd.Version != to || !versions[resourceType+"/"+from] {
+			continue
+		}
+		gvr := schema.GroupVersionResource{Group: crd.Group, Version: crd.Version, Resource: crd.Plural}
+		resource := client.Resource(gvr)
+		list, err := resource.Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.GroupResource(), err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			var itemChanges []Change
+			for _, field := range []string{"ccrn", "urn"} {
+				value, _, _ := unstructured.NestedString(item.Object, "spec", field)
+				if value == "" {
+					continue
+				}
+				change := Change{Resource: gvr.GroupResource().String(), Namespace: item.GetNamespace(), Name: item.GetName(), Path: "spec." + field, From: value}
+				migrated, isMigrated, err := c.MigrateIdentifier(ctx, value, from, to)
+				switch {
+				case err != nil:
+					change.Error = err.Error()
+				case !isMigrated:
+					continue
+				default:
+					change.To = migrated
+					_ = unstructured.SetNestedField(item.Object, migrated, "spec", field)
+				}
+				itemChanges = append(itemChanges, change)
+			}
+
+			switch {
+			case len(itemChanges) == 0:
+			case hasErrors(itemChanges):
+				for j := range itemChanges {
+					if itemChanges[j].Error == "" {
+						itemChanges[j].Error = "not updated, another identifier of the resource cannot be migrated"
+					}
+				}
+			default:
+				options := metav1.UpdateOptions{}
+				if dryRun {
+					options.DryRun = []string{metav1.DryRunAll}
+				}
+				if _, err := resource.Namespace(item.GetNamespace()).Update(ctx, item, options); err != nil {
+					for j := range itemChanges {
+						itemChanges[j].Error = fmt.Sprintf("failed to update: %v", err)
+					}
+				}
+			}
+			changes = append(changes, itemChanges...)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].String() < changes[j].String()
+	})
+	return changes, nil
+}
+
+// hasErrors reports whether any change failed
+func hasErrors(changes []Change) bool {
+	for _, change := range changes {
+		if change.Error != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package conversion_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/conversion"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+var _ = Describe("Migration", func() {
+	var converter *conversion.Converter
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "conversion_crd.yaml"))).To(Succeed())
+		converter = conversion.NewConverter(backend)
+	})
+
+	It("renames the fields of CCRNs", func() {
+		// Act
+		migrated, ok, err := converter.MigrateIdentifier(context.Background(),
+			"ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1, name=web", "v1alpha1", "v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(migrated).To(Equal("ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web"))
+	})
+
+	It("renders URNs with the template of the target version", func() {
+		// Act
+		migrated, ok, err := converter.MigrateIdentifier(context.Background(),
+			"urn:ccrn:gadget.tr.ccrn.example.com/v1alpha1/eu-de-1/web", "v1alpha1", "v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(migrated).To(Equal("urn:ccrn:gadget.tr.ccrn.example.com/v1/eu-de-1/web"))
+	})
+
+	It("keeps identifiers of other versions", func() {
+		// Act
+		migrated, ok, err := converter.MigrateIdentifier(context.Background(),
+			"ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web", "v1alpha1", "v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+		Expect(migrated).To(Equal("ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web"))
+	})
+
+	It("rejects identifiers that violate the schema of the target version", func() {
+		// Act
+		_, ok, err := converter.MigrateIdentifier(context.Background(),
+			"ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1", "v1alpha1", "v1")
+		// Assert
+		Expect(ok).To(BeFalse())
+		Expect(errors.Is(err, apis.ErrSchemaViolation)).To(BeTrue())
+	})
+
+	It("rejects unknown target versions", func() {
+		// Act
+		_, _, err := converter.MigrateIdentifier(context.Background(),
+			"ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1, name=web", "v1alpha1", "v2")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("gadget.tr.ccrn.example.com has no version v2")))
+	})
+
+	It("migrates and updates the resources in the cluster", func() {
+		// Arrange
+		gvr := schema.GroupVersionResource{Group: "tr.ccrn.example.com", Version: "v1", Resource: "gadgets"}
+		gadget := func(name string, spec map[string]interface{}) runtime.Object {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "tr.ccrn.example.com/v1",
+				"kind":       "gadget",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+				"spec":       spec,
+			}}
+		}
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "gadgetList"},
+			gadget("web", map[string]interface{}{
+				"ccrn": "ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1, name=web",
+				"urn":  "urn:ccrn:gadget.tr.ccrn.example.com/v1alpha1/eu-de-1/web",
+			}),
+			gadget("broken", map[string]interface{}{"ccrn": "ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1"}),
+			gadget("current", map[string]interface{}{"ccrn": "ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=current"}),
+		)
+		// Act
+		changes, err := converter.MigrateObjects(context.Background(), client, "", "v1alpha1", "v1", false)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(3))
+		Expect(changes[0].Name).To(Equal("broken"))
+		Expect(changes[0].Error).To(ContainSubstring("missing required fields: name"))
+		Expect(changes[1].String()).To(Equal("gadgets.tr.ccrn.example.com/default/web spec.ccrn: " +
+			"ccrn=gadget.tr.ccrn.example.com/v1alpha1, region=eu-de-1, name=web -> ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web"))
+		Expect(changes[2].To).To(Equal("urn:ccrn:gadget.tr.ccrn.example.com/v1/eu-de-1/web"))
+
+		updated, err := client.Resource(gvr).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Object["spec"]).To(HaveKeyWithValue("ccrn", "ccrn=gadget.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web"))
+	})
+})
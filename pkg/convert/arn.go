@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// SchemeARN is the scheme of AWS ARNs, declared via the CRD annotation "ccrn/<version>.arn-template"
+const SchemeARN = "arn"
+
+// ARN is an Amazon Resource Name "arn:<partition>:<service>:<region>:<account>:<resource>"
+type ARN struct {
+	Partition string // e.g. "aws" or "aws-cn"
+	Service   string // e.g. "ec2"
+	Region    string // Empty for global services
+	AccountID string // Empty for some services, e.g. s3
+	Resource  string // Resource type and id, e.g. "instance/i-0abc"
+}
+
+// ParseARN splits an ARN into its components. The resource may contain colons.
+func ParseARN(arn string) (ARN, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ARN{}, &apis.ParseError{Input: arn, Segment: 1, Expected: "arn:<partition>:<service>:<region>:<account>:<resource>", Got: arn, Err: apis.ErrUnknownFormat}
+	}
+	parsed := ARN{Partition: parts[1], Service: parts[2], Region: parts[3], AccountID: parts[4], Resource: parts[5]}
+	switch {
+	case parsed.Partition == "":
+		return ARN{}, &apis.ParseError{Input: arn, Segment: 2, Offset: len("arn:"), Expected: "partition", Err: apis.ErrUnknownFormat}
+	case parsed.Service == "":
+		return ARN{}, &apis.ParseError{Input: arn, Segment: 3, Offset: len("arn::") + len(parsed.Partition), Expected: "service", Err: apis.ErrUnknownFormat}
+	case parsed.Resource == "":
+		return ARN{}, &apis.ParseError{Input: arn, Segment: 6, Offset: len(arn), Expected: "resource", Err: apis.ErrUnknownFormat}
+	}
+	return parsed, nil
+}
+
+// String returns the ARN string
+func (a ARN) String() string {
+	return strings.Join([]string{"arn", a.Partition, a.Service, a.Region, a.AccountID, a.Resource}, ":")
+}
+
+// NewARNRegistry creates an empty registry for ARN templates like
+// "arn:aws:ec2:<region>:<account>:instance/<name>", identifiers are checked to be well-formed ARNs
+func NewARNRegistry() *Registry {
+	registry := NewRegistry(SchemeARN)
+	registry.validate = func(id string) error {
+		_, err := ParseARN(id)
+		return err
+	}
+	return registry
+}
@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package convert_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/convert"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestConvert(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Convert Suite")
+}
+
+var _ = Describe("Template", func() {
+	It("lets the last placeholder match slashes", func() {
+		// Arrange
+		template, err := convert.CompileTemplate("arn:aws:s3:::<bucket>/<key>")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		fields, ok := template.Match("arn:aws:s3:::my-bucket/logs/2025/app.log")
+		// Assert
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal(map[string]string{"bucket": "my-bucket", "key": "logs/2025/app.log"}))
+	})
+
+	It("rejects templates without or with adjacent placeholders", func() {
+		// Act
+		_, errNone := convert.CompileTemplate("arn:aws:ec2")
+		_, errAdjacent := convert.CompileTemplate("arn:aws:ec2:<region><account>")
+		// Assert
+		Expect(errNone).To(MatchError(apis.ErrInvalidTemplate))
+		Expect(errAdjacent).To(MatchError(apis.ErrInvalidTemplate))
+	})
+
+	It("refuses to render missing fields and separators in inner fields", func() {
+		// Arrange
+		template, err := convert.CompileTemplate("arn:aws:ec2:<region>:<account>:instance/<name>")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		_, errMissing := template.Render(map[string]string{"region": "eu-west-1", "name": "i-1"})
+		_, errSeparator := template.Render(map[string]string{"region": "eu/west", "account": "123456789012", "name": "i-1"})
+		// Assert
+		Expect(errMissing).To(MatchError(ContainSubstring("account")))
+		Expect(errMissing).To(MatchError(apis.ErrTemplateMismatch))
+		Expect(errSeparator).To(MatchError(apis.ErrTemplateMismatch))
+	})
+})
+
+var _ = Describe("ARN", func() {
+	It("parses and renders ARNs with colons in the resource", func() {
+		// Act
+		arn, err := convert.ParseARN("arn:aws:logs:eu-west-1:123456789012:log-group:/app:*")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arn.Service).To(Equal("logs"))
+		Expect(arn.Resource).To(Equal("log-group:/app:*"))
+		Expect(arn.String()).To(Equal("arn:aws:logs:eu-west-1:123456789012:log-group:/app:*"))
+	})
+
+	It("rejects malformed ARNs", func() {
+		// Act
+		_, err := convert.ParseARN("arn:aws:ec2")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrUnknownFormat))
+	})
+})
+
+var _ = Describe("ARN Registry", func() {
+	const (
+		ccrn = "ccrn=instance.ec2.tr.ccrn.example.com/v1, account=123456789012, name=i-0abc, region=eu-west-1"
+		arn  = "arn:aws:ec2:eu-west-1:123456789012:instance/i-0abc"
+	)
+	var registry *convert.Registry
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "aws_crd.yaml"))).To(Succeed())
+		crd, err := backend.GetCRD("instance.ec2.tr.ccrn.example.com/v1")
+		Expect(err).ToNot(HaveOccurred())
+		registry = convert.NewARNRegistry()
+		Expect(registry.RegisterCRDs(crd)).To(Succeed())
+	})
+
+	It("exports a CCRN to its ARN", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		result, err := registry.FromCCRN(parsed)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(arn))
+	})
+
+	It("imports an ARN as CCRN", func() {
+		// Act
+		parsed, err := registry.ToCCRN(arn)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.CanonicalCCRN()).To(Equal(ccrn))
+		Expect(parsed.Raw).To(Equal(ccrn))
+	})
+
+	It("prefers the most specific template", func() {
+		// Arrange
+		Expect(registry.Register("anything.ec2.tr.ccrn.example.com/v1", "arn:aws:ec2:<region>:<account>:<name>")).To(Succeed())
+		// Act
+		instance, errInstance := registry.ToCCRN(arn)
+		volume, errVolume := registry.ToCCRN("arn:aws:ec2:eu-west-1:123456789012:volume/vol-1")
+		// Assert
+		Expect(errInstance).ToNot(HaveOccurred())
+		Expect(instance.CCRNKey()).To(Equal("instance.ec2.tr.ccrn.example.com/v1"))
+		Expect(errVolume).ToNot(HaveOccurred())
+		Expect(volume.CCRNKey()).To(Equal("anything.ec2.tr.ccrn.example.com/v1"))
+		Expect(volume.Fields).To(HaveKeyWithValue("name", "volume/vol-1"))
+	})
+
+	It("reports unmapped resource types and ARNs", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, name=foo")
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		_, errExport := registry.FromCCRN(parsed)
+		_, errImport := registry.ToCCRN("arn:aws:s3:::my-bucket")
+		_, errMalformed := registry.ToCCRN("not-an-arn")
+		// Assert
+		Expect(errExport).To(MatchError(apis.ErrUnsupportedType))
+		Expect(errImport).To(MatchError(apis.ErrUnsupportedType))
+		Expect(errMalformed).To(MatchError(apis.ErrUnknownFormat))
+	})
+
+	It("rejects templates that are no ARNs", func() {
+		// Act
+		err := registry.Register("bucket.s3.tr.ccrn.example.com/v1", "s3://<bucket>")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrInvalidTemplate))
+	})
+})
+
+var _ = Describe("GCP resource name", func() {
+	It("parses and renders full resource names", func() {
+		// Act
+		name, err := convert.ParseGCPResourceName("//storage.googleapis.com/projects/_/buckets/my-bucket")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name.Service).To(Equal("storage.googleapis.com"))
+		Expect(name.Path).To(Equal("projects/_/buckets/my-bucket"))
+		Expect(name.String()).To(Equal("//storage.googleapis.com/projects/_/buckets/my-bucket"))
+	})
+
+	It("rejects relative and malformed names", func() {
+		// Act
+		_, errRelative := convert.ParseGCPResourceName("projects/my-project/instances/vm-1")
+		_, errEmpty := convert.ParseGCPResourceName("//compute.googleapis.com/")
+		// Assert
+		Expect(errRelative).To(MatchError(apis.ErrUnknownFormat))
+		Expect(errEmpty).To(MatchError(apis.ErrUnknownFormat))
+	})
+})
+
+var _ = Describe("Converter", func() {
+	const (
+		awsCCRN = "ccrn=instance.ec2.tr.ccrn.example.com/v1, account=123456789012, name=i-0abc, region=eu-west-1"
+		gcpCCRN = "ccrn=instance.compute.tr.ccrn.example.com/v1, name=vm-1, project=my-project, zone=us-central1-a"
+		gcpName = "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/vm-1"
+	)
+	var converter *convert.Converter
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "aws_crd.yaml"))).To(Succeed())
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "gcp_crd.yaml"))).To(Succeed())
+		var crds []*apis.CRDInfo
+		for _, key := range backend.GetLoadedCRDs() {
+			crd, err := backend.GetCRD(key)
+			Expect(err).ToNot(HaveOccurred())
+			crds = append(crds, crd)
+		}
+		var err error
+		converter, err = convert.NewConverter(crds...)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("registers the built-in schemes", func() {
+		// Assert
+		Expect(convert.Schemes()).To(ContainElements(convert.SchemeARN, convert.SchemeGCP))
+	})
+
+	It("converts identifiers of any scheme to CCRNs", func() {
+		// Act
+		gcp, gcpScheme, errGCP := converter.ToCCRN(gcpName)
+		aws, awsScheme, errAWS := converter.ToCCRN("arn:aws:ec2:eu-west-1:123456789012:instance/i-0abc")
+		// Assert
+		Expect(errGCP).ToNot(HaveOccurred())
+		Expect(gcpScheme).To(Equal(convert.SchemeGCP))
+		Expect(gcp.CanonicalCCRN()).To(Equal(gcpCCRN))
+		Expect(errAWS).ToNot(HaveOccurred())
+		Expect(awsScheme).To(Equal(convert.SchemeARN))
+		Expect(aws.CanonicalCCRN()).To(Equal(awsCCRN))
+	})
+
+	It("renders the identifier of the requested scheme", func() {
+		// Arrange
+		parsed, err := apis.ParseCCRN(gcpCCRN)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		name, err := converter.FromCCRN(parsed, convert.SchemeGCP)
+		_, errARN := converter.FromCCRN(parsed, convert.SchemeARN)
+		_, errScheme := converter.FromCCRN(parsed, "azure")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal(gcpName))
+		Expect(errARN).To(MatchError(apis.ErrUnsupportedType))
+		Expect(errScheme).To(MatchError(apis.ErrUnknownFormat))
+	})
+
+	It("accepts schemes plugged in by the application", func() {
+		// Arrange
+		convert.RegisterScheme("test", func() *convert.Registry { return convert.NewRegistry("test") })
+		plugged, err := convert.NewConverter(&apis.CRDInfo{
+			Kind: "widget", Group: "tr.ccrn.example.com", Version: "v1",
+			ExternalTemplates: map[string]string{"test": "test:<name>"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		parsed, scheme, err := plugged.ToCCRN("test:foo")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scheme).To(Equal("test"))
+		Expect(parsed.CanonicalCCRN()).To(Equal("ccrn=widget.tr.ccrn.example.com/v1, name=foo"))
+		Expect(func() { convert.RegisterScheme("test", convert.NewARNRegistry) }).To(Panic())
+	})
+})
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// SchemeGCP is the scheme of GCP full resource names, declared via the CRD annotation "ccrn/<version>.gcp-template"
+const SchemeGCP = "gcp"
+
+// GCPResourceName is a GCP full resource name "//<service>/<path>"
+type GCPResourceName struct {
+	Service string // Service host, e.g. "compute.googleapis.com"
+	Path    string // Relative resource name, e.g. "projects/my-project/zones/us-central1-a/instances/vm-1"
+}
+
+// ParseGCPResourceName splits a full resource name into the service and the relative resource name
+func ParseGCPResourceName(name string) (GCPResourceName, error) {
+	rest, found := strings.CutPrefix(name, "//")
+	if !found {
+		return GCPResourceName{}, &apis.ParseError{Input: name, Segment: 1, Expected: "'//' prefix", Got: name, Err: apis.ErrUnknownFormat}
+	}
+	service, path, _ := strings.Cut(rest, "/")
+	if service == "" || !strings.Contains(service, ".") {
+		return GCPResourceName{}, &apis.ParseError{Input: name, Segment: 1, Offset: len("//"), Expected: "service host like compute.googleapis.com", Got: service, Err: apis.ErrUnknownFormat}
+	}
+	if path == "" || strings.HasSuffix(path, "/") || strings.Contains(path, "//") {
+		return GCPResourceName{}, &apis.ParseError{Input: name, Segment: 2, Offset: len("//") + len(service), Expected: "resource path", Got: path, Err: apis.ErrUnknownFormat}
+	}
+	return GCPResourceName{Service: service, Path: path}, nil
+}
+
+// String returns the full resource name
+func (n GCPResourceName) String() string {
+	return "//" + n.Service + "/" + n.Path
+}
+
+// NewGCPRegistry creates an empty registry for full resource name templates like
+// "//compute.googleapis.com/projects/<project>/zones/<zone>/instances/<name>"
+func NewGCPRegistry() *Registry {
+	registry := NewRegistry(SchemeGCP)
+	registry.validate = func(id string) error {
+		_, err := ParseGCPResourceName(id)
+		return err
+	}
+	return registry
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package convert translates between CCRNs and the resource identifiers of other systems, like AWS ARNs,
+// so inventories keyed by those identifiers can be joined with CCRNs. Mappings are template driven,
+// resource types declare their templates in the CRD annotations "ccrn/<version>.<scheme>-template".
+package convert
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Registry maps resource types to their identifier templates of one scheme. It is safe for concurrent use.
+type Registry struct {
+	scheme   string
+	validate func(id string) error // Syntax check of identifiers before matching, optional
+
+	mu       sync.RWMutex
+	byKey    map[string]mapping // Lowercase CCRN key to mapping
+	mappings []mapping          // Most specific template first
+}
+
+// mapping is a registered template and the CCRN key of its resource type
+type mapping struct {
+	ccrnKey  string
+	template *Template
+}
+
+// NewRegistry creates an empty registry for the identifier scheme, e.g. "arn"
+func NewRegistry(scheme string) *Registry {
+	return &Registry{scheme: scheme, byKey: make(map[string]mapping)}
+}
+
+// Scheme returns the identifier scheme of the registry
+func (r *Registry) Scheme() string {
+	return r.scheme
+}
+
+// Register maps the resource type "<kind>.<group>/<version>" to the template. Placeholders of the
+// template name CCRN fields. Registering a resource type again replaces its template.
+func (r *Registry) Register(ccrnKey, template string) error {
+	if !strings.Contains(ccrnKey, "/") {
+		return apis.Errorf(apis.ErrUnsupportedType, "resource type %s must be <kind>.<group>/<version>", ccrnKey)
+	}
+	if r.validate != nil {
+		if err := r.validate(template); err != nil {
+			return apis.Errorf(apis.ErrInvalidTemplate, "template %s is no valid %s: %w", template, r.scheme, err)
+		}
+	}
+	compiled, err := CompileTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byKey[strings.ToLower(ccrnKey)] = mapping{ccrnKey: ccrnKey, template: compiled}
+	r.mappings = r.mappings[:0:0]
+	for _, m := range r.byKey {
+		r.mappings = append(r.mappings, m)
+	}
+	sort.Slice(r.mappings, func(i, j int) bool {
+		if r.mappings[i].template.literal != r.mappings[j].template.literal {
+			return r.mappings[i].template.literal > r.mappings[j].template.literal
+		}
+		return r.mappings[i].ccrnKey < r.mappings[j].ccrnKey
+	})
+	return nil
+}
+
+// RegisterCRDs registers the templates of the scheme declared by the CRDs, CRDs without one are skipped
+func (r *Registry) RegisterCRDs(crds ...*apis.CRDInfo) error {
+	var errs []error
+	for _, crd := range crds {
+		template, ok := crd.ExternalTemplates[r.scheme]
+		if !ok {
+			continue
+		}
+		if err := r.Register(crd.CCRNKey(), template); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FromCCRN renders the identifier of the parsed resource with the template of its resource type
+func (r *Registry) FromCCRN(parsed *apis.ParsedResource) (string, error) {
+	r.mu.RLock()
+	m, ok := r.byKey[strings.ToLower(parsed.CCRNKey())]
+	r.mu.RUnlock()
+	if !ok {
+		return "", apis.Errorf(apis.ErrUnsupportedType, "no %s mapping for resource type %s", r.scheme, parsed.CCRNKey())
+	}
+	return m.template.Render(parsed.Fields)
+}
+
+// ToCCRN parses the identifier with the most specific matching template and returns the resource
+// with the fields of the template and the ccrn field of the mapped resource type
+func (r *Registry) ToCCRN(id string) (*apis.ParsedResource, error) {
+	if r.validate != nil {
+		if err := r.validate(id); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, m := range r.mappings {
+		fields, ok := m.template.Match(id)
+		if !ok {
+			continue
+		}
+		fields["ccrn"] = m.ccrnKey
+		parsed := &apis.ParsedResource{Format: "CCRN", Fields: fields}
+		parsed.Raw = parsed.CanonicalCCRN()
+		return parsed, nil
+	}
+	return nil, apis.Errorf(apis.ErrUnsupportedType, "no %s mapping matches %s", r.scheme, id)
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = make(map[string]func() *Registry)
+)
+
+func init() {
+	RegisterScheme(SchemeARN, NewARNRegistry)
+	RegisterScheme(SchemeGCP, NewGCPRegistry)
+}
+
+// RegisterScheme makes an identifier scheme available to NewConverter. The factory creates an empty registry
+// of the scheme, typically checking the syntax of identifiers. Schemes declared by CRD annotations without a
+// registered factory get a plain registry. It panics if the scheme is registered twice or the factory is nil.
+func RegisterScheme(scheme string, factory func() *Registry) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if factory == nil {
+		panic("convert: RegisterScheme factory is nil")
+	}
+	if _, exists := schemes[scheme]; exists {
+		panic("convert: RegisterScheme called twice for scheme " + scheme)
+	}
+	schemes[scheme] = factory
+}
+
+// Schemes returns the sorted names of the registered schemes
+func Schemes() []string {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	names := make([]string, 0, len(schemes))
+	for scheme := range schemes {
+		names = append(names, scheme)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Converter holds a registry per scheme and converts identifiers of any of them
+type Converter struct {
+	registries map[string]*Registry
+	order      []string // Sorted schemes, identifiers are matched in this order
+}
+
+// NewConverter creates registries for all registered schemes and registers the templates declared by the CRDs
+func NewConverter(crds ...*apis.CRDInfo) (*Converter, error) {
+	c := &Converter{registries: make(map[string]*Registry)}
+	schemesMu.RLock()
+	for scheme, factory := range schemes {
+		c.registries[scheme] = factory()
+	}
+	schemesMu.RUnlock()
+	for _, crd := range crds {
+		for scheme := range crd.ExternalTemplates {
+			if _, exists := c.registries[scheme]; !exists {
+				c.registries[scheme] = NewRegistry(scheme)
+			}
+		}
+	}
+
+	var errs []error
+	for scheme, registry := range c.registries {
+		c.order = append(c.order, scheme)
+		if err := registry.RegisterCRDs(crds...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	sort.Strings(c.order)
+	return c, errors.Join(errs...)
+}
+
+// Registry returns the registry of the scheme
+func (c *Converter) Registry(scheme string) (*Registry, bool) {
+	registry, ok := c.registries[scheme]
+	return registry, ok
+}
+
+// FromCCRN renders the identifier of the parsed resource in the scheme
+func (c *Converter) FromCCRN(parsed *apis.ParsedResource, scheme string) (string, error) {
+	registry, ok := c.registries[scheme]
+	if !ok {
+		return "", apis.Errorf(apis.ErrUnknownFormat, "unknown identifier scheme %s", scheme)
+	}
+	return registry.FromCCRN(parsed)
+}
+
+// ToCCRN converts an identifier of any scheme to a CCRN and returns the scheme it was matched with.
+// Schemes are tried in alphabetical order, skipping those whose syntax the identifier does not follow.
+func (c *Converter) ToCCRN(id string) (*apis.ParsedResource, string, error) {
+	for _, scheme := range c.order {
+		registry := c.registries[scheme]
+		if registry.validate != nil && registry.validate(id) != nil {
+			continue
+		}
+		parsed, err := registry.ToCCRN(id)
+		if err == nil {
+			return parsed, scheme, nil
+		}
+	}
+	return nil, "", apis.Errorf(apis.ErrUnsupportedType, "no mapping matches %s", id)
+}
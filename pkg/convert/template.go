@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// placeholderPattern matches the <field> placeholders of an identifier template
+var placeholderPattern = regexp.MustCompile(`<([A-Za-z0-9_.-]+)>`)
+
+// Template is an external identifier template like "arn:aws:ec2:<region>:<account>:instance/<name>".
+// Placeholders match up to the next ':' or '/', the last placeholder matches the rest of the identifier,
+// so resource ids may contain slashes.
+type Template struct {
+	Raw     string
+	Fields  []string // Field names in order of appearance
+	pattern *regexp.Regexp
+	literal int // Number of literal characters, more specific templates win
+}
+
+// CompileTemplate compiles an identifier template
+func CompileTemplate(template string) (*Template, error) {
+	matches := placeholderPattern.FindAllStringSubmatchIndex(template, -1)
+	if len(matches) == 0 {
+		return nil, apis.Errorf(apis.ErrInvalidTemplate, "template %s has no placeholders", template)
+	}
+
+	compiled := &Template{Raw: template}
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for i, match := range matches {
+		literal := template[last:match[0]]
+		if i > 0 && literal == "" {
+			return nil, apis.Errorf(apis.ErrInvalidTemplate, "adjacent placeholders in template %s", template)
+		}
+		expr.WriteString(regexp.QuoteMeta(literal))
+		compiled.literal += len(literal)
+
+		field := template[match[2]:match[3]]
+		for _, existing := range compiled.Fields {
+			if existing == field {
+				return nil, apis.Errorf(apis.ErrInvalidTemplate, "duplicate placeholder <%s> in template %s", field, template)
+			}
+		}
+		compiled.Fields = append(compiled.Fields, field)
+		if i == len(matches)-1 {
+			expr.WriteString("(.+)")
+		} else {
+			expr.WriteString("([^:/]+)")
+		}
+		last = match[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	compiled.literal += len(template) - last
+	expr.WriteString("$")
+
+	pattern, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, apis.Errorf(apis.ErrInvalidTemplate, "template %s: %w", template, err)
+	}
+	compiled.pattern = pattern
+	return compiled, nil
+}
+
+// Match extracts the fields from the identifier, it reports false if the identifier does not match
+func (t *Template) Match(id string) (map[string]string, bool) {
+	values := t.pattern.FindStringSubmatch(id)
+	if values == nil {
+		return nil, false
+	}
+	fields := make(map[string]string, len(t.Fields))
+	for i, field := range t.Fields {
+		fields[field] = values[i+1]
+	}
+	return fields, true
+}
+
+// Render fills the placeholders with the fields. All placeholders need a non-empty value, which must
+// not contain ':' or '/' unless it is the last placeholder.
+func (t *Template) Render(fields map[string]string) (string, error) {
+	var missing []string
+	var err error
+	rendered := placeholderPattern.ReplaceAllStringFunc(t.Raw, func(placeholder string) string {
+		field := placeholder[1 : len(placeholder)-1]
+		value := fields[field]
+		if value == "" {
+			missing = append(missing, field)
+		} else if field != t.Fields[len(t.Fields)-1] && strings.ContainsAny(value, ":/") && err == nil {
+			err = fmt.Errorf("value of %s must not contain ':' or '/' in template %s", field, t.Raw)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", apis.Errorf(apis.ErrTemplateMismatch, "unresolved placeholders in template %s: %s", t.Raw, strings.Join(missing, ", "))
+	}
+	if err != nil {
+		return "", apis.Errorf(apis.ErrTemplateMismatch, "%w", err)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package docs generates Markdown reference pages of the CCRN resource types from their CRDs: one page per
+// resource type with the fields, constraints, URN template and an example identifier of every version, and
+// an index linking all pages.
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// IndexFile is the file name of the index page
+const IndexFile = "README.md"
+
+// Page is a generated Markdown page
+type Page struct {
+	File    string // File name relative to the output directory, e.g. "pod.k8s.ccrn.example.com.md"
+	Content []byte
+}
+
+// resourceType are the versions of a resource type, highest version first
+type resourceType struct {
+	name     string // Lowercase "<kind>.<group>"
+	versions []*apis.CRDInfo
+}
+
+// Generate returns the index page followed by the pages of all resource types of the CRD versions, sorted by name
+func Generate(crds []*apis.CRDInfo) []Page {
+	types := groupVersions(crds)
+	pages := []Page{{File: IndexFile, Content: renderIndex(types)}}
+	for _, resourceType := range types {
+		pages = append(pages, Page{File: resourceType.name + ".md", Content: renderType(resourceType)})
+	}
+	return pages
+}
+
+// groupVersions groups the CRD versions by resource type
+func groupVersions(crds []*apis.CRDInfo) []resourceType {
+	byName := map[string]*resourceType{}
+	var names []string
+	for _, crd := range crds {
+		name := strings.ToLower(crd.Kind + "." + crd.Group)
+		if byName[name] == nil {
+			byName[name] = &resourceType{name: name}
+			names = append(names, name)
+		}
+		byName[name].versions = append(byName[name].versions, crd)
+	}
+	sort.Strings(names)
+
+	types := make([]resourceType, 0, len(names))
+	for _, name := range names {
+		versions := byName[name].versions
+		sort.SliceStable(versions, func(i, j int) bool {
+			return version.CompareKubeAwareVersionStrings(versions[i].Version, versions[j].Version) > 0
+		})
+		types = append(types, *byName[name])
+	}
+	return types
+}
+
+func renderIndex(types []resourceType) []byte {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# CCRN Resource Types\n\n")
+	if len(types) == 0 {
+		fmt.Fprintf(&out, "No resource types are defined.\n")
+		return out.Bytes()
+	}
+	fmt.Fprintf(&out, "| Type | Aliases | Versions | Description |\n|---|---|---|---|\n")
+	for _, resourceType := range types {
+		latest := resourceType.versions[0]
+		fmt.Fprintf(&out, "| [%s](%s.md) | %s | %s | %s |\n", resourceType.name, resourceType.name,
+			codeList(latest.Aliases), versionList(resourceType.versions), cell(description(latest)))
+	}
+	return out.Bytes()
+}
+
+func renderType(resourceType resourceType) []byte {
+	latest := resourceType.versions[0]
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# %s\n\n", resourceType.name)
+	if text := description(latest); text != "" {
+		fmt.Fprintf(&out, "%s\n\n", text)
+	}
+	fmt.Fprintf(&out, "| | |\n|---|---|\n")
+	fmt.Fprintf(&out, "| Kind | `%s` |\n| Group | `%s` |\n", latest.Kind, latest.Group)
+	if len(latest.Aliases) > 0 {
+		fmt.Fprintf(&out, "| Aliases | %s |\n", codeList(latest.Aliases))
+	}
+	fmt.Fprintf(&out, "| Versions | %s |\n", versionList(resourceType.versions))
+	if latest.SourceResource != "" {
+		fmt.Fprintf(&out, "| Source resource | `%s` |\n", latest.SourceResource)
+	}
+
+	for _, crd := range resourceType.versions {
+		fmt.Fprintf(&out, "\n## %s\n\n", crd.Version)
+		if message := crd.DeprecationMessage(); message != "" {
+			fmt.Fprintf(&out, "> **Deprecated:** %s\n\n", message)
+		}
+		if crd.URNFormat != "" {
+			fmt.Fprintf(&out, "URN template: `%s`\n\n", crd.URNFormat)
+		} else {
+			fmt.Fprintf(&out, "No URN template, identifiers of this version are CCRNs only.\n\n")
+		}
+
+		fmt.Fprintf(&out, "| Field | Type | Required | Constraints | Description |\n|---|---|---|---|---|\n")
+		for _, field := range crd.Fields() {
+			required := ""
+			if field.Required {
+				required = "yes"
+			}
+			name := field.Name
+			if field.Map {
+				name += ".<key>"
+			}
+			fmt.Fprintf(&out, "| `%s` | %s | %s | %s | %s |\n", name, fieldType(field), required, constraints(field), cell(field.Description))
+		}
+
+		ccrn, urn := Example(crd)
+		fmt.Fprintf(&out, "\n### Example\n\n```\n%s\n", ccrn)
+		if urn != "" {
+			fmt.Fprintf(&out, "%s\n", urn)
+		}
+		fmt.Fprintf(&out, "```\n")
+	}
+	return out.Bytes()
+}
+
+// Example returns an example CCRN of the CRD version with all required fields and the fields of the URN
+// template, and the URN it renders to, which is empty if the version has no URN template or the example
+// values do not fit it. The values are the schema examples, the first enum value other than the wildcard,
+// or the field name.
+func Example(crd *apis.CRDInfo) (string, string) {
+	fields := map[string]string{"ccrn": crd.CCRNKey()}
+	include := crd.RequiredFields()
+	var template *apis.Template
+	if crd.URNFormat != "" {
+		if compiled, err := apis.CompileTemplate(crd.URNFormat); err == nil {
+			template = compiled
+			include = append(include, compiled.Fields()...)
+		}
+	}
+	for _, name := range include {
+		field, declared := crd.Field(name)
+		if name == "ccrn" || !declared || field.Map || field.Type == "object" {
+			continue
+		}
+		fields[name] = exampleValue(field)
+	}
+
+	ccrn := (&apis.ParsedResource{Fields: fields}).CanonicalCCRN()
+	if template == nil {
+		return ccrn, ""
+	}
+	urn, err := template.Render(fields)
+	if err != nil {
+		return ccrn, ""
+	}
+	return ccrn, urn
+}
+
+// exampleValue returns the example value of a field
+func exampleValue(field apis.FieldInfo) string {
+	if field.Example != "" {
+		return field.Example
+	}
+	for _, value := range field.Enum {
+		if value != "*" {
+			return value
+		}
+	}
+	return strings.ReplaceAll(field.Name, ".", "-")
+}
+
+// description returns the description of the resource type in the schema of the CRD version
+func description(crd *apis.CRDInfo) string {
+	if schema := crd.FieldsSchema(); schema != nil {
+		return schema.Description
+	}
+	return ""
+}
+
+// fieldType returns the type of a field, with the item type of arrays and maps
+func fieldType(field apis.FieldInfo) string {
+	switch {
+	case field.Map && field.ItemType != "":
+		return "map of " + field.ItemType
+	case field.Map:
+		return "map"
+	case field.Type == "array" && field.ItemType != "":
+		return "[]" + field.ItemType
+	default:
+		return field.Type
+	}
+}
+
+// constraints returns the enum, pattern and length constraints of a field as table cell
+func constraints(field apis.FieldInfo) string {
+	var parts []string
+	if len(field.Enum) > 0 {
+		parts = append(parts, "one of "+codeList(field.Enum))
+	}
+	if field.Pattern != "" {
+		parts = append(parts, "matches `"+field.Pattern+"`")
+	}
+	switch {
+	case field.MinLength != nil && field.MaxLength != nil:
+		parts = append(parts, fmt.Sprintf("%d to %d characters", *field.MinLength, *field.MaxLength))
+	case field.MinLength != nil:
+		parts = append(parts, fmt.Sprintf("at least %d characters", *field.MinLength))
+	case field.MaxLength != nil:
+		parts = append(parts, fmt.Sprintf("at most %d characters", *field.MaxLength))
+	}
+	return cell(strings.Join(parts, ", "))
+}
+
+// versionList returns the versions as table cell, marking deprecated versions
+func versionList(versions []*apis.CRDInfo) string {
+	names := make([]string, 0, len(versions))
+	for _, crd := range versions {
+		name := "`" + crd.Version + "`"
+		if crd.Deprecated {
+			name += " (deprecated)"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// codeList returns the values as comma-separated list of code spans
+func codeList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, "`"+value+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// cell escapes text for a Markdown table cell
+func cell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(strings.TrimSpace(text))
+}
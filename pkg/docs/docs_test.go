@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package docs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/docs"
+)
+
+func TestDocs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Docs Suite")
+}
+
+var _ = Describe("Generate", func() {
+	maxLength := int64(63)
+	schema := func(description string) *apiextensionsv1.JSONSchemaProps {
+		return &apiextensionsv1.JSONSchemaProps{
+			Type:        "object",
+			Description: description,
+			Required:    []string{"ccrn", "region", "name"},
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"ccrn":   {Type: "string"},
+				"region": {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"*"`)}, {Raw: []byte(`"eu-de"`)}}},
+				"name": {Type: "string", Pattern: "^[a-z|-]+$", MaxLength: &maxLength, Description: "Name of\nthe bucket",
+					Example: &apiextensionsv1.JSON{Raw: []byte(`"backups"`)}},
+				"tags": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+			},
+		}
+	}
+	bucketV1 := &apis.CRDInfo{Kind: "Bucket", Group: "storage.ccrn.example.com", Version: "v1", Schema: schema("Object storage bucket"),
+		URNFormat: "urn:ccrn:<ccrn>/<region>/[<project>]/<name>", Aliases: []string{"bucket", "bk"}}
+	bucketV1beta1 := &apis.CRDInfo{Kind: "Bucket", Group: "storage.ccrn.example.com", Version: "v1beta1", Schema: schema("Bucket"), Deprecated: true}
+	volume := &apis.CRDInfo{Kind: "volume", Group: "storage.ccrn.example.com", Version: "v1", Schema: schema("Block storage volume")}
+
+	It("generates an index and one page per resource type", func() {
+		// Act
+		pages := docs.Generate([]*apis.CRDInfo{volume, bucketV1beta1, bucketV1})
+		// Assert
+		files := make([]string, 0, len(pages))
+		for _, page := range pages {
+			files = append(files, page.File)
+		}
+		Expect(files).To(Equal([]string{docs.IndexFile, "bucket.storage.ccrn.example.com.md", "volume.storage.ccrn.example.com.md"}))
+		Expect(string(pages[0].Content)).To(ContainSubstring(
+			"| [bucket.storage.ccrn.example.com](bucket.storage.ccrn.example.com.md) | `bucket`, `bk` | `v1`, `v1beta1` (deprecated) | Object storage bucket |\n"))
+	})
+
+	It("documents the versions of a resource type, highest first", func() {
+		// Act
+		page := string(docs.Generate([]*apis.CRDInfo{bucketV1beta1, bucketV1})[1].Content)
+		// Assert
+		Expect(page).To(HavePrefix("# bucket.storage.ccrn.example.com\n\nObject storage bucket\n"))
+		Expect(page).To(ContainSubstring("| Aliases | `bucket`, `bk` |\n"))
+		Expect(page).To(MatchRegexp("(?s)## v1\n.*## v1beta1\n\n> \\*\\*Deprecated:\\*\\* bucket.storage.ccrn.example.com/v1beta1 is deprecated\n"))
+		Expect(page).To(ContainSubstring("URN template: `urn:ccrn:<ccrn>/<region>/[<project>]/<name>`\n"))
+		Expect(page).To(ContainSubstring("No URN template, identifiers of this version are CCRNs only.\n"))
+	})
+
+	It("describes the fields with their types and constraints", func() {
+		// Act
+		page := string(docs.Generate([]*apis.CRDInfo{bucketV1})[1].Content)
+		// Assert
+		Expect(page).To(ContainSubstring("| `name` | string | yes | matches `^[a-z\\|-]+$`, at most 63 characters | Name of the bucket |\n"))
+		Expect(page).To(ContainSubstring("| `region` | string | yes | one of `*`, `eu-de` |  |\n"))
+		Expect(page).To(ContainSubstring("| `tags` | []string |  |  |  |\n"))
+	})
+
+	It("gives an example CCRN and URN", func() {
+		// Act
+		ccrn, urn := docs.Example(bucketV1)
+		// Assert
+		Expect(ccrn).To(Equal("ccrn=bucket.storage.ccrn.example.com/v1, name=backups, region=eu-de"))
+		Expect(urn).To(Equal("urn:ccrn:bucket.storage.ccrn.example.com/v1/eu-de/backups"))
+	})
+
+	It("gives no example URN without URN template", func() {
+		// Act
+		ccrn, urn := docs.Example(volume)
+		// Assert
+		Expect(ccrn).To(Equal("ccrn=volume.storage.ccrn.example.com/v1, name=backups, region=eu-de"))
+		Expect(urn).To(BeEmpty())
+	})
+})
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package drift detects CCRN resources whose identified objects no longer exist. The reconciler periodically
+// looks up the target of every CCRN resource in the source resource of its CRD (ccrn/source-resource) and
+// records the result in the TargetFound condition of the resource, so orphaned identifiers can be cleaned up.
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/generate"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/sweep"
+)
+
+// ConditionTargetFound is the condition type reporting whether the target of a CCRN resource exists
+const ConditionTargetFound = "TargetFound"
+
+// Reasons of the TargetFound condition
+const (
+	ReasonTargetFound    = "TargetFound"    // The target exists
+	ReasonTargetNotFound = "TargetNotFound" // The target does not exist, the identifier is orphaned
+	ReasonTargetUnknown  = "TargetUnknown"  // The identifier is invalid or does not locate a single object
+)
+
+// Locator reads the live object identified by a CCRN, it is implemented by generate.Generator.
+// Missing objects are returned as NotFound errors of the API server.
+type Locator interface {
+	Target(ctx context.Context, parsed *apis.ParsedResource) (map[string]interface{}, error)
+}
+
+// Report is the result of a reconciliation
+type Report struct {
+	Objects  int            `json:"objects"`  // Number of checked CCRN resources
+	Found    int            `json:"found"`    // Number of resources whose target exists
+	Orphaned int            `json:"orphaned"` // Number of resources whose target does not exist
+	Unknown  int            `json:"unknown"`  // Number of resources whose target could not be checked
+	Orphans  []sweep.Object `json:"orphans"`  // Orphaned resources sorted by object
+}
+
+// Reconciler checks the targets of the CCRN resources of the resource types of a backend
+type Reconciler struct {
+	log       *logrus.Logger
+	backend   apis.ValidationBackend
+	validator sweep.Validator
+	locator   Locator
+	client    dynamic.Interface
+}
+
+// NewReconciler creates a reconciler listing the CCRN resources of the CRDs of the backend with the client,
+// the backend must implement apis.CRDLister
+func NewReconciler(log *logrus.Logger, backend apis.ValidationBackend, validator sweep.Validator, locator Locator, client dynamic.Interface) *Reconciler {
+	return &Reconciler{log: log, backend: backend, validator: validator, locator: locator, client: client}
+}
+
+// NewReconcilerForConfig creates a reconciler reading the CCRN resources and their targets from the cluster of the config
+func NewReconcilerForConfig(log *logrus.Logger, backend apis.ValidationBackend, validator sweep.Validator, config *rest.Config) (*Reconciler, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	generator, err := generate.NewGeneratorForConfig(backend, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewReconciler(log, backend, validator, generator, client), nil
+}
+
+// Run reconciles immediately and then at every interval until the context is done. Failed reconciliations
+// are logged and retried at the next interval.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := r.Reconcile(ctx)
+		if err != nil {
+			r.log.Errorf("Failed to detect drift of CCRN resources: %v", err)
+		} else {
+			r.log.Infof("Checked the targets of %d CCRN resources: %d found, %d orphaned, %d unknown",
+				report.Objects, report.Found, report.Orphaned, report.Unknown)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks the targets of the CCRN resources of all namespaces and updates their TargetFound
+// condition. Every resource type is listed once in its highest version, resource types not installed
+// in the cluster are skipped.
+func (r *Reconciler) Reconcile(ctx context.Context) (*Report, error) {
+	lister, ok := r.backend.(apis.CRDLister)
+	if !ok {
+		return nil, fmt.Errorf("backend %T cannot list its resource types", r.backend)
+	}
+
+	report := &Report{Orphans: []sweep.Object{}}
+	orphaned := map[string]int{}
+	for _, crd := range apis.HighestVersions(lister.ListCRDs()) {
+		gvr := schema.GroupVersionResource{Group: crd.Group, Version: crd.Version, Resource: crd.Plural}
+		list, err := r.client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.GroupResource(), err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			condition := r.check(ctx, item)
+			report.Objects++
+			switch condition.Reason {
+			case ReasonTargetFound:
+				report.Found++
+			case ReasonTargetNotFound:
+				report.Orphaned++
+				orphaned[gvr.GroupResource().String()]++
+				report.Orphans = append(report.Orphans, sweep.Object{
+					Resource: gvr.GroupResource().String(), Namespace: item.GetNamespace(), Name: item.GetName()})
+			default:
+				report.Unknown++
+			}
+			if err := r.setCondition(ctx, gvr, item, condition); err != nil {
+				r.log.Warnf("Failed to update the %s condition of %s %s/%s: %v",
+					ConditionTargetFound, gvr.GroupResource(), item.GetNamespace(), item.GetName(), err)
+			}
+		}
+	}
+
+	sort.Slice(report.Orphans, func(i, j int) bool {
+		return report.Orphans[i].String() < report.Orphans[j].String()
+	})
+	recordOrphans(orphaned)
+	return report, nil
+}
+
+// check returns the TargetFound condition of the CCRN resource
+func (r *Reconciler) check(ctx context.Context, item *unstructured.Unstructured) metav1.Condition {
+	condition := metav1.Condition{Type: ConditionTargetFound, Status: metav1.ConditionUnknown, Reason: ReasonTargetUnknown}
+
+	var identifier string
+	for _, field := range []string{"ccrn", "urn"} {
+		if value, _, _ := unstructured.NestedString(item.Object, "spec", field); value != "" {
+			identifier = value
+			break
+		}
+	}
+	if identifier == "" {
+		condition.Message = "resource must have either spec.ccrn or spec.urn defined"
+		return condition
+	}
+	result, err := r.validator.ValidateCCRNContext(ctx, identifier)
+	if err != nil || !result.Valid || result.ParsedCCRN == nil {
+		messages := apis.ErrorMessages(err)
+		if result != nil {
+			messages = append(messages, result.Errors...)
+		}
+		condition.Message = "invalid identifier: " + strings.Join(messages, "; ")
+		return condition
+	}
+
+	_, err = r.locator.Target(ctx, result.ParsedCCRN)
+	switch {
+	case err == nil:
+		condition.Status, condition.Reason = metav1.ConditionTrue, ReasonTargetFound
+		condition.Message = "the identified object exists"
+	case apierrors.IsNotFound(err):
+		condition.Status, condition.Reason = metav1.ConditionFalse, ReasonTargetNotFound
+		condition.Message = "the identified object does not exist, the identifier is orphaned"
+	default:
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// setCondition updates the condition of the CCRN resource if it changed. The status subresource is used if
+// the CRD declares it, otherwise the resource itself is patched.
+func (r *Reconciler) setCondition(ctx context.Context, gvr schema.GroupVersionResource, item *unstructured.Unstructured, condition metav1.Condition) error {
+	var conditions []metav1.Condition
+	if existing, found, _ := unstructured.NestedSlice(item.Object, "status", "conditions"); found {
+		for _, value := range existing {
+			content, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var parsed metav1.Condition
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &parsed); err == nil {
+				conditions = append(conditions, parsed)
+			}
+		}
+	}
+	condition.ObservedGeneration = item.GetGeneration()
+	if !meta.SetStatusCondition(&conditions, condition) {
+		return nil
+	}
+	if current := meta.FindStatusCondition(conditions, ConditionTargetFound); current.Reason == ReasonTargetNotFound {
+		r.log.Warnf("Target of %s %s/%s not found, the identifier is orphaned", gvr.GroupResource(), item.GetNamespace(), item.GetName())
+	}
+
+	patch, err := json.Marshal(map[string]any{"status": map[string]any{"conditions": conditions}})
+	if err != nil {
+		return err
+	}
+	resource := r.client.Resource(gvr).Namespace(item.GetNamespace())
+	_, err = resource.Patch(ctx, item.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if apierrors.IsNotFound(err) {
+		_, err = resource.Patch(ctx, item.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
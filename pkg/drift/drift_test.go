@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package drift_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/drift"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/generate"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestDrift(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Drift Suite")
+}
+
+// object returns an object of the kind with the spec
+func object(apiVersion, kind, namespace, name string, spec map[string]interface{}) runtime.Object {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+var _ = Describe("Reconciler", func() {
+	const podVersion = "k8s-registry.tr.ccrn.example.com/v1"
+	ccrnPods := schema.GroupVersionResource{Group: "k8s-registry.tr.ccrn.example.com", Version: "v1", Resource: "pods"}
+	var (
+		client     *dynamicfake.FakeDynamicClient
+		reconciler *drift.Reconciler
+	)
+
+	// condition returns the TargetFound condition of the CCRN resource
+	condition := func(namespace, name string) *metav1.Condition {
+		item, err := client.Resource(ccrnPods).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		existing, _, err := unstructured.NestedSlice(item.Object, "status", "conditions")
+		Expect(err).ToNot(HaveOccurred())
+		var conditions []metav1.Condition
+		for _, value := range existing {
+			var parsed metav1.Condition
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(value.(map[string]interface{}), &parsed)).To(Succeed())
+			conditions = append(conditions, parsed)
+		}
+		return meta.FindStatusCondition(conditions, drift.ConditionTargetFound)
+	}
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+
+		client = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				ccrnPods:                          "podList",
+				{Version: "v1", Resource: "pods"}: "PodList",
+			},
+			object("v1", "Pod", "default", "web", map[string]interface{}{"nodeName": "node-1"}),
+			object(podVersion, "pod", "default", "web", map[string]interface{}{
+				"ccrn": "ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web"}),
+			object(podVersion, "pod", "default", "gone", map[string]interface{}{
+				"urn": "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/gone"}),
+			object(podVersion, "pod", "shop", "broken", map[string]interface{}{
+				"ccrn": "ccrn=pod/v1, cluster=us-west-1, namespace=shop, name=broken"}),
+		)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+		generator := generate.NewGenerator(backend, client, mapper)
+		reconciler = drift.NewReconciler(logrus.New(), backend, validation.NewCCRNValidator(backend), generator, client)
+	})
+
+	It("sets the TargetFound condition of every CCRN resource", func() {
+		// Act
+		report, err := reconciler.Reconcile(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Objects).To(Equal(3))
+		Expect(report.Found).To(Equal(1))
+		Expect(report.Orphaned).To(Equal(1))
+		Expect(report.Unknown).To(Equal(1))
+		Expect(report.Orphans).To(HaveLen(1))
+		Expect(report.Orphans[0].String()).To(Equal("pods.k8s-registry.tr.ccrn.example.com/default/gone"))
+
+		Expect(condition("default", "web").Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition("default", "gone").Reason).To(Equal(drift.ReasonTargetNotFound))
+		Expect(condition("shop", "broken").Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition("shop", "broken").Message).To(ContainSubstring("invalid identifier"))
+	})
+
+	It("only updates changed conditions", func() {
+		// Arrange
+		_, err := reconciler.Reconcile(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		client.ClearActions()
+		// Act
+		_, err = reconciler.Reconcile(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		for _, action := range client.Actions() {
+			Expect(action.GetVerb()).ToNot(Equal("patch"))
+		}
+	})
+
+	It("marks a resource orphaned once its target is deleted", func() {
+		// Arrange
+		_, err := reconciler.Reconcile(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		Expect(client.Resource(pods).Namespace("default").Delete(context.Background(), "web", metav1.DeleteOptions{})).To(Succeed())
+		// Act
+		report, err := reconciler.Reconcile(context.Background())
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Orphaned).To(Equal(2))
+		Expect(condition("default", "web").Reason).To(Equal(drift.ReasonTargetNotFound))
+	})
+})
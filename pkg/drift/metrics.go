@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package drift
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var orphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "ccrn_orphaned_resources",
+	Help: "Number of CCRN resources whose target object did not exist at the last drift check, by resource.",
+}, []string{"resource"})
+
+// Collector returns the collector of the orphaned resources, it is registered by the binary running the
+// Reconciler
+func Collector() prometheus.Collector {
+	return orphanedResources
+}
+
+// recordOrphans replaces the orphaned resources of the previous check
+func recordOrphans(orphaned map[string]int) {
+	orphanedResources.Reset()
+	for resource, count := range orphaned {
+		orphanedResources.WithLabelValues(resource).Set(float64(count))
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package featuregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is the name of a feature that can be turned on or off
+type Feature string
+
+// Stage describes the maturity of a feature
+type Stage string
+
+const (
+	Alpha Stage = "ALPHA" // Experimental, off by default
+	Beta  Stage = "BETA"  // Well tested, usually on by default
+	GA    Stage = "GA"    // Always on, the gate will be removed
+)
+
+// FeatureSpec describes the default and maturity of a feature
+type FeatureSpec struct {
+	Default bool
+	Stage   Stage
+}
+
+// FeatureGate tracks which features are enabled. It implements flag.Value,
+// so it can be set via a flag like --feature-gates=CanonicalMutation=true,...
+type FeatureGate struct {
+	mutex   sync.RWMutex
+	known   map[Feature]FeatureSpec
+	enabled map[Feature]bool
+}
+
+// NewFeatureGate creates a feature gate for the known features
+func NewFeatureGate(known map[Feature]FeatureSpec) *FeatureGate {
+	return &FeatureGate{
+		known:   known,
+		enabled: map[Feature]bool{},
+	}
+}
+
+// Enabled reports whether the feature is enabled, unknown features are disabled
+func (g *FeatureGate) Enabled(feature Feature) bool {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if enabled, ok := g.enabled[feature]; ok {
+		return enabled
+	}
+	return g.known[feature].Default
+}
+
+// SetFromMap enables or disables the given features
+func (g *FeatureGate) SetFromMap(features map[string]bool) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for name, enabled := range features {
+		spec, ok := g.known[Feature(name)]
+		if !ok {
+			return fmt.Errorf("unknown feature gate %s", name)
+		}
+		if spec.Stage == GA && !enabled {
+			return fmt.Errorf("feature gate %s is GA and cannot be disabled", name)
+		}
+		g.enabled[Feature(name)] = enabled
+	}
+	return nil
+}
+
+// Set parses a comma separated list of Feature=bool pairs, it implements flag.Value
+func (g *FeatureGate) Set(value string) error {
+	features := map[string]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, rawEnabled, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("missing bool value for feature gate %s", name)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(rawEnabled))
+		if err != nil {
+			return fmt.Errorf("invalid value %s for feature gate %s: %w", rawEnabled, name, err)
+		}
+		features[strings.TrimSpace(name)] = enabled
+	}
+	return g.SetFromMap(features)
+}
+
+// String returns the explicitly set features as comma separated Feature=bool pairs, it implements flag.Value
+func (g *FeatureGate) String() string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	pairs := make([]string, 0, len(g.enabled))
+	for feature, enabled := range g.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", feature, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// KnownFeatures returns a description of all known features, suitable for flag usage strings
+func (g *FeatureGate) KnownFeatures() []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	known := make([]string, 0, len(g.known))
+	for feature, spec := range g.known {
+		known = append(known, fmt.Sprintf("%s=true|false (%s - default=%t)", feature, spec.Stage, spec.Default))
+	}
+	sort.Strings(known)
+	return known
+}
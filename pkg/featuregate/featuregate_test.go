@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package featuregate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/featuregate"
+)
+
+func TestFeatureGate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FeatureGate Suite")
+}
+
+var _ = Describe("FeatureGate", func() {
+	const (
+		alphaFeature featuregate.Feature = "AlphaFeature"
+		betaFeature  featuregate.Feature = "BetaFeature"
+		gaFeature    featuregate.Feature = "GAFeature"
+	)
+
+	var gate *featuregate.FeatureGate
+
+	BeforeEach(func() {
+		gate = featuregate.NewFeatureGate(map[featuregate.Feature]featuregate.FeatureSpec{
+			alphaFeature: {Default: false, Stage: featuregate.Alpha},
+			betaFeature:  {Default: true, Stage: featuregate.Beta},
+			gaFeature:    {Default: true, Stage: featuregate.GA},
+		})
+	})
+
+	It("uses the defaults of the features", func() {
+		// Assert
+		Expect(gate.Enabled(alphaFeature)).To(BeFalse())
+		Expect(gate.Enabled(betaFeature)).To(BeTrue())
+		Expect(gate.Enabled("Unknown")).To(BeFalse())
+	})
+
+	It("parses comma separated Feature=bool pairs", func() {
+		// Act
+		err := gate.Set("AlphaFeature=true, BetaFeature=false")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gate.Enabled(alphaFeature)).To(BeTrue())
+		Expect(gate.Enabled(betaFeature)).To(BeFalse())
+		Expect(gate.String()).To(Equal("AlphaFeature=true,BetaFeature=false"))
+	})
+
+	It("rejects unknown features", func() {
+		// Act
+		err := gate.Set("Unknown=true")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("unknown feature gate")))
+	})
+
+	It("rejects invalid values", func() {
+		// Act
+		err := gate.Set("AlphaFeature=maybe")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("does not allow disabling GA features", func() {
+		// Act
+		err := gate.Set("GAFeature=false")
+		// Assert
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package featuregate
+
+const (
+	// CanonicalMutation rewrites spec.ccrn of admitted objects into the canonical CCRN form
+	CanonicalMutation Feature = "CanonicalMutation"
+
+	// HomoglyphScreening rejects values of identifier fields mixing scripts or consisting of letters
+	// looking like Latin ones, see apis.CheckHomoglyphs
+	HomoglyphScreening Feature = "HomoglyphScreening"
+
+	// LabelMutation sets the ccrn/* labels identifying the referenced resource on admitted objects,
+	// so they can be listed with the label selector of a CCRN pattern
+	LabelMutation Feature = "LabelMutation"
+
+	// StrictParsing rejects CCRN fields not declared in the schema of the resource type at parse time
+	StrictParsing Feature = "StrictParsing"
+
+	// SunsetEnforcement rejects CCRNs referencing versions past the sunset date of their
+	// ccrn/<version>.deprecated annotation, earlier they are admitted with a deprecation warning
+	SunsetEnforcement Feature = "SunsetEnforcement"
+)
+
+// defaultFeatures lists all known features with their defaults
+var defaultFeatures = map[Feature]FeatureSpec{
+	CanonicalMutation:  {Default: false, Stage: Alpha},
+	HomoglyphScreening: {Default: false, Stage: Alpha},
+	LabelMutation:      {Default: false, Stage: Alpha},
+	StrictParsing:      {Default: false, Stage: Alpha},
+	SunsetEnforcement:  {Default: false, Stage: Alpha},
+}
+
+// Default is the feature gate of the running binary, set via --feature-gates
+var Default = NewFeatureGate(defaultFeatures)
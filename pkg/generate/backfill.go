@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Backfill modes
+const (
+	ModeApply  = "apply"   // Annotate the objects
+	ModeDryRun = "dry-run" // Send the annotations as server-side dry run, nothing is persisted
+	ModeReport = "report"  // Only report the objects lacking the annotation, nothing is sent
+)
+
+// Backfill results of an object
+const (
+	ResultAnnotated = "annotated" // The annotation was added, or would be added in dry run
+	ResultMissing   = "missing"   // The object lacks the annotation, reported in report mode
+	ResultExisting  = "existing"  // The object already carries an annotation, which is kept
+	ResultFailed    = "failed"    // No valid CCRN could be derived or the annotation could not be written
+)
+
+// BackfillResult is the result of an object of the cluster
+type BackfillResult struct {
+	Resource   string `json:"resource"` // Resource and group, e.g. "pods" or "deployments.apps"
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Result     string `json:"result"`               // ResultAnnotated, ResultMissing, ResultExisting or ResultFailed
+	Identifier string `json:"identifier,omitempty"` // The derived CCRN, or the existing annotation
+	Error      string `json:"error,omitempty"`
+}
+
+// String returns the result as "<resource>/<namespace>/<name>: <result>: <identifier or error>"
+func (r BackfillResult) String() string {
+	object := r.Resource + "/" + r.Name
+	if r.Namespace != "" {
+		object = r.Resource + "/" + r.Namespace + "/" + r.Name
+	}
+	if r.Error != "" {
+		return fmt.Sprintf("%s: %s: %s", object, r.Result, r.Error)
+	}
+	return fmt.Sprintf("%s: %s: %s", object, r.Result, r.Identifier)
+}
+
+// BackfillReport is the result of a backfill
+type BackfillReport struct {
+	Mode      string           `json:"mode"`
+	Objects   int              `json:"objects"`
+	Annotated int              `json:"annotated"`
+	Missing   int              `json:"missing"`
+	Existing  int              `json:"existing"`
+	Failed    int              `json:"failed"`
+	Results   []BackfillResult `json:"results"` // Results sorted by object
+}
+
+// add adds a result to the report and counts it
+func (r *BackfillReport) add(result BackfillResult) {
+	r.Results = append(r.Results, result)
+	r.Objects++
+	switch result.Result {
+	case ResultAnnotated:
+		r.Annotated++
+	case ResultMissing:
+		r.Missing++
+	case ResultExisting:
+		r.Existing++
+	case ResultFailed:
+		r.Failed++
+	}
+}
+
+// Backfill retrofits identifiers onto the existing objects of the resource types, e.g. "pod" or "deployment/v1":
+// the objects of their source resources in the namespace, in all namespaces if it is empty, are annotated with
+// their CCRN in apis.IdentifierAnnotation. The fields set values the objects do not provide, like the cluster.
+// Existing annotations are kept, cluster-scoped resources are only listed in all namespaces.
+func (g *Generator) Backfill(ctx context.Context, resourceTypes []string, namespace string, fields map[string]string, mode string) (*BackfillReport, error) {
+	switch mode {
+	case ModeApply, ModeDryRun, ModeReport:
+	default:
+		return nil, fmt.Errorf("invalid backfill mode %q, use %s, %s or %s", mode, ModeApply, ModeDryRun, ModeReport)
+	}
+
+	report := &BackfillReport{Mode: mode, Results: []BackfillResult{}}
+	for _, resourceType := range resourceTypes {
+		crdInfo, err := g.crd(resourceType)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.backfill(ctx, report, crdInfo, namespace, fields, mode); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return report.Results[i].String() < report.Results[j].String()
+	})
+	return report, nil
+}
+
+// backfill annotates the objects of the source resource of the CRD
+func (g *Generator) backfill(ctx context.Context, report *BackfillReport, crdInfo *apis.CRDInfo, namespace string, fields map[string]string, mode string) error {
+	mapping, err := g.sourceMapping(crdInfo)
+	if err != nil {
+		return err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace && namespace != "" {
+		return nil
+	}
+	resource := g.client.Resource(mapping.Resource)
+	list, err := resource.Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", mapping.Resource.GroupResource(), err)
+	}
+
+	for i := range list.Items {
+		item := &list.Items[i]
+		result := BackfillResult{Resource: mapping.Resource.GroupResource().String(), Namespace: item.GetNamespace(), Name: item.GetName()}
+		if existing, ok := item.GetAnnotations()[apis.IdentifierAnnotation]; ok {
+			result.Result, result.Identifier = ResultExisting, existing
+			report.add(result)
+			continue
+		}
+
+		parsed, err := crdInfo.ResourceFromObject(item.Object, fields)
+		if err != nil {
+			result.Result, result.Error = ResultFailed, strings.Join(apis.ErrorMessages(err), "; ")
+			report.add(result)
+			continue
+		}
+		result.Result, result.Identifier = ResultAnnotated, parsed.Raw
+		if mode == ModeReport {
+			result.Result = ResultMissing
+			report.add(result)
+			continue
+		}
+
+		patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": map[string]string{apis.IdentifierAnnotation: parsed.Raw}}})
+		if err != nil {
+			return err
+		}
+		options := metav1.PatchOptions{}
+		if mode == ModeDryRun {
+			options.DryRun = []string{metav1.DryRunAll}
+		}
+		if _, err := resource.Namespace(item.GetNamespace()).Patch(ctx, item.GetName(), types.MergePatchType, patch, options); err != nil {
+			result.Result, result.Error = ResultFailed, fmt.Sprintf("failed to annotate: %v", err)
+		}
+		report.add(result)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package generate derives CCRNs from live Kubernetes objects. The CCRN CRDs declare which resource
+// the identified objects are (ccrn/source-resource) and where their fields are found in the objects
+// (ccrn/<version>.object-mapping), see apis.CRDInfo.ResourceFromObject.
+package generate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// Generator reads live objects and derives their CCRNs
+type Generator struct {
+	backend apis.ValidationBackend
+	parser  *parser.ResourceParser
+	client  dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// NewGenerator creates a generator looking up CCRN CRDs in the backend and reading the objects with the client,
+// the mapper resolves the source resources of the CRDs
+func NewGenerator(backend apis.ValidationBackend, client dynamic.Interface, mapper meta.RESTMapper) *Generator {
+	return &Generator{
+		backend: backend,
+		parser:  parser.NewResourceParser(nil, backend),
+		client:  client,
+		mapper:  mapper,
+	}
+}
+
+// NewGeneratorForConfig creates a generator reading the objects from the cluster of the config
+func NewGeneratorForConfig(backend apis.ValidationBackend, config *rest.Config) (*Generator, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return NewGenerator(backend, client, mapper), nil
+}
+
+// Generate reads the object of the CCRN resource type, e.g. "pod", "pod/v1" or "pod.k8s-registry.ccrn.example.com/v1",
+// and returns its CCRN. The namespace is ignored for cluster-scoped resources. The fields set values the object
+// does not provide, like the cluster. If the derived CCRN violates the schema it is returned together with the error.
+func (g *Generator) Generate(ctx context.Context, resourceType, namespace, name string, fields map[string]string) (*apis.ParsedResource, error) {
+	crdInfo, err := g.crd(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := g.get(ctx, crdInfo, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return crdInfo.ResourceFromObject(object, fields)
+}
+
+// crd returns the CRD version of the resource type, the latest version if the type has none
+func (g *Generator) crd(resourceType string) (*apis.CRDInfo, error) {
+	key := resourceType
+	if !strings.Contains(key, "/") {
+		key += "/" + apis.LatestVersion
+	}
+	key = g.parser.ResolveKey(key)
+	crdInfo, err := g.backend.GetCRD(key)
+	if err != nil {
+		return nil, apis.Errorf(apis.ErrCRDNotFound, "no CCRN definition for %s: %v", resourceType, err)
+	}
+	return crdInfo, nil
+}
+
+// get reads the live object from the source resource of the CRD
+func (g *Generator) get(ctx context.Context, crdInfo *apis.CRDInfo, namespace, name string) (map[string]interface{}, error) {
+	mapping, err := g.sourceMapping(crdInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := g.client.Resource(mapping.Resource)
+	var getter dynamic.ResourceInterface = resource
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		getter = resource.Namespace(namespace)
+	}
+	object, err := getter.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", mapping.Resource.Resource, name, err)
+	}
+	return object.Object, nil
+}
+
+// Target reads the live object identified by the CCRN from the source resource of its CRD. The object is
+// located by the fields mapped to metadata.name and metadata.namespace, see apis.CRDInfo.ObjectMappingOrDefault.
+// A missing object is returned as a NotFound error of the API server.
+func (g *Generator) Target(ctx context.Context, parsed *apis.ParsedResource) (map[string]interface{}, error) {
+	crdInfo, err := g.backend.GetCRD(parsed.CCRNKey())
+	if err != nil {
+		return nil, apis.Errorf(apis.ErrCRDNotFound, "no CCRN definition for %s: %v", parsed.CCRNKey(), err)
+	}
+
+	var name, namespace string
+	for field, path := range crdInfo.ObjectMappingOrDefault() {
+		switch path {
+		case "metadata.name":
+			name = parsed.Fields[field]
+		case "metadata.namespace":
+			namespace = parsed.Fields[field]
+		}
+	}
+	if name == "" || name == apis.Wildcard || namespace == apis.Wildcard {
+		return nil, apis.Errorf(apis.ErrUnsupportedType, "%s does not identify a single object of its source resource", parsed.CCRNKey())
+	}
+	return g.get(ctx, crdInfo, namespace, name)
+}
+
+// sourceMapping resolves the source resource of the CRD, see apis.CRDInfo.SourceResource
+func (g *Generator) sourceMapping(crdInfo *apis.CRDInfo) (*meta.RESTMapping, error) {
+	source := crdInfo.SourceResource
+	if source == "" {
+		source = strings.ToLower(crdInfo.Kind)
+	}
+	gvr, err := g.mapper.ResourceFor(schema.ParseGroupResource(source).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource %s of %s: %w", source, crdInfo.CCRNKey(), err)
+	}
+	gvk, err := g.mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind of resource %s: %w", gvr, err)
+	}
+	mapping, err := g.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource %s: %w", gvr, err)
+	}
+	return mapping, nil
+}
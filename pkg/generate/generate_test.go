@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package generate_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/generate"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestGenerate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Generate Suite")
+}
+
+var _ = Describe("Generator", func() {
+	var generator *generate.Generator
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "minimal_crd.yaml"))).To(Succeed())
+
+		pod := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      "my-pod",
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": "shop"},
+			},
+			"spec": map[string]interface{}{"nodeName": "node-1"},
+		}}
+		podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{podGVR: "PodList"}, pod)
+
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+		generator = generate.NewGenerator(backend, client, mapper)
+	})
+
+	It("derives the CCRN of a live object via the object mapping", func() {
+		// Act
+		parsed, err := generator.Generate(context.Background(), "pod", "default", "my-pod", map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.CanonicalCCRN()).To(Equal("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, labels.app=shop, name=my-pod, namespace=default, nodeName=node-1"))
+		Expect(apis.BuildURN(parsed, "")).To(Equal("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+	})
+
+	It("accepts full CCRN keys", func() {
+		// Act
+		parsed, err := generator.Generate(context.Background(), "pod.k8s-registry.tr.ccrn.example.com/v1", "default", "my-pod", map[string]string{"cluster": "eu-de-1"})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Fields["name"]).To(Equal("my-pod"))
+	})
+
+	It("returns the CCRN together with its schema violations", func() {
+		// Act
+		parsed, err := generator.Generate(context.Background(), "pod", "default", "my-pod", map[string]string{"cluster": "us-west-1"})
+		// Assert
+		Expect(errors.Is(err, apis.ErrSchemaViolation)).To(BeTrue())
+		Expect(parsed).ToNot(BeNil())
+	})
+
+	It("fails for objects that do not exist", func() {
+		// Act
+		_, err := generator.Generate(context.Background(), "pod", "kube-system", "my-pod", nil)
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to get pods my-pod")))
+	})
+
+	It("fails for unknown resource types", func() {
+		// Act
+		_, err := generator.Generate(context.Background(), "volume", "default", "my-pod", nil)
+		// Assert
+		Expect(errors.Is(err, apis.ErrCRDNotFound)).To(BeTrue())
+	})
+
+	It("fails when the source resource is not served by the cluster", func() {
+		// Act
+		_, err := generator.Generate(context.Background(), "testresource.tr.ccrn.example.com/v1", "default", "my-pod", nil)
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to resolve resource testresource")))
+	})
+
+	It("reads the target object of a CCRN", func() {
+		// Arrange
+		parsed := &apis.ParsedResource{Fields: map[string]string{
+			"ccrn": "pod.k8s-registry.tr.ccrn.example.com/v1", "cluster": "eu-de-1", "namespace": "default", "name": "my-pod"}}
+		// Act
+		object, err := generator.Target(context.Background(), parsed)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(object["spec"]).To(HaveKeyWithValue("nodeName", "node-1"))
+	})
+
+	It("returns NotFound for missing targets and rejects wildcard names", func() {
+		// Arrange
+		parsed := &apis.ParsedResource{Fields: map[string]string{
+			"ccrn": "pod.k8s-registry.tr.ccrn.example.com/v1", "cluster": "eu-de-1", "namespace": "shop", "name": "my-pod"}}
+		// Act
+		_, err := generator.Target(context.Background(), parsed)
+		parsed.Fields["name"] = apis.Wildcard
+		_, wildcardErr := generator.Target(context.Background(), parsed)
+		// Assert
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(errors.Is(wildcardErr, apis.ErrUnsupportedType)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Backfill", func() {
+	var (
+		generator *generate.Generator
+		client    *dynamicfake.FakeDynamicClient
+	)
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	pod := func(namespace, name string, annotations map[string]interface{}) *unstructured.Unstructured {
+		metadata := map[string]interface{}{"name": name, "namespace": namespace}
+		if annotations != nil {
+			metadata["annotations"] = annotations
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Pod", "metadata": metadata}}
+	}
+
+	annotation := func(namespace, name string) string {
+		object, err := client.Resource(podGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return object.GetAnnotations()[apis.IdentifierAnnotation]
+	}
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+
+		client = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{podGVR: "PodList"},
+			pod("shop", "web", nil),
+			pod("shop", "Invalid", nil),
+			pod("billing", "api", map[string]interface{}{apis.IdentifierAnnotation: "ccrn=custom"}),
+		)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+		generator = generate.NewGenerator(backend, client, mapper)
+	})
+
+	It("annotates the objects of all namespaces and keeps existing annotations", func() {
+		// Act
+		report, err := generator.Backfill(context.Background(), []string{"pod"}, "", map[string]string{"cluster": "eu-de-1"}, generate.ModeApply)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Objects).To(Equal(3))
+		Expect(report.Annotated).To(Equal(1))
+		Expect(report.Existing).To(Equal(1))
+		Expect(report.Failed).To(Equal(1))
+		Expect(report.Results[0].String()).To(Equal("pods/billing/api: existing: ccrn=custom"))
+		Expect(report.Results[1].String()).To(HavePrefix("pods/shop/Invalid: failed: "))
+		Expect(annotation("shop", "web")).To(Equal("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=web, namespace=shop"))
+		Expect(annotation("billing", "api")).To(Equal("ccrn=custom"))
+	})
+
+	It("only reports missing annotations in report mode", func() {
+		// Act
+		report, err := generator.Backfill(context.Background(), []string{"pod"}, "shop", map[string]string{"cluster": "eu-de-1"}, generate.ModeReport)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Objects).To(Equal(2))
+		Expect(report.Missing).To(Equal(1))
+		Expect(annotation("shop", "web")).To(BeEmpty())
+	})
+
+	It("sends the annotations in dry-run mode", func() {
+		// Arrange
+		patched := 0
+		client.PrependReactor("patch", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			// The fake client drops the patch options, the API server would not persist a dry run
+			patched++
+			return true, nil, nil
+		})
+		// Act
+		report, err := generator.Backfill(context.Background(), []string{"pod"}, "shop", map[string]string{"cluster": "eu-de-1"}, generate.ModeDryRun)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Annotated).To(Equal(1))
+		Expect(patched).To(Equal(1))
+	})
+
+	It("rejects unknown modes", func() {
+		// Act
+		_, err := generator.Backfill(context.Background(), []string{"pod"}, "", nil, "force")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`invalid backfill mode "force"`)))
+	})
+})
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lru
+
+import (
+	"sync/atomic"
+)
+
+var evictionHook atomic.Pointer[func(cache string)]
+
+// SetEvictionHook sets the function called with the name of a cache whenever it evicts an entry because it is
+// full, nil removes it. The package does not count evictions itself, which keeps library users free of a
+// metrics registry; the webhook counts them in the ccrn_cache_evictions_total metric.
+func SetEvictionHook(hook func(cache string)) {
+	if hook == nil {
+		evictionHook.Store(nil)
+		return
+	}
+	evictionHook.Store(&hook)
+}
+
+// evicted calls the eviction hook for the cache
+func evicted(cache string) {
+	if hook := evictionHook.Load(); hook != nil {
+		(*hook)(cache)
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lru provides a size bounded cache evicting the least recently used entries. It keeps the memory
+// of the webhook stable when caches are keyed by values of admission requests, evictions are reported to
+// the hook set with SetEvictionHook.
+//
+// Lookups only take a read lock, so concurrent admission requests do not contend on cache hits. They mark the
+// entry as used instead of reordering the entries, and eviction gives used entries a second chance (the CLOCK
+// approximation of LRU): an entry used since it became the oldest is kept and the next oldest is evicted.
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// Cache is a thread-safe cache holding at most its size of entries
+type Cache[K comparable, V any] struct {
+	name string
+
+	mutex   sync.RWMutex
+	size    int
+	entries map[K]*list.Element
+	order   *list.List // Most recently added or kept entry first
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	used  atomic.Bool // Whether the entry was read since it was added or last kept by eviction
+}
+
+// New creates a cache holding up to size entries, a size of 0 or less does not bound it. The name
+// identifies the cache to the eviction hook.
+func New[K comparable, V any](name string, size int) *Cache[K, V] {
+	return &Cache[K, V]{name: name, size: size, entries: map[K]*list.Element{}, order: list.New()}
+}
+
+// Get returns the value of the key and marks it as recently used
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		var zero V
+		return zero, false
+	}
+	e := element.Value.(*entry[K, V])
+	e.used.Store(true)
+	return e.value, true
+}
+
+// Add sets the value of the key and evicts the least recently used entries exceeding the size
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		e := element.Value.(*entry[K, V])
+		e.value = value
+		e.used.Store(true)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	c.evict()
+}
+
+// Resize changes the number of entries the cache holds and evicts the entries exceeding it, a size of 0 or
+// less does not bound the cache
+func (c *Cache[K, V]) Resize(size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.size = size
+	c.evict()
+}
+
+// evict removes the oldest unused entries until the size is kept, used entries are kept once and cleared
+func (c *Cache[K, V]) evict() {
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		e := oldest.Value.(*entry[K, V])
+		if e.used.Swap(false) {
+			c.order.MoveToFront(oldest)
+			continue
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, e.key)
+		evicted(c.name)
+	}
+}
+
+// Remove deletes the key
+func (c *Cache[K, V]) Remove(key K) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// RemoveFunc deletes all entries the function returns true for
+func (c *Cache[K, V]) RemoveFunc(remove func(key K, value V) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, element := range c.entries {
+		if remove(key, element.Value.(*entry[K, V]).value) {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of entries
+func (c *Cache[K, V]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package lru_test
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/lru"
+)
+
+func TestLRU(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LRU Suite")
+}
+
+var _ = Describe("Cache", func() {
+	It("evicts the least recently used entries", func() {
+		// Arrange
+		cache := lru.New[string, int]("test", 2)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+		_, _ = cache.Get("a")
+		// Act
+		cache.Add("c", 3)
+		// Assert
+		Expect(cache.Len()).To(Equal(2))
+		for key, expected := range map[string]int{"a": 1, "c": 3} {
+			value, exists := cache.Get(key)
+			Expect(exists).To(BeTrue())
+			Expect(value).To(Equal(expected))
+		}
+		_, exists := cache.Get("b")
+		Expect(exists).To(BeFalse())
+	})
+
+	It("reports evictions to the hook by cache", func() {
+		// Arrange
+		evicted := map[string]int{}
+		lru.SetEvictionHook(func(cache string) { evicted[cache]++ })
+		DeferCleanup(func() { lru.SetEvictionHook(nil) })
+		cache := lru.New[int, int]("counted", 1)
+		// Act
+		for i := range 4 {
+			cache.Add(i, i)
+		}
+		// Assert
+		Expect(evicted).To(Equal(map[string]int{"counted": 3}))
+	})
+
+	It("is unbounded with a size of 0", func() {
+		// Arrange
+		cache := lru.New[int, int]("unbounded", 0)
+		// Act
+		for i := range 100 {
+			cache.Add(i, i)
+		}
+		// Assert
+		Expect(cache.Len()).To(Equal(100))
+	})
+
+	It("evicts the entries exceeding a smaller size", func() {
+		// Arrange
+		cache := lru.New[int, int]("resized", 10)
+		for i := range 10 {
+			cache.Add(i, i)
+		}
+		_, _ = cache.Get(0)
+		// Act
+		cache.Resize(2)
+		// Assert
+		Expect(cache.Len()).To(Equal(2))
+		_, exists := cache.Get(0)
+		Expect(exists).To(BeTrue(), "recently used entries are kept")
+	})
+
+	It("serves concurrent lookups", func() {
+		// Arrange
+		cache := lru.New[int, int]("concurrent", 8)
+		for i := range 8 {
+			cache.Add(i, i)
+		}
+		// Act
+		var wg sync.WaitGroup
+		for worker := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 1000 {
+					if _, exists := cache.Get(i % 8); !exists {
+						cache.Add(i%8+worker, i)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		// Assert
+		Expect(cache.Len()).To(Equal(8))
+	})
+
+	It("removes entries", func() {
+		// Arrange
+		cache := lru.New[int, int]("removed", 10)
+		for i := range 5 {
+			cache.Add(i, i)
+		}
+		// Act
+		cache.Remove(0)
+		cache.RemoveFunc(func(_ int, value int) bool { return value%2 == 0 })
+		// Assert
+		Expect(cache.Len()).To(Equal(2))
+		for _, key := range []int{1, 3} {
+			value, exists := cache.Get(key)
+			Expect(exists).To(BeTrue())
+			Expect(value).To(Equal(key))
+		}
+	})
+})
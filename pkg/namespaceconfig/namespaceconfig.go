@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package namespaceconfig keeps the CCRNConfig resources of a cluster, so multi-tenant clusters can tune the
+// enforcement of CCRNs per namespace without global flags, see webhook.NamespaceConfigs. CCRNConfigs are
+// namespaced resources of the group validate.<ccrn-group>, e.g.
+//
+//	apiVersion: validate.ccrn.example.com/v1
+//	kind: CCRNConfig
+//	metadata:
+//	  name: shop
+//	  namespace: shop-prod
+//	spec:
+//	  enforcedGroups: ["k8s-registry.ccrn.example.com"]
+//	  strictness: Warn
+//	  defaults:
+//	    cluster: eu-de-1
+//	  exemptions:
+//	  - groups: ["platform-admins"]
+package namespaceconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// Resource is the plural resource name of CCRNConfigs
+const Resource = "ccrnconfigs"
+
+// GroupVersionResource returns the resource of the CCRNConfigs of the CCRN group
+func GroupVersionResource(ccrnGroup string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "validate." + ccrnGroup, Version: "v1", Resource: Resource}
+}
+
+// Store keeps the CCRNConfigs by namespace, it implements webhook.NamespaceConfigs. One store can be shared by the
+// watches of several CCRN groups, configs are kept by their API group and name.
+type Store struct {
+	mu      sync.RWMutex
+	configs map[string]map[configKey]apis.CCRNConfigSpec // Specs by namespace, API group and name
+	merged  map[string]*apis.CCRNConfigSpec              // Merged specs by namespace
+}
+
+// configKey identifies a CCRNConfig within its namespace
+type configKey struct {
+	group string // API group, e.g. validate.ccrn.example.com
+	name  string
+}
+
+var _ webhook.NamespaceConfigs = &Store{}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{configs: map[string]map[configKey]apis.CCRNConfigSpec{}, merged: map[string]*apis.CCRNConfigSpec{}}
+}
+
+// Set adds or replaces a config, it is identified by the group of its apiVersion, its namespace and name
+func (s *Store) Set(config *apis.CCRNConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.configs[config.Namespace] == nil {
+		s.configs[config.Namespace] = map[configKey]apis.CCRNConfigSpec{}
+	}
+	s.configs[config.Namespace][configKey{group: config.GroupVersionKind().Group, name: config.Name}] = config.Spec
+	s.merge(config.Namespace)
+}
+
+// Delete removes the config of the API group
+func (s *Store) Delete(group, namespace, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs[namespace], configKey{group: group, name: name})
+	s.merge(namespace)
+}
+
+// merge updates the merged config of the namespace, the configs are merged in the order of their names, configs
+// of the same name in the order of their groups
+func (s *Store) merge(namespace string) {
+	if len(s.configs[namespace]) == 0 {
+		delete(s.configs, namespace)
+		delete(s.merged, namespace)
+		return
+	}
+	keys := make([]configKey, 0, len(s.configs[namespace]))
+	for key := range s.configs[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].group < keys[j].group
+	})
+	specs := make([]apis.CCRNConfigSpec, 0, len(keys))
+	for _, key := range keys {
+		specs = append(specs, s.configs[namespace][key])
+	}
+	s.merged[namespace] = apis.MergeConfigs(specs...)
+}
+
+// NamespaceConfig implements webhook.NamespaceConfigs
+func (s *Store) NamespaceConfig(namespace string) *apis.CCRNConfigSpec {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.merged[namespace]
+}
+
+// Watch keeps the store in sync with the CCRNConfigs of all namespaces until the context is done.
+// It returns once the existing configs are loaded.
+func Watch(ctx context.Context, log *logrus.Logger, client dynamic.Interface, ccrnGroup string, store *Store) error {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 10*time.Minute)
+	informer := factory.ForResource(GroupVersionResource(ccrnGroup)).Informer()
+
+	set := func(obj any) {
+		config, err := fromUnstructured(obj)
+		if err != nil {
+			log.Warnf("Ignoring invalid CCRNConfig: %v", err)
+			return
+		}
+		log.Infof("Loaded CCRNConfig %s/%s", config.Namespace, config.Name)
+		store.Set(config)
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    set,
+		UpdateFunc: func(_, obj any) { set(obj) },
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				log.Infof("Removed CCRNConfig %s/%s", u.GetNamespace(), u.GetName())
+				store.Delete(u.GroupVersionKind().Group, u.GetNamespace(), u.GetName())
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch CCRNConfigs: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync CCRNConfigs: %w", ctx.Err())
+	}
+	return nil
+}
+
+// fromUnstructured converts an object of the informer into a CCRNConfig
+func fromUnstructured(obj any) (*apis.CCRNConfig, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+	config := &apis.CCRNConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, config); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return config, nil
+}
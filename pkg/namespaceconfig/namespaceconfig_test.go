@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package namespaceconfig_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/namespaceconfig"
+)
+
+func TestNamespaceConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Namespace Config Suite")
+}
+
+func config(namespace, name string, spec apis.CCRNConfigSpec) *apis.CCRNConfig {
+	return groupConfig("validate.ccrn.example.com", namespace, name, spec)
+}
+
+func groupConfig(group, namespace, name string, spec apis.CCRNConfigSpec) *apis.CCRNConfig {
+	return &apis.CCRNConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: group + "/v1", Kind: "CCRNConfig"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       spec,
+	}
+}
+
+var _ = Describe("Store", func() {
+	It("merges the configs of a namespace in the order of their names", func() {
+		// Arrange
+		store := namespaceconfig.NewStore()
+		// Act
+		store.Set(config("shop", "b", apis.CCRNConfigSpec{Strictness: apis.StrictnessWarn, Defaults: map[string]string{"cluster": "eu-de-2"}}))
+		store.Set(config("shop", "a", apis.CCRNConfigSpec{EnforcedGroups: []string{"k8s-registry.*"}, Defaults: map[string]string{"cluster": "eu-de-1", "region": "eu-de"}}))
+		// Assert
+		merged := store.NamespaceConfig("shop")
+		Expect(merged.EnforcedGroups).To(Equal([]string{"k8s-registry.*"}))
+		Expect(merged.Defaults).To(Equal(map[string]string{"cluster": "eu-de-2", "region": "eu-de"}))
+		Expect(merged.WarnOnly()).To(BeFalse())
+		Expect(store.NamespaceConfig("other")).To(BeNil())
+	})
+
+	It("only warns if all configs of a namespace only warn", func() {
+		// Arrange
+		store := namespaceconfig.NewStore()
+		store.Set(config("shop", "a", apis.CCRNConfigSpec{Strictness: apis.StrictnessWarn}))
+		store.Set(config("shop", "b", apis.CCRNConfigSpec{}))
+		// Act
+		store.Delete("validate.ccrn.example.com", "shop", "b")
+		// Assert
+		Expect(store.NamespaceConfig("shop").WarnOnly()).To(BeTrue())
+		store.Delete("validate.ccrn.example.com", "shop", "a")
+		Expect(store.NamespaceConfig("shop")).To(BeNil())
+	})
+
+	It("keeps configs of the same name in different groups apart", func() {
+		// Arrange
+		store := namespaceconfig.NewStore()
+		store.Set(groupConfig("validate.ccrn.example.com", "shop", "default", apis.CCRNConfigSpec{EnforcedGroups: []string{"k8s-registry.ccrn.example.com"}}))
+		store.Set(groupConfig("validate.ccrn.other.example.com", "shop", "default", apis.CCRNConfigSpec{EnforcedGroups: []string{"vault.ccrn.other.example.com"}}))
+		// Act
+		merged := store.NamespaceConfig("shop")
+		store.Delete("validate.ccrn.other.example.com", "shop", "default")
+		// Assert
+		Expect(merged.EnforcedGroups).To(ConsistOf("k8s-registry.ccrn.example.com", "vault.ccrn.other.example.com"))
+		Expect(store.NamespaceConfig("shop").EnforcedGroups).To(Equal([]string{"k8s-registry.ccrn.example.com"}))
+	})
+})
+
+var _ = Describe("Watch", func() {
+	It("keeps the store in sync with the CCRNConfigs of the cluster", func(ctx SpecContext) {
+		// Arrange
+		gvr := namespaceconfig.GroupVersionResource("ccrn.example.com")
+		existing, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config("shop", "default", apis.CCRNConfigSpec{EnforcedGroups: []string{"vault.ccrn.example.com"}}))
+		Expect(err).ToNot(HaveOccurred())
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "CCRNConfigList"}, &unstructured.Unstructured{Object: existing})
+		store := namespaceconfig.NewStore()
+		watchCtx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+
+		// Act
+		Expect(namespaceconfig.Watch(watchCtx, logrus.New(), client, "ccrn.example.com", store)).To(Succeed())
+
+		// Assert
+		Expect(store.NamespaceConfig("shop").EnforcedGroups).To(Equal([]string{"vault.ccrn.example.com"}))
+		Expect(client.Resource(gvr).Namespace("shop").Delete(ctx, "default", metav1.DeleteOptions{})).To(Succeed())
+		Eventually(func() *apis.CCRNConfigSpec { return store.NamespaceConfig("shop") }).Should(BeNil())
+	})
+})
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package opa evaluates Rego policies against CCRN resources with the Open Policy Agent, see webhook.PolicyEngine.
+// Policies are Rego v1 modules of the package ccrn, or the one given to NewEngine, with deny and warn rules
+// collecting the messages of violations. The input is a webhook.PolicyInput, e.g.
+//
+//	package ccrn
+//
+//	deny contains msg if {
+//		startswith(input.request.namespace, "prod-")
+//		input.resource.fields.cluster == "*"
+//		msg := "production namespaces must not use wildcard cluster fields"
+//	}
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// DefaultPackage is the default Rego package of the policies
+const DefaultPackage = "ccrn"
+
+// Rules of the policies collecting the messages of violations
+const (
+	denyRule = "deny" // Violations rejecting the resource
+	warnRule = "warn" // Violations returned as warnings
+)
+
+// Engine evaluates prepared Rego policies, it implements webhook.PolicyEngine
+type Engine struct {
+	query rego.PreparedEvalQuery
+}
+
+var _ webhook.PolicyEngine = &Engine{}
+
+// NewEngine compiles the Rego modules by file name and prepares the evaluation of the deny and warn rules of the
+// package, DefaultPackage if it is empty
+func NewEngine(ctx context.Context, pkg string, modules map[string]string) (*Engine, error) {
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no Rego policies")
+	}
+	if pkg == "" {
+		pkg = DefaultPackage
+	}
+	options := []func(*rego.Rego){rego.Query("data." + pkg)}
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		options = append(options, rego.Module(name, modules[name]))
+	}
+
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile Rego policies: %w", err)
+	}
+	return &Engine{query: query}, nil
+}
+
+// LoadEngine creates an engine from the .rego files of the paths, directories are searched recursively.
+// Rego tests (_test.rego) are left out.
+func LoadEngine(ctx context.Context, pkg string, paths ...string) (*Engine, error) {
+	modules := map[string]string{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.HasSuffix(file, ".rego") || strings.HasSuffix(file, "_test.rego") {
+				return nil
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			modules[file] = string(content)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Rego policies from %s: %w", path, err)
+		}
+	}
+	return NewEngine(ctx, pkg, modules)
+}
+
+// Evaluate implements webhook.PolicyEngine
+func (e *Engine) Evaluate(ctx context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	// Pass the input as JSON document, like the OPA server does
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy input: %w", err)
+	}
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy input: %w", err)
+	}
+
+	results, err := e.query.Eval(ctx, rego.EvalInput(document))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate Rego policies: %w", err)
+	}
+	decision := &webhook.PolicyDecision{}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return decision, nil
+	}
+	rules, _ := results[0].Expressions[0].Value.(map[string]any)
+	if decision.Denials, err = messages(rules, denyRule); err != nil {
+		return nil, err
+	}
+	if decision.Warnings, err = messages(rules, warnRule); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+// messages returns the messages collected by the rule, a set or an array of strings
+func messages(rules map[string]any, rule string) ([]string, error) {
+	value, defined := rules[rule]
+	if !defined {
+		return nil, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("rule %s must be a set of messages, got %T", rule, value)
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		message, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("rule %s must be a set of messages, got %v", rule, item)
+		}
+		result = append(result, message)
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package opa_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/opa"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+func TestOPA(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OPA Suite")
+}
+
+var _ = Describe("Engine", func() {
+	ctx := context.Background()
+
+	input := func(namespace, cluster string, labels map[string]string) *webhook.PolicyInput {
+		return &webhook.PolicyInput{
+			Resource: &apis.ParsedResource{Format: "CCRN", Fields: map[string]string{"ccrn": "pod.k8s.ccrn.example.com/v1", "cluster": cluster, "name": "web"}},
+			Request:  webhook.PolicyRequest{Operation: "CREATE", Namespace: namespace, Name: "web", Labels: labels},
+		}
+	}
+
+	Context("loaded from a directory", func() {
+		var engine *opa.Engine
+
+		BeforeEach(func() {
+			var err error
+			engine, err = opa.LoadEngine(ctx, "", filepath.Join("testdata", "policies"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("denies resources violating a deny rule", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("prod-shop", "*", map[string]string{"team": "shop"}))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(Equal([]string{"production namespaces must not use wildcard cluster fields"}))
+			Expect(decision.Warnings).To(BeEmpty())
+		})
+
+		It("returns the messages of warn rules as warnings", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("dev-shop", "*", nil))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(BeEmpty())
+			Expect(decision.Warnings).To(Equal([]string{"web has no team label"}))
+		})
+	})
+
+	It("evaluates the rules of another package", func() {
+		// Arrange
+		engine, err := opa.NewEngine(ctx, "naming.pods", map[string]string{"pods.rego": `package naming.pods
+
+deny contains "names must be longer than three characters" if count(input.resource.fields.name) <= 3
+`})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		decision, err := engine.Evaluate(ctx, input("default", "eu-de-1", nil))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.Denials).To(ConsistOf("names must be longer than three characters"))
+	})
+
+	It("allows resources if the package has no rules", func() {
+		// Arrange
+		engine, err := opa.NewEngine(ctx, "", map[string]string{"other.rego": "package other\n\nallow := true\n"})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		decision, err := engine.Evaluate(ctx, input("default", "eu-de-1", nil))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.Denials).To(BeEmpty())
+	})
+
+	It("fails on rules that are no sets of messages", func() {
+		// Arrange
+		engine, err := opa.NewEngine(ctx, "", map[string]string{"deny.rego": "package ccrn\n\ndeny := true\n"})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		_, err = engine.Evaluate(ctx, input("default", "eu-de-1", nil))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("rule deny must be a set of messages")))
+	})
+
+	It("fails on invalid policies", func() {
+		// Act
+		_, err := opa.NewEngine(ctx, "", map[string]string{"invalid.rego": "package ccrn\n\ndeny contains msg if {\n"})
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to compile Rego policies")))
+	})
+})
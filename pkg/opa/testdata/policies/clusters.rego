@@ -0,0 +1,15 @@
+# SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+# SPDX-License-Identifier: Apache-2.0
+
+package ccrn
+
+deny contains msg if {
+	startswith(input.request.namespace, "prod-")
+	input.resource.fields.cluster == "*"
+	msg := "production namespaces must not use wildcard cluster fields"
+}
+
+warn contains msg if {
+	not input.request.labels.team
+	msg := sprintf("%s has no team label", [input.request.name])
+}
@@ -0,0 +1,10 @@
+# SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+# SPDX-License-Identifier: Apache-2.0
+
+package ccrn_test
+
+import data.ccrn
+
+test_wildcard_cluster_in_production if {
+	ccrn.deny with input as {"request": {"namespace": "prod-shop"}, "resource": {"fields": {"cluster": "*"}}}
+}
@@ -0,0 +1,9 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package parser
+
+import "github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+
+// ParseError describes where and why parsing a CCRN or URN failed, see apis.ParseError
+type ParseError = apis.ParseError
@@ -6,164 +6,341 @@
 package parser
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const DEFAULT_URN_TEMPLATE string = "urn:ccrn:<ccrn>"
 
+// URNPrefix is the default prefix of CCRN URNs
+const URNPrefix = apis.URNPrefix
+
 // ResourceParser parses both CCRN and URN formats and converts between them, without backend dependencies
 // It requires a URN template to parse a URN.
 type ResourceParser struct {
-	log     *logrus.Logger
-	backend apis.ValidationBackend
+	log       *logrus.Logger
+	backend   apis.ValidationBackend
+	strict    bool
+	sunset    bool
+	prefixes  []string
+	maxLength int
+	aliases   []apis.AliasResolver
+	keepLast  bool
+	homoglyph bool
+	limits    apis.Limits
+}
+
+// Option configures optional behavior of the ResourceParser
+type Option func(*ResourceParser)
+
+// WithStrictMode rejects fields that are not declared in the schema of the resource type and
+// reports missing required fields at parse time, values of declared fields must satisfy their
+// enum, length and pattern constraints. Resource types unknown to the backend or without schema are not checked.
+func WithStrictMode() Option {
+	return func(p *ResourceParser) {
+		p.strict = true
+	}
+}
+
+// WithSunsetEnforcement rejects identifiers referencing a version past its sunset date, see
+// apis.CRDInfo.Sunset. Deprecated versions without sunset date are still accepted.
+func WithSunsetEnforcement() Option {
+	return func(p *ResourceParser) {
+		p.sunset = true
+	}
+}
+
+// WithURNPrefixes sets the "urn:<NID>:" prefixes accepted for URNs, replacing the default "urn:ccrn:".
+// The URN template of a resource type decides which of them its URNs use.
+func WithURNPrefixes(prefixes ...string) Option {
+	return func(p *ResourceParser) {
+		p.prefixes = prefixes
+	}
+}
+
+// WithMaxLength rejects inputs longer than maxLength characters before parsing them,
+// see apis.DefaultMaxLength
+func WithMaxLength(maxLength int) Option {
+	return func(p *ResourceParser) {
+		p.maxLength = maxLength
+	}
+}
+
+// WithLimits rejects identifiers exceeding the limits of their field count, key length and nesting depth
+// right after parsing, before they are looked up in the backend, see apis.CheckComplexity
+func WithLimits(limits apis.Limits) Option {
+	return func(p *ResourceParser) {
+		p.limits = limits
+	}
+}
+
+// WithAliases resolves short names of resource types like "pod/v1" with the resolver. Aliases of the
+// backend, if it implements apis.AliasResolver, are used after the configured ones.
+func WithAliases(resolver apis.AliasResolver) Option {
+	return func(p *ResourceParser) {
+		p.aliases = append(p.aliases, resolver)
+	}
+}
+
+// WithKeepLastDuplicates accepts CCRNs setting a field more than once, the last value wins instead of
+// failing with apis.ErrDuplicateField, see apis.ParseCCRNKeepLast
+func WithKeepLastDuplicates() Option {
+	return func(p *ResourceParser) {
+		p.keepLast = true
+	}
+}
+
+// WithHomoglyphCheck rejects values of identifier fields that could be mistaken for a different identifier,
+// like "pаyments" with a Cyrillic "а", see apis.CheckHomoglyphs. Resource types unknown to the backend have
+// all their fields checked.
+func WithHomoglyphCheck() Option {
+	return func(p *ResourceParser) {
+		p.homoglyph = true
+	}
 }
 
 // NewResourceParser creates a new resource parser
-func NewResourceParser(log *logrus.Logger, backend apis.ValidationBackend) *ResourceParser {
-	return &ResourceParser{log: log, backend: backend}
+func NewResourceParser(log *logrus.Logger, backend apis.ValidationBackend, opts ...Option) *ResourceParser {
+	p := &ResourceParser{log: log, backend: backend, prefixes: []string{URNPrefix}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if resolver, ok := backend.(apis.AliasResolver); ok {
+		p.aliases = append(p.aliases, resolver)
+	}
+	return p
 }
 
 // Parse parses a CCRN or URN string. For URN, a template must be provided.
 func (p *ResourceParser) Parse(input string, urnTemplate string) (*apis.ParsedResource, error) {
-	if strings.HasPrefix(input, "ccrn=") {
-		parsed, err := parseCCRNFields(input)
+	return p.ParseContext(context.Background(), input, urnTemplate)
+}
+
+// ParseContext is like Parse but records a trace span as child of the given context
+func (p *ResourceParser) ParseContext(ctx context.Context, input string, urnTemplate string) (*apis.ParsedResource, error) {
+	ctx, span := tracing.StartSpan(ctx, "parser.Parse", attribute.String("ccrn.input", input))
+	var parsed *apis.ParsedResource
+	err := apis.CheckLength(input, p.maxLength)
+	if err == nil {
+		parsed, err = p.parse(ctx, input, urnTemplate)
+	}
+	if err == nil {
+		err = apis.CheckComplexity(parsed, p.limits)
 		if err != nil {
-			return nil, err
+			parsed = nil
 		}
-		return &apis.ParsedResource{
-			Format: "CCRN",
-			Fields: parsed,
-			Raw:    input,
-		}, nil
-	} else if strings.HasPrefix(input, "urn:ccrn:") {
-		if urnTemplate == "" || urnTemplate == DEFAULT_URN_TEMPLATE {
-
-			parsed, err := parseURNCCRNField(input)
-
-			if err != nil {
-				return nil, err
-			}
-
-			parsedResource := &apis.ParsedResource{
-				Format: "URN",
-				Fields: map[string]string{"ccrn": parsed},
-				Raw:    input,
-			}
-
-			template, err := p.backend.GetURNTemplate(parsedResource.CCRNName(), parsedResource.Version())
-			if err != nil {
-				return nil, fmt.Errorf("failed to get URN template: %w", err)
-			}
-			return p.Parse(input, template)
+	}
+	if err == nil && p.strict {
+		err = p.checkSchemaFields(parsed)
+		if err != nil {
+			parsed = nil
 		}
+	}
+	if err == nil && p.homoglyph {
+		err = p.checkHomoglyphs(parsed)
+		if err != nil {
+			parsed = nil
+		}
+	}
+	if err == nil && p.sunset {
+		err = p.checkSunset(parsed)
+		if err != nil {
+			parsed = nil
+		}
+	}
+	tracing.EndSpan(span, err)
+	return parsed, err
+}
+
+// MaxLength returns the maximum length of inputs, 0 if it is not limited, see WithMaxLength
+func (p *ResourceParser) MaxLength() int {
+	return p.maxLength
+}
+
+// checkSchemaFields verifies the parsed fields against the properties and required fields of the schema,
+// and the values of declared fields against their enum, length and pattern constraints
+func (p *ResourceParser) checkSchemaFields(parsed *apis.ParsedResource) error {
+	if p.backend == nil {
+		return nil
+	}
+	crdInfo, err := p.backend.GetCRD(parsed.CCRNKey())
+	if err != nil {
+		return nil
+	}
+	return apis.CheckSchemaFields(parsed, crdInfo)
+}
+
+// checkHomoglyphs rejects the values of identifier fields that could be mistaken for a different identifier
+func (p *ResourceParser) checkHomoglyphs(parsed *apis.ParsedResource) error {
+	var crdInfo *apis.CRDInfo
+	if p.backend != nil {
+		crdInfo, _ = p.backend.GetCRD(parsed.CCRNKey())
+	}
+	return apis.CheckHomoglyphs(parsed, crdInfo)
+}
+
+// checkSunset rejects the parsed resource if its version is past its sunset date
+func (p *ResourceParser) checkSunset(parsed *apis.ParsedResource) error {
+	if p.backend == nil {
+		return nil
+	}
+	crdInfo, err := p.backend.GetCRD(parsed.CCRNKey())
+	if err != nil || !crdInfo.Sunset(time.Now()) {
+		return nil
+	}
+	message := fmt.Sprintf("%s is past its sunset date %s", crdInfo.CCRNKey(), crdInfo.SunsetDate.Format(time.DateOnly))
+	if crdInfo.DeprecationWarning != "" {
+		message += ": " + crdInfo.DeprecationWarning
+	}
+	return apis.Errorf(apis.ErrVersionSunset, "%s", message)
+}
+
+// ResolveAlias resolves a short resource type in a CCRN key like "pod/v1", or a type name like "pod",
+// to the full resource type. Keys without alias are returned unchanged.
+func (p *ResourceParser) ResolveAlias(key string) string {
+	return apis.ResolveCCRNKey(key, p.aliases...)
+}
 
-		if !strings.HasPrefix(urnTemplate, "urn:ccrn:") {
-			return nil, errors.New("invalid URN template: must start with 'urn:ccrn:'")
+// ResolveKey resolves a type alias and the "latest" version of a CCRN key like "pod/latest", the latest
+// version is looked up in the backend if it implements apis.VersionResolver
+func (p *ResourceParser) ResolveKey(key string) string {
+	key = p.ResolveAlias(key)
+	resourceType, version, _ := strings.Cut(key, "/")
+	if version != apis.LatestVersion {
+		return key
+	}
+	if resolver, ok := p.backend.(apis.VersionResolver); ok {
+		if latest, exists := resolver.ResolveLatestVersion(resourceType); exists {
+			return resourceType + "/" + latest
 		}
-		parsed, err := parseURNFields(input, urnTemplate)
+	}
+	return key
+}
+
+func (p *ResourceParser) parse(ctx context.Context, input string, urnTemplate string) (*apis.ParsedResource, error) {
+	parsed, err := p.parseFormat(ctx, input, urnTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if key, exists := parsed.Fields["ccrn"]; exists {
+		parsed.Fields["ccrn"] = p.ResolveKey(key)
+	}
+	return parsed, nil
+}
+
+// parseFormat parses the input according to its CCRN or URN format
+func (p *ResourceParser) parseFormat(ctx context.Context, input string, urnTemplate string) (*apis.ParsedResource, error) {
+	if strings.HasPrefix(input, "ccrn=") && p.keepLast {
+		return apis.ParseCCRNKeepLast(input)
+	} else if strings.HasPrefix(input, "ccrn=") {
+		return apis.ParseCCRN(input)
+	} else if p.hasURNPrefix(input) {
+		urn, components := apis.SplitURNComponents(input)
+		parsed, err := p.parseURN(ctx, urn, urnTemplate)
 		if err != nil {
 			return nil, err
 		}
-		return &apis.ParsedResource{
-			Format: "URN",
-			Fields: parsed,
-			Raw:    input,
-		}, nil
+		parsed.Raw = input
+		parsed.URNComponents = components
+		return parsed, nil
 	}
-	return nil, errors.New("unknown format: must start with 'ccrn=' or 'urn:ccrn:'")
+	return nil, &ParseError{Input: input, Segment: 1, Expected: fmt.Sprintf("'ccrn=' or '%s' prefix", strings.Join(p.prefixes, "' or '")), Got: input, Err: apis.ErrUnknownFormat}
 }
 
-// parseCCRNFields parses a CCRN string into fields
-func parseCCRNFields(ccrn string) (map[string]string, error) {
-	if !strings.HasPrefix(ccrn, "ccrn=") {
-		return nil, errors.New("invalid CCRN format: must start with 'ccrn='")
+// hasURNPrefix reports whether the input starts with one of the accepted URN prefixes
+func (p *ResourceParser) hasURNPrefix(input string) bool {
+	for _, prefix := range p.prefixes {
+		if len(input) >= len(prefix) && strings.EqualFold(input[:len(prefix)], prefix) {
+			return true
+		}
 	}
-	fieldsPart := strings.TrimSpace(ccrn)
-	fields := make(map[string]string)
-	fieldEntries := strings.Split(fieldsPart, ",")
-	for _, entry := range fieldEntries {
-		entry = strings.TrimSpace(entry)
-		if entry == "" {
-			continue
+	return false
+}
+
+// isDefaultTemplate reports whether the template only extracts the ccrn field, like DEFAULT_URN_TEMPLATE
+func isDefaultTemplate(urnTemplate string) bool {
+	_, body, ok := apis.SplitURNPrefix(urnTemplate)
+	return urnTemplate == "" || (ok && body == "<ccrn>")
+}
+
+// parseURN parses a URN without RFC 8141 components. Without template, the template is looked up in the backend.
+func (p *ResourceParser) parseURN(ctx context.Context, input string, urnTemplate string) (*apis.ParsedResource, error) {
+	if isDefaultTemplate(urnTemplate) {
+
+		parsed, err := parseURNCCRNField(input)
+
+		if err != nil {
+			return nil, err
 		}
-		parts := strings.SplitN(entry, "=", 2)
-		if len(parts) != 2 {
-			return nil, errors.New("invalid field format: " + entry + " (must be key=value)")
+
+		parsedResource := &apis.ParsedResource{
+			Format: "URN",
+			Fields: map[string]string{"ccrn": p.ResolveKey(parsed)},
+			Raw:    input,
 		}
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
-			value = value[1 : len(value)-1]
+
+		_, span := tracing.StartSpan(ctx, "backend.GetURNTemplate", attribute.String("ccrn.key", parsedResource.CCRNKey()))
+		template, err := p.backend.GetURNTemplate(parsedResource.CCRNName(), parsedResource.Version())
+		tracing.EndSpan(span, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get URN template: %w", err)
+		}
+		resource, err := p.parseURN(ctx, input, template)
+		if err != nil {
+			return p.parseLegacyURN(input, parsedResource.CCRNKey(), err)
 		}
-		fields[key] = value
+		return resource, nil
 	}
-	if _, exists := fields["ccrn"]; !exists {
-		return nil, errors.New("missing required field: ccrn")
+
+	if _, _, ok := apis.SplitURNPrefix(urnTemplate); !ok {
+		return nil, errors.New("invalid URN template: must start with 'urn:<NID>:'")
 	}
-	return fields, nil
+	return apis.ParseURN(input, urnTemplate)
 }
 
-func parseURNCCRNField(urn string) (string, error) {
-	// Remove prefix
-	body := strings.TrimPrefix(urn, "urn:ccrn:")
-	parts := strings.Split(body, "/")
-	if len(parts) < 3 {
-		return "", errors.New("invalid URN format: must contain at least three segments after 'urn:ccrn:'")
-	}
-	return parts[0] + "/" + parts[1], nil
-}
-
-// parseURNFields parses a URN string into fields using the provided template
-func parseURNFields(urn, urnTemplate string) (map[string]string, error) {
-	if !strings.HasPrefix(urn, "urn:ccrn:") {
-		return nil, errors.New("invalid URN format: must start with 'urn:ccrn:'")
-	}
-	if !strings.HasPrefix(urnTemplate, "urn:ccrn:") {
-		return nil, errors.New("invalid URN template: must start with 'urn:ccrn:'")
-	}
-	// Remove prefix
-	body := strings.TrimPrefix(urn, "urn:ccrn:")
-	templateBody := strings.TrimPrefix(urnTemplate, "urn:ccrn:")
-	templateParts := strings.Split(templateBody, "/")
-
-	// The first element is the ccrn type/version so we rebuild the parts accordingly, the last part can be an path with slashes
-	tmpParts := strings.SplitN(body, "/", len(templateParts)+1)
-	parts := make([]string, len(tmpParts)-1)
-	parts[0] = tmpParts[0] + "/" + tmpParts[1]
-	for i := 2; i < len(tmpParts); i++ {
-		if tmpParts[i] != "" {
-			parts[i-1] = tmpParts[i]
-		}
+// parseLegacyURN parses a URN not matching the current URN template of its resource type with its legacy
+// templates, see apis.CRDInfo.LegacyURNFormats, and renames the fields to the current ones. Without a matching
+// legacy template it fails with err.
+func (p *ResourceParser) parseLegacyURN(input, key string, err error) (*apis.ParsedResource, error) {
+	crdInfo, lookupErr := p.backend.GetCRD(key)
+	if lookupErr != nil || len(crdInfo.LegacyURNFormats) == 0 {
+		return nil, err
 	}
-
-	if len(parts) < len(templateParts) {
-		return nil, errors.New("URN and template do not match in segment count. Expected format " + urnTemplate + " segments, got: " + urn)
+	parsed, legacyErr := apis.ParseURNWithTemplates(input, crdInfo.LegacyURNFormats...)
+	if legacyErr != nil {
+		return nil, err
 	}
+	parsed.Fields = crdInfo.LegacyURNMapping.Apply(parsed.Fields)
+	return parsed, nil
+}
 
-	fields := make(map[string]string)
-	for i, t := range templateParts {
-		if strings.HasPrefix(t, "<") && strings.HasSuffix(t, ">") {
-			key := t[1 : len(t)-1]
-			fields[key] = parts[i]
-		} else if t == "<ccrn>" {
-			fields["ccrn"] = parts[i]
-		} else if t != parts[i] {
-			return nil, fmt.Errorf("URN segment '%s' does not match template '%s'", parts[i], t)
+// parseURNCCRNField returns the "<kind>.<group>/<version>" segments following the URN prefix, it scans the
+// URN instead of splitting it as it runs for every URN without template
+func parseURNCCRNField(urn string) (string, error) {
+	prefix, body, _ := apis.SplitURNPrefix(urn)
+	kind, rest, hasVersion := strings.Cut(body, "/")
+	version, _, hasName := strings.Cut(rest, "/")
+	if !hasVersion || !hasName {
+		segments := 1
+		if hasVersion {
+			segments = 2
 		}
+		return "", &ParseError{Input: urn, Segment: segments + 1, Offset: len(urn), Expected: "at least three segments after '" + prefix + "'"}
 	}
-	if _, exists := fields["ccrn"]; !exists {
-		return nil, errors.New("missing required field: ccrn")
-	}
-	return fields, nil
+	return body[:len(kind)+1+len(version)], nil
 }
 
 // ExtractCCRNKeyFromURN extracts the CCRN key from a URN using the template
 func (p *ResourceParser) ExtractCCRNKeyFromURN(urn string) (string, error) {
+	urn, _ = apis.SplitURNComponents(urn)
 	ccrn, err := parseURNCCRNField(urn)
 	if err != nil {
 		return "", err
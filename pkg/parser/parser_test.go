@@ -0,0 +1,430 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package parser_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestParser(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parser Suite")
+}
+
+var _ = Describe("ResourceParser", func() {
+	var p *parser.ResourceParser
+
+	BeforeEach(func() {
+		p = parser.NewResourceParser(logrus.New(), nil)
+	})
+
+	Context("escaping", func() {
+		It("keeps commas and equals signs inside quoted values", func() {
+			// Act
+			parsed, err := p.Parse(`ccrn=pod.k8s-registry.ccrn.example.com/v1, name="app=frontend, blue", cluster=eu-de-1`, "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("name", "app=frontend, blue"))
+			Expect(parsed.Fields).To(HaveKeyWithValue("cluster", "eu-de-1"))
+		})
+
+		It("resolves escaped quotes and backslashes", func() {
+			// Act
+			parsed, err := p.Parse(`ccrn=pod.k8s-registry.ccrn.example.com/v1, name="say \"hi\" \\ bye"`, "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("name", `say "hi" \ bye`))
+		})
+
+		It("keeps quotes that do not start a value", func() {
+			// Act
+			parsed, err := p.Parse(`ccrn=pod.k8s-registry.ccrn.example.com/v1, name=a"b`, "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("name", `a"b`))
+		})
+
+		It("rejects unterminated quoted values", func() {
+			// Act
+			_, err := p.Parse(`ccrn=pod.k8s-registry.ccrn.example.com/v1, name="open, cluster=eu-de-1`, "")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("expected closing quote")))
+		})
+
+		It("round-trips values through CCRN()", func() {
+			// Arrange
+			resource := &apis.ParsedResource{Fields: map[string]string{
+				"ccrn": "pod.k8s-registry.ccrn.example.com/v1",
+				"name": `app=frontend, "blue" \ green`,
+			}}
+			// Act
+			parsed, err := p.Parse(resource.CCRN(), "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(Equal(resource.Fields))
+		})
+	})
+
+	Context("strict mode", func() {
+		BeforeEach(func() {
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			p = parser.NewResourceParser(logrus.New(), backend, parser.WithStrictMode())
+		})
+
+		It("accepts fields declared in the schema", func() {
+			// Act
+			_, err := p.Parse("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, nodeName=node-1", "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("rejects unknown and reports missing required fields", func() {
+			// Act
+			_, err := p.Parse("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, color=blue", "")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("unknown fields: color; missing required fields: namespace")))
+		})
+
+		It("resolves dot path fields through nested schemas", func() {
+			// Act
+			_, accepted := p.Parse("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, labels.app=web", "")
+			_, rejected := p.Parse("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, name.first=my", "")
+			// Assert
+			Expect(accepted).ToNot(HaveOccurred())
+			Expect(rejected).To(MatchError(ContainSubstring("unknown fields: name.first")))
+		})
+
+		It("checks the patterns of declared fields", func() {
+			// Act
+			_, err := p.Parse("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=Default, name=my-pod", "")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrSchemaViolation))
+			Expect(err.Error()).To(HavePrefix("field namespace: value 'Default' does not match pattern"))
+		})
+
+		It("leaves unknown resource types to the backend", func() {
+			// Act
+			_, err := p.Parse("ccrn=unknown.tr.ccrn.example.com/v1, color=blue", "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("sunset enforcement", func() {
+		BeforeEach(func() {
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "sunset_crd.yaml"))).To(Succeed())
+			p = parser.NewResourceParser(logrus.New(), backend, parser.WithSunsetEnforcement())
+		})
+
+		It("rejects versions past their sunset date", func() {
+			// Act
+			_, err := p.Parse("ccrn=gadget.tr.ccrn.example.com/v1alpha1, name=my-gadget", "")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrVersionSunset))
+			Expect(err.Error()).To(Equal("gadget.tr.ccrn.example.com/v1alpha1 is past its sunset date 2020-12-31: gadget.tr.ccrn.example.com/v1alpha1 is deprecated, use v1"))
+		})
+
+		It("accepts deprecated versions before their sunset date", func() {
+			// Act
+			_, deprecated := p.Parse("ccrn=gadget.tr.ccrn.example.com/v1beta1, name=my-gadget", "")
+			_, current := p.Parse("ccrn=gadget.tr.ccrn.example.com/v1, name=my-gadget", "")
+			// Assert
+			Expect(deprecated).ToNot(HaveOccurred())
+			Expect(current).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("legacy URN templates", func() {
+		BeforeEach(func() {
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "legacy_crd.yaml"))).To(Succeed())
+			p = parser.NewResourceParser(logrus.New(), backend)
+		})
+
+		It("parses URNs with the current template first", func() {
+			// Act
+			parsed, err := p.Parse("urn:ccrn:disk.tr.ccrn.example.com/v1/eu-de-1/data", "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("region", "eu-de-1"))
+		})
+
+		It("falls back to the legacy templates in priority order", func() {
+			// Act
+			legacy, legacyErr := p.Parse("urn:ccrn:disk.tr.ccrn.example.com/v1/data", "")
+			// Assert
+			Expect(legacyErr).ToNot(HaveOccurred())
+			Expect(legacy.Fields).To(Equal(map[string]string{"ccrn": "disk.tr.ccrn.example.com/v1", "name": "data"}))
+			Expect(legacy.UrnTemplate).To(Equal("urn:ccrn:<ccrn>/<name:dns>"))
+			Expect(legacy.CanonicalCCRN()).To(Equal("ccrn=disk.tr.ccrn.example.com/v1, name=data"))
+		})
+
+		It("renames the fields of legacy templates to the current ones", func() {
+			// Act
+			parsed, err := p.Parse("urn:ccrn:disk.tr.ccrn.example.com/v1/disks/data", "")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(Equal(map[string]string{"ccrn": "disk.tr.ccrn.example.com/v1", "name": "data"}))
+			Expect(parsed.UrnTemplate).To(Equal("urn:ccrn:<ccrn>/disks/<disk:dns>"))
+		})
+
+		It("fails with the error of the current template if no template matches", func() {
+			// Act
+			_, err := p.Parse("urn:ccrn:disk.tr.ccrn.example.com/v1/eu-de-1/Data_1", "")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		})
+	})
+
+	It("rejects inputs exceeding the maximum length", func() {
+		// Arrange
+		p = parser.NewResourceParser(logrus.New(), nil, parser.WithMaxLength(40))
+		// Act
+		_, err := p.Parse("ccrn=pod.k8s-registry.ccrn.example.com/v1, name=my-pod", "")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTooLong))
+	})
+
+	It("keeps the last value of repeated fields if configured", func() {
+		// Arrange
+		input := "ccrn=pod.k8s-registry.ccrn.example.com/v1, name=a, name=b"
+		lenient := parser.NewResourceParser(logrus.New(), nil, parser.WithKeepLastDuplicates())
+		// Act
+		_, err := p.Parse(input, "")
+		parsed, lenientErr := lenient.Parse(input, "")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrDuplicateField))
+		Expect(lenientErr).ToNot(HaveOccurred())
+		Expect(parsed.Fields).To(HaveKeyWithValue("name", "b"))
+	})
+
+	It("rejects homoglyphs in identifier fields if configured", func() {
+		// Arrange
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "sunset_crd.yaml"))).To(Succeed())
+		screening := parser.NewResourceParser(logrus.New(), backend, parser.WithHomoglyphCheck())
+		input := "ccrn=gadget.tr.ccrn.example.com/v1, name=pаyments, note=Привет world"
+		// Act
+		_, err := screening.Parse(input, "")
+		_, unscreened := p.Parse(input, "")
+		_, descriptive := screening.Parse("ccrn=gadget.tr.ccrn.example.com/v1, name=payments, note=Привет world", "")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrSchemaViolation))
+		Expect(err.Error()).To(Equal("field name: value 'pаyments' mixes Cyrillic and Latin letters"))
+		Expect(unscreened).ToNot(HaveOccurred())
+		Expect(descriptive).ToNot(HaveOccurred(), "note is no field of the URN template")
+	})
+
+	It("rejects identifiers exceeding the complexity limits", func() {
+		// Arrange
+		p = parser.NewResourceParser(logrus.New(), nil, parser.WithLimits(apis.Limits{MaxFields: 2}))
+		// Act
+		_, err := p.Parse("ccrn=pod.k8s-registry.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod", "")
+		// Assert
+		Expect(err).To(MatchError(apis.ErrTooManyFields))
+		Expect(err.Error()).To(Equal("3 fields exceed the maximum of 2"))
+	})
+
+	Context("type aliases", func() {
+		It("resolves aliases of the backend and the configuration", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			p = parser.NewResourceParser(logrus.New(), backend, parser.WithAliases(apis.Aliases{"node": "node.k8s-registry.tr.ccrn.example.com"}))
+			// Act
+			pod, podErr := p.Parse("ccrn=po/v1, cluster=eu-de-1, namespace=default, name=my-pod", "")
+			node, nodeErr := p.Parse("ccrn=node/v1, name=node-1", "")
+			// Assert
+			Expect(podErr).ToNot(HaveOccurred())
+			Expect(pod.CCRNKey()).To(Equal("pod.k8s-registry.tr.ccrn.example.com/v1"))
+			Expect(nodeErr).ToNot(HaveOccurred())
+			Expect(node.CCRNKey()).To(Equal("node.k8s-registry.tr.ccrn.example.com/v1"))
+		})
+
+		It("resolves aliases in URNs before looking up their template", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			p = parser.NewResourceParser(logrus.New(), backend)
+			// Act
+			parsed, err := p.Parse("urn:ccrn:pod/v1/eu-de-1/default/my-pod", parser.DEFAULT_URN_TEMPLATE)
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.CCRNKey()).To(Equal("pod.k8s-registry.tr.ccrn.example.com/v1"))
+			Expect(parsed.Fields).To(HaveKeyWithValue("name", "my-pod"))
+		})
+	})
+
+	Context("URN components", func() {
+		It("keeps RFC 8141 components out of the template match", func() {
+			// Arrange
+			input := "urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod?+rev=3?=view=full#status"
+			// Act
+			parsed, err := p.Parse(input, "urn:ccrn:<ccrn>/<cluster>/<name>")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("name", "my-pod"))
+			Expect(parsed.URNComponents).To(Equal(apis.URNComponents{R: "rev=3", Q: "view=full", F: "status"}))
+			Expect(parsed.Raw).To(Equal(input))
+			Expect(parsed.URN("urn:ccrn:<ccrn>/<cluster>/<name>")).To(Equal(input))
+		})
+	})
+
+	Context("URN prefixes", func() {
+		It("accepts configured URN namespaces", func() {
+			// Arrange
+			p = parser.NewResourceParser(logrus.New(), nil, parser.WithURNPrefixes("urn:sap-ccrn:"))
+			// Act
+			parsed, err := p.Parse("urn:sap-ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod", "urn:sap-ccrn:<ccrn>/<cluster>/<name>")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Fields).To(HaveKeyWithValue("cluster", "eu-de-1"))
+		})
+
+		It("rejects URN namespaces that are not configured", func() {
+			// Act
+			_, err := p.Parse("urn:sap-ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod", "urn:sap-ccrn:<ccrn>/<cluster>/<name>")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrUnknownFormat))
+		})
+
+		It("requires the prefix of the URN template", func() {
+			// Arrange
+			p = parser.NewResourceParser(logrus.New(), nil, parser.WithURNPrefixes("urn:ccrn:", "urn:sap-ccrn:"))
+			// Act
+			_, err := p.Parse("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/eu-de-1/my-pod", "urn:sap-ccrn:<ccrn>/<cluster>/<name>")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrTemplateMismatch))
+		})
+	})
+
+	Context("errors", func() {
+		It("points at the broken CCRN field", func() {
+			// Arrange
+			input := "ccrn=pod.k8s-registry.ccrn.example.com/v1, namespace=default, clustereu-de-1, name=my-pod"
+			// Act
+			_, err := p.Parse(input, "")
+			// Assert
+			var parseErr *parser.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.Segment).To(Equal(3))
+			Expect(input[parseErr.Offset:]).To(HavePrefix(" clustereu-de-1"))
+			Expect(err).To(MatchError("segment 3: expected key=value, got 'clustereu-de-1'"))
+		})
+
+		It("points at the URN segment not matching the template", func() {
+			// Arrange
+			input := "urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/pods/my-pod"
+			// Act
+			_, err := p.Parse(input, "urn:ccrn:<ccrn>/nodes/<name>")
+			// Assert
+			var parseErr *parser.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.Segment).To(Equal(2))
+			Expect(parseErr.Got).To(Equal("pods"))
+			Expect(input[parseErr.Offset:]).To(HavePrefix("pods/"))
+		})
+
+		It("reports URNs with too few segments", func() {
+			// Act
+			_, err := p.Parse("urn:ccrn:pod.k8s-registry.ccrn.example.com", "urn:ccrn:<ccrn>/<name>")
+			// Assert
+			var parseErr *parser.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.Segment).To(Equal(1))
+		})
+
+		It("classifies failures with sentinel errors", func() {
+			// Act
+			_, unknownErr := p.Parse("arn:aws:s3:::bucket", "")
+			_, mismatchErr := p.Parse("urn:ccrn:pod.k8s-registry.ccrn.example.com/v1/pods/my-pod", "urn:ccrn:<ccrn>/nodes/<name>")
+			// Assert
+			Expect(unknownErr).To(MatchError(apis.ErrUnknownFormat))
+			Expect(mismatchErr).To(MatchError(apis.ErrTemplateMismatch))
+		})
+	})
+})
+
+var _ = Describe("ValidateTemplate", func() {
+	var crdInfo *apis.CRDInfo
+
+	BeforeEach(func() {
+		crdInfo = &apis.CRDInfo{
+			Kind: "container",
+			Schema: &apiextensionsv1.JSONSchemaProps{
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"cluster":   {Type: "string"},
+					"namespace": {Type: "string"},
+					"name":      {Type: "string"},
+				},
+				Required: []string{"cluster", "name"},
+			},
+		}
+	})
+
+	It("accepts well-formed templates", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<ccrn>/<cluster>/<namespace:dns>/<name>", crdInfo)
+		// Assert
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("reports malformed templates", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<ccrn>/<cluster", crdInfo)
+		// Assert
+		Expect(issues).To(HaveLen(1))
+		Expect(parser.HasErrors(issues)).To(BeTrue())
+	})
+
+	It("reports placeholders that are not fields of the schema", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<ccrn>/<cluster>/<pod>/<name>", crdInfo)
+		// Assert
+		Expect(issues).To(ConsistOf(parser.Issue{Severity: parser.SeverityError, Segment: 3, Message: "placeholder <pod> is not a field of container"}))
+	})
+
+	It("warns about required fields missing from the template", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<ccrn>/<name>", crdInfo)
+		// Assert
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Severity).To(Equal(parser.SeverityWarning))
+		Expect(issues[0].Message).To(HavePrefix("required field cluster of container"))
+	})
+
+	It("reports duplicate placeholders and a misplaced <ccrn>", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<cluster>/<ccrn>/<cluster>/<name>", nil)
+		// Assert
+		Expect(issues).To(ConsistOf(
+			parser.Issue{Severity: parser.SeverityError, Segment: 1, Message: "the first segment must be <ccrn>, it is used to look up the template of a URN"},
+			parser.Issue{Severity: parser.SeverityError, Segment: 3, Message: "duplicate placeholder <cluster>, first used in segment 1"},
+		))
+	})
+
+	It("warns about ambiguous segments", func() {
+		// Act
+		issues := parser.ValidateTemplate("urn:ccrn:<ccrn>/<region><az>/[<namespace>]/[<zone>]/<name>", nil)
+		// Assert
+		Expect(issues).To(HaveLen(3))
+		Expect(issues[0].String()).To(HavePrefix("warning: segment 2: adjacent placeholders <region><az>"))
+		Expect(issues[1].String()).To(HavePrefix("warning: segment 3: optional segments <namespace> and <zone>"))
+		Expect(issues[2].String()).To(HavePrefix("warning: segment 4: optional segment <zone> is followed by <name>"))
+	})
+})
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package parser
+
+import (
+	"fmt"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Severity classifies the issues found in a URN template
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The template cannot be used as is
+	SeverityWarning Severity = "warning" // The template works but may not do what was intended
+)
+
+// Issue is a problem found in a URN template
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Segment  int      `json:"segment,omitempty"` // 1-based template segment, 0 if the issue concerns the whole template
+	Message  string   `json:"message"`
+}
+
+// String returns the issue in a human-readable form, e.g. "error: segment 3: placeholder <pod> is not a field of container"
+func (i Issue) String() string {
+	if i.Segment == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: segment %d: %s", i.Severity, i.Segment, i.Message)
+}
+
+// HasErrors reports whether any of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTemplate lints a URN template. It reports malformed templates, a missing or misplaced <ccrn>,
+// duplicate placeholders and segments that cannot be matched unambiguously. If the CRD information
+// carries a schema, placeholders must be fields of it and required fields should be part of the template.
+func ValidateTemplate(template string, schema *apis.CRDInfo) []Issue {
+	compiled, err := apis.CompileTemplate(template)
+	if err != nil {
+		return []Issue{{Severity: SeverityError, Message: err.Error()}}
+	}
+
+	var issues []Issue
+	issue := func(severity Severity, segment int, format string, args ...any) {
+		issues = append(issues, Issue{Severity: severity, Segment: segment, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if placeholder, ok := compiled.Segments[0].Placeholder(); !ok || placeholder.Field != "ccrn" {
+		issue(SeverityError, 1, "the first segment must be <ccrn>, it is used to look up the template of a URN")
+	}
+
+	seen := map[string]int{}
+	for i, segment := range compiled.Segments {
+		for _, field := range segment.Fields() {
+			if first, exists := seen[field]; exists {
+				issue(SeverityError, i+1, "duplicate placeholder <%s>, first used in segment %d", field, first)
+				continue
+			}
+			seen[field] = i + 1
+		}
+		for _, message := range ambiguities(compiled, i) {
+			issue(SeverityWarning, i+1, "%s", message)
+		}
+	}
+
+	if schema == nil || schema.Schema == nil {
+		return issues
+	}
+	for i, segment := range compiled.Segments {
+		for _, field := range segment.Fields() {
+			if !schema.HasField(field) && field != "ccrn" && seen[field] == i+1 {
+				issue(SeverityError, i+1, "placeholder <%s> is not a field of %s", field, schema.Kind)
+			}
+		}
+	}
+	for _, field := range schema.FieldsSchema().Required {
+		if _, used := seen[field]; !used && field != "ccrn" {
+			issue(SeverityWarning, 0, "required field %s of %s is not part of the template, URNs of different resources may collide", field, schema.Kind)
+		}
+	}
+	return issues
+}
+
+// ambiguities describes why the segment i of the template may not be matched as intended
+func ambiguities(template *apis.Template, i int) []string {
+	var messages []string
+	segment := template.Segments[i]
+
+	var previous *apis.TemplatePart
+	for j := range segment.Parts {
+		part := &segment.Parts[j]
+		if previous != nil && previous.Field != "" && part.Field != "" && previous.Constraint == "" {
+			messages = append(messages, fmt.Sprintf("adjacent placeholders %s%s are split after the first character, separate them by a literal or constrain %s", previous.Raw, part.Raw, previous.Raw))
+		}
+		previous = part
+	}
+
+	if !segment.Optional || !unconstrained(segment) || i+1 >= len(template.Segments) {
+		return messages
+	}
+	next := template.Segments[i+1]
+	switch {
+	case next.Optional && unconstrained(next):
+		messages = append(messages, fmt.Sprintf("optional segments %s and %s cannot be told apart without constraints, the first one is matched first", segment.Raw, next.Raw))
+	case i+1 == len(template.Segments)-1 && len(next.Fields()) > 0:
+		messages = append(messages, fmt.Sprintf("optional segment %s is followed by %s which takes all remaining segments, a value containing '/' is split between them", segment.Raw, next.Raw))
+	}
+	return messages
+}
+
+// unconstrained reports whether the segment consists of a single placeholder accepting any value
+func unconstrained(segment apis.TemplateSegment) bool {
+	placeholder, ok := segment.Placeholder()
+	return ok && placeholder.Constraint == ""
+}
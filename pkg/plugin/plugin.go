@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugin runs custom validation plugins against CCRN resources, see webhook.PolicyEngine.
+// Plugins let product teams add organization specific checks without forking the webhook. They are
+// executables or WebAssembly modules (WASI commands) speaking the same protocol: the webhook.PolicyInput
+// is written as JSON to stdin, and the plugin writes a webhook.PolicyDecision as JSON to stdout, e.g.
+//
+//	{"denials": ["clusters of team shop must be in eu-de"], "warnings": []}
+//
+// Plugins exiting with a non-zero code fail the evaluation, which denies the request.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// DefaultTimeout is the default time a plugin may take to evaluate a resource
+const DefaultTimeout = 5 * time.Second
+
+// Load loads a plugin from a file, modules ending in .wasm run in a WebAssembly runtime and
+// all other files are executed
+func Load(ctx context.Context, file string, timeout time.Duration) (webhook.PolicyEngine, error) {
+	if strings.HasSuffix(file, ".wasm") {
+		return LoadWASMPlugin(ctx, file, timeout)
+	}
+	return NewExecPlugin(file, timeout), nil
+}
+
+// ExecPlugin runs an executable for every evaluation
+type ExecPlugin struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+var _ webhook.PolicyEngine = &ExecPlugin{}
+
+// NewExecPlugin creates a plugin running the command with the arguments, evaluations are killed after the timeout
+func NewExecPlugin(command string, timeout time.Duration, args ...string) *ExecPlugin {
+	return &ExecPlugin{command: command, args: args, timeout: timeout}
+}
+
+// Evaluate implements webhook.PolicyEngine
+func (p *ExecPlugin) Evaluate(ctx context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin input: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, pluginError(p.command, err, &stderr)
+	}
+	return decode(p.command, &stdout)
+}
+
+// WASMPlugin runs a WebAssembly module compiled for WASI, a new instance is started for every evaluation
+type WASMPlugin struct {
+	name    string
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	timeout time.Duration
+}
+
+var _ webhook.PolicyEngine = &WASMPlugin{}
+
+// LoadWASMPlugin compiles the WebAssembly module of the file, evaluations are aborted after the timeout.
+// The plugin must be closed to release the runtime.
+func LoadWASMPlugin(ctx context.Context, file string, timeout time.Duration) (*WASMPlugin, error) {
+	binary, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WASM plugin: %w", err)
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+	module, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to compile WASM plugin %s: %w", file, err)
+	}
+	return &WASMPlugin{name: filepath.Base(file), runtime: runtime, module: module, timeout: timeout}, nil
+}
+
+// Evaluate implements webhook.PolicyEngine
+func (p *WASMPlugin) Evaluate(ctx context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin input: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	// Instances are anonymous, so evaluations can run concurrently
+	config := wazero.NewModuleConfig().WithName("").WithArgs(p.name).
+		WithStdin(bytes.NewReader(stdin)).WithStdout(&stdout).WithStderr(&stderr)
+	instance, err := p.runtime.InstantiateModule(ctx, p.module, config)
+	if instance != nil {
+		_ = instance.Close(ctx)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, pluginError(p.name, err, &stderr)
+	}
+	return decode(p.name, &stdout)
+}
+
+// Close releases the runtime of the plugin
+func (p *WASMPlugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
+
+// decode reads the decision a plugin has written to stdout
+func decode(name string, stdout *bytes.Buffer) (*webhook.PolicyDecision, error) {
+	decision := &webhook.PolicyDecision{}
+	if err := json.Unmarshal(stdout.Bytes(), decision); err != nil {
+		return nil, fmt.Errorf("plugin %s returned an invalid decision: %w", name, err)
+	}
+	return decision, nil
+}
+
+// pluginError returns the error of a failed plugin including its error output
+func pluginError(name string, err error, stderr *bytes.Buffer) error {
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return fmt.Errorf("plugin %s failed: %w: %s", name, err, message)
+	}
+	return fmt.Errorf("plugin %s failed: %w", name, err)
+}
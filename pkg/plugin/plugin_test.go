@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/plugin"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+func TestPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Plugin Suite")
+}
+
+// Paths of the test plugin built as executable and as WASI module
+var execPath, wasmPath string
+
+var _ = BeforeSuite(func() {
+	dir := GinkgoT().TempDir()
+	execPath = filepath.Join(dir, "plugin")
+	wasmPath = filepath.Join(dir, "plugin.wasm")
+
+	build := exec.Command("go", "build", "-o", execPath, "./testdata/plugin")
+	build.Stderr = GinkgoWriter
+	Expect(build.Run()).To(Succeed())
+	build = exec.Command("go", "build", "-o", wasmPath, "./testdata/plugin")
+	build.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	build.Stderr = GinkgoWriter
+	Expect(build.Run()).To(Succeed())
+})
+
+var _ = Describe("Plugins", func() {
+	ctx := context.Background()
+
+	input := func(namespace, cluster, name string) *webhook.PolicyInput {
+		return &webhook.PolicyInput{
+			Resource: &apis.ParsedResource{Format: "CCRN", Fields: map[string]string{"ccrn": "pod.k8s.ccrn.example.com/v1", "cluster": cluster, "name": name}},
+			Request:  webhook.PolicyRequest{Operation: "CREATE", Namespace: namespace, Name: name},
+		}
+	}
+
+	DescribeTable("evaluate resources",
+		func(load func() webhook.PolicyEngine) {
+			// Arrange
+			engine := load()
+			// Act
+			allowed, errAllowed := engine.Evaluate(ctx, input("prod", "eu-de-1", "shop-web"))
+			denied, errDenied := engine.Evaluate(ctx, input("prod", "*", "web"))
+			// Assert
+			Expect(errAllowed).ToNot(HaveOccurred())
+			Expect(allowed.Denials).To(BeEmpty())
+			Expect(allowed.Warnings).To(BeEmpty())
+			Expect(errDenied).ToNot(HaveOccurred())
+			Expect(denied.Denials).To(Equal([]string{"production resources must not use wildcard clusters"}))
+			Expect(denied.Warnings).To(Equal([]string{"names should have at least four characters"}))
+		},
+		Entry("as executable", func() webhook.PolicyEngine {
+			return plugin.NewExecPlugin(execPath, time.Second)
+		}),
+		Entry("as WASM module", func() webhook.PolicyEngine {
+			engine, err := plugin.Load(ctx, wasmPath, 5*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(engine.(*plugin.WASMPlugin).Close, ctx)
+			return engine
+		}),
+	)
+
+	DescribeTable("fail with the error output of plugins",
+		func(load func() webhook.PolicyEngine) {
+			// Act
+			_, err := load().Evaluate(ctx, input("prod", "eu-de-1", "crash"))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("plugin crashed")))
+		},
+		Entry("as executable", func() webhook.PolicyEngine {
+			return plugin.NewExecPlugin(execPath, time.Second)
+		}),
+		Entry("as WASM module", func() webhook.PolicyEngine {
+			engine, err := plugin.LoadWASMPlugin(ctx, wasmPath, 5*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(engine.Close, ctx)
+			return engine
+		}),
+	)
+
+	DescribeTable("abort plugins exceeding the timeout",
+		func(load func() webhook.PolicyEngine) {
+			// Act
+			_, err := load().Evaluate(ctx, input("prod", "eu-de-1", "hang"))
+			// Assert
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		},
+		Entry("as executable", func() webhook.PolicyEngine {
+			return plugin.NewExecPlugin(execPath, 200*time.Millisecond)
+		}),
+		Entry("as WASM module", func() webhook.PolicyEngine {
+			engine, err := plugin.LoadWASMPlugin(ctx, wasmPath, 500*time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(engine.Close, ctx)
+			return engine
+		}),
+	)
+
+	It("rejects invalid decisions", func() {
+		// Arrange
+		engine := plugin.NewExecPlugin("echo", time.Second, "denied")
+		// Act
+		_, err := engine.Evaluate(ctx, input("prod", "eu-de-1", "web"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("plugin echo returned an invalid decision")))
+	})
+
+	It("rejects invalid WASM modules", func() {
+		// Arrange
+		file := filepath.Join(GinkgoT().TempDir(), "invalid.wasm")
+		Expect(os.WriteFile(file, []byte("not wasm"), 0o600)).To(Succeed())
+		// Act
+		_, err := plugin.Load(ctx, file, time.Second)
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to compile WASM plugin")))
+	})
+})
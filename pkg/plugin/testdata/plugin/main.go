@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Command plugin is a validation plugin used by the tests, built as executable and as WASI module
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+type input struct {
+	Resource struct {
+		Fields map[string]string `json:"fields"`
+	} `json:"resource"`
+	Request struct {
+		Namespace string `json:"namespace"`
+	} `json:"request"`
+}
+
+type decision struct {
+	Denials  []string `json:"denials"`
+	Warnings []string `json:"warnings"`
+}
+
+func main() {
+	var in input
+	if err := json.NewDecoder(os.Stdin).Decode(&in); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid input: %v\n", err)
+		os.Exit(1)
+	}
+
+	var out decision
+	switch in.Resource.Fields["name"] {
+	case "crash":
+		fmt.Fprintln(os.Stderr, "plugin crashed")
+		os.Exit(2)
+	case "hang":
+		for {
+			runtime.Gosched()
+		}
+	}
+	if in.Resource.Fields["cluster"] == "*" && in.Request.Namespace == "prod" {
+		out.Denials = append(out.Denials, "production resources must not use wildcard clusters")
+	}
+	if len(in.Resource.Fields["name"]) < 4 {
+		out.Warnings = append(out.Warnings, "names should have at least four characters")
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(out)
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var ruleEvaluationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "ccrn_policy_rule_evaluations_total",
+	Help: "Total number of naming rule evaluations by rule, mode and result (pass or violation).",
+}, []string{"rule", "mode", "result"})
+
+// Collector returns the collector of the rule evaluations, it is registered by the binary loading the rules
+func Collector() prometheus.Collector {
+	return ruleEvaluationsTotal
+}
+
+// recordResult records the evaluation of a rule that applied to a resource
+func recordResult(rule *Rule, violated bool) {
+	result := "pass"
+	if violated {
+		result = "violation"
+	}
+	ruleEvaluationsTotal.WithLabelValues(rule.Name, rule.Mode, result).Inc()
+}
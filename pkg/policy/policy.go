@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package policy evaluates declarative naming rules against validated CCRN resources, see webhook.PolicyEngine.
+// Rules are configured in YAML and restrict the values of fields beyond the CRD schemas: values must match
+// a regular expression, must not be one of the forbidden values or must start with a prefix, optionally only
+// in some namespaces or for some teams. Violations deny the resource or are returned as warnings, e.g.
+//
+//	teamLabel: team
+//	rules:
+//	- name: no-wildcard-cluster-in-production
+//	  namespaces: ["prod-*"]
+//	  field: cluster
+//	  forbidden: ["*"]
+//	- name: team-prefixed-names
+//	  mode: warn
+//	  field: name
+//	  prefix: "${team}-"
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// Modes of rules
+const (
+	ModeDeny = "deny" // Violations reject the resource
+	ModeWarn = "warn" // Violations are returned as warnings
+)
+
+// DefaultTeamLabel is the default label of CCRN objects naming the team that owns them
+const DefaultTeamLabel = "team"
+
+// Config is the YAML configuration of the rules
+type Config struct {
+	TeamLabel string `json:"teamLabel,omitempty"` // Label of CCRN objects naming their team, defaults to DefaultTeamLabel
+	Rules     []Rule `json:"rules"`
+}
+
+// Rule restricts the values of a field. Resources without the field are not affected.
+type Rule struct {
+	Name          string   `json:"name"`                    // Unique name, used in messages and metrics
+	Mode          string   `json:"mode,omitempty"`          // ModeDeny or ModeWarn, defaults to ModeDeny
+	Message       string   `json:"message,omitempty"`       // Message of violations, defaults to a description of the check
+	Namespaces    []string `json:"namespaces,omitempty"`    // Glob patterns of the namespaces the rule applies to, all if empty
+	Teams         []string `json:"teams,omitempty"`         // Teams the rule applies to, all if empty
+	ResourceTypes []string `json:"resourceTypes,omitempty"` // Resource types "<kind>.<group>" the rule applies to, all if empty
+	Field         string   `json:"field"`                   // Field whose value is checked, e.g. "cluster" or "labels.app"
+	Pattern       string   `json:"pattern,omitempty"`       // Regular expression the value must match
+	Forbidden     []string `json:"forbidden,omitempty"`     // Values the field must not have
+	Prefix        string   `json:"prefix,omitempty"`        // Prefix the value must start with, ${namespace} and ${team} are replaced
+
+	pattern *regexp.Regexp
+}
+
+// Engine evaluates the rules of a configuration, it implements webhook.PolicyEngine
+type Engine struct {
+	teamLabel string
+	rules     []Rule
+}
+
+var _ webhook.PolicyEngine = &Engine{}
+
+// NewEngine validates the configuration and creates an engine evaluating its rules
+func NewEngine(config Config) (*Engine, error) {
+	engine := &Engine{teamLabel: config.TeamLabel, rules: make([]Rule, 0, len(config.Rules))}
+	if engine.teamLabel == "" {
+		engine.teamLabel = DefaultTeamLabel
+	}
+
+	var errs []error
+	names := map[string]bool{}
+	for i, rule := range config.Rules {
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("rule %d: name is required", i+1))
+			continue
+		}
+		if names[rule.Name] {
+			errs = append(errs, fmt.Errorf("rule %s: duplicate name", rule.Name))
+		}
+		names[rule.Name] = true
+		if rule.Mode == "" {
+			rule.Mode = ModeDeny
+		}
+		if rule.Mode != ModeDeny && rule.Mode != ModeWarn {
+			errs = append(errs, fmt.Errorf("rule %s: invalid mode %q, use %s or %s", rule.Name, rule.Mode, ModeDeny, ModeWarn))
+		}
+		if rule.Field == "" {
+			errs = append(errs, fmt.Errorf("rule %s: field is required", rule.Name))
+		}
+		if rule.Pattern == "" && len(rule.Forbidden) == 0 && rule.Prefix == "" {
+			errs = append(errs, fmt.Errorf("rule %s: requires pattern, forbidden or prefix", rule.Name))
+		}
+		for _, namespace := range rule.Namespaces {
+			if _, err := path.Match(namespace, ""); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: invalid namespace pattern %q: %v", rule.Name, namespace, err))
+			}
+		}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: invalid pattern: %v", rule.Name, err))
+			}
+			rule.pattern = pattern
+		}
+		engine.rules = append(engine.rules, rule)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return engine, nil
+}
+
+// LoadEngine creates an engine from a YAML configuration file
+func LoadEngine(file string) (*Engine, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy rules: %w", err)
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse policy rules %s: %w", file, err)
+	}
+	engine, err := NewEngine(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy rules %s: %w", file, err)
+	}
+	return engine, nil
+}
+
+// Evaluate implements webhook.PolicyEngine, the violations of all rules are returned
+func (e *Engine) Evaluate(_ context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	decision := &webhook.PolicyDecision{}
+	team := input.Request.Labels[e.teamLabel]
+	for i := range e.rules {
+		rule := &e.rules[i]
+		value, exists := input.Resource.Fields[rule.Field]
+		if !exists || !rule.applies(input, team) {
+			continue
+		}
+		violation := rule.check(value, input.Request.Namespace, team)
+		recordResult(rule, violation != "")
+		switch {
+		case violation == "":
+		case rule.Mode == ModeWarn:
+			decision.Warnings = append(decision.Warnings, violation)
+		default:
+			decision.Denials = append(decision.Denials, violation)
+		}
+	}
+	return decision, nil
+}
+
+// applies reports whether the rule applies to the namespace, team and resource type of the input
+func (r *Rule) applies(input *webhook.PolicyInput, team string) bool {
+	if len(r.Namespaces) > 0 && !slices.ContainsFunc(r.Namespaces, func(pattern string) bool {
+		matched, _ := path.Match(pattern, input.Request.Namespace)
+		return matched
+	}) {
+		return false
+	}
+	if len(r.Teams) > 0 && !slices.Contains(r.Teams, team) {
+		return false
+	}
+	return len(r.ResourceTypes) == 0 || slices.ContainsFunc(r.ResourceTypes, func(resourceType string) bool {
+		return strings.EqualFold(resourceType, input.Resource.CCRNName())
+	})
+}
+
+// check returns the message of the violation of the rule by the value, or "" if the value complies
+func (r *Rule) check(value, namespace, team string) string {
+	var violation string
+	prefix := strings.NewReplacer("${namespace}", namespace, "${team}", team).Replace(r.Prefix)
+	switch {
+	case slices.Contains(r.Forbidden, value):
+		violation = fmt.Sprintf("value '%s' of field %s is forbidden", value, r.Field)
+	case r.pattern != nil && !r.pattern.MatchString(value):
+		violation = fmt.Sprintf("value '%s' of field %s does not match %s", value, r.Field, r.Pattern)
+	case r.Prefix != "" && !strings.HasPrefix(value, prefix):
+		violation = fmt.Sprintf("value '%s' of field %s must start with '%s'", value, r.Field, prefix)
+	default:
+		return ""
+	}
+	if r.Message != "" {
+		violation = r.Message
+	}
+	return r.Name + ": " + violation
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/policy"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+func TestPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Policy Suite")
+}
+
+var _ = Describe("Engine", func() {
+	ctx := context.Background()
+
+	input := func(namespace, cluster, name string, labels map[string]string) *webhook.PolicyInput {
+		return &webhook.PolicyInput{
+			Resource: &apis.ParsedResource{Format: "CCRN", Fields: map[string]string{"ccrn": "pod.k8s.ccrn.example.com/v1", "cluster": cluster, "name": name}},
+			Request:  webhook.PolicyRequest{Operation: "CREATE", Namespace: namespace, Name: name, Labels: labels},
+		}
+	}
+
+	Context("loaded from a file", func() {
+		var engine *policy.Engine
+
+		BeforeEach(func() {
+			var err error
+			engine, err = policy.LoadEngine(filepath.Join("testdata", "rules.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("allows resources complying with all rules", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("prod-shop", "eu-de-1", "shop-web", map[string]string{"team": "shop"}))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(BeEmpty())
+			Expect(decision.Warnings).To(BeEmpty())
+		})
+
+		It("denies forbidden values in matching namespaces", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("prod-shop", "*", "shop-web", map[string]string{"team": "shop"}))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(Equal([]string{"no-wildcard-cluster-in-production: production namespaces must not use wildcard cluster fields"}))
+		})
+
+		It("does not apply rules to other namespaces", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("dev-shop", "*", "web", nil))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(BeEmpty())
+			Expect(decision.Warnings).To(BeEmpty())
+		})
+
+		It("denies values not matching the pattern", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("dev-shop", "cluster-1", "web", nil))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(Equal([]string{`region-cluster-names: value 'cluster-1' of field cluster does not match ^(\*|[a-z]{2}-[a-z]{2}-[0-9])$`}))
+		})
+
+		It("returns violations of warn rules as warnings with the prefix of the team", func() {
+			// Act
+			decision, err := engine.Evaluate(ctx, input("dev-shop", "eu-de-1", "web", map[string]string{"team": "shop"}))
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decision.Denials).To(BeEmpty())
+			Expect(decision.Warnings).To(Equal([]string{"team-prefixed-names: value 'web' of field name must start with 'shop-'"}))
+		})
+	})
+
+	It("skips resources without the field of a rule", func() {
+		// Arrange
+		engine, err := policy.NewEngine(policy.Config{Rules: []policy.Rule{{Name: "zones", Field: "zone", Forbidden: []string{"*"}}}})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		decision, err := engine.Evaluate(ctx, input("default", "*", "web", nil))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.Denials).To(BeEmpty())
+	})
+
+	It("replaces the namespace in prefixes", func() {
+		// Arrange
+		engine, err := policy.NewEngine(policy.Config{Rules: []policy.Rule{{Name: "namespaced", Field: "name", Prefix: "${namespace}-"}}})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		decision, err := engine.Evaluate(ctx, input("shop", "eu-de-1", "web", nil))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decision.Denials).To(Equal([]string{"namespaced: value 'web' of field name must start with 'shop-'"}))
+	})
+
+	It("rejects invalid rules", func() {
+		// Act
+		_, err := policy.NewEngine(policy.Config{Rules: []policy.Rule{
+			{Name: "a", Field: "name", Pattern: "("},
+			{Name: "a", Field: "name", Mode: "block", Prefix: "x"},
+			{Name: "b", Field: "name"},
+			{Field: "name", Prefix: "x"},
+		}})
+		// Assert
+		Expect(err).To(HaveOccurred())
+		Expect(apis.ErrorMessages(err)).To(ConsistOf(
+			ContainSubstring("rule a: invalid pattern"),
+			"rule a: duplicate name",
+			`rule a: invalid mode "block", use deny or warn`,
+			"rule b: requires pattern, forbidden or prefix",
+			"rule 4: name is required",
+		))
+	})
+
+	It("rejects unknown keys in configuration files", func() {
+		// Act
+		_, err := policy.LoadEngine(filepath.Join("testdata", "invalid.yaml"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to parse policy rules")))
+	})
+})
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind classifies the tokens of a query
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator // ==, !=, =~, !~, &&, ||, !
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexical unit of a query and its byte offset
+type token struct {
+	kind   tokenKind
+	text   string // Identifier, unquoted string or operator
+	offset int
+}
+
+// SyntaxError describes where and why a query could not be parsed
+type SyntaxError struct {
+	Input    string // The query
+	Offset   int    // Byte offset of the offending token
+	Expected string // What the parser expected
+	Got      string // What it found instead
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	got := e.Got
+	if got == "" {
+		got = "end of query"
+	}
+	return fmt.Sprintf("invalid query at offset %d: expected %s, got %s", e.Offset, e.Expected, got)
+}
+
+// isIdentChar reports whether c may be part of an identifier, dots allow nested field paths
+func isIdentChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9', c == '.', c == '-':
+		return !first
+	}
+	return false
+}
+
+// tokenize splits the query into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", offset: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", offset: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", offset: i})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(input) && input[end] != '"' {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, &SyntaxError{Input: input, Offset: i, Expected: "closing quote", Got: input[i:]}
+			}
+			value, err := strconv.Unquote(input[i : end+1])
+			if err != nil {
+				return nil, &SyntaxError{Input: input, Offset: i, Expected: "valid string literal", Got: input[i : end+1]}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, offset: i})
+			i = end + 1
+		case isIdentChar(c, true):
+			end := i + 1
+			for end < len(input) && isIdentChar(input[end], false) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[i:end], offset: i})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range []string{"==", "!=", "=~", "!~", "&&", "||", "!"} {
+				if strings.HasPrefix(input[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, &SyntaxError{Input: input, Offset: i, Expected: "operator, identifier or string", Got: string(c)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, offset: i})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, offset: len(input)}), nil
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"regexp"
+)
+
+// parser is a recursive descent parser over the tokens of a query. From lowest to highest precedence:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" or ")" | "has" "(" ident ")" | ident ( "==" | "!=" | "=~" | "!~" ) string | ident "in" "(" string { "," string } ")"
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+// peek returns the next token without consuming it
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes the next token, the final EOF token is never consumed
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// errorf creates a syntax error at the token
+func (p *parser) errorf(t token, expected string) error {
+	got := t.text
+	if t.kind == tokenString {
+		got = `"` + t.text + `"`
+	}
+	return &SyntaxError{Input: p.input, Offset: t.offset, Expected: expected, Got: got}
+}
+
+// expect consumes the next token if it is of the kind, fails otherwise
+func (p *parser) expect(kind tokenKind, expected string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, expected)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenOperator && p.peek().text == "!" {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case t.kind == tokenIdent && t.text == "has" && p.peek().kind == tokenLParen:
+		p.next()
+		name, err := p.expect(tokenIdent, "field name")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return &hasNode{name: name.text}, nil
+	case t.kind != tokenIdent:
+		return nil, p.errorf(t, "field name, !, has( or (")
+	}
+
+	operator := p.next()
+	switch {
+	case operator.kind == tokenIdent && operator.text == "in":
+		return p.parseIn(t.text)
+	case operator.kind != tokenOperator || !isComparison(operator.text):
+		return nil, p.errorf(operator, "==, !=, =~, !~ or in")
+	}
+	value, err := p.expect(tokenString, "string")
+	if err != nil {
+		return nil, err
+	}
+	compare := &compareNode{name: t.text, operator: operator.text, value: value.text}
+	if operator.text == "=~" || operator.text == "!~" {
+		if compare.pattern, err = regexp.Compile("^(?:" + value.text + ")$"); err != nil {
+			return nil, p.errorf(value, "valid regular expression")
+		}
+	}
+	return compare, nil
+}
+
+// parseIn parses the parenthesized string list of an in comparison
+func (p *parser) parseIn(name string) (node, error) {
+	if _, err := p.expect(tokenLParen, "("); err != nil {
+		return nil, err
+	}
+	in := &inNode{name: name}
+	for {
+		value, err := p.expect(tokenString, "string")
+		if err != nil {
+			return nil, err
+		}
+		in.values = append(in.values, value.text)
+		separator := p.next()
+		if separator.kind == tokenRParen {
+			return in, nil
+		}
+		if separator.kind != tokenComma {
+			return nil, p.errorf(separator, ", or )")
+		}
+	}
+}
+
+// isComparison reports whether the operator compares an attribute with a value
+func isComparison(operator string) bool {
+	switch operator {
+	case "==", "!=", "=~", "!~":
+		return true
+	}
+	return false
+}
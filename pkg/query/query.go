@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package query implements a small expression language selecting parsed CCRNs, e.g. for filters and policy rules:
+//
+//	kind == "pod" && cluster in ("eu-de-1", "eu-de-2") && name =~ "api-.*"
+//
+// Identifiers refer to the fields of a CCRN, nested fields use dot paths like labels.app. The identifiers kind,
+// group and version refer to the resource type of the CCRN instead, kind and group compare case-insensitively.
+// Comparisons are == and != for equality, =~ and !~ for regular expressions matching the whole value and in for
+// a list of strings. has(field) tests for the presence of a field. Expressions combine with &&, || and !,
+// parentheses group them. A missing field only satisfies != and !~.
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Query is a parsed query, it is safe for concurrent use
+type Query struct {
+	root node
+	raw  string
+}
+
+// Parse parses a query, syntax errors are reported as *SyntaxError
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: input, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.errorf(next, "&&, || or end of query")
+	}
+	return &Query{root: root, raw: input}, nil
+}
+
+// MustParse parses a query and panics on syntax errors, it is meant for queries known at compile time
+func MustParse(input string) *Query {
+	q, err := Parse(input)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Match reports whether the resource satisfies the query
+func (q *Query) Match(resource *apis.ParsedResource) bool {
+	return resource != nil && q.root.match(resource)
+}
+
+// Filter returns the resources satisfying the query in their original order
+func (q *Query) Filter(resources []*apis.ParsedResource) []*apis.ParsedResource {
+	var matched []*apis.ParsedResource
+	for _, resource := range resources {
+		if q.Match(resource) {
+			matched = append(matched, resource)
+		}
+	}
+	return matched
+}
+
+// String returns the query in normalized form with minimal parentheses
+func (q *Query) String() string {
+	return q.root.String()
+}
+
+// node is an element of the syntax tree of a query
+type node interface {
+	match(resource *apis.ParsedResource) bool
+	String() string
+}
+
+// attribute returns the value of the identifier for the resource and whether it is set
+func attribute(resource *apis.ParsedResource, name string) (string, bool) {
+	if _, hasType := resource.Fields["ccrn"]; hasType {
+		switch name {
+		case "kind":
+			return resource.GetKind(), true
+		case "group":
+			return resource.ApiGroup(), true
+		case "version":
+			return resource.Version(), true
+		}
+	}
+	value, ok := resource.Fields[name]
+	return value, ok
+}
+
+// caseInsensitive reports whether values of the identifier compare case-insensitively
+func caseInsensitive(name string) bool {
+	return name == "kind" || name == "group"
+}
+
+// binaryNode combines two expressions with && or ||
+type binaryNode struct {
+	operator    string
+	left, right node
+}
+
+func (n *binaryNode) match(resource *apis.ParsedResource) bool {
+	if n.operator == "&&" {
+		return n.left.match(resource) && n.right.match(resource)
+	}
+	return n.left.match(resource) || n.right.match(resource)
+}
+
+func (n *binaryNode) String() string {
+	operand := func(child node) string {
+		if inner, ok := child.(*binaryNode); ok && n.operator == "&&" && inner.operator == "||" {
+			return "(" + inner.String() + ")"
+		}
+		return child.String()
+	}
+	return operand(n.left) + " " + n.operator + " " + operand(n.right)
+}
+
+// notNode negates an expression
+type notNode struct {
+	expr node
+}
+
+func (n *notNode) match(resource *apis.ParsedResource) bool {
+	return !n.expr.match(resource)
+}
+
+func (n *notNode) String() string {
+	if _, ok := n.expr.(*binaryNode); ok {
+		return "!(" + n.expr.String() + ")"
+	}
+	return "!" + n.expr.String()
+}
+
+// compareNode compares an attribute with a value or regular expression
+type compareNode struct {
+	name     string
+	operator string
+	value    string
+	pattern  *regexp.Regexp // Compiled value of =~ and !~
+}
+
+func (n *compareNode) match(resource *apis.ParsedResource) bool {
+	value, ok := attribute(resource, n.name)
+	switch n.operator {
+	case "==":
+		return ok && equal(n.name, value, n.value)
+	case "!=":
+		return !ok || !equal(n.name, value, n.value)
+	case "=~":
+		return ok && n.pattern.MatchString(value)
+	default:
+		return !ok || !n.pattern.MatchString(value)
+	}
+}
+
+func (n *compareNode) String() string {
+	return n.name + " " + n.operator + " " + strconv.Quote(n.value)
+}
+
+// equal compares the values of the identifier
+func equal(name, a, b string) bool {
+	if caseInsensitive(name) {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// inNode tests whether an attribute has one of the listed values
+type inNode struct {
+	name   string
+	values []string
+}
+
+func (n *inNode) match(resource *apis.ParsedResource) bool {
+	value, ok := attribute(resource, n.name)
+	if !ok {
+		return false
+	}
+	for _, candidate := range n.values {
+		if equal(n.name, value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *inNode) String() string {
+	quoted := make([]string, 0, len(n.values))
+	for _, value := range n.values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return n.name + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// hasNode tests for the presence of an attribute
+type hasNode struct {
+	name string
+}
+
+func (n *hasNode) match(resource *apis.ParsedResource) bool {
+	_, ok := attribute(resource, n.name)
+	return ok
+}
+
+func (n *hasNode) String() string {
+	return "has(" + n.name + ")"
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package query_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/query"
+)
+
+func TestQuery(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Query Suite")
+}
+
+var _ = Describe("Query", func() {
+	parse := func(ccrn string) *apis.ParsedResource {
+		parsed, err := apis.ParseCCRN(ccrn)
+		Expect(err).ToNot(HaveOccurred())
+		return parsed
+	}
+	apiPod := parse("ccrn=Pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, namespace=shop, name=api-7f9, labels.app=shop")
+	webPod := parse("ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-2, namespace=shop, name=web-1")
+	node := parse("ccrn=node.k8s.ccrn.example.com/v1beta1, cluster=eu-de-1, name=api-node")
+
+	DescribeTable("matches resources",
+		func(input string, expected []bool) {
+			// Arrange
+			q, err := query.Parse(input)
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			matched := []bool{q.Match(apiPod), q.Match(webPod), q.Match(node)}
+			// Assert
+			Expect(matched).To(Equal(expected))
+		},
+		Entry("equality", `cluster == "eu-de-1"`, []bool{true, false, true}),
+		Entry("resource type case-insensitively", `kind == "pod"`, []bool{true, true, false}),
+		Entry("version", `version != "v1"`, []bool{false, false, true}),
+		Entry("in", `cluster in ("eu-de-1", "eu-de-2") && kind == "pod"`, []bool{true, true, false}),
+		Entry("regular expression matching the whole value", `name =~ "api-.*"`, []bool{true, false, true}),
+		Entry("regular expression partially matching", `name =~ "api"`, []bool{false, false, false}),
+		Entry("missing fields", `namespace != "shop"`, []bool{false, false, true}),
+		Entry("presence", `has(labels.app)`, []bool{true, false, false}),
+		Entry("negation and grouping", `!(kind == "node" || cluster == "eu-de-2")`, []bool{true, false, false}),
+		Entry("precedence of && over ||", `kind == "node" || kind == "pod" && name !~ "api-.*"`, []bool{false, true, true}),
+		Entry("the example of the documentation", `kind == "pod" && cluster in ("eu-de-1","eu-de-2") && name =~ "api-.*"`, []bool{true, false, false}),
+	)
+
+	It("filters resources in order", func() {
+		// Act
+		matched := query.MustParse(`cluster == "eu-de-1"`).Filter([]*apis.ParsedResource{node, webPod, apiPod})
+		// Assert
+		Expect(matched).To(Equal([]*apis.ParsedResource{node, apiPod}))
+	})
+
+	It("normalizes queries", func() {
+		// Act
+		q := query.MustParse(`(kind=="pod"||kind=="node")&&!has(name) && cluster in ("a","b\"c")`)
+		// Assert
+		Expect(q.String()).To(Equal(`(kind == "pod" || kind == "node") && !has(name) && cluster in ("a", "b\"c")`))
+		Expect(query.MustParse(q.String()).String()).To(Equal(q.String()))
+	})
+
+	DescribeTable("reports syntax errors with their offset",
+		func(input string, offset int, expected string) {
+			// Act
+			_, err := query.Parse(input)
+			// Assert
+			var syntaxErr *query.SyntaxError
+			Expect(errors.As(err, &syntaxErr)).To(BeTrue())
+			Expect(syntaxErr.Offset).To(Equal(offset))
+			Expect(syntaxErr.Expected).To(ContainSubstring(expected))
+		},
+		Entry("missing value", `kind ==`, 7, "string"),
+		Entry("unknown operator", `kind > "pod"`, 5, "operator"),
+		Entry("unterminated string", `kind == "pod`, 8, "closing quote"),
+		Entry("missing closing parenthesis", `(kind == "pod"`, 14, ")"),
+		Entry("trailing tokens", `kind == "pod" name == "x"`, 14, "&&"),
+		Entry("invalid regular expression", `name =~ "("`, 8, "regular expression"),
+		Entry("empty in list", `cluster in ()`, 12, "string"),
+	)
+})
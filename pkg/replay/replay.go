@@ -0,0 +1,424 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replay records admission requests of the webhook and replays them against another build or CRD bundle.
+// Recorded requests are sanitized: users and groups are pseudonymized and objects are reduced to an allowlist of
+// the metadata, the CCRN annotations and the paths the webhook reads, like spec.ccrn or the object mapping of a
+// CRD, so specs with environment variables or commands are never recorded. Pseudonyms are keyed hashes, so they
+// cannot be reversed by hashing candidate usernames without the key. Replaying compares the decisions and patches
+// of the recorded responses with the responses of the handler under test, so schema upgrades can be validated
+// against real traffic before they are rolled out.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Record is a recorded admission request together with the response of the webhook
+type Record struct {
+	Timestamp time.Time                      `json:"timestamp"`
+	Path      string                         `json:"path"` // Path of the webhook handler, e.g. /validate
+	Request   *admissionv1.AdmissionRequest  `json:"request"`
+	Response  *admissionv1.AdmissionResponse `json:"response"`
+}
+
+// Recorder persists recorded admission requests
+type Recorder interface {
+	// Record persists a single record, the request is already sanitized
+	Record(ctx context.Context, record Record) error
+
+	// Close flushes pending records and releases resources
+	Close() error
+}
+
+// ErrRecordingFull is returned by FileRecorder once the recording reached its maximum size
+var ErrRecordingFull = errors.New("the recording reached its maximum size")
+
+// FileRecorder writes records as JSON lines into a file up to a maximum size, further records are dropped
+type FileRecorder struct {
+	path    string
+	maxSize int64
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+// NewFileRecorder opens (or creates) the file records are appended to, including its directory. A maximum size
+// of 0 does not limit the recording.
+func NewFileRecorder(path string, maxSize int64) (*FileRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of recording file %s: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat recording file %s: %w", path, err)
+	}
+	return &FileRecorder{path: path, maxSize: maxSize, file: file, size: info.Size()}, nil
+}
+
+// Record appends the record to the file, ErrRecordingFull if it would exceed the maximum size
+func (f *FileRecorder) Record(_ context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	line = append(line, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.maxSize > 0 && f.size+int64(len(line)) > f.maxSize {
+		return ErrRecordingFull
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (f *FileRecorder) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.file.Close()
+}
+
+// BufferedRecorder decouples admission handling from the recorder. Records are written by a background worker,
+// when the buffer is full records are dropped and logged.
+type BufferedRecorder struct {
+	log      *logrus.Logger
+	recorder Recorder
+	records  chan Record
+	done     chan struct{}
+
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// NewBufferedRecorder wraps the recorder with a buffer of the given size
+func NewBufferedRecorder(log *logrus.Logger, recorder Recorder, size int) *BufferedRecorder {
+	if log == nil {
+		log = logrus.New()
+	}
+	b := &BufferedRecorder{
+		log:      log,
+		recorder: recorder,
+		records:  make(chan Record, size),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Record queues the record, it never blocks. Records after Close are dropped.
+func (b *BufferedRecorder) Record(_ context.Context, record Record) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if b.closed {
+		return nil
+	}
+	select {
+	case b.records <- record:
+	default:
+		b.log.Warnf("Recording buffer full, dropping the record of request %s", record.Request.UID)
+	}
+	return nil
+}
+
+// Close writes all queued records and closes the underlying recorder
+func (b *BufferedRecorder) Close() error {
+	b.mutex.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.records)
+	}
+	b.mutex.Unlock()
+	<-b.done
+	return b.recorder.Close()
+}
+
+func (b *BufferedRecorder) run() {
+	defer close(b.done)
+	full := false
+	for record := range b.records {
+		err := b.recorder.Record(context.Background(), record)
+		switch {
+		case errors.Is(err, ErrRecordingFull):
+			// Logged once, the recording stays full
+			if !full {
+				b.log.Warnf("Recording stopped: %v", err)
+				full = true
+			}
+		case err != nil:
+			b.log.Errorf("Failed to record request %s: %v", record.Request.UID, err)
+		}
+	}
+}
+
+// CCRNPaths are the paths of CCRN resources the webhook reads besides the metadata, see Sanitize
+var CCRNPaths = []string{"spec.ccrn", "spec.urn"}
+
+// keptMetadata are the metadata fields kept by Sanitize, the webhook also reads the deletion timestamp and the
+// finalizers of CCRN resources, annotations are limited to the ones of the CCRN framework
+var keptMetadata = []string{"name", "namespace", "generateName", "labels", "annotations", "deletionTimestamp", "finalizers"}
+
+// Sanitize returns a copy of the request without personal data: the username and groups are replaced by their
+// pseudonyms under the key, which stay the same for the same user and key, the UID and extra attributes of the user
+// are dropped. Objects are reduced to an allowlist: apiVersion, kind, the name, namespace, generateName, labels,
+// deletion timestamp and finalizers of the metadata, the annotations of the CCRN framework (ccrn.cloud/id,
+// ccrn/...) and the given dotted paths like "spec.ccrn" (see CCRNPaths) or the paths of an object mapping.
+// Objects that are no JSON objects are dropped entirely.
+func Sanitize(request *admissionv1.AdmissionRequest, key []byte, paths ...string) *admissionv1.AdmissionRequest {
+	sanitized := request.DeepCopy()
+	if sanitized.UserInfo.Username != "" {
+		sanitized.UserInfo.Username = Pseudonym(key, sanitized.UserInfo.Username)
+	}
+	for i, group := range sanitized.UserInfo.Groups {
+		sanitized.UserInfo.Groups[i] = pseudonym("group-", key, group)
+	}
+	sanitized.UserInfo.UID = ""
+	sanitized.UserInfo.Extra = nil
+	sanitized.Object.Raw = sanitizeObject(sanitized.Object.Raw, paths)
+	sanitized.Object.Object = nil
+	sanitized.OldObject.Raw = sanitizeObject(sanitized.OldObject.Raw, paths)
+	sanitized.OldObject.Object = nil
+	return sanitized
+}
+
+// Pseudonym returns the pseudonym of a user in sanitized requests, an HMAC-SHA256 of the username with the key
+func Pseudonym(key []byte, username string) string {
+	return pseudonym("user-", key, username)
+}
+
+// pseudonym returns the prefixed HMAC-SHA256 of the value with the key
+func pseudonym(prefix string, key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return prefix + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+// sanitizeObject copies the allowlisted fields and paths of a JSON object
+func sanitizeObject(raw []byte, paths []string) []byte {
+	if len(raw) == 0 {
+		return nil
+	}
+	object := map[string]any{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil
+	}
+
+	kept := map[string]any{}
+	for _, field := range []string{"apiVersion", "kind"} {
+		if value, ok := object[field]; ok {
+			kept[field] = value
+		}
+	}
+	if metadata, ok := object["metadata"].(map[string]any); ok {
+		keptMeta := map[string]any{}
+		for _, field := range keptMetadata {
+			if value, ok := metadata[field]; ok {
+				keptMeta[field] = value
+			}
+		}
+		if annotations, ok := keptMeta["annotations"].(map[string]any); ok {
+			for key := range annotations {
+				if key != apis.IdentifierAnnotation && !strings.HasPrefix(key, "ccrn/") {
+					delete(annotations, key)
+				}
+			}
+			if len(annotations) == 0 {
+				delete(keptMeta, "annotations")
+			}
+		}
+		kept["metadata"] = keptMeta
+	}
+	for _, path := range paths {
+		copyPath(kept, object, strings.Split(path, "."))
+	}
+
+	sanitized, err := json.Marshal(kept)
+	if err != nil {
+		return nil
+	}
+	return sanitized
+}
+
+// copyPath copies the value at the path, whose elements may index lists like "ownerReferences[0]", from the
+// source to the destination. Other items of indexed lists are left null, so indices stay the same.
+func copyPath(dst, src map[string]any, path []string) {
+	name, index, hasIndex := strings.Cut(path[0], "[")
+	value, ok := src[name]
+	if !ok {
+		return
+	}
+	if hasIndex {
+		i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+		list, isList := value.([]any)
+		if err != nil || !isList || i < 0 || i >= len(list) {
+			return
+		}
+		keptList, _ := dst[name].([]any)
+		if len(keptList) <= i {
+			keptList = append(keptList, make([]any, i+1-len(keptList))...)
+		}
+		dst[name] = keptList
+		if len(path) == 1 {
+			keptList[i] = list[i]
+			return
+		}
+		item, isMap := list[i].(map[string]any)
+		if !isMap {
+			return
+		}
+		keptItem, _ := keptList[i].(map[string]any)
+		if keptItem == nil {
+			keptItem = map[string]any{}
+			keptList[i] = keptItem
+		}
+		copyPath(keptItem, item, path[1:])
+		return
+	}
+	if len(path) == 1 {
+		dst[name] = value
+		return
+	}
+	child, isMap := value.(map[string]any)
+	if !isMap {
+		return
+	}
+	keptChild, _ := dst[name].(map[string]any)
+	if keptChild == nil {
+		keptChild = map[string]any{}
+		dst[name] = keptChild
+	}
+	copyPath(keptChild, child, path[1:])
+}
+
+// Read reads the records of JSON lines as written by FileRecorder, empty lines are skipped
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		record := Record{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: invalid record: %w", line, err)
+		}
+		if record.Request == nil || record.Response == nil {
+			return nil, fmt.Errorf("line %d: record lacks its request or response", line)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	return records, nil
+}
+
+// Decision is the outcome of an admission request
+type Decision struct {
+	Allowed  bool            `json:"allowed"`
+	Message  string          `json:"message,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+	Patch    json.RawMessage `json:"patch,omitempty"`
+}
+
+// Result compares the recorded decision on a request with the decision of the replay
+type Result struct {
+	UID       string   `json:"uid"`
+	Operation string   `json:"operation"`
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Recorded  Decision `json:"recorded"`
+	Replayed  Decision `json:"replayed"`
+	Changed   bool     `json:"changed"` // The request is allowed or denied differently, or patched differently
+}
+
+// Replay sends the requests of the records to the paths of the handler, e.g. the one of webhook.WebhookServer,
+// and compares the responses with the recorded ones. Messages and warnings are reported but do not count as
+// changes since they carry details like the versions of the webhook.
+func Replay(handler http.Handler, records []Record) ([]Result, error) {
+	results := make([]Result, 0, len(records))
+	for _, record := range records {
+		replayed, err := send(handler, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay request %s: %w", record.Request.UID, err)
+		}
+		result := Result{
+			UID:       string(record.Request.UID),
+			Operation: string(record.Request.Operation),
+			Namespace: record.Request.Namespace,
+			Name:      record.Request.Name,
+			Recorded:  decision(record.Response),
+			Replayed:  decision(replayed),
+		}
+		result.Changed = result.Recorded.Allowed != result.Replayed.Allowed ||
+			!bytes.Equal(result.Recorded.Patch, result.Replayed.Patch)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// send posts the request of the record to its path of the handler and returns the response
+func send(handler http.Handler, record Record) (*admissionv1.AdmissionResponse, error) {
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request:  record.Request,
+	})
+	if err != nil {
+		return nil, err
+	}
+	request := httptest.NewRequest(http.MethodPost, record.Path, bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &review); err != nil || review.Response == nil {
+		return nil, fmt.Errorf("handler answered with status %d: %s", recorder.Code, strings.TrimSpace(recorder.Body.String()))
+	}
+	return review.Response, nil
+}
+
+// decision returns the decision of the response
+func decision(response *admissionv1.AdmissionResponse) Decision {
+	d := Decision{Allowed: response.Allowed, Warnings: response.Warnings, Patch: response.Patch}
+	if response.Result != nil {
+		d.Message = response.Result.Message
+	}
+	return d
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+)
+
+func TestReplay(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Replay Suite")
+}
+
+var key = []byte("pseudonym-key")
+
+func request(uid string) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		UID:       types.UID("uid-" + uid),
+		Namespace: "default",
+		Name:      uid,
+		Operation: admissionv1.Create,
+		UserInfo: authenticationv1.UserInfo{
+			Username: "alice",
+			UID:      "1234",
+			Extra:    map[string]authenticationv1.ExtraValue{"scopes": {"admin"}},
+		},
+		Object: runtime.RawExtension{Raw: []byte(`{
+			"metadata": {"name": "` + uid + `", "annotations": {"owner": "alice@example.com", "ccrn.cloud/id": "abc", "ccrn/skip": "true"},
+				"managedFields": [{"manager": "kubectl"}]},
+			"spec": {"ccrn": "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web"},
+			"status": {"valid": true}}`)},
+	}
+}
+
+// handlerAllowing answers every admission request with the decision and patch
+func handlerAllowing(allowed bool, patch []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		Expect(json.NewDecoder(r.Body).Decode(&review)).To(Succeed())
+		review.Response = &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: allowed, Patch: patch,
+			Result: &metav1.Status{Message: "replayed on " + r.URL.Path}}
+		Expect(json.NewEncoder(w).Encode(review)).To(Succeed())
+	})
+}
+
+var _ = Describe("Sanitize", func() {
+	It("pseudonymizes the user and strips the objects", func() {
+		// Arrange
+		original := request("web")
+		// Act
+		sanitized := replay.Sanitize(original, key, replay.CCRNPaths...)
+		// Assert
+		Expect(sanitized.UserInfo.Username).To(Equal(replay.Pseudonym(key, "alice")))
+		Expect(sanitized.UserInfo.Username).To(HavePrefix("user-"))
+		Expect(sanitized.UserInfo.UID).To(BeEmpty())
+		Expect(sanitized.UserInfo.Extra).To(BeEmpty())
+		object := map[string]any{}
+		Expect(json.Unmarshal(sanitized.Object.Raw, &object)).To(Succeed())
+		Expect(object).ToNot(HaveKey("status"))
+		Expect(object["spec"]).To(Equal(map[string]any{"ccrn": "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=web"}))
+		metadata := object["metadata"].(map[string]any)
+		Expect(metadata).ToNot(HaveKey("managedFields"))
+		Expect(metadata["annotations"]).To(Equal(map[string]any{"ccrn.cloud/id": "abc", "ccrn/skip": "true"}))
+		Expect(original.UserInfo.Username).To(Equal("alice"), "the original request must not be modified")
+	})
+
+	It("drops everything of a Pod but the allowlisted fields and paths", func() {
+		// Arrange
+		original := request("web")
+		original.UserInfo.Groups = []string{"system:authenticated", "shop-admins"}
+		original.Object.Raw = []byte(`{"apiVersion": "v1", "kind": "Pod",
+			"metadata": {"name": "web", "namespace": "shop", "labels": {"app": "shop"}, "uid": "1234",
+				"ownerReferences": [{"kind": "ReplicaSet", "name": "web-1"}, {"kind": "Node", "name": "node-1"}]},
+			"spec": {"nodeName": "node-1", "containers": [{"name": "web", "image": "registry.example.com/web:1",
+				"command": ["serve", "--token=hunter2"], "env": [{"name": "DB_PASSWORD", "value": "s3cret"}]}]}}`)
+		// Act
+		sanitized := replay.Sanitize(original, key, "spec.nodeName", "metadata.ownerReferences[1].name")
+		// Assert
+		Expect(string(sanitized.Object.Raw)).ToNot(ContainSubstring("s3cret"))
+		Expect(string(sanitized.Object.Raw)).ToNot(ContainSubstring("hunter2"))
+		Expect(string(sanitized.Object.Raw)).To(MatchJSON(`{"apiVersion": "v1", "kind": "Pod",
+			"metadata": {"name": "web", "namespace": "shop", "labels": {"app": "shop"}, "ownerReferences": [null, {"name": "node-1"}]},
+			"spec": {"nodeName": "node-1"}}`))
+		Expect(sanitized.UserInfo.Groups).To(HaveLen(2))
+		Expect(sanitized.UserInfo.Groups).ToNot(ContainElement("shop-admins"))
+		Expect(sanitized.UserInfo.Groups[1]).To(HavePrefix("group-"))
+	})
+
+	It("keeps pseudonyms stable per user and key", func() {
+		// Act & Assert
+		Expect(replay.Pseudonym(key, "alice")).To(Equal(replay.Pseudonym(key, "alice")))
+		Expect(replay.Pseudonym(key, "alice")).ToNot(Equal(replay.Pseudonym(key, "bob")))
+		Expect(replay.Pseudonym(key, "alice")).ToNot(Equal(replay.Pseudonym([]byte("other-key"), "alice")))
+	})
+
+	It("derives pseudonyms with HMAC-SHA256 instead of a plain hash", func() {
+		// Arrange
+		sum := sha256.Sum256([]byte("alice"))
+		// Act
+		pseudonym := replay.Pseudonym(key, "alice")
+		// Assert
+		Expect(pseudonym).To(HaveLen(len("user-") + 12))
+		Expect(pseudonym).ToNot(Equal("user-" + hex.EncodeToString(sum[:6])))
+	})
+})
+
+var _ = Describe("FileRecorder", func() {
+	It("appends records which are read back", func() {
+		// Arrange
+		path := filepath.Join(GinkgoT().TempDir(), "recording", "recording.jsonl")
+		recorder, err := replay.NewFileRecorder(path, 0)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		for _, name := range []string{"web", "db"} {
+			Expect(recorder.Record(context.Background(), replay.Record{
+				Path:     "/validate",
+				Request:  replay.Sanitize(request(name), key),
+				Response: &admissionv1.AdmissionResponse{Allowed: true},
+			})).To(Succeed())
+		}
+		Expect(recorder.Close()).To(Succeed())
+		// Assert
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		records, err := replay.Read(bytes.NewReader(content))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[1].Path).To(Equal("/validate"))
+		Expect(records[1].Request.Name).To(Equal("db"))
+		Expect(records[1].Response.Allowed).To(BeTrue())
+	})
+
+	It("stops recording at the maximum size", func() {
+		// Arrange
+		record := replay.Record{Path: "/validate", Request: replay.Sanitize(request("web"), key, replay.CCRNPaths...), Response: &admissionv1.AdmissionResponse{Allowed: true}}
+		line, err := json.Marshal(record)
+		Expect(err).ToNot(HaveOccurred())
+		path := filepath.Join(GinkgoT().TempDir(), "recording.jsonl")
+		recorder, err := replay.NewFileRecorder(path, int64(len(line))+10)
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		first := recorder.Record(context.Background(), record)
+		second := recorder.Record(context.Background(), record)
+		Expect(recorder.Close()).To(Succeed())
+		// Assert
+		Expect(first).To(Succeed())
+		Expect(second).To(MatchError(replay.ErrRecordingFull))
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(HaveLen(len(line) + 1))
+		records, err := replay.Read(bytes.NewReader(content))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+	})
+
+	It("rejects invalid records with their line", func() {
+		// Act
+		_, err := replay.Read(strings.NewReader("\n{\"path\": \"/validate\"}\n"))
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("line 2: record lacks its request or response")))
+	})
+})
+
+var _ = Describe("BufferedRecorder", func() {
+	It("writes the queued records in the background and flushes them on close", func() {
+		// Arrange
+		path := filepath.Join(GinkgoT().TempDir(), "recording.jsonl")
+		fileRecorder, err := replay.NewFileRecorder(path, 0)
+		Expect(err).ToNot(HaveOccurred())
+		recorder := replay.NewBufferedRecorder(nil, fileRecorder, 16)
+		// Act
+		for _, name := range []string{"web", "db", "cache"} {
+			Expect(recorder.Record(context.Background(), replay.Record{
+				Path:     "/validate",
+				Request:  replay.Sanitize(request(name), key),
+				Response: &admissionv1.AdmissionResponse{Allowed: true},
+			})).To(Succeed())
+		}
+		Expect(recorder.Close()).To(Succeed())
+		// Assert
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		records, err := replay.Read(bytes.NewReader(content))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+		Expect(records[2].Request.Name).To(Equal("cache"))
+	})
+})
+
+var _ = Describe("Replay", func() {
+	record := func(allowed bool, patch string) replay.Record {
+		response := &admissionv1.AdmissionResponse{Allowed: allowed, Result: &metav1.Status{Message: "recorded"}}
+		if patch != "" {
+			response.Patch = []byte(patch)
+		}
+		return replay.Record{Path: "/annotate", Request: request("web"), Response: response}
+	}
+
+	It("reports unchanged decisions despite changed messages", func() {
+		// Act
+		results, err := replay.Replay(handlerAllowing(true, nil), []replay.Record{record(true, "")})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Changed).To(BeFalse())
+		Expect(results[0].UID).To(Equal("uid-web"))
+		Expect(results[0].Recorded.Message).To(Equal("recorded"))
+		Expect(results[0].Replayed.Message).To(Equal("replayed on /annotate"))
+	})
+
+	DescribeTable("detects changed decisions",
+		func(recorded replay.Record, handler http.Handler) {
+			// Act
+			results, err := replay.Replay(handler, []replay.Record{recorded})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results[0].Changed).To(BeTrue())
+		},
+		Entry("when denied now", record(true, ""), handlerAllowing(false, nil)),
+		Entry("when allowed now", record(false, ""), handlerAllowing(true, nil)),
+		Entry("when patched differently", record(true, `[{"op":"add","path":"/spec/urn","value":"a"}]`),
+			handlerAllowing(true, []byte(`[{"op":"add","path":"/spec/urn","value":"b"}]`))),
+	)
+
+	It("fails when the handler does not answer with an AdmissionReview", func() {
+		// Arrange
+		handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		})
+		// Act
+		_, err := replay.Replay(handler, []replay.Record{record(true, "")})
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("failed to replay request uid-web: handler answered with status 500: boom")))
+	})
+})
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resolver provides webhook.FieldResolver implementations verifying field values against
+// external inventories:
+//   - https://regions.example.com/clusters/{value} asks an HTTP service, 2xx means the value exists and 404 it doesn't
+//   - file:///etc/ccrn/clusters.txt reads the known values from a file, one per line
+//
+// Results of remote resolvers are cached, so admission requests do not depend on every lookup. The cache
+// holds up to DefaultMaxCacheEntries values, the least recently used values are evicted first.
+package resolver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/lru"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// ValuePlaceholder is replaced by the value in the URLs of HTTP resolvers, path escaped in the path and query
+// escaped in the query
+const ValuePlaceholder = "{value}"
+
+// DefaultMaxCacheEntries is the default number of values a CachingResolver keeps
+const DefaultMaxCacheEntries = 10000
+
+// NewFromURL creates a resolver from a URL, see the package documentation. HTTP resolvers time out after
+// the timeout and are cached for the TTL with the options, a TTL of 0 disables caching.
+func NewFromURL(rawURL string, timeout, ttl time.Duration, opts ...CachingOption) (webhook.FieldResolver, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver URL %s: %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return LoadStaticResolver(u.Path)
+	case "http", "https":
+		if !strings.Contains(rawURL, ValuePlaceholder) {
+			return nil, fmt.Errorf("resolver URL %s must contain %s", rawURL, ValuePlaceholder)
+		}
+		var resolver webhook.FieldResolver = NewHTTPResolver(rawURL, timeout)
+		if ttl > 0 {
+			resolver = NewCachingResolver(resolver, ttl, opts...)
+		}
+		return resolver, nil
+	default:
+		return nil, fmt.Errorf("unsupported resolver scheme %q", u.Scheme)
+	}
+}
+
+// HTTPResolver resolves values with GET requests, a 2xx response means the value exists and 404 that it doesn't
+type HTTPResolver struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPResolver creates a resolver requesting the URL with ValuePlaceholder replaced by the value
+func NewHTTPResolver(url string, timeout time.Duration) *HTTPResolver {
+	return &HTTPResolver{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Resolve implements webhook.FieldResolver
+func (h *HTTPResolver) Resolve(ctx context.Context, value string) (bool, error) {
+	// Values in the query are query escaped, so they cannot add parameters with & or =
+	path, query, hasQuery := strings.Cut(h.url, "?")
+	requestURL := strings.ReplaceAll(path, ValuePlaceholder, url.PathEscape(value))
+	if hasQuery {
+		requestURL += "?" + strings.ReplaceAll(query, ValuePlaceholder, url.QueryEscape(value))
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create resolver request: %w", err)
+	}
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return false, nil
+	case response.StatusCode >= http.StatusMultipleChoices:
+		return false, fmt.Errorf("resolver %s responded with %s", requestURL, response.Status)
+	}
+	return true, nil
+}
+
+// StaticResolver resolves values against a fixed set of known values
+type StaticResolver struct {
+	values map[string]bool
+}
+
+// NewStaticResolver creates a resolver knowing the values
+func NewStaticResolver(values ...string) *StaticResolver {
+	resolver := &StaticResolver{values: make(map[string]bool, len(values))}
+	for _, value := range values {
+		resolver.values[value] = true
+	}
+	return resolver
+}
+
+// LoadStaticResolver creates a resolver knowing the values of a file, one per line.
+// Empty lines and lines starting with # are skipped.
+func LoadStaticResolver(file string) (*StaticResolver, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resolver values: %w", err)
+	}
+	defer f.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			values = append(values, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read resolver values %s: %w", file, err)
+	}
+	return NewStaticResolver(values...), nil
+}
+
+// Resolve implements webhook.FieldResolver
+func (s *StaticResolver) Resolve(_ context.Context, value string) (bool, error) {
+	return s.values[value], nil
+}
+
+// CachingResolver caches the results of a resolver for a TTL, errors are not cached. Expired results are
+// replaced when their value is requested again, values that are not requested anymore are evicted by the size
+// bound of the cache.
+type CachingResolver struct {
+	resolver   webhook.FieldResolver
+	ttl        time.Duration
+	maxEntries int
+	results    *lru.Cache[string, cachedResult]
+}
+
+type cachedResult struct {
+	exists  bool
+	expires time.Time
+}
+
+// CachingOption configures a CachingResolver
+type CachingOption func(*CachingResolver)
+
+// WithMaxEntries sets the number of values the resolver keeps, 0 does not bound the cache
+func WithMaxEntries(maxEntries int) CachingOption {
+	return func(c *CachingResolver) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// NewCachingResolver creates a resolver caching the results of the resolver for the TTL, it keeps up to
+// DefaultMaxCacheEntries values unless configured otherwise
+func NewCachingResolver(resolver webhook.FieldResolver, ttl time.Duration, opts ...CachingOption) *CachingResolver {
+	c := &CachingResolver{resolver: resolver, ttl: ttl, maxEntries: DefaultMaxCacheEntries}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.results = lru.New[string, cachedResult]("resolver", c.maxEntries)
+	return c
+}
+
+// Resolve implements webhook.FieldResolver
+func (c *CachingResolver) Resolve(ctx context.Context, value string) (bool, error) {
+	now := time.Now()
+	result, cached := c.results.Get(value)
+	if cached && now.Before(result.expires) {
+		return result.exists, nil
+	}
+
+	exists, err := c.resolver.Resolve(ctx, value)
+	if err != nil {
+		return false, err
+	}
+	c.results.Add(value, cachedResult{exists: exists, expires: now.Add(c.ttl)})
+	return exists, nil
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/resolver"
+)
+
+func TestResolver(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resolver Suite")
+}
+
+// countingResolver knows a single value and counts its calls
+type countingResolver struct {
+	value string
+	err   error
+	calls int
+}
+
+func (c *countingResolver) Resolve(_ context.Context, value string) (bool, error) {
+	c.calls++
+	return value == c.value, c.err
+}
+
+var _ = Describe("Resolvers", func() {
+	ctx := context.Background()
+
+	Context("HTTP", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.EscapedPath() {
+				case "/clusters/eu-de-1":
+				case "/clusters/broken":
+					w.WriteHeader(http.StatusInternalServerError)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("resolves values by the status of the response", func() {
+			// Arrange
+			clusters := resolver.NewHTTPResolver(server.URL+"/clusters/{value}", time.Second)
+			// Act
+			known, errKnown := clusters.Resolve(ctx, "eu-de-1")
+			unknown, errUnknown := clusters.Resolve(ctx, "eu-de-9")
+			_, errBroken := clusters.Resolve(ctx, "broken")
+			// Assert
+			Expect(errKnown).ToNot(HaveOccurred())
+			Expect(known).To(BeTrue())
+			Expect(errUnknown).ToNot(HaveOccurred())
+			Expect(unknown).To(BeFalse())
+			Expect(errBroken).To(MatchError(ContainSubstring("500 Internal Server Error")))
+		})
+
+		It("escapes values in the path", func() {
+			// Arrange
+			clusters := resolver.NewHTTPResolver(server.URL+"/clusters/{value}", time.Second)
+			// Act
+			exists, err := clusters.Resolve(ctx, "eu-de-1/../../admin")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("escapes values in the query", func() {
+			// Arrange
+			var query url.Values
+			inventory := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+			}))
+			DeferCleanup(inventory.Close)
+			clusters := resolver.NewHTTPResolver(inventory.URL+"/clusters?name={value}&active=true", time.Second)
+			// Act
+			exists, err := clusters.Resolve(ctx, "eu-de-1&x=y+z")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(query).To(Equal(url.Values{"name": {"eu-de-1&x=y+z"}, "active": {"true"}}))
+		})
+
+		It("is created from URLs with a placeholder", func() {
+			// Act
+			clusters, err := resolver.NewFromURL(server.URL+"/clusters/{value}", time.Second, time.Minute)
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusters.Resolve(ctx, "eu-de-1")).To(BeTrue())
+			_, err = resolver.NewFromURL(server.URL+"/clusters", time.Second, time.Minute)
+			Expect(err).To(MatchError(ContainSubstring("must contain {value}")))
+		})
+	})
+
+	It("loads static values from files", func() {
+		// Arrange
+		file := filepath.Join(GinkgoT().TempDir(), "clusters.txt")
+		Expect(os.WriteFile(file, []byte("# regions\neu-de-1\n\n  eu-de-2  \n"), 0o600)).To(Succeed())
+		// Act
+		clusters, err := resolver.NewFromURL("file://"+file, time.Second, time.Minute)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusters.Resolve(ctx, "eu-de-2")).To(BeTrue())
+		Expect(clusters.Resolve(ctx, "# regions")).To(BeFalse())
+	})
+
+	It("rejects unsupported schemes", func() {
+		// Act
+		_, err := resolver.NewFromURL("ftp://inventory/{value}", time.Second, time.Minute)
+		// Assert
+		Expect(err).To(MatchError(`unsupported resolver scheme "ftp"`))
+	})
+
+	Context("caching", func() {
+		It("caches results until the TTL expires", func() {
+			// Arrange
+			inner := &countingResolver{value: "eu-de-1"}
+			cached := resolver.NewCachingResolver(inner, 20*time.Millisecond)
+			// Act
+			for range 3 {
+				Expect(cached.Resolve(ctx, "eu-de-1")).To(BeTrue())
+				Expect(cached.Resolve(ctx, "eu-de-9")).To(BeFalse())
+			}
+			// Assert
+			Expect(inner.calls).To(Equal(2))
+			Eventually(func() int {
+				_, _ = cached.Resolve(ctx, "eu-de-1")
+				return inner.calls
+			}).Should(BeNumerically(">", 2))
+		})
+
+		It("evicts the least recently used values beyond the maximum entries", func() {
+			// Arrange
+			inner := &countingResolver{value: "eu-de-1"}
+			cached := resolver.NewCachingResolver(inner, time.Minute, resolver.WithMaxEntries(2))
+			// Act
+			for _, value := range []string{"eu-de-1", "eu-de-2", "eu-de-1", "eu-de-3", "eu-de-1", "eu-de-2"} {
+				_, err := cached.Resolve(ctx, value)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			// Assert
+			Expect(inner.calls).To(Equal(4))
+		})
+
+		It("does not cache errors", func() {
+			// Arrange
+			inner := &countingResolver{err: errors.New("unavailable")}
+			cached := resolver.NewCachingResolver(inner, time.Minute)
+			// Act
+			_, err1 := cached.Resolve(ctx, "eu-de-1")
+			_, err2 := cached.Resolve(ctx, "eu-de-1")
+			// Assert
+			Expect(err1).To(MatchError("unavailable"))
+			Expect(err2).To(MatchError("unavailable"))
+			Expect(inner.calls).To(Equal(2))
+		})
+	})
+})
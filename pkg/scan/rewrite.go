@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rewrite replaces the values of identifiers in the content they were extracted from, leaving the rest of the
+// file including comments untouched. replace returns the new value of an identifier, or false to keep it.
+// Values keep their quoting style unless the new value requires quotes. Values that do not fit on their line,
+// like block scalars and multi-line strings, are kept and returned as skipped.
+func Rewrite(content []byte, identifiers []Identifier, replace func(Identifier) (string, bool)) ([]byte, []Identifier) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+
+	// Rewrite from the end of each line, so the columns of the other identifiers on the line stay valid
+	sorted := append([]Identifier{}, identifiers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line < sorted[j].Line
+		}
+		return sorted[i].Column > sorted[j].Column
+	})
+
+	var skipped []Identifier
+	for _, identifier := range sorted {
+		value, ok := replace(identifier)
+		if !ok || value == identifier.Value {
+			continue
+		}
+		if identifier.Line < 1 || identifier.Line > len(lines) {
+			skipped = append(skipped, identifier)
+			continue
+		}
+		line := string(lines[identifier.Line-1])
+		start := byteOffset(line, identifier.Column-1)
+		end, quote, ok := scalarEnd(line, start, identifier.Value)
+		if !ok {
+			skipped = append(skipped, identifier)
+			continue
+		}
+		lines[identifier.Line-1] = []byte(line[:start] + quoteScalar(value, quote) + line[end:])
+	}
+	return bytes.Join(lines, nil), skipped
+}
+
+// byteOffset returns the byte offset of the 0-based character column in the line
+func byteOffset(line string, column int) int {
+	for offset := range line {
+		if column == 0 {
+			return offset
+		}
+		column--
+	}
+	return len(line)
+}
+
+// scalarEnd returns the end offset and the quote character of the scalar starting at the offset,
+// ok is false if the text of the scalar on the line does not decode to the value
+func scalarEnd(line string, start int, value string) (int, byte, bool) {
+	if start >= len(line) {
+		return 0, 0, false
+	}
+	switch quote := line[start]; quote {
+	case '"':
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				var decoded string
+				if err := yaml.Unmarshal([]byte(line[start:i+1]), &decoded); err != nil || decoded != value {
+					return 0, 0, false
+				}
+				return i + 1, quote, true
+			}
+		}
+	case '\'':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] != '\'' {
+				continue
+			}
+			if i+1 < len(line) && line[i+1] == '\'' {
+				i++
+				continue
+			}
+			if strings.ReplaceAll(line[start+1:i], "''", "'") != value {
+				return 0, 0, false
+			}
+			return i + 1, quote, true
+		}
+	case '|', '>':
+		return 0, 0, false
+	default:
+		end := len(strings.TrimRight(line, "\r\n"))
+		if comment := strings.Index(line[start:end], " #"); comment >= 0 {
+			end = start + comment
+		}
+		end = start + len(strings.TrimRight(line[start:end], " \t"))
+		if line[start:end] != value {
+			return 0, 0, false
+		}
+		return end, 0, true
+	}
+	return 0, 0, false
+}
+
+// quoteScalar returns the value as YAML scalar with the quote character, plain if quote is 0 and the value
+// reads back unchanged as plain scalar, otherwise double-quoted
+func quoteScalar(value string, quote byte) string {
+	if quote == 0 {
+		var decoded string
+		if err := yaml.Unmarshal([]byte("value: "+value), &struct {
+			Value *string `yaml:"value"`
+		}{&decoded}); err == nil && decoded == value && !strings.ContainsAny(value, "\n\t") {
+			return value
+		}
+		quote = '"'
+	}
+	if quote == '\'' {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
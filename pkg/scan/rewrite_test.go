@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package scan_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+)
+
+// upper replaces the values of all identifiers by their upper case form
+func upper(identifier scan.Identifier) (string, bool) {
+	return strings.ToUpper(identifier.Value), true
+}
+
+var _ = Describe("Rewrite", func() {
+	It("keeps the quoting style, comments and the rest of the file", func() {
+		// Arrange
+		content := `# manifests
+apiVersion: k8s.ccrn.example.com/v1
+kind: pod
+metadata:
+  annotations:
+    ccrn.cloud/id: 'ccrn=it''s'   # single quoted
+spec:
+  ccrn: ccrn=plain # plain
+---
+kind: Deployment
+metadata:
+  annotations:
+    ccrn.cloud/id: "ccrn=\"double\""
+`
+		identifiers, _ := scan.Extract("manifests.yaml", []byte(content), "ccrn.example.com")
+		// Act
+		rewritten, skipped := scan.Rewrite([]byte(content), identifiers, upper)
+		// Assert
+		Expect(skipped).To(BeEmpty())
+		Expect(string(rewritten)).To(Equal(`# manifests
+apiVersion: k8s.ccrn.example.com/v1
+kind: pod
+metadata:
+  annotations:
+    ccrn.cloud/id: 'CCRN=IT''S'   # single quoted
+spec:
+  ccrn: CCRN=PLAIN # plain
+---
+kind: Deployment
+metadata:
+  annotations:
+    ccrn.cloud/id: "CCRN=\"DOUBLE\""
+`))
+	})
+
+	It("quotes plain values that would not read back unchanged", func() {
+		// Arrange
+		content := "apiVersion: k8s.ccrn.example.com/v1\nspec:\n  ccrn: ccrn=a\n"
+		identifiers, _ := scan.Extract("manifests.yaml", []byte(content), "ccrn.example.com")
+		// Act
+		rewritten, _ := scan.Rewrite([]byte(content), identifiers, func(scan.Identifier) (string, bool) {
+			return "ccrn=a, note=b: c #d", true
+		})
+		// Assert
+		Expect(string(rewritten)).To(Equal("apiVersion: k8s.ccrn.example.com/v1\nspec:\n  ccrn: \"ccrn=a, note=b: c #d\"\n"))
+	})
+
+	It("skips values spanning several lines", func() {
+		// Arrange
+		content := "apiVersion: k8s.ccrn.example.com/v1\nspec:\n  ccrn: >-\n    ccrn=a,\n    name=b\n"
+		identifiers, _ := scan.Extract("manifests.yaml", []byte(content), "ccrn.example.com")
+		// Act
+		rewritten, skipped := scan.Rewrite([]byte(content), identifiers, upper)
+		// Assert
+		Expect(skipped).To(Equal(identifiers))
+		Expect(string(rewritten)).To(Equal(content))
+	})
+
+	It("keeps values the replace function declines", func() {
+		// Arrange
+		content := "apiVersion: k8s.ccrn.example.com/v1\nspec:\n  ccrn: ccrn=a\n"
+		identifiers, _ := scan.Extract("manifests.yaml", []byte(content), "ccrn.example.com")
+		// Act
+		rewritten, skipped := scan.Rewrite([]byte(content), identifiers, func(scan.Identifier) (string, bool) {
+			return "", false
+		})
+		// Assert
+		Expect(skipped).To(BeEmpty())
+		Expect(string(rewritten)).To(Equal(content))
+	})
+})
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scan finds CCRNs and URNs in Kubernetes manifests and validates them concurrently.
+// Identifiers are read from spec.ccrn and spec.urn of CCRN resources and from the
+// apis.IdentifierAnnotation of any object.
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Identifier is a CCRN or URN found in a manifest
+type Identifier struct {
+	Value  string `json:"value"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`             // 1-based line of the value in the file
+	Column int    `json:"column"`           // 1-based column of the value in the line
+	Object string `json:"object,omitempty"` // Kind and name of the object carrying the identifier, e.g. "CCRN/web"
+	Path   string `json:"path"`             // Where the identifier is found in the object, e.g. "spec.ccrn"
+}
+
+// Result is the validation result of an identifier
+type Result struct {
+	Identifier
+	*apis.ValidationResult
+}
+
+// Validator validates identifiers, it is implemented by validation.CCRNValidator and must be safe for concurrent use
+type Validator interface {
+	ValidateCCRNContext(ctx context.Context, input string) (*apis.ValidationResult, error)
+}
+
+// Extract returns the identifiers of the YAML documents in the content. Resources of groups ending with one of
+// the comma separated CCRN groups contribute spec.ccrn and spec.urn, all objects the apis.IdentifierAnnotation. Documents that are
+// no valid YAML, like Helm templates, are skipped and counted.
+func Extract(file string, content []byte, ccrnGroup string) ([]Identifier, int) {
+	var identifiers []Identifier
+	skipped := 0
+	for _, document := range splitDocuments(content) {
+		var node yaml.Node
+		if err := yaml.Unmarshal(document.content, &node); err != nil {
+			skipped++
+			continue
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		object := node.Content[0]
+
+		found := func(path string, value *yaml.Node) {
+			identifiers = append(identifiers, Identifier{
+				Value:  value.Value,
+				File:   file,
+				Line:   document.line + value.Line - 1,
+				Column: value.Column,
+				Object: objectName(object),
+				Path:   path,
+			})
+		}
+		if group, _, _ := strings.Cut(scalar(object, "apiVersion"), "/"); hasGroupSuffix(group, ccrnGroup) {
+			for _, field := range []string{"ccrn", "urn"} {
+				if value := lookup(object, "spec", field); value != nil && value.Kind == yaml.ScalarNode && value.Value != "" {
+					found("spec."+field, value)
+				}
+			}
+		}
+		if value := lookup(object, "metadata", "annotations", apis.IdentifierAnnotation); value != nil && value.Kind == yaml.ScalarNode && value.Value != "" {
+			found("metadata.annotations."+apis.IdentifierAnnotation, value)
+		}
+	}
+	return identifiers, skipped
+}
+
+// Validate validates the identifiers with the given number of workers, the results are in the order of the identifiers
+func Validate(ctx context.Context, validator Validator, identifiers []Identifier, workers int) []Result {
+	results := make([]Result, len(identifiers))
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(identifiers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, _ := validator.ValidateCCRNContext(ctx, identifiers[i].Value)
+				results[i] = Result{Identifier: identifiers[i], ValidationResult: result}
+			}
+		}()
+	}
+	for i := range identifiers {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// document is a YAML document of a multi-document file
+type document struct {
+	content []byte
+	line    int // 1-based line of the first line of the document in the file
+}
+
+// splitDocuments splits multi-document YAML at "---" separators, keeping track of the line numbers
+func splitDocuments(content []byte) []document {
+	var documents []document
+	current := document{line: 1}
+	var buffer bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "---" {
+			current.content = bytes.Clone(buffer.Bytes())
+			documents = append(documents, current)
+			buffer.Reset()
+			current = document{line: line + 1}
+			continue
+		}
+		buffer.WriteString(text)
+		buffer.WriteByte('\n')
+	}
+	current.content = buffer.Bytes()
+	return append(documents, current)
+}
+
+// lookup returns the node at the path of mapping keys, nil if it does not exist
+func lookup(node *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// scalar returns the value of the scalar at the path, empty if it does not exist
+func scalar(node *yaml.Node, path ...string) string {
+	if value := lookup(node, path...); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// objectName returns "<kind>/<name>" of the object, or what is known of it
+func objectName(object *yaml.Node) string {
+	kind, name := scalar(object, "kind"), scalar(object, "metadata", "name")
+	switch {
+	case kind != "" && name != "":
+		return fmt.Sprintf("%s/%s", kind, name)
+	case kind != "":
+		return kind
+	default:
+		return name
+	}
+}
+
+// hasGroupSuffix reports whether the API group ends with one of the comma separated CCRN groups
+func hasGroupSuffix(group, ccrnGroups string) bool {
+	for _, ccrnGroup := range apis.ParseGroups(ccrnGroups) {
+		if strings.HasSuffix(strings.ToLower(group), ccrnGroup) {
+			return true
+		}
+	}
+	return false
+}
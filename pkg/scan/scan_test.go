@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package scan_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/scan"
+)
+
+func TestScan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scan Suite")
+}
+
+const manifests = `apiVersion: k8s.ccrn.example.com/v1
+kind: pod
+metadata:
+  name: web
+spec:
+  ccrn: "ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=web"
+---
+{{- if .Values.web.enabled }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  annotations:
+    ccrn.cloud/id: urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/web
+{{- end }}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    ccrn.cloud/id: urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/api
+spec:
+  urn: "not read, Deployments are no CCRN resources"
+`
+
+// recordingValidator accepts inputs starting with "ccrn=" after a delay and records the maximum concurrency
+type recordingValidator struct {
+	running, maxRunning atomic.Int32
+}
+
+func (v *recordingValidator) ValidateCCRNContext(_ context.Context, input string) (*apis.ValidationResult, error) {
+	running := v.running.Add(1)
+	defer v.running.Add(-1)
+	for {
+		maxRunning := v.maxRunning.Load()
+		if running <= maxRunning || v.maxRunning.CompareAndSwap(maxRunning, running) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return &apis.ValidationResult{Valid: len(input) > 5 && input[:5] == "ccrn="}, nil
+}
+
+var _ = Describe("Extract", func() {
+	It("finds identifiers of CCRN resources and annotations with their location", func() {
+		// Act
+		identifiers, skipped := scan.Extract("manifests.yaml", []byte(manifests), "ccrn.example.com")
+		// Assert
+		Expect(skipped).To(Equal(1))
+		Expect(identifiers).To(Equal([]scan.Identifier{
+			{
+				Value:  "ccrn=pod.k8s.ccrn.example.com/v1, cluster=eu-de-1, name=web",
+				File:   "manifests.yaml",
+				Line:   6,
+				Column: 9,
+				Object: "pod/web",
+				Path:   "spec.ccrn",
+			},
+			{
+				Value:  "urn:ccrn:pod.k8s.ccrn.example.com/v1/eu-de-1/api",
+				File:   "manifests.yaml",
+				Line:   22,
+				Column: 20,
+				Object: "Deployment/api",
+				Path:   "metadata.annotations." + apis.IdentifierAnnotation,
+			},
+		}))
+	})
+
+	It("ignores spec fields of other groups", func() {
+		// Act
+		identifiers, skipped := scan.Extract("manifests.yaml", []byte(manifests), "other.example.com")
+		// Assert
+		Expect(skipped).To(Equal(1))
+		Expect(identifiers).To(HaveLen(1))
+		Expect(identifiers[0].Path).To(Equal("metadata.annotations." + apis.IdentifierAnnotation))
+	})
+
+	It("finds spec fields of any of several groups", func() {
+		// Act
+		identifiers, _ := scan.Extract("manifests.yaml", []byte(manifests), "other.example.com, ccrn.example.com")
+		// Assert
+		Expect(identifiers).To(HaveLen(2))
+		Expect(identifiers[0].Path).To(Equal("spec.ccrn"))
+	})
+
+	It("ignores empty documents and documents without identifiers", func() {
+		// Act
+		identifiers, skipped := scan.Extract("empty.yaml", []byte("---\n# comment\n---\nkind: ConfigMap\n"), "ccrn.example.com")
+		// Assert
+		Expect(skipped).To(BeZero())
+		Expect(identifiers).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Validate", func() {
+	It("validates concurrently and keeps the order of the identifiers", func() {
+		// Arrange
+		identifiers := make([]scan.Identifier, 50)
+		for i := range identifiers {
+			identifiers[i] = scan.Identifier{Value: fmt.Sprintf("urn:ccrn:%d", i), Line: i}
+			if i%2 == 0 {
+				identifiers[i].Value = fmt.Sprintf("ccrn=%d", i)
+			}
+		}
+		validator := &recordingValidator{}
+		// Act
+		results := scan.Validate(context.Background(), validator, identifiers, 4)
+		// Assert
+		Expect(results).To(HaveLen(50))
+		for i, result := range results {
+			Expect(result.Identifier).To(Equal(identifiers[i]))
+			Expect(result.Valid).To(Equal(i%2 == 0))
+		}
+		Expect(validator.maxRunning.Load()).To(BeNumerically(">", 1))
+		Expect(validator.maxRunning.Load()).To(BeNumerically("<=", 4))
+	})
+
+	It("returns no results without identifiers", func() {
+		// Act
+		results := scan.Validate(context.Background(), &recordingValidator{}, nil, 0)
+		// Assert
+		Expect(results).To(BeEmpty())
+	})
+})
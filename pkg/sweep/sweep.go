@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sweep audits the CCRN resources existing in a cluster, e.g. for periodic compliance sweeps.
+// Resources admitted before a schema change may no longer be valid, may identify the same resource as
+// another one or may reference versions deprecated since. Every CCRN resource is re-validated against
+// the current CRDs of the backend.
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Finding kinds
+const (
+	FindingInvalid    = "invalid"    // The identifier does not validate against the current schema
+	FindingDuplicate  = "duplicate"  // Another resource has an identifier of the same resource
+	FindingDeprecated = "deprecated" // The identifier references a deprecated version
+)
+
+// Object is a CCRN resource of the cluster
+type Object struct {
+	Resource   string `json:"resource"` // Resource and group, e.g. "pods.k8s-registry.ccrn.example.com"
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Identifier string `json:"identifier,omitempty"` // spec.ccrn, or spec.urn if no CCRN is set
+}
+
+// String returns the resource and the namespaced name of the object, e.g. "pods.k8s.ccrn.example.com/default/web"
+func (o Object) String() string {
+	if o.Namespace == "" {
+		return o.Resource + "/" + o.Name
+	}
+	return o.Resource + "/" + o.Namespace + "/" + o.Name
+}
+
+// Finding is a problem of a CCRN resource
+type Finding struct {
+	Object
+	Kind    string `json:"kind"` // FindingInvalid, FindingDuplicate or FindingDeprecated
+	Message string `json:"message"`
+}
+
+// String returns the finding as "<object>: <kind>: <message>"
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Object, f.Kind, f.Message)
+}
+
+// Report is the result of an audit
+type Report struct {
+	Objects    int       `json:"objects"`    // Number of audited CCRN resources
+	Invalid    int       `json:"invalid"`    // Number of invalid resources
+	Duplicates int       `json:"duplicates"` // Number of resources sharing their identified resource with another one
+	Deprecated int       `json:"deprecated"` // Number of resources referencing deprecated versions
+	Findings   []Finding `json:"findings"`   // Findings sorted by object
+}
+
+// Failed reports whether any resource is invalid or a duplicate, deprecated versions are only reported
+func (r *Report) Failed() bool {
+	return r.Invalid > 0 || r.Duplicates > 0
+}
+
+// Validator validates identifiers, it is implemented by validation.CCRNValidator
+type Validator interface {
+	ValidateCCRNContext(ctx context.Context, input string) (*apis.ValidationResult, error)
+}
+
+// Auditor lists the CCRN resources of the resource types of a backend and re-validates them
+type Auditor struct {
+	backend   apis.ValidationBackend
+	validator Validator
+	client    dynamic.Interface
+}
+
+// NewAuditor creates an auditor listing the CCRN resources of the CRDs of the backend with the client,
+// the backend must implement apis.CRDLister
+func NewAuditor(backend apis.ValidationBackend, validator Validator, client dynamic.Interface) *Auditor {
+	return &Auditor{backend: backend, validator: validator, client: client}
+}
+
+// NewAuditorForConfig creates an auditor reading the CCRN resources from the cluster of the config
+func NewAuditorForConfig(backend apis.ValidationBackend, validator Validator, config *rest.Config) (*Auditor, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return NewAuditor(backend, validator, client), nil
+}
+
+// Audit lists the CCRN resources in the namespace, in all namespaces if it is empty, and returns the report.
+// Every resource type is listed once in its highest version. Resource types not installed in the cluster
+// are skipped, cluster-scoped ones are only listed in all namespaces.
+func (a *Auditor) Audit(ctx context.Context, namespace string) (*Report, error) {
+	lister, ok := a.backend.(apis.CRDLister)
+	if !ok {
+		return nil, fmt.Errorf("backend %T cannot list its resource types", a.backend)
+	}
+
+	var objects []Object
+	for _, crd := range apis.HighestVersions(lister.ListCRDs()) {
+		listed, err := a.list(ctx, crd, namespace)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, listed...)
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].String() < objects[j].String()
+	})
+
+	report := &Report{Objects: len(objects), Findings: []Finding{}}
+	byFingerprint := map[string][]Object{}
+	var fingerprints []string
+	for _, object := range objects {
+		if object.Identifier == "" {
+			report.add(object, FindingInvalid, "resource must have either spec.ccrn or spec.urn defined")
+			continue
+		}
+		result, err := a.validator.ValidateCCRNContext(ctx, object.Identifier)
+		if err != nil || !result.Valid || result.ParsedCCRN == nil {
+			messages := apis.ErrorMessages(err)
+			if result != nil {
+				messages = append(messages, result.Errors...)
+			}
+			report.add(object, FindingInvalid, strings.Join(messages, "; "))
+			continue
+		}
+
+		if crd, err := a.backend.GetCRD(result.ParsedCCRN.CCRNKey()); err == nil && crd.Deprecated {
+			report.add(object, FindingDeprecated, crd.DeprecationMessage())
+		}
+		fingerprint := result.ParsedCCRN.Fingerprint()
+		if len(byFingerprint[fingerprint]) == 0 {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		byFingerprint[fingerprint] = append(byFingerprint[fingerprint], object)
+	}
+
+	for _, fingerprint := range fingerprints {
+		duplicates := byFingerprint[fingerprint]
+		if len(duplicates) < 2 {
+			continue
+		}
+		for i, object := range duplicates {
+			others := make([]string, 0, len(duplicates)-1)
+			for j, other := range duplicates {
+				if i != j {
+					others = append(others, other.String())
+				}
+			}
+			report.add(object, FindingDuplicate, "identifies the same resource as "+strings.Join(others, ", "))
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Object.String() < report.Findings[j].Object.String()
+	})
+	return report, nil
+}
+
+// add adds a finding to the report and counts it
+func (r *Report) add(object Object, kind, message string) {
+	r.Findings = append(r.Findings, Finding{Object: object, Kind: kind, Message: message})
+	switch kind {
+	case FindingInvalid:
+		r.Invalid++
+	case FindingDuplicate:
+		r.Duplicates++
+	case FindingDeprecated:
+		r.Deprecated++
+	}
+}
+
+// list returns the CCRN resources of the CRD in the namespace, in all namespaces if it is empty
+func (a *Auditor) list(ctx context.Context, crd *apis.CRDInfo, namespace string) ([]Object, error) {
+	gvr := schema.GroupVersionResource{Group: crd.Group, Version: crd.Version, Resource: crd.Plural}
+	list, err := a.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", gvr.GroupResource(), err)
+	}
+
+	objects := make([]Object, 0, len(list.Items))
+	for _, item := range list.Items {
+		object := Object{Resource: gvr.GroupResource().String(), Namespace: item.GetNamespace(), Name: item.GetName()}
+		for _, field := range []string{"ccrn", "urn"} {
+			if value, _, _ := unstructured.NestedString(item.Object, "spec", field); value != "" {
+				object.Identifier = value
+				break
+			}
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sweep_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/sweep"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+func TestSweep(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sweep Suite")
+}
+
+// ccrnObject returns a CCRN resource with the spec
+func ccrnObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) runtime.Object {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}}
+	if spec != nil {
+		object.Object["spec"] = spec
+	}
+	return object
+}
+
+var _ = Describe("Auditor", func() {
+	const pods = "pods.k8s-registry.tr.ccrn.example.com"
+	var auditor *sweep.Auditor
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "deprecated_crd.yaml"))).To(Succeed())
+
+		podVersion := "k8s-registry.tr.ccrn.example.com/v1"
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				{Group: "k8s-registry.tr.ccrn.example.com", Version: "v1", Resource: "pods"}: "podList",
+				{Group: "tr.ccrn.example.com", Version: "v1", Resource: "widgets"}:           "widgetList",
+			},
+			ccrnObject(podVersion, "pod", "default", "web", map[string]interface{}{
+				"ccrn": "ccrn=pod/v1, cluster=eu-de-1, namespace=default, name=web"}),
+			ccrnObject(podVersion, "pod", "default", "web-copy", map[string]interface{}{
+				"urn": "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/web"}),
+			ccrnObject(podVersion, "pod", "shop", "broken", map[string]interface{}{
+				"ccrn": "ccrn=pod/v1, cluster=us-west-1, namespace=shop, name=broken"}),
+			ccrnObject(podVersion, "pod", "shop", "empty", nil),
+			ccrnObject("tr.ccrn.example.com/v1", "widget", "default", "old", map[string]interface{}{
+				"ccrn": "ccrn=widget.tr.ccrn.example.com/v1alpha1, name=old"}),
+		)
+		auditor = sweep.NewAuditor(backend, validation.NewCCRNValidator(backend), client)
+	})
+
+	It("reports invalid, duplicate and deprecated identifiers in all namespaces", func() {
+		// Act
+		report, err := auditor.Audit(context.Background(), "")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Objects).To(Equal(5))
+		Expect(report.Invalid).To(Equal(2))
+		Expect(report.Duplicates).To(Equal(2))
+		Expect(report.Deprecated).To(Equal(1))
+		Expect(report.Failed()).To(BeTrue())
+
+		findings := make([]string, 0, len(report.Findings))
+		for _, finding := range report.Findings {
+			findings = append(findings, finding.Object.String()+" "+finding.Kind)
+		}
+		Expect(findings).To(Equal([]string{
+			pods + "/default/web duplicate",
+			pods + "/default/web-copy duplicate",
+			pods + "/shop/broken invalid",
+			pods + "/shop/empty invalid",
+			"widgets.tr.ccrn.example.com/default/old deprecated",
+		}))
+		Expect(report.Findings[0].Message).To(Equal("identifies the same resource as " + pods + "/default/web-copy"))
+		Expect(report.Findings[3].Message).To(Equal("resource must have either spec.ccrn or spec.urn defined"))
+		Expect(report.Findings[4].Message).To(Equal("widget.tr.ccrn.example.com/v1alpha1 is deprecated, use v1"))
+	})
+
+	It("audits a single namespace", func() {
+		// Act
+		report, err := auditor.Audit(context.Background(), "default")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Objects).To(Equal(3))
+		Expect(report.Invalid).To(BeZero())
+	})
+
+	It("only reports deprecated versions", func() {
+		// Act
+		report, err := auditor.Audit(context.Background(), "default")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Findings).To(ContainElement(HaveField("Kind", sweep.FindingDeprecated)))
+		Expect((&sweep.Report{Deprecated: 1}).Failed()).To(BeFalse())
+	})
+})
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation/backendtest"
+)
+
+// crdDirectory writes the CRDs to a file of a temporary directory and returns the directory along with a
+// function replacing the CRDs of the file
+func crdDirectory(t *testing.T, crds []byte) (string, func([]byte)) {
+	dir := t.TempDir()
+	update := func(crds []byte) {
+		if err := os.WriteFile(filepath.Join(dir, "crds.yaml"), crds, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	update(crds)
+	return dir, update
+}
+
+func TestFilesystemBackendContract(t *testing.T) {
+	backendtest.Run(t, func(t *testing.T, crds []byte) (apis.ValidationBackend, func([]byte)) {
+		dir, update := crdDirectory(t, crds)
+		backend := validation.NewOfflineBackend(logrus.New(), backendtest.Group)
+		if err := backend.LoadCRDs(filepath.Join(dir, "crds.yaml")); err != nil {
+			t.Fatal(err)
+		}
+		return backend, update
+	})
+}
+
+func TestMultiGroupBackendContract(t *testing.T) {
+	backendtest.Run(t, func(t *testing.T, crds []byte) (apis.ValidationBackend, func([]byte)) {
+		dir, update := crdDirectory(t, crds)
+		// Serve the minimal CRD under a second group, the contract CRDs must be routed to their group
+		content, err := os.ReadFile(filepath.Join("testdata", "minimal_crd.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		legacy := strings.ReplaceAll(string(content), "ccrn.example.com", "ccrn.legacy.example.com")
+		if err := os.WriteFile(filepath.Join(dir, "legacy.yaml"), []byte(legacy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		backend, err := validation.NewBackendFromURL(logrus.New(), "file://"+dir, backendtest.Group+", ccrn.legacy.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return backend, update
+	})
+}
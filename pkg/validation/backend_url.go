@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+)
+
+// MaxBundleBytes limits the size of CRD bundles downloaded by NewBackendFromURL
+const MaxBundleBytes = 32 << 20
+
+// NewBackendFromURL creates a validation backend from a URL:
+//
+//   - file:///path/to/crds loads a CRD file, glob pattern or directory (file://crds for relative paths)
+//   - k8s:// uses the CRDs of the cluster in $KUBECONFIG or the in-cluster config, k8s://<context>
+//     selects a kubeconfig context
+//   - https://host/crds.yaml (or http://) downloads a multi-document YAML bundle of CRDs
+//
+// Files and downloads ending in .tar.gz or .tgz are read as schema bundles, see pkg/bundle. The digest query
+// parameter pins the backend to the digest of its CRD set, e.g. file:///crds.tgz?digest=sha256:..., creating
+// the backend fails with bundle.ErrDigestMismatch if the CRDs differ.
+//
+// The key query parameter names a PEM encoded public key, e.g. file:///crds.tgz?key=/etc/ccrn/cosign.pub,
+// bundles are then only loaded with a valid signature of the key, as written by cosign sign-blob. The
+// signature is read from the bundle location with bundle.SignatureSuffix unless the signature query parameter
+// names another path or URL. Creating the backend fails with bundle.ErrInvalidSignature for unsigned or
+// tampered bundles, and for sources other than bundles.
+//
+// Signatures are opt-in, see RequireSignature to refuse downloads and bundles that are neither signed nor pinned.
+//
+// A comma separated list of CCRN groups creates a MultiGroupBackend with a backend per group.
+func NewBackendFromURL(log *logrus.Logger, rawURL, ccrnGroups string, opts ...BackendURLOption) (apis.ValidationBackend, error) {
+	if log == nil {
+		log = logrus.New()
+	}
+	options := &backendURLOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend URL %s: %w", rawURL, err)
+	}
+	query := u.Query()
+	pinned := query.Get("digest")
+	signer := &bundleSigner{signature: query.Get("signature")}
+	if keyFile := query.Get("key"); keyFile != "" {
+		if signer.key, err = bundle.LoadPublicKey(keyFile); err != nil {
+			return nil, err
+		}
+	}
+	remote := u.Scheme == "http" || u.Scheme == "https"
+	if options.requireSignature && signer.key == nil && pinned == "" && (remote || bundle.IsBundle(u.Host+u.Path+u.Opaque)) {
+		return nil, fmt.Errorf("%w: backend %s has neither a key nor a digest parameter, but signatures are required", bundle.ErrInvalidSignature, rawURL)
+	}
+	for _, param := range []string{"digest", "key", "signature"} {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+
+	backend, err := newBackendFromURL(log, u, rawURL, apis.ParseGroups(ccrnGroups), signer)
+	if err != nil || pinned == "" {
+		return backend, err
+	}
+	source, ok := backend.(apis.CRDSource)
+	if !ok {
+		return nil, fmt.Errorf("backend %s cannot be pinned to a digest", rawURL)
+	}
+	if err := bundle.Verify(source.CustomResourceDefinitions(), pinned); err != nil {
+		return nil, fmt.Errorf("backend %s: %w", rawURL, err)
+	}
+	return backend, nil
+}
+
+// BackendURLOption configures NewBackendFromURL
+type BackendURLOption func(*backendURLOptions)
+
+type backendURLOptions struct {
+	requireSignature bool
+}
+
+// RequireSignature refuses HTTP backends and schema bundles without key or digest parameter, so only bundles
+// verified with a trusted key or pinned to a known digest are loaded. Local CRD files, directories and
+// clusters are not affected.
+func RequireSignature() BackendURLOption {
+	return func(o *backendURLOptions) {
+		o.requireSignature = true
+	}
+}
+
+// newBackendFromURL creates the backend of the URL without digest and signature parameters, see
+// NewBackendFromURL
+func newBackendFromURL(log *logrus.Logger, u *url.URL, rawURL string, groups []string, signer *bundleSigner) (apis.ValidationBackend, error) {
+	switch u.Scheme {
+	case "file":
+		path := u.Host + u.Path
+		if u.Opaque != "" {
+			path = u.Opaque
+		}
+		if path == "" {
+			return nil, fmt.Errorf("file backend URL %s must contain a path", rawURL)
+		}
+		if bundle.IsBundle(path) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read bundle %s: %w", path, err)
+			}
+			err = signer.verify(path, path+bundle.SignatureSuffix, content, func(signature string) ([]byte, error) {
+				return os.ReadFile(signature)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if content, err = bundleYAML(path, content); err != nil {
+				return nil, err
+			}
+			return newBackendFromBytes(log, groups, path, content)
+		}
+		if signer.key != nil {
+			return nil, fmt.Errorf("%w: only schema bundles can be verified, %s is none", bundle.ErrInvalidSignature, path)
+		}
+		return NewMultiGroupBackend(groups, func(ccrnGroup string) (apis.ValidationBackend, error) {
+			backend := NewOfflineBackend(log, ccrnGroup)
+			var err error
+			if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+				err = backend.LoadCRDsFromDirectory(path)
+			} else {
+				err = backend.LoadCRDs(path)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return backend, nil
+		})
+	case "k8s":
+		if signer.key != nil {
+			return nil, fmt.Errorf("%w: only schema bundles can be verified, not the CRDs of a cluster", bundle.ErrInvalidSignature)
+		}
+		restConfig, err := config.GetConfigWithContext(u.Host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Kubernetes config: %w", err)
+		}
+		return NewMultiGroupBackend(groups, func(ccrnGroup string) (apis.ValidationBackend, error) {
+			return NewKubernetesBackend(restConfig, log, ccrnGroup)
+		})
+	case "http", "https":
+		if signer.key != nil && !bundle.IsBundle(u.Path) {
+			return nil, fmt.Errorf("%w: only schema bundles can be verified, %s is none", bundle.ErrInvalidSignature, u.String())
+		}
+		content, err := fetchBundle(u.String(), 30*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		if bundle.IsBundle(u.Path) {
+			// The suffix goes to the path, URLs may keep other query parameters
+			signatureURL := *u
+			signatureURL.Path += bundle.SignatureSuffix
+			signatureURL.RawPath = ""
+			err = signer.verify(u.String(), signatureURL.String(), content, func(signature string) ([]byte, error) {
+				return fetchBundle(signature, 30*time.Second)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if content, err = bundleYAML(u.String(), content); err != nil {
+				return nil, err
+			}
+		}
+		return newBackendFromBytes(log, groups, u.String(), content)
+	default:
+		return nil, fmt.Errorf("unsupported backend scheme %q", u.Scheme)
+	}
+}
+
+// bundleSigner is the trusted signer of bundles selected by the key and signature parameters of a backend URL
+type bundleSigner struct {
+	key       crypto.PublicKey // Public key of the signer, nil if signatures are not verified
+	signature string           // Path or URL of the signature, defaults to the bundle location with bundle.SignatureSuffix
+}
+
+// verify verifies the signature of the bundle read from the location, if a key is configured. The signature
+// is read with readSignature from the signature parameter or the default location.
+func (s *bundleSigner) verify(location, defaultSignature string, content []byte, readSignature func(signature string) ([]byte, error)) error {
+	if s.key == nil {
+		return nil
+	}
+	signatureLocation := s.signature
+	if signatureLocation == "" {
+		signatureLocation = defaultSignature
+	}
+	signature, err := readSignature(signatureLocation)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read the signature of bundle %s: %v", bundle.ErrInvalidSignature, location, err)
+	}
+	if err := bundle.VerifySignature(s.key, content, signature); err != nil {
+		return fmt.Errorf("%s: %w", location, err)
+	}
+	return nil
+}
+
+// newBackendFromBytes creates offline backends of the groups loading the multi-document YAML content
+func newBackendFromBytes(log *logrus.Logger, groups []string, source string, content []byte) (apis.ValidationBackend, error) {
+	return NewMultiGroupBackend(groups, func(ccrnGroup string) (apis.ValidationBackend, error) {
+		backend := NewOfflineBackend(log, ccrnGroup)
+		if err := backend.LoadCRDsFromBytes(source, content); err != nil {
+			return nil, err
+		}
+		return backend, nil
+	})
+}
+
+// bundleYAML reads the bundle and returns its CRDs as multi-document YAML
+func bundleYAML(location string, content []byte) ([]byte, error) {
+	b, err := bundle.Read(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+	return b.YAML()
+}
+
+// fetchBundle downloads a CRD bundle of at most MaxBundleBytes
+func fetchBundle(bundleURL string, timeout time.Duration) ([]byte, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(bundleURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download CRDs from %s: %w", bundleURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download CRDs from %s: %s", bundleURL, resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, MaxBundleBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download CRDs from %s: %w", bundleURL, err)
+	}
+	if len(content) > MaxBundleBytes {
+		return nil, fmt.Errorf("CRD bundle %s exceeds %d bytes", bundleURL, MaxBundleBytes)
+	}
+	return content, nil
+}
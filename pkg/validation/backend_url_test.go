@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+var _ = Describe("NewBackendFromURL", func() {
+	It("loads a CRD file", func() {
+		// Arrange
+		path, err := filepath.Abs(filepath.Join("testdata", "minimal_crd.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		backend, err := validation.NewBackendFromURL(logrus.New(), "file://"+path, "ccrn.example.com")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+	})
+
+	It("loads a directory given as relative path", func() {
+		// Act
+		backend, err := validation.NewBackendFromURL(logrus.New(), "file://testdata", "ccrn.example.com")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+	})
+
+	It("fails when no CRD file matches", func() {
+		// Act
+		_, err := validation.NewBackendFromURL(logrus.New(), "file:///non/existent/crds.yaml", "ccrn.example.com")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no files found")))
+	})
+
+	It("downloads a CRD bundle via HTTP", func() {
+		// Arrange
+		content, err := os.ReadFile(filepath.Join("testdata", "minimal_crd.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		}))
+		defer server.Close()
+		// Act
+		backend, err := validation.NewBackendFromURL(logrus.New(), server.URL+"/crds.yaml", "ccrn.example.com")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+	})
+
+	It("fails when the bundle cannot be downloaded", func() {
+		// Arrange
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		// Act
+		_, err := validation.NewBackendFromURL(logrus.New(), server.URL+"/crds.yaml", "ccrn.example.com")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("404 Not Found")))
+	})
+
+	It("fails when the bundle contains no CRDs", func() {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("---\n"))
+		}))
+		defer server.Close()
+		// Act
+		_, err := validation.NewBackendFromURL(logrus.New(), server.URL, "ccrn.example.com")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no CRDs found")))
+	})
+
+	It("rejects unsupported schemes", func() {
+		// Act
+		_, err := validation.NewBackendFromURL(logrus.New(), "ftp://example.com/crds.yaml", "ccrn.example.com")
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring(`unsupported backend scheme "ftp"`)))
+	})
+
+	Context("schema bundles", func() {
+		var (
+			path   string
+			digest string
+		)
+
+		BeforeEach(func() {
+			source, err := validation.NewBackendFromURL(logrus.New(), "file://testdata/minimal_crd.yaml", "ccrn.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			b, err := bundle.New("1.0.0", "", source.(apis.CRDSource).CustomResourceDefinitions())
+			Expect(err).ToNot(HaveOccurred())
+			path = filepath.Join(GinkgoT().TempDir(), "crds-1.0.0.tgz")
+			f, err := os.Create(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.Write(f)).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+			digest = b.Manifest.Digest
+		})
+
+		It("loads a bundle pinned to its digest", func() {
+			// Act
+			backend, err := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?digest="+digest, "ccrn.example.com")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+		})
+
+		It("downloads a bundle via HTTP", func() {
+			// Arrange
+			content, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.RawQuery).To(BeEmpty(), "the digest must not be sent")
+				_, _ = w.Write(content)
+			}))
+			defer server.Close()
+			// Act
+			backend, err := validation.NewBackendFromURL(logrus.New(), server.URL+"/crds-1.0.0.tgz?digest="+digest, "ccrn.example.com")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+		})
+
+		It("pins plain CRD files to the digest of a bundle of them", func() {
+			// Act
+			_, err := validation.NewBackendFromURL(logrus.New(), "file://testdata/minimal_crd.yaml?digest="+digest, "ccrn.example.com")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("fails when the CRDs do not have the pinned digest", func() {
+			// Act
+			_, err := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?digest=sha256:0", "ccrn.example.com")
+			// Assert
+			Expect(err).To(MatchError(bundle.ErrDigestMismatch))
+		})
+
+		Context("signed with cosign", func() {
+			var (
+				keyFile string
+				sign    func(content []byte) []byte
+			)
+
+			BeforeEach(func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Expect(err).ToNot(HaveOccurred())
+				der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+				Expect(err).ToNot(HaveOccurred())
+				keyFile = filepath.Join(GinkgoT().TempDir(), "cosign.pub")
+				Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)).To(Succeed())
+				sign = func(content []byte) []byte {
+					digest := sha256.Sum256(content)
+					signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+					Expect(err).ToNot(HaveOccurred())
+					return []byte(base64.StdEncoding.EncodeToString(signature))
+				}
+			})
+
+			// signBundle writes the signature of the bundle to path
+			signBundle := func(path string) {
+				content, err := os.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(path+bundle.SignatureSuffix, sign(content), 0o600)).To(Succeed())
+			}
+
+			It("loads a bundle signed with the key", func() {
+				// Arrange
+				signBundle(path)
+				// Act
+				backend, err := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?key="+keyFile+"&digest="+digest, "ccrn.example.com")
+				// Assert
+				Expect(err).ToNot(HaveOccurred())
+				Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+			})
+
+			It("reads the signature from the signature parameter", func() {
+				// Arrange
+				signBundle(path)
+				signature := filepath.Join(GinkgoT().TempDir(), "bundle.sig")
+				Expect(os.Rename(path+bundle.SignatureSuffix, signature)).To(Succeed())
+				// Act
+				_, err := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?key="+keyFile+"&signature="+signature, "ccrn.example.com")
+				// Assert
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("refuses unsigned bundles", func() {
+				// Act
+				_, err := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?key="+keyFile, "ccrn.example.com")
+				// Assert
+				Expect(err).To(MatchError(bundle.ErrInvalidSignature))
+			})
+
+			It("refuses tampered bundles", func() {
+				// Arrange
+				signBundle(path)
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = f.Write([]byte{0})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(f.Close()).To(Succeed())
+				// Act
+				_, err = validation.NewBackendFromURL(logrus.New(), "file://"+path+"?key="+keyFile, "ccrn.example.com")
+				// Assert
+				Expect(err).To(MatchError(bundle.ErrInvalidSignature))
+			})
+
+			It("downloads the signature of a bundle via HTTP", func() {
+				// Arrange
+				content, err := os.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.URL.RawQuery).To(BeEmpty(), "the key must not be sent")
+					if r.URL.Path == "/crds-1.0.0.tgz"+bundle.SignatureSuffix {
+						_, _ = w.Write(sign(content))
+						return
+					}
+					_, _ = w.Write(content)
+				}))
+				defer server.Close()
+				// Act
+				backend, err := validation.NewBackendFromURL(logrus.New(), server.URL+"/crds-1.0.0.tgz?key="+keyFile, "ccrn.example.com")
+				// Assert
+				Expect(err).ToNot(HaveOccurred())
+				Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+			})
+
+			It("appends the signature suffix to the path of URLs with other query parameters", func() {
+				// Arrange
+				content, err := os.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.URL.Query().Get("token")).To(Equal("abc"))
+					if r.URL.Path == "/crds-1.0.0.tgz"+bundle.SignatureSuffix {
+						_, _ = w.Write(sign(content))
+						return
+					}
+					_, _ = w.Write(content)
+				}))
+				defer server.Close()
+				// Act
+				_, err = validation.NewBackendFromURL(logrus.New(), server.URL+"/crds-1.0.0.tgz?token=abc&key="+keyFile, "ccrn.example.com")
+				// Assert
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("requires a key or digest for bundles and downloads if signatures are required", func() {
+				// Arrange
+				signBundle(path)
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte("unused"))
+				}))
+				defer server.Close()
+				// Act
+				_, errUnsigned := validation.NewBackendFromURL(logrus.New(), "file://"+path, "ccrn.example.com", validation.RequireSignature())
+				_, errRemote := validation.NewBackendFromURL(logrus.New(), server.URL+"/crds.yaml", "ccrn.example.com", validation.RequireSignature())
+				_, errSigned := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?key="+keyFile, "ccrn.example.com", validation.RequireSignature())
+				_, errPinned := validation.NewBackendFromURL(logrus.New(), "file://"+path+"?digest="+digest, "ccrn.example.com", validation.RequireSignature())
+				_, errLocal := validation.NewBackendFromURL(logrus.New(), "file://testdata/minimal_crd.yaml", "ccrn.example.com", validation.RequireSignature())
+				// Assert
+				Expect(errUnsigned).To(MatchError(bundle.ErrInvalidSignature))
+				Expect(errRemote).To(MatchError(bundle.ErrInvalidSignature))
+				Expect(errSigned).ToNot(HaveOccurred())
+				Expect(errPinned).ToNot(HaveOccurred())
+				Expect(errLocal).ToNot(HaveOccurred())
+			})
+
+			It("rejects keys for backends that are no bundles", func() {
+				// Act
+				_, err := validation.NewBackendFromURL(logrus.New(), "file://testdata/minimal_crd.yaml?key="+keyFile, "ccrn.example.com")
+				// Assert
+				Expect(err).To(MatchError(bundle.ErrInvalidSignature))
+			})
+		})
+	})
+})
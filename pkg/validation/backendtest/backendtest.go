@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package backendtest provides contract tests for implementations of apis.ValidationBackend. A backend
+// proves that it satisfies the semantics of the interface, e.g. lookups, errors and refreshes, with a call
+// to Run from a test of its package:
+//
+//	func TestContract(t *testing.T) {
+//		backendtest.Run(t, func(t *testing.T, crds []byte) (apis.ValidationBackend, func([]byte)) {
+//			...
+//		})
+//	}
+//
+// The CRDs of the contract are in the CCRN group Group, backends may serve further groups. The optional
+// interfaces of backends, e.g. apis.AliasResolver or apis.CRDLister, are checked if implemented.
+package backendtest
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	schemabundle "github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+)
+
+// Group is the CCRN group of the CRDs of the contract, backends under test must serve it
+const Group = "backendtest.ccrn.example.com"
+
+// Namespace is the namespace resources are validated in
+const Namespace = "default"
+
+// CRDs is the initial multi-document YAML bundle of CRDs passed to the Factory. It declares the resource
+// type widget with the versions v1 with a URN template, v1alpha1 without one and v0 which is not served.
+//
+//go:embed crds.yaml
+var CRDs []byte
+
+// Gadgets is a bundle with the additional resource type gadget, Run adds and removes it to check refreshes
+//
+//go:embed gadgets.yaml
+var Gadgets []byte
+
+// Resource types of the contract CRDs
+const (
+	widgetType = "widget." + Group
+	gadgetType = "gadget." + Group
+)
+
+// Factory creates the backend under test serving the CRDs of a YAML bundle, along with a function replacing
+// the CRDs of its source, e.g. a directory or a cluster. The backend must pick up replaced CRDs on Refresh
+// at the latest. Factories fail the test if the backend or its source cannot be set up.
+type Factory func(t *testing.T, crds []byte) (backend apis.ValidationBackend, update func(crds []byte))
+
+// Run runs the contract tests against backends created by the factory, each in its own subtest
+func Run(t *testing.T, factory Factory) {
+	t.Run("SupportedTypes", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		g.Expect(backend.IsResourceTypeSupported(widgetType + "/v1")).To(gomega.BeTrue())
+		g.Expect(backend.IsResourceTypeSupported(widgetType + "/v1alpha1")).To(gomega.BeTrue())
+		g.Expect(backend.IsResourceTypeSupported(widgetType+"/v0")).To(gomega.BeFalse(), "v0 is not served")
+		g.Expect(backend.IsResourceTypeSupported(widgetType + "/v2")).To(gomega.BeFalse())
+		g.Expect(backend.IsResourceTypeSupported(gadgetType + "/v1")).To(gomega.BeFalse())
+		g.Expect(backend.IsResourceTypeSupported("pod.k8s-registry.tr.ccrn.example.com/v1")).To(gomega.BeFalse())
+	})
+
+	t.Run("GetCRD", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		crdInfo, err := backend.GetCRD(widgetType + "/v1")
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(crdInfo.Name).To(gomega.Equal("widgets." + Group))
+		g.Expect(crdInfo.Kind).To(gomega.Equal("widget"))
+		g.Expect(crdInfo.Plural).To(gomega.Equal("widgets"))
+		g.Expect(crdInfo.Group).To(gomega.Equal(Group))
+		g.Expect(crdInfo.Version).To(gomega.Equal("v1"))
+		g.Expect(crdInfo.CCRNKey()).To(gomega.Equal(widgetType + "/v1"))
+		g.Expect(crdInfo.Schema).ToNot(gomega.BeNil())
+
+		for _, key := range []string{widgetType + "/v0", widgetType + "/v2", gadgetType + "/v1"} {
+			_, err := backend.GetCRD(key)
+			g.Expect(errors.Is(err, apis.ErrCRDNotFound)).To(gomega.BeTrue(), "GetCRD(%s): %v", key, err)
+		}
+	})
+
+	t.Run("GetURNTemplate", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		g.Expect(backend.GetURNTemplate(widgetType, "v1")).To(gomega.Equal("urn:ccrn:<ccrn>/<region>/<name>"))
+		g.Expect(backend.GetURNTemplate("widgets."+Group, "v1")).To(gomega.Equal("urn:ccrn:<ccrn>/<region>/<name>"),
+			"the template is looked up by the name of the CRD as well")
+
+		_, err := backend.GetURNTemplate(widgetType, "v1alpha1")
+		g.Expect(errors.Is(err, apis.ErrCRDNotFound)).To(gomega.BeTrue(), "v1alpha1 has no template: %v", err)
+		_, err = backend.GetURNTemplate(gadgetType, "v1")
+		g.Expect(errors.Is(err, apis.ErrCRDNotFound)).To(gomega.BeTrue(), "gadget is unknown: %v", err)
+	})
+
+	t.Run("ValidateResource", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		g.Expect(backend.ValidateResource(Namespace, mustParse(g, "ccrn="+widgetType+"/v1, region=eu-de-1, name=web"))).To(gomega.Succeed())
+		g.Expect(backend.ValidateResource(Namespace, mustParse(g, "ccrn="+widgetType+"/v1alpha1, name=web"))).To(gomega.Succeed())
+
+		err := backend.ValidateResource(Namespace, mustParse(g, "ccrn="+widgetType+"/v1, region=mars-1, name=web"))
+		g.Expect(errors.Is(err, apis.ErrSchemaViolation)).To(gomega.BeTrue(), "region is not in the enum: %v", err)
+		err = backend.ValidateResource(Namespace, mustParse(g, "ccrn="+widgetType+"/v1, region=eu-de-1, name=Web_1"))
+		g.Expect(errors.Is(err, apis.ErrSchemaViolation)).To(gomega.BeTrue(), "name does not match the pattern: %v", err)
+		err = backend.ValidateResource(Namespace, mustParse(g, "ccrn="+gadgetType+"/v1, name=web"))
+		g.Expect(errors.Is(err, apis.ErrUnsupportedType)).To(gomega.BeTrue(), "gadget is unknown: %v", err)
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, update := factory(t, CRDs)
+
+		// Refreshing an unchanged source keeps the CRDs
+		g.Expect(backend.Refresh()).To(gomega.Succeed())
+		g.Expect(backend.IsResourceTypeSupported(widgetType + "/v1")).To(gomega.BeTrue())
+
+		// Added CRDs are picked up
+		update(bundle(CRDs, Gadgets))
+		g.Expect(backend.Refresh()).To(gomega.Succeed())
+		g.Expect(backend.IsResourceTypeSupported(gadgetType + "/v1")).To(gomega.BeTrue())
+		g.Expect(backend.GetURNTemplate(gadgetType, "v1")).To(gomega.Equal("urn:ccrn:<ccrn>/<name>"))
+		g.Expect(backend.ValidateResource(Namespace, mustParse(g, "ccrn="+gadgetType+"/v1, name=web"))).To(gomega.Succeed())
+
+		// Removed CRDs are dropped
+		update(CRDs)
+		g.Expect(backend.Refresh()).To(gomega.Succeed())
+		g.Expect(backend.IsResourceTypeSupported(gadgetType + "/v1")).To(gomega.BeFalse())
+		g.Expect(backend.IsResourceTypeSupported(widgetType + "/v1")).To(gomega.BeTrue())
+	})
+
+	t.Run("ConcurrentRefresh", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		// Lookups during refreshes of an unchanged source never miss the CRDs
+		var wg sync.WaitGroup
+		misses := make(chan string, 100)
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 50 {
+					if _, err := backend.GetCRD(widgetType + "/v1"); err != nil {
+						misses <- err.Error()
+						return
+					}
+				}
+			}()
+		}
+		for range 3 {
+			g.Expect(backend.Refresh()).To(gomega.Succeed())
+		}
+		wg.Wait()
+		close(misses)
+		g.Expect(misses).To(gomega.BeEmpty())
+	})
+
+	t.Run("OptionalInterfaces", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+		backend, _ := factory(t, CRDs)
+
+		if resolver, ok := backend.(apis.AliasResolver); ok {
+			for _, alias := range []string{"wd", "widget", "WIDGET"} {
+				resourceType, exists := resolver.ResolveAlias(alias)
+				g.Expect(exists).To(gomega.BeTrue(), "alias %s, aliases are case-insensitive", alias)
+				g.Expect(resourceType).To(gomega.Equal(widgetType))
+			}
+			_, exists := resolver.ResolveAlias("gadget")
+			g.Expect(exists).To(gomega.BeFalse())
+		}
+		if resolver, ok := backend.(apis.VersionResolver); ok {
+			version, exists := resolver.ResolveLatestVersion(widgetType)
+			g.Expect(exists).To(gomega.BeTrue())
+			g.Expect(version).To(gomega.Equal("v1"), "v1 is the highest served version")
+			_, exists = resolver.ResolveLatestVersion(gadgetType)
+			g.Expect(exists).To(gomega.BeFalse())
+		}
+		if lister, ok := backend.(apis.CRDLister); ok {
+			var keys []string
+			for _, crdInfo := range lister.ListCRDs() {
+				if crdInfo.Group == Group {
+					keys = append(keys, crdInfo.CCRNKey())
+				}
+			}
+			g.Expect(keys).To(gomega.Equal([]string{widgetType + "/v1", widgetType + "/v1alpha1"}))
+		}
+		if exporter, ok := backend.(apis.SchemaExporter); ok {
+			bundle, err := exporter.Export(apis.SchemaFormatJSONSchema)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(bundle).ToNot(gomega.BeEmpty())
+		}
+		if source, ok := backend.(apis.CRDSource); ok {
+			// The CRDs have the digest of the manifests, whether read from files or served by the API server
+			var crds []*apiextensionsv1.CustomResourceDefinition
+			for _, crd := range source.CustomResourceDefinitions() {
+				if crd.Spec.Group == Group {
+					crds = append(crds, crd)
+				}
+			}
+			g.Expect(crds).To(gomega.HaveLen(1))
+			digest, err := schemabundle.Digest(crds)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(digest).To(gomega.Equal(manifestDigest(g, CRDs)))
+		}
+	})
+}
+
+// manifestDigest returns the schema bundle digest of the CRDs of the multi-document YAML manifest
+func manifestDigest(g *gomega.WithT, manifest []byte) string {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := decoder.Decode(crd); errors.Is(err, io.EOF) {
+			break
+		} else {
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+		}
+		if crd.Name != "" {
+			crds = append(crds, crd)
+		}
+	}
+	digest, err := schemabundle.Digest(crds)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	return digest
+}
+
+// mustParse parses a CCRN of the contract
+func mustParse(g *gomega.WithT, ccrn string) *apis.ParsedResource {
+	parsed, err := apis.ParseCCRN(ccrn)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	return parsed
+}
+
+// bundle joins YAML bundles into one
+func bundle(bundles ...[]byte) []byte {
+	var joined []byte
+	for _, b := range bundles {
+		joined = append(joined, "\n---\n"...)
+		joined = append(joined, b...)
+	}
+	return joined
+}
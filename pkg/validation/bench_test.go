@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// benchBackend returns a backend with the test pod CRD and a logger discarding its output
+func benchBackend(b *testing.B) *validation.FilesystemBackend {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	backend := validation.NewOfflineBackend(log, "tr.ccrn.example.com")
+	if err := backend.LoadCRDs(filepath.Join("testdata", "testpod_crd.yaml")); err != nil {
+		b.Fatal(err)
+	}
+	return backend
+}
+
+func BenchmarkValidateCCRN(b *testing.B) {
+	validator := validation.NewCCRNValidator(benchBackend(b))
+	for _, input := range []struct{ name, identifier string }{
+		{"ccrn", "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"},
+		{"urn", "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"},
+	} {
+		b.Run(input.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if result, err := validator.ValidateCCRN(input.identifier); err != nil || !result.Valid {
+					b.Fatalf("validation failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidateResource(b *testing.B) {
+	backend := benchBackend(b)
+	parsed, err := apis.ParseCCRN("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := backend.ValidateResource("default", parsed); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
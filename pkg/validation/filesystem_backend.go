@@ -7,22 +7,32 @@ package validation
 
 import (
     "bufio"
+    "context"
     "errors"
     "fmt"
     "github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+    "github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
     "os"
     "path/filepath"
+    "slices"
+    "sort"
     "strings"
     "sync"
+    "sync/atomic"
+    "time"
 
     "github.com/sirupsen/logrus"
     "sigs.k8s.io/yaml"
 
     "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
     apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+    structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+    "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
     "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
     "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+    "k8s.io/apimachinery/pkg/runtime/schema"
     "k8s.io/apimachinery/pkg/util/validation/field"
+    celconfig "k8s.io/apiserver/pkg/apis/cel"
 )
 
 const (
@@ -38,6 +48,57 @@ const (
 
     // URNTemplateAnnotationFormat defines the format for URN template annotations
     URNTemplateAnnotationFormat = "ccrn/%s.urn-template"
+
+    // BaseURNTemplateAnnotation declares the URN template of all versions without a template of their own,
+    // see URNTemplateAnnotationFormat
+    BaseURNTemplateAnnotation = "ccrn/urn-template"
+
+    // URNTemplateSuffixAnnotationFormat defines the format for annotations extending the base URN template
+    // for a version, e.g. ccrn/v2.urn-template-suffix: "/<revision>". The suffix is appended to the base
+    // template, versions with a template of their own ignore it.
+    URNTemplateSuffixAnnotationFormat = "ccrn/%s.urn-template-suffix"
+
+    // LegacyURNTemplatesAnnotationFormat defines the format for annotations listing former URN templates of a
+    // version, one per line in priority order. URNs are parsed with the first matching template, rendering
+    // uses the URN template of the version, so URN layouts can be migrated gradually.
+    LegacyURNTemplatesAnnotationFormat = "ccrn/%s.legacy-urn-templates"
+
+    // LegacyURNMappingAnnotationFormat defines the format for annotations describing how the fields of the legacy
+    // URN templates of a version were renamed in its URN template, as comma separated <former>=<current> pairs,
+    // e.g. ccrn/v1.legacy-urn-template-mapping: "az=zone". URNs of legacy templates are parsed into the current
+    // fields, see apis.CRDInfo.UpgradeURN.
+    LegacyURNMappingAnnotationFormat = "ccrn/%s.legacy-urn-template-mapping"
+
+    // FieldMappingAnnotationFormat defines the format for field mapping annotations used by the conversion webhook.
+    // The value is a comma separated list of <field of this version>=<field of the storage version> pairs.
+    FieldMappingAnnotationFormat = "ccrn/%s.field-mapping"
+
+    // ObjectMappingAnnotationFormat defines the format for annotations mapping the fields of a version to the live
+    // objects identified by its CCRNs. The value is a comma separated list of <field>=<path in the object> pairs,
+    // e.g. "namespace=metadata.namespace, name=metadata.name, owner=metadata.ownerReferences[0].name".
+    ObjectMappingAnnotationFormat = "ccrn/%s.object-mapping"
+
+    // SourceResourceAnnotation declares the Kubernetes resource of the live objects identified by the CCRNs
+    // in kubectl notation, e.g. "pods" or "deployments.apps". It defaults to the kind of the CRD.
+    SourceResourceAnnotation = "ccrn/source-resource"
+
+    // AliasesAnnotation declares comma separated short names of the resource type, e.g. "pod,po",
+    // so CCRNs can be written as "ccrn=pod/v1, ..."
+    AliasesAnnotation = "ccrn/aliases"
+
+    // ExternalTemplateAnnotationFormat defines the format for templates of external identifiers like AWS ARNs,
+    // e.g. "ccrn/v1.arn-template". The scheme names the identifier format, see pkg/convert.
+    ExternalTemplateAnnotationFormat = "ccrn/%s.%s-template"
+
+    // DeprecatedAnnotationFormat defines the format for annotations deprecating a version, e.g.
+    // ccrn/v1alpha1.deprecated: "2025-12-31". The value is the sunset date of the version, or "true"
+    // to deprecate it without one.
+    DeprecatedAnnotationFormat = "ccrn/%s.deprecated"
+
+    // WildcardsAnnotationFormat defines the format for annotations declaring the fields accepting "*", e.g.
+    // ccrn/v1.wildcards: "cluster=any, name=literal". Without it every field accepts "*" as far as its
+    // schema allows, with it only the declared fields do, see apis.WildcardMode.
+    WildcardsAnnotationFormat = "ccrn/%s.wildcards"
 )
 
 // CRDLoadingResult contains detailed information about CRD loading operation
@@ -55,19 +116,18 @@ type CRDLoadingResult struct {
 // including multi-document YAML files with Helm-style "---" separators.
 type FilesystemBackend struct {
     log         *logrus.Logger
-    crds        map[string]*apis.CRDInfo                               // Cache of loaded CRD information
-    crdsByFile  map[string][]*apiextensionsv1.CustomResourceDefinition // CRDs organized by source file
-    validators  map[string]*validation.SchemaValidator                 // Schema validators for each CRD version
-    crdsMutex   sync.RWMutex                                           // Thread-safe access to CRD data
-    ccrnGroup   string                                                 // CCRN group for filtering CRDs
-    loadedPaths []string                                               // Paths that were loaded (for refresh functionality)
+    snapshot    atomic.Pointer[crdSnapshot] // Loaded CRDs, validators and aliases, read without locking
+    writeMutex  sync.Mutex                  // Serializes the writers of the snapshot
+    ccrnGroup   string                      // CCRN group for filtering CRDs
+    loadedPaths []string                    // Paths that were loaded (for refresh functionality)
+    schemaDiff  schemaDiffRecorder          // Changes of the CRD set by the last refresh changing it
 }
 
 // NewOfflineBackend creates a new filesystem-based validation backend
 //
 // Parameters:
 //   - log: Logger instance (will create default if nil)
-//   - ccrnGroup: CCRN group name used for filtering relevant CRDs
+//   - ccrnGroup: CCRN group name used for filtering relevant CRDs, comma separated for several groups
 //
 // Returns:
 //   - *FilesystemBackend: Configured filesystem backend instance
@@ -76,14 +136,13 @@ func NewOfflineBackend(log *logrus.Logger, ccrnGroup string) *FilesystemBackend
         log = logrus.New()
     }
 
-    return &FilesystemBackend{
+    fb := &FilesystemBackend{
         log:         log,
-        crds:        make(map[string]*apis.CRDInfo),
-        crdsByFile:  make(map[string][]*apiextensionsv1.CustomResourceDefinition),
-        validators:  make(map[string]*validation.SchemaValidator),
         ccrnGroup:   ccrnGroup,
         loadedPaths: make([]string, 0),
     }
+    fb.snapshot.Store(newCRDSnapshot())
+    return fb
 }
 
 // LoadCRDs loads CRD definitions from a glob pattern (files or directories)
@@ -137,6 +196,35 @@ func (fb *FilesystemBackend) LoadCRDs(pattern string) error {
     return nil
 }
 
+// LoadCRDsFromBytes loads CRD definitions from multi-document YAML content,
+// e.g. a CRD bundle downloaded via HTTP. The content is not reloaded by Refresh.
+//
+// Parameters:
+//   - source: Name of the content (e.g. its URL), used in logs and errors
+//   - content: Multi-document YAML content
+//
+// Returns:
+//   - error: Error if no CRDs could be loaded from the content
+func (fb *FilesystemBackend) LoadCRDsFromBytes(source string, content []byte) error {
+    fb.log.Infof("Loading CRDs from %s", source)
+
+    result := &CRDLoadingResult{
+        Errors:        make([]error, 0),
+        LoadedCRDKeys: make([]string, 0),
+    }
+    result.ProcessedFiles++
+    fb.processContent(source, content, result)
+    fb.logLoadingResults(result)
+
+    if result.ProcessedCRDs == 0 {
+        if len(result.Errors) > 0 {
+            return fmt.Errorf("failed to load any CRDs from %s: %w", source, errors.Join(result.Errors...))
+        }
+        return fmt.Errorf("no CRDs found in %s", source)
+    }
+    return nil
+}
+
 // LoadCRDsFromDirectory loads all CRD YAML files from a directory recursively
 // This method searches both the root directory and subdirectories for YAML files
 //
@@ -165,10 +253,7 @@ func (fb *FilesystemBackend) LoadCRDsFromDirectory(dir string) error {
             allErrors = append(allErrors, fmt.Errorf("pattern %s: %w", pattern, err))
         } else {
             // Count how many CRDs we have now to track progress
-            fb.crdsMutex.RLock()
-            currentCount := len(fb.crds)
-            fb.crdsMutex.RUnlock()
-            totalLoaded = currentCount
+            totalLoaded = len(fb.snapshot.Load().crds)
         }
     }
 
@@ -200,6 +285,16 @@ func (fb *FilesystemBackend) processFile(filePath string, result *CRDLoadingResu
         return
     }
 
+    fb.processContent(filePath, fileContent, result)
+}
+
+// processContent processes the YAML documents of a file or downloaded bundle
+//
+// Parameters:
+//   - filePath: Path or URL the content was read from, used as source in logs and errors
+//   - fileContent: Multi-document YAML content
+//   - result: Result accumulator for tracking processing statistics
+func (fb *FilesystemBackend) processContent(filePath string, fileContent []byte, result *CRDLoadingResult) {
     // Split content into individual YAML documents
     documents, err := fb.splitYAMLDocuments(string(fileContent))
     if err != nil {
@@ -247,9 +342,11 @@ func (fb *FilesystemBackend) processFile(filePath string, result *CRDLoadingResu
 
     // Store all successfully loaded CRDs from this file
     if len(loadedCRDs) > 0 {
-        fb.crdsMutex.Lock()
-        fb.crdsByFile[filePath] = loadedCRDs
-        fb.crdsMutex.Unlock()
+        fb.writeMutex.Lock()
+        next := fb.snapshot.Load().clone()
+        next.crdsByFile[filePath] = loadedCRDs
+        fb.snapshot.Store(next)
+        fb.writeMutex.Unlock()
     }
 }
 
@@ -385,7 +482,7 @@ func (fb *FilesystemBackend) validateCRDStructure(crd *apiextensionsv1.CustomRes
     return nil
 }
 
-// isCCRNRelevant checks if a CRD is relevant to the configured CCRN group
+// isCCRNRelevant checks if a CRD is relevant to the configured CCRN groups, see apis.InGroups
 //
 // Parameters:
 //   - crd: CRD to check
@@ -393,7 +490,7 @@ func (fb *FilesystemBackend) validateCRDStructure(crd *apiextensionsv1.CustomRes
 // Returns:
 //   - bool: true if CRD is relevant to CCRN group
 func (fb *FilesystemBackend) isCCRNRelevant(crd *apiextensionsv1.CustomResourceDefinition) bool {
-    return strings.Contains(crd.Spec.Group, fb.ccrnGroup)
+    return apis.InGroups(crd.Spec.Group, fb.ccrnGroup)
 }
 
 // storeCRD stores a validated CRD and creates necessary validators
@@ -404,8 +501,12 @@ func (fb *FilesystemBackend) isCCRNRelevant(crd *apiextensionsv1.CustomResourceD
 // Returns:
 //   - error: Error if storage fails
 func (fb *FilesystemBackend) storeCRD(crd *apiextensionsv1.CustomResourceDefinition) error {
-    fb.crdsMutex.Lock()
-    defer fb.crdsMutex.Unlock()
+    fb.writeMutex.Lock()
+    defer fb.writeMutex.Unlock()
+
+    next := fb.snapshot.Load().clone()
+    aliases := extractAliases(crd)
+    registerAliases(fb.log, next.aliases, crd, aliases)
 
     // Process each version of the CRD
     for _, version := range crd.Spec.Versions {
@@ -421,20 +522,34 @@ func (fb *FilesystemBackend) storeCRD(crd *apiextensionsv1.CustomResourceDefinit
 
         // Create CRD info structure
         crdInfo := &apis.CRDInfo{
-            Name:      crd.Name,
-            Plural:    crd.Spec.Names.Plural,
-            Singular:  crd.Spec.Names.Singular,
-            Group:     crd.Spec.Group,
-            Kind:      crd.Spec.Names.Kind,
-            Version:   version.Name,
-            Schema:    version.Schema.OpenAPIV3Schema,
-            URNFormat: urnFormat,
+            Name:       crd.Name,
+            Plural:     crd.Spec.Names.Plural,
+            Singular:   crd.Spec.Names.Singular,
+            Group:      crd.Spec.Group,
+            Kind:       crd.Spec.Names.Kind,
+            Version:    version.Name,
+            Schema:     version.Schema.OpenAPIV3Schema,
+            URNFormat:  urnFormat,
+            Deprecated: version.Deprecated,
         }
-
-        fb.crds[crdKey] = crdInfo
+        if version.DeprecationWarning != nil {
+            crdInfo.DeprecationWarning = *version.DeprecationWarning
+        }
+        applyDeprecation(fb.log, crd, crdKey, crdInfo)
+        applyWildcards(fb.log, crd, crdKey, crdInfo)
+        crdInfo.FieldMapping = extractFieldMapping(crd, version.Name)
+        crdInfo.ObjectMapping = extractObjectMapping(crd, version.Name)
+        crdInfo.SourceResource = crd.Annotations[SourceResourceAnnotation]
+        crdInfo.Aliases = aliases
+        crdInfo.ExternalTemplates = extractExternalTemplates(crd, version.Name)
+        crdInfo.LegacyURNFormats = extractLegacyURNTemplates(crd, version.Name)
+        applyLegacyURNMapping(fb.log, crd, crdKey, crdInfo)
+        logTemplateIssues(fb.log, crdKey, crdInfo)
+
+        next.crds[crdKey] = crdInfo
 
         // Create schema validator for this version
-        if err := fb.createSchemaValidator(crdKey, version); err != nil {
+        if err := fb.createSchemaValidator(next, crdKey, version); err != nil {
             fb.log.Warnf("Failed to create schema validator for %s: %v", crdKey, err)
             // Don't fail the entire operation for validator creation issues
         }
@@ -442,6 +557,7 @@ func (fb *FilesystemBackend) storeCRD(crd *apiextensionsv1.CustomResourceDefinit
         fb.log.Debugf("Successfully stored CRD version: %s", crdKey)
     }
 
+    fb.snapshot.Store(next)
     return nil
 }
 
@@ -454,27 +570,300 @@ func (fb *FilesystemBackend) storeCRD(crd *apiextensionsv1.CustomResourceDefinit
 // Returns:
 //   - string: URN template if found, empty string otherwise
 func (fb *FilesystemBackend) extractURNTemplate(crd *apiextensionsv1.CustomResourceDefinition, version string) string {
-    if crd.Annotations == nil {
+    return resolveURNTemplate(crd, version)
+}
+
+// resolveURNTemplate returns the URN template of a version: its own template if it declares one, otherwise the
+// base template of the CRD followed by the suffix of the version. Without base template the suffix is ignored.
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//   - version: Version name to resolve the template for
+//
+// Returns:
+//   - string: URN template if declared, empty string otherwise
+func resolveURNTemplate(crd *apiextensionsv1.CustomResourceDefinition, version string) string {
+    if template := crd.Annotations[fmt.Sprintf(URNTemplateAnnotationFormat, version)]; template != "" {
+        return template
+    }
+    base := crd.Annotations[BaseURNTemplateAnnotation]
+    if base == "" {
         return ""
     }
+    return base + crd.Annotations[fmt.Sprintf(URNTemplateSuffixAnnotationFormat, version)]
+}
+
+// extractLegacyURNTemplates extracts the former URN templates of a version, see LegacyURNTemplatesAnnotationFormat
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//   - version: Version name to look for
+//
+// Returns:
+//   - []string: Legacy URN templates in priority order, nil if not declared
+func extractLegacyURNTemplates(crd *apiextensionsv1.CustomResourceDefinition, version string) []string {
+    var templates []string
+    for _, line := range strings.Split(crd.Annotations[fmt.Sprintf(LegacyURNTemplatesAnnotationFormat, version)], "\n") {
+        if template := strings.TrimSpace(line); template != "" {
+            templates = append(templates, template)
+        }
+    }
+    return templates
+}
+
+// extractFieldMapping extracts the field mapping from CRD annotations for a specific version
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//   - version: Version name to look for
+//
+// Returns:
+//   - map[string]string: Field names of the version mapped to storage version field names, nil if not declared
+func extractFieldMapping(crd *apiextensionsv1.CustomResourceDefinition, version string) map[string]string {
+    return extractMapping(crd, FieldMappingAnnotationFormat, version)
+}
+
+// extractObjectMapping extracts the mapping of fields to paths in the live objects for a specific version
+//
+// Parameters:
+//   - crd: The CRD to extract the object mapping from
+//   - version: The version to look up the object mapping annotation for
+//
+// Returns:
+//   - map[string]string: CCRN fields mapped to paths in the live objects, nil if not annotated
+func extractObjectMapping(crd *apiextensionsv1.CustomResourceDefinition, version string) map[string]string {
+    return extractMapping(crd, ObjectMappingAnnotationFormat, version)
+}
+
+// extractDeprecation extracts the deprecation of a specific version from CRD annotations
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//   - version: Version name to look for
+//
+// Returns:
+//   - time.Time: Sunset date of the version, zero if it has none
+//   - bool: Whether the annotation deprecates the version
+//   - error: Invalid sunset date, the version is deprecated nonetheless
+func extractDeprecation(crd *apiextensionsv1.CustomResourceDefinition, version string) (time.Time, bool, error) {
+    annotation, exists := crd.Annotations[fmt.Sprintf(DeprecatedAnnotationFormat, version)]
+    if !exists || strings.EqualFold(strings.TrimSpace(annotation), "false") {
+        return time.Time{}, false, nil
+    }
+
+    value := strings.TrimSpace(annotation)
+    if value == "" || strings.EqualFold(value, "true") {
+        return time.Time{}, true, nil
+    }
+    sunset, err := time.Parse(time.DateOnly, value)
+    if err != nil {
+        return time.Time{}, true, fmt.Errorf("invalid sunset date %q, use YYYY-MM-DD", value)
+    }
+
+    return sunset, true, nil
+}
+
+// applyDeprecation marks the CRD version deprecated if its annotation says so, see DeprecatedAnnotationFormat
+func applyDeprecation(log *logrus.Logger, crd *apiextensionsv1.CustomResourceDefinition, crdKey string, crdInfo *apis.CRDInfo) {
+    sunset, deprecated, err := extractDeprecation(crd, crdInfo.Version)
+    if err != nil {
+        log.Warnf("Deprecation of %s: %v", crdKey, err)
+    }
+    if deprecated {
+        crdInfo.Deprecated = true
+        crdInfo.SunsetDate = sunset
+    }
+}
+
+// applyWildcards sets the declared wildcards of the CRD version, see WildcardsAnnotationFormat. Invalid
+// declarations are logged and leave the wildcards undeclared.
+func applyWildcards(log *logrus.Logger, crd *apiextensionsv1.CustomResourceDefinition, crdKey string, crdInfo *apis.CRDInfo) {
+    annotation, exists := crd.Annotations[fmt.Sprintf(WildcardsAnnotationFormat, crdInfo.Version)]
+    if !exists {
+        return
+    }
+    wildcards, err := apis.ParseWildcards(annotation)
+    if err != nil {
+        log.Warnf("Wildcards of %s: %v", crdKey, err)
+        return
+    }
+    crdInfo.Wildcards = wildcards
+}
+
+// applyLegacyURNMapping sets the renamed fields of the legacy URN templates of the CRD version, see
+// LegacyURNMappingAnnotationFormat. Invalid mappings are logged and leave the fields unmapped.
+func applyLegacyURNMapping(log *logrus.Logger, crd *apiextensionsv1.CustomResourceDefinition, crdKey string, crdInfo *apis.CRDInfo) {
+    annotation, exists := crd.Annotations[fmt.Sprintf(LegacyURNMappingAnnotationFormat, crdInfo.Version)]
+    if !exists {
+        return
+    }
+    mapping, err := apis.ParseTemplateMapping(annotation)
+    if err != nil {
+        log.Warnf("Legacy URN template mapping of %s: %v", crdKey, err)
+        return
+    }
+    crdInfo.LegacyURNMapping = mapping
+}
+
+// extractMapping parses the comma separated <from>=<to> pairs of the annotation for a specific version
+func extractMapping(crd *apiextensionsv1.CustomResourceDefinition, annotationFormat, version string) map[string]string {
+    annotation, exists := crd.Annotations[fmt.Sprintf(annotationFormat, version)]
+    if !exists {
+        return nil
+    }
+
+    mapping := make(map[string]string)
+    for _, pair := range strings.Split(annotation, ",") {
+        from, to, found := strings.Cut(strings.TrimSpace(pair), "=")
+        if !found || from == "" || to == "" {
+            continue
+        }
+        mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+    }
+
+    return mapping
+}
+
+// extractExternalTemplates extracts the templates of external identifiers from CRD annotations for a specific version
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//   - version: Version name to look for
+//
+// Returns:
+//   - map[string]string: Templates by scheme (e.g. "arn"), nil if none are declared
+func extractExternalTemplates(crd *apiextensionsv1.CustomResourceDefinition, version string) map[string]string {
+    prefix, suffix := fmt.Sprintf(ExternalTemplateAnnotationFormat, version, ""), "-template"
+    prefix = strings.TrimSuffix(prefix, suffix)
+
+    var templates map[string]string
+    for key, template := range crd.Annotations {
+        scheme, found := strings.CutPrefix(key, prefix)
+        if !found || !strings.HasSuffix(scheme, suffix) {
+            continue
+        }
+        scheme = strings.TrimSuffix(scheme, suffix)
+        if scheme == "" || scheme == "urn" || strings.Contains(scheme, ".") {
+            continue
+        }
+        if templates == nil {
+            templates = make(map[string]string)
+        }
+        templates[scheme] = template
+    }
+
+    return templates
+}
+
+// extractAliases extracts the short names of the resource type from the CRD annotations
+//
+// Parameters:
+//   - crd: CRD containing annotations
+//
+// Returns:
+//   - []string: Lowercase aliases of the resource type, nil if not declared
+func extractAliases(crd *apiextensionsv1.CustomResourceDefinition) []string {
+    var aliases []string
+    for _, alias := range strings.Split(crd.Annotations[AliasesAnnotation], ",") {
+        if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+            aliases = append(aliases, alias)
+        }
+    }
+
+    return aliases
+}
+
+// registerAliases maps the aliases to the resource type of the CRD, aliases already taken
+// by another resource type are skipped
+//
+// Parameters:
+//   - log: Logger to report conflicting aliases to
+//   - registry: Aliases of all loaded resource types
+//   - crd: CRD declaring the aliases
+//   - aliases: Aliases of the CRD, see extractAliases
+func registerAliases(log *logrus.Logger, registry apis.Aliases, crd *apiextensionsv1.CustomResourceDefinition, aliases []string) {
+    resourceType := strings.ToLower(crd.Spec.Names.Kind + "." + crd.Spec.Group)
+    for _, alias := range aliases {
+        if existing, taken := registry[alias]; taken && existing != resourceType {
+            log.Warnf("Alias %s of %s is already used by %s, skipping it", alias, resourceType, existing)
+            continue
+        }
+        registry[alias] = resourceType
+    }
+}
+
+// latestVersion returns the highest version of the resource type among the cached CRD versions
+//
+// Parameters:
+//   - crds: CRD information keyed by "<kind>.<group>/<version>"
+//   - resourceType: Resource type "<kind>.<group>"
+//
+// Returns:
+//   - string: Highest version in Kubernetes version order
+//   - bool: false if no version of the resource type is cached
+func latestVersion(crds map[string]*apis.CRDInfo, resourceType string) (string, bool) {
+    prefix := strings.ToLower(resourceType) + "/"
+    var versions []string
+    for key := range crds {
+        if version, found := strings.CutPrefix(key, prefix); found {
+            versions = append(versions, version)
+        }
+    }
 
-    annotationKey := fmt.Sprintf(URNTemplateAnnotationFormat, version)
-    if urnFormat, exists := crd.Annotations[annotationKey]; exists {
-        return urnFormat
+    return apis.HighestVersion(versions), len(versions) > 0
+}
+
+// sortedCRDs returns the cached CRD versions sorted by CCRN key
+//
+// Parameters:
+//   - crds: CRD information keyed by "<kind>.<group>/<version>"
+//
+// Returns:
+//   - []*apis.CRDInfo: CRD information of all versions
+func sortedCRDs(crds map[string]*apis.CRDInfo) []*apis.CRDInfo {
+    list := make([]*apis.CRDInfo, 0, len(crds))
+    for _, crdInfo := range crds {
+        list = append(list, crdInfo)
     }
+    sort.Slice(list, func(i, j int) bool {
+        return list[i].CCRNKey() < list[j].CCRNKey()
+    })
+
+    return list
+}
 
-    return ""
+// logTemplateIssues lints the current and legacy URN templates of a CRD version and logs the issues found
+//
+// Parameters:
+//   - log: Logger to report the issues to
+//   - crdKey: Key of the CRD version, used in the log messages
+//   - crdInfo: CRD information carrying the URN template and schema
+func logTemplateIssues(log *logrus.Logger, crdKey string, crdInfo *apis.CRDInfo) {
+    for i, template := range crdInfo.URNFormats() {
+        if i > 0 {
+            // Legacy templates are checked with the fields they are parsed into
+            template = crdInfo.LegacyURNMapping.ApplyTemplate(template)
+        }
+        for _, issue := range parser.ValidateTemplate(template, crdInfo) {
+            if issue.Severity == parser.SeverityError {
+                log.Errorf("URN template %s of %s: %s", template, crdKey, issue)
+            } else {
+                log.Warnf("URN template %s of %s: %s", template, crdKey, issue)
+            }
+        }
+    }
 }
 
 // createSchemaValidator creates and stores a schema validator for a CRD version
 //
 // Parameters:
+//   - next: Snapshot being written to store the validator in
 //   - crdKey: Key to store the validator under
 //   - version: CRD version spec containing the schema
 //
 // Returns:
 //   - error: Error if validator creation fails
-func (fb *FilesystemBackend) createSchemaValidator(crdKey string, version apiextensionsv1.CustomResourceDefinitionVersion) error {
+func (fb *FilesystemBackend) createSchemaValidator(next *crdSnapshot, crdKey string, version apiextensionsv1.CustomResourceDefinitionVersion) error {
     if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
         return fmt.Errorf("no schema available for version")
     }
@@ -496,10 +885,68 @@ func (fb *FilesystemBackend) createSchemaValidator(crdKey string, version apiext
         return fmt.Errorf("failed to create schema validator: %w", err)
     }
 
-    fb.validators[crdKey] = &validator
+    next.validators[crdKey] = &validator
+
+    // Compile the x-kubernetes-validations rules, they need a structural schema like in the apiserver
+    if !hasValidationRules(&jsonSchemaProps) {
+        return nil
+    }
+    structural, err := structuralschema.NewStructural(&jsonSchemaProps)
+    if err != nil {
+        return fmt.Errorf("failed to evaluate x-kubernetes-validations of non-structural schema: %w", err)
+    }
+    if celValidator := cel.NewValidator(structural, true, celconfig.PerCallLimit); celValidator != nil {
+        next.celRules[crdKey] = &celRules{validator: celValidator, schema: structural}
+    }
     return nil
 }
 
+// celRules are the compiled x-kubernetes-validations rules of a CRD version
+type celRules struct {
+    validator *cel.Validator
+    schema    *structuralschema.Structural
+}
+
+// validate evaluates the rules against the object within the runtime cost budget of the apiserver
+//
+// Parameters:
+//   - obj: Unstructured object to validate
+//
+// Returns:
+//   - field.ErrorList: Violated rules and compilation or budget errors
+func (r *celRules) validate(obj map[string]interface{}) field.ErrorList {
+    errs, _ := r.validator.Validate(context.Background(), field.NewPath(""), r.schema, obj, nil, celconfig.RuntimeCELCostBudget)
+    return errs
+}
+
+// hasValidationRules reports whether the schema or any nested schema declares x-kubernetes-validations
+//
+// Parameters:
+//   - schema: Schema to inspect
+//
+// Returns:
+//   - bool: true if at least one rule is declared
+func hasValidationRules(schema *apiextensions.JSONSchemaProps) bool {
+    if schema == nil {
+        return false
+    }
+    if len(schema.XValidations) > 0 {
+        return true
+    }
+    for _, property := range schema.Properties {
+        if hasValidationRules(&property) {
+            return true
+        }
+    }
+    if schema.Items != nil && hasValidationRules(schema.Items.Schema) {
+        return true
+    }
+    if schema.AdditionalProperties != nil && hasValidationRules(schema.AdditionalProperties.Schema) {
+        return true
+    }
+    return false
+}
+
 // isYAMLFile checks if a file has a YAML extension
 //
 // Parameters:
@@ -566,12 +1013,9 @@ func (fb *FilesystemBackend) logLoadingResults(result *CRDLoadingResult) {
 
 // GetCRD retrieves CRD information for a given ccrnVersion
 func (fb *FilesystemBackend) GetCRD(ccrnVersion string) (*apis.CRDInfo, error) {
-    fb.crdsMutex.RLock()
-    defer fb.crdsMutex.RUnlock()
-
-    crdInfo, exists := fb.crds[ccrnVersion]
+    crdInfo, exists := fb.snapshot.Load().crds[ccrnVersion]
     if !exists {
-        return nil, fmt.Errorf("CRD for resource type %s not found", ccrnVersion)
+        return nil, apis.Errorf(apis.ErrCRDNotFound, "CRD for resource type %s not found", ccrnVersion)
     }
 
     return crdInfo, nil
@@ -582,58 +1026,155 @@ func (fb *FilesystemBackend) ValidateResource(namespace string, parsedCCRN *apis
     ccrnVersion := parsedCCRN.CCRNKey()
     kind := parsedCCRN.GetKind()
 
-    fb.crdsMutex.RLock()
-    validator, exists := fb.validators[ccrnVersion]
-    fb.crdsMutex.RUnlock()
+    snapshot := fb.snapshot.Load()
+    validator, exists := snapshot.validators[ccrnVersion]
+    crdInfo := snapshot.crds[ccrnVersion]
+    rules := snapshot.celRules[ccrnVersion]
 
     if !exists || validator == nil {
-        return fmt.Errorf("no schema validator available for %s", ccrnVersion)
+        return apis.Errorf(apis.ErrUnsupportedType, "no schema validator available for %s", ccrnVersion)
+    }
+    if err := apis.CheckWildcards(parsedCCRN, crdInfo); err != nil {
+        return apis.Errorf(apis.ErrSchemaViolation, "validation failed for %s: %w", ccrnVersion, err)
     }
 
     // Convert parsed CCRN to unstructured object for validation
     resourceName := strings.ToLower(kind) + "-validation"
-    resourceObj := parsedCCRN.ToResourceMap(namespace, resourceName)
+    resourceObj := parsedCCRN.ToResourceMap(namespace, resourceName, apis.WithCRD(crdInfo))
 
     // Convert to unstructured for validation
     unstructuredObj := &unstructured.Unstructured{Object: resourceObj}
 
-    // Validate against schema using the custom resource validation
-    if errs := validation.ValidateCustomResource(field.NewPath(""), unstructuredObj, *validator); len(errs) > 0 {
-        var errorMessages []string
+    // Validate against schema using the custom resource validation, then evaluate the CEL rules like the apiserver
+    errs := validation.ValidateCustomResource(field.NewPath(""), unstructuredObj, *validator)
+    errs = slices.DeleteFunc(errs, func(err *field.Error) bool {
+        return isDeclaredWildcard(err, crdInfo)
+    })
+    if rules != nil {
+        errs = append(errs, rules.validate(unstructuredObj.Object)...)
+    }
+    if len(errs) > 0 {
+        // Report every violation on its own, see apis.ErrorMessages
+        violations := make([]error, 0, len(errs))
         for _, err := range errs {
-            errorMessages = append(errorMessages, err.Error())
+            violations = append(violations, apis.Errorf(apis.ErrSchemaViolation, "validation failed for %s: %s", ccrnVersion, err.Error()))
         }
-        return fmt.Errorf("validation failed for %s: %s", ccrnVersion, strings.Join(errorMessages, "; "))
+        return errors.Join(violations...)
     }
 
     fb.log.Debugf("Resource %s validated successfully against schema", ccrnVersion)
     return nil
 }
 
-// GetURNTemplate retrieves the URN template from CRD annotations
+// isDeclaredWildcard reports whether the schema violation is about "*" in a field declaring it a wildcard
+// matching any value, which the schema constraints do not apply to, see apis.WildcardAny
+func isDeclaredWildcard(err *field.Error, crdInfo *apis.CRDInfo) bool {
+    if err.BadValue != apis.Wildcard || crdInfo == nil || crdInfo.Wildcards == nil {
+        return false
+    }
+    // Paths are those of the resource object, e.g. cluster, regions[0] for list items or spec.cluster
+    // for spec-wrapped schemas, below the root path passed to the validator
+    prefix := field.NewPath("").String() + "."
+    if crdInfo.SpecWrapped() {
+        prefix += "spec."
+    }
+    path, isField := strings.CutPrefix(err.Field, prefix)
+    if !isField {
+        return false
+    }
+    if index := strings.IndexByte(path, '['); index >= 0 {
+        path = path[:index]
+    }
+    return crdInfo.IsWildcard(path, apis.Wildcard)
+}
+
+// GetURNTemplate retrieves the URN template from CRD annotations. The CRD is looked up by resource type
+// "<kind>.<group>" like in CCRNs, or by the name of the CRD.
 func (fb *FilesystemBackend) GetURNTemplate(crdName, version string) (string, error) {
-    fb.crdsMutex.RLock()
-    defer fb.crdsMutex.RUnlock()
+    snapshot := fb.snapshot.Load()
+    if crdInfo, exists := snapshot.crds[strings.ToLower(crdName+"/"+version)]; exists {
+        if crdInfo.URNFormat != "" {
+            return crdInfo.URNFormat, nil
+        }
+        return "", apis.Errorf(apis.ErrCRDNotFound, "URN template annotation %s not found in CRD %s",
+            fmt.Sprintf(URNTemplateAnnotationFormat, version), crdInfo.Name)
+    }
 
     // Search through all loaded CRDs to find the specified one
-    for _, crds := range fb.crdsByFile {
+    for _, crds := range snapshot.crdsByFile {
         for _, crd := range crds {
             if crd.Name == crdName {
-                annotationKey := fmt.Sprintf(URNTemplateAnnotationFormat, version)
-                if crd.Annotations != nil {
-                    if urnFormat, exists := crd.Annotations[annotationKey]; exists && urnFormat != "" {
-                        return urnFormat, nil
-                    }
+                if urnFormat := resolveURNTemplate(crd, version); urnFormat != "" {
+                    return urnFormat, nil
                 }
-                return "", fmt.Errorf("URN template annotation %s not found in CRD %s", annotationKey, crdName)
+                return "", apis.Errorf(apis.ErrCRDNotFound, "URN template annotation %s not found in CRD %s",
+                    fmt.Sprintf(URNTemplateAnnotationFormat, version), crdName)
             }
         }
     }
 
-    return "", fmt.Errorf("CRD %s not found in loaded CRDs", crdName)
+    return "", apis.Errorf(apis.ErrCRDNotFound, "CRD %s not found in loaded CRDs", crdName)
+}
+
+// ResolveAlias returns the full resource type of a short name declared via AliasesAnnotation
+func (fb *FilesystemBackend) ResolveAlias(alias string) (string, bool) {
+    return fb.snapshot.Load().aliases.ResolveAlias(alias)
+}
+
+// ResolveSource returns the highest version of the resource type whose source is the resource
+func (fb *FilesystemBackend) ResolveSource(resource schema.GroupResource) (*apis.CRDInfo, bool) {
+    crdInfo, found := fb.snapshot.Load().sourceIndex()[resource]
+    return crdInfo, found
+}
+
+// ResolveLatestVersion returns the highest served version of the resource type
+func (fb *FilesystemBackend) ResolveLatestVersion(resourceType string) (string, bool) {
+    return latestVersion(fb.snapshot.Load().crds, resourceType)
+}
+
+// ListCRDs returns the information of all loaded CRD versions sorted by CCRN key
+func (fb *FilesystemBackend) ListCRDs() []*apis.CRDInfo {
+    return sortedCRDs(fb.snapshot.Load().crds)
+}
+
+// CustomResourceDefinitions returns the loaded CRDs sorted by name, a CRD loaded from several files is
+// taken from the last file in path order
+func (fb *FilesystemBackend) CustomResourceDefinitions() []*apiextensionsv1.CustomResourceDefinition {
+    snapshot := fb.snapshot.Load()
+    files := make([]string, 0, len(snapshot.crdsByFile))
+    for file := range snapshot.crdsByFile {
+        files = append(files, file)
+    }
+    sort.Strings(files)
+
+    byName := map[string]*apiextensionsv1.CustomResourceDefinition{}
+    for _, file := range files {
+        for _, crd := range snapshot.crdsByFile[file] {
+            byName[crd.Name] = crd
+        }
+    }
+    crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(byName))
+    for _, crd := range byName {
+        crds = append(crds, crd)
+    }
+    sort.Slice(crds, func(i, j int) bool { return crds[i].Name < crds[j].Name })
+    return crds
+}
+
+// Export returns a self-contained schema bundle of all loaded CRD versions
+//
+// Parameters:
+//   - format: apis.SchemaFormatJSONSchema or apis.SchemaFormatOpenAPI
+//
+// Returns:
+//   - map[string]any: The bundle, see apis.ExportSchemas
+//   - error: An error if the format is invalid or a schema cannot be exported
+func (fb *FilesystemBackend) Export(format string) (map[string]any, error) {
+    return apis.ExportSchemas(fb.ListCRDs(), format)
 }
 
-// Refresh reloads CRD information from previously loaded paths
+// Refresh reloads CRD information from previously loaded paths. The CRDs are loaded into a new snapshot
+// which replaces the current one once complete, so lookups keep using the previous CRDs meanwhile.
 func (fb *FilesystemBackend) Refresh() error {
     if len(fb.loadedPaths) == 0 {
         fb.log.Debug("No paths to refresh - no previous LoadCRDs calls")
@@ -642,20 +1183,18 @@ func (fb *FilesystemBackend) Refresh() error {
 
     fb.log.Info("Refreshing CRD information from previously loaded paths")
 
-    // Clear current state
-    fb.crdsMutex.Lock()
-    fb.crds = make(map[string]*apis.CRDInfo)
-    fb.crdsByFile = make(map[string][]*apiextensionsv1.CustomResourceDefinition)
-    fb.validators = make(map[string]*validation.SchemaValidator)
-    fb.crdsMutex.Unlock()
-
-    // Reload from all previously loaded paths
+    // Reload from all previously loaded paths into a separate backend and swap in its snapshot
+    reloaded := NewOfflineBackend(fb.log, fb.ccrnGroup)
     var allErrors []error
     for _, path := range fb.loadedPaths {
-        if err := fb.LoadCRDs(path); err != nil {
+        if err := reloaded.LoadCRDs(path); err != nil {
             allErrors = append(allErrors, fmt.Errorf("failed to refresh path %s: %w", path, err))
         }
     }
+    fb.writeMutex.Lock()
+    previous := fb.snapshot.Swap(reloaded.snapshot.Load())
+    fb.writeMutex.Unlock()
+    fb.schemaDiff.record(fb.log, previous, reloaded.snapshot.Load())
 
     if len(allErrors) > 0 {
         return fmt.Errorf("refresh completed with errors: %w", errors.Join(allErrors...))
@@ -665,12 +1204,14 @@ func (fb *FilesystemBackend) Refresh() error {
     return nil
 }
 
+// LastSchemaDiff returns how the last refresh changing the CRD set changed it, see apis.SchemaDiffSource
+func (fb *FilesystemBackend) LastSchemaDiff() *apis.SchemaDiff {
+    return fb.schemaDiff.load()
+}
+
 // IsResourceTypeSupported checks if a resource type is supported
 func (fb *FilesystemBackend) IsResourceTypeSupported(ccrnVersion string) bool {
-    fb.crdsMutex.RLock()
-    defer fb.crdsMutex.RUnlock()
-
-    _, exists := fb.crds[ccrnVersion]
+    _, exists := fb.snapshot.Load().crds[ccrnVersion]
     return exists
 }
 
@@ -692,11 +1233,9 @@ func (fb *FilesystemBackend) getCRDKey(group, version, kind string) string {
 // Returns:
 //   - []string: List of all loaded CRD keys
 func (fb *FilesystemBackend) GetLoadedCRDs() []string {
-    fb.crdsMutex.RLock()
-    defer fb.crdsMutex.RUnlock()
-
-    keys := make([]string, 0, len(fb.crds))
-    for k := range fb.crds {
+    crds := fb.snapshot.Load().crds
+    keys := make([]string, 0, len(crds))
+    for k := range crds {
         keys = append(keys, k)
     }
     return keys
@@ -707,20 +1246,18 @@ func (fb *FilesystemBackend) GetLoadedCRDs() []string {
 // Returns:
 //   - map[string]interface{}: Statistics including counts and file information
 func (fb *FilesystemBackend) GetLoadingStatistics() map[string]interface{} {
-    fb.crdsMutex.RLock()
-    defer fb.crdsMutex.RUnlock()
-
+    snapshot := fb.snapshot.Load()
     stats := map[string]interface{}{
-        "total_crds":        len(fb.crds),
-        "total_files":       len(fb.crdsByFile),
-        "total_validators":  len(fb.validators),
+        "total_crds":        len(snapshot.crds),
+        "total_files":       len(snapshot.crdsByFile),
+        "total_validators":  len(snapshot.validators),
         "loaded_paths":      fb.loadedPaths,
         "ccrn_group_filter": fb.ccrnGroup,
     }
 
     // Add per-file statistics
     fileStats := make(map[string]int)
-    for filePath, crds := range fb.crdsByFile {
+    for filePath, crds := range snapshot.crdsByFile {
         fileStats[filePath] = len(crds)
     }
     stats["crds_per_file"] = fileStats
@@ -10,6 +10,7 @@ import (
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -151,6 +152,77 @@ var _ = Describe("FilesystemBackend", func() {
 			Entry("with invalid testpod CCRN - wrong API version", "ccrn=invalid.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod", false),
 			Entry("with invalid testpod CCRN - invalid cluster pattern", "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!, namespace=default, name=my-pod", false),
 		)
+
+		It("resolves the latest version and warns about deprecated versions", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "deprecated_crd.yaml"))).To(Succeed())
+			// Act
+			latest, latestErr := validator.ValidateCCRN("ccrn=widget.tr.ccrn.example.com/latest, name=my-widget")
+			deprecated, deprecatedErr := validator.ValidateCCRN("ccrn=widget.tr.ccrn.example.com/v1alpha1, name=my-widget")
+			// Assert
+			Expect(latestErr).ToNot(HaveOccurred())
+			Expect(latest.ParsedCCRN.CCRNKey()).To(Equal("widget.tr.ccrn.example.com/v1"))
+			Expect(latest.Warnings).To(BeEmpty())
+			Expect(deprecatedErr).ToNot(HaveOccurred())
+			Expect(deprecated.Warnings).To(Equal([]string{"widget.tr.ccrn.example.com/v1alpha1 is deprecated, use v1"}))
+		})
+
+		It("reads sunset dates from the deprecated annotations", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "sunset_crd.yaml"))).To(Succeed())
+			// Act
+			result, err := validator.ValidateCCRN("ccrn=gadget.tr.ccrn.example.com/v1beta1, name=my-gadget")
+			current, currentErr := validator.ValidateCCRN("ccrn=gadget.tr.ccrn.example.com/v1, name=my-gadget")
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Warnings).To(Equal([]string{"gadget.tr.ccrn.example.com/v1beta1 is deprecated, sunset on 2999-12-31"}))
+			Expect(currentErr).ToNot(HaveOccurred())
+			Expect(current.Warnings).To(BeEmpty())
+		})
+
+		It("accepts URNs of legacy templates with a warning", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "legacy_crd.yaml"))).To(Succeed())
+			// Act
+			current, currentErr := validator.ValidateCCRN("urn:ccrn:disk.tr.ccrn.example.com/v1/eu-de-1/data")
+			legacy, legacyErr := validator.ValidateCCRN("urn:ccrn:disk.tr.ccrn.example.com/v1/data")
+			// Assert
+			Expect(currentErr).ToNot(HaveOccurred())
+			Expect(current.Warnings).To(BeEmpty())
+			Expect(legacyErr).ToNot(HaveOccurred())
+			Expect(legacy.ParsedCCRN.Fields).To(HaveKeyWithValue("name", "data"))
+			Expect(legacy.Warnings).To(Equal([]string{
+				"URN follows the legacy template urn:ccrn:<ccrn>/<name:dns>, the current template of disk.tr.ccrn.example.com/v1 is urn:ccrn:<ccrn>/<region:[a-z]{2}-[a-z]{2}-[0-9]+>/<name:dns>",
+			}))
+		})
+
+		It("applies the declared wildcard semantics of the fields", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "wildcards_crd.yaml"))).To(Succeed())
+			// Act
+			anyRegion, anyErr := validator.ValidateCCRN("ccrn=host.tr.ccrn.example.com/v1, region=*, tag=*, name=my-host")
+			undeclared, undeclaredErr := validator.ValidateCCRN("ccrn=host.tr.ccrn.example.com/v1, region=eu-de-1, zone=*, name=my-host")
+			literal, literalErr := validator.ValidateCCRN("ccrn=host.tr.ccrn.example.com/v1, region=eu-de-1, name=*")
+			// Assert
+			Expect(anyErr).ToNot(HaveOccurred())
+			Expect(anyRegion.Valid).To(BeTrue(), "region accepts any value, got errors: %v", anyRegion.Errors)
+			Expect(anyRegion.Warnings).To(Equal([]string{"field region is a wildcard and matches any value"}), "tag is a literal value")
+			Expect(undeclaredErr).To(MatchError(apis.ErrSchemaViolation))
+			Expect(undeclared.Errors).To(ContainElement(ContainSubstring("field zone: value '*' is a wildcard, which the field does not accept")))
+			Expect(literalErr).To(MatchError(apis.ErrSchemaViolation))
+			Expect(literal.Errors).To(ContainElement(ContainSubstring(`name: Invalid value: "*"`)))
+		})
+
+		It("reports every schema violation at once", func() {
+			// Act
+			result, err := validator.ValidateCCRN("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!, name=my-pod")
+			// Assert
+			Expect(err).To(MatchError(apis.ErrSchemaViolation))
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Errors).To(HaveLen(2))
+			Expect(result.Errors).To(ContainElement(ContainSubstring("cluster")))
+			Expect(result.Errors).To(ContainElement(ContainSubstring("namespace")))
+		})
 	})
 
 	Context("GetCRD", func() {
@@ -170,6 +242,7 @@ var _ = Describe("FilesystemBackend", func() {
 			_, err := backend.GetCRD("DoesNotExist.ccrn.example.com/v1")
 			// Assert
 			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(apis.ErrCRDNotFound))
 		})
 	})
 
@@ -185,6 +258,22 @@ var _ = Describe("FilesystemBackend", func() {
 			Expect(val).To(Equal("urn:ccrn:testurn.tr.ccrn.example.com/v1/<name>"))
 		})
 
+		It("resolves the base URN template and the suffixes of the versions", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "inherited_crd.yaml"))).To(Succeed())
+			// Act
+			base, baseErr := backend.GetURNTemplate("image.tr.ccrn.example.com", "v1")
+			extended, extendedErr := backend.GetURNTemplate("image.tr.ccrn.example.com", "v2")
+			overridden, overriddenErr := backend.GetURNTemplate("image.tr.ccrn.example.com", "v3")
+			// Assert
+			Expect(baseErr).ToNot(HaveOccurred())
+			Expect(base).To(Equal("urn:ccrn:<ccrn>/<region>/<name>"))
+			Expect(extendedErr).ToNot(HaveOccurred())
+			Expect(extended).To(Equal("urn:ccrn:<ccrn>/<region>/<name>/<revision>"))
+			Expect(overriddenErr).ToNot(HaveOccurred())
+			Expect(overridden).To(Equal("urn:ccrn:<ccrn>/<name>"))
+		})
+
 		It("returns error if URN template annotation is missing", func() {
 			// Arrange
 			crdPath := filepath.Join("testdata", "testurn2_crd.yaml")
@@ -202,6 +291,7 @@ var _ = Describe("FilesystemBackend", func() {
 			// Assert
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("CRD doesnotexist not found"))
+			Expect(err).To(MatchError(apis.ErrCRDNotFound))
 		})
 	})
 
@@ -220,6 +310,95 @@ var _ = Describe("FilesystemBackend", func() {
 		})
 	})
 
+	Context("Refresh", func() {
+		It("keeps serving the loaded CRDs while refreshing", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "minimal_crd.yaml"))).To(Succeed())
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				for range 20 {
+					Expect(backend.Refresh()).To(Succeed())
+				}
+			}()
+			// Act & Assert
+			for refreshing := true; refreshing; {
+				select {
+				case <-done:
+					refreshing = false
+				default:
+				}
+				Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+			}
+		})
+
+		It("remembers how a refresh changed the CRD set", func() {
+			// Arrange
+			content, err := os.ReadFile(filepath.Join("testdata", "minimal_crd.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			path := filepath.Join(GinkgoT().TempDir(), "crd.yaml")
+			Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+			Expect(backend.LoadCRDs(path)).To(Succeed())
+			Expect(backend.Refresh()).To(Succeed())
+			Expect(backend.LastSchemaDiff()).To(BeNil(), "an unchanged CRD set is no change")
+			changed := strings.Replace(string(content), "maxLength: 253", "maxLength: 63", 1)
+			Expect(os.WriteFile(path, []byte(changed), 0o600)).To(Succeed())
+			// Act
+			Expect(backend.Refresh()).To(Succeed())
+			// Assert
+			diff := backend.LastSchemaDiff()
+			Expect(diff).ToNot(BeNil())
+			Expect(diff.Time).ToNot(BeZero())
+			Expect(diff.Added).To(BeEmpty())
+			Expect(diff.Removed).To(BeEmpty())
+			Expect(diff.Changed).To(Equal([]apis.TypeChange{{Type: "testresource.tr.ccrn.example.com/v1", FieldsChanged: []string{"name"}}}))
+		})
+	})
+
+	Context("ResolveAlias", func() {
+		It("resolves the aliases declared in the CRD annotations", func() {
+			// Arrange
+			backend.LoadCRDs(filepath.Join("testdata", "testpod_crd.yaml"))
+			// Act
+			resourceType, exists := backend.ResolveAlias("PO")
+			// Assert
+			Expect(exists).To(BeTrue())
+			Expect(resourceType).To(Equal("pod.k8s-registry.tr.ccrn.example.com"))
+			crdInfo, err := backend.GetCRD("pod.k8s-registry.tr.ccrn.example.com/v1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(crdInfo.Aliases).To(Equal([]string{"pod", "po"}))
+		})
+
+		It("does not resolve unknown aliases", func() {
+			// Act
+			_, exists := backend.ResolveAlias("pod")
+			// Assert
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Context("ListCRDs", func() {
+		It("lists the loaded CRD versions sorted by CCRN key", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "testpod_crd.yaml"))).To(Succeed())
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "minimal_crd.yaml"))).To(Succeed())
+			// Act
+			crds := backend.ListCRDs()
+			// Assert
+			keys := make([]string, 0, len(crds))
+			for _, crd := range crds {
+				keys = append(keys, crd.CCRNKey())
+			}
+			Expect(keys).To(Equal([]string{"pod.k8s-registry.tr.ccrn.example.com/v1", "testresource.tr.ccrn.example.com/v1"}))
+		})
+
+		It("returns no CRDs before loading", func() {
+			// Act & Assert
+			Expect(backend.ListCRDs()).To(BeEmpty())
+		})
+	})
+
 	Context("ValidateResource", func() {
 		It("validates resource successfully", func() {
 			// Arrange
@@ -240,6 +419,71 @@ var _ = Describe("FilesystemBackend", func() {
 			err := backend.ValidateResource("default", parsed)
 			// Assert
 			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(apis.ErrUnsupportedType))
+		})
+
+		It("returns a schema violation if fields do not match the schema", func() {
+			// Arrange
+			crdPath := filepath.Join("testdata", "minimal_crd.yaml")
+			backend.LoadCRDs(crdPath)
+			parsed := &apis.ParsedResource{Fields: map[string]string{"ccrn": "testresource.tr.ccrn.example.com/v1", "name": "NOT VALID"}}
+			// Act
+			err := backend.ValidateResource("default", parsed)
+			// Assert
+			Expect(err).To(MatchError(apis.ErrSchemaViolation))
+			Expect(err.Error()).To(HavePrefix("validation failed for testresource.tr.ccrn.example.com/v1"))
+		})
+
+		It("validates the fields of spec-wrapped schemas under spec", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "specwrapped_crd.yaml"))).To(Succeed())
+			valid := &apis.ParsedResource{Fields: map[string]string{"ccrn": "gadget.tr.ccrn.example.com/v1", "name": "foo"}}
+			invalid := &apis.ParsedResource{Fields: map[string]string{"ccrn": "gadget.tr.ccrn.example.com/v1", "name": "NOT VALID"}}
+			// Act & Assert
+			Expect(backend.ValidateResource("default", valid)).To(Succeed())
+			Expect(backend.ValidateResource("default", invalid)).To(MatchError(ContainSubstring("spec.name")))
+		})
+
+		It("validates dot path fields as nested objects", func() {
+			// Arrange
+			crdPath := filepath.Join("testdata", "testpod_crd.yaml")
+			backend.LoadCRDs(crdPath)
+			valid := &apis.ParsedResource{Fields: map[string]string{"ccrn": "pod.k8s-registry.tr.ccrn.example.com/v1", "cluster": "eu-de-1", "namespace": "default", "name": "foo", "labels.app": "web"}}
+			invalid := &apis.ParsedResource{Fields: map[string]string{"ccrn": "pod.k8s-registry.tr.ccrn.example.com/v1", "cluster": "eu-de-1", "namespace": "default", "name": "foo", "labels": "[a,b]"}}
+			// Act & Assert
+			Expect(backend.ValidateResource("default", valid)).To(Succeed())
+			Expect(backend.ValidateResource("default", invalid)).To(MatchError(apis.ErrSchemaViolation))
+		})
+
+		It("exempts wildcards declared any from the schema constraints", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "wildcards_crd.yaml"))).To(Succeed())
+			host := func(region, zone string) *apis.ParsedResource {
+				return &apis.ParsedResource{Fields: map[string]string{"ccrn": "host.tr.ccrn.example.com/v1", "region": region, "zone": zone, "name": "my-host"}}
+			}
+			// Act & Assert
+			Expect(backend.ValidateResource("default", host("*", "a"))).To(Succeed())
+			Expect(backend.ValidateResource("default", host("us-east-1", "a"))).To(MatchError(apis.ErrSchemaViolation))
+			Expect(backend.ValidateResource("default", host("eu-de-1", "*"))).To(MatchError(ContainSubstring("field zone: value '*' is a wildcard")))
+		})
+
+		It("evaluates x-kubernetes-validations rules", func() {
+			// Arrange
+			Expect(backend.LoadCRDs(filepath.Join("testdata", "cel_crd.yaml"))).To(Succeed())
+			volume := func(zone, name string) *apis.ParsedResource {
+				return &apis.ParsedResource{Fields: map[string]string{"ccrn": "volume.tr.ccrn.example.com/v1", "region": "eu-de-1", "zone": zone, "name": name}}
+			}
+			// Act
+			validErr := backend.ValidateResource("default", volume("eu-de-1a", "data"))
+			zoneErr := backend.ValidateResource("default", volume("us-east-1a", "data"))
+			nameErr := backend.ValidateResource("default", volume("eu-de-1a", "default"))
+			// Assert
+			Expect(validErr).ToNot(HaveOccurred())
+			Expect(zoneErr).To(MatchError(apis.ErrSchemaViolation))
+			Expect(zoneErr.Error()).To(ContainSubstring("zone must be within the region"))
+			Expect(nameErr).To(MatchError(apis.ErrSchemaViolation))
+			Expect(nameErr.Error()).To(ContainSubstring("name: Invalid value"))
+			Expect(nameErr.Error()).To(ContainSubstring("name default is reserved"))
 		})
 	})
 })
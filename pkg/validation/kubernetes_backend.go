@@ -8,19 +8,22 @@ package validation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
 
 	"k8s.io/apimachinery/pkg/util/rand"
+	"slices"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -35,9 +38,9 @@ type KubernetesBackend struct {
 	kubeClient    kubernetes.Interface
 	apiextClient  apiextensionsclientset.Interface
 	dynamicClient dynamic.Interface
-	ccrns         map[string]*apis.CRDInfo
-	crdsMutex     sync.RWMutex
-	ccrnGroup     string // CCRN group for filtering CRDs
+	snapshot      atomic.Pointer[crdSnapshot] // Cached CRDs and aliases, swapped as a whole on refresh
+	ccrnGroup     string                      // CCRN group for filtering CRDs
+	schemaDiff    schemaDiffRecorder          // Changes of the CRD set by the last refresh changing it
 }
 
 // NewKubernetesBackend creates a new Kubernetes validation backend
@@ -65,9 +68,9 @@ func NewKubernetesBackend(config *rest.Config, log *logrus.Logger, ccrnGroup str
 		kubeClient:    kubeClient,
 		apiextClient:  apiextClient,
 		dynamicClient: dynamicClient,
-		ccrns:         make(map[string]*apis.CRDInfo),
 		ccrnGroup:     ccrnGroup,
 	}
+	backend.snapshot.Store(newCRDSnapshot())
 
 	// Initial load of CRDs
 	if err := backend.Refresh(); err != nil {
@@ -79,23 +82,20 @@ func NewKubernetesBackend(config *rest.Config, log *logrus.Logger, ccrnGroup str
 
 // GetCRD retrieves CRD information for a given apiVersion and kind
 func (kb *KubernetesBackend) GetCRD(crdVersion string) (*apis.CRDInfo, error) {
-	kb.crdsMutex.RLock()
-	crdInfo, exists := kb.ccrns[crdVersion]
-	kb.crdsMutex.RUnlock()
+	crdInfo, exists := kb.snapshot.Load().crds[crdVersion]
 
 	if !exists {
-		// Try to refresh CRDs to see if it was added recently
+		// Try to refresh CRDs to see if it was added recently, a failed refresh is reported to the caller, which
+		// logs it with the request
 		if err := kb.Refresh(); err != nil {
-			kb.log.Errorf("Failed to refresh CRDs: %v", err)
+			return nil, apis.Errorf(apis.ErrCRDNotFound, "CRD for resource type %s not found, refreshing the CRDs failed: %w", crdVersion, err)
 		}
 
 		// Check again after refresh
-		kb.crdsMutex.RLock()
-		crdInfo, exists = kb.ccrns[crdVersion]
-		kb.crdsMutex.RUnlock()
+		crdInfo, exists = kb.snapshot.Load().crds[crdVersion]
 
 		if !exists {
-			return nil, fmt.Errorf("CRD for resource type %s not found", crdVersion)
+			return nil, apis.Errorf(apis.ErrCRDNotFound, "CRD for resource type %s not found", crdVersion)
 		}
 	}
 
@@ -104,56 +104,174 @@ func (kb *KubernetesBackend) GetCRD(crdVersion string) (*apis.CRDInfo, error) {
 
 // ValidateResource validates a resource by creating it in the Kubernetes cluster
 func (kb *KubernetesBackend) ValidateResource(namespace string, parsedCCRN *apis.ParsedResource) error {
+	return kb.createResource(logrus.NewEntry(kb.log), namespace, parsedCCRN, metav1.CreateOptions{}, nil)
+}
+
+// ValidateResourceDryRun validates the resource like ValidateResource with a server-side dry run, so the
+// resource is not persisted
+func (kb *KubernetesBackend) ValidateResourceDryRun(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource) error {
+	return kb.createResource(log, namespace, parsedCCRN, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}, nil)
+}
+
+// ValidateOwnedResource validates the resource like ValidateResource, the created resource is labeled with
+// the CCRN object owning it and carries an owner reference once the UID of the owner is known. A resource of
+// the same type derived from the owner before, e.g. by an earlier update of the CCRN object, is updated
+// instead, so updates do not pile up derived resources. Resources derived from the owner as another type,
+// before an update changed the type of the CCRN, are deleted.
+func (kb *KubernetesBackend) ValidateOwnedResource(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource, owner metav1.OwnerReference) error {
+	return kb.createResource(log, namespace, parsedCCRN, metav1.CreateOptions{}, &owner)
+}
+
+// createResource validates the resource by creating it in the cluster, or by updating the resource derived
+// from the owner if there is one
+func (kb *KubernetesBackend) createResource(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource, createOptions metav1.CreateOptions, owner *metav1.OwnerReference) error {
 
 	// Get CRD info
-	group := parsedCCRN.ApiGroup()
-	version := parsedCCRN.Version()
 	kind := parsedCCRN.GetKind()
 
 	crdInfo, err := kb.GetCRD(parsedCCRN.CCRNKey())
 	if err != nil {
-		return err
+		return apis.Errorf(apis.ErrUnsupportedType, "%w", err)
+	}
+	// The API server checks the schema only, "*" in fields not accepting it is rejected here
+	if err := apis.CheckWildcards(parsedCCRN, crdInfo); err != nil {
+		return apis.Errorf(apis.ErrSchemaViolation, "validation failed for %s: %w", parsedCCRN.CCRNKey(), err)
 	}
 
 	// Generate a resource name based on the kind and timestamp
 	resourceName := fmt.Sprintf("%s-%s-%d", strings.ToLower(kind), rand.String(4), time.Now().Unix())
 
 	// Convert parsed CCRN to a resource map
-	resourceObj := parsedCCRN.ToResourceMap(namespace, resourceName)
+	opts := []apis.ResourceMapOption{apis.WithCRD(crdInfo)}
+	if owner != nil {
+		opts = append(opts, apis.WithOwner(*owner))
+	}
+	resource := &unstructured.Unstructured{Object: parsedCCRN.ToResourceMap(namespace, resourceName, opts...)}
 
 	// Get the resource API
-	gvr := schema.GroupVersionResource{
-		Group:    group,
-		Version:  version,
-		Resource: crdInfo.Plural,
+	gvr := crdInfo.GroupVersionResource()
+	resourceClient := kb.dynamicClient.Resource(gvr).Namespace(namespace)
+
+	// Update the resource derived from the owner before
+	if owner != nil {
+		list, err := resourceClient.List(context.TODO(), metav1.ListOptions{LabelSelector: apis.OwnerSelector(*owner)})
+		if err != nil {
+			return fmt.Errorf("failed to list derived resources: %w", err)
+		}
+		if len(list.Items) > 0 {
+			existing := list.Items[0]
+			resource.SetName(existing.GetName())
+			resource.SetResourceVersion(existing.GetResourceVersion())
+			log.WithField("resource", resource.Object).Infof("Updating resource %s/%s", namespace, existing.GetName())
+			_, err = resourceClient.Update(context.TODO(), resource, metav1.UpdateOptions{DryRun: createOptions.DryRun})
+			if err != nil {
+				return resourceError(err)
+			}
+			return kb.deleteDerivedResources(log, namespace, *owner, gvr.GroupResource())
+		}
 	}
 
 	// Create the resource
-	kb.log.WithField("resource", resourceObj).Infof("Creating resource %s/%s", namespace, resourceName)
-	resourceClient := kb.dynamicClient.Resource(gvr).Namespace(namespace)
-	_, err = resourceClient.Create(context.TODO(), &unstructured.Unstructured{Object: resourceObj}, metav1.CreateOptions{})
+	log.WithField("resource", resource.Object).Infof("Creating resource %s/%s", namespace, resourceName)
+	_, err = resourceClient.Create(context.TODO(), resource, createOptions)
+	if err != nil || owner == nil {
+		return resourceError(err)
+	}
+	return kb.deleteDerivedResources(log, namespace, *owner, gvr.GroupResource())
+}
+
+// resourceError returns the schema violations of a failed create or update, other errors are wrapped
+func resourceError(err error) error {
+	if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+		return schemaViolations(err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create resource: %w", err)
 	}
-
 	return nil
 }
 
-// GetURNTemplate retrieves the URN template from CRD annotations
+// DeleteDerivedResources deletes the resources created for the CCRN object in the namespace, i.e. the
+// resources of all cached resource types labeled with the owner, see apis.OwnerSelector
+func (kb *KubernetesBackend) DeleteDerivedResources(log *logrus.Entry, namespace string, owner metav1.OwnerReference) error {
+	return kb.deleteDerivedResources(log, namespace, owner, schema.GroupResource{})
+}
+
+// deleteDerivedResources deletes the resources derived from the CCRN object except the ones of the kept
+// resource, which are served by all versions of the resource type
+func (kb *KubernetesBackend) deleteDerivedResources(log *logrus.Entry, namespace string, owner metav1.OwnerReference, keep schema.GroupResource) error {
+	selector := apis.OwnerSelector(owner)
+	var errs []error
+	for _, crdInfo := range apis.HighestVersions(kb.ListCRDs()) {
+		gvr := crdInfo.GroupVersionResource()
+		if gvr.GroupResource() == keep {
+			continue
+		}
+		resourceClient := kb.dynamicClient.Resource(gvr).Namespace(namespace)
+		list, err := resourceClient.List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list %s: %w", gvr.GroupResource(), err))
+			continue
+		}
+		for _, item := range list.Items {
+			log.Infof("Deleting resource %s/%s derived from %s", namespace, item.GetName(), owner.Name)
+			err := resourceClient.Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", gvr.GroupResource(), item.GetName(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// schemaViolations returns a schema violation per cause reported by the apiserver, so all of them are
+// reported at once. Errors without causes are returned as a single schema violation.
+func schemaViolations(err error) error {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil || len(status.Status().Details.Causes) == 0 {
+		return apis.Errorf(apis.ErrSchemaViolation, "failed to create resource: %w", err)
+	}
+
+	var errs []error
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Field == "" {
+			errs = append(errs, apis.Errorf(apis.ErrSchemaViolation, "failed to create resource: %s", cause.Message))
+			continue
+		}
+		errs = append(errs, apis.Errorf(apis.ErrSchemaViolation, "failed to create resource: %s: %s", cause.Field, cause.Message))
+	}
+	return errors.Join(errs...)
+}
+
+// GetURNTemplate retrieves the URN template from CRD annotations. The CRD is looked up by resource type
+// "<kind>.<group>" like in CCRNs in the cache, or by the name of the CRD in the cluster.
 func (kb *KubernetesBackend) GetURNTemplate(crdName, version string) (string, error) {
+	annotationKey := fmt.Sprintf("ccrn/%s.urn-template", version)
+	if crdInfo, exists := kb.snapshot.Load().crds[strings.ToLower(crdName+"/"+version)]; exists {
+		if crdInfo.URNFormat != "" {
+			return crdInfo.URNFormat, nil
+		}
+		return "", apis.Errorf(apis.ErrCRDNotFound, "URN Template %s not found in CRD %s", annotationKey, crdInfo.Name)
+	}
+
 	// Get the CRD
 	apiextClient := kb.apiextClient.ApiextensionsV1().CustomResourceDefinitions()
 	crd, err := apiextClient.Get(context.TODO(), crdName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", apis.Errorf(apis.ErrCRDNotFound, "failed to get CRD %s: %w", crdName, err)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to get CRD %s: %w", crdName, err)
 	}
 
-	annotationKey := fmt.Sprintf("ccrn/%s.urn-template", version)
-	if urnFormat, exists := crd.Annotations[annotationKey]; exists && urnFormat != "" {
+	if urnFormat := resolveURNTemplate(crd, version); urnFormat != "" {
 		return urnFormat, nil
 	}
 
-	return "", fmt.Errorf("URN Template %s not found in CRD %s", annotationKey, crdName)
+	return "", apis.Errorf(apis.ErrCRDNotFound, "URN Template %s not found in CRD %s", annotationKey, crdName)
 }
 
 // Refresh reloads CRD information from the cluster
@@ -167,57 +285,110 @@ func (kb *KubernetesBackend) Refresh() error {
 		return fmt.Errorf("failed to list CRDs: %w", err)
 	}
 
-	// Clear the current cache
-	kb.crdsMutex.Lock()
-	defer kb.crdsMutex.Unlock()
-
-	kb.ccrns = make(map[string]*apis.CRDInfo)
+	// Build a new cache, lookups keep using the current one until it is swapped in
+	next := newCRDSnapshot()
+	next.loaded = true
 
 	// Add relevant CRDs to the cache
 	for _, crd := range crdList.Items {
-		if strings.Contains(crd.Spec.Group, kb.ccrnGroup) {
+		if apis.InGroups(crd.Spec.Group, kb.ccrnGroup) {
+			next.crdObjects = append(next.crdObjects, &crd)
+			aliases := extractAliases(&crd)
+			registerAliases(kb.log, next.aliases, &crd, aliases)
 			for _, version := range crd.Spec.Versions {
 				if version.Served {
 					crdKey := kb.getCRDKeyFromCRD(&crd, version.Name)
 					kb.log.Infof("Found CCRN related CRD: %s", crdKey)
 
 					// Extract URN format if available
-					urnFormat := ""
-					annotationKey := fmt.Sprintf("ccrn/%s.urn-template", version.Name)
-					if format, exists := crd.Annotations[annotationKey]; exists {
-						urnFormat = format
-					}
+					urnFormat := resolveURNTemplate(&crd, version.Name)
 
 					// Store CRD info
-					kb.ccrns[crdKey] = &apis.CRDInfo{
-						Name:      crd.Name,
-						Plural:    crd.Spec.Names.Plural,
-						Singular:  crd.Spec.Names.Singular,
-						Group:     crd.Spec.Group,
-						Kind:      crd.Spec.Names.Kind,
-						Version:   version.Name,
-						Schema:    version.Schema.OpenAPIV3Schema,
-						URNFormat: urnFormat,
+					crdInfo := &apis.CRDInfo{
+						Name:       crd.Name,
+						Plural:     crd.Spec.Names.Plural,
+						Singular:   crd.Spec.Names.Singular,
+						Group:      crd.Spec.Group,
+						Kind:       crd.Spec.Names.Kind,
+						Version:    version.Name,
+						Schema:     version.Schema.OpenAPIV3Schema,
+						URNFormat:  urnFormat,
+						Deprecated: version.Deprecated,
 					}
+					if version.DeprecationWarning != nil {
+						crdInfo.DeprecationWarning = *version.DeprecationWarning
+					}
+					applyDeprecation(kb.log, &crd, crdKey, crdInfo)
+					applyWildcards(kb.log, &crd, crdKey, crdInfo)
+					crdInfo.FieldMapping = extractFieldMapping(&crd, version.Name)
+					crdInfo.ObjectMapping = extractObjectMapping(&crd, version.Name)
+					crdInfo.SourceResource = crd.Annotations[SourceResourceAnnotation]
+					crdInfo.Aliases = aliases
+					crdInfo.ExternalTemplates = extractExternalTemplates(&crd, version.Name)
+					crdInfo.LegacyURNFormats = extractLegacyURNTemplates(&crd, version.Name)
+					applyLegacyURNMapping(kb.log, &crd, crdKey, crdInfo)
+					logTemplateIssues(kb.log, crdKey, crdInfo)
+					next.crds[crdKey] = crdInfo
 				}
 			}
 		}
 	}
 
-	kb.log.Infof("Refreshed CRDs cache, found %d relevant CRDs", len(kb.ccrns))
+	slices.SortFunc(next.crdObjects, func(a, b *apiextensionsv1.CustomResourceDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// The initial load is no change of the CRD set
+	if previous := kb.snapshot.Swap(next); previous.loaded {
+		kb.schemaDiff.record(kb.log, previous, next)
+	}
+	kb.log.Infof("Refreshed CRDs cache, found %d relevant CRDs", len(next.crds))
 	return nil
 }
 
 // IsResourceTypeSupported checks if a resource type is supported
 func (kb *KubernetesBackend) IsResourceTypeSupported(ccrnVersion string) bool {
-	kb.crdsMutex.RLock()
-	defer kb.crdsMutex.RUnlock()
-
-	_, exists := kb.ccrns[ccrnVersion]
+	_, exists := kb.snapshot.Load().crds[ccrnVersion]
 
 	return exists
 }
 
+// ResolveAlias returns the full resource type of a short name declared via AliasesAnnotation
+func (kb *KubernetesBackend) ResolveAlias(alias string) (string, bool) {
+	return kb.snapshot.Load().aliases.ResolveAlias(alias)
+}
+
+// ResolveSource returns the highest version of the resource type whose source is the resource
+func (kb *KubernetesBackend) ResolveSource(resource schema.GroupResource) (*apis.CRDInfo, bool) {
+	crdInfo, found := kb.snapshot.Load().sourceIndex()[resource]
+	return crdInfo, found
+}
+
+// ResolveLatestVersion returns the highest served version of the resource type
+func (kb *KubernetesBackend) ResolveLatestVersion(resourceType string) (string, bool) {
+	return latestVersion(kb.snapshot.Load().crds, resourceType)
+}
+
+// ListCRDs returns the information of all cached CRD versions sorted by CCRN key
+func (kb *KubernetesBackend) ListCRDs() []*apis.CRDInfo {
+	return sortedCRDs(kb.snapshot.Load().crds)
+}
+
+// CustomResourceDefinitions returns the cached CRDs of the CCRN group sorted by name
+func (kb *KubernetesBackend) CustomResourceDefinitions() []*apiextensionsv1.CustomResourceDefinition {
+	return kb.snapshot.Load().crdObjects
+}
+
+// LastSchemaDiff returns how the last refresh changing the CRD set changed it, see apis.SchemaDiffSource
+func (kb *KubernetesBackend) LastSchemaDiff() *apis.SchemaDiff {
+	return kb.schemaDiff.load()
+}
+
+// Export returns a self-contained schema bundle of all cached CRD versions, see apis.ExportSchemas
+func (kb *KubernetesBackend) Export(format string) (map[string]any, error) {
+	return apis.ExportSchemas(kb.ListCRDs(), format)
+}
+
 // StartRefreshLoop starts a background goroutine to refresh CRDs periodically
 func (kb *KubernetesBackend) StartRefreshLoop(interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -0,0 +1,377 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+)
+
+// Finding is a problem found by the Linter
+type Finding struct {
+	Severity parser.Severity `json:"severity"`
+	Source   string          `json:"source"`            // File the CRD was read from
+	CRD      string          `json:"crd,omitempty"`     // Name of the CRD, empty if the document could not be parsed
+	Version  string          `json:"version,omitempty"` // Version of the CRD, empty if the finding concerns the whole CRD
+	Message  string          `json:"message"`
+}
+
+// String returns the finding in a human-readable form, e.g.
+// "crds/pod.yaml: pod.k8s.ccrn.example.com/v1: error: no URN template annotation ccrn/v1.urn-template"
+func (f Finding) String() string {
+	subject := f.CRD
+	if f.Version != "" {
+		subject += "/" + f.Version
+	}
+	if subject == "" {
+		return fmt.Sprintf("%s: %s: %s", f.Source, f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Source, subject, f.Severity, f.Message)
+}
+
+// Linter checks CCRN CRDs for problems the backends only report when loading them: missing or invalid
+// URN template annotations, placeholders that are not fields of the schema, non-structural schemas and
+// CCRN keys defined by more than one CRD
+type Linter struct {
+	backend  *FilesystemBackend
+	keys     map[string]string // CCRN key to the CRD and file that defined it first
+	crds     int
+	findings []Finding
+}
+
+// NewLinter creates a linter for the CRDs of the CCRN group, CRDs of other groups are ignored
+func NewLinter(log *logrus.Logger, ccrnGroup string) *Linter {
+	return &Linter{
+		backend: NewOfflineBackend(log, ccrnGroup),
+		keys:    make(map[string]string),
+	}
+}
+
+// LintFiles lints all CRDs in the files matching the patterns, see FindYAMLFiles
+func (l *Linter) LintFiles(patterns ...string) error {
+	files, err := FindYAMLFiles(patterns...)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		l.LintContent(file, content)
+	}
+	return nil
+}
+
+// FindYAMLFiles returns the files matching the glob patterns without duplicates. Directories are searched
+// recursively for YAML files, "**" matches any number of directories, e.g. "charts/**/*.yaml".
+// Every pattern must match at least one file.
+func FindYAMLFiles(patterns ...string) ([]string, error) {
+	include := (&FilesystemBackend{}).isYAMLFile
+	seen := make(map[string]bool)
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := expandPattern(pattern, include)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range matches {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+// LintContent lints all CRDs of a multi-document YAML file, other Kubernetes objects are ignored
+func (l *Linter) LintContent(source string, content []byte) {
+	documents, err := l.backend.splitYAMLDocuments(string(content))
+	if err != nil {
+		l.report(parser.SeverityError, source, "", "", "%v", err)
+		return
+	}
+	for i, document := range documents {
+		if l.backend.isEmptyDocument(document) {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal([]byte(document), crd); err != nil {
+			// Helm templates are no valid YAML, only report documents that are meant to be CRDs
+			if strings.Contains(document, CRDKind) {
+				l.report(parser.SeverityError, source, "", "", "document %d cannot be parsed: %v", i, err)
+			}
+			continue
+		}
+		if crd.Kind == CRDKind && l.backend.isCCRNRelevant(crd) {
+			l.LintCRD(source, crd)
+		}
+	}
+}
+
+// LintCRD lints a single CRD read from the source
+func (l *Linter) LintCRD(source string, crd *apiextensionsv1.CustomResourceDefinition) {
+	l.crds++
+	if err := l.backend.validateCRDStructure(crd); err != nil {
+		l.report(parser.SeverityError, source, crd.Name, "", "%v", err)
+		return
+	}
+
+	versions := make(map[string]bool, len(crd.Spec.Versions))
+	for _, version := range crd.Spec.Versions {
+		versions[version.Name] = true
+		if version.Served {
+			l.lintVersion(source, crd, version)
+		}
+	}
+
+	annotations := make([]string, 0, len(crd.Annotations))
+	for annotation := range crd.Annotations {
+		annotations = append(annotations, annotation)
+	}
+	sort.Strings(annotations)
+	for _, annotation := range annotations {
+		name, ok := strings.CutPrefix(annotation, "ccrn/")
+		if !ok {
+			continue
+		}
+		var version string
+		switch {
+		case strings.HasSuffix(name, ".urn-template-suffix"):
+			version = strings.TrimSuffix(name, ".urn-template-suffix")
+		case strings.HasSuffix(name, ".legacy-urn-templates"):
+			version = strings.TrimSuffix(name, ".legacy-urn-templates")
+		case strings.HasSuffix(name, ".legacy-urn-template-mapping"):
+			version = strings.TrimSuffix(name, ".legacy-urn-template-mapping")
+		case strings.HasSuffix(name, ".urn-template"):
+			version = strings.TrimSuffix(name, ".urn-template")
+		default:
+			continue
+		}
+		if !versions[version] {
+			l.report(parser.SeverityWarning, source, crd.Name, "", "annotation %s refers to the unknown version %s", annotation, version)
+		}
+	}
+}
+
+// lintVersion checks the schema and URN template of a served CRD version
+func (l *Linter) lintVersion(source string, crd *apiextensionsv1.CustomResourceDefinition, version apiextensionsv1.CustomResourceDefinitionVersion) {
+	key := l.backend.getCRDKey(crd.Spec.Group, version.Name, crd.Spec.Names.Kind)
+	definedBy := fmt.Sprintf("CRD %s in %s", crd.Name, source)
+	if first, exists := l.keys[key]; exists {
+		l.report(parser.SeverityError, source, crd.Name, version.Name, "duplicate CCRN key %s, already defined by %s", key, first)
+	} else {
+		l.keys[key] = definedBy
+	}
+
+	if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		l.report(parser.SeverityError, source, crd.Name, version.Name, "no OpenAPI schema")
+		return
+	}
+	for _, message := range structuralViolations(version.Schema.OpenAPIV3Schema) {
+		l.report(parser.SeverityError, source, crd.Name, version.Name, "schema is not structural: %s", message)
+	}
+
+	l.lintWildcards(source, crd, version)
+
+	suffixAnnotation := fmt.Sprintf(URNTemplateSuffixAnnotationFormat, version.Name)
+	if _, extended := crd.Annotations[suffixAnnotation]; extended {
+		switch {
+		case crd.Annotations[fmt.Sprintf(URNTemplateAnnotationFormat, version.Name)] != "":
+			l.report(parser.SeverityWarning, source, crd.Name, version.Name, "annotation %s has no effect, the version has a URN template of its own", suffixAnnotation)
+		case crd.Annotations[BaseURNTemplateAnnotation] == "":
+			l.report(parser.SeverityError, source, crd.Name, version.Name, "annotation %s extends no base template %s", suffixAnnotation, BaseURNTemplateAnnotation)
+		}
+	}
+
+	template := l.backend.extractURNTemplate(crd, version.Name)
+	if template == "" {
+		l.report(parser.SeverityError, source, crd.Name, version.Name, "no URN template annotation "+URNTemplateAnnotationFormat, version.Name)
+		return
+	}
+	crdInfo := &apis.CRDInfo{
+		Name:    crd.Name,
+		Group:   crd.Spec.Group,
+		Kind:    crd.Spec.Names.Kind,
+		Version: version.Name,
+		Schema:  version.Schema.OpenAPIV3Schema,
+	}
+	l.lintTemplate(source, crd.Name, version.Name, fmt.Sprintf(URNTemplateAnnotationFormat, version.Name), template, crdInfo)
+	mapping := l.lintLegacyURNMapping(source, crd, version.Name, crdInfo)
+	for _, legacy := range extractLegacyURNTemplates(crd, version.Name) {
+		// Legacy templates are checked with the fields they are parsed into
+		l.lintTemplate(source, crd.Name, version.Name, fmt.Sprintf(LegacyURNTemplatesAnnotationFormat, version.Name)+" "+legacy, mapping.ApplyTemplate(legacy), crdInfo)
+	}
+}
+
+// lintLegacyURNMapping checks and returns the renamed fields of the legacy URN templates, see
+// LegacyURNMappingAnnotationFormat. The current fields must be declared in the schema.
+func (l *Linter) lintLegacyURNMapping(source string, crd *apiextensionsv1.CustomResourceDefinition, version string, crdInfo *apis.CRDInfo) apis.TemplateMapping {
+	annotation := fmt.Sprintf(LegacyURNMappingAnnotationFormat, version)
+	value, exists := crd.Annotations[annotation]
+	if !exists {
+		return nil
+	}
+	mapping, err := apis.ParseTemplateMapping(value)
+	if err != nil {
+		l.report(parser.SeverityError, source, crd.Name, version, "%s: %v", annotation, err)
+		return nil
+	}
+	if _, legacy := crd.Annotations[fmt.Sprintf(LegacyURNTemplatesAnnotationFormat, version)]; !legacy {
+		l.report(parser.SeverityWarning, source, crd.Name, version, "%s has no effect without legacy URN templates", annotation)
+	}
+	for _, field := range slices.Sorted(maps.Values(mapping)) {
+		if !crdInfo.HasField(field) {
+			l.report(parser.SeverityError, source, crd.Name, version, "%s: unknown field %s", annotation, field)
+		}
+	}
+	return mapping
+}
+
+// lintTemplate reports the issues of a URN template of a CRD version, prefixed with where it is declared
+func (l *Linter) lintTemplate(source, crd, version, declaredBy, template string, crdInfo *apis.CRDInfo) {
+	for _, issue := range parser.ValidateTemplate(template, crdInfo) {
+		message := issue.Message
+		if issue.Segment > 0 {
+			message = fmt.Sprintf("segment %d: %s", issue.Segment, message)
+		}
+		l.report(issue.Severity, source, crd, version, "%s: %s", declaredBy, message)
+	}
+}
+
+// lintWildcards checks the declared wildcards of a CRD version, see WildcardsAnnotationFormat. Wildcards
+// matching any value whose schema rejects "*" are reported, the Kubernetes backend cannot create them.
+func (l *Linter) lintWildcards(source string, crd *apiextensionsv1.CustomResourceDefinition, version apiextensionsv1.CustomResourceDefinitionVersion) {
+	annotation, exists := crd.Annotations[fmt.Sprintf(WildcardsAnnotationFormat, version.Name)]
+	if !exists {
+		return
+	}
+	wildcards, err := apis.ParseWildcards(annotation)
+	if err != nil {
+		l.report(parser.SeverityError, source, crd.Name, version.Name, WildcardsAnnotationFormat+": %v", version.Name, err)
+		return
+	}
+	crdInfo := &apis.CRDInfo{Schema: version.Schema.OpenAPIV3Schema}
+	for _, field := range slices.Sorted(maps.Keys(wildcards)) {
+		if !crdInfo.HasField(field) {
+			l.report(parser.SeverityError, source, crd.Name, version.Name, WildcardsAnnotationFormat+": unknown field %s", version.Name, field)
+			continue
+		}
+		if wildcards[field] != apis.WildcardAny {
+			continue
+		}
+		wildcard := &apis.ParsedResource{Fields: map[string]string{field: apis.Wildcard}}
+		if err := apis.CheckFieldConstraints(wildcard, crdInfo); err != nil {
+			l.report(parser.SeverityWarning, source, crd.Name, version.Name, WildcardsAnnotationFormat+": the schema of %s rejects wildcards, the Kubernetes backend cannot create them", version.Name, field)
+		}
+	}
+}
+
+// Findings returns the findings of all linted CRDs, errors first
+func (l *Linter) Findings() []Finding {
+	findings := append([]Finding{}, l.findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == parser.SeverityError && findings[j].Severity != parser.SeverityError
+	})
+	return findings
+}
+
+// LintedCRDs returns the number of CCRN CRDs linted
+func (l *Linter) LintedCRDs() int {
+	return l.crds
+}
+
+func (l *Linter) report(severity parser.Severity, source, crd, version, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		Severity: severity,
+		Source:   source,
+		CRD:      crd,
+		Version:  version,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// structuralViolations lists why the schema is not structural as required by the apiserver
+func structuralViolations(schema *apiextensionsv1.JSONSchemaProps) []string {
+	props := apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, &props, nil); err != nil {
+		return []string{err.Error()}
+	}
+	structural, err := structuralschema.NewStructural(&props)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var messages []string
+	for _, violation := range structuralschema.ValidateStructural(nil, structural) {
+		messages = append(messages, violation.Error())
+	}
+	return messages
+}
+
+// expandPattern returns the files matching a glob pattern, where "**" matches any number of directories
+// and directories match all files accepted by include below them
+func expandPattern(pattern string, include func(string) bool) ([]string, error) {
+	var matches []string
+	if root, rest, found := strings.Cut(filepath.ToSlash(pattern), "**"); found {
+		root = filepath.Clean(filepath.FromSlash(root))
+		rest = strings.TrimPrefix(rest, "/")
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !include(path) {
+				return err
+			}
+			if rest != "" {
+				if ok, err := filepath.Match(rest, entry.Name()); err != nil || !ok {
+					return err
+				}
+			}
+			matches = append(matches, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", pattern, err)
+		}
+	} else {
+		globbed, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve glob pattern %s: %w", pattern, err)
+		}
+		for _, path := range globbed {
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				matches = append(matches, path)
+				continue
+			}
+			nested, err := expandPattern(filepath.Join(path, "**"), include)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, nested...)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files found matching pattern: %s", pattern)
+	}
+	return matches, nil
+}
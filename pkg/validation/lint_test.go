@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// lintCRD returns a CCRN CRD with the annotations and the schema properties of the name field
+func lintCRD(annotations, nameSchema string) string {
+	return fmt.Sprintf(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: volume.storage.tr.ccrn.example.com
+  annotations:
+    ccrn/aliases: vol
+%s
+spec:
+  group: storage.tr.ccrn.example.com
+  names:
+    kind: volume
+    plural: volumes
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          required: ["ccrn", "name"]
+          properties:
+            ccrn:
+              type: string
+            name: %s
+`, annotations, nameSchema)
+}
+
+var _ = Describe("Linter", func() {
+	var linter *validation.Linter
+
+	BeforeEach(func() {
+		linter = validation.NewLinter(logrus.New(), "ccrn.example.com")
+	})
+
+	It("accepts the CRDs of the test data", func() {
+		// Act
+		err := linter.LintFiles(filepath.Join("testdata", "testpod_crd.yaml"), filepath.Join("testdata", "minimal_crd.yaml"))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linter.LintedCRDs()).To(Equal(2))
+		Expect(parser.HasErrors(issues(linter.Findings()))).To(BeFalse())
+	})
+
+	It("checks legacy URN templates with the fields they are parsed into", func() {
+		// Act
+		err := linter.LintFiles(filepath.Join("testdata", "legacy_crd.yaml"))
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linter.Findings()).To(BeEmpty())
+	})
+
+	DescribeTable("reports problems of CRDs",
+		func(annotations, nameSchema, expected string) {
+			// Act
+			linter.LintContent("volume.yaml", []byte(lintCRD(annotations, nameSchema)))
+			// Assert
+			Expect(linter.Findings()).To(ContainElement(HaveField("String()", expected)))
+		},
+		Entry("with a missing URN template", "", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: no URN template annotation ccrn/v1.urn-template"),
+		Entry("with a malformed URN template", `    ccrn/v1.urn-template: "urn:ccrn:<ccrn>/<name"`, "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.urn-template: invalid URN template urn:ccrn:<ccrn>/<name: unbalanced placeholder brackets in <name"),
+		Entry("with a placeholder missing in the schema", `    ccrn/v1.urn-template: "urn:ccrn:<ccrn>/<zone>/<name>"`, "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.urn-template: segment 2: placeholder <zone> is not a field of volume"),
+		Entry("with a non-structural schema", `    ccrn/v1.urn-template: "urn:ccrn:<ccrn>/<name>"`, "{}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: schema is not structural: properties[name].type: Required value: must not be empty for specified object fields"),
+		Entry("with a template of an unknown version", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1beta1.urn-template: \"urn:ccrn:<ccrn>/<name>\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com: warning: annotation ccrn/v1beta1.urn-template refers to the unknown version v1beta1"),
+		Entry("with a template suffix of an unknown version", "    ccrn/urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v2.urn-template-suffix: \"/<name>\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com: warning: annotation ccrn/v2.urn-template-suffix refers to the unknown version v2"),
+		Entry("with a template suffix without base template", `    ccrn/v1.urn-template-suffix: "/<name>"`, "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: annotation ccrn/v1.urn-template-suffix extends no base template ccrn/urn-template"),
+		Entry("with a template suffix overridden by the template of the version", "    ccrn/urn-template: \"urn:ccrn:<ccrn>\"\n    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.urn-template-suffix: \"/<name>\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: warning: annotation ccrn/v1.urn-template-suffix has no effect, the version has a URN template of its own"),
+		Entry("with a placeholder of the template suffix missing in the schema", "    ccrn/urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.urn-template-suffix: \"/<zone>\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.urn-template: segment 3: placeholder <zone> is not a field of volume"),
+		Entry("with a legacy template of a placeholder missing in the schema", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.legacy-urn-templates: |\n      urn:ccrn:<ccrn>/<zone>/<name>", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.legacy-urn-templates urn:ccrn:<ccrn>/<zone>/<name>: segment 2: placeholder <zone> is not a field of volume"),
+		Entry("with an invalid legacy template mapping", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.legacy-urn-templates: \"urn:ccrn:<ccrn>/<id>\"\n    ccrn/v1.legacy-urn-template-mapping: \"id\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.legacy-urn-template-mapping: invalid template mapping \"id\", use <former field>=<current field>"),
+		Entry("with a legacy template mapping to an unknown field", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.legacy-urn-templates: \"urn:ccrn:<ccrn>/<id>\"\n    ccrn/v1.legacy-urn-template-mapping: \"id=zone\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.legacy-urn-template-mapping: unknown field zone"),
+		Entry("with a legacy template mapping without legacy templates", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.legacy-urn-template-mapping: \"id=name\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: warning: ccrn/v1.legacy-urn-template-mapping has no effect without legacy URN templates"),
+		Entry("with an invalid wildcard mode", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.wildcards: \"name=all\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.wildcards: invalid wildcard mode \"all\" of field name, use any or literal"),
+		Entry("with a wildcard of an unknown field", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.wildcards: \"zone=any\"", "{type: string}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: error: ccrn/v1.wildcards: unknown field zone"),
+		Entry("with a wildcard rejected by the schema", "    ccrn/v1.urn-template: \"urn:ccrn:<ccrn>/<name>\"\n    ccrn/v1.wildcards: \"name=any\"", "{type: string, pattern: \"^[a-z]+$\"}",
+			"volume.yaml: volume.storage.tr.ccrn.example.com/v1: warning: ccrn/v1.wildcards: the schema of name rejects wildcards, the Kubernetes backend cannot create them"),
+	)
+
+	It("reports CCRN keys defined twice", func() {
+		// Arrange
+		crd := lintCRD(`    ccrn/v1.urn-template: "urn:ccrn:<ccrn>/<name>"`, "{type: string}")
+		// Act
+		linter.LintContent("a.yaml", []byte(crd))
+		linter.LintContent("b.yaml", []byte(crd))
+		// Assert
+		Expect(linter.Findings()).To(ConsistOf(HaveField("String()",
+			"b.yaml: volume.storage.tr.ccrn.example.com/v1: error: duplicate CCRN key volume.storage.tr.ccrn.example.com/v1, already defined by CRD volume.storage.tr.ccrn.example.com in a.yaml")))
+	})
+
+	It("ignores other objects and Helm templates", func() {
+		// Act
+		linter.LintContent("templates.yaml", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n---\napiVersion: v1\nkind: Secret\n"))
+		// Assert
+		Expect(linter.Findings()).To(BeEmpty())
+		Expect(linter.LintedCRDs()).To(BeZero())
+	})
+
+	It("reports CRDs that cannot be parsed", func() {
+		// Act
+		linter.LintContent("broken.yaml", []byte("kind: CustomResourceDefinition\nmetadata: [\n"))
+		// Assert
+		Expect(linter.Findings()).To(ConsistOf(HaveField("Severity", parser.SeverityError)))
+	})
+})
+
+// issues converts findings to template issues to use parser.HasErrors
+func issues(findings []validation.Finding) []parser.Issue {
+	result := make([]parser.Issue, 0, len(findings))
+	for _, finding := range findings {
+		result = append(result, parser.Issue{Severity: finding.Severity, Message: finding.Message})
+	}
+	return result
+}
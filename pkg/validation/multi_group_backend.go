@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// MultiGroupBackend serves the CRDs of several CCRN groups, e.g. a legacy and a new group during a migration.
+// Each group has its own backend and cache, requests are routed by the group of the resource type, see
+// apis.MatchGroup.
+type MultiGroupBackend struct {
+	groups   []string
+	backends map[string]apis.ValidationBackend
+}
+
+var (
+	_ apis.ValidationBackend      = &MultiGroupBackend{}
+	_ apis.CRDLister              = &MultiGroupBackend{}
+	_ apis.CRDSource              = &MultiGroupBackend{}
+	_ apis.AliasResolver          = &MultiGroupBackend{}
+	_ apis.VersionResolver        = &MultiGroupBackend{}
+	_ apis.SourceResolver         = &MultiGroupBackend{}
+	_ apis.SchemaExporter         = &MultiGroupBackend{}
+	_ apis.SchemaDiffSource       = &MultiGroupBackend{}
+	_ apis.DerivedResourceManager = &MultiGroupBackend{}
+	_ apis.DryRunValidator        = &MultiGroupBackend{}
+)
+
+// NewMultiGroupBackend creates the backends of the CCRN groups with newBackend. A single group gets its
+// backend returned as is, no groups the backend of the empty group, which matches all API groups.
+func NewMultiGroupBackend(groups []string, newBackend func(ccrnGroup string) (apis.ValidationBackend, error)) (apis.ValidationBackend, error) {
+	switch len(groups) {
+	case 0:
+		return newBackend("")
+	case 1:
+		return newBackend(groups[0])
+	}
+	backends := make(map[string]apis.ValidationBackend, len(groups))
+	for _, group := range groups {
+		backend, err := newBackend(group)
+		if err != nil {
+			return nil, err
+		}
+		backends[group] = backend
+	}
+	return &MultiGroupBackend{groups: groups, backends: backends}, nil
+}
+
+// Backend returns the backend of the CCRN group
+func (mb *MultiGroupBackend) Backend(ccrnGroup string) (apis.ValidationBackend, bool) {
+	backend, ok := mb.backends[strings.ToLower(ccrnGroup)]
+	return backend, ok
+}
+
+// route returns the backend of the resource type "<kind>.<group>[/<version>]"
+func (mb *MultiGroupBackend) route(resourceType string) (apis.ValidationBackend, error) {
+	name, _, _ := strings.Cut(resourceType, "/")
+	_, apiGroup, _ := strings.Cut(name, ".")
+	group, found := apis.MatchGroup(apiGroup, mb.groups)
+	if !found {
+		return nil, apis.Errorf(apis.ErrCRDNotFound, "resource type %s belongs to none of the CCRN groups %s",
+			resourceType, strings.Join(mb.groups, ", "))
+	}
+	return mb.backends[group], nil
+}
+
+// GetCRD retrieves CRD information from the backend of the group
+func (mb *MultiGroupBackend) GetCRD(ccrnVersion string) (*apis.CRDInfo, error) {
+	backend, err := mb.route(ccrnVersion)
+	if err != nil {
+		return nil, err
+	}
+	return backend.GetCRD(ccrnVersion)
+}
+
+// ValidateResource validates the resource with the backend of its group
+func (mb *MultiGroupBackend) ValidateResource(namespace string, parsedCCRN *apis.ParsedResource) error {
+	backend, err := mb.route(parsedCCRN.CCRNKey())
+	if err != nil {
+		return err
+	}
+	return backend.ValidateResource(namespace, parsedCCRN)
+}
+
+// ValidateOwnedResource validates the resource with the backend of its group, owning the created resources
+// if the backend derives resources, see apis.DerivedResourceManager
+func (mb *MultiGroupBackend) ValidateOwnedResource(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource, owner metav1.OwnerReference) error {
+	backend, err := mb.route(parsedCCRN.CCRNKey())
+	if err != nil {
+		return err
+	}
+	if manager, ok := backend.(apis.DerivedResourceManager); ok {
+		return manager.ValidateOwnedResource(log, namespace, parsedCCRN, owner)
+	}
+	return backend.ValidateResource(namespace, parsedCCRN)
+}
+
+// ValidateResourceDryRun validates the resource with the backend of its group without persisting anything,
+// backends without side effects validate it as usual, see apis.DryRunValidator
+func (mb *MultiGroupBackend) ValidateResourceDryRun(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource) error {
+	backend, err := mb.route(parsedCCRN.CCRNKey())
+	if err != nil {
+		return err
+	}
+	if validator, ok := backend.(apis.DryRunValidator); ok {
+		return validator.ValidateResourceDryRun(log, namespace, parsedCCRN)
+	}
+	return backend.ValidateResource(namespace, parsedCCRN)
+}
+
+// DeleteDerivedResources deletes the resources derived from the CCRN object by the backends of all groups
+func (mb *MultiGroupBackend) DeleteDerivedResources(log *logrus.Entry, namespace string, owner metav1.OwnerReference) error {
+	var errs []error
+	for _, group := range mb.groups {
+		if manager, ok := mb.backends[group].(apis.DerivedResourceManager); ok {
+			errs = append(errs, manager.DeleteDerivedResources(log, namespace, owner))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetURNTemplate retrieves the URN template from the backend of the group
+func (mb *MultiGroupBackend) GetURNTemplate(ccrnName, ccrnVersion string) (string, error) {
+	backend, err := mb.route(ccrnName)
+	if err != nil {
+		return "", err
+	}
+	return backend.GetURNTemplate(ccrnName, ccrnVersion)
+}
+
+// Refresh reloads the CRD information of all groups
+func (mb *MultiGroupBackend) Refresh() error {
+	var errs []error
+	for _, group := range mb.groups {
+		errs = append(errs, mb.backends[group].Refresh())
+	}
+	return errors.Join(errs...)
+}
+
+// IsResourceTypeSupported checks if the backend of the group supports the resource type
+func (mb *MultiGroupBackend) IsResourceTypeSupported(ccrnVersion string) bool {
+	backend, err := mb.route(ccrnVersion)
+	return err == nil && backend.IsResourceTypeSupported(ccrnVersion)
+}
+
+// ResolveAlias returns the resource type of the alias from the first group declaring it
+func (mb *MultiGroupBackend) ResolveAlias(alias string) (string, bool) {
+	for _, group := range mb.groups {
+		if resolver, ok := mb.backends[group].(apis.AliasResolver); ok {
+			if resourceType, found := resolver.ResolveAlias(alias); found {
+				return resourceType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ResolveSource returns the resource type of the source resource from the first group declaring one
+func (mb *MultiGroupBackend) ResolveSource(resource schema.GroupResource) (*apis.CRDInfo, bool) {
+	for _, group := range mb.groups {
+		if resolver, ok := mb.backends[group].(apis.SourceResolver); ok {
+			if crdInfo, found := resolver.ResolveSource(resource); found {
+				return crdInfo, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ResolveLatestVersion returns the highest served version of the resource type from the backend of its group
+func (mb *MultiGroupBackend) ResolveLatestVersion(resourceType string) (string, bool) {
+	backend, err := mb.route(resourceType)
+	if err != nil {
+		return "", false
+	}
+	if resolver, ok := backend.(apis.VersionResolver); ok {
+		return resolver.ResolveLatestVersion(resourceType)
+	}
+	return "", false
+}
+
+// ListCRDs returns the CRD versions of all groups sorted by CCRN key
+func (mb *MultiGroupBackend) ListCRDs() []*apis.CRDInfo {
+	crds := map[string]*apis.CRDInfo{}
+	for _, group := range mb.groups {
+		lister, ok := mb.backends[group].(apis.CRDLister)
+		if !ok {
+			continue
+		}
+		for _, crdInfo := range lister.ListCRDs() {
+			// Nested groups cache the same CRDs, the routed group owns them
+			if owner, _ := apis.MatchGroup(crdInfo.Group, mb.groups); owner == group {
+				crds[crdInfo.CCRNKey()] = crdInfo
+			}
+		}
+	}
+	return sortedCRDs(crds)
+}
+
+// CustomResourceDefinitions returns the CRDs of all groups sorted by name
+func (mb *MultiGroupBackend) CustomResourceDefinitions() []*apiextensionsv1.CustomResourceDefinition {
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{}
+	for _, group := range mb.groups {
+		source, ok := mb.backends[group].(apis.CRDSource)
+		if !ok {
+			continue
+		}
+		for _, crd := range source.CustomResourceDefinitions() {
+			// Nested groups cache the same CRDs, the routed group owns them
+			if owner, _ := apis.MatchGroup(crd.Spec.Group, mb.groups); owner == group {
+				crds[crd.Name] = crd
+			}
+		}
+	}
+	names := slices.Sorted(maps.Keys(crds))
+	sorted := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, crds[name])
+	}
+	return sorted
+}
+
+// LastSchemaDiff merges the diffs of the last refreshes changing the CRD sets of the groups, nil if none did.
+// Nested groups cache the same CRDs, so their changes are reported once.
+func (mb *MultiGroupBackend) LastSchemaDiff() *apis.SchemaDiff {
+	var merged *apis.SchemaDiff
+	added, removed, changed := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, group := range mb.groups {
+		source, ok := mb.backends[group].(apis.SchemaDiffSource)
+		if !ok {
+			continue
+		}
+		diff := source.LastSchemaDiff()
+		if diff == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &apis.SchemaDiff{}
+		}
+		if diff.Time.After(merged.Time) {
+			merged.Time = diff.Time
+		}
+		for _, key := range diff.Added {
+			if !added[key] {
+				added[key] = true
+				merged.Added = append(merged.Added, key)
+			}
+		}
+		for _, key := range diff.Removed {
+			if !removed[key] {
+				removed[key] = true
+				merged.Removed = append(merged.Removed, key)
+			}
+		}
+		for _, change := range diff.Changed {
+			if !changed[change.Type] {
+				changed[change.Type] = true
+				merged.Changed = append(merged.Changed, change)
+			}
+		}
+	}
+	if merged != nil {
+		slices.Sort(merged.Added)
+		slices.Sort(merged.Removed)
+		slices.SortFunc(merged.Changed, func(a, b apis.TypeChange) int {
+			return strings.Compare(a.Type, b.Type)
+		})
+	}
+	return merged
+}
+
+// Export returns a self-contained schema bundle of the CRD versions of all groups, see apis.ExportSchemas
+func (mb *MultiGroupBackend) Export(format string) (map[string]any, error) {
+	return apis.ExportSchemas(mb.ListCRDs(), format)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+var _ = Describe("MultiGroupBackend", func() {
+	var backend apis.ValidationBackend
+
+	BeforeEach(func() {
+		// Serve the minimal CRD under the new and a legacy group
+		content, err := os.ReadFile(filepath.Join("testdata", "minimal_crd.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "new.yaml"), content, 0o600)).To(Succeed())
+		legacy := strings.ReplaceAll(string(content), "ccrn.example.com", "ccrn.legacy.example.com")
+		Expect(os.WriteFile(filepath.Join(dir, "legacy.yaml"), []byte(legacy), 0o600)).To(Succeed())
+
+		backend, err = validation.NewBackendFromURL(logrus.New(), "file://"+dir, "ccrn.example.com, ccrn.legacy.example.com")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("keeps a cache per group", func() {
+		// Arrange
+		multi, ok := backend.(*validation.MultiGroupBackend)
+		Expect(ok).To(BeTrue())
+		// Act
+		legacy, found := multi.Backend("ccrn.legacy.example.com")
+		// Assert
+		Expect(found).To(BeTrue())
+		Expect(legacy.IsResourceTypeSupported("testresource.tr.ccrn.legacy.example.com/v1")).To(BeTrue())
+		Expect(legacy.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeFalse())
+	})
+
+	It("routes by the group of the resource type", func() {
+		// Act
+		crdInfo, err := backend.GetCRD("testresource.tr.ccrn.legacy.example.com/v1")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(crdInfo.Group).To(Equal("tr.ccrn.legacy.example.com"))
+		Expect(backend.IsResourceTypeSupported("testresource.tr.ccrn.example.com/v1")).To(BeTrue())
+		_, err = backend.GetCRD("testresource.tr.ccrn.example.org/v1")
+		Expect(errors.Is(err, apis.ErrCRDNotFound)).To(BeTrue())
+	})
+
+	It("lists the CRDs of all groups", func() {
+		// Act
+		crds := backend.(apis.CRDLister).ListCRDs()
+		// Assert
+		keys := make([]string, 0, len(crds))
+		for _, crdInfo := range crds {
+			keys = append(keys, crdInfo.CCRNKey())
+		}
+		Expect(keys).To(Equal([]string{"testresource.tr.ccrn.example.com/v1", "testresource.tr.ccrn.legacy.example.com/v1"}))
+	})
+
+	It("validates CCRNs of both groups", func() {
+		// Arrange
+		validator := validation.NewCCRNValidator(backend)
+		// Act
+		result, err := validator.ValidateCCRN("ccrn=testresource.tr.ccrn.legacy.example.com/v1, name=example")
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.Valid).To(BeTrue())
+	})
+	It("validates owned resources with backends that derive no resources", func() {
+		// Arrange
+		manager := backend.(apis.DerivedResourceManager)
+		parsed := &apis.ParsedResource{Fields: map[string]string{"ccrn": "testresource.tr.ccrn.legacy.example.com/v1", "name": "example"}}
+		owner := metav1.OwnerReference{APIVersion: "validate.ccrn.legacy.example.com/v1", Kind: "CCRN", Name: "example"}
+		// Act & Assert
+		Expect(manager.ValidateOwnedResource(logrus.NewEntry(logrus.New()), "default", parsed, owner)).To(Succeed())
+		Expect(manager.DeleteDerivedResources(logrus.NewEntry(logrus.New()), "default", owner)).To(Succeed())
+	})
+})
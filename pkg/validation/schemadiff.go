@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+var schemaChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "ccrn_schema_changes_total",
+	Help: "Number of resource types added, removed or changed by refreshes of the CRD set, by change.",
+}, []string{"change"})
+
+// Collector returns the collector of the schema change metrics, the webhook registers it next to its own
+func Collector() prometheus.Collector {
+	return schemaChanges
+}
+
+// schemaDiffRecorder remembers how the last refresh changed the CRD set of a backend, see apis.SchemaDiffSource
+type schemaDiffRecorder struct {
+	last atomic.Pointer[apis.SchemaDiff]
+}
+
+// record compares the resource types of the snapshots and logs, counts and remembers the diff if they differ
+func (r *schemaDiffRecorder) record(log *logrus.Logger, previous, next *crdSnapshot) {
+	diff := apis.DiffSchemas(sortedCRDs(previous.crds), sortedCRDs(next.crds))
+	if diff.Empty() {
+		return
+	}
+	diff.Time = time.Now()
+	r.last.Store(diff)
+
+	schemaChanges.WithLabelValues("added").Add(float64(len(diff.Added)))
+	schemaChanges.WithLabelValues("removed").Add(float64(len(diff.Removed)))
+	schemaChanges.WithLabelValues("changed").Add(float64(len(diff.Changed)))
+	log.WithFields(logrus.Fields{
+		"added":   len(diff.Added),
+		"removed": len(diff.Removed),
+		"changed": len(diff.Changed),
+	}).Infof("Refresh changed the CRD set: %s", diff)
+}
+
+// load returns the diff of the last refresh that changed the CRD set, nil if none did
+func (r *schemaDiffRecorder) load() *apis.SchemaDiff {
+	return r.last.Load()
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"maps"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// crdSnapshot is an immutable view of the cached CRDs of a backend. Readers load the current snapshot of
+// the backend without locking, writers copy it, change the copy and swap it in (copy-on-write), so
+// admission requests never wait for a refresh.
+type crdSnapshot struct {
+	crds       map[string]*apis.CRDInfo                               // CRD information keyed by "<kind>.<group>/<version>"
+	crdsByFile map[string][]*apiextensionsv1.CustomResourceDefinition // CRDs by source file, only used by FilesystemBackend
+	validators map[string]*validation.SchemaValidator                 // Schema validators, only used by FilesystemBackend
+	celRules   map[string]*celRules                                   // x-kubernetes-validations, only used by FilesystemBackend
+	aliases    apis.Aliases                                           // Short names of the cached resource types
+	crdObjects []*apiextensionsv1.CustomResourceDefinition            // CRDs of the cached versions sorted by name, only used by KubernetesBackend
+	loaded     bool                                                   // Whether the CRDs were listed from the cluster, only used by KubernetesBackend
+
+	sourcesOnce sync.Once
+	sources     map[schema.GroupResource]*apis.CRDInfo // Resource types by source resource, see sourceIndex
+}
+
+// newCRDSnapshot returns an empty snapshot
+func newCRDSnapshot() *crdSnapshot {
+	return &crdSnapshot{
+		crds:       make(map[string]*apis.CRDInfo),
+		crdsByFile: make(map[string][]*apiextensionsv1.CustomResourceDefinition),
+		validators: make(map[string]*validation.SchemaValidator),
+		celRules:   make(map[string]*celRules),
+		aliases:    make(apis.Aliases),
+	}
+}
+
+// sourceIndex returns the resource types by their source resource, see apis.SourceIndex. It is built on first
+// use, so admission requests do not scan the CRDs of the snapshot again.
+func (s *crdSnapshot) sourceIndex() map[schema.GroupResource]*apis.CRDInfo {
+	s.sourcesOnce.Do(func() {
+		s.sources = apis.SourceIndex(sortedCRDs(s.crds))
+	})
+	return s.sources
+}
+
+// clone returns a copy of the snapshot for a writer, the cached values are shared as they are never modified
+func (s *crdSnapshot) clone() *crdSnapshot {
+	return &crdSnapshot{
+		crds:       maps.Clone(s.crds),
+		crdsByFile: maps.Clone(s.crdsByFile),
+		validators: maps.Clone(s.validators),
+		celRules:   maps.Clone(s.celRules),
+		aliases:    maps.Clone(s.aliases),
+		crdObjects: s.crdObjects,
+		loaded:     s.loaded,
+	}
+}
@@ -8,8 +8,14 @@ package validation
 
 import "C"
 import (
+	"context"
+	"fmt"
+
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // CCRNValidator provides CCRN validation using a pluggable backend
@@ -18,34 +24,49 @@ type CCRNValidator struct {
 	parser  *parser.ResourceParser
 }
 
-// NewCCRNValidator creates a new CCRN validator with the specified backend
-func NewCCRNValidator(backend apis.ValidationBackend) *CCRNValidator {
+// NewCCRNValidator creates a new CCRN validator with the specified backend, the options configure its parser
+func NewCCRNValidator(backend apis.ValidationBackend, opts ...parser.Option) *CCRNValidator {
 	return &CCRNValidator{
 		backend: backend,
-		parser:  parser.NewResourceParser(nil, backend),
+		parser:  parser.NewResourceParser(nil, backend, opts...),
 	}
 }
 
-// ValidateCCRN validates a CCRN string
+// ValidateCCRN validates a CCRN string. Every violation found is reported in the Errors of the result,
+// the returned error joins them. Valid CCRNs may carry Warnings, see apis.Warnings.
 func (v *CCRNValidator) ValidateCCRN(ccrnStr string) (*apis.ValidationResult, error) {
-	parsed, err := v.parser.Parse(ccrnStr, parser.DEFAULT_URN_TEMPLATE)
+	return v.ValidateCCRNContext(context.Background(), ccrnStr)
+}
+
+// ValidateCCRNContext is like ValidateCCRN but records trace spans for the parser and
+// backend calls as children of the given context
+func (v *CCRNValidator) ValidateCCRNContext(ctx context.Context, ccrnStr string) (*apis.ValidationResult, error) {
+	ctx, span := tracing.StartSpan(ctx, "validator.ValidateCCRN")
+	result, err := v.validateCCRN(ctx, ccrnStr)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (v *CCRNValidator) validateCCRN(ctx context.Context, ccrnStr string) (*apis.ValidationResult, error) {
+	parsed, err := v.parser.ParseContext(ctx, ccrnStr, parser.DEFAULT_URN_TEMPLATE)
 	if err != nil {
 		return &apis.ValidationResult{
 			Valid:  false,
-			Errors: []string{err.Error()},
+			Errors: apis.ErrorMessages(err),
 		}, err
 	}
 
 	if parsed.Format == "URN" {
-		info, err := v.backend.GetCRD(parsed.CCRNKey())
+		_, span := tracing.StartSpan(ctx, "backend.GetCRD", attribute.String("ccrn.key", parsed.CCRNKey()))
+		_, err := v.backend.GetCRD(parsed.CCRNKey())
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return &apis.ValidationResult{
 				Valid:      false,
 				ParsedCCRN: parsed,
-				Errors:     []string{"A CCRN definition for %s could not be retrieved: %s", parsed.CCRNKey(), err.Error()},
+				Errors:     []string{fmt.Sprintf("A CCRN definition for %s could not be retrieved: %s", parsed.CCRNKey(), err.Error())},
 			}, err
 		}
-		parsed, err = v.parser.Parse(ccrnStr, info.URNFormat)
 	}
 
 	if parsed != nil && !v.backend.IsResourceTypeSupported(parsed.CCRNKey()) {
@@ -56,17 +77,33 @@ func (v *CCRNValidator) ValidateCCRN(ccrnStr string) (*apis.ValidationResult, er
 		}, nil
 	}
 
+	_, span := tracing.StartSpan(ctx, "backend.ValidateResource", attribute.String("ccrn.key", parsed.CCRNKey()))
 	err = v.backend.ValidateResource("", parsed)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return &apis.ValidationResult{
 			Valid:      false,
 			ParsedCCRN: parsed,
-			Errors:     []string{err.Error()},
+			Errors:     apis.ErrorMessages(err),
 		}, err
 	}
 
+	maxLength := v.parser.MaxLength()
+	if maxLength <= 0 {
+		maxLength = apis.DefaultMaxLength
+	}
+	crdInfo, err := v.backend.GetCRD(parsed.CCRNKey())
+	if err != nil {
+		crdInfo = nil
+	}
+	warnings := apis.Warnings(ccrnStr, parsed, crdInfo, maxLength)
+	if crdInfo != nil && parsed.Format == "URN" && parsed.UrnTemplate != "" && parsed.UrnTemplate != crdInfo.URNFormat {
+		warnings = append(warnings, fmt.Sprintf("URN follows the legacy template %s, the current template of %s is %s",
+			parsed.UrnTemplate, crdInfo.CCRNKey(), crdInfo.URNFormat))
+	}
 	return &apis.ValidationResult{
 		Valid:      true,
 		ParsedCCRN: parsed,
+		Warnings:   warnings,
 	}, nil
 }
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, set at build time via
+// -ldflags "-X github.com/cloudoperators/common-cloud-resource-names/pkg/version.Version=..."
+var (
+	// Version is the released version of the binary
+	Version = "dev"
+	// GitCommit is the commit the binary was built from
+	GitCommit = ""
+	// BuildDate is the RFC 3339 timestamp of the build
+	BuildDate = ""
+	// SchemaBundleVersion identifies the CCRN schema snapshot shipped with or pinned by the build
+	SchemaBundleVersion = ""
+)
+
+// Info contains the build metadata of the running binary
+type Info struct {
+	Version             string `json:"version"`
+	GitCommit           string `json:"gitCommit,omitempty"`
+	BuildDate           string `json:"buildDate,omitempty"`
+	GoVersion           string `json:"goVersion"`
+	Platform            string `json:"platform"`
+	SchemaBundleVersion string `json:"schemaBundleVersion,omitempty"`
+}
+
+// Get returns the build metadata of the running binary. The commit falls back to the
+// VCS information embedded by the Go toolchain if it was not set via ldflags.
+func Get() Info {
+	info := Info{
+		Version:             Version,
+		GitCommit:           GitCommit,
+		BuildDate:           BuildDate,
+		GoVersion:           runtime.Version(),
+		Platform:            fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		SchemaBundleVersion: SchemaBundleVersion,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitCommit == "" {
+					info.GitCommit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// String returns a human-readable single line representation of the build metadata
+func (i Info) String() string {
+	s := fmt.Sprintf("version=%s commit=%s buildDate=%s go=%s platform=%s", i.Version, i.GitCommit, i.BuildDate, i.GoVersion, i.Platform)
+	if i.SchemaBundleVersion != "" {
+		s += " schemaBundle=" + i.SchemaBundleVersion
+	}
+	return s
+}
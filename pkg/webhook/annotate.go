@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
+)
+
+// Results of annotating a workload, see the ccrn_workload_annotations_total metric
+const (
+	AnnotationResultAnnotated = "annotated" // The identifier annotation was added
+	AnnotationResultUnchanged = "unchanged" // The object already carries an identifier annotation
+	AnnotationResultSkipped   = "skipped"   // The resource is not annotated, or the object has no name yet and the object mapping needs it
+	AnnotationResultFailed    = "failed"    // No CCRN could be derived from the object
+)
+
+// workloadAnnotation configures the annotation of workloads with their CCRN, see WithWorkloadAnnotation
+type workloadAnnotation struct {
+	resources []schema.GroupResource
+	fields    map[string]string
+}
+
+// WithWorkloadAnnotation annotates objects of the resources, e.g. "pods", "services" or "deployments.apps", sent
+// to the /annotate path with their CCRN in apis.IdentifierAnnotation. The CCRN is derived by the CRD declaring
+// the resource as its source (ccrn/source-resource, or the kind by default) from the object mapping of the CRD,
+// the fields set values the objects do not provide, like the cluster. Existing annotations are kept.
+// Objects are never rejected, failures are returned as warnings.
+func WithWorkloadAnnotation(resources []string, fields map[string]string) Option {
+	return func(s *WebhookServer) {
+		s.annotation = &workloadAnnotation{fields: fields}
+		for _, resource := range resources {
+			s.annotation.resources = append(s.annotation.resources, schema.ParseGroupResource(strings.TrimSpace(resource)))
+		}
+	}
+}
+
+// handleAnnotateRequest adds the identifier annotation to the object of the request
+func (s *WebhookServer) handleAnnotateRequest(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	_, span := tracing.StartSpan(ctx, "webhook.Annotate",
+		attribute.String("admission.uid", string(request.UID)),
+		attribute.String("admission.resource", request.Resource.Resource),
+	)
+	defer span.End()
+
+	start := time.Now()
+	log := s.requestLogger(request)
+	response := &admissionv1.AdmissionResponse{Allowed: true}
+	resource := schema.GroupResource{Group: request.Resource.Group, Resource: request.Resource.Resource}
+	result := func(result string) *admissionv1.AdmissionResponse {
+		span.SetAttributes(attribute.String("annotation.result", result))
+		recordAnnotation(resource, result)
+		return response
+	}
+	fail := func(format string, args ...any) *admissionv1.AdmissionResponse {
+		warning := fmt.Sprintf(format, args...)
+		log.Warnf("Not annotating %s: %s", resource, warning)
+		response.Warnings = []string{"Not annotated with " + apis.IdentifierAnnotation + ": " + warning}
+		return result(AnnotationResultFailed)
+	}
+
+	if !s.annotates(resource) || request.SubResource != "" ||
+		(request.Operation != admissionv1.Create && request.Operation != admissionv1.Update) {
+		return result(AnnotationResultSkipped)
+	}
+
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal(request.Object.Raw, &object.Object); err != nil {
+		return fail("failed to parse object: %v", err)
+	}
+	annotations := object.GetAnnotations()
+	if _, exists := annotations[apis.IdentifierAnnotation]; exists {
+		return result(AnnotationResultUnchanged)
+	}
+	if object.GetNamespace() == "" {
+		object.SetNamespace(request.Namespace)
+	}
+
+	crdInfo := s.sourceCRD(resource, request.Kind.Kind)
+	if crdInfo == nil {
+		return fail("no CCRN resource type declares %s as its source", resource)
+	}
+	// Objects created with generateName, like the pods of controllers, only get their name after admission.
+	// They are annotated if the object mapping does not need the name, e.g. maps metadata.generateName or
+	// metadata.ownerReferences[0].name instead.
+	if object.GetName() == "" && mapsName(crdInfo) {
+		log.Debugf("Not annotating %s without name", resource)
+		return result(AnnotationResultSkipped)
+	}
+	parsed, err := crdInfo.ResourceFromObject(object.Object, s.annotation.fields)
+	if err != nil {
+		return fail("%s", strings.Join(apis.ErrorMessages(err), "; "))
+	}
+
+	patch := map[string]any{"op": "add", "path": "/metadata/annotations", "value": map[string]string{apis.IdentifierAnnotation: parsed.Raw}}
+	if len(annotations) > 0 {
+		patch = map[string]any{"op": "add", "path": "/metadata/annotations/" + escapeJSONPointer(apis.IdentifierAnnotation), "value": parsed.Raw}
+	}
+	patchBytes, err := json.Marshal([]map[string]any{patch})
+	if err != nil {
+		return fail("failed to marshal patch: %v", err)
+	}
+	pt := admissionv1.PatchTypeJSONPatch
+	response.Patch = patchBytes
+	response.PatchType = &pt
+	s.logDecision(log.WithField("ccrn", parsed.CCRNKey()), DecisionAnnotated, start, "Annotated "+resource.String())
+	return result(AnnotationResultAnnotated)
+}
+
+// annotates reports whether objects of the resource are annotated
+func (s *WebhookServer) annotates(resource schema.GroupResource) bool {
+	if s.annotation == nil {
+		return false
+	}
+	for _, annotated := range s.annotation.resources {
+		if annotated == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceCRD returns the highest version of the first resource type, by CCRN key, whose objects are of the
+// resource, nil if there is none. The backend resolves it from an index of its current CRDs.
+func (s *WebhookServer) sourceCRD(resource schema.GroupResource, kind string) *apis.CRDInfo {
+	resolver, ok := s.backend.(apis.SourceResolver)
+	if !ok {
+		return nil
+	}
+	if crdInfo, found := resolver.ResolveSource(resource); found {
+		return crdInfo
+	}
+	crdInfo, _ := resolver.ResolveSource(schema.GroupResource{Group: resource.Group, Resource: strings.ToLower(kind)})
+	return crdInfo
+}
+
+// mapsName reports whether the object mapping of the CRD version derives a field from the name of the object
+func mapsName(crdInfo *apis.CRDInfo) bool {
+	for _, path := range crdInfo.ObjectMappingOrDefault() {
+		if path == "metadata.name" {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeJSONPointer escapes a key for use in a JSON patch path
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
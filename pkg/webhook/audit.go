@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/audit"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+)
+
+// audit writes the admission decision to the configured audit sink, if any
+func (s *WebhookServer) audit(ctx context.Context, log *logrus.Entry, request *admissionv1.AdmissionRequest, parsed *apis.ParsedResource,
+	urn, decision, reason, message string, patches []byte, latency time.Duration) {
+	if s.auditSink == nil {
+		return
+	}
+
+	event := audit.Event{
+		Timestamp: time.Now().UTC(),
+		UID:       string(request.UID),
+		User:      request.UserInfo.Username,
+		Groups:    request.UserInfo.Groups,
+		Operation: string(request.Operation),
+		Namespace: request.Namespace,
+		Name:      request.Name,
+		URN:       urn,
+		Decision:  decision,
+		Reason:    reason,
+		Message:   message,
+		Patches:   patches,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+	}
+	if parsed != nil {
+		event.CCRN = parsed.CCRN()
+	}
+
+	if err := s.auditSink.Write(ctx, event); err != nil {
+		log.Errorf("Failed to write audit event: %v", err)
+	}
+}
+
+// record writes the sanitized request and its response to the configured recorder, if any
+func (s *WebhookServer) record(ctx context.Context, path string, request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse) {
+	if s.requestRecorder == nil {
+		return
+	}
+
+	sanitized := replay.Sanitize(request, s.pseudonymKey, s.recordedPaths(path, request)...)
+	record := replay.Record{Timestamp: time.Now().UTC(), Path: path, Request: sanitized, Response: response}
+	if err := s.requestRecorder.Record(ctx, record); err != nil {
+		s.requestLogger(request).Errorf("Failed to record admission request: %v", err)
+	}
+}
+
+// recordedPaths returns the paths of the object the handler of the path reads besides the metadata: the CCRN
+// fields of CCRN resources, or the object mapping of the source CRD of annotated workloads
+func (s *WebhookServer) recordedPaths(path string, request *admissionv1.AdmissionRequest) []string {
+	if path != "/annotate" {
+		return replay.CCRNPaths
+	}
+	crdInfo := s.sourceCRD(schema.GroupResource{Group: request.Resource.Group, Resource: request.Resource.Resource}, request.Kind.Kind)
+	if crdInfo == nil {
+		return nil
+	}
+	mapping := crdInfo.ObjectMappingOrDefault()
+	paths := make([]string, 0, len(mapping))
+	for _, objectPath := range mapping {
+		paths = append(paths, objectPath)
+	}
+	return paths
+}
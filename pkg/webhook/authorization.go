@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Authorizer decides whether a user may register identifiers for a CCRN resource type
+type Authorizer interface {
+	// Authorize returns whether the user is allowed to manage the resource type in the namespace,
+	// and a human-readable reason if not
+	Authorize(ctx context.Context, user authenticationv1.UserInfo, namespace string, crd *apis.CRDInfo) (bool, string, error)
+}
+
+// SubjectAccessReviewAuthorizer authorizes users by asking the API server whether they are allowed
+// to create the target CCRN kind referenced by the identifier, not just the wrapping CCRN resource
+type SubjectAccessReviewAuthorizer struct {
+	client kubernetes.Interface
+	verb   string
+}
+
+// NewSubjectAccessReviewAuthorizer creates an authorizer checking the create verb via SubjectAccessReviews
+func NewSubjectAccessReviewAuthorizer(client kubernetes.Interface) *SubjectAccessReviewAuthorizer {
+	return &SubjectAccessReviewAuthorizer{client: client, verb: "create"}
+}
+
+// Authorize implements Authorizer
+func (a *SubjectAccessReviewAuthorizer) Authorize(ctx context.Context, user authenticationv1.UserInfo, namespace string, crd *apis.CRDInfo) (bool, string, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      a.verb,
+				Group:     crd.Group,
+				Version:   crd.Version,
+				Resource:  crd.Plural,
+			},
+		},
+	}
+
+	result, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+
+	if result.Status.Allowed {
+		return true, "", nil
+	}
+
+	reason := fmt.Sprintf("user %s is not allowed to %s %s.%s in namespace %q", user.Username, a.verb, crd.Plural, crd.Group, namespace)
+	if result.Status.Reason != "" {
+		reason += ": " + result.Status.Reason
+	}
+	return false, reason, nil
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// Run with e.g. "make bench" or "go test ./pkg/webhook -run '^$' -bench . -benchmem".
+
+// BenchmarkAdmission measures the full handling of an admission review by the validate endpoint, from
+// decoding the request to encoding the response with the URN patch
+func BenchmarkAdmission(b *testing.B) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	backend := validation.NewOfflineBackend(log, "tr.ccrn.example.com")
+	if err := backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml")); err != nil {
+		b.Fatal(err)
+	}
+	server, err := webhook.NewWebhookServer(log, backend)
+	if err != nil {
+		b.Fatal(err)
+	}
+	handler := server.Handler()
+
+	for _, input := range []struct {
+		name    string
+		spec    apis.CCRNSpec
+		allowed bool
+	}{
+		{"allowed", apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}, true},
+		{"denied", apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!, namespace=default, name=my-pod"}, false},
+	} {
+		raw, err := json.Marshal(apis.CCRN{Spec: input.spec})
+		if err != nil {
+			b.Fatal(err)
+		}
+		body, err := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+			UID: "bench-uid", Namespace: "default", Name: "bench", Operation: admissionv1.Create,
+			Object: runtime.RawExtension{Raw: raw},
+		}})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(input.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+				request.Header.Set("Content-Type", "application/json")
+				handler.ServeHTTP(recorder, request)
+
+				review := admissionv1.AdmissionReview{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &review); err != nil || review.Response == nil {
+					b.Fatalf("invalid response %q: %v", recorder.Body.String(), err)
+				}
+				if review.Response.Allowed != input.allowed {
+					b.Fatalf("allowed is %t, expected %t", review.Response.Allowed, input.allowed)
+				}
+			}
+		})
+	}
+}
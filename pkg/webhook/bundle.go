@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+)
+
+// WithBundleDigest pins the webhook to the digest of a schema bundle, see pkg/bundle. The backend must
+// implement apis.CRDSource. While the digest of its CRDs differs, e.g. because a CRD of the cluster was
+// changed, CCRN resources are denied with the reason bundle_mismatch and the readiness check fails, so
+// validation stays reproducible across environments. See VerifyBundleDigest.
+func WithBundleDigest(digest string) Option {
+	return func(s *WebhookServer) {
+		s.bundleDigest = digest
+	}
+}
+
+// VerifyBundleDigest compares the digest of the CRDs of the backend with the pinned one and remembers the
+// result for the admission requests and the readiness check. It returns nil if no digest is pinned.
+func (s *WebhookServer) VerifyBundleDigest() error {
+	if s.bundleDigest == "" {
+		return nil
+	}
+	// NewWebhookServer makes sure the backend lists its CRDs
+	source := s.backend.(apis.CRDSource)
+	err := bundle.Verify(source.CustomResourceDefinitions(), s.bundleDigest)
+	if previous := s.bundleErr.Swap(&err); err != nil && (previous == nil || *previous == nil) {
+		s.log.Errorf("Denying CCRN resources until the CRDs match the pinned schema bundle: %v", err)
+	} else if err == nil && previous != nil && *previous != nil {
+		s.log.Infof("CRDs match the pinned schema bundle %s again", s.bundleDigest)
+	}
+	return err
+}
+
+// StartBundleVerification starts a background goroutine verifying the digest of the CRDs periodically,
+// see VerifyBundleDigest
+func (s *WebhookServer) StartBundleVerification(interval time.Duration) {
+	if s.bundleDigest == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			_ = s.VerifyBundleDigest()
+		}
+	}()
+}
+
+// bundleMismatch returns the result of the last verification of the pinned digest
+func (s *WebhookServer) bundleMismatch() error {
+	if err := s.bundleErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// bundleChecker is the readiness check failing while the CRDs do not match the pinned digest
+func (s *WebhookServer) bundleChecker(_ *http.Request) error {
+	return s.bundleMismatch()
+}
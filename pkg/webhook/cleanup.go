@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// validateResource validates the target resource with the backend. Resources the backend derives from the
+// CCRN, see apis.DerivedResourceManager, are owned by the admitted CCRN object. Objects created with
+// generateName have no name yet, their derived resources are not owned. Dry-run requests are validated
+// without side effects where the backend supports it, see apis.DryRunValidator.
+func (s *WebhookServer) validateResource(log *logrus.Entry, request *admissionv1.AdmissionRequest, ccrn *apis.CCRN, parsedCCRN *apis.ParsedResource) error {
+	if isDryRun(request) {
+		if validator, ok := s.backend.(apis.DryRunValidator); ok {
+			return validator.ValidateResourceDryRun(log, request.Namespace, parsedCCRN)
+		}
+		return s.backend.ValidateResource(request.Namespace, parsedCCRN)
+	}
+	if manager, ok := s.backend.(apis.DerivedResourceManager); ok && ownsDerivedResources(request, ccrn) {
+		return manager.ValidateOwnedResource(log, request.Namespace, parsedCCRN, ownerReference(request, ccrn.ObjectMeta))
+	}
+	return s.backend.ValidateResource(request.Namespace, parsedCCRN)
+}
+
+// finalizerPatch returns the patch adding apis.FinalizerDerivedResources to CCRN objects owning derived
+// resources, nil if the object has it already or owns none. The finalizer keeps the object until the
+// cleanup controller deleted its derived resources, see package cleanup.
+func (s *WebhookServer) finalizerPatch(request *admissionv1.AdmissionRequest, ccrn *apis.CCRN) map[string]any {
+	if _, ok := s.backend.(apis.DerivedResourceManager); !ok || isDryRun(request) || !ownsDerivedResources(request, ccrn) {
+		return nil
+	}
+	if slices.Contains(ccrn.Finalizers, apis.FinalizerDerivedResources) {
+		return nil
+	}
+	if len(ccrn.Finalizers) == 0 {
+		return map[string]any{"op": "add", "path": "/metadata/finalizers", "value": []string{apis.FinalizerDerivedResources}}
+	}
+	return map[string]any{"op": "add", "path": "/metadata/finalizers/-", "value": apis.FinalizerDerivedResources}
+}
+
+// ownsDerivedResources reports whether the derived resources of the request can be owned by the CCRN object,
+// objects created with generateName have no name yet
+func ownsDerivedResources(request *admissionv1.AdmissionRequest, ccrn *apis.CCRN) bool {
+	return ownerReference(request, ccrn.ObjectMeta).Name != ""
+}
+
+// isDryRun reports whether the request must not have side effects
+func isDryRun(request *admissionv1.AdmissionRequest) bool {
+	return request.DryRun != nil && *request.DryRun
+}
+
+// ownerReference references the CCRN object of the request, objects being created have no UID yet
+func ownerReference(request *admissionv1.AdmissionRequest, metadata metav1.ObjectMeta) metav1.OwnerReference {
+	name := metadata.Name
+	if name == "" {
+		name = request.Name
+	}
+	return metav1.OwnerReference{
+		APIVersion: schema.GroupVersion{Group: request.Kind.Group, Version: request.Kind.Version}.String(),
+		Kind:       request.Kind.Kind,
+		Name:       name,
+		UID:        metadata.UID,
+	}
+}
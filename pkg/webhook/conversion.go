@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// convert is the HTTP handler for CRD conversion requests of CCRN custom resources
+func (s *WebhookServer) convert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "Request body is not a ConversionReview", http.StatusBadRequest)
+		return
+	}
+
+	log := s.log.WithFields(logrus.Fields{
+		"uid":               review.Request.UID,
+		"desiredAPIVersion": review.Request.DesiredAPIVersion,
+	})
+	review.Response = s.convertObjects(review.Request)
+	review.Request = nil
+
+	if review.Response.Result.Status == metav1.StatusFailure {
+		log.Warnf("Conversion failed: %s", review.Response.Result.Message)
+	} else {
+		log.Debugf("Converted %d objects", len(review.Response.ConvertedObjects))
+	}
+	respBytes, err := json.Marshal(review)
+	if err != nil {
+		log.Errorf("Failed to marshal response: %v", err)
+		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(respBytes); err != nil {
+		log.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// convertObjects converts all objects of the request, a single failure fails the whole request
+func (s *WebhookServer) convertObjects(request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	response := &apiextensionsv1.ConversionResponse{UID: request.UID}
+
+	for _, raw := range request.Objects {
+		object := map[string]any{}
+		if err := json.Unmarshal(raw.Raw, &object); err != nil {
+			response.Result = conversionFailure("Failed to decode object: %v", err)
+			return response
+		}
+
+		converted, err := s.converter.Convert(object, request.DesiredAPIVersion)
+		if err != nil {
+			response.Result = conversionFailure("Failed to convert object: %v", err)
+			return response
+		}
+
+		convertedRaw, err := json.Marshal(converted)
+		if err != nil {
+			response.Result = conversionFailure("Failed to encode object: %v", err)
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: convertedRaw})
+	}
+
+	response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return response
+}
+
+// conversionFailure creates the failed result of a ConversionResponse
+func conversionFailure(format string, args ...any) metav1.Status {
+	return metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// Reasons of the Kubernetes Events emitted by the webhook
+const (
+	EventReasonDenied            = "CCRNDenied"
+	EventReasonMutated           = "CCRNMutated"
+	EventReasonDeprecatedVersion = "DeprecatedCCRNVersion"
+)
+
+// WithEventRecorder emits Kubernetes Events on the admitted CCRN object for rejections,
+// applied mutations and usage of deprecated CCRN versions
+func WithEventRecorder(recorder record.EventRecorder) Option {
+	return func(s *WebhookServer) {
+		s.recorder = recorder
+	}
+}
+
+// WithNamespaceEvents additionally emits every event on the namespace of the admitted object,
+// so rejected creations, which never result in an object, remain visible to users
+func WithNamespaceEvents() Option {
+	return func(s *WebhookServer) {
+		s.namespaceEvents = true
+	}
+}
+
+// recordEvent emits an event for the admitted object and, if configured, its namespace.
+// Objects being created don't have a UID yet, events for them are found by name, e.g. via
+// kubectl get events --field-selector involvedObject.name=<name>
+func (s *WebhookServer) recordEvent(request *admissionv1.AdmissionRequest, eventType, reason, messageFmt string, args ...any) {
+	if s.recorder == nil {
+		return
+	}
+
+	if request.Name != "" {
+		s.recorder.Eventf(objectReference(request), eventType, reason, messageFmt, args...)
+	}
+	if s.namespaceEvents && request.Namespace != "" {
+		s.recorder.Eventf(&corev1.ObjectReference{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+			Name:       request.Namespace,
+			Namespace:  request.Namespace,
+		}, eventType, reason, messageFmt, args...)
+	}
+}
+
+// recordMutationEvent emits an event listing the fields added by the patches
+func (s *WebhookServer) recordMutationEvent(request *admissionv1.AdmissionRequest, patches []map[string]any) {
+	paths := make([]string, 0, len(patches))
+	for _, patch := range patches {
+		paths = append(paths, fmt.Sprint(patch["path"]))
+	}
+	s.recordEvent(request, corev1.EventTypeNormal, EventReasonMutated, "Added %s", strings.Join(paths, ", "))
+}
+
+// recordDeprecationEvent emits a warning if the CCRN references a deprecated version of its type
+func (s *WebhookServer) recordDeprecationEvent(request *admissionv1.AdmissionRequest, parsed *apis.ParsedResource) {
+	if s.recorder == nil {
+		return
+	}
+
+	if message := s.deprecationMessage(parsed); message != "" {
+		s.recordEvent(request, corev1.EventTypeWarning, EventReasonDeprecatedVersion, "%s", message)
+	}
+}
+
+// deprecationMessage returns the deprecation warning of the referenced version, empty if it is not deprecated
+func (s *WebhookServer) deprecationMessage(parsed *apis.ParsedResource) string {
+	crdInfo, err := s.backend.GetCRD(parsed.CCRNKey())
+	if err != nil {
+		return ""
+	}
+	return crdInfo.DeprecationMessage()
+}
+
+// objectReference references the object of an admission request
+func objectReference(request *admissionv1.AdmissionRequest) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		Kind:       request.Kind.Kind,
+		APIVersion: schema.GroupVersion{Group: request.Kind.Group, Version: request.Kind.Version}.String(),
+		Name:       request.Name,
+		Namespace:  request.Namespace,
+	}
+
+	// Existing objects carry their UID, which kubectl describe uses to find events
+	metadata := metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(request.Object.Raw, &metadata); err == nil {
+		ref.UID = metadata.UID
+		ref.ResourceVersion = metadata.ResourceVersion
+	}
+	return ref
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+)
+
+// Decisions logged for admission requests
+const (
+	DecisionAllowed   = "allowed"
+	DecisionDenied    = "denied"
+	DecisionAnnotated = "annotated"
+)
+
+// WithLogSampling logs the decisions of only every nth allowed or annotated request at info level and of the
+// others at debug level, so deploy storms do not flood the log pipeline. Denials are always logged, a value
+// of 1 or less logs every request.
+func WithLogSampling(n int) Option {
+	return func(s *WebhookServer) {
+		s.logSampling = n
+	}
+}
+
+// logDecision logs the decision of an admission request with its duration as one line. Allowed and annotated
+// requests are sampled, see WithLogSampling.
+func (s *WebhookServer) logDecision(log *logrus.Entry, decision string, start time.Time, message string) {
+	level := logrus.InfoLevel
+	if decision != DecisionDenied && s.logSampling > 1 && s.logSequence.Add(1)%uint64(s.logSampling) != 0 {
+		level = logrus.DebugLevel
+	}
+	log.WithFields(logrus.Fields{
+		"decision":    decision,
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}).Log(level, message)
+}
+
+// NewLogrusLogger returns a logr.Logger writing to the logrus logger, e.g. to route the logs of controller-runtime
+// through the log pipeline of the webhook. Info messages of verbosity 0 are logged at info level, more verbose
+// ones at debug level and errors at error level.
+func NewLogrusLogger(log *logrus.Logger) logr.Logger {
+	return logr.New(&logrusSink{entry: logrus.NewEntry(log)})
+}
+
+// logrusSink implements logr.LogSink with a logrus entry carrying the name and values of the logger
+type logrusSink struct {
+	entry *logrus.Entry
+	name  string
+}
+
+func (l *logrusSink) Init(logr.RuntimeInfo) {}
+
+func (l *logrusSink) Enabled(level int) bool {
+	return l.entry.Logger.IsLevelEnabled(infoLevel(level))
+}
+
+func (l *logrusSink) Info(level int, msg string, keysAndValues ...any) {
+	l.entry.WithFields(logrusFields(keysAndValues)).Log(infoLevel(level), msg)
+}
+
+func (l *logrusSink) Error(err error, msg string, keysAndValues ...any) {
+	l.entry.WithFields(logrusFields(keysAndValues)).WithError(err).Error(msg)
+}
+
+func (l *logrusSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logrusSink{entry: l.entry.WithFields(logrusFields(keysAndValues)), name: l.name}
+}
+
+func (l *logrusSink) WithName(name string) logr.LogSink {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return &logrusSink{entry: l.entry.WithField("logger", name), name: name}
+}
+
+// infoLevel maps the verbosity of a logr info message to a logrus level
+func infoLevel(level int) logrus.Level {
+	if level > 0 {
+		return logrus.DebugLevel
+	}
+	return logrus.InfoLevel
+}
+
+// logrusFields converts logr key value pairs to logrus fields, a key without value gets a nil value
+func logrusFields(keysAndValues []any) logrus.Fields {
+	fields := make(logrus.Fields, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value any
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fields[fmt.Sprint(keysAndValues[i])] = value
+	}
+	return fields
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/lru"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+)
+
+// Coarse taxonomy of reasons why an admission request is denied
+const (
+	DenyReasonParseError      = "parse_error"      // The CCRN/URN or the wrapping object could not be parsed
+	DenyReasonUnsupportedType = "unsupported_type" // The referenced resource type is not known
+	DenyReasonSchemaViolation = "schema_violation" // The fields do not satisfy the resource type schema
+	DenyReasonBackendError    = "backend_error"    // The validation backend failed to answer
+	DenyReasonUnauthorized    = "unauthorized"     // The user may not manage the referenced resource type
+	DenyReasonPolicyViolation = "policy_violation" // An organization policy denies the resource, see PolicyEngine
+	DenyReasonUnknownValue    = "unknown_value"    // A field value is unknown to its external inventory, see FieldResolver
+	DenyReasonVersionSunset   = "version_sunset"   // The referenced version is past its sunset date, see featuregate.SunsetEnforcement
+	DenyReasonBundleMismatch  = "bundle_mismatch"  // The CRDs do not match the pinned schema bundle, see WithBundleDigest
+	DenyReasonTooComplex      = "too_complex"      // The identifier exceeds the complexity limits, see WithLimits
+)
+
+// unknownLabel is used for kind and version if a request was denied before they were known
+const unknownLabel = "unknown"
+
+var (
+	admissionRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_admission_requests_total",
+		Help: "Total number of CCRN admission requests by CCRN kind, version, operation and decision.",
+	}, []string{"kind", "version", "operation", "decision"})
+
+	admissionDenialsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_admission_denials_total",
+		Help: "Total number of denied CCRN admission requests by CCRN kind, version and deny reason.",
+	}, []string{"kind", "version", "reason"})
+
+	admissionMutationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_admission_mutations_total",
+		Help: "Total number of fields added by the mutating webhook by CCRN kind, version and field.",
+	}, []string{"kind", "version", "field"})
+
+	admissionDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ccrn_admission_duration_seconds",
+		Help:    "Duration of CCRN admission request handling by decision.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"decision"})
+
+	fieldResolutionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_field_resolutions_total",
+		Help: "Total number of field values resolved against external inventories by field and result (found, not_found or error).",
+	}, []string{"field", "result"})
+
+	workloadAnnotationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_workload_annotations_total",
+		Help: "Total number of workload annotation requests by resource and result (annotated, unchanged, skipped or failed).",
+	}, []string{"resource", "result"})
+
+	cacheEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ccrn_cache_evictions_total",
+		Help: "Number of entries evicted from size bounded caches because they were full, by cache.",
+	}, []string{"cache"})
+)
+
+// The collectors are registered with the controller-runtime registry, which is served by the
+// metrics server of the manager and by the /metrics route of Handler. The schema changes of the backend are
+// registered here as well, see validation.Collector, and the evictions of the caches of the libraries used
+// are counted with the hook of package lru.
+func init() {
+	ctrlmetrics.Registry.MustRegister(admissionRequestsTotal, admissionDenialsTotal, admissionMutationsTotal, admissionDurationSeconds, fieldResolutionsTotal,
+		workloadAnnotationsTotal, cacheEvictionsTotal, validation.Collector())
+	lru.SetEvictionHook(func(cache string) {
+		cacheEvictionsTotal.WithLabelValues(cache).Inc()
+	})
+}
+
+// kindVersionLabels returns the kind and version label values for a parsed resource
+func kindVersionLabels(parsed *apis.ParsedResource) (string, string) {
+	if parsed == nil || parsed.CCRNName() == "" {
+		return unknownLabel, unknownLabel
+	}
+	version := parsed.Version()
+	if version == "" {
+		version = unknownLabel
+	}
+	return parsed.CCRNName(), version
+}
+
+// recordAdmission records the outcome of an admission request
+func recordAdmission(request *admissionv1.AdmissionRequest, parsed *apis.ParsedResource, allowed bool, duration time.Duration) {
+	decision := "denied"
+	if allowed {
+		decision = "allowed"
+	}
+	kind, version := kindVersionLabels(parsed)
+	admissionRequestsTotal.WithLabelValues(kind, version, string(request.Operation), decision).Inc()
+	admissionDurationSeconds.WithLabelValues(decision).Observe(duration.Seconds())
+}
+
+// recordDenial records a denied admission request including its deny reason
+func recordDenial(request *admissionv1.AdmissionRequest, d *denial, duration time.Duration) {
+	kind, version := kindVersionLabels(d.parsed)
+	admissionDenialsTotal.WithLabelValues(kind, version, d.reason).Inc()
+	recordAdmission(request, d.parsed, false, duration)
+}
+
+// recordMutations records the fields added by the mutation patches, label and finalizer patches are counted as
+// field "labels" and "finalizers"
+func recordMutations(parsed *apis.ParsedResource, patches []map[string]any) {
+	kind, version := kindVersionLabels(parsed)
+	for _, patch := range patches {
+		path, _ := patch["path"].(string)
+		field := strings.TrimPrefix(path, "/spec/")
+		switch {
+		case strings.HasPrefix(path, "/metadata/labels"):
+			field = "labels"
+		case strings.HasPrefix(path, "/metadata/finalizers"):
+			field = "finalizers"
+		}
+		admissionMutationsTotal.WithLabelValues(kind, version, field).Inc()
+	}
+}
+
+// recordResolution records the result of resolving a field value
+func recordResolution(field string, exists bool, err error) {
+	result := "not_found"
+	switch {
+	case err != nil:
+		result = "error"
+	case exists:
+		result = "found"
+	}
+	fieldResolutionsTotal.WithLabelValues(field, result).Inc()
+}
+
+// recordAnnotation records the result of annotating a workload with its CCRN
+func recordAnnotation(resource schema.GroupResource, result string) {
+	workloadAnnotationsTotal.WithLabelValues(resource.String(), result).Inc()
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// NamespaceConfigs provides the CCRNConfigs tuning the enforcement per namespace, see pkg/namespaceconfig
+type NamespaceConfigs interface {
+	// NamespaceConfig returns the merged config of the namespace, nil if it has none
+	NamespaceConfig(namespace string) *apis.CCRNConfigSpec
+}
+
+// WithNamespaceConfigs applies the CCRNConfigs of the namespaces: defaults are added to CCRNs before validation,
+// and enforced groups and policies are skipped for exemptions and only warn in namespaces of strictness Warn
+func WithNamespaceConfigs(configs NamespaceConfigs) Option {
+	return func(s *WebhookServer) {
+		s.namespaceConfigs = configs
+	}
+}
+
+// namespaceConfig returns the config of the namespace, nil if there is none
+func (s *WebhookServer) namespaceConfig(namespace string) *apis.CCRNConfigSpec {
+	if s.namespaceConfigs == nil {
+		return nil
+	}
+	return s.namespaceConfigs.NamespaceConfig(namespace)
+}
+
+// applyDefaults returns the CCRN with the missing fields set to the defaults of the namespace config, followed
+// by the defaults of the schema, see apis.ApplyDefaults. Only fields of the referenced resource type are added,
+// unparsable CCRNs are returned unchanged.
+func (s *WebhookServer) applyDefaults(log *logrus.Entry, config *apis.CCRNConfigSpec, ccrn string) string {
+	if ccrn == "" {
+		return ccrn
+	}
+	written, err := apis.ParseCCRN(ccrn)
+	if err != nil {
+		return ccrn
+	}
+	crdInfo, err := s.backend.GetCRD(s.parser.ResolveKey(written.Fields["ccrn"]))
+	if err != nil {
+		return ccrn
+	}
+
+	var defaulted []string
+	if config != nil {
+		for field, value := range config.Defaults {
+			if _, set := written.Fields[field]; !set && field != "ccrn" && crdInfo.HasField(field) {
+				written.Fields[field] = value
+				defaulted = append(defaulted, field)
+			}
+		}
+	}
+	defaulted = append(defaulted, apis.ApplyDefaults(written, crdInfo)...)
+	if len(defaulted) == 0 {
+		return ccrn
+	}
+	log.Debugf("Added defaults for %s", strings.Join(defaulted, ", "))
+	return written.CanonicalCCRN()
+}
+
+// enforceGroup denies CCRNs referencing resource types of groups that are not enforced in the namespace
+func enforceGroup(config *apis.CCRNConfigSpec, namespace string, parsedCCRN *apis.ParsedResource) *denial {
+	if config.EnforcesGroup(parsedCCRN.ApiGroup()) {
+		return nil
+	}
+	return deny(DenyReasonPolicyViolation, parsedCCRN, "Resource types of group %s are not allowed in namespace %s, allowed groups: %s",
+		parsedCCRN.ApiGroup(), namespace, strings.Join(config.EnforcedGroups, ", "))
+}
+
+// exempted reports whether the user or the resource type is exempted by the namespace config
+func exempted(config *apis.CCRNConfigSpec, user authenticationv1.UserInfo, parsedCCRN *apis.ParsedResource) bool {
+	return config.Exempts(user.Username, user.Groups, parsedCCRN.CCRNName())
+}
+
+// fieldsDefaulted reports whether fields were added to the written CCRN, e.g. the defaults of the namespace
+func fieldsDefaulted(ccrn string, parsed *apis.ParsedResource) bool {
+	written, err := apis.ParseCCRN(ccrn)
+	return err == nil && len(written.Fields) < len(parsed.Fields)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
+)
+
+// PolicyEngine evaluates organization policies against validated CCRN resources, e.g. Rego policies via pkg/opa.
+// Policies express rules beyond the CRD schemas, like "production namespaces must not use wildcard clusters".
+type PolicyEngine interface {
+	// Evaluate returns the violations of the policies for the input, an error denies the request
+	Evaluate(ctx context.Context, input *PolicyInput) (*PolicyDecision, error)
+}
+
+// PolicyInput is the document policies are evaluated against
+type PolicyInput struct {
+	Resource *apis.ParsedResource `json:"resource"` // Validated CCRN resource with resolved type and version
+	Request  PolicyRequest        `json:"request"`
+}
+
+// PolicyRequest is the metadata of the admission request of a CCRN resource
+type PolicyRequest struct {
+	Operation string                    `json:"operation"` // CREATE or UPDATE
+	Namespace string                    `json:"namespace"`
+	Name      string                    `json:"name"`
+	Labels    map[string]string         `json:"labels,omitempty"` // Labels of the CCRN object
+	UserInfo  authenticationv1.UserInfo `json:"userInfo"`
+	DryRun    bool                      `json:"dryRun,omitempty"`
+}
+
+// PolicyDecision are the violations of the policies, denials reject the request and warnings are returned to the user
+type PolicyDecision struct {
+	Denials  []string `json:"denials,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// WithPolicyEngines evaluates the policies of the engines after schema validation, in order
+func WithPolicyEngines(engines ...PolicyEngine) Option {
+	return func(s *WebhookServer) {
+		s.policyEngines = append(s.policyEngines, engines...)
+	}
+}
+
+// newPolicyInput returns the policy input of an admission request of a validated CCRN resource
+func newPolicyInput(request *admissionv1.AdmissionRequest, ccrn *apis.CCRN, parsedCCRN *apis.ParsedResource) *PolicyInput {
+	return &PolicyInput{
+		Resource: parsedCCRN,
+		Request: PolicyRequest{
+			Operation: string(request.Operation),
+			Namespace: request.Namespace,
+			Name:      request.Name,
+			Labels:    ccrn.Labels,
+			UserInfo:  request.UserInfo,
+			DryRun:    request.DryRun != nil && *request.DryRun,
+		},
+	}
+}
+
+// evaluatePolicies evaluates the policy engines and returns the warnings of all engines, or a denial
+// with the denials of all engines
+func (s *WebhookServer) evaluatePolicies(ctx context.Context, log *logrus.Entry, input *PolicyInput) ([]string, *denial) {
+	var denials, warnings []string
+	for _, engine := range s.policyEngines {
+		ctx, span := tracing.StartSpan(ctx, "policy.Evaluate", attribute.String("ccrn.key", input.Resource.CCRNKey()))
+		decision, err := engine.Evaluate(ctx, input)
+		tracing.EndSpan(span, err)
+		if err != nil {
+			return nil, deny(DenyReasonBackendError, input.Resource, "Failed to evaluate policies: %v", err)
+		}
+		denials = append(denials, decision.Denials...)
+		warnings = append(warnings, decision.Warnings...)
+	}
+	if len(denials) > 0 {
+		return nil, deny(DenyReasonPolicyViolation, input.Resource, "Policy violation: %s", strings.Join(denials, "; "))
+	}
+	if len(warnings) > 0 {
+		log.Debugf("Policy warnings: %s", strings.Join(warnings, "; "))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
+)
+
+// DefaultResolverTimeout is the default time the FieldResolvers may take to resolve the values of a request
+const DefaultResolverTimeout = 2 * time.Second
+
+// MaxResolvedValues is the maximum number of values resolved per field, requests with longer lists are denied
+const MaxResolvedValues = 16
+
+// FieldResolver verifies field values against an external inventory, e.g. clusters against a region registry
+// or projects against a project service, see pkg/resolver. Resolvers catch typos that pass the schema patterns.
+type FieldResolver interface {
+	// Resolve returns whether the value exists, an error denies the request
+	Resolve(ctx context.Context, value string) (bool, error)
+}
+
+// WithFieldResolver verifies the values of the field with the resolver after schema validation
+func WithFieldResolver(field string, resolver FieldResolver) Option {
+	return func(s *WebhookServer) {
+		if s.resolvers == nil {
+			s.resolvers = map[string]FieldResolver{}
+		}
+		s.resolvers[field] = resolver
+	}
+}
+
+// WithResolverTimeout limits the time the FieldResolvers may take for all values of a request,
+// DefaultResolverTimeout by default
+func WithResolverTimeout(timeout time.Duration) Option {
+	return func(s *WebhookServer) {
+		s.resolverTimeout = timeout
+	}
+}
+
+// resolveFields verifies the fields with resolvers and returns a denial listing all unknown values.
+// Wildcards are not resolved, the items of list values are resolved individually. All values are resolved
+// concurrently within one resolver timeout, so lists cannot delay the admission beyond it.
+func (s *WebhookServer) resolveFields(ctx context.Context, log *logrus.Entry, parsedCCRN *apis.ParsedResource) *denial {
+	fields := make([]string, 0, len(s.resolvers))
+	for field := range s.resolvers {
+		if _, exists := parsedCCRN.Fields[field]; exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	type resolution struct {
+		field, value string
+		exists       bool
+		err          error
+	}
+	var resolutions []*resolution
+	for _, field := range fields {
+		values := []string{parsedCCRN.Fields[field]}
+		if items, isList := apis.ListValue(values[0]); isList {
+			values = items
+		}
+		if len(values) > MaxResolvedValues {
+			return deny(DenyReasonTooComplex, parsedCCRN, "Field %s has %d values, at most %d can be resolved", field, len(values), MaxResolvedValues)
+		}
+		for _, value := range values {
+			if value != apis.Wildcard {
+				resolutions = append(resolutions, &resolution{field: field, value: value})
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.resolverTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, r := range resolutions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.exists, r.err = s.resolveField(ctx, r.field, r.value)
+		}()
+	}
+	wg.Wait()
+
+	var unknown []string
+	for _, r := range resolutions {
+		if r.err != nil {
+			return deny(DenyReasonBackendError, parsedCCRN, "Failed to resolve field %s: %v", r.field, r.err)
+		}
+		if !r.exists {
+			unknown = append(unknown, fmt.Sprintf("%s=%s", r.field, r.value))
+		}
+	}
+	if len(unknown) > 0 {
+		return deny(DenyReasonUnknownValue, parsedCCRN, "Unknown field values: %s", strings.Join(unknown, ", "))
+	}
+	log.Debugf("Resolved fields %s", strings.Join(fields, ", "))
+	return nil
+}
+
+// resolveField resolves a single value within the deadline of the context
+func (s *WebhookServer) resolveField(ctx context.Context, field, value string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "resolver.Resolve", attribute.String("ccrn.field", field))
+	exists, err := s.resolvers[field].Resolve(ctx, value)
+	tracing.EndSpan(span, err)
+	recordResolution(field, exists, err)
+	return exists, err
+}
@@ -7,152 +7,461 @@
 package webhook
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/audit"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/conversion"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/featuregate"
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/parser"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/tracing"
 	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/version"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// DefaultMaxRequestBytes is the default limit for admission request bodies. An AdmissionReview carries
+// the object and the old object, each of which can be up to the 1.5 MiB etcd limit.
+const DefaultMaxRequestBytes int64 = 3 * 1024 * 1024
+
 // WebhookServer implements the admission webhook for CCRN validation
 type WebhookServer struct {
-	log       *logrus.Logger
-	validator *validation.CCRNValidator
-	backend   apis.ValidationBackend
-	parser    *parser.ResourceParser
+	log             *logrus.Logger
+	validator       *validation.CCRNValidator
+	backend         apis.ValidationBackend
+	parser          *parser.ResourceParser
+	converter       *conversion.Converter
+	maxRequestBytes int64
+	authorizer      Authorizer
+	auditSink       audit.Sink
+	requestRecorder replay.Recorder
+	pseudonymKey    []byte
+	recorder        record.EventRecorder
+	namespaceEvents bool
+	featureGate     *featuregate.FeatureGate
+	urnPrefixes     []string
+	aliases         apis.Aliases
+	keepLast        bool
+	limits          apis.Limits
+	policyEngines   []PolicyEngine
+	resolvers       map[string]FieldResolver
+	resolverTimeout time.Duration
+	annotation      *workloadAnnotation
+	logSampling     int
+	logSequence     atomic.Uint64
+	bundleDigest    string
+	bundleErr       atomic.Pointer[error] // Result of the last VerifyBundleDigest
+
+	namespaceConfigs NamespaceConfigs
 }
 
-// NewWebhookServer creates a new webhook server using the provided validation backend
-func NewWebhookServer(log *logrus.Logger, backend apis.ValidationBackend) (*WebhookServer, error) {
-	server := &WebhookServer{
-		log:       log,
-		validator: validation.NewCCRNValidator(backend),
-		backend:   backend,
-		parser:    parser.NewResourceParser(log, backend),
+// Option configures optional behavior of the WebhookServer
+type Option func(*WebhookServer)
+
+// WithMaxRequestBytes limits the size of admission request bodies, larger requests are denied
+func WithMaxRequestBytes(maxRequestBytes int64) Option {
+	return func(s *WebhookServer) {
+		s.maxRequestBytes = maxRequestBytes
 	}
+}
 
-	return server, nil
+// WithAuthorizer verifies that the requesting user may manage the resource type referenced by a CCRN
+func WithAuthorizer(authorizer Authorizer) Option {
+	return func(s *WebhookServer) {
+		s.authorizer = authorizer
+	}
 }
 
-// NewWebhookServerFromConfig creates a new webhook server with Kubernetes backend (backward compatibility)
-func NewWebhookServerFromConfig(log *logrus.Logger, ccrnGroup string) (*WebhookServer, error) {
-	// Get in-cluster config
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
+// WithAuditSink records every admission decision in the given audit sink
+func WithAuditSink(sink audit.Sink) Option {
+	return func(s *WebhookServer) {
+		s.auditSink = sink
 	}
+}
 
-	// Create Kubernetes backend
-	backend, err := validation.NewKubernetesBackend(config, log, ccrnGroup)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes backend: %w", err)
+// WithRecorder records every admission request, sanitized by replay.Sanitize with the pseudonym key and the paths
+// the handler reads, with its response for replays
+func WithRecorder(recorder replay.Recorder, pseudonymKey []byte) Option {
+	return func(s *WebhookServer) {
+		s.requestRecorder = recorder
+		s.pseudonymKey = pseudonymKey
+	}
+}
+
+// WithFeatureGate replaces the default feature gate, e.g. in tests
+func WithFeatureGate(gate *featuregate.FeatureGate) Option {
+	return func(s *WebhookServer) {
+		s.featureGate = gate
+	}
+}
+
+// WithURNPrefixes sets the accepted "urn:<NID>:" prefixes of URNs, replacing the default "urn:ccrn:"
+func WithURNPrefixes(prefixes ...string) Option {
+	return func(s *WebhookServer) {
+		s.urnPrefixes = prefixes
+	}
+}
+
+// WithTypeAliases resolves short resource types like "pod/v1" in addition to the aliases declared
+// in the CRD annotations, CCRNs using them are rewritten to the full resource type
+func WithTypeAliases(aliases apis.Aliases) Option {
+	return func(s *WebhookServer) {
+		s.aliases = aliases
 	}
+}
 
-	// Start the refresh loop
-	backend.StartRefreshLoop(5 * time.Minute)
+// WithKeepLastDuplicates accepts CCRNs setting a field more than once with a warning, the last value wins.
+// By default they are denied.
+func WithKeepLastDuplicates() Option {
+	return func(s *WebhookServer) {
+		s.keepLast = true
+	}
+}
 
-	return NewWebhookServer(log, backend)
+// WithLimits replaces the default complexity limits of identifiers, apis.DefaultLimits
+func WithLimits(limits apis.Limits) Option {
+	return func(s *WebhookServer) {
+		s.limits = limits
+	}
 }
 
-// Serve starts the webhook server
-func (s *WebhookServer) Serve(port int, certFile, keyFile string) error {
-	// Setup the HTTP server
+// NewWebhookServer creates a new webhook server using the provided validation backend
+func NewWebhookServer(log *logrus.Logger, backend apis.ValidationBackend, opts ...Option) (*WebhookServer, error) {
+	server := &WebhookServer{
+		log:             log,
+		backend:         backend,
+		converter:       conversion.NewConverter(backend),
+		maxRequestBytes: DefaultMaxRequestBytes,
+		featureGate:     featuregate.Default,
+		resolverTimeout: DefaultResolverTimeout,
+		limits:          apis.DefaultLimits,
+	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+
+	parserOpts := []parser.Option{parser.WithLimits(server.limits)}
+	if server.featureGate.Enabled(featuregate.StrictParsing) {
+		parserOpts = append(parserOpts, parser.WithStrictMode())
+	}
+	if server.featureGate.Enabled(featuregate.HomoglyphScreening) {
+		parserOpts = append(parserOpts, parser.WithHomoglyphCheck())
+	}
+	if server.featureGate.Enabled(featuregate.SunsetEnforcement) {
+		parserOpts = append(parserOpts, parser.WithSunsetEnforcement())
+	}
+	if len(server.urnPrefixes) > 0 {
+		parserOpts = append(parserOpts, parser.WithURNPrefixes(server.urnPrefixes...))
+	}
+	if len(server.aliases) > 0 {
+		parserOpts = append(parserOpts, parser.WithAliases(server.aliases))
+	}
+	if server.keepLast {
+		parserOpts = append(parserOpts, parser.WithKeepLastDuplicates())
+	}
+	server.validator = validation.NewCCRNValidator(backend, parserOpts...)
+	server.parser = parser.NewResourceParser(log, backend, parserOpts...)
+
+	if server.requestRecorder != nil && len(server.pseudonymKey) == 0 {
+		return nil, errors.New("recording requires a pseudonym key")
+	}
+	if server.bundleDigest != "" {
+		if _, ok := backend.(apis.CRDSource); !ok {
+			return nil, errors.New("the backend cannot be pinned to a schema bundle digest")
+		}
+		_ = server.VerifyBundleDigest()
+	}
+
+	return server, nil
+}
+
+// Handler returns the HTTP routes of the webhook server
+func (s *WebhookServer) Handler() http.Handler {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/validate", s.mutateCCRN)
+	mux.Handle("/validate", s.admissionHandler("/validate", s.handleCombinedRequest))
+	mux.Handle("/annotate", s.admissionHandler("/annotate", s.handleAnnotateRequest))
+	mux.HandleFunc("/convert", s.convert)
 	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/version", s.version)
+	mux.HandleFunc("/schema/diff", s.schemaDiff)
+	mux.Handle("/metrics", promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{}))
+	return mux
+}
 
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: mux,
+// SetupWithManager registers the admission, conversion, version and schema routes with the webhook server of a
+// controller-runtime manager, which takes care of TLS (including certificate reloading) and the server lifecycle.
+// Health probes and metrics are served by the plaintext listeners of the manager, not on the TLS port.
+// It also adds a liveness check and a readiness check that passes once the webhook server serves, and one
+// that fails while the CRDs do not match the pinned schema bundle, see WithBundleDigest.
+func (s *WebhookServer) SetupWithManager(mgr manager.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register("/validate", s.admissionHandler("/validate", s.handleCombinedRequest))
+	server.Register("/annotate", s.admissionHandler("/annotate", s.handleAnnotateRequest))
+	server.Register("/convert", http.HandlerFunc(s.convert))
+	server.Register("/version", http.HandlerFunc(s.version))
+	server.Register("/schema/diff", http.HandlerFunc(s.schemaDiff))
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return fmt.Errorf("failed to add health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("webhook", server.StartedChecker()); err != nil {
+		return fmt.Errorf("failed to add readiness check: %w", err)
+	}
+	if s.bundleDigest != "" {
+		if err := mgr.AddReadyzCheck("bundle", s.bundleChecker); err != nil {
+			return fmt.Errorf("failed to add bundle readiness check: %w", err)
+		}
 	}
+	return nil
+}
 
-	s.log.Infof("Starting webhook server on port %d", port)
-	return server.ListenAndServeTLS(certFile, keyFile)
+// admissionHandler serves the admission requests of the path with the admission.Webhook of controller-runtime,
+// which decodes v1 and v1beta1 AdmissionReviews, recovers from panics in the handler and completes the response
+func (s *WebhookServer) admissionHandler(path string, handle func(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) http.Handler {
+	webhook := &admission.Webhook{
+		Handler: admission.HandlerFunc(func(ctx context.Context, request admission.Request) admission.Response {
+			response := handle(ctx, &request.AdmissionRequest)
+			response.UID = request.UID
+			s.record(ctx, path, &request.AdmissionRequest, response)
+			return admission.Response{AdmissionResponse: *response}
+		}),
+		// Continue a trace started by the API server, if any
+		WithContextFunc: func(ctx context.Context, r *http.Request) context.Context {
+			return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.checkAdmissionRequest(w, r) {
+			webhook.ServeHTTP(w, r)
+		}
+	})
 }
 
-// mutateCCRN is the HTTP handler for webhook mutation requests
-func (s *WebhookServer) mutateCCRN(w http.ResponseWriter, r *http.Request) {
-	// Read the AdmissionReview from the request
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		s.log.Errorf("Failed to read request body: %v", err)
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
+// checkAdmissionRequest rejects anything that cannot be an AdmissionReview early and limits the body to
+// the configured size, see WithMaxRequestBytes. It reports whether the request can be decoded.
+func (s *WebhookServer) checkAdmissionRequest(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return false
 	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return false
+	}
+	// admission.Webhook accepts the bare media type only
+	r.Header.Set("Content-Type", mediaType)
 
-	// Decode the AdmissionReview
-	admissionReview := admissionv1.AdmissionReview{}
-	if err := json.Unmarshal(body, &admissionReview); err != nil {
-		s.log.Errorf("Failed to parse AdmissionReview: %v", err)
-		http.Error(w, "Failed to parse AdmissionReview", http.StatusBadRequest)
-		return
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.rejectMalformed(w, apierrors.NewRequestEntityTooLargeError(
+				fmt.Sprintf("request body exceeds the limit of %d bytes", maxBytesErr.Limit)))
+			return false
+		}
+		s.rejectMalformed(w, apierrors.NewBadRequest(fmt.Sprintf("failed to read request body: %v", err)))
+		return false
 	}
 
-	// Process the AdmissionRequest
-	admissionResponse := s.handleCombinedRequest(admissionReview.Request)
+	// admission.Webhook answers reviews it cannot decode with an allowed=false response without UID, which
+	// the API server rejects as invalid, so they get an HTTP error here
+	var review struct {
+		Request *struct {
+			UID string `json:"uid"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(body, &review); err != nil {
+		s.rejectMalformed(w, apierrors.NewBadRequest(fmt.Sprintf("failed to parse AdmissionReview: %v", err)))
+		return false
+	}
+	if review.Request == nil || review.Request.UID == "" {
+		s.rejectMalformed(w, apierrors.NewBadRequest("AdmissionReview does not contain a request"))
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
 
-	admissionResponse.UID = admissionReview.Request.UID
-	admissionReview.Response = admissionResponse
+// rejectMalformed answers a malformed admission request with the HTTP status of the error and a Status
+// carrying its reason. There is no request UID to echo, so the API server reports the webhook call as failed
+// with the message instead of a denial.
+func (s *WebhookServer) rejectMalformed(w http.ResponseWriter, statusErr *apierrors.StatusError) {
+	status := statusErr.Status()
+	s.log.WithField("code", status.Code).Warnf("Rejecting malformed admission request: %s", status.Message)
 
-	respBytes, err := json.Marshal(admissionReview)
+	body, err := json.Marshal(status)
 	if err != nil {
-		s.log.Errorf("Failed to marshal response: %v", err)
-		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
+		http.Error(w, status.Message, int(status.Code))
 		return
 	}
-
 	w.Header().Set("Content-Type", "application/json")
-	_, err = w.Write(respBytes)
-	if err != nil {
+	w.WriteHeader(int(status.Code))
+	if _, err := w.Write(body); err != nil {
 		s.log.Errorf("Failed to write response: %v", err)
 	}
 }
 
+// requestLogger returns a logger carrying the correlation fields of an admission request,
+// so all log lines of one request can be joined in the log pipeline
+func (s *WebhookServer) requestLogger(request *admissionv1.AdmissionRequest) *logrus.Entry {
+	return s.log.WithFields(logrus.Fields{
+		"uid":       request.UID,
+		"kind":      request.Kind.Kind,
+		"namespace": request.Namespace,
+		"name":      request.Name,
+		"operation": request.Operation,
+	})
+}
+
 // handleCombinedRequest orchestrates the validation, mutation, and resource creation
-func (s *WebhookServer) handleCombinedRequest(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
-	s.log.Infof("Handling combined request for %s/%s", request.Namespace, request.Name)
+func (s *WebhookServer) handleCombinedRequest(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	ctx, span := tracing.StartSpan(ctx, "webhook.Admit",
+		attribute.String("admission.uid", string(request.UID)),
+		attribute.String("admission.namespace", request.Namespace),
+		attribute.String("admission.operation", string(request.Operation)),
+	)
+	defer span.End()
+
+	start := time.Now()
+	log := s.requestLogger(request)
+	log.Debugf("Handling combined request for %s/%s", request.Namespace, request.Name)
+	if request.Operation == admissionv1.Delete {
+		// Deletions are never rejected, derived resources are deleted by the cleanup controller, see package cleanup
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	// URN of the resource for the audit events, the one in the request or the one added by the mutation
+	var urn string
+	denied := func(d *denial) *admissionv1.AdmissionResponse {
+		s.logDecision(log.WithFields(logrus.Fields{"reason": d.reason, "message": d.message}), DecisionDenied, start, "Denied CCRN")
+		span.SetAttributes(attribute.Bool("admission.allowed", false), attribute.String("admission.deny_reason", d.reason))
+		recordDenial(request, d, time.Since(start))
+		s.audit(ctx, log, request, d.parsed, urn, audit.DecisionDenied, d.reason, d.message, nil, time.Since(start))
+		s.recordEvent(request, corev1.EventTypeWarning, EventReasonDenied, "%s", d.message)
+		return d.response()
+	}
+
+	// Fail closed while the CRDs differ from the pinned schema bundle
+	if err := s.bundleMismatch(); err != nil {
+		return denied(deny(DenyReasonBundleMismatch, nil, "CRDs do not match the pinned schema bundle: %v", err))
+	}
 
 	// Parse the CCRN resource
 	ccrn := &apis.CCRN{}
 	if err := json.Unmarshal(request.Object.Raw, ccrn); err != nil {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Status:  "Failure",
-				Message: fmt.Sprintf("Failed to parse CCRN resource: %v", err),
-			},
-		}
+		return denied(deny(DenyReasonParseError, nil, "Failed to parse CCRN resource: %v", err))
 	}
+	urn = ccrn.Spec.URN
+	if ccrn.DeletionTimestamp != nil {
+		// Updates of objects being deleted, e.g. removing finalizers, must neither be rejected nor derive resources
+		log.Debug("Admitting update of CCRN resource being deleted")
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	// Add the defaults of the namespace to the CCRN before validating it, the original stays for the patches
+	config := s.namespaceConfig(request.Namespace)
+	validated := *ccrn
+	validated.Spec.CCRN = s.applyDefaults(log, config, ccrn.Spec.CCRN)
 
 	// 1. Basic Validation
-	parsedCCRN, validationResponse := s.validateFormats(ccrn)
-	if validationResponse != nil {
-		return validationResponse
+	parsedCCRN, warnings, d := s.validateFormats(ctx, log, &validated)
+	if d != nil {
+		return denied(d)
+	}
+	log = log.WithField("ccrn", parsedCCRN.CCRNKey())
+	span.SetAttributes(attribute.String("ccrn.key", parsedCCRN.CCRNKey()))
+	s.recordDeprecationEvent(request, parsedCCRN)
+
+	// Make sure the user may register identifiers for the referenced resource type
+	if d := s.authorize(ctx, log, request, parsedCCRN); d != nil {
+		return denied(d)
+	}
+
+	// Verify fields against external inventories
+	if len(s.resolvers) > 0 {
+		if d := s.resolveFields(ctx, log, parsedCCRN); d != nil {
+			return denied(d)
+		}
+	}
+
+	// Enforce the groups and policies of the namespace, unless the request is exempted
+	if !exempted(config, request.UserInfo, parsedCCRN) {
+		if d := enforceGroup(config, request.Namespace, parsedCCRN); d != nil {
+			if !config.WarnOnly() {
+				return denied(d)
+			}
+			warnings = append(warnings, d.message)
+		}
+
+		// Evaluate the organization policies against the validated resource
+		if len(s.policyEngines) > 0 {
+			policyWarnings, d := s.evaluatePolicies(ctx, log, newPolicyInput(request, ccrn, parsedCCRN))
+			switch {
+			case d != nil && d.reason == DenyReasonPolicyViolation && config.WarnOnly():
+				warnings = append(warnings, d.message)
+			case d != nil:
+				return denied(d)
+			}
+			warnings = append(warnings, policyWarnings...)
+		}
+	} else {
+		log.Debugf("Request of %s is exempted from the enforcement of namespace %s", request.UserInfo.Username, request.Namespace)
 	}
 
 	// 2. Mutation (if needed)
-	patches, mutated := s.generateMutationPatches(ccrn, parsedCCRN)
+	patches, mutated := s.generateMutationPatches(ctx, log, ccrn, parsedCCRN)
+	if added, ok := patchValue(patches, "/spec/urn").(string); ok {
+		urn = added
+	}
 
 	// 3. Target Resource Creation/Validation
-	if err := s.backend.ValidateResource(request.Namespace, parsedCCRN); err != nil {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Status:  "Failure",
-				Message: fmt.Sprintf("Resource validation failed: %v", err),
-			},
+	log.Debug("Validating target resource with backend")
+	_, backendSpan := tracing.StartSpan(ctx, "backend.ValidateResource", attribute.String("ccrn.key", parsedCCRN.CCRNKey()))
+	err := s.validateResource(log, request, ccrn, parsedCCRN)
+	tracing.EndSpan(backendSpan, err)
+	if err != nil {
+		reason, parsed := DenyReasonBackendError, parsedCCRN
+		switch {
+		case errors.Is(err, apis.ErrSchemaViolation), apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+			reason = DenyReasonSchemaViolation
+		case errors.Is(err, apis.ErrUnsupportedType):
+			reason, parsed = DenyReasonUnsupportedType, nil
 		}
+		return denied(deny(reason, parsed, "Resource validation failed: %s", strings.Join(apis.ErrorMessages(err), "; ")))
+	}
+	if patch := s.finalizerPatch(request, ccrn); patch != nil {
+		patches = append(patches, patch)
+		mutated = true
 	}
 
 	// Build the final success response with any patches for mutation
@@ -164,153 +473,198 @@ func (s *WebhookServer) handleCombinedRequest(request *admissionv1.AdmissionRequ
 		},
 	}
 
+	response.Warnings = warnings
+
 	if mutated {
 		patchBytes, err := json.Marshal(patches)
 		if err != nil {
-			s.log.Errorf("Failed to marshal patches: %v", err)
+			log.Errorf("Failed to marshal patches: %v", err)
 		} else {
 			pt := admissionv1.PatchTypeJSONPatch
 			response.Patch = patchBytes
 			response.PatchType = &pt
 			response.Result.Message = "CCRN is valid, missing format added, and target resource created"
+			recordMutations(parsedCCRN, patches)
+			s.recordMutationEvent(request, patches)
 		}
 	}
 
+	s.logDecision(log, DecisionAllowed, start, "Allowed CCRN")
+	span.SetAttributes(attribute.Bool("admission.allowed", true))
+	recordAdmission(request, parsedCCRN, true, time.Since(start))
+	s.audit(ctx, log, request, parsedCCRN, urn, audit.DecisionAllowed, "", response.Result.Message, response.Patch, time.Since(start))
 	return response
 }
 
-// validateFormats performs basic validation of the CCRN and URN formats
-func (s *WebhookServer) validateFormats(ccrn *apis.CCRN) (*apis.ParsedResource, *admissionv1.AdmissionResponse) {
-	if ccrn.Spec.CCRN == "" && ccrn.Spec.URN == "" {
-		return nil, &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Status:  "Failure",
-				Message: "Resource must have either spec.ccrn or spec.urn defined",
-			},
+// denial describes why an admission request is rejected
+type denial struct {
+	reason  string               // Coarse deny reason used for metrics, see DenyReason constants
+	message string               // Message returned to the user
+	parsed  *apis.ParsedResource // Parsed resource, if parsing got that far
+}
+
+// deny creates a new denial
+func deny(reason string, parsed *apis.ParsedResource, format string, args ...any) *denial {
+	return &denial{reason: reason, message: fmt.Sprintf(format, args...), parsed: parsed}
+}
+
+// response converts the denial into an AdmissionResponse
+func (d *denial) response() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  "Failure",
+			Message: d.message,
+		},
+	}
+}
+
+// validationDenial classifies a failed validator result. Denials of unsupported resource types carry no parsed
+// resource, so that the kind and version given by the user do not become metric labels.
+func (s *WebhookServer) validationDenial(result *apis.ValidationResult, err error, prefix string) *denial {
+	var parsed *apis.ParsedResource
+	if result != nil {
+		parsed = result.ParsedCCRN
+	}
+	if err != nil {
+		message := strings.Join(apis.ErrorMessages(err), "; ")
+		switch {
+		case errors.Is(err, apis.ErrUnsupportedType), errors.Is(err, apis.ErrCRDNotFound):
+			return deny(DenyReasonUnsupportedType, nil, "%s validation error: %s", prefix, message)
+		case errors.Is(err, apis.ErrVersionSunset):
+			return deny(DenyReasonVersionSunset, parsed, "%s validation error: %s", prefix, message)
+		case tooComplex(err):
+			return deny(DenyReasonTooComplex, nil, "%s validation error: %s", prefix, message)
+		case parsed == nil:
+			return deny(DenyReasonParseError, nil, "%s validation error: %s", prefix, message)
 		}
+		return deny(DenyReasonSchemaViolation, parsed, "%s validation error: %s", prefix, message)
+	}
+	errorMsg := prefix + " is invalid"
+	if len(result.Errors) > 0 {
+		errorMsg += ": " + strings.Join(result.Errors, "; ")
+	}
+	switch {
+	case parsed == nil:
+		return deny(DenyReasonParseError, nil, "%s", errorMsg)
+	case !s.backend.IsResourceTypeSupported(parsed.CCRNKey()):
+		return deny(DenyReasonUnsupportedType, nil, "%s", errorMsg)
+	}
+	return deny(DenyReasonSchemaViolation, parsed, "%s", errorMsg)
+}
+
+// tooComplex reports whether the identifier was rejected for exceeding the complexity limits, see apis.Limits
+func tooComplex(err error) bool {
+	return errors.Is(err, apis.ErrTooManyFields) || errors.Is(err, apis.ErrKeyTooLong) || errors.Is(err, apis.ErrTooDeep)
+}
+
+// validateFormats performs basic validation of the CCRN and URN formats and returns the validation warnings
+func (s *WebhookServer) validateFormats(ctx context.Context, log *logrus.Entry, ccrn *apis.CCRN) (*apis.ParsedResource, []string, *denial) {
+	if ccrn.Spec.CCRN == "" && ccrn.Spec.URN == "" {
+		return nil, nil, deny(DenyReasonParseError, nil, "Resource must have either spec.ccrn or spec.urn defined")
 	}
 
 	var parsed *apis.ParsedResource
+	var warnings []string
 
 	if ccrn.Spec.CCRN != "" {
-		result, err := s.validator.ValidateCCRN(ccrn.Spec.CCRN)
-		if err != nil {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: fmt.Sprintf("CCRN validation error: %v", err),
-				},
-			}
-		}
-		if !result.Valid {
-			errorMsg := "Invalid CCRN format"
-			if len(result.Errors) > 0 {
-				errorMsg = result.Errors[0]
-			}
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: errorMsg,
-				},
-			}
+		log.Debugf("Validating CCRN %s", ccrn.Spec.CCRN)
+		result, err := s.validator.ValidateCCRNContext(ctx, ccrn.Spec.CCRN)
+		if err != nil || !result.Valid {
+			return nil, nil, s.validationDenial(result, err, "CCRN")
 		}
-		parsed = result.ParsedCCRN
+		parsed, warnings = result.ParsedCCRN, result.Warnings
 	} else {
-		// URN path: get URN template from backend, parse URN, extract CCRN, validate
-		// We need the CRD name and version to get the template. Assume URN is in the form urn:ccrn:<crd>/<version>/...
-		// We'll extract the CRD name and version from the URN string.
-		parts := strings.Split(strings.TrimPrefix(ccrn.Spec.URN, "urn:ccrn:"), "/")
+		// URN path: parse the URN with the URN templates of its resource type, its current template followed by
+		// its legacy ones, and validate the CCRN of its fields. The URN is in the form urn:<NID>:<crd>/<version>/...
+		if err := apis.CheckLength(ccrn.Spec.URN, s.parser.MaxLength()); err != nil {
+			return nil, nil, deny(DenyReasonParseError, nil, "Failed to parse URN: %v", err)
+		}
+		_, nss, _ := apis.SplitURNPrefix(ccrn.Spec.URN)
+		parts := strings.Split(nss, "/")
 		if len(parts) < 2 {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: "URN does not contain enough segments to determine CRD and version",
-				},
-			}
+			return nil, nil, deny(DenyReasonParseError, nil, "URN does not contain enough segments to determine CRD and version")
 		}
-		crdName := parts[0]
-		version := parts[1]
+		crdName, version, _ := strings.Cut(s.parser.ResolveKey(parts[0]+"/"+parts[1]), "/")
+		log.Debugf("Looking up URN template for %s/%s", crdName, version)
+		_, span := tracing.StartSpan(ctx, "backend.GetURNTemplate", attribute.String("ccrn.key", crdName+"/"+version))
 		urnTemplate, err := s.backend.GetURNTemplate(crdName, version)
+		tracing.EndSpan(span, err)
 		if err != nil {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: fmt.Sprintf("Failed to get URN template: %v", err),
-				},
-			}
+			return nil, nil, deny(DenyReasonBackendError, nil, "Failed to get URN template: %v", err)
 		}
-		parsed, err = s.parser.Parse(ccrn.Spec.URN, urnTemplate)
-		if err != nil {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: fmt.Sprintf("Failed to parse URN: %v", err),
-				},
-			}
+		templates := []string{urnTemplate}
+		crdInfo, err := s.backend.GetCRD(crdName + "/" + version)
+		if err == nil {
+			templates = crdInfo.URNFormats()
 		}
-
-		ccrnValue, err := s.parser.ExtractCCRNKeyFromURN(ccrn.Spec.URN)
+		log.Debugf("Parsing URN %s with templates %s", ccrn.Spec.URN, strings.Join(templates, ", "))
+		parsedURN, err := apis.ParseURNWithTemplates(ccrn.Spec.URN, templates...)
 		if err != nil {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: fmt.Sprintf("Failed to extract CCRN from URN: %v", err),
-				},
-			}
+			return nil, nil, deny(DenyReasonParseError, nil, "Failed to parse URN: %v", err)
 		}
-		result, err := s.validator.ValidateCCRN(ccrnValue)
-		if err != nil {
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: fmt.Sprintf("Derived CCRN validation error: %v", err),
-				},
-			}
+		if crdInfo != nil && parsedURN.UrnTemplate != crdInfo.URNFormat {
+			parsedURN.Fields = crdInfo.LegacyURNMapping.Apply(parsedURN.Fields)
 		}
-		if !result.Valid {
-			errorMsg := "Derived CCRN is invalid"
-			if len(result.Errors) > 0 {
-				errorMsg += ": " + result.Errors[0]
-			}
-			return nil, &admissionv1.AdmissionResponse{
-				Allowed: false,
-				Result: &metav1.Status{
-					Status:  "Failure",
-					Message: errorMsg,
-				},
-			}
+		parsedURN.Fields["ccrn"] = crdName + "/" + version
+
+		// The fields of the URN go through the same checks and schema validation as a CCRN
+		derived := parsedURN.CanonicalCCRN()
+		log.Debugf("Validating derived CCRN %s", derived)
+		result, err := s.validator.ValidateCCRNContext(ctx, derived)
+		if err != nil || !result.Valid {
+			return nil, nil, s.validationDenial(result, err, "Derived CCRN")
 		}
-		parsed = result.ParsedCCRN
+		parsed, warnings = result.ParsedCCRN, result.Warnings
 	}
-	return parsed, nil
+	return parsed, warnings, nil
+}
+
+// authorize checks with the configured Authorizer whether the requesting user may manage the target CCRN kind
+func (s *WebhookServer) authorize(ctx context.Context, log *logrus.Entry, request *admissionv1.AdmissionRequest, parsedCCRN *apis.ParsedResource) *denial {
+	if s.authorizer == nil {
+		return nil
+	}
+
+	crdInfo, err := s.backend.GetCRD(parsedCCRN.CCRNKey())
+	if err != nil {
+		return deny(DenyReasonBackendError, parsedCCRN, "Failed to get CCRN definition for %s: %v", parsedCCRN.CCRNKey(), err)
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "webhook.Authorize", attribute.String("ccrn.key", parsedCCRN.CCRNKey()))
+	allowed, reason, err := s.authorizer.Authorize(ctx, request.UserInfo, request.Namespace, crdInfo)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return deny(DenyReasonBackendError, parsedCCRN, "Failed to authorize user %s: %v", request.UserInfo.Username, err)
+	}
+	if !allowed {
+		return deny(DenyReasonUnauthorized, parsedCCRN, "Not allowed to register identifiers for %s: %s", parsedCCRN.CCRNKey(), reason)
+	}
+
+	log.Debugf("User %s is authorized for %s", request.UserInfo.Username, parsedCCRN.CCRNKey())
+	return nil
 }
 
 // generateMutationPatches creates mutation patches if a format is missing
-func (s *WebhookServer) generateMutationPatches(ccrn *apis.CCRN, parsedCCRN *apis.ParsedResource) ([]map[string]any, bool) {
+func (s *WebhookServer) generateMutationPatches(ctx context.Context, log *logrus.Entry, ccrn *apis.CCRN, parsedCCRN *apis.ParsedResource) ([]map[string]any, bool) {
 	patches := []map[string]any{}
 
 	// Case A: Has CCRN, need to potentially add URN
 	if ccrn.Spec.CCRN != "" && ccrn.Spec.URN == "" {
-		s.log.Infof("CCRN is present, generating URN from CCRN")
+		log.Debugf("CCRN is present, generating URN from CCRN")
+		_, span := tracing.StartSpan(ctx, "backend.GetURNTemplate", attribute.String("ccrn.key", parsedCCRN.CCRNKey()))
 		template, err := s.backend.GetURNTemplate(parsedCCRN.CCRNName(), parsedCCRN.Version())
+		tracing.EndSpan(span, err)
 		if err != nil {
-			s.log.Errorf("Failed to get URN template for %s/%s: %v", parsedCCRN.ApiGroup(), parsedCCRN.Version(), err)
+			log.Errorf("Failed to get URN template for %s/%s: %v", parsedCCRN.ApiGroup(), parsedCCRN.Version(), err)
 			return nil, false
 		}
-		urn := parsedCCRN.URN(template)
-		if urn == "" {
-			s.log.Errorf("Failed to generate URN from CCRN.")
+		urn, err := apis.BuildURN(parsedCCRN, template)
+		if err != nil {
+			log.Errorf("Failed to generate URN from CCRN: %v", err)
 			return nil, false
 		}
-		s.log.Infof("URN generated: %s", urn)
+		log.Debugf("URN generated: %s", urn)
 		patches = append(patches, map[string]any{
 			"op":    "add",
 			"path":  "/spec/urn",
@@ -319,16 +673,14 @@ func (s *WebhookServer) generateMutationPatches(ccrn *apis.CCRN, parsedCCRN *api
 
 		// Case B: Has URN but no CCRN, add CCRN
 	} else if ccrn.Spec.URN != "" && ccrn.Spec.CCRN == "" {
-		s.log.Infof("URN is present, generating CCRN from URN")
-		// Validate URN and derive CCRN
-		parsedURN, err := s.parser.Parse(ccrn.Spec.URN, parser.DEFAULT_URN_TEMPLATE) // Use default template to get the ccrn field
-		if err != nil {
-			s.log.Errorf("Failed to parse URN using default template: %v", err)
-			return nil, false
+		log.Debugf("URN is present, generating CCRN from URN")
+		// The validated resource was parsed from the URN, so it holds the fields of the CCRN
+		ccrnValue := parsedCCRN.CCRN()
+		if s.featureGate.Enabled(featuregate.CanonicalMutation) {
+			ccrnValue = parsedCCRN.CanonicalCCRN()
 		}
-		ccrnValue := parsedURN.CCRN()
 
-		s.log.Infof("CCRN generated: %s", ccrnValue)
+		log.Debugf("CCRN generated: %s", ccrnValue)
 		patches = append(patches, map[string]any{
 			"op":    "add",
 			"path":  "/spec/ccrn",
@@ -336,9 +688,67 @@ func (s *WebhookServer) generateMutationPatches(ccrn *apis.CCRN, parsedCCRN *api
 		})
 	}
 
+	// Rewrite a present CCRN into its canonical form, always if it uses a type alias or the latest version,
+	// or if defaults were added
+	if ccrn.Spec.CCRN != "" && (s.featureGate.Enabled(featuregate.CanonicalMutation) || keyResolved(ccrn.Spec.CCRN, parsedCCRN) || fieldsDefaulted(ccrn.Spec.CCRN, parsedCCRN)) {
+		if canonical := parsedCCRN.CanonicalCCRN(); canonical != ccrn.Spec.CCRN {
+			log.Debugf("Canonicalizing CCRN to %s", canonical)
+			patches = append(patches, map[string]any{
+				"op":    "replace",
+				"path":  "/spec/ccrn",
+				"value": canonical,
+			})
+		}
+	}
+
+	if s.featureGate.Enabled(featuregate.LabelMutation) {
+		patches = append(patches, labelPatches(ccrn.Labels, parsedCCRN.Labels())...)
+	}
+
 	return patches, len(patches) > 0
 }
 
+// patchValue returns the value the patches set at the path, or nil if none of them does
+func patchValue(patches []map[string]any, path string) any {
+	for _, patch := range patches {
+		if patch["path"] == path {
+			return patch["value"]
+		}
+	}
+	return nil
+}
+
+// labelPatches creates the patches setting the labels that are missing or differ from the current labels
+func labelPatches(current, desired map[string]string) []map[string]any {
+	if len(current) == 0 {
+		return []map[string]any{{"op": "add", "path": "/metadata/labels", "value": desired}}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		if existing, ok := current[key]; !ok || existing != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	patches := make([]map[string]any, 0, len(keys))
+	for _, key := range keys {
+		patches = append(patches, map[string]any{
+			"op":    "add",
+			"path":  "/metadata/labels/" + escapeJSONPointer(key),
+			"value": desired[key],
+		})
+	}
+	return patches
+}
+
+// keyResolved reports whether the CCRN was written with a type alias or the latest version that got resolved
+func keyResolved(ccrn string, parsed *apis.ParsedResource) bool {
+	written, err := apis.ParseCCRN(ccrn)
+	return err == nil && written.CCRNKey() != parsed.CCRNKey()
+}
+
 // healthz is the health check endpoint
 func (s *WebhookServer) healthz(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
@@ -346,3 +756,44 @@ func (s *WebhookServer) healthz(w http.ResponseWriter, r *http.Request) {
 		s.log.Errorf("Failed to write response: %v", err)
 	}
 }
+
+// version is the build information endpoint
+func (s *WebhookServer) version(w http.ResponseWriter, r *http.Request) {
+	respBytes, err := json.Marshal(version.Get())
+	if err != nil {
+		s.log.Errorf("Failed to marshal version info: %v", err)
+		http.Error(w, "Failed to marshal version info", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(respBytes); err != nil {
+		s.log.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// schemaDiff serves how the last refresh changing the CRD set of the backend changed it as JSON, see
+// apis.SchemaDiff. It answers 204 No Content until a refresh changed the CRD set.
+func (s *WebhookServer) schemaDiff(w http.ResponseWriter, r *http.Request) {
+	source, ok := s.backend.(apis.SchemaDiffSource)
+	if !ok {
+		http.Error(w, "The backend does not report schema changes", http.StatusNotFound)
+		return
+	}
+	diff := source.LastSchemaDiff()
+	if diff == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	respBytes, err := json.Marshal(diff)
+	if err != nil {
+		s.log.Errorf("Failed to marshal schema diff: %v", err)
+		http.Error(w, "Failed to marshal schema diff", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(respBytes); err != nil {
+		s.log.Errorf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,1269 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/audit"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/bundle"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/featuregate"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/namespaceconfig"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/replay"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+// denyAllAuthorizer is an Authorizer rejecting every user
+type denyAllAuthorizer struct{}
+
+func (denyAllAuthorizer) Authorize(_ context.Context, user authenticationv1.UserInfo, _ string, crd *apis.CRDInfo) (bool, string, error) {
+	return false, user.Username + " may not create " + crd.Plural, nil
+}
+
+// policyEngineFunc is a PolicyEngine calling a function
+type policyEngineFunc func(input *webhook.PolicyInput) *webhook.PolicyDecision
+
+func (f policyEngineFunc) Evaluate(_ context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	return f(input), nil
+}
+
+// staticPolicyEngine is a PolicyEngine returning the same decision for every input and keeping the inputs
+type staticPolicyEngine struct {
+	decision webhook.PolicyDecision
+	inputs   []*webhook.PolicyInput
+}
+
+func (e *staticPolicyEngine) Evaluate(_ context.Context, input *webhook.PolicyInput) (*webhook.PolicyDecision, error) {
+	e.inputs = append(e.inputs, input)
+	return &e.decision, nil
+}
+
+// resolverFunc is a FieldResolver calling a function
+type resolverFunc func(ctx context.Context, value string) (bool, error)
+
+func (f resolverFunc) Resolve(ctx context.Context, value string) (bool, error) {
+	return f(ctx, value)
+}
+
+// recordingSink is an audit.Sink keeping all events in memory
+type recordingSink struct {
+	events []audit.Event
+}
+
+func (r *recordingSink) Write(_ context.Context, event audit.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingSink) Close() error {
+	return nil
+}
+
+// derivingBackend is a backend recording the owners of the resources it derives and deletes
+type derivingBackend struct {
+	*validation.FilesystemBackend
+	owned   []metav1.OwnerReference
+	deleted []metav1.OwnerReference
+	dryRuns int
+	uids    []any
+}
+
+func (b *derivingBackend) ValidateResourceDryRun(_ *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource) error {
+	b.dryRuns++
+	return b.ValidateResource(namespace, parsedCCRN)
+}
+
+func (b *derivingBackend) ValidateOwnedResource(log *logrus.Entry, namespace string, parsedCCRN *apis.ParsedResource, owner metav1.OwnerReference) error {
+	b.owned = append(b.owned, owner)
+	b.uids = append(b.uids, log.Data["uid"])
+	return b.ValidateResource(namespace, parsedCCRN)
+}
+
+func (b *derivingBackend) DeleteDerivedResources(_ *logrus.Entry, _ string, owner metav1.OwnerReference) error {
+	b.deleted = append(b.deleted, owner)
+	return nil
+}
+
+// memoryRecorder is a replay.Recorder keeping all records in memory
+type memoryRecorder struct {
+	records []replay.Record
+}
+
+func (m *memoryRecorder) Record(_ context.Context, record replay.Record) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *memoryRecorder) Close() error {
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Suite")
+}
+
+// admissionReviewFor wraps a CCRN object into an AdmissionReview request body
+func admissionReviewFor(spec apis.CCRNSpec) []byte {
+	raw, err := json.Marshal(apis.CCRN{Spec: spec})
+	Expect(err).ToNot(HaveOccurred())
+	review := admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "test-uid",
+			Namespace: "default",
+			Name:      "test",
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+	body, err := json.Marshal(review)
+	Expect(err).ToNot(HaveOccurred())
+	return body
+}
+
+var _ = Describe("WebhookServer", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithMaxRequestBytes(4096))
+		Expect(err).ToNot(HaveOccurred())
+		handler = server.Handler()
+	})
+
+	post := func(contentType string, body []byte) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// admit posts the body to the validate endpoint and returns the decoded response
+	admit := func(body []byte) *admissionv1.AdmissionResponse {
+		recorder := post("application/json", body)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		review := admissionv1.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &review)).To(Succeed())
+		Expect(review.Response).ToNot(BeNil())
+		return review.Response
+	}
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, err := io.ReadAll(recorder.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return string(body)
+	}
+
+	It("allows a valid CCRN and adds the URN", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+		// Assert
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.UID).To(BeEquivalentTo("test-uid"))
+		Expect(string(response.Patch)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+		Expect(scrape()).To(ContainSubstring(`ccrn_admission_mutations_total{field="urn",kind="pod.k8s-registry.tr.ccrn.example.com",version="v1"}`))
+	})
+
+	It("allows a valid URN and adds the CCRN", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{URN: "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"}))
+		// Assert
+		Expect(response.Allowed).To(BeTrue())
+		Expect(string(response.Patch)).To(ContainSubstring(`"op":"add","path":"/spec/ccrn","value":"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, namespace=default"`))
+	})
+
+	It("resolves type aliases and rewrites the CCRN to the full resource type", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=po/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+		// Assert
+		Expect(response.Allowed).To(BeTrue())
+		Expect(string(response.Patch)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+		Expect(string(response.Patch)).To(ContainSubstring(`"op":"replace","path":"/spec/ccrn","value":"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, namespace=default"`))
+	})
+
+	It("denies a CCRN violating the schema", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!, namespace=default, name=my-pod"}))
+		// Assert
+		Expect(response.Allowed).To(BeFalse())
+		Expect(scrape()).To(ContainSubstring(`ccrn_admission_denials_total{kind="pod.k8s-registry.tr.ccrn.example.com",reason="schema_violation",version="v1"}`))
+	})
+
+	It("denies an unknown resource type", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=unknown.tr.ccrn.example.com/v1, name=my-pod"}))
+		// Assert
+		Expect(response.Allowed).To(BeFalse())
+		metrics := scrape()
+		Expect(metrics).To(ContainSubstring(`ccrn_admission_denials_total{kind="unknown",reason="unsupported_type",version="unknown"}`))
+		Expect(metrics).ToNot(ContainSubstring(`kind="unknown.tr.ccrn.example.com"`))
+	})
+
+	It("denies an unparsable CCRN", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster"}))
+		// Assert
+		Expect(response.Allowed).To(BeFalse())
+		Expect(scrape()).To(ContainSubstring(`ccrn_admission_denials_total{kind="unknown",reason="parse_error",version="unknown"}`))
+	})
+
+	It("answers v1beta1 AdmissionReviews in the same version", func() {
+		// Arrange
+		body := bytes.Replace(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}),
+			[]byte("{"), []byte(`{"apiVersion":"admission.k8s.io/v1beta1","kind":"AdmissionReview",`), 1)
+		// Act
+		recorder := post("application/json", body)
+		// Assert
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		review := admissionv1beta1.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &review)).To(Succeed())
+		Expect(review.APIVersion).To(Equal("admission.k8s.io/v1beta1"))
+		Expect(review.Response.UID).To(BeEquivalentTo("test-uid"))
+		Expect(review.Response.Allowed).To(BeTrue())
+		Expect(string(review.Response.Patch)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+	})
+
+	Context("malformed requests", func() {
+		It("rejects methods other than POST", func() {
+			// Act
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/validate", nil))
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+
+		It("rejects unexpected content types", func() {
+			// Act
+			recorder := post("text/plain", admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1"}))
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusUnsupportedMediaType))
+		})
+
+		// status decodes the Status answering a malformed request
+		status := func(recorder *httptest.ResponseRecorder) metav1.Status {
+			status := metav1.Status{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &status)).To(Succeed())
+			return status
+		}
+
+		It("rejects bodies exceeding the size limit", func() {
+			// Act
+			recorder := post("application/json", admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, name=" + strings.Repeat("a", 5000)}))
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+			Expect(status(recorder)).To(And(
+				HaveField("Code", BeEquivalentTo(http.StatusRequestEntityTooLarge)),
+				HaveField("Reason", Equal(metav1.StatusReasonRequestEntityTooLarge)),
+				HaveField("Message", ContainSubstring("exceeds the limit of 4096 bytes")),
+			))
+		})
+
+		It("rejects bodies that are not an AdmissionReview", func() {
+			// Act
+			recorder := post("application/json", []byte("{not json"))
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(status(recorder).Reason).To(Equal(metav1.StatusReasonBadRequest))
+		})
+
+		It("rejects an AdmissionReview without request", func() {
+			// Act
+			recorder := post("application/json", []byte(`{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`))
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			Expect(status(recorder).Message).To(ContainSubstring("does not contain a request"))
+		})
+	})
+
+	It("denies identifiers exceeding the complexity limits", func() {
+		// Act
+		response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, labels.a.b.c.d.e.f.g.h=x"}))
+		// Assert
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("key labels.a.b.c.d.e.f.g.h is nested 9 levels deep, the maximum is 8"))
+		Expect(scrape()).To(ContainSubstring(`reason="too_complex"`))
+	})
+
+	Context("duplicate fields", func() {
+		It("denies CCRNs setting a field more than once", func() {
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, cluster=eu-de-2"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("expected a single value of cluster"))
+			Expect(scrape()).To(ContainSubstring(`reason="parse_error"`))
+		})
+
+		It("admits them with a warning if the last value wins", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithKeepLastDuplicates())
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod, cluster=eu-de-2"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(Equal([]string{"field cluster is set more than once, the last value wins"}))
+			Expect(string(response.Patch)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-2/default/my-pod"))
+		})
+	})
+
+	Context("schema diff", func() {
+		It("serves how the last refresh changed the CRD set", func() {
+			// Arrange
+			content, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			path := filepath.Join(GinkgoT().TempDir(), "testpod_crd.yaml")
+			Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(path)).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend)
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			get := func() *httptest.ResponseRecorder {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/schema/diff", nil))
+				return recorder
+			}
+			Expect(get().Code).To(Equal(http.StatusNoContent))
+			Expect(os.WriteFile(path, []byte(strings.Replace(string(content), `"eu-de-2", `, "", 1)), 0o600)).To(Succeed())
+			Expect(backend.Refresh()).To(Succeed())
+			// Act
+			recorder := get()
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			diff := apis.SchemaDiff{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &diff)).To(Succeed())
+			Expect(diff.Changed).To(Equal([]apis.TypeChange{{Type: "pod.k8s-registry.tr.ccrn.example.com/v1", FieldsChanged: []string{"cluster"}}}))
+		})
+	})
+
+	Context("authorization", func() {
+		It("denies users not allowed to manage the referenced resource type", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithAuthorizer(denyAllAuthorizer{}))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("may not create pods"))
+			Expect(scrape()).To(ContainSubstring(`reason="unauthorized"`))
+		})
+	})
+
+	Context("policies", func() {
+		var engine *staticPolicyEngine
+
+		BeforeEach(func() {
+			engine = &staticPolicyEngine{}
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithPolicyEngines(engine))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		It("evaluates the policies against the validated resource and the request", func() {
+			// Arrange
+			engine.decision.Warnings = []string{"prefer named clusters"}
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=po/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ContainElement("prefer named clusters"))
+			Expect(engine.inputs).To(HaveLen(1))
+			Expect(engine.inputs[0].Resource.CCRNKey()).To(Equal("pod.k8s-registry.tr.ccrn.example.com/v1"))
+			Expect(engine.inputs[0].Request).To(Equal(webhook.PolicyRequest{Operation: "CREATE", Namespace: "default", Name: "test"}))
+		})
+
+		It("denies resources violating a policy", func() {
+			// Arrange
+			engine.decision.Denials = []string{"wildcard clusters are not allowed", "name too short"}
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=*, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(Equal("Policy violation: wildcard clusters are not allowed; name too short"))
+			Expect(scrape()).To(ContainSubstring(`reason="policy_violation"`))
+		})
+	})
+
+	It("evaluates policies against the fields of URN-only resources", func() {
+		// Arrange
+		backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+		Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		engine := policyEngineFunc(func(input *webhook.PolicyInput) *webhook.PolicyDecision {
+			if input.Resource.Fields["cluster"] == "eu-de-2" {
+				return &webhook.PolicyDecision{Denials: []string{"cluster eu-de-2 is retired"}}
+			}
+			return &webhook.PolicyDecision{}
+		})
+		server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithPolicyEngines(engine))
+		Expect(err).ToNot(HaveOccurred())
+		handler = server.Handler()
+		// Act
+		denied := admit(admissionReviewFor(apis.CCRNSpec{URN: "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-2/default/my-pod"}))
+		allowed := admit(admissionReviewFor(apis.CCRNSpec{URN: "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"}))
+		// Assert
+		Expect(denied.Allowed).To(BeFalse())
+		Expect(denied.Result.Message).To(Equal("Policy violation: cluster eu-de-2 is retired"))
+		Expect(allowed.Allowed).To(BeTrue())
+	})
+
+	Context("namespace configs", func() {
+		var configs *namespaceconfig.Store
+
+		BeforeEach(func() {
+			configs = namespaceconfig.NewStore()
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "defaults_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithNamespaceConfigs(configs))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		setConfig := func(spec apis.CCRNConfigSpec) {
+			configs.Set(&apis.CCRNConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "default"}, Spec: spec})
+		}
+
+		It("adds the defaults of the namespace to CCRNs", func() {
+			// Arrange
+			setConfig(apis.CCRNConfigSpec{Defaults: map[string]string{"cluster": "eu-de-1", "namespace": "other", "region": "eu-de"}})
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(string(response.Patch)).To(ContainSubstring(`"op":"replace","path":"/spec/ccrn","value":"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, namespace=default"`))
+			Expect(string(response.Patch)).To(ContainSubstring("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+		})
+
+		It("adds the defaults of the schema after those of the namespace", func() {
+			// Arrange
+			setConfig(apis.CCRNConfigSpec{Defaults: map[string]string{"cluster": "eu-de-2"}})
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, name=data"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(string(response.Patch)).To(ContainSubstring(`"op":"replace","path":"/spec/ccrn","value":"ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-2, name=data, size=10Gi, zones=[a,b]"`))
+		})
+
+		It("adds the defaults of the schema without namespace config", func() {
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, name=data, size=20Gi"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(string(response.Patch)).To(ContainSubstring(`"value":"ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=data, size=20Gi, zones=[a,b]"`))
+		})
+
+		It("denies resource types of groups that are not enforced", func() {
+			// Arrange
+			setConfig(apis.CCRNConfigSpec{EnforcedGroups: []string{"vault.*", "keystone.*"}})
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(Equal("Resource types of group k8s-registry.tr.ccrn.example.com are not allowed in namespace default, allowed groups: vault.*, keystone.*"))
+		})
+
+		It("only warns in namespaces of strictness Warn", func() {
+			// Arrange
+			setConfig(apis.CCRNConfigSpec{EnforcedGroups: []string{"vault.*"}, Strictness: apis.StrictnessWarn})
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ContainElement(ContainSubstring("are not allowed in namespace default")))
+		})
+
+		It("skips the enforcement for exemptions", func() {
+			// Arrange
+			setConfig(apis.CCRNConfigSpec{
+				EnforcedGroups: []string{"vault.*"},
+				Exemptions:     []apis.CCRNConfigExemption{{ResourceTypes: []string{"pod.k8s-registry.tr.ccrn.example.com"}}},
+			})
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("field resolvers", func() {
+		newHandler := func(clusters webhook.FieldResolver) http.Handler {
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend,
+				webhook.WithFieldResolver("cluster", clusters), webhook.WithResolverTimeout(50*time.Millisecond))
+			Expect(err).ToNot(HaveOccurred())
+			return server.Handler()
+		}
+		knownClusters := resolverFunc(func(_ context.Context, value string) (bool, error) {
+			return value == "eu-de-1" || value == "eu-de-2", nil
+		})
+
+		It("allows known values and wildcards", func() {
+			// Arrange
+			handler = newHandler(knownClusters)
+			// Act & Assert
+			for _, cluster := range []string{"eu-de-1", "*"} {
+				response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=" + cluster + ", namespace=default, name=my-pod"}))
+				Expect(response.Allowed).To(BeTrue(), cluster)
+			}
+		})
+
+		It("denies unknown values", func() {
+			// Arrange
+			handler = newHandler(knownClusters)
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-3, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(Equal("Unknown field values: cluster=eu-de-3"))
+			Expect(scrape()).To(ContainSubstring(`ccrn_field_resolutions_total{field="cluster",result="not_found"}`))
+		})
+
+		Context("list values", func() {
+			newListHandler := func(zones webhook.FieldResolver) http.Handler {
+				backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+				Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "defaults_crd.yaml"))).To(Succeed())
+				server, err := webhook.NewWebhookServer(logrus.New(), backend,
+					webhook.WithFieldResolver("zones", zones), webhook.WithResolverTimeout(200*time.Millisecond))
+				Expect(err).ToNot(HaveOccurred())
+				return server.Handler()
+			}
+			volume := func(zones []string) []byte {
+				return admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=data, zones=" + apis.FormatList(zones)})
+			}
+
+			It("resolves the items concurrently within one timeout", func() {
+				// Arrange
+				handler = newListHandler(resolverFunc(func(ctx context.Context, _ string) (bool, error) {
+					select {
+					case <-time.After(100 * time.Millisecond):
+						return true, nil
+					case <-ctx.Done():
+						return false, ctx.Err()
+					}
+				}))
+				// Act
+				response := admit(volume([]string{"a", "b", "c", "d", "e"}))
+				// Assert
+				Expect(response.Allowed).To(BeTrue(), "sequential resolution would exceed the timeout")
+			})
+
+			It("denies lists with more values than are resolved", func() {
+				// Arrange
+				handler = newListHandler(resolverFunc(func(context.Context, string) (bool, error) {
+					return true, nil
+				}))
+				zones := make([]string, webhook.MaxResolvedValues+1)
+				for i := range zones {
+					zones[i] = fmt.Sprintf("z%d", i)
+				}
+				// Act
+				response := admit(volume(zones))
+				// Assert
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Message).To(Equal(fmt.Sprintf("Field zones has %d values, at most %d can be resolved", len(zones), webhook.MaxResolvedValues)))
+			})
+		})
+
+		It("denies requests if a resolver times out", func() {
+			// Arrange
+			handler = newHandler(resolverFunc(func(ctx context.Context, _ string) (bool, error) {
+				<-ctx.Done()
+				return false, ctx.Err()
+			}))
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(Equal("Failed to resolve field cluster: context deadline exceeded"))
+		})
+	})
+
+	Context("workload annotation", func() {
+		BeforeEach(func() {
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend,
+				webhook.WithWorkloadAnnotation([]string{"pods"}, map[string]string{"cluster": "eu-de-1"}))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		// annotate posts a request for the object of the resource to the annotate endpoint
+		annotate := func(resource string, object map[string]any) *admissionv1.AdmissionResponse {
+			raw, err := json.Marshal(object)
+			Expect(err).ToNot(HaveOccurred())
+			body, err := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Namespace: "shop",
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: resource},
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/annotate", bytes.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			review := admissionv1.AdmissionReview{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &review)).To(Succeed())
+			return review.Response
+		}
+
+		It("annotates objects with the CCRN derived from the object mapping", func() {
+			// Act
+			response := annotate("pods", map[string]any{
+				"metadata": map[string]any{"name": "web", "labels": map[string]any{"app": "shop"}},
+				"spec":     map[string]any{"nodeName": "node-1"},
+			})
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+			Expect(string(response.Patch)).To(MatchJSON(`[{"op":"add","path":"/metadata/annotations","value":{"ccrn.cloud/id":"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, labels.app=shop, name=web, namespace=shop, nodeName=node-1"}}]`))
+			Expect(scrape()).To(ContainSubstring(`ccrn_workload_annotations_total{resource="pods",result="annotated"}`))
+		})
+
+		It("keeps existing annotations", func() {
+			// Act
+			response := annotate("pods", map[string]any{
+				"metadata": map[string]any{"name": "web", "annotations": map[string]any{apis.IdentifierAnnotation: "ccrn=custom"}},
+			})
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patch).To(BeEmpty())
+		})
+
+		It("skips objects without name and resources that are not annotated", func() {
+			// Act & Assert
+			Expect(annotate("pods", map[string]any{"metadata": map[string]any{"generateName": "web-"}}).Patch).To(BeEmpty())
+			Expect(annotate("services", map[string]any{"metadata": map[string]any{"name": "web"}}).Patch).To(BeEmpty())
+			Expect(scrape()).To(ContainSubstring(`ccrn_workload_annotations_total{resource="services",result="skipped"}`))
+		})
+
+		It("allows objects violating the schema with a warning", func() {
+			// Act
+			response := annotate("pods", map[string]any{"metadata": map[string]any{"name": "Web"}})
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patch).To(BeEmpty())
+			Expect(response.Warnings).To(ConsistOf(HavePrefix("Not annotated with ccrn.cloud/id: ")))
+		})
+
+		Context("with an object mapping not needing the name", func() {
+			BeforeEach(func() {
+				backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+				Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "workload_crd.yaml"))).To(Succeed())
+				server, err := webhook.NewWebhookServer(logrus.New(), backend,
+					webhook.WithWorkloadAnnotation([]string{"pods"}, map[string]string{"cluster": "eu-de-1"}))
+				Expect(err).ToNot(HaveOccurred())
+				handler = server.Handler()
+			})
+
+			It("annotates objects created with generateName by their owner", func() {
+				// Act
+				response := annotate("pods", map[string]any{
+					"metadata": map[string]any{
+						"generateName":    "web-5d8f7-",
+						"ownerReferences": []any{map[string]any{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-5d8f7", "controller": true}},
+					},
+				})
+				// Assert
+				Expect(response.Allowed).To(BeTrue())
+				Expect(response.Warnings).To(BeEmpty())
+				Expect(string(response.Patch)).To(MatchJSON(`[{"op":"add","path":"/metadata/annotations","value":{"ccrn.cloud/id":"ccrn=workload.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=shop, ownerKind=ReplicaSet, ownerName=web-5d8f7"}}]`))
+			})
+		})
+	})
+
+	Context("derived resources", func() {
+		var backend *derivingBackend
+
+		BeforeEach(func() {
+			backend = &derivingBackend{FilesystemBackend: validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")}
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend)
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		// review returns an AdmissionReview of the operation on the CCRN object
+		review := func(operation admissionv1.Operation, ccrn apis.CCRN, dryRun bool) []byte {
+			raw, err := json.Marshal(ccrn)
+			Expect(err).ToNot(HaveOccurred())
+			request := &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Kind:      metav1.GroupVersionKind{Group: "validate.tr.ccrn.example.com", Version: "v1", Kind: "CCRN"},
+				Namespace: "default",
+				Name:      ccrn.Name,
+				Operation: operation,
+				DryRun:    &dryRun,
+			}
+			if operation == admissionv1.Delete {
+				request.OldObject = runtime.RawExtension{Raw: raw}
+			} else {
+				request.Object = runtime.RawExtension{Raw: raw}
+			}
+			body, err := json.Marshal(admissionv1.AdmissionReview{Request: request})
+			Expect(err).ToNot(HaveOccurred())
+			return body
+		}
+		ccrn := apis.CCRN{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pod", UID: "1234"},
+			Spec:       apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"},
+		}
+
+		It("owns the derived resources by the CCRN object", func() {
+			// Act
+			response := admit(review(admissionv1.Update, ccrn, false))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(backend.owned).To(ConsistOf(metav1.OwnerReference{
+				APIVersion: "validate.tr.ccrn.example.com/v1", Kind: "CCRN", Name: "my-pod", UID: "1234"}))
+			Expect(backend.uids).To(ConsistOf(BeEquivalentTo("test-uid")), "the backend logs with the request logger")
+		})
+
+		It("adds the finalizer of the derived resources", func() {
+			// Arrange
+			created := ccrn
+			created.UID = ""
+			// Act
+			response := admit(review(admissionv1.Create, created, false))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(string(response.Patch)).To(ContainSubstring(`{"op":"add","path":"/metadata/finalizers","value":["ccrn/derived-resources"]}`))
+		})
+
+		It("appends the finalizer to existing finalizers only once", func() {
+			// Arrange
+			finalized := ccrn
+			finalized.Finalizers = []string{"other"}
+			// Act
+			response := admit(review(admissionv1.Update, finalized, false))
+			finalized.Finalizers = append(finalized.Finalizers, apis.FinalizerDerivedResources)
+			again := admit(review(admissionv1.Update, finalized, false))
+			// Assert
+			Expect(string(response.Patch)).To(ContainSubstring(`{"op":"add","path":"/metadata/finalizers/-","value":"ccrn/derived-resources"}`))
+			Expect(string(again.Patch)).ToNot(ContainSubstring("finalizers"))
+		})
+
+		It("validates dry-run requests without deriving resources", func() {
+			// Act
+			response := admit(review(admissionv1.Create, ccrn, true))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(backend.dryRuns).To(Equal(1))
+			Expect(backend.owned).To(BeEmpty())
+			Expect(string(response.Patch)).ToNot(ContainSubstring("finalizers"))
+		})
+
+		It("leaves the deletion of the derived resources to the cleanup controller", func() {
+			// Act
+			response := admit(review(admissionv1.Delete, ccrn, false))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(backend.deleted).To(BeEmpty())
+		})
+
+		It("admits updates of CCRN objects being deleted without deriving resources", func() {
+			// Arrange
+			deleted := ccrn
+			deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			deleted.Spec.CCRN = "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!"
+			// Act
+			response := admit(review(admissionv1.Update, deleted, false))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(backend.owned).To(BeEmpty())
+		})
+	})
+
+	Context("auditing", func() {
+		var sink *recordingSink
+
+		BeforeEach(func() {
+			sink = &recordingSink{}
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithAuditSink(sink))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		It("records allowed requests with their patches", func() {
+			// Act
+			admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(sink.events).To(HaveLen(1))
+			Expect(sink.events[0].Decision).To(Equal(audit.DecisionAllowed))
+			Expect(sink.events[0].UID).To(Equal("test-uid"))
+			Expect(sink.events[0].CCRN).To(ContainSubstring("pod.k8s-registry.tr.ccrn.example.com/v1"))
+			Expect(string(sink.events[0].Patches)).To(ContainSubstring("/spec/urn"))
+			Expect(sink.events[0].URN).To(Equal("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/my-pod"))
+		})
+
+		It("records denied requests with their reason", func() {
+			// Act
+			admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=mars-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(sink.events).To(HaveLen(1))
+			Expect(sink.events[0].Decision).To(Equal(audit.DecisionDenied))
+			Expect(sink.events[0].Reason).To(Equal(webhook.DenyReasonSchemaViolation))
+		})
+
+		It("records the URN of denied requests", func() {
+			// Act
+			admit(admissionReviewFor(apis.CCRNSpec{URN: "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/mars-1/default/my-pod"}))
+			// Assert
+			Expect(sink.events).To(HaveLen(1))
+			Expect(sink.events[0].Decision).To(Equal(audit.DecisionDenied))
+			Expect(sink.events[0].URN).To(Equal("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/mars-1/default/my-pod"))
+		})
+	})
+
+	Context("bundle pinning", func() {
+		var backend *validation.FilesystemBackend
+
+		BeforeEach(func() {
+			backend = validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+		})
+
+		It("admits CCRNs while the CRDs match the pinned digest", func() {
+			// Arrange
+			digest, err := bundle.Digest(backend.CustomResourceDefinitions())
+			Expect(err).ToNot(HaveOccurred())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithBundleDigest(digest))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(server.VerifyBundleDigest()).To(Succeed())
+		})
+
+		It("denies CCRNs while the CRDs differ from the pinned digest", func() {
+			// Arrange
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithBundleDigest("sha256:0"))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("CRDs do not match the pinned schema bundle: digest mismatch"))
+			Expect(server.VerifyBundleDigest()).To(MatchError(bundle.ErrDigestMismatch))
+		})
+
+		It("requires a backend listing its CRDs", func() {
+			// Act
+			_, err := webhook.NewWebhookServer(logrus.New(), struct{ apis.ValidationBackend }{backend}, webhook.WithBundleDigest("sha256:0"))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("cannot be pinned")))
+		})
+	})
+
+	Context("recording", func() {
+		var recorder *memoryRecorder
+
+		BeforeEach(func() {
+			recorder = &memoryRecorder{}
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithRecorder(recorder, []byte("pseudonym-key")))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		It("records sanitized requests with their responses", func() {
+			// Arrange
+			raw, err := json.Marshal(apis.CCRN{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Annotations: map[string]string{"owner": "alice@example.com"}},
+				Spec:       apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			body, err := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Namespace: "default",
+				Name:      "my-pod",
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "alice", UID: "1234"},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			response := admit(body)
+			// Assert
+			Expect(recorder.records).To(HaveLen(1))
+			recorded := recorder.records[0]
+			Expect(recorded.Path).To(Equal("/validate"))
+			Expect(recorded.Request.UID).To(BeEquivalentTo("test-uid"))
+			Expect(recorded.Request.UserInfo.Username).To(Equal(replay.Pseudonym([]byte("pseudonym-key"), "alice")))
+			Expect(recorded.Request.UserInfo.UID).To(BeEmpty())
+			Expect(string(recorded.Request.Object.Raw)).ToNot(ContainSubstring("alice@example.com"))
+			Expect(string(recorded.Request.Object.Raw)).To(ContainSubstring("cluster=eu-de-1"))
+			Expect(recorded.Response.Allowed).To(BeTrue())
+			Expect(recorded.Response.Patch).To(Equal(response.Patch))
+		})
+
+		It("records only the object mapping of annotated workloads", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithRecorder(recorder, []byte("pseudonym-key")),
+				webhook.WithWorkloadAnnotation([]string{"pods"}, map[string]string{"cluster": "eu-de-1"}))
+			Expect(err).ToNot(HaveOccurred())
+			raw, err := json.Marshal(map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "web", "labels": map[string]any{"app": "shop"}},
+				"spec": map[string]any{"nodeName": "node-1", "containers": []any{map[string]any{
+					"name": "web", "command": []any{"serve", "--token=hunter2"},
+					"env": []any{map[string]any{"name": "DB_PASSWORD", "value": "s3cret"}},
+				}}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			body, err := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Namespace: "shop",
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"shop-admins"}},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			request := httptest.NewRequest(http.MethodPost, "/annotate", bytes.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+			// Act
+			server.Handler().ServeHTTP(httptest.NewRecorder(), request)
+			// Assert
+			Expect(recorder.records).To(HaveLen(1))
+			recorded := recorder.records[0]
+			Expect(recorded.Path).To(Equal("/annotate"))
+			Expect(recorded.Request.UserInfo.Groups).To(HaveLen(1))
+			Expect(recorded.Request.UserInfo.Groups[0]).ToNot(Equal("shop-admins"))
+			Expect(string(recorded.Request.Object.Raw)).To(MatchJSON(`{"apiVersion":"v1","kind":"Pod",
+				"metadata":{"name":"web","labels":{"app":"shop"}},"spec":{"nodeName":"node-1"}}`))
+		})
+
+		It("requires a pseudonym key", func() {
+			// Act
+			_, err := webhook.NewWebhookServer(logrus.New(), validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com"),
+				webhook.WithRecorder(recorder, nil))
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("requires a pseudonym key")))
+		})
+	})
+
+	Context("events", func() {
+		var recorder *record.FakeRecorder
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "deprecated_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithEventRecorder(recorder), webhook.WithNamespaceEvents())
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+		})
+
+		It("records rejections on the object and its namespace", func() {
+			// Act
+			admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=mars-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(HavePrefix("Warning " + webhook.EventReasonDenied))
+		})
+
+		It("records applied mutations", func() {
+			// Act
+			admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(<-recorder.Events).To(Equal("Normal " + webhook.EventReasonMutated + " Added /spec/urn"))
+		})
+
+		It("warns about deprecated versions", func() {
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=widget.tr.ccrn.example.com/v1alpha1, name=my-widget"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(<-recorder.Events).To(ContainSubstring(webhook.EventReasonDeprecatedVersion + " widget.tr.ccrn.example.com/v1alpha1 is deprecated, use v1"))
+			Expect(response.Warnings).To(Equal([]string{"widget.tr.ccrn.example.com/v1alpha1 is deprecated, use v1"}))
+		})
+
+		It("warns about wildcard fields", func() {
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=*, namespace=default, name=my-pod"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(Equal([]string{"field cluster is a wildcard and matches any value"}))
+		})
+
+		It("pins the latest version", func() {
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=widget.tr.ccrn.example.com/latest, name=my-widget"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+			Expect(string(response.Patch)).To(ContainSubstring(`"op":"add","path":"/spec/urn","value":"urn:ccrn:widget.tr.ccrn.example.com/v1/my-widget"`))
+			Expect(string(response.Patch)).To(ContainSubstring(`"op":"replace","path":"/spec/ccrn","value":"ccrn=widget.tr.ccrn.example.com/v1, name=my-widget"`))
+		})
+	})
+
+	Context("controller-runtime manager", func() {
+		It("serves the admission routes on the webhook server", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend)
+			Expect(err).ToNot(HaveOccurred())
+			mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{Metrics: metricsserver.Options{BindAddress: "0"}})
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			Expect(server.SetupWithManager(mgr)).To(Succeed())
+			handler = mgr.GetWebhookServer().WebhookMux()
+			// Assert
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"}))
+			Expect(response.Allowed).To(BeTrue())
+			for _, path := range []string{"/healthz", "/metrics"} {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+				Expect(recorder.Code).To(Equal(http.StatusNotFound), path+" must only be served by the plaintext listeners")
+			}
+		})
+	})
+
+	Context("conversion", func() {
+		It("converts objects to the desired version", func() {
+			// Arrange
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "conversion_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend)
+			Expect(err).ToNot(HaveOccurred())
+			body, err := json.Marshal(apiextensionsv1.ConversionReview{
+				Request: &apiextensionsv1.ConversionRequest{
+					UID:               "test-uid",
+					DesiredAPIVersion: "tr.ccrn.example.com/v1",
+					Objects: []runtime.RawExtension{{
+						Raw: []byte(`{"apiVersion":"tr.ccrn.example.com/v1alpha1","kind":"gadget","metadata":{"name":"g"},"ccrn":"gadget.tr.ccrn.example.com/v1alpha1","region":"eu-de-1","name":"g"}`),
+					}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body))
+			// Act
+			server.Handler().ServeHTTP(recorder, request)
+			// Assert
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			review := apiextensionsv1.ConversionReview{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &review)).To(Succeed())
+			Expect(review.Response.UID).To(BeEquivalentTo("test-uid"))
+			Expect(review.Response.Result.Status).To(Equal("Success"))
+			Expect(review.Response.ConvertedObjects).To(HaveLen(1))
+			Expect(string(review.Response.ConvertedObjects[0].Raw)).To(ContainSubstring(`"cluster":"eu-de-1"`))
+		})
+	})
+
+	Context("feature gates", func() {
+		It("canonicalizes the CCRN with CanonicalMutation enabled", func() {
+			// Arrange
+			gate := featuregate.NewFeatureGate(map[featuregate.Feature]featuregate.FeatureSpec{
+				featuregate.CanonicalMutation: {Default: false, Stage: featuregate.Alpha},
+			})
+			Expect(gate.Set("CanonicalMutation=true")).To(Succeed())
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithFeatureGate(gate))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1,name=my-pod, namespace=default, cluster=eu-de-1"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			Expect(string(response.Patch)).To(ContainSubstring(`"op":"replace","path":"/spec/ccrn","value":"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, namespace=default"`))
+		})
+
+		It("labels the object with LabelMutation enabled", func() {
+			// Arrange
+			gate := featuregate.NewFeatureGate(map[featuregate.Feature]featuregate.FeatureSpec{
+				featuregate.LabelMutation: {Default: false, Stage: featuregate.Alpha},
+			})
+			Expect(gate.Set("LabelMutation=true")).To(Succeed())
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithFeatureGate(gate))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			response := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, name=my-pod, namespace=default"}))
+			// Assert
+			Expect(response.Allowed).To(BeTrue())
+			var patches []map[string]any
+			Expect(json.Unmarshal(response.Patch, &patches)).To(Succeed())
+			Expect(patches).To(ContainElement(SatisfyAll(
+				HaveKeyWithValue("path", "/metadata/labels"),
+				HaveKeyWithValue("value", SatisfyAll(
+					HaveKeyWithValue("ccrn/kind", "pod"),
+					HaveKeyWithValue("ccrn/field.cluster", "eu-de-1"),
+				)),
+			)))
+		})
+
+		It("denies versions past their sunset date with SunsetEnforcement enabled", func() {
+			// Arrange
+			gate := featuregate.NewFeatureGate(map[featuregate.Feature]featuregate.FeatureSpec{
+				featuregate.SunsetEnforcement: {Default: false, Stage: featuregate.Alpha},
+			})
+			Expect(gate.Set("SunsetEnforcement=true")).To(Succeed())
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "sunset_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithFeatureGate(gate))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			sunset := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=gadget.tr.ccrn.example.com/v1alpha1, name=my-gadget"}))
+			deprecated := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=gadget.tr.ccrn.example.com/v1beta1, name=my-gadget"}))
+			// Assert
+			Expect(sunset.Allowed).To(BeFalse())
+			Expect(sunset.Result.Message).To(ContainSubstring("gadget.tr.ccrn.example.com/v1alpha1 is past its sunset date 2020-12-31"))
+			Expect(deprecated.Allowed).To(BeTrue())
+			Expect(deprecated.Warnings).To(ContainElement(ContainSubstring("sunset on 2999-12-31")))
+		})
+
+		It("denies homoglyphs with HomoglyphScreening enabled", func() {
+			// Arrange
+			gate := featuregate.NewFeatureGate(map[featuregate.Feature]featuregate.FeatureSpec{
+				featuregate.HomoglyphScreening: {Default: false, Stage: featuregate.Alpha},
+			})
+			Expect(gate.Set("HomoglyphScreening=true")).To(Succeed())
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "sunset_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(logrus.New(), backend, webhook.WithFeatureGate(gate))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			homoglyph := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=gadget.tr.ccrn.example.com/v1, name=pаyments"}))
+			latin := admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=gadget.tr.ccrn.example.com/v1, name=payments"}))
+			// Assert
+			Expect(homoglyph.Allowed).To(BeFalse())
+			Expect(homoglyph.Result.Message).To(ContainSubstring("field name: value 'pаyments' mixes Cyrillic and Latin letters"))
+			Expect(latin.Allowed).To(BeTrue())
+		})
+	})
+
+	Context("logging", func() {
+		It("samples allowed requests and logs every denial with its decision", func() {
+			// Arrange
+			var buffer bytes.Buffer
+			log := logrus.New()
+			log.SetOutput(&buffer)
+			log.SetFormatter(&logrus.JSONFormatter{})
+			backend := validation.NewOfflineBackend(logrus.New(), "tr.ccrn.example.com")
+			Expect(backend.LoadCRDs(filepath.Join("..", "validation", "testdata", "testpod_crd.yaml"))).To(Succeed())
+			server, err := webhook.NewWebhookServer(log, backend, webhook.WithLogSampling(2))
+			Expect(err).ToNot(HaveOccurred())
+			handler = server.Handler()
+			// Act
+			for range 4 {
+				Expect(admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=my-pod"})).Allowed).To(BeTrue())
+			}
+			for range 2 {
+				Expect(admit(admissionReviewFor(apis.CCRNSpec{CCRN: "ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=INVALID!, namespace=default, name=my-pod"})).Allowed).To(BeFalse())
+			}
+			// Assert
+			decisions := map[string]int{}
+			for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+				entry := map[string]any{}
+				Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+				if decision, ok := entry["decision"].(string); ok {
+					decisions[decision]++
+					Expect(entry).To(HaveKey("duration_ms"))
+					Expect(entry).To(HaveKeyWithValue("namespace", "default"))
+				}
+			}
+			Expect(decisions).To(Equal(map[string]int{webhook.DecisionAllowed: 2, webhook.DecisionDenied: 2}))
+		})
+	})
+})
+
+var _ = Describe("NewLogrusLogger", func() {
+	It("logs errors at error level and verbose messages at debug level", func() {
+		// Arrange
+		var out bytes.Buffer
+		log := logrus.New()
+		log.SetOutput(&out)
+		log.SetFormatter(&logrus.JSONFormatter{})
+		log.SetLevel(logrus.DebugLevel)
+		logger := webhook.NewLogrusLogger(log).WithName("admission").WithValues("webhook", "/validate")
+		// Act
+		logger.Error(errors.New("broken pipe"), "unable to write the response")
+		logger.V(1).Info("received request", "uid", "test-uid")
+		// Assert
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(And(ContainSubstring(`"level":"error"`), ContainSubstring(`"error":"broken pipe"`),
+			ContainSubstring(`"logger":"admission"`), ContainSubstring(`"webhook":"/validate"`)))
+		Expect(lines[1]).To(And(ContainSubstring(`"level":"debug"`), ContainSubstring(`"uid":"test-uid"`)))
+	})
+
+	It("disables verbose messages above debug level", func() {
+		// Arrange
+		log := logrus.New()
+		log.SetLevel(logrus.InfoLevel)
+		// Act
+		logger := webhook.NewLogrusLogger(log)
+		// Assert
+		Expect(logger.Enabled()).To(BeTrue())
+		Expect(logger.V(1).Enabled()).To(BeFalse())
+	})
+})
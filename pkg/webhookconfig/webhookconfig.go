@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhookconfig generates the MutatingWebhookConfiguration registering the CCRN webhook with the API
+// server. The rules are derived from the CCRN CRDs instead of being written by hand, so they cannot drift from
+// the served resource types: the ccrns of the validate.<group> API group of every CCRN group in the versions of
+// their CRD, and the source resources of the resource types with an object mapping for workload annotation.
+// Both paths of the webhook patch the objects they admit, so the API server only accepts them as mutating webhooks.
+package webhookconfig
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// DefaultName is the default name of the webhook configuration
+const DefaultName = "ccrn-webhook"
+
+// Paths of the webhook, see webhook.WebhookServer
+const (
+	ValidatePath = "/validate"
+	AnnotatePath = "/annotate"
+)
+
+// Options configure the generated webhook configuration
+type Options struct {
+	Name           string                                    // Name of the configuration, defaults to DefaultName
+	Service        string                                    // Service of the webhook as "<name>.<namespace>"
+	Port           int32                                     // Port of the service, defaults to 443
+	CABundle       []byte                                    // PEM encoded CA certificates of the serving certificate, empty if injected later
+	Groups         []string                                  // CCRN groups, the ccrns of validate.<group> are validated
+	FailurePolicy  admissionregistrationv1.FailurePolicyType // Failure policy of the webhooks, defaults to Fail
+	TimeoutSeconds int32                                     // Timeout of the webhooks, defaults to 10
+	Annotate       bool                                      // Add the workload annotation webhook for the source resources
+}
+
+// Generate returns the webhook configuration for the resource type versions of the CRDs
+func Generate(crds []*apis.CRDInfo, opts Options) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	serviceName, namespace, found := strings.Cut(opts.Service, ".")
+	if !found || serviceName == "" || namespace == "" {
+		return nil, fmt.Errorf("invalid service %q, use <name>.<namespace>", opts.Service)
+	}
+	if len(opts.Groups) == 0 {
+		return nil, errors.New("no CCRN groups to validate")
+	}
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.Port == 0 {
+		opts.Port = 443
+	}
+	if opts.FailurePolicy == "" {
+		opts.FailurePolicy = admissionregistrationv1.Fail
+	}
+	if opts.TimeoutSeconds == 0 {
+		opts.TimeoutSeconds = 10
+	}
+
+	clientConfig := func(path string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{
+				Name:      serviceName,
+				Namespace: namespace,
+				Path:      ptr.To(path),
+				Port:      ptr.To(opts.Port),
+			},
+			CABundle: opts.CABundle,
+		}
+	}
+	webhookName := serviceName + "." + namespace + ".svc"
+
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		TypeMeta:   metav1.TypeMeta{APIVersion: admissionregistrationv1.SchemeGroupVersion.String(), Kind: "MutatingWebhookConfiguration"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name:                    webhookName,
+			ClientConfig:            clientConfig(ValidatePath),
+			Rules:                   ValidateRules(crds, opts.Groups),
+			FailurePolicy:           ptr.To(opts.FailurePolicy),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNoneOnDryRun), // Derived resources are created unless dry-run
+			TimeoutSeconds:          ptr.To(opts.TimeoutSeconds),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+	if opts.Annotate {
+		rules := AnnotateRules(crds)
+		if len(rules) == 0 {
+			return nil, errors.New("no resource type declares an object mapping for workload annotation")
+		}
+		config.Webhooks = append(config.Webhooks, admissionregistrationv1.MutatingWebhook{
+			Name:                    "annotate." + webhookName,
+			ClientConfig:            clientConfig(AnnotatePath),
+			Rules:                   rules,
+			FailurePolicy:           ptr.To(admissionregistrationv1.Ignore),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			TimeoutSeconds:          ptr.To(opts.TimeoutSeconds),
+			AdmissionReviewVersions: []string{"v1"},
+			ReinvocationPolicy:      ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy),
+		})
+	}
+	return config, nil
+}
+
+// ValidateRules returns the rules of the ccrns of the validate.<group> API groups of the CCRN groups. The versions
+// are those of the ccrns CRD of the group if it is among the CRDs, v1 otherwise. Deletions are not sent to the
+// webhook, the derived resources are deleted by the cleanup controller, see apis.FinalizerDerivedResources.
+func ValidateRules(crds []*apis.CRDInfo, groups []string) []admissionregistrationv1.RuleWithOperations {
+	var rules []admissionregistrationv1.RuleWithOperations
+	for _, group := range groups {
+		apiGroup := "validate." + group
+		var versions []string
+		for _, crd := range crds {
+			if crd.Group == apiGroup && crd.Plural == "ccrns" {
+				versions = append(versions, crd.Version)
+			}
+		}
+		if len(versions) == 0 {
+			versions = []string{"v1"}
+		}
+		slices.Sort(versions)
+		rules = append(rules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{apiGroup},
+				APIVersions: slices.Compact(versions),
+				Resources:   []string{"ccrns"},
+			},
+		})
+	}
+	return rules
+}
+
+// AnnotateRules returns a rule per API group of the source resources of the resource types with an object
+// mapping, see apis.CRDInfo.SourceResource. Resource types without source resource are sourced from the
+// resource of their kind, e.g. pods for the kind pod.
+func AnnotateRules(crds []*apis.CRDInfo) []admissionregistrationv1.RuleWithOperations {
+	resources := map[string][]string{}
+	for _, crd := range crds {
+		if len(crd.ObjectMapping) == 0 {
+			continue
+		}
+		source := schema.ParseGroupResource(crd.SourceResource)
+		if crd.SourceResource == "" {
+			plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Kind: crd.Kind})
+			source = schema.GroupResource{Resource: plural.Resource}
+		}
+		if !slices.Contains(resources[source.Group], source.Resource) {
+			resources[source.Group] = append(resources[source.Group], source.Resource)
+		}
+	}
+
+	rules := make([]admissionregistrationv1.RuleWithOperations, 0, len(resources))
+	for _, group := range slices.Sorted(maps.Keys(resources)) {
+		slices.Sort(resources[group])
+		rules = append(rules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{group},
+				APIVersions: []string{"*"},
+				Resources:   resources[group],
+			},
+		})
+	}
+	return rules
+}
+
+// AnnotatedResources returns the resources of the rules in the notation of the --annotate-resources flag of the
+// webhook, e.g. "pods" or "deployments.apps"
+func AnnotatedResources(rules []admissionregistrationv1.RuleWithOperations) []string {
+	var resources []string
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				resources = append(resources, schema.GroupResource{Group: group, Resource: resource}.String())
+			}
+		}
+	}
+	return resources
+}
+
+// Marshal returns the webhook configuration as YAML document ready for kubectl apply
+func Marshal(config *admissionregistrationv1.MutatingWebhookConfiguration) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config)
+	if err != nil {
+		return nil, err
+	}
+	// Leave out the fields of an empty object meta
+	delete(content["metadata"].(map[string]any), "creationTimestamp")
+	return yaml.Marshal(content)
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhookconfig"
+)
+
+func TestWebhookConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Config Suite")
+}
+
+var _ = Describe("Generate", func() {
+	var crds []*apis.CRDInfo
+
+	BeforeEach(func() {
+		crds = []*apis.CRDInfo{
+			{Kind: "CCRN", Plural: "ccrns", Group: "validate.ccrn.example.com", Version: "v1"},
+			{Kind: "CCRN", Plural: "ccrns", Group: "validate.ccrn.example.com", Version: "v2"},
+			{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v1", ObjectMapping: map[string]string{"name": "metadata.name"}},
+			{Kind: "pod", Group: "k8s.ccrn.example.com", Version: "v2", ObjectMapping: map[string]string{"name": "metadata.name"}},
+			{Kind: "deployment", Group: "k8s.ccrn.example.com", Version: "v1", SourceResource: "deployments.apps", ObjectMapping: map[string]string{"name": "metadata.name"}},
+			{Kind: "statefulset", Group: "k8s.ccrn.example.com", Version: "v1", SourceResource: "statefulsets.apps", ObjectMapping: map[string]string{"name": "metadata.name"}},
+			{Kind: "project", Group: "openstack.ccrn.example.com", Version: "v1"},
+		}
+	})
+
+	It("derives the rules of the validation from the ccrns CRDs", func() {
+		// Act
+		config, err := webhookconfig.Generate(crds, webhookconfig.Options{Service: "ccrn-webhook.ccrn-system", Groups: []string{"ccrn.example.com", "legacy.example.com"}})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Name).To(Equal(webhookconfig.DefaultName))
+		Expect(config.Webhooks).To(HaveLen(1))
+		webhook := config.Webhooks[0]
+		Expect(webhook.Name).To(Equal("ccrn-webhook.ccrn-system.svc"))
+		Expect(webhook.ClientConfig.Service.Name).To(Equal("ccrn-webhook"))
+		Expect(webhook.ClientConfig.Service.Namespace).To(Equal("ccrn-system"))
+		Expect(*webhook.ClientConfig.Service.Path).To(Equal(webhookconfig.ValidatePath))
+		Expect(*webhook.ClientConfig.Service.Port).To(BeEquivalentTo(443))
+		Expect(*webhook.FailurePolicy).To(Equal(admissionregistrationv1.Fail))
+		Expect(*webhook.SideEffects).To(Equal(admissionregistrationv1.SideEffectClassNoneOnDryRun))
+		Expect(webhook.Rules).To(HaveLen(2))
+		Expect(webhook.Rules[0].APIGroups).To(Equal([]string{"validate.ccrn.example.com"}))
+		Expect(webhook.Rules[0].APIVersions).To(Equal([]string{"v1", "v2"}))
+		Expect(webhook.Rules[0].Resources).To(Equal([]string{"ccrns"}))
+		Expect(webhook.Rules[0].Operations).To(ConsistOf(admissionregistrationv1.Create, admissionregistrationv1.Update))
+		Expect(webhook.Rules[1].APIGroups).To(Equal([]string{"validate.legacy.example.com"}))
+		Expect(webhook.Rules[1].APIVersions).To(Equal([]string{"v1"}), "v1 without ccrns CRD")
+	})
+
+	It("derives the rules of the workload annotation from the source resources", func() {
+		// Act
+		config, err := webhookconfig.Generate(crds, webhookconfig.Options{Service: "ccrn-webhook.ccrn-system", Groups: []string{"ccrn.example.com"}, Annotate: true})
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Webhooks).To(HaveLen(2))
+		webhook := config.Webhooks[1]
+		Expect(webhook.Name).To(Equal("annotate.ccrn-webhook.ccrn-system.svc"))
+		Expect(*webhook.ClientConfig.Service.Path).To(Equal(webhookconfig.AnnotatePath))
+		Expect(*webhook.FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+		Expect(webhook.Rules).To(HaveLen(2))
+		Expect(webhook.Rules[0].APIGroups).To(Equal([]string{""}))
+		Expect(webhook.Rules[0].Resources).To(Equal([]string{"pods"}))
+		Expect(webhook.Rules[1].APIGroups).To(Equal([]string{"apps"}))
+		Expect(webhook.Rules[1].Resources).To(Equal([]string{"deployments", "statefulsets"}))
+		Expect(webhookconfig.AnnotatedResources(webhook.Rules)).To(Equal([]string{"pods", "deployments.apps", "statefulsets.apps"}))
+	})
+
+	It("fails if no resource type can be annotated", func() {
+		// Act
+		_, err := webhookconfig.Generate(crds[:2], webhookconfig.Options{Service: "ccrn-webhook.ccrn-system", Groups: []string{"ccrn.example.com"}, Annotate: true})
+		// Assert
+		Expect(err).To(MatchError(ContainSubstring("no resource type declares an object mapping")))
+	})
+
+	DescribeTable("rejects services without namespace",
+		func(service string) {
+			// Act
+			_, err := webhookconfig.Generate(crds, webhookconfig.Options{Service: service, Groups: []string{"ccrn.example.com"}})
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("use <name>.<namespace>")))
+		},
+		Entry("name only", "ccrn-webhook"),
+		Entry("empty namespace", "ccrn-webhook."),
+		Entry("empty name", ".ccrn-system"),
+	)
+
+	It("marshals the configuration as YAML document", func() {
+		// Arrange
+		config, err := webhookconfig.Generate(crds, webhookconfig.Options{Service: "ccrn-webhook.ccrn-system", Groups: []string{"ccrn.example.com"}, CABundle: []byte("CA")})
+		Expect(err).ToNot(HaveOccurred())
+		// Act
+		data, err := webhookconfig.Marshal(config)
+		// Assert
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).ToNot(ContainSubstring("creationTimestamp"))
+		var decoded admissionregistrationv1.MutatingWebhookConfiguration
+		Expect(yaml.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded.Kind).To(Equal("MutatingWebhookConfiguration"))
+		Expect(decoded.Webhooks[0].ClientConfig.CABundle).To(Equal([]byte("CA")))
+	})
+})
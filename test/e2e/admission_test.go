@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+)
+
+// ccrnObject returns a CCRN resource identifying the pod of the cluster in the namespace
+func ccrnObject(namespace, name, cluster string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ccrns.GroupVersion().String(),
+		"kind":       "CCRN",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"ccrn": fmt.Sprintf("ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=%s, namespace=%s, name=%s", cluster, namespace, name),
+		},
+	}}
+}
+
+// derivedPods returns the pod resources the webhook created for the CCRN resource
+func derivedPods(namespace, name string) []unstructured.Unstructured {
+	list, err := client.Resource(pods).Namespace(namespace).List(context.Background(),
+		metav1.ListOptions{LabelSelector: apis.OwnerSelector(metav1.OwnerReference{APIVersion: ccrns.GroupVersion().String(), Name: name})})
+	Expect(err).ToNot(HaveOccurred())
+	return list.Items
+}
+
+var _ = Describe("Admission", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Context("with the Kubernetes backend", func() {
+		const namespace = "default"
+
+		It("adds the URN and creates the derived resource", func() {
+			// Act
+			created, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "create", "eu-de-1"), metav1.CreateOptions{})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			urn, _, _ := unstructured.NestedString(created.Object, "spec", "urn")
+			Expect(urn).To(Equal("urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/create"))
+			Expect(derivedPods(namespace, "create")).To(HaveLen(1))
+		})
+
+		It("denies a CCRN violating the schema of its resource type", func() {
+			// Act
+			_, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "invalid", "us-west-1"), metav1.CreateOptions{})
+			// Assert
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("denied the request"))
+			_, err = client.Resource(ccrns).Namespace(namespace).Get(ctx, "invalid", metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("denies an update violating the schema of its resource type", func() {
+			// Arrange
+			created, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "update", "eu-de-1"), metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unstructured.SetNestedField(created.Object,
+				"ccrn=pod.k8s-registry.tr.ccrn.example.com/v1, cluster=us-west-1, namespace=default, name=update", "spec", "ccrn")).To(Succeed())
+			// Act
+			_, err = client.Resource(ccrns).Namespace(namespace).Update(ctx, created, metav1.UpdateOptions{})
+			// Assert
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("denied the request"))
+			stored, err := client.Resource(ccrns).Namespace(namespace).Get(ctx, "update", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Object["spec"]).To(HaveKeyWithValue("ccrn", ContainSubstring("cluster=eu-de-1")))
+		})
+
+		It("keeps one derived resource across updates", func() {
+			// Arrange
+			created, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "updates", "eu-de-1"), metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			// Act
+			updated := created
+			for _, label := range []string{"first", "second"} {
+				updated.SetLabels(map[string]string{"update": label})
+				updated, err = client.Resource(ccrns).Namespace(namespace).Update(ctx, updated, metav1.UpdateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			// Assert
+			derived := derivedPods(namespace, "updates")
+			Expect(derived).To(HaveLen(1))
+			Expect(derived[0].GetOwnerReferences()).To(ConsistOf(HaveField("UID", created.GetUID())))
+		})
+
+		It("deletes the derived resource of the previous type when an update changes the type", func() {
+			// Arrange
+			created, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "retype", "eu-de-1"), metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(derivedPods(namespace, "retype")).To(HaveLen(1))
+			Expect(unstructured.SetNestedField(created.Object,
+				"ccrn=volume.k8s-registry.tr.ccrn.example.com/v1, cluster=eu-de-1, namespace=default, name=retype", "spec", "ccrn")).To(Succeed())
+			unstructured.RemoveNestedField(created.Object, "spec", "urn")
+			// Act
+			_, err = client.Resource(ccrns).Namespace(namespace).Update(ctx, created, metav1.UpdateOptions{})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(derivedPods(namespace, "retype")).To(BeEmpty())
+			volumeList, err := client.Resource(volumes).Namespace(namespace).List(ctx,
+				metav1.ListOptions{LabelSelector: apis.OwnerSelector(metav1.OwnerReference{APIVersion: ccrns.GroupVersion().String(), Name: "retype"})})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(volumeList.Items).To(HaveLen(1))
+		})
+
+		It("deletes the derived resources with the CCRN", func() {
+			// Arrange
+			_, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "delete", "eu-de-1"), metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(derivedPods(namespace, "delete")).To(HaveLen(1))
+			// Act
+			err = client.Resource(ccrns).Namespace(namespace).Delete(ctx, "delete", metav1.DeleteOptions{})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() []unstructured.Unstructured { return derivedPods(namespace, "delete") }).Should(BeEmpty())
+			Eventually(func() bool {
+				_, err := client.Resource(ccrns).Namespace(namespace).Get(ctx, "delete", metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue(), "the finalizer is removed after the cleanup")
+		})
+
+		It("mutates dry-run requests without persisting the CCRN", func() {
+			// Act
+			created, err := client.Resource(ccrns).Namespace(namespace).Create(ctx, ccrnObject(namespace, "dry-run", "eu-de-1"),
+				metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created.Object["spec"]).To(HaveKeyWithValue("urn", "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-1/default/dry-run"))
+			_, err = client.Resource(ccrns).Namespace(namespace).Get(ctx, "dry-run", metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(derivedPods(namespace, "dry-run")).To(BeEmpty())
+		})
+	})
+
+	Context("with the offline backend", func() {
+		It("validates against the CRD schemas without creating resources", func() {
+			// Act
+			created, err := client.Resource(ccrns).Namespace(offlineNamespace).Create(ctx, ccrnObject(offlineNamespace, "create", "eu-de-2"), metav1.CreateOptions{})
+			// Assert
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created.Object["spec"]).To(HaveKeyWithValue("urn", "urn:ccrn:pod.k8s-registry.tr.ccrn.example.com/v1/eu-de-2/offline/create"))
+			Expect(derivedPods(offlineNamespace, "create")).To(BeEmpty())
+		})
+
+		It("denies a CCRN violating the schema of its resource type", func() {
+			// Act
+			_, err := client.Resource(ccrns).Namespace(offlineNamespace).Create(ctx, ccrnObject(offlineNamespace, "invalid", "us-west-1"), metav1.CreateOptions{})
+			// Assert
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("denied the request"))
+		})
+	})
+})
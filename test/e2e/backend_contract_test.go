@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/apis"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation/backendtest"
+)
+
+// TestKubernetesBackendContract runs the backend contract against the KubernetesBackend in an API server of
+// its own, the CRDs of the contract group are replaced by the CRDs of each subtest
+func TestKubernetesBackendContract(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run \"make test-e2e\" to download the envtest binaries")
+	}
+	env := &envtest.Environment{}
+	config, err := env.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	crdClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundles := t.TempDir()
+
+	// install makes the CRDs of the bundle the only CRDs of the contract group, deleted CRDs are waited for
+	// since the API server lists them until their resources are gone
+	install := func(t *testing.T, crds []byte) {
+		dir, err := os.MkdirTemp(bundles, "crds")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "crds.yaml"), crds, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		installed, err := envtest.InstallCRDs(config, envtest.CRDInstallOptions{Paths: []string{dir}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		keep := make(map[string]bool, len(installed))
+		for _, crd := range installed {
+			keep[crd.Name] = true
+		}
+
+		ctx := context.Background()
+		existing, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, crd := range existing.Items {
+			if crd.Spec.Group != backendtest.Group || keep[crd.Name] {
+				continue
+			}
+			if err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Delete(ctx, crd.Name, metav1.DeleteOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+				_, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crd.Name, metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				return false, err
+			})
+			if err != nil {
+				t.Fatalf("CRD %s is not deleted: %v", crd.Name, err)
+			}
+		}
+	}
+
+	backendtest.Run(t, func(t *testing.T, crds []byte) (apis.ValidationBackend, func([]byte)) {
+		install(t, crds)
+		backend, err := validation.NewKubernetesBackend(config, logrus.New(), backendtest.Group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return backend, func(crds []byte) { install(t, crds) }
+	})
+}
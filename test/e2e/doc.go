@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package e2e runs the webhook against a real API server started by envtest. The tests install the CRDs of
+// testdata/crds, register the webhook for CCRN resources and exercise the admission of creates, updates,
+// deletes and dry-run requests. They are skipped unless KUBEBUILDER_ASSETS points to the envtest binaries,
+// see "make test-e2e".
+package e2e
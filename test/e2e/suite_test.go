@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/cleanup"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/validation"
+	"github.com/cloudoperators/common-cloud-resource-names/pkg/webhook"
+)
+
+const (
+	ccrnGroup        = "tr.ccrn.example.com"
+	offlineNamespace = "offline"
+	backendLabel     = "e2e.ccrn.example.com/backend"
+)
+
+var (
+	ccrns   = schema.GroupVersionResource{Group: "validate." + ccrnGroup, Version: "v1", Resource: "ccrns"}
+	pods    = schema.GroupVersionResource{Group: "k8s-registry." + ccrnGroup, Version: "v1", Resource: "pods"}
+	volumes = schema.GroupVersionResource{Group: "k8s-registry." + ccrnGroup, Version: "v1", Resource: "volumes"}
+
+	testEnv *envtest.Environment
+	client  dynamic.Interface
+	cancel  context.CancelFunc
+)
+
+func TestE2E(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run \"make test-e2e\" to download the envtest binaries")
+	}
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "E2E Suite")
+}
+
+// mutatingWebhook sends the admission requests for CCRN resources in the namespaces of the selector to the path,
+// envtest replaces the service by the URL of the local webhook server
+func mutatingWebhook(name, path string, selector metav1.LabelSelectorRequirement) admissionregistrationv1.MutatingWebhook {
+	return admissionregistrationv1.MutatingWebhook{
+		Name:                    name,
+		SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNoneOnDryRun),
+		AdmissionReviewVersions: []string{"v1"},
+		FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+		ClientConfig: admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{Name: "ccrn", Namespace: "default", Path: ptr.To(path)},
+		},
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{selector}},
+		Rules: []admissionregistrationv1.RuleWithOperations{{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups: []string{ccrns.Group}, APIVersions: []string{ccrns.Version}, Resources: []string{ccrns.Resource}},
+		}},
+	}
+}
+
+var _ = BeforeSuite(func() {
+	log := logrus.New()
+	log.SetOutput(GinkgoWriter)
+	crdPath := filepath.Join("testdata", "crds")
+
+	// Requests in the offline namespace are validated by the FilesystemBackend, all others by the
+	// KubernetesBackend creating the resources in the API server
+	offline := metav1.LabelSelectorRequirement{Key: backendLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"offline"}}
+	online := metav1.LabelSelectorRequirement{Key: backendLabel, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"offline"}}
+	// The paths are relative, envtest joins them to the URL of the webhook server with a slash
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{crdPath},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			MutatingWebhooks: []*admissionregistrationv1.MutatingWebhookConfiguration{{
+				ObjectMeta: metav1.ObjectMeta{Name: "ccrn-webhook"},
+				Webhooks: []admissionregistrationv1.MutatingWebhook{
+					mutatingWebhook("ccrn.tr.ccrn.example.com", "validate", online),
+					mutatingWebhook("offline.ccrn.tr.ccrn.example.com", "offline/validate", offline),
+				},
+			}},
+		},
+	}
+	config, err := testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(func() {
+		cancel()
+		Expect(testEnv.Stop()).To(Succeed())
+	})
+
+	client, err = dynamic.NewForConfig(config)
+	Expect(err).ToNot(HaveOccurred())
+	kubeClient, err := kubernetes.NewForConfig(config)
+	Expect(err).ToNot(HaveOccurred())
+	_, err = kubeClient.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: offlineNamespace, Labels: map[string]string{backendLabel: "offline"}},
+	}, metav1.CreateOptions{})
+	Expect(err).ToNot(HaveOccurred())
+
+	// Serve both webhook servers with the manager like cmd/webhook does
+	options := testEnv.WebhookInstallOptions
+	mgr, err := manager.New(config, manager.Options{
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Host: options.LocalServingHost, Port: options.LocalServingPort, CertDir: options.LocalServingCertDir}),
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	kubernetesBackend, err := validation.NewKubernetesBackend(config, log, ccrnGroup)
+	Expect(err).ToNot(HaveOccurred())
+	server, err := webhook.NewWebhookServer(log, kubernetesBackend)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(server.SetupWithManager(mgr)).To(Succeed())
+	controller := cleanup.NewController(log, client, kubernetesBackend)
+	Expect(mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return controller.Watch(ctx, ccrnGroup)
+	}))).To(Succeed())
+
+	offlineBackend := validation.NewOfflineBackend(log, ccrnGroup)
+	Expect(offlineBackend.LoadCRDsFromDirectory(crdPath)).To(Succeed())
+	offlineServer, err := webhook.NewWebhookServer(log, offlineBackend)
+	Expect(err).ToNot(HaveOccurred())
+	mgr.GetWebhookServer().Register("/offline/validate", http.StripPrefix("/offline", offlineServer.Handler()))
+
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+	Eventually(func() error {
+		return mgr.GetWebhookServer().StartedChecker()(nil)
+	}).WithTimeout(30 * time.Second).Should(Succeed())
+})